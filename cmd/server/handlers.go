@@ -3,6 +3,7 @@ package main
 import (
 	"chrisgross-ctrl-project/internal/handlers"
 	"chrisgross-ctrl-project/internal/security"
+	"chrisgross-ctrl-project/internal/services"
 	"gorm.io/gorm"
 )
 
@@ -41,12 +42,15 @@ type AllHandlers struct {
 	// Email
 	EmailSender           *handlers.EmailSenderHandlers
 	Unsubscribe           *handlers.UnsubscribeHandlers
+	CommunicationSMS      *handlers.CommunicationSMSHandlers
 
 
 	// Lead Management
 	LeadReengagement      *handlers.LeadReengagementHandler
 	LeadsList             *handlers.LeadsListHandler
 	BulkOperations        *handlers.BulkOperationsHandler
+	LeadRouting           *handlers.LeadRoutingHandlers
+	LeadSafety            *handlers.LeadSafetyHandlers
 
 	// Team Management
 	Team                  *handlers.TeamHandlers
@@ -59,12 +63,20 @@ type AllHandlers struct {
 	SavedProperties       *handlers.SavedPropertiesHandler
 	Recommendations       *handlers.RecommendationsHandler
 	PropertyAlerts        *handlers.PropertyAlertsHandler
+	SavedSearches         *handlers.SavedSearchHandlers
+	DailyDigest           *handlers.DailyDigestHandlers
+	ContactTimeline       *handlers.ContactTimelineHandlers
 	LiveActivity          *handlers.LiveActivityHandler
 	BehavioralSessions    *handlers.BehavioralSessionsHandler
 
 	// Security
 	SecurityMonitoring    *handlers.SecurityMonitoringHandlers
 	AdvancedSecurityAPI   *handlers.AdvancedSecurityAPIHandlers
+	EncryptionRotation    *handlers.EncryptionRotationHandlers
+	ComplianceAlerts      *handlers.ComplianceAlertHandlers
+	ComplianceConfig      *handlers.ComplianceConfigHandlers
+	ComplianceDNC         *handlers.ComplianceDNCHandlers
+	ComplianceHistory     *handlers.ComplianceHistoryHandlers
 
 	// Webhooks
 	Webhook               *handlers.WebhookHandlers
@@ -88,6 +100,23 @@ type AllHandlers struct {
 	// Daily Schedule
 	DailySchedule         *handlers.DailyScheduleHandlers
 
+	// Campaign Automation (nil when Redis is unavailable)
+	CampaignAutomation    *handlers.CampaignAutomationHandlers
+
+	// Application Recovery
+	ApplicationRecovery   *handlers.ApplicationRecoveryHandlers
+
+	// Conversion Funnel and Attribution
+	FunnelAnalytics       *handlers.FunnelAnalyticsHandlers
+	Attribution           *handlers.AttributionHandlers
+
+	// Data Retention and Privacy Requests
+	DataRetention         *handlers.DataRetentionHandlers
+	Privacy               *handlers.PrivacyHandlers
+
+	// IP Access Control
+	IPAccess              *handlers.IPAccessHandlers
+
 	// MFA
 	MFA                   *handlers.MFAHandler
 
@@ -100,9 +129,18 @@ type AllHandlers struct {
 	// Setup
 	Setup                 *handlers.SetupHandlers
 
+	// Performance Monitoring
+	PerformanceMonitoring *handlers.PerformanceMonitoringHandlers
+
 	// Database (for inline handlers that need it)
 	DB                    *gorm.DB
 	
 	// Encryption Manager (for decrypting addresses)
 	EncryptionManager     *security.EncryptionManager
+
+	// MFA re-verification (for routes that require a fresh second factor)
+	MFAReverification     *services.MFAReverificationService
+
+	// CORS origins allowed to open admin WebSockets besides this host
+	WebSocketOrigins      []string
 }
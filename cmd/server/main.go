@@ -2,15 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"chrisgross-ctrl-project/internal/auth"
 	"chrisgross-ctrl-project/internal/config"
 	"chrisgross-ctrl-project/internal/handlers"
+	"chrisgross-ctrl-project/internal/logging"
 	"chrisgross-ctrl-project/internal/middleware"
 	"chrisgross-ctrl-project/internal/models"
 	"chrisgross-ctrl-project/internal/repositories"
@@ -37,10 +40,18 @@ func main() {
 
         log.Println("🚀 Starting PropertyHub Enterprise System v2.0...")
 
+        // Background goroutines stop when appCtx is cancelled during shutdown
+        appCtx, cancelApp := context.WithCancel(context.Background())
+        defer cancelApp()
+
         // Load enterprise configuration
         cfg := config.LoadConfig()
         log.Println("⚙️ Enterprise configuration loaded")
 
+        // Structured logger; request-scoped copies carry the request ID
+        logger := logging.New(os.Stdout, cfg.LogLevel, cfg.LogFormat)
+        logging.SetDefault(logger)
+
         // Validate AWS credentials for SES/SNS
         if os.Getenv("AWS_REGION") == "" || os.Getenv("AWS_ACCESS_KEY_ID") == "" || os.Getenv("AWS_SECRET_ACCESS_KEY") == "" {
                 log.Println("⚠️  WARNING: AWS credentials not configured - email/SMS will not work")
@@ -76,18 +87,59 @@ func main() {
                 &models.EmailProcessingRule{},
                 &models.EmailProcessingLog{},
                 &models.IncomingEmail{},
+                &models.IncomingEmailAttachment{},
+                &models.ReceivedWebhook{},
                 &models.BehavioralEvent{},
                 &models.BehavioralSession{},
                 &models.BehavioralScore{},
                 &models.NotificationState{},
                 &models.AdminNotification{},
+                &models.AdminNotificationRead{},
+                &models.NotificationPreference{},
+                &models.QueuedNotification{},
+                &models.DailyDigestDelivery{},
+                &models.Task{},
+                &models.ContextFUBPush{},
+                &models.LeadRoutingConfig{},
+                &models.AgentRoutingProfile{},
+                &models.LeadAssignment{},
+                &models.ApplicationRecovery{},
+                &models.DataRetentionLog{},
+                &models.PrivacyRequest{},
+                &models.AuditLog{},
+                &models.SecurityEvent{},
+                &models.IPAccessRule{},
                 &models.DataImport{},
                 &models.ClosingPipeline{},
                 &models.WebhookEvent{},
                 &models.PriceChangeEvent{},
+                &models.SavedSearch{},
+                &models.SavedSearchAlert{},
+                &models.DNCEntry{},
+                &models.DNCSuppressionLog{},
+                &models.CampaignSuppression{},
+                &models.ClosingPipelineStageHistory{},
                 &models.PropertyApplicationGroup{},
                 &models.ApplicationNumber{},
                 &models.ApplicationApplicant{},
+                &models.CampaignExecution{},
+                &models.DripCampaign{},
+                &models.DripCampaignStep{},
+                &models.DripEnrollment{},
+                &models.SMSSendLog{},
+                &models.ValuationBatch{},
+                &models.ValuationResult{},
+                &models.AddressValuation{},
+                &models.EncryptionRotationJob{},
+                &models.AdminRefreshToken{},
+                &models.ComplianceAlert{},
+                &models.ReputationSnapshot{},
+                &models.ComplianceConfig{},
+                &models.CampaignEmergencyStop{},
+                &models.ScoringConfig{},
+                &models.ComplianceCheckSnapshot{},
+                &models.PropertyAuditLog{},
+                &models.LeadMergeAudit{},
         }
 
         for _, model := range safeModels {
@@ -98,6 +150,11 @@ func main() {
         }
         log.Println("✅ Database migrations completed")
 
+        // A campaign emergency stop stays in force across restarts
+        if err := services.RestoreCampaignEmergencyStop(gormDB); err != nil {
+                log.Printf("⚠️ Failed to restore campaign emergency stop: %v", err)
+        }
+
         // Initialize enterprise authentication manager
         authManager := auth.NewSimpleAuthManager(sqlDB)
         log.Println("🔐 Enterprise authentication initialized")
@@ -149,6 +206,14 @@ if cfg.RedisURL != "" {
         }
 }
 
+if propertyValuationService != nil {
+        propertyValuationService.SetValuationCacheTTL(cfg.ValuationCacheTTL)
+        if redisClient != nil {
+                propertyValuationService.SetRedisCache(redisClient)
+        }
+        log.Printf("💾 Valuation cache enabled (TTL: %v)", cfg.ValuationCacheTTL)
+}
+
 // Initialize all enterprise handlers
 log.Println("🔧 Initializing enterprise handlers...")
 
@@ -166,6 +231,7 @@ log.Println("✅ Workflow handlers initialized")
 // Behavioral Intelligence & FUB Integration
 behavioralHandler := handlers.NewBehavioralIntelligenceHandlers(gormDB)
 contextFUBHandler := handlers.NewContextFUBIntegrationHandlers(gormDB, cfg.FUBAPIKey)
+contextFUBHandler.StartFUBPushRetries(appCtx, cfg.FUBPushRetryInterval)
 log.Println("🧠 Behavioral intelligence handlers initialized")
 
 // Calendar & Scheduling
@@ -184,8 +250,13 @@ log.Println("📥 Data migration handlers initialized")
 emailSenderHandler := handlers.NewEmailSenderHandlers(gormDB)
 unsubscribeHandler := handlers.NewUnsubscribeHandlers(gormDB)
 
+// Do-not-contact list, enforced on every email and SMS send path
+dncService := services.NewDNCService(gormDB, encryptionManager)
+dncService.StartRecheck(appCtx, cfg.DNCRecheckInterval)
+
 // Email automation (only if Redis is available)
 var emailBatchService *services.EmailBatchService
+var smtpPool *services.SMTPPool
 var emailAutomationHandler *handlers.EmailAutomationHandlers
 if redisClient != nil {
         // Create SMTP config
@@ -198,6 +269,20 @@ if redisClient != nil {
 
         // Initialize email batch service
         emailBatchService = services.NewEmailBatchService(redisClient, smtpConfig)
+        emailBatchService.SetDNCService(dncService)
+        if cfg.SMTPEnabled {
+                // Reuse SMTP connections across a batch instead of SES
+                smtpConfig.From = cfg.EmailFromAddress
+                smtpConfig.UseTLS = cfg.SMTPUseTLS
+                poolConfig := services.DefaultSMTPPoolConfig()
+                poolConfig.MaxConnections = cfg.SMTPMaxConnections
+                poolConfig.IdleTimeout = cfg.SMTPKeepAlive
+                poolConfig.MaxMessagesPerConnection = cfg.SMTPMaxMessagesPerConnection
+                smtpPool = services.NewSMTPPool(smtpConfig, poolConfig)
+                emailBatchService.SetSMTPPool(smtpPool)
+                log.Printf("📧 Email batches sent over pooled SMTP (%s:%d, %d connections)", cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPMaxConnections)
+        }
+        emailBatchService.Start()
 
         // Initialize email automation handler
         emailAutomationHandler = handlers.NewEmailAutomationHandlers(gormDB, emailProcessor, emailBatchService)
@@ -208,10 +293,65 @@ if redisClient != nil {
 
 // HAR Market removed - HAR blocked access
 
+// Lead safety filter keeps flagged leads out of campaigns and FUB pushes
+leadSafetyFilter := services.NewLeadSafetyFilter(gormDB, nil)
+if err := leadSafetyFilter.AutoMigrate(); err != nil {
+        log.Printf("⚠️ Lead safety filter migration failed: %v", err)
+}
+contextFUBHandler.SetLeadSafetyFilter(leadSafetyFilter)
+
 // Lead Management & Reengagement
 leadReengagementHandler := handlers.NewLeadReengagementHandler(gormDB, encryptionManager)
+leadReengagementHandler.SetSafetyFilter(leadSafetyFilter)
+if cfg.TrackingSecret == "" || cfg.TrackingSecret == cfg.JWTSecret {
+        log.Fatal("❌ TRACKING_SECRET must be set and differ from JWT_SECRET")
+}
+campaignTracker := services.NewCampaignTracker(cfg.PublicBaseURL, cfg.TrackingSecret)
+leadReengagementHandler.SetCampaignTracker(campaignTracker)
+unsubscribeHandler.SetCampaignTracker(campaignTracker)
+unsubscribeHandler.SetEncryptionManager(encryptionManager)
+go func() {
+        if indexed, err := models.BackfillLeadBlindIndexes(gormDB, encryptionManager); err != nil {
+                log.Printf("⚠️ Lead blind index backfill failed: %v", err)
+        } else if indexed > 0 {
+                log.Printf("🔎 Rebuilt blind indexes for %d leads", indexed)
+        }
+}()
+go func() {
+        if normalized, err := models.BackfillNormalizedAddresses(gormDB); err != nil {
+                log.Printf("⚠️ Normalized address backfill failed: %v", err)
+        } else if normalized > 0 {
+                log.Printf("🏠 Normalized addresses for %d pre-listing items and approvals", normalized)
+        }
+}()
+var sendingWarmup *services.WarmupSchedule
+if !cfg.WarmupStartDate.IsZero() {
+        sendingWarmup = services.NewWarmupSchedule(cfg.WarmupDomain, cfg.WarmupStartDate, cfg.WarmupInitialDailyCap, 1+float64(cfg.WarmupGrowthPercent)/100, cfg.WarmupStepDays, cfg.WarmupMaxDailyCap)
+        log.Printf("🌡️ Sending domain warmup active for %s (day %d, cap %d)", cfg.WarmupDomain, sendingWarmup.DayOn(time.Now()), sendingWarmup.CapOn(time.Now()))
+}
+var campaignDispatcher *services.CampaignDispatcher
+var dripScheduler *services.DripCampaignService
+if emailBatchService != nil {
+        campaignDispatcher = services.NewCampaignDispatcher(gormDB, emailBatchService, encryptionManager)
+        campaignDispatcher.SetTracker(campaignTracker)
+        campaignDispatcher.SetWarmupSchedule(sendingWarmup)
+        campaignDispatcher.SetRateShaper(services.NewDomainRateShaper(cfg.CampaignSenderRatePerMinute, cfg.CampaignDomainRatePerMinute, cfg.CampaignDomainRateLimits))
+        campaignDispatcher.SetRetryPolicy(cfg.CampaignMaxSendAttempts, time.Duration(cfg.CampaignRetryBackoffMinutes)*time.Minute)
+        emailBatchService.SetFailureHandler(campaignDispatcher.HandleEmailFailure)
+        campaignDispatcher.SetDNCService(dncService)
+        campaignDispatcher.SetSafetyFilter(leadSafetyFilter)
+        leadReengagementHandler.SetRateShaper(campaignDispatcher.RateShaper())
+        campaignDispatcher.Start()
+        log.Println("📨 Re-engagement campaign dispatcher started")
+        dripScheduler = services.NewDripCampaignService(gormDB)
+        dripScheduler.SetWarmupSchedule(sendingWarmup)
+        dripScheduler.Start()
+} else {
+        log.Println("⚠️ Re-engagement campaign dispatcher skipped - email batch service not available")
+}
 leadsListHandler := handlers.NewLeadsListHandler(gormDB, encryptionManager)
 bulkOperationsHandler := handlers.NewBulkOperationsHandler(gormDB)
+bulkOperationsHandler.SetAgentScoping(cfg.LeadAgentScoping)
 log.Println("👥 Lead management handlers initialized")
 
 // Team Management
@@ -231,9 +371,21 @@ if propertyValuationService != nil {
 
 // Security & Monitoring
 securityMonitoringHandler := handlers.NewSecurityMonitoringHandlers(gormDB)
+encryptionRotation := services.NewEncryptionRotationService(gormDB, encryptionManager)
 advancedSecurityAPIHandler := handlers.NewAdvancedSecurityAPIHandlers(gormDB, encryptionManager)
+advancedSecurityAPIHandler.SetEncryptionRotation(encryptionRotation)
+encryptionRotationHandler := handlers.NewEncryptionRotationHandlers(encryptionManager, encryptionRotation)
 log.Println("🔒 Security handlers initialized")
 
+// Compliance alerts
+complianceMonitoringService := services.NewComplianceMonitoringService(gormDB)
+complianceMonitoringService.SetWarmupSchedule(sendingWarmup)
+complianceAlertHandler := handlers.NewComplianceAlertHandlers(complianceMonitoringService)
+complianceConfigHandler := handlers.NewComplianceConfigHandlers(complianceMonitoringService)
+complianceHistoryHandler := handlers.NewComplianceHistoryHandlers(complianceMonitoringService)
+complianceDNCHandler := handlers.NewComplianceDNCHandlers(gormDB, encryptionManager)
+log.Println("🚨 Compliance handlers initialized")
+
 // Webhook Integrations
 webhookHandler := handlers.NewWebhookHandlers(gormDB)
 log.Println("🔗 Webhook handlers initialized")
@@ -262,22 +414,26 @@ if cfg.FUBAPIKey != "" {
 	log.Println("⚠️  FUB API client created (inactive - no API key)")
 }
 
-// NOTE: These services are initialized but not yet wired to handlers
-// They are ready for future integration when needed
+// One error handler classifies and counts failures across FUB callers
 fubErrorHandler := services.NewFUBErrorHandler()
-log.Println("⚠️ FUB error handler initialized (available for future use)")
-_ = fubErrorHandler // Not yet wired to handlers
+fubAPIClient.SetErrorHandler(fubErrorHandler)
+contextFUBHandler.SetFUBErrorHandler(fubErrorHandler)
+log.Println("⚠️ FUB error handler initialized")
 
 var fubBatchService *services.FUBBatchService
 if redisClient != nil {
 	fubBatchService = services.NewFUBBatchService(gormDB, redisClient)
-	log.Println("📦 FUB batch service initialized (available for future use)")
-	_ = fubBatchService // Not yet wired to handlers
+	fubBatchService.SetRateLimit(cfg.FUBRateLimitPerMinute, cfg.FUBRateLimitBurst)
+	contextFUBHandler.SetFUBBatchService(fubBatchService)
+	log.Printf("📦 FUB batch service initialized (%d requests/min, burst %d)", cfg.FUBRateLimitPerMinute, cfg.FUBRateLimitBurst)
 } else {
-	log.Println("⚠️ FUB batch service skipped - Redis not available")
+	log.Println("⚠️ FUB batch service skipped - Redis not available, FUB writes call the API directly")
 }
 
+// NOTE: These services are initialized but not yet wired to handlers
+// They are ready for future integration when needed
 fubBidirectionalSync := services.NewFUBBidirectionalSync(gormDB, cfg.FUBAPIKey)
+fubBidirectionalSync.SetErrorHandler(fubErrorHandler)
 if cfg.FUBAPIKey != "" {
 	log.Println("🔄 FUB bidirectional sync initialized (available for future use)")
 } else {
@@ -297,36 +453,35 @@ _ = fubBridge // Not yet wired to handlers
 funnelAnalytics := services.NewFunnelAnalyticsService(gormDB)
 log.Println("📊 Funnel analytics initialized")
 
-// NOTE: These services are initialized but not yet wired to handlers
 var analyticsCacheService *services.AnalyticsCacheService
 var performanceMonitor *services.PerformanceMonitoringService
 if redisClient != nil {
 	analyticsCacheService = services.NewAnalyticsCacheService(redisClient, repos.Property, repos.Booking, repos.Admin)
-	log.Println("📊 Analytics cache service initialized (available for future use)")
-	_ = analyticsCacheService // Not yet wired to handlers
+	log.Println("📊 Analytics cache service initialized")
 	
 	performanceMonitor = services.NewPerformanceMonitoringService(redisClient)
-	log.Println("📈 Performance monitoring initialized (available for future use)")
-	_ = performanceMonitor // Not yet wired to handlers
+	performanceMonitor.RegisterServices(analyticsCacheService, fubBatchService, emailBatchService, nil)
+	performanceMonitor.Start()
+	log.Println("📈 Performance monitoring initialized")
 } else {
 	log.Println("⚠️ Analytics cache and performance monitoring skipped - Redis not available")
 }
+if analyticsCacheService != nil {
+	funnelAnalytics.SetAnalyticsCache(analyticsCacheService, cfg.FunnelCacheTTL)
+}
 
 // Routing and Scheduling Services
-// NOTE: These services are initialized but not yet wired to handlers
 leadRouting := services.NewLeadRoutingService()
-log.Println("🔀 Lead routing service initialized (available for future use)")
-_ = leadRouting // Not yet wired to handlers
+leadAssignments := services.NewLeadAssignmentService(gormDB, leadRouting)
+log.Println("🔀 Lead routing service initialized")
 
 dailySchedule := services.NewDailyScheduleService(gormDB)
-log.Println("📅 Daily schedule service initialized (available for future use)")
-_ = dailySchedule // Not yet wired to handlers
+log.Println("📅 Daily schedule service initialized")
 
 // Campaign Services (will be initialized after scoringEngine and email/SMS services)
 var campaignTriggers *services.CampaignTriggerAutomation
 var eventOrchestrator *services.EventCampaignOrchestrator
 var relationshipEngine *services.RelationshipIntelligenceEngine
-var leadSafetyFilter *services.LeadSafetyFilter
 
 
 	// Property Matching (needed by campaign services and relationship engine)
@@ -338,6 +493,12 @@ var leadSafetyFilter *services.LeadSafetyFilter
 	log.Println("🏠 Initializing PropertyHub AI Intelligence System...")
 	
 	intelligenceCache := services.NewIntelligenceCacheService(redisClient)
+	intelligenceCache.SetTTLs(services.IntelligenceCacheTTLs{
+		Hot:   cfg.IntelligenceCacheHotTTL,
+		Warm:  cfg.IntelligenceCacheWarmTTL,
+		Daily: cfg.IntelligenceCacheDailyTTL,
+		Lead:  cfg.IntelligenceCacheLeadTTL,
+	})
 	if intelligenceCache.IsAvailable() {
 		log.Println("✅ Intelligence cache service initialized (Redis)")
 	} else {
@@ -345,6 +506,7 @@ var leadSafetyFilter *services.LeadSafetyFilter
 	}
 	
 	scoringEngine := services.NewBehavioralScoringEngine(gormDB)
+	scoringEngine.SetIntelligenceCache(intelligenceCache)
 	insightGenerator := services.NewInsightGeneratorService(gormDB, scoringEngine, biService)
 	
 	// Initialize relationship engine now that we have scoringEngine
@@ -358,9 +520,11 @@ var leadSafetyFilter *services.LeadSafetyFilter
 	// CRITICAL FIX: Initialize email/SMS/abandonmentRecovery BEFORE campaignTriggers
 	// ============================================================================
 	emailService := services.NewEmailService(cfg, gormDB)
+	emailService.SetDNCService(dncService)
 	log.Println("📧 Email service initialized")
 	
 	smsService := services.NewSMSService(cfg, gormDB)
+	smsService.SetDNCService(dncService)
 	log.Println("📱 SMS service initialized")
 	
 	notificationService := services.NewNotificationService(emailService, gormDB)
@@ -374,17 +538,50 @@ var leadSafetyFilter *services.LeadSafetyFilter
 	abandonmentRecovery := services.NewAbandonmentRecoveryService(emailService, smsService, analyticsAutomationService, leadService, propertyService)
 	log.Println("🔄 Abandonment recovery service initialized")
 	
+	// Reminders for applications started and not submitted
+	abandonmentRecovery.EnableApplicationRecovery(gormDB, services.ApplicationRecoverySettings{
+		Delay:        cfg.ApplicationRecoveryDelay,
+		Interval:     cfg.ApplicationRecoveryInterval,
+		MaxReminders: cfg.ApplicationRecoveryMaxReminders,
+	})
+	abandonmentRecovery.SetLeadSafetyFilter(leadSafetyFilter)
+	abandonmentRecovery.StartApplicationRecovery(appCtx, cfg.ApplicationRecoveryCheckInterval)
+	applicationRecoveryHandler := handlers.NewApplicationRecoveryHandlers(services.NewApplicationWorkflowService(gormDB), abandonmentRecovery)
+	log.Println("📝 Application recovery initialized")
+	
+	// Retention policy: purge old behavioral events, anonymize inactive leads
+	dataRetention := services.NewDataRetentionService(gormDB, services.DataRetentionSettings{
+		EventRetentionDays:   cfg.DataRetentionEventDays,
+		LeadInactivityMonths: cfg.DataRetentionLeadInactiveMonths,
+		DryRun:               cfg.DataRetentionDryRun,
+	})
+	dataRetention.SetIntelligenceCache(intelligenceCache)
+	dataRetention.Start(appCtx, cfg.DataRetentionInterval)
+
+	// Admin IP allowlist and the denylist fed by repeated security events
+	ipAccess, err := services.NewIPAccessService(gormDB, services.IPAccessSettings{
+		Allowlist:      cfg.AdminIPAllowlist,
+		EventThreshold: cfg.IPDenyEventThreshold,
+		EventWindow:    cfg.IPDenyEventWindow,
+		DenyDuration:   cfg.IPDenyDuration,
+	})
+	if err != nil {
+		log.Fatalf("❌ Invalid ADMIN_IP_ALLOWLIST: %v", err)
+	}
+	if err := ipAccess.Reload(); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+	ipAccess.Start(appCtx, 5*time.Minute)
+	
 	// Initialize campaign services now that we have all dependencies (including abandonmentRecovery)
 	if emailBatchService != nil {
 		campaignTriggers = services.NewCampaignTriggerAutomation(gormDB, emailBatchService, relationshipEngine, propertyMatcher, abandonmentRecovery)
-		log.Println("🎯 Campaign trigger automation initialized with abandonment recovery (available for future use)")
-		_ = campaignTriggers // Not yet wired to handlers
+		log.Println("🎯 Campaign trigger automation initialized with abandonment recovery")
 		
 		// SMSEmailAutomationService for EventCampaignOrchestrator
 		smsEmailAutomation := services.NewSMSEmailAutomationService(gormDB)
 		eventOrchestrator = services.NewEventCampaignOrchestrator(gormDB, smsEmailAutomation)
-		log.Println("📡 Event campaign orchestrator initialized (available for future use)")
-		_ = eventOrchestrator // Not yet wired to handlers
+		log.Println("📡 Event campaign orchestrator initialized")
 	} else {
 		log.Println("⚠️ Campaign services skipped - email batch service not available")
 	}
@@ -407,13 +604,17 @@ var leadSafetyFilter *services.LeadSafetyFilter
 	log.Println("✅ PropertyHub AI System initialized")
 	
 	dashboardStatsService := services.NewDashboardStatsService(gormDB, propertyHubAI, intelligenceCache)
+	if analyticsCacheService != nil {
+		dashboardStatsService.SetAnalyticsCache(analyticsCacheService, cfg.DashboardCacheTTL)
+		log.Printf("📊 Dashboard stats served from analytics cache (TTL: %v)", cfg.DashboardCacheTTL)
+	}
 	log.Println("✅ Dashboard stats service initialized")
 	
 	tieredStatsHandler := handlers.NewTieredStatsHandlers(gormDB, dashboardStatsService)
 	log.Println("✅ Tiered stats handler initialized")
 	
-	go propertyHubAI.StartAutomatedIntelligence(5)
-	log.Println("🤖 Automated intelligence cycle started (5 minute interval)")
+	propertyHubAI.SetConcurrency(cfg.IntelligenceWorkers, cfg.IntelligenceBatchSize)
+	propertyHubAI.StartAutomatedIntelligence(appCtx, cfg.IntelligenceCycleInterval)
 	propertiesHandler := handlers.NewPropertiesHandler(gormDB, repos, encryptionManager)
 	log.Println("🏠 Properties handler initialized with decryption")
 	
@@ -426,12 +627,37 @@ var leadSafetyFilter *services.LeadSafetyFilter
 	// NOTE: email/SMS/notification/abandonmentRecovery services already initialized above (before campaignTriggers)
 	
 	// Safety Services (depend on FUB API client)
-	leadSafetyFilter = services.NewLeadSafetyFilter(gormDB, fubAPIClient)
-	log.Println("🛡️ Lead safety filter initialized (available for future use)")
-	_ = leadSafetyFilter // Not yet wired to handlers
+	leadSafetyFilter.SetFUBClient(fubAPIClient)
+	leadSafetyHandler := handlers.NewLeadSafetyHandlers(gormDB, leadSafetyFilter)
+	log.Println("🛡️ Lead safety filter initialized")
 	
 	propertyAlertsHandler := handlers.NewPropertyAlertsHandler(gormDB, emailService)
 	log.Println("🔔 Property alerts handler initialized")
+
+	// Saved search alerts for new and re-priced listings
+	savedSearchAlerts := services.NewSavedSearchAlertService(gormDB, emailService, smsService)
+	savedSearchLocation, err := time.LoadLocation(cfg.SavedSearchTimezone)
+	if err != nil {
+		log.Printf("⚠️  Invalid SAVED_SEARCH_TIMEZONE %q, using server time: %v", cfg.SavedSearchTimezone, err)
+		savedSearchLocation = time.Local
+	}
+	savedSearchAlerts.SetQuietHours(cfg.SavedSearchQuietHoursStart, cfg.SavedSearchQuietHoursEnd, savedSearchLocation)
+	savedSearchAlerts.SetBaseURL(cfg.PublicBaseURL)
+	savedSearchAlerts.Start(appCtx, cfg.SavedSearchAlertInterval)
+	savedSearchHandlers := handlers.NewSavedSearchHandlers(gormDB)
+
+	// Daily digest emails for admins who opted in
+	dailyDigest := services.NewDailyDigestService(gormDB, emailService, cfg.TrackingSecret)
+	dailyDigest.SetIntelligence(relationshipEngine, insightGenerator)
+	dailyDigest.SetBaseURL(cfg.PublicBaseURL)
+	dailyDigestLocation, err := time.LoadLocation(cfg.DailyDigestTimezone)
+	if err != nil {
+		log.Printf("⚠️  Invalid DAILY_DIGEST_TIMEZONE %q, using server time: %v", cfg.DailyDigestTimezone, err)
+		dailyDigestLocation = time.Local
+	}
+	dailyDigest.SetSchedule(cfg.DailyDigestHour, dailyDigestLocation)
+	dailyDigest.Start(appCtx, cfg.DailyDigestCheckInterval)
+	dailyDigestHandlers := handlers.NewDailyDigestHandlers(dailyDigest)
 	
 	liveActivityHandler := handlers.NewLiveActivityHandler(gormDB)
 	log.Println("📡 Live activity handler initialized")
@@ -448,25 +674,47 @@ var leadSafetyFilter *services.LeadSafetyFilter
 	activityBroadcastService.SetBroadcaster(activityHubAdapter)
 	log.Println("📡 Activity broadcast service initialized and wired to WebSocket")
 
-	// Start periodic active count broadcasting
+	// Start periodic active count broadcasting (only broadcasts when the count changes)
 	go func() {
 		ticker := time.NewTicker(15 * time.Second)
 		defer ticker.Stop()
 		
-		for range ticker.C {
-			var count int64
-			gormDB.Raw("SELECT COUNT(DISTINCT id) FROM behavioral_sessions WHERE end_time IS NULL AND start_time >= NOW() - INTERVAL '15 minutes'").Scan(&count)
-			activityHubAdapter.BroadcastActiveCount(int(count))
+		lastBroadcast := -1
+		for {
+			select {
+			case <-appCtx.Done():
+				return
+			case <-ticker.C:
+				count := behavioralSessionsHandler.ActiveSessionCount()
+				if count != lastBroadcast {
+					activityHubAdapter.BroadcastActiveCount(count)
+					lastBroadcast = count
+				}
+			}
 		}
 	}()
 	log.Println("⏰ Periodic active count broadcasting started (15 second interval)")
 
 	// Initialize Behavioral Event Service and Handler
 	behavioralEventService := services.NewBehavioralEventService(gormDB)
+	behavioralEventService.SetSessionChangeListener(behavioralSessionsHandler.MarkSessionsChanged)
+	behavioralEventService.SetIntelligenceCache(intelligenceCache)
+	behavioralEventService.StartIdleSessionCloser(appCtx, cfg.BehavioralSessionIdleTimeout, cfg.BehavioralSessionCloseInterval)
 	behavioralEventHandler := handlers.NewBehavioralEventHandler(gormDB, behavioralEventService, activityBroadcastService)
 	log.Println("🧠 Behavioral event handler initialized with activity broadcasting")
 
+	// Behavioral events trigger campaigns (only if Redis available)
+	var campaignAutomationHandler *handlers.CampaignAutomationHandlers
+	if campaignTriggers != nil {
+		campaignAutomation := services.NewBehavioralCampaignAutomation(gormDB, campaignTriggers, eventOrchestrator)
+		behavioralEventHandler.SetCampaignAutomation(campaignAutomation)
+		campaignAutomationHandler = handlers.NewCampaignAutomationHandlers(campaignAutomation)
+		log.Println("🎯 Behavioral events wired to campaign automation")
+	}
+
 	adminNotificationHub := services.NewAdminNotificationHub(gormDB)
+	adminNotificationHub.SetNotifiers(emailService, smsService)
+	adminNotificationHub.StartQueuedDelivery(appCtx, time.Minute)
 	log.Println("🔔 Admin notification hub initialized")
 
 	adminNotificationHandler := handlers.NewAdminNotificationHandler(adminNotificationHub, gormDB)
@@ -482,6 +730,30 @@ var leadSafetyFilter *services.LeadSafetyFilter
 	applicationWorkflowHandler.SetNotificationHub(adminNotificationHub)
 	log.Println("📝 Application workflow handler wired to notifications")
 
+	closingPipelineHandler.SetNotificationHub(adminNotificationHub)
+	log.Println("🏁 Closing pipeline handler wired to notifications")
+
+	outboundWebhooks := services.NewOutboundWebhookService(cfg.OutboundWebhookURLs, cfg.OutboundWebhookSecret)
+	if outboundWebhooks.IsConfigured() {
+		log.Printf("🪝 Outbound webhooks configured (%d endpoints)", len(cfg.OutboundWebhookURLs))
+	}
+
+	if propertyValuationService != nil {
+		propertyValuationService.SetNotificationHub(adminNotificationHub)
+		propertyValuationService.SetWebhookService(outboundWebhooks)
+		propertyValuationService.SetValuationChangeThreshold(cfg.ValuationChangeThresholdPercent)
+		log.Printf("💲 Valuation change alerts enabled (threshold: %.1f%%)", cfg.ValuationChangeThresholdPercent)
+	}
+
+	complianceAlerts := complianceMonitoringService.Alerts()
+	complianceAlerts.SetNotifiers(emailService, smsService, outboundWebhooks)
+	complianceAlerts.SetNotificationRoutes(services.AlertNotificationRoutesFromConfig(cfg))
+	complianceAlerts.SetNotificationCooldown(cfg.ComplianceAlertCooldown)
+	log.Printf("🚨 Compliance alert notifications wired (cooldown: %v)", cfg.ComplianceAlertCooldown)
+
+	// Scheduled compliance check (records history and the daily reputation snapshot, raises alerts)
+	complianceMonitoringService.StartScheduledChecks(appCtx, cfg.ComplianceCheckInterval)
+
 	// Command Center - AI-driven actionable insights
 	fubIntegrationService := services.NewBehavioralFUBIntegrationService(gormDB, cfg.FUBAPIKey)
 	commandCenterHandler := handlers.NewCommandCenterHandlers(
@@ -492,8 +764,17 @@ var leadSafetyFilter *services.LeadSafetyFilter
 		propertyMatcher,
 		fubIntegrationService,
 	)
+	commandCenterTasks := services.NewCommandCenterTaskService(gormDB, insightGenerator)
+	commandCenterTasks.SetNotificationHub(adminNotificationHub)
+	commandCenterTasks.SetMinConfidence(float64(cfg.CommandCenterTaskMinScore) / 100)
+	commandCenterTasks.Start(appCtx, cfg.CommandCenterTaskInterval)
+	commandCenterHandler.SetTaskService(commandCenterTasks)
 	log.Println("🎯 Command Center handler initialized")
 
+	// Lead routing notifies agents of the leads assigned to them
+	leadAssignments.SetNotificationHub(adminNotificationHub)
+	leadRoutingHandler := handlers.NewLeadRoutingHandlers(gormDB, leadAssignments)
+
 	// Safety Management
 	safetyHandler := handlers.NewSafetyHandlers(gormDB)
 	log.Println("🔒 Safety handlers initialized")
@@ -511,11 +792,15 @@ var leadSafetyFilter *services.LeadSafetyFilter
 	log.Println("🔄 Central property sync handlers initialized")
 
 	// Daily Schedule
-	dailyScheduleHandler := handlers.NewDailyScheduleHandlers(gormDB)
+	dailyScheduleHandler := handlers.NewDailyScheduleHandlers(dailySchedule)
 	log.Println("📆 Daily schedule handlers initialized")
 
 	// MFA Authentication
 	mfaHandler := handlers.NewMFAHandler(gormDB, authManager)
+	adminTOTP := services.NewAdminTOTPService(gormDB, encryptionManager)
+	mfaReverification := services.NewMFAReverificationService(adminTOTP, cfg.MFAReverifyWindow)
+	mfaHandler.SetAdminTOTP(adminTOTP)
+	mfaHandler.SetReverification(mfaReverification)
 	log.Println("🔐 MFA handlers initialized")
 
 	// Settings Management
@@ -546,20 +831,31 @@ var leadSafetyFilter *services.LeadSafetyFilter
 		DataMigration:         dataMigrationHandler,
 		EmailSender:           emailSenderHandler,
 		Unsubscribe:           unsubscribeHandler,
+		CommunicationSMS:      handlers.NewCommunicationSMSHandlers(gormDB, encryptionManager),
 		// HARMarket removed - HAR blocked access
 		LeadReengagement:      leadReengagementHandler,
 		LeadsList:             leadsListHandler,
 		BulkOperations:        bulkOperationsHandler,
+		LeadRouting:           leadRoutingHandler,
+		LeadSafety:            leadSafetyHandler,
 		Team:                  teamHandler,
 		PreListing:            preListingHandler,
 		Properties:            propertiesHandler,
 		SavedProperties:       savedPropertiesHandler,
 		Recommendations:       recommendationsHandler,
 		PropertyAlerts:        propertyAlertsHandler,
+		SavedSearches:         savedSearchHandlers,
+		DailyDigest:           dailyDigestHandlers,
+		ContactTimeline:       handlers.NewContactTimelineHandlers(gormDB, encryptionManager),
 		LiveActivity:          liveActivityHandler,
 		BehavioralSessions:    behavioralSessionsHandler,
 		SecurityMonitoring:    securityMonitoringHandler,
 		AdvancedSecurityAPI:   advancedSecurityAPIHandler,
+		EncryptionRotation:    encryptionRotationHandler,
+		ComplianceAlerts:      complianceAlertHandler,
+		ComplianceConfig:      complianceConfigHandler,
+		ComplianceDNC:         complianceDNCHandler,
+		ComplianceHistory:     complianceHistoryHandler,
 		Webhook:               webhookHandler,
 		WebSocket:             webSocketHandler,
 		AdminNotification:     adminNotificationHandler,
@@ -568,9 +864,19 @@ var leadSafetyFilter *services.LeadSafetyFilter
 		CentralProperty:       centralPropertyHandler,
 		CentralPropertySync:   centralPropertySyncHandler,
 		DailySchedule:         dailyScheduleHandler,
+		CampaignAutomation:    campaignAutomationHandler,
+		ApplicationRecovery:   applicationRecoveryHandler,
+		FunnelAnalytics:       handlers.NewFunnelAnalyticsHandlers(funnelAnalytics),
+		Attribution:           handlers.NewAttributionHandlers(services.NewAttributionService(gormDB, encryptionManager)),
+		DataRetention:         handlers.NewDataRetentionHandlers(dataRetention),
+		Privacy:               handlers.NewPrivacyHandlers(services.NewPrivacyService(gormDB, encryptionManager, dataRetention)),
+		IPAccess:              handlers.NewIPAccessHandlers(ipAccess),
 		MFA:                   mfaHandler,
+		MFAReverification:     mfaReverification,
+		WebSocketOrigins:      cfg.CORSAllowedOrigins,
 		Settings:              settingsHandler,
 		Validation:            validationHandler,
+		PerformanceMonitoring: handlers.NewPerformanceMonitoringHandlers(performanceMonitor),
 		DB:                    gormDB,
 	}
 	log.Println("📦 Handler struct initialized for route registration")
@@ -578,42 +884,94 @@ var leadSafetyFilter *services.LeadSafetyFilter
 
         // Initialize Gin with enterprise security
         gin.SetMode(gin.ReleaseMode)
-        r := gin.Default()
+        r := gin.New()
+        // Only these proxies may set X-Forwarded-For / X-Real-IP for c.ClientIP()
+        if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+                log.Fatalf("❌ Invalid TRUSTED_PROXIES: %v", err)
+        }
+        r.Use(gin.Recovery())
+
+        // Tag every request with an ID and log it once it completes
+        r.Use(middleware.RequestLogging(logger))
+
+        // Cross-origin access for the admin SPA; same-origin only unless CORS_ALLOWED_ORIGINS is set
+        corsMiddleware := middleware.NewCORSMiddlewareWithConfig(middleware.CORSConfig{
+                AllowedOrigins:   cfg.CORSAllowedOrigins,
+                AllowCredentials: true,
+                CredentialPaths:  cfg.CORSCredentialPaths,
+        }, log.Default())
+        r.Use(middleware.GinCORSWrapper(corsMiddleware))
+        if len(cfg.CORSAllowedOrigins) > 0 {
+                log.Printf("🌐 CORS enabled for %d origins", len(cfg.CORSAllowedOrigins))
+        }
 
         // Configure Pongo2 template engine with Django-style inheritance
         templates.RegisterPongo2Filters()
-        r.HTMLRender = templates.NewPongo2Render(templates.GetTemplateDir())
+        r.HTMLRender = templates.NewPongo2Render(templates.GetTemplateDir(), cfg.TemplateReload)
         r.Static("/static", "./web/static")
-        log.Println("🎨 Pongo2 template engine configured with custom filters")
+        if cfg.TemplateReload {
+                log.Println("🎨 Pongo2 template engine configured with custom filters (reloading templates on every render)")
+        } else {
+                log.Println("🎨 Pongo2 template engine configured with custom filters (caching parsed templates)")
+        }
+
+        // Bound request bodies and handler run time (uploads get larger limits, WebSockets none)
+        requestLimits := middleware.DefaultRequestLimitsConfig()
+        requestLimits.MaxBodyBytes = cfg.MaxRequestBodyBytes
+        requestLimits.UploadMaxBodyBytes = cfg.UploadMaxRequestBodyBytes
+        requestLimits.Timeout = cfg.RequestTimeout
+        requestLimits.UploadTimeout = cfg.UploadRequestTimeout
+        requestLimits.UploadPaths = cfg.UploadPaths
+        r.Use(middleware.BodySizeLimit(requestLimits), middleware.RequestTimeout(requestLimits))
+        log.Printf("📏 Request limits: %dMB bodies, %s timeout (%dMB, %s on %d upload paths)",
+                requestLimits.MaxBodyBytes>>20, requestLimits.Timeout,
+                requestLimits.UploadMaxBodyBytes>>20, requestLimits.UploadTimeout, len(requestLimits.UploadPaths))
+
+        // Record per-route latency (no-op when performance monitoring is unavailable)
+        r.Use(middleware.PerformanceTracking(performanceMonitor))
 
         // Initialize enhanced security middleware
         securityMiddleware := middleware.NewSecurityMiddleware(gormDB)
         log.Println("🔒 Enhanced security middleware initialized")
 
-        // Enterprise security headers with CSP (exclude static files)
-        r.Use(func(c *gin.Context) {
-                // Don't apply nosniff to static files
-                if !strings.HasPrefix(c.Request.URL.Path, "/static/") {
-                        c.Header("X-Content-Type-Options", "nosniff")
-                }
-                c.Header("X-Frame-Options", "DENY") 
-                c.Header("X-XSS-Protection", "1; mode=block")
-	c.Header("Strict-Transport-Security", "max-age=31536000; includeSubDomains; preload")
-	c.Header("Referrer-Policy", "strict-origin-when-cross-origin")
-	c.Header("Permissions-Policy", "geolocation=(), microphone=(), camera=()")
-	c.Header("Content-Security-Policy", "default-src 'self'; script-src 'self' 'unsafe-inline' 'unsafe-eval'; style-src 'self' 'unsafe-inline' https://fonts.googleapis.com; img-src 'self' data: https:; font-src 'self' https://fonts.gstatic.com; connect-src 'self'; frame-ancestors 'none';")
-		c.Next()
-	})
+        // Turn denylisted IPs away everywhere and non-allowlisted IPs away from admin APIs
+        r.Use(middleware.IPAccessControl(ipAccess, cfg.AdminIPPaths))
+        log.Printf("🧱 IP access control applied (%d trusted proxies, %d configured admin networks)",
+                len(cfg.TrustedProxies), len(cfg.AdminIPAllowlist))
+        // Admin login lockouts show up on the security dashboard as brute force detections
+        middleware.AdminLoginRateLimiter.OnBlock(securityMiddleware.BruteForceRecorder("/admin/login"))
+
+        // Security headers with a per-request CSP nonce (static files are sent without nosniff)
+        securityHeaders := middleware.NewSecurityHeaders(middleware.SecurityHeadersConfig{
+                ContentSecurityPolicy:   cfg.ContentSecurityPolicy,
+                FrameOptions:            cfg.FrameOptions,
+                StrictTransportSecurity: cfg.StrictTransportSecurity,
+                ReferrerPolicy:          cfg.ReferrerPolicy,
+                PermissionsPolicy:       cfg.PermissionsPolicy,
+                XSSProtection:           "1; mode=block",
+                NoSniffExcludePrefixes:  []string{"/static/"},
+                Overrides: []middleware.SecurityHeaderOverride{{
+                        // Embeddable pages may be framed by the configured sites
+                        PathPrefixes:  cfg.EmbedPaths,
+                        CSPDirectives: map[string]string{"frame-ancestors": cfg.EmbedFrameAncestors},
+                        Headers:       map[string]string{"X-Frame-Options": ""},
+                }},
+        })
+        r.Use(securityHeaders.Middleware())
 	log.Println("🛡️ Enhanced security headers applied (CSP, Referrer-Policy, Permissions-Policy)")
 
 	// ISSUE #3 FIX: Apply CSRF protection middleware globally
 	r.Use(middleware.CSRFProtection())
 
-	// Apply SQL injection and XSS protection globally
-	r.Use(gin.WrapH(securityMiddleware.SQLInjectionProtection(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))))
-	r.Use(gin.WrapH(securityMiddleware.XSSProtection(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))))
+	// Scan untrusted query, form and JSON fields for SQL injection and XSS;
+	// rich-text fields are left to per-endpoint schema validation
+	r.Use(securityMiddleware.InputScanning(middleware.DefaultInputScanConfig()))
 	log.Println("🛡️ SQL injection and XSS protection applied")
 
+	// Record every write a signed-in admin makes, with before/after rows for key entities
+	r.Use(middleware.AuditTrail(gormDB, middleware.DefaultAuditTrailConfig()))
+	log.Println("📝 Admin audit trail enabled")
+
 	// ===== TEMPLATE ROUTES (All 35+ Templates) =====
 
 	// Register all routes
@@ -628,9 +986,15 @@ var leadSafetyFilter *services.LeadSafetyFilter
 	log.Println("🛣️ Registering API routes...")
 	api := r.Group("/api")
 	// Apply API rate limiting to all API routes
+	if cfg.RateLimitByAdmin {
+		// Authenticated agents get their own bucket instead of sharing one per office IP
+		adminResolver := middleware.SessionAdminResolver(authManager)
+		middleware.PublicAPIRateLimiter.KeyByAdmin(adminResolver)
+		middleware.AdminLoginRateLimiter.KeyByAdmin(adminResolver)
+	}
 	api.Use(middleware.PublicAPIRateLimiter.RateLimit())
-	log.Println("🔒 API rate limiting applied (10/min, 50/hour)")
-	RegisterAPIRoutes(api, allHandlers, propertyValuationHandler, emailAutomationHandler)
+	log.Printf("🔒 API rate limiting applied (10/min, 50/hour, keyed by admin: %v)", cfg.RateLimitByAdmin)
+	RegisterAPIRoutes(api, allHandlers, propertyValuationHandler, emailAutomationHandler, authManager)
 	log.Println("✅ API routes registered")
 
 	// Register admin authentication routes with enhanced security
@@ -641,11 +1005,14 @@ var leadSafetyFilter *services.LeadSafetyFilter
 	// Apply brute force protection
 	adminAuth.Use(gin.WrapH(securityMiddleware.BruteForceProtection(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))))
 	log.Println("🔒 Admin login protection applied (rate limiting + brute force detection)")
-	handlers.RegisterAdminAuthRoutes(r, gormDB, cfg.JWTSecret)
+	handlers.RegisterAdminAuthRoutes(r, gormDB, cfg.JWTSecret, cfg.AdminAccessTokenTTL, cfg.AdminRefreshTokenTTL)
 	log.Println("✅ Admin authentication routes registered")
 
 	log.Println("🛣️ Registering health check and error handlers...")
-	RegisterHealthRoutes(r, gormDB, authManager, encryptionManager)
+	healthChecker := services.NewHealthChecker(gormDB, redisClient, cfg.RedisURL != "", encryptionManager)
+	healthChecker.SetIntegration("fub", cfg.FUBAPIKey != "")
+	healthChecker.SetIntegration("scraper", cfg.ScraperAPIKey != "")
+	RegisterHealthRoutes(r, healthChecker)
 	log.Println("✅ Health check and error handlers registered")
 
 	// ============================================================================
@@ -692,8 +1059,70 @@ var leadSafetyFilter *services.LeadSafetyFilter
 	}
 
 	// Start server
-	log.Printf("🚀 Starting server on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	// No write timeout: WebSockets and exports stream for longer than any
+	// request timeout. Slow bodies are cut off by the read timeout.
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       cfg.UploadRequestTimeout,
+		IdleTimeout:       2 * time.Minute,
 	}
+
+	go func() {
+		log.Printf("🚀 Starting server on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for SIGINT/SIGTERM, then drain in-flight requests before exiting
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-quit
+	log.Printf("🛑 Received %s, shutting down gracefully...", sig)
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelShutdown()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⚠️  HTTP server shutdown did not complete cleanly: %v", err)
+	}
+
+	cancelApp()
+
+	// Let an in-flight intelligence cycle finish before the database closes
+	propertyHubAI.Stop()
+
+	if performanceMonitor != nil {
+		performanceMonitor.Stop()
+	}
+
+	// Stop the dispatcher before the batch service so no campaign email is queued onto a closed queue
+	if dripScheduler != nil {
+		dripScheduler.Stop()
+	}
+	if campaignDispatcher != nil {
+		campaignDispatcher.Stop()
+	}
+	if emailBatchService != nil {
+		emailBatchService.Stop()
+	}
+	if smtpPool != nil {
+		smtpPool.Close()
+	}
+
+	if redisClient != nil {
+		if err := redisClient.Close(); err != nil {
+			log.Printf("⚠️  Failed to close Redis client: %v", err)
+		}
+	}
+
+	if sqlDB != nil {
+		if err := sqlDB.Close(); err != nil {
+			log.Printf("⚠️  Failed to close database connection: %v", err)
+		}
+	}
+
+	log.Println("✅ Server stopped")
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -318,5 +319,54 @@ func RegisterAdminRoutes(r *gin.Engine, h *AllHandlers, propertyHubAI *services.
 			go propertyHubAI.RunIntelligenceCycle()
 			c.JSON(http.StatusOK, gin.H{"message": "Intelligence cycle triggered"})
 		})
+
+		// Automated Intelligence Cycle - Settings, last run, and pause/resume
+		admin.GET("/intelligence/cycle/status", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"status": propertyHubAI.AutomationStatus()})
+		})
+		admin.POST("/intelligence/cycle/pause", func(c *gin.Context) {
+			propertyHubAI.PauseAutomation()
+			c.JSON(http.StatusOK, gin.H{"success": true, "status": propertyHubAI.AutomationStatus()})
+		})
+		admin.POST("/intelligence/cycle/resume", func(c *gin.Context) {
+			propertyHubAI.ResumeAutomation()
+			c.JSON(http.StatusOK, gin.H{"success": true, "status": propertyHubAI.AutomationStatus()})
+		})
+
+		// Intelligence Cache - Hit/miss metrics and TTLs
+		admin.GET("/intelligence/cache/metrics", func(c *gin.Context) {
+			if propertyHubAI.Cache() == nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Intelligence cache not configured"})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"metrics": propertyHubAI.Cache().Metrics()})
+		})
+
+		// Intelligence Cache - Evict the listed leads, or everything when none are given
+		admin.POST("/intelligence/cache/invalidate", func(c *gin.Context) {
+			cache := propertyHubAI.Cache()
+			if cache == nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Intelligence cache not configured"})
+				return
+			}
+			var request struct {
+				LeadIDs []int64 `json:"lead_ids"`
+			}
+			if err := c.ShouldBindJSON(&request); err != nil && err != io.EOF {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+				return
+			}
+			var err error
+			if len(request.LeadIDs) > 0 {
+				err = cache.InvalidateLeads(request.LeadIDs)
+			} else {
+				err = cache.InvalidateAll()
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to invalidate intelligence cache", "details": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"success": true, "invalidated_leads": len(request.LeadIDs)})
+		})
 	}
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"time"
 
+	"chrisgross-ctrl-project/internal/auth"
 	"chrisgross-ctrl-project/internal/handlers"
 	"chrisgross-ctrl-project/internal/middleware"
 	"chrisgross-ctrl-project/internal/models"
@@ -12,17 +13,20 @@ import (
 )
 
 // RegisterAPIRoutes registers all API routes
-func RegisterAPIRoutes(api *gin.RouterGroup, h *AllHandlers, propertyValuationHandler *handlers.PropertyValuationHandlers, emailAutomationHandler *handlers.EmailAutomationHandlers) {
+func RegisterAPIRoutes(api *gin.RouterGroup, h *AllHandlers, propertyValuationHandler *handlers.PropertyValuationHandlers, emailAutomationHandler *handlers.EmailAutomationHandlers, authManager *auth.SimpleAuthManager) {
 	// ============================================================================
 	// WEBSOCKET - Real-time Updates
 	// ============================================================================
-	api.GET("/ws", h.WebSocket.HandleWebSocket)
-	api.GET("/ws/admin/activity", h.WebSocket.HandleAdminActivityFeed)
+	// Upgrades require an admin session (cookie, ?token= or token subprotocol)
+	// from this host or a CORS-allowed origin
+	wsAuth := middleware.WebSocketAuth(authManager, h.WebSocketOrigins)
+	api.GET("/ws", wsAuth, h.WebSocket.HandleWebSocket)
+	api.GET("/ws/admin/activity", wsAuth, h.WebSocket.HandleAdminActivityFeed)
 
 	// ============================================================================
 	// ADMIN NOTIFICATIONS - Real-time Alerts
 	// ============================================================================
-	api.GET("/notifications/ws", h.AdminNotification.HandleWebSocket)
+	api.GET("/notifications/ws", wsAuth, h.AdminNotification.HandleWebSocket)
 	api.GET("/notifications", h.AdminNotification.GetNotifications)
 	api.GET("/notifications/unread-count", h.AdminNotification.GetUnreadCount)
 	api.PUT("/notifications/:id/read", h.AdminNotification.MarkAsRead)
@@ -127,6 +131,8 @@ func RegisterAPIRoutes(api *gin.RouterGroup, h *AllHandlers, propertyValuationHa
 	api.PUT("/application-workflow/:id/status", h.ApplicationWorkflow.UpdateApplicationStatus)
 	api.POST("/application-workflow/assign-agent", h.ApplicationWorkflow.AssignAgentToApplication)
 	api.POST("/application-workflow/move-applicant", h.ApplicationWorkflow.MoveApplicantToApplication)
+	api.POST("/application-workflow/applicants/:id/screen", h.ApplicationWorkflow.ScreenApplicant)
+	api.GET("/application-workflow/:id/history", h.ApplicationWorkflow.GetApplicationHistory)
 	api.POST("/applications/:id/approve", h.ApplicationWorkflow.ApproveApplication)
 	api.POST("/applications/:id/deny", h.ApplicationWorkflow.DenyApplication)
 	api.POST("/applications/:id/request-info", h.ApplicationWorkflow.RequestMoreInfo)
@@ -156,7 +162,8 @@ func RegisterAPIRoutes(api *gin.RouterGroup, h *AllHandlers, propertyValuationHa
 	api.GET("/closing-pipeline", h.ClosingPipeline.GetClosingPipelines)
 	api.GET("/closing-pipeline/:id", h.ClosingPipeline.GetPipelineItem)
 	api.POST("/closing-pipeline", h.ClosingPipeline.CreatePipelineItem)
-	api.PUT("/closing-pipeline/:id/stage", h.ClosingPipeline.UpdatePipelineStage)
+	api.PUT("/closing-pipeline/:id/stage", h.ClosingPipeline.PutClosingPipelineStatus)
+	api.PUT("/closing-pipeline/:id/status", h.ClosingPipeline.PutClosingPipelineStatus)
 	api.PUT("/closing-pipeline/:id/lease-status", h.ClosingPipeline.UpdateLeaseWorkflowStatus)
 	api.DELETE("/closing-pipeline/:id", h.ClosingPipeline.DeletePipelineItem)
 
@@ -211,18 +218,22 @@ func RegisterAPIRoutes(api *gin.RouterGroup, h *AllHandlers, propertyValuationHa
 
 	// HAR Market API removed - HAR blocked access
 
-	// Lead Reengagement API
-	api.GET("/leads/list", h.LeadReengagement.GetLeads)
+	// Lead Reengagement API - lead queries are scoped to the authenticated agent
+	api.GET("/leads/list", middleware.AuthRequired(authManager), h.LeadReengagement.GetLeads)
 	api.GET("/leads/metrics", h.LeadReengagement.GetMetrics)
-	api.GET("/leads/segment-stats", h.LeadReengagement.GetSegmentStats)
+	api.GET("/leads/segment-stats", middleware.AuthRequired(authManager), h.LeadReengagement.GetSegmentStats)
 	api.GET("/leads/safety-status", h.LeadReengagement.GetSafetyStatus)
 	api.GET("/leads/templates", h.LeadReengagement.GetTemplates)
-	api.POST("/leads/import", h.LeadReengagement.ImportLeads)
+	api.POST("/leads/import", middleware.AuthRequired(authManager), h.LeadReengagement.ImportLeads)
+	api.POST("/leads/import-csv", middleware.AuthRequired(authManager), h.LeadReengagement.ImportLeadsCSV)
 	api.POST("/leads/segment", h.LeadReengagement.SegmentLeads)
-	api.POST("/leads/prepare-campaign", h.LeadReengagement.PrepareCampaign)
+	api.POST("/leads/prepare-campaign", middleware.AuthRequired(authManager), h.LeadReengagement.PrepareCampaign)
 	api.POST("/leads/template", h.LeadReengagement.CreateTemplate)
 	api.POST("/leads/emergency-stop", h.LeadReengagement.EmergencyStopAll)
 
+	// Lead Re-engagement API v1 (/api/v1/reengagement/...)
+	h.LeadReengagement.RegisterRoutes(api.Group("/v1", middleware.AuthRequired(authManager)))
+
 	api.GET("/leads", h.LeadsList.GetAllLeads)
 	
 	// Bulk Lead Operations
@@ -262,6 +273,9 @@ func RegisterAPIRoutes(api *gin.RouterGroup, h *AllHandlers, propertyValuationHa
 	// API v1 Aliases - for backward compatibility with frontend JavaScript
 	v1 := api.Group("/v1")
 	{
+		// CSRF token for JavaScript clients (send it back in the X-CSRF-Token header)
+		v1.GET("/csrf-token", middleware.CSRFTokenHandler)
+
 		v1.GET("/properties", h.Properties.GetPropertiesGin)
 		v1.GET("/properties/:id", h.Properties.GetPropertyByIDGin)
 		v1.POST("/properties/search", h.Properties.SearchPropertiesPost)
@@ -270,14 +284,151 @@ func RegisterAPIRoutes(api *gin.RouterGroup, h *AllHandlers, propertyValuationHa
 		v1.GET("/properties/saved", h.SavedProperties.GetSavedProperties)
 		v1.GET("/properties/:id/is-saved", h.SavedProperties.CheckIfSaved)
 		v1.GET("/properties/:id/similar", h.Recommendations.GetSimilarProperties)
-		v1.POST("/bookings", middleware.BookingRateLimiter.RateLimit(), h.Booking.CreateBooking)
+		v1.POST("/bookings", middleware.BookingRateLimiter.RateLimit(), middleware.ValidateJSON(models.BookingRequest{}), h.Booking.CreateBooking)
 		v1.GET("/bookings/:id", h.Booking.GetBooking)
 		v1.POST("/bookings/:id/cancel", h.Booking.CancelBooking)
 		v1.GET("/bookings", h.Booking.ListBookings)
 		v1.POST("/bookings/:id/complete", h.Booking.MarkCompleted)
 		v1.POST("/bookings/:id/no-show", h.Booking.MarkNoShow)
 		v1.PUT("/bookings/:id/reschedule", h.Booking.RescheduleBooking)
+		v1.POST("/email/reprocess-failed", middleware.AuthRequired(authManager), h.EmailSender.ReprocessFailedEmails)
+		v1.GET("/email/reprocess-failed/:job_id", middleware.AuthRequired(authManager), h.EmailSender.GetReprocessJobStatus)
+		v1.GET("/compliance/emergency/status", middleware.AuthRequired(authManager), h.LeadReengagement.GetEmergencyStatus)
+		v1.POST("/compliance/emergency/deactivate", middleware.AuthRequired(authManager), h.LeadReengagement.DeactivateEmergencyStop)
+		v1.GET("/performance/metrics", middleware.AuthRequired(authManager), h.PerformanceMonitoring.GetMetrics)
+		v1.POST("/dashboard/cache/invalidate", middleware.AuthRequired(authManager), h.TieredStats.InvalidateDashboardCache)
 	}
+
+	v1.GET("/leads/search", middleware.AuthRequired(authManager), h.LeadReengagement.SearchLeads)
+	v1.POST("/leads/bulk/status", middleware.AuthRequired(authManager), h.BulkOperations.BulkUpdateLeadStatus)
+
+	// Lead deduplication
+	v1.GET("/leads/duplicates", middleware.AuthRequired(authManager), h.LeadReengagement.GetDuplicateLeads)
+	v1.POST("/leads/merge", middleware.AuthRequired(authManager), h.LeadReengagement.MergeLeads)
+	v1.GET("/leads/merges", middleware.AuthRequired(authManager), h.LeadReengagement.GetLeadMerges)
+
+	// Data retention (dry-run preview and audit trail)
+	v1.GET("/data-retention/preview", middleware.AuthRequired(authManager), h.DataRetention.GetRetentionPreview)
+	v1.GET("/data-retention/logs", middleware.AuthRequired(authManager), h.DataRetention.GetRetentionLogs)
+
+	// Outbound SMS, gated on TCPA consent
+	v1.POST("/communications/sms", middleware.AuthRequired(authManager), h.CommunicationSMS.PostCommunicationSendSMS)
+
+	// Data subject requests (GDPR/CCPA export and erasure)
+	v1.POST("/privacy/export", middleware.AuthRequired(authManager), h.Privacy.ExportSubjectData)
+	v1.POST("/privacy/erasure", middleware.AuthRequired(authManager), h.Privacy.EraseSubjectData)
+	v1.GET("/privacy/requests", middleware.AuthRequired(authManager), h.Privacy.GetPrivacyRequests)
+
+	// IP access lists (admin allowlist, site-wide denylist)
+	v1.GET("/admin/ip-access/rules", middleware.AuthRequired(authManager), h.IPAccess.GetIPAccessRules)
+	v1.POST("/admin/ip-access/rules", middleware.AuthRequired(authManager), h.IPAccess.CreateIPAccessRule)
+	v1.DELETE("/admin/ip-access/rules/:id", middleware.AuthRequired(authManager), h.IPAccess.DeleteIPAccessRule)
+	v1.POST("/admin/ip-access/security-events/:id/deny", middleware.AuthRequired(authManager), h.IPAccess.DenySecurityEventIP)
+
+	// Exports (CSV or XLSX, ?format=)
+	v1.GET("/leads/export", middleware.AuthRequired(authManager), h.LeadReengagement.ExportLeads)
+	v1.GET("/campaigns/export", middleware.AuthRequired(authManager), h.LeadReengagement.ExportCampaigns)
+
+	// Campaign dead-letter queue (failed executions and manual requeue)
+	v1.GET("/campaigns/executions/failed", middleware.AuthRequired(authManager), h.LeadReengagement.GetFailedExecutions)
+	v1.POST("/campaigns/executions/:id/requeue", middleware.AuthRequired(authManager), h.LeadReengagement.RequeueExecution)
+
+	// Property trash (soft-deleted properties can be restored)
+	v1.GET("/properties/trash", middleware.AuthRequired(authManager), h.Properties.ListDeletedProperties)
+	v1.DELETE("/properties/:id", middleware.AuthRequired(authManager), h.Properties.DeletePropertyGin)
+	v1.POST("/properties/:id/restore", middleware.AuthRequired(authManager), h.Properties.RestoreProperty)
+
+	// Encryption key rotation (re-encrypt PII to the current key; MFA re-verification required)
+	v1.POST("/admin/encryption/rotate", middleware.AuthRequired(authManager), middleware.RequireMFAReverification(h.MFAReverification), h.EncryptionRotation.StartRotation)
+	v1.GET("/admin/encryption/rotate/:job_id", middleware.AuthRequired(authManager), h.EncryptionRotation.GetRotationJob)
+
+	// Compliance alerts, scoring config and check history
+	v1.GET("/compliance/alerts", middleware.AuthRequired(authManager), h.ComplianceAlerts.ListAlerts)
+	v1.POST("/compliance/alerts/:id/acknowledge", middleware.AuthRequired(authManager), h.ComplianceAlerts.AcknowledgeAlert)
+	v1.POST("/compliance/alerts/:id/resolve", middleware.AuthRequired(authManager), h.ComplianceAlerts.ResolveAlert)
+	v1.GET("/compliance/config", middleware.AuthRequired(authManager), h.ComplianceConfig.GetConfig)
+	v1.PUT("/compliance/config", middleware.AuthRequired(authManager), h.ComplianceConfig.UpdateConfig)
+	v1.GET("/compliance/history", middleware.AuthRequired(authManager), h.ComplianceHistory.GetHistory)
+	v1.POST("/compliance/dnc/import", middleware.AuthRequired(authManager), h.ComplianceDNC.ImportList)
+	v1.GET("/compliance/dnc/suppressions", middleware.AuthRequired(authManager), h.ComplianceDNC.ListSuppressions)
+
+	// Context intelligence webhook replay and scoring config
+	v1.POST("/context-fub/replay", middleware.AuthRequired(authManager), h.ContextFUB.ReplayWebhooks)
+	v1.GET("/context-fub/scoring-config", middleware.AuthRequired(authManager), h.ContextFUB.GetScoringConfig)
+	v1.PUT("/context-fub/scoring-config", middleware.AuthRequired(authManager), h.ContextFUB.UpdateScoringConfig)
+
+	// Lead score explanation and ranked property matches
+	v1.GET("/leads/:id/score-explanation", middleware.AuthRequired(authManager), h.CommandCenter.GetScoreExplanation)
+	v1.GET("/leads/:id/property-matches", middleware.AuthRequired(authManager), h.CommandCenter.GetPropertyMatches)
+
+	// Lead routing: assign by round robin, weight or territory, or reassign by hand
+	v1.POST("/leads", middleware.AuthRequired(authManager), h.LeadRouting.CreateLead)
+	v1.POST("/leads/:id/route", middleware.AuthRequired(authManager), h.LeadRouting.RouteLead)
+	v1.POST("/leads/:id/reassign", middleware.AuthRequired(authManager), h.LeadRouting.ReassignLead)
+	v1.GET("/leads/:id/assignments", middleware.AuthRequired(authManager), h.LeadRouting.GetLeadAssignments)
+	v1.GET("/leads/:id/safety", middleware.AuthRequired(authManager), h.LeadSafety.GetLeadSafety)
+	v1.GET("/lead-routing/config", middleware.AuthRequired(authManager), h.LeadRouting.GetRoutingConfig)
+	v1.PUT("/lead-routing/config", middleware.AuthRequired(authManager), h.LeadRouting.UpdateRoutingConfig)
+	v1.PUT("/lead-routing/agents/:agent_id", middleware.AuthRequired(authManager), h.LeadRouting.UpdateAgentProfile)
+
+	// Agent daily plan: showings, follow-ups booked into business hours, overdue work
+	v1.GET("/schedule/daily", middleware.AuthRequired(authManager), h.DailySchedule.GetAgentDailyPlan)
+	v1.POST("/schedule/daily", middleware.AuthRequired(authManager), h.DailySchedule.RegenerateDailyPlan)
+
+	// Applications started on the site, and reminders to finish them
+	v1.POST("/applications/started", h.ApplicationRecovery.RecordApplicationStarted)
+	v1.GET("/application-recovery/report", middleware.AuthRequired(authManager), h.ApplicationRecovery.GetRecoveryReport)
+
+	// Conversion funnel, cohort retention and attribution
+	v1.GET("/analytics/funnel", middleware.AuthRequired(authManager), h.FunnelAnalytics.GetConversionFunnel)
+	v1.GET("/analytics/cohorts", middleware.AuthRequired(authManager), h.BusinessIntelligence.GetCohortRetention)
+	v1.GET("/analytics/attribution", middleware.AuthRequired(authManager), h.Attribution.GetAttribution)
+
+	// Campaign automation fed by behavioral events (only if Redis available)
+	if h.CampaignAutomation != nil {
+		v1.GET("/campaign-automation/status", middleware.AuthRequired(authManager), h.CampaignAutomation.GetStatus)
+		v1.POST("/campaign-automation/:name/enable", middleware.AuthRequired(authManager), h.CampaignAutomation.EnableAutomation)
+		v1.POST("/campaign-automation/:name/disable", middleware.AuthRequired(authManager), h.CampaignAutomation.DisableAutomation)
+	}
+
+	// Command center tasks converted from high-confidence insights
+	v1.GET("/command-center/tasks", middleware.AuthRequired(authManager), h.CommandCenter.GetTasks)
+	v1.POST("/command-center/tasks", middleware.AuthRequired(authManager), h.CommandCenter.CreateTask)
+	v1.POST("/command-center/tasks/generate", middleware.AuthRequired(authManager), h.CommandCenter.GenerateTasks)
+	v1.POST("/command-center/tasks/:id/complete", middleware.AuthRequired(authManager), h.CommandCenter.CompleteTask)
+	v1.POST("/command-center/tasks/:id/snooze", middleware.AuthRequired(authManager), h.CommandCenter.SnoozeTask)
+	v1.POST("/command-center/tasks/:id/assign", middleware.AuthRequired(authManager), h.CommandCenter.AssignTask)
+
+	// Saved searches (alerts on new and re-priced matching listings)
+	v1.POST("/saved-searches", middleware.AuthRequired(authManager), h.SavedSearches.CreateSavedSearch)
+	v1.GET("/saved-searches", middleware.AuthRequired(authManager), h.SavedSearches.ListSavedSearches)
+	v1.DELETE("/saved-searches/:id", middleware.AuthRequired(authManager), h.SavedSearches.DeleteSavedSearch)
+
+	// Contact timeline (contact, leads, behavior, campaigns, emails, approvals)
+	v1.GET("/contacts/:id/timeline", middleware.AuthRequired(authManager), h.ContactTimeline.GetContactTimeline)
+
+	// Incoming email attachments
+	v1.GET("/email/incoming/:id/attachments", middleware.AuthRequired(authManager), h.EmailSender.GetIncomingEmailAttachments)
+	v1.GET("/email/attachments/:id", middleware.AuthRequired(authManager), h.EmailSender.DownloadEmailAttachment)
+
+	// Admin TOTP enrollment and MFA recovery codes
+	v1.POST("/admin/mfa/totp/enroll", middleware.AuthRequired(authManager), h.MFA.EnrollTOTP)
+	v1.POST("/admin/mfa/totp/confirm", middleware.AuthRequired(authManager), h.MFA.ConfirmTOTP)
+	v1.POST("/admin/mfa/backup-codes/regenerate", middleware.AuthRequired(authManager), h.MFA.RegenerateBackupCodes)
+	v1.POST("/admin/mfa/reverify", middleware.AuthRequired(authManager), h.MFA.ReverifyMFA)
+
+	// Admin notifications with per-admin read state
+	v1.GET("/admin/notifications", middleware.AuthRequired(authManager), h.AdminNotification.ListAdminNotifications)
+	v1.GET("/admin/notifications/unread-count", middleware.AuthRequired(authManager), h.AdminNotification.GetAdminUnreadCount)
+	v1.POST("/admin/notifications/:id/read", middleware.AuthRequired(authManager), h.AdminNotification.MarkAdminNotificationRead)
+	v1.POST("/admin/notifications/mark-all-read", middleware.AuthRequired(authManager), h.AdminNotification.MarkAllAdminNotificationsRead)
+
+	// Daily digest preview; admins opt in with a "daily_digest" notification preference
+	v1.GET("/admin/digest/preview", middleware.AuthRequired(authManager), h.DailyDigest.PreviewDigest)
+
+	// Admin notification preferences (channels and quiet hours per event type)
+	v1.GET("/admin/notification-preferences", middleware.AuthRequired(authManager), h.AdminNotification.GetNotificationPreferences)
+	v1.PUT("/admin/notification-preferences", middleware.AuthRequired(authManager), h.AdminNotification.UpdateNotificationPreferences)
 	
 	// Live Activity API (Admin Real-Time)
 	api.GET("/admin/live-activity", h.LiveActivity.GetLiveActivity)
@@ -322,23 +473,33 @@ func RegisterAPIRoutes(api *gin.RouterGroup, h *AllHandlers, propertyValuationHa
 		api.GET("/valuation/comparables/:id", propertyValuationHandler.GetComparableProperties)
 		api.GET("/valuation/bulk", propertyValuationHandler.GetBulkValuations)
 		api.GET("/valuation/performance", propertyValuationHandler.GetPerformanceReport)
+		api.GET("/valuation/cache/stats", propertyValuationHandler.GetValuationCacheStats)
+		api.GET("/v1/valuation/scraper/health", propertyValuationHandler.GetScraperHealth)
+		api.POST("/v1/valuation/bulk", propertyValuationHandler.PostValuationBulkRequest)
+		api.GET("/v1/valuation/bulk/:batch_id", propertyValuationHandler.GetValuationBulkStatus)
+		api.GET("/v1/valuation/stats", propertyValuationHandler.GetValuationStats)
 		api.POST("/valuation/calibrate", propertyValuationHandler.CalibrateValuationModel)
 		api.POST("/valuation/test-accuracy", propertyValuationHandler.TestValuationAccuracy)
 	}
 
 	// Security API
 	api.GET("/security/metrics", h.SecurityMonitoring.GetSecurityMetrics)
-	api.GET("/security/events", h.SecurityMonitoring.GetSecurityEvents)
-	api.GET("/security/events/:id", h.SecurityMonitoring.GetSecurityEventDetails)
+	api.GET("/security/events", middleware.AuthRequired(authManager), h.SecurityMonitoring.GetSecurityEvents)
+	api.GET("/security/events/:id", middleware.AuthRequired(authManager), h.SecurityMonitoring.GetSecurityEventDetails)
 	api.POST("/security/events", h.SecurityMonitoring.CreateSecurityEvent)
 	api.POST("/security/session", h.SecurityMonitoring.CreateSecuritySession)
-	api.PUT("/security/events/:id/resolve", h.SecurityMonitoring.ResolveSecurityEvent)
+	api.POST("/security/events/:id/resolve", middleware.AuthRequired(authManager), h.SecurityMonitoring.PostSecurityEventResolve)
+	api.GET("/security/audit-logs", middleware.AuthRequired(authManager), h.SecurityMonitoring.GetSecurityAuditLogs)
 
 	// Advanced Security API
 	api.GET("/security/advanced/metrics", h.AdvancedSecurityAPI.GetSecurityMetrics)
 	api.POST("/security/advanced/session", h.AdvancedSecurityAPI.CreateSecuritySession)
 	api.POST("/security/advanced/analyze-threat", h.AdvancedSecurityAPI.AnalyzeThreat)
 
+	// Encryption key status and re-encryption backfill (MFA re-verification required)
+	v1.GET("/security/encryption/status", middleware.AuthRequired(authManager), middleware.RequireMFAReverification(h.MFAReverification), h.AdvancedSecurityAPI.GetEncryptionStatus)
+	v1.POST("/security/encryption/rotate", middleware.AuthRequired(authManager), middleware.RequireMFAReverification(h.MFAReverification), h.AdvancedSecurityAPI.RotateEncryption)
+
 	// Unsubscribe API
 	api.GET("/unsubscribe/list", h.Unsubscribe.GetUnsubscribeList)
 	api.GET("/unsubscribe/stats", h.Unsubscribe.GetUnsubscribeStats)
@@ -368,13 +529,7 @@ func RegisterAPIRoutes(api *gin.RouterGroup, h *AllHandlers, propertyValuationHa
 	// Lead Assignment API
 	api.POST("/admin/leads/assign", h.Team.AssignLead)
 
-	api.POST("/admin/leads/add", func(c *gin.Context) {
-		// TODO: Add new lead manually
-		c.JSON(200, gin.H{
-			"success": true,
-			"message": "Lead added successfully",
-		})
-	})
+	api.POST("/admin/leads/add", middleware.AuthRequired(authManager), h.LeadRouting.CreateLead)
 
 	// ============================================================================
 	// CONTACT FORM API
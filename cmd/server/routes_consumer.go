@@ -213,12 +213,22 @@ func RegisterConsumerRoutes(r *gin.Engine, h *AllHandlers, cfg *config.Config) {
 	r.GET("/sitemap", func(c *gin.Context) {
 		c.HTML(200, "consumer/pages/sitemap.html", gin.H{"Title": "Sitemap"})
 	})
+	// Signed campaign unsubscribe link; POST is RFC 8058 one-click
+	r.GET("/unsubscribe", h.Unsubscribe.HandleTokenUnsubscribe)
+	r.POST("/unsubscribe", h.Unsubscribe.HandleTokenUnsubscribe)
+	// Signed daily digest unsubscribe link for admins
+	r.GET("/digest/unsubscribe", h.DailyDigest.HandleUnsubscribe)
+	r.POST("/digest/unsubscribe", h.DailyDigest.HandleUnsubscribe)
 	r.GET("/unsubscribe/error", func(c *gin.Context) {
 		c.HTML(200, "consumer/pages/unsubscribe_error.html", gin.H{"Title": "Unsubscribe Error"})
 	})
 	r.GET("/unsubscribe/success", func(c *gin.Context) {
 		c.HTML(200, "consumer/pages/unsubscribe_success.html", gin.H{"Title": "Unsubscribe Success"})
 	})
+	// Campaign email open/click tracking
+	r.GET("/t/open/:executionID", h.LeadReengagement.TrackOpen)
+	r.GET("/t/click/:executionID", h.LeadReengagement.TrackClick)
+
 	r.GET("/trec-compliance", func(c *gin.Context) {
 		c.HTML(200, "consumer/pages/trec-compliance.html", gin.H{"Title": "TREC Compliance"})
 	})
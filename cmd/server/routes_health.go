@@ -2,26 +2,45 @@ package main
 
 import (
 	"fmt"
-	"time"
+	"net/http"
 
-	"chrisgross-ctrl-project/internal/auth"
-	"chrisgross-ctrl-project/internal/security"
+	"chrisgross-ctrl-project/internal/services"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 // RegisterHealthRoutes registers health check and error handling routes
-func RegisterHealthRoutes(r *gin.Engine, gormDB *gorm.DB, authManager *auth.SimpleAuthManager, encryptionManager *security.EncryptionManager) {
-	// Health check endpoint
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":              "ok",
-			"mode":                "enterprise",
-			"database":            gormDB != nil,
-			"enterprise_auth":     authManager != nil,
-			"enterprise_security": encryptionManager != nil,
-			"timestamp":           time.Now(),
+func RegisterHealthRoutes(r *gin.Engine, healthChecker *services.HealthChecker) {
+	// Health check endpoint: probes every dependency and returns 503 when a
+	// critical one (database, encryption) is down
+	healthHandler := func(c *gin.Context) {
+		report := healthChecker.Check(c.Request.Context())
+		status := http.StatusOK
+		if !report.Healthy() {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{
+			"status":     report.Status,
+			"mode":       "enterprise",
+			"components": report.Components,
+			"timestamp":  report.Timestamp,
+		})
+	}
+	r.GET("/health", healthHandler)
+	r.GET("/healthz", healthHandler)
+
+	// Readiness endpoint: only the components needed to serve requests
+	r.GET("/readyz", func(c *gin.Context) {
+		report := healthChecker.Ready(c.Request.Context())
+		status := http.StatusOK
+		if !report.Healthy() {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{
+			"ready":      report.Healthy(),
+			"status":     report.Status,
+			"components": report.Components,
+			"timestamp":  report.Timestamp,
 		})
 	})
 
@@ -10,6 +10,8 @@ import (
 
 	"github.com/dustin/go-humanize"
 	"github.com/flosch/pongo2/v6"
+
+	"chrisgross-ctrl-project/internal/templates"
 )
 
 func main() {
@@ -54,6 +56,36 @@ func main() {
 		log.Fatalf("Failed to walk templates: %v", err)
 	}
 
+	// Render the filters whose output depends on their input type
+	filterChecks := []struct {
+		name     string
+		template string
+		ctx      pongo2.Context
+		want     string
+	}{
+		{"timeAgo past", "{{ t|timeAgo }}", pongo2.Context{"t": time.Now().Add(-2 * time.Hour)}, "2 hours ago"},
+		{"timeAgo future", "{{ t|timeAgo }}", pongo2.Context{"t": time.Now().Add(72*time.Hour + time.Minute)}, "3 days from now"},
+		{"timeAgo now", "{{ t|timeAgo }}", pongo2.Context{"t": time.Now()}, "just now"},
+		{"timeAgo zero", "{{ t|timeAgo }}", pongo2.Context{"t": time.Time{}}, ""},
+		{"markdown", "{{ md|markdown }}", pongo2.Context{"md": "**Hi** <script>x</script>"}, "<p><strong>Hi</strong> &lt;script&gt;x&lt;/script&gt;</p>\n"},
+	}
+	for _, check := range filterChecks {
+		tpl, parseErr := templateSet.FromString(check.template)
+		if parseErr != nil {
+			errors = append(errors, fmt.Sprintf("❌ filter %s: %v", check.name, parseErr))
+			continue
+		}
+		out, execErr := tpl.Execute(check.ctx)
+		if execErr != nil {
+			errors = append(errors, fmt.Sprintf("❌ filter %s: %v", check.name, execErr))
+		} else if out != check.want {
+			errors = append(errors, fmt.Sprintf("❌ filter %s: got %q, want %q", check.name, out, check.want))
+		} else {
+			passed++
+			log.Printf("✅ filter %s", check.name)
+		}
+	}
+
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Printf("📊 Results: %d passed", passed)
 	if len(errors) > 0 {
@@ -71,18 +103,7 @@ func main() {
 }
 
 func registerFilters() {
-	pongo2.RegisterFilter("formatPrice", func(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
-		switch v := in.Interface().(type) {
-		case int:
-			return pongo2.AsValue(fmt.Sprintf("$%s", humanize.Comma(int64(v)))), nil
-		case int64:
-			return pongo2.AsValue(fmt.Sprintf("$%s", humanize.Comma(v))), nil
-		case float64:
-			return pongo2.AsValue(fmt.Sprintf("$%s", humanize.CommafWithDigits(v, 2))), nil
-		default:
-			return pongo2.AsValue(fmt.Sprintf("%v", v)), nil
-		}
-	})
+	pongo2.RegisterFilter("formatPrice", templates.FilterFormatPrice)
 
 	pongo2.RegisterFilter("formatDate", func(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
 		if t, ok := in.Interface().(time.Time); ok {
@@ -91,6 +112,10 @@ func registerFilters() {
 		return in, nil
 	})
 
+	pongo2.RegisterFilter("timeAgo", templates.FilterTimeAgo)
+
+	pongo2.RegisterFilter("markdown", templates.FilterMarkdown)
+
 	pongo2.RegisterFilter("formatNumber", func(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
 		switch v := in.Interface().(type) {
 		case int:
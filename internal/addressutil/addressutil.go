@@ -0,0 +1,144 @@
+// Package addressutil normalizes street addresses so the same property matches
+// regardless of how an email, form, or import happened to format it.
+package addressutil
+
+import (
+	"regexp"
+	"strings"
+)
+
+// streetSuffixes maps spelled-out street types to their USPS abbreviations
+var streetSuffixes = map[string]string{
+	"street":     "st",
+	"str":        "st",
+	"avenue":     "ave",
+	"av":         "ave",
+	"boulevard":  "blvd",
+	"drive":      "dr",
+	"road":       "rd",
+	"lane":       "ln",
+	"court":      "ct",
+	"circle":     "cir",
+	"place":      "pl",
+	"parkway":    "pkwy",
+	"pky":        "pkwy",
+	"freeway":    "fwy",
+	"highway":    "hwy",
+	"expressway": "expy",
+	"trail":      "trl",
+	"terrace":    "ter",
+	"square":     "sq",
+	"crossing":   "xing",
+	"point":      "pt",
+	"bend":       "bnd",
+	"hollow":     "holw",
+	"meadows":    "mdws",
+	"ridge":      "rdg",
+	"grove":      "grv",
+	"creek":      "crk",
+	"cove":       "cv",
+}
+
+// directionals maps compass words to their abbreviations
+var directionals = map[string]string{
+	"north":     "n",
+	"south":     "s",
+	"east":      "e",
+	"west":      "w",
+	"northeast": "ne",
+	"northwest": "nw",
+	"southeast": "se",
+	"southwest": "sw",
+}
+
+// unitDesignators are words that introduce a unit number; all collapse to "unit"
+var unitDesignators = map[string]bool{
+	"#":         true,
+	"apt":       true,
+	"apartment": true,
+	"unit":      true,
+	"ste":       true,
+	"suite":     true,
+}
+
+// houstonAreaCities are dropped from the end of a comma-less address when they
+// precede the state, e.g. "123 Main St Houston TX"
+var houstonAreaCities = map[string]bool{
+	"houston":  true,
+	"katy":     true,
+	"cypress":  true,
+	"spring":   true,
+	"humble":   true,
+	"pearland": true,
+	"bellaire": true,
+	"tomball":  true,
+}
+
+var (
+	// Anything other than letters, digits, '#', '-', '/', ',' and spaces is dropped
+	addressStripPattern = regexp.MustCompile(`[^a-z0-9#\-/, ]+`)
+	zipPattern          = regexp.MustCompile(`^\d{5}(-\d{4})?$`)
+)
+
+// Normalize returns the canonical form of a street address: lowercase, single
+// spaced, with street types and directionals abbreviated and any unit written as
+// "unit <id>". Comma-separated city, state, and ZIP parts are dropped so
+// "123 Main Street, Houston, TX 77002" and "123 MAIN ST" normalize the same.
+// It returns "" when raw contains no address.
+func Normalize(raw string) string {
+	cleaned := strings.ToLower(raw)
+	cleaned = strings.ReplaceAll(cleaned, ".", "")
+	cleaned = strings.ReplaceAll(cleaned, "#", " # ")
+	cleaned = addressStripPattern.ReplaceAllString(cleaned, " ")
+
+	parts := strings.Split(cleaned, ",")
+	tokens := strings.Fields(parts[0])
+	// Later comma parts are kept only when they hold a unit, e.g. "123 Main St, Apt 4"
+	for _, part := range parts[1:] {
+		fields := strings.Fields(part)
+		if len(fields) > 0 && unitDesignators[fields[0]] {
+			tokens = append(tokens, fields...)
+		}
+	}
+
+	tokens = trimTrailingLocality(tokens)
+
+	normalized := make([]string, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if unitDesignators[token] {
+			// Collapse "apt # 4", "suite 4", "#4" into "unit 4"
+			for i+1 < len(tokens) && unitDesignators[tokens[i+1]] {
+				i++
+			}
+			if i+1 < len(tokens) {
+				normalized = append(normalized, "unit", strings.TrimLeft(tokens[i+1], "-"))
+				i++
+			}
+			continue
+		}
+		if abbreviation, ok := streetSuffixes[token]; ok && i > 0 {
+			token = abbreviation
+		} else if abbreviation, ok := directionals[token]; ok {
+			token = abbreviation
+		}
+		normalized = append(normalized, token)
+	}
+
+	return strings.Join(normalized, " ")
+}
+
+// trimTrailingLocality drops a trailing ZIP code, Texas state token, and the
+// Houston-area city before it from an address that was written without commas
+func trimTrailingLocality(tokens []string) []string {
+	if n := len(tokens); n > 1 && zipPattern.MatchString(tokens[n-1]) {
+		tokens = tokens[:n-1]
+	}
+	if n := len(tokens); n > 1 && (tokens[n-1] == "tx" || tokens[n-1] == "texas") {
+		tokens = tokens[:n-1]
+		if n := len(tokens); n > 2 && houstonAreaCities[tokens[n-1]] {
+			tokens = tokens[:n-1]
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,87 @@
+package addressutil
+
+import "testing"
+
+func TestNormalize_HoustonAddressVariants(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants []string
+		want     string
+	}{
+		{
+			name: "street suffix and casing",
+			variants: []string{
+				"1234 Westheimer Road",
+				"1234 WESTHEIMER RD",
+				"1234 westheimer rd.",
+				"  1234   Westheimer  Rd ",
+			},
+			want: "1234 westheimer rd",
+		},
+		{
+			name: "directional prefix",
+			variants: []string{
+				"500 West Gray Street",
+				"500 W. Gray St",
+				"500 w gray st.",
+			},
+			want: "500 w gray st",
+		},
+		{
+			name: "unit suffixes",
+			variants: []string{
+				"2100 Kirby Drive Apt 4B",
+				"2100 Kirby Dr #4B",
+				"2100 Kirby Dr, Unit 4B",
+				"2100 Kirby Dr Apartment # 4b",
+				"2100 Kirby Dr. Suite 4B",
+			},
+			want: "2100 kirby dr unit 4b",
+		},
+		{
+			name: "city, state, and zip",
+			variants: []string{
+				"8800 Katy Freeway, Houston, TX 77024",
+				"8800 Katy Fwy, Houston, Texas",
+				"8800 Katy Fwy Houston TX 77024-1234",
+				"8800 KATY FWY",
+			},
+			want: "8800 katy fwy",
+		},
+		{
+			name: "parkway and boulevard",
+			variants: []string{
+				"1500 Memorial Parkway",
+				"1500 Memorial Pkwy",
+				"1500 Memorial Pky",
+			},
+			want: "1500 memorial pkwy",
+		},
+		{
+			name: "street name that looks like a city",
+			variants: []string{
+				"45 Spring Creek Boulevard, Spring, TX",
+				"45 Spring Creek Blvd Spring TX 77373",
+			},
+			want: "45 spring crk blvd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, variant := range tt.variants {
+				if got := Normalize(variant); got != tt.want {
+					t.Errorf("Normalize(%q) = %q, want %q", variant, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalize_EmptyInput(t *testing.T) {
+	for _, raw := range []string{"", "   ", ",", "#"} {
+		if got := Normalize(raw); got != "" {
+			t.Errorf("Normalize(%q) = %q, want empty", raw, got)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -19,23 +20,49 @@ type Config struct {
         Port        string
         Environment string
         LogLevel    string
+        LogFormat   string // "json" or "text" structured request logs
+
+        // Re-parse page templates on every render instead of caching them
+        TemplateReload bool
 
         // Everything else from database
         JWTSecret          string
         EncryptionKey      string
         SessionTimeout     time.Duration
         MFARequired        bool
+        MFAReverifyWindow  time.Duration // How long an MFA re-verification unlocks sensitive operations
         RateLimitPerMinute int
+        RateLimitByAdmin   bool // Key API rate limits on the authenticated admin instead of IP
+
+        // Admin JWT lifetimes
+        AdminAccessTokenTTL  time.Duration
+        AdminRefreshTokenTTL time.Duration
 
         // External services (all from database)
         FUBAPIKey     string
         FUBAPIURL     string
         ScraperAPIKey string // Generic scraper for property data
 
+        // Scraper resilience
+        ScraperMaxAttempts      int
+        ScraperBreakerThreshold int
+        ScraperBreakerCooldown  time.Duration
+
         // TREC compliance (from database)
         TRECComplianceEnabled bool
         AuditLogRetentionDays int
 
+        // Leads: restrict agents to leads assigned to them in bulk operations
+        LeadAgentScoping bool
+
+        // Data retention: behavioral events older than DataRetentionEventDays are
+        // purged and leads inactive for DataRetentionLeadInactiveMonths are
+        // anonymized. Only reports what it would do while DataRetentionDryRun is set.
+        DataRetentionEventDays          int
+        DataRetentionLeadInactiveMonths int
+        DataRetentionDryRun             bool
+        DataRetentionInterval           time.Duration
+
         // Redis configuration (from database)
         RedisURL      string
         RedisPassword string
@@ -47,6 +74,13 @@ type Config struct {
         SMTPUsername string
         SMTPPassword string
 
+        // Pooled SMTP sending for email batches (SES is used when disabled)
+        SMTPEnabled                  bool
+        SMTPUseTLS                   bool // Implicit TLS on port 465; otherwise STARTTLS when offered
+        SMTPMaxConnections           int
+        SMTPKeepAlive                time.Duration // Idle connections older than this are not reused
+        SMTPMaxMessagesPerConnection int
+
         // SendGrid configuration (from database)
         SendGridAPIKey    string
         EmailFromAddress  string
@@ -57,6 +91,133 @@ type Config struct {
         TwilioAuthToken  string
         TwilioPhoneNumber string
 
+        // Public URL used in outbound links and tracking (from database)
+        PublicBaseURL  string
+        TrackingSecret string // Signs click and digest links; must differ from JWT_SECRET
+
+        // Campaign dispatch rate shaping, in sends per minute (0 = unlimited)
+        CampaignSenderRatePerMinute int
+        CampaignDomainRatePerMinute int
+        CampaignDomainRateLimits    map[string]int // Per-domain overrides, e.g. gmail.com=30
+
+        // Campaign send retries; failures past the last attempt are dead-lettered
+        CampaignMaxSendAttempts     int
+        CampaignRetryBackoffMinutes int // Doubles after each failed attempt
+
+        // Sending domain warmup (disabled while the start date is unset)
+        WarmupDomain          string
+        WarmupStartDate       time.Time
+        WarmupInitialDailyCap int
+        WarmupGrowthPercent   int // Daily cap growth per step, 100 = doubling
+        WarmupStepDays        int
+        WarmupMaxDailyCap     int
+
+        // Dashboard
+        DashboardCacheTTL time.Duration
+        FunnelCacheTTL    time.Duration
+
+        // Intelligence cache tiers
+        IntelligenceCacheHotTTL   time.Duration
+        IntelligenceCacheWarmTTL  time.Duration
+        IntelligenceCacheDailyTTL time.Duration
+        IntelligenceCacheLeadTTL  time.Duration
+
+        // Automated intelligence cycle
+        IntelligenceCycleInterval time.Duration
+        IntelligenceWorkers       int
+        IntelligenceBatchSize     int
+
+        // Property valuation
+        ValuationCacheTTL               time.Duration
+        ValuationChangeThresholdPercent float64
+
+        // Outbound webhooks
+        OutboundWebhookURLs   []string
+        OutboundWebhookSecret string
+
+        // Cross-origin API access. No origins means same-origin only; credentialed
+        // (cookie) requests are only allowed on the listed path prefixes.
+        CORSAllowedOrigins  []string
+        CORSCredentialPaths []string
+
+        // Security headers. "{nonce}" in the CSP is replaced by a per-request
+        // nonce (e.g. "script-src 'self' 'nonce-{nonce}'"), which templates get
+        // as CSPNonce. Paths under EmbedPaths may be framed by EmbedFrameAncestors.
+        ContentSecurityPolicy   string
+        FrameOptions            string
+        StrictTransportSecurity string
+        ReferrerPolicy          string
+        PermissionsPolicy       string
+        EmbedPaths              []string
+        EmbedFrameAncestors     string
+
+        // IP access control. X-Forwarded-For is only honored from TrustedProxies.
+        // When AdminIPAllowlist (or an allow rule) exists, AdminIPPaths only
+        // accept those networks. An IP with IPDenyEventThreshold unresolved
+        // high/critical security events within IPDenyEventWindow is denied
+        // everywhere for IPDenyDuration.
+        TrustedProxies       []string
+        AdminIPAllowlist     []string
+        AdminIPPaths         []string
+        IPDenyEventThreshold int
+        IPDenyEventWindow    time.Duration
+        IPDenyDuration       time.Duration
+
+        // Request limits. Upload paths (imports, inbound email) get the larger
+        // body limit and timeout; WebSocket paths are not limited.
+        MaxRequestBodyBytes       int64
+        UploadMaxRequestBodyBytes int64
+        RequestTimeout            time.Duration
+        UploadRequestTimeout      time.Duration
+        UploadPaths               []string
+
+        // Compliance alert notifications, keyed by severity
+        ComplianceAlertChannels map[string][]string // "email", "sms", "webhook"
+        ComplianceAlertEmails   map[string][]string
+        ComplianceAlertPhones   map[string][]string
+        ComplianceAlertCooldown time.Duration // Minimum gap between notifications for one alert type
+
+        // Scheduled compliance checks
+        ComplianceCheckInterval time.Duration
+
+        // Behavioral sessions with no events for the idle timeout are closed
+        BehavioralSessionIdleTimeout   time.Duration
+        BehavioralSessionCloseInterval time.Duration
+
+        // Saved search alerts are held during quiet hours (local hours in SavedSearchTimezone)
+        SavedSearchAlertInterval   time.Duration
+        SavedSearchQuietHoursStart int
+        SavedSearchQuietHoursEnd   int
+        SavedSearchTimezone        string
+
+        // Daily digest emails go to opted-in admins from DailyDigestHour (local to DailyDigestTimezone)
+        DailyDigestHour          int
+        DailyDigestTimezone      string
+        DailyDigestCheckInterval time.Duration
+
+        // Command center insights scoring at least CommandCenterTaskMinScore (0-100) become tasks
+        CommandCenterTaskInterval time.Duration
+        CommandCenterTaskMinScore int
+
+        // Leads are re-flagged against the imported do-not-contact list on this interval
+        DNCRecheckInterval time.Duration
+
+        // High-intent context triggers the FUB API rejected are re-pushed on this interval
+        FUBPushRetryInterval time.Duration
+
+        // FUB API writes routed through the batch service are limited to this
+        // many calls per minute, with bursts of up to FUBRateLimitBurst
+        FUBRateLimitPerMinute int
+        FUBRateLimitBurst     int
+
+        // Applicants who start an application and don't submit it are reminded
+        // after ApplicationRecoveryDelay, then every ApplicationRecoveryInterval,
+        // up to ApplicationRecoveryMaxReminders times
+        ApplicationRecoveryDelay         time.Duration
+        ApplicationRecoveryInterval      time.Duration
+        ApplicationRecoveryMaxReminders  int
+        ApplicationRecoveryCheckInterval time.Duration
+
         // Business (from database)
         BusinessName    string
         BusinessPhone   string
@@ -101,6 +262,10 @@ func LoadConfig() *Config {
                 Port:        getEnv("PORT", "8080"),
                 Environment: getEnv("ENVIRONMENT", "production"),
                 LogLevel:    getEnv("LOG_LEVEL", "info"),
+                LogFormat:   getEnv("LOG_FORMAT", "text"),
+
+                // Templates reload without a restart in development, cached elsewhere
+                TemplateReload: getDbSettingBool(dbSettings, "TEMPLATE_RELOAD", getEnv("ENVIRONMENT", "production") == "development"),
 
                 // Database connection settings
                 DatabaseMaxConns: getDbSettingInt(dbSettings, "DATABASE_MAX_CONNS", 25),
@@ -111,17 +276,37 @@ func LoadConfig() *Config {
                 EncryptionKey:      dbSettings["ENCRYPTION_KEY"],
                 SessionTimeout:     time.Duration(getDbSettingInt(dbSettings, "SESSION_TIMEOUT_MINUTES", 60)) * time.Minute,
                 MFARequired:        getDbSettingBool(dbSettings, "MFA_REQUIRED", false),
+                MFAReverifyWindow:  time.Duration(getDbSettingInt(dbSettings, "MFA_REVERIFY_MINUTES", 5)) * time.Minute,
                 RateLimitPerMinute: getDbSettingInt(dbSettings, "RATE_LIMIT_REQUESTS_PER_MINUTE", 100),
+                RateLimitByAdmin:   getDbSettingBool(dbSettings, "RATE_LIMIT_BY_ADMIN", true),
+
+                // Admin JWT lifetimes
+                AdminAccessTokenTTL:  time.Duration(getDbSettingInt(dbSettings, "ADMIN_ACCESS_TOKEN_TTL_MINUTES", 15)) * time.Minute,
+                AdminRefreshTokenTTL: time.Duration(getDbSettingInt(dbSettings, "ADMIN_REFRESH_TOKEN_TTL_DAYS", 30)) * 24 * time.Hour,
 
                 // External services (ALL from database)
                 FUBAPIKey:     dbSettings["FUB_API_KEY"],
                 FUBAPIURL:     getDbSetting(dbSettings, "FUB_API_URL", "https://api.followupboss.com"),
                 ScraperAPIKey: dbSettings["SCRAPER_API_KEY"], // Generic scraper
 
+                // Scraper resilience
+                ScraperMaxAttempts:      getDbSettingInt(dbSettings, "SCRAPER_MAX_ATTEMPTS", 3),
+                ScraperBreakerThreshold: getDbSettingInt(dbSettings, "SCRAPER_BREAKER_THRESHOLD", 5),
+                ScraperBreakerCooldown:  time.Duration(getDbSettingInt(dbSettings, "SCRAPER_BREAKER_COOLDOWN_SECONDS", 60)) * time.Second,
+
                 // TREC compliance
                 TRECComplianceEnabled: getDbSettingBool(dbSettings, "TREC_COMPLIANCE_ENABLED", true),
                 AuditLogRetentionDays: getDbSettingInt(dbSettings, "AUDIT_LOG_RETENTION_DAYS", 365),
 
+                // Leads
+                LeadAgentScoping: getDbSettingBool(dbSettings, "LEAD_AGENT_SCOPING", false),
+
+                // Data retention
+                DataRetentionEventDays:          getDbSettingInt(dbSettings, "DATA_RETENTION_EVENT_DAYS", 730),
+                DataRetentionLeadInactiveMonths: getDbSettingInt(dbSettings, "DATA_RETENTION_LEAD_INACTIVE_MONTHS", 24),
+                DataRetentionDryRun:             getDbSettingBool(dbSettings, "DATA_RETENTION_DRY_RUN", true),
+                DataRetentionInterval:           time.Duration(getDbSettingInt(dbSettings, "DATA_RETENTION_INTERVAL_HOURS", 24)) * time.Hour,
+
                 // Redis
                 RedisURL:      getDbSetting(dbSettings, "REDIS_URL", "localhost:6379"),
                 RedisPassword: dbSettings["REDIS_PASSWORD"],
@@ -133,6 +318,13 @@ func LoadConfig() *Config {
                 SMTPUsername: dbSettings["SMTP_USERNAME"],
                 SMTPPassword: dbSettings["SMTP_PASSWORD"],
 
+                // Pooled SMTP
+                SMTPEnabled:                  getDbSettingBool(dbSettings, "SMTP_ENABLED", false),
+                SMTPUseTLS:                   getDbSettingBool(dbSettings, "SMTP_USE_TLS", false),
+                SMTPMaxConnections:           getDbSettingInt(dbSettings, "SMTP_MAX_CONNECTIONS", 4),
+                SMTPKeepAlive:                time.Duration(getDbSettingInt(dbSettings, "SMTP_KEEPALIVE_SECONDS", 30)) * time.Second,
+                SMTPMaxMessagesPerConnection: getDbSettingInt(dbSettings, "SMTP_MAX_MESSAGES_PER_CONNECTION", 100),
+
                 // SendGrid
                 SendGridAPIKey:   dbSettings["SENDGRID_API_KEY"],
                 EmailFromAddress: getDbSetting(dbSettings, "EMAIL_FROM_ADDRESS", "noreply@landlordsoftexas.com"),
@@ -143,6 +335,130 @@ func LoadConfig() *Config {
                 TwilioAuthToken:   dbSettings["TWILIO_AUTH_TOKEN"],
                 TwilioPhoneNumber: dbSettings["TWILIO_PHONE_NUMBER"],
 
+                // Public URL and tracking
+                PublicBaseURL:  getDbSetting(dbSettings, "PUBLIC_BASE_URL", getEnv("BASE_URL", "http://localhost:8080")),
+                TrackingSecret: dbSettings["TRACKING_SECRET"],
+
+                // Campaign dispatch rate shaping
+                CampaignSenderRatePerMinute: getDbSettingInt(dbSettings, "CAMPAIGN_SENDER_RATE_PER_MINUTE", 60),
+                CampaignDomainRatePerMinute: getDbSettingInt(dbSettings, "CAMPAIGN_DOMAIN_RATE_PER_MINUTE", 20),
+                CampaignDomainRateLimits:    getDbSettingIntMap(dbSettings, "CAMPAIGN_DOMAIN_RATE_LIMITS"),
+
+                // Campaign send retries
+                CampaignMaxSendAttempts:     getDbSettingInt(dbSettings, "CAMPAIGN_MAX_SEND_ATTEMPTS", 3),
+                CampaignRetryBackoffMinutes: getDbSettingInt(dbSettings, "CAMPAIGN_RETRY_BACKOFF_MINUTES", 5),
+
+                // Sending domain warmup
+                WarmupDomain:          dbSettings["WARMUP_DOMAIN"],
+                WarmupStartDate:       getDbSettingDate(dbSettings, "WARMUP_START_DATE"),
+                WarmupInitialDailyCap: getDbSettingInt(dbSettings, "WARMUP_INITIAL_DAILY_CAP", 50),
+                WarmupGrowthPercent:   getDbSettingInt(dbSettings, "WARMUP_GROWTH_PERCENT", 100),
+                WarmupStepDays:        getDbSettingInt(dbSettings, "WARMUP_STEP_DAYS", 1),
+                WarmupMaxDailyCap:     getDbSettingInt(dbSettings, "WARMUP_MAX_DAILY_CAP", 500),
+
+                // Dashboard
+                DashboardCacheTTL: time.Duration(getDbSettingInt(dbSettings, "DASHBOARD_CACHE_TTL_SECONDS", 60)) * time.Second,
+                FunnelCacheTTL:    time.Duration(getDbSettingInt(dbSettings, "FUNNEL_CACHE_TTL_SECONDS", 300)) * time.Second,
+
+                // Intelligence cache tiers
+                IntelligenceCacheHotTTL:   time.Duration(getDbSettingInt(dbSettings, "INTELLIGENCE_CACHE_HOT_TTL_MINUTES", 5)) * time.Minute,
+                IntelligenceCacheWarmTTL:  time.Duration(getDbSettingInt(dbSettings, "INTELLIGENCE_CACHE_WARM_TTL_MINUTES", 60)) * time.Minute,
+                IntelligenceCacheDailyTTL: time.Duration(getDbSettingInt(dbSettings, "INTELLIGENCE_CACHE_DAILY_TTL_HOURS", 24)) * time.Hour,
+                IntelligenceCacheLeadTTL:  time.Duration(getDbSettingInt(dbSettings, "INTELLIGENCE_CACHE_LEAD_TTL_MINUTES", 15)) * time.Minute,
+
+                // Automated intelligence cycle
+                IntelligenceCycleInterval: time.Duration(getDbSettingInt(dbSettings, "INTELLIGENCE_CYCLE_INTERVAL_MINUTES", 5)) * time.Minute,
+                IntelligenceWorkers:       getDbSettingInt(dbSettings, "INTELLIGENCE_WORKERS", 4),
+                IntelligenceBatchSize:     getDbSettingInt(dbSettings, "INTELLIGENCE_BATCH_SIZE", 100),
+
+                // Property valuation
+                ValuationCacheTTL:               time.Duration(getDbSettingInt(dbSettings, "VALUATION_CACHE_TTL_HOURS", 24)) * time.Hour,
+                ValuationChangeThresholdPercent: float64(getDbSettingInt(dbSettings, "VALUATION_CHANGE_THRESHOLD_PERCENT", 5)),
+
+                // Outbound webhooks
+                OutboundWebhookURLs:   getDbSettingList(dbSettings, "OUTBOUND_WEBHOOK_URLS"),
+                OutboundWebhookSecret: getDbSetting(dbSettings, "OUTBOUND_WEBHOOK_SECRET", dbSettings["JWT_SECRET"]),
+
+                // CORS
+                CORSAllowedOrigins:  splitSettingList(getDbSetting(dbSettings, "CORS_ALLOWED_ORIGINS", os.Getenv("CORS_ALLOWED_ORIGINS"))),
+                CORSCredentialPaths: splitSettingList(getDbSetting(dbSettings, "CORS_CREDENTIAL_PATHS", "/api/v1,/admin")),
+
+                // Security headers (an empty CSP uses the middleware default)
+                ContentSecurityPolicy:   getDbSetting(dbSettings, "CSP_POLICY", ""),
+                FrameOptions:            getDbSetting(dbSettings, "SECURITY_FRAME_OPTIONS", "DENY"),
+                StrictTransportSecurity: getDbSetting(dbSettings, "SECURITY_HSTS", "max-age=31536000; includeSubDomains; preload"),
+                ReferrerPolicy:          getDbSetting(dbSettings, "SECURITY_REFERRER_POLICY", "strict-origin-when-cross-origin"),
+                PermissionsPolicy:       getDbSetting(dbSettings, "SECURITY_PERMISSIONS_POLICY", "geolocation=(), microphone=(), camera=()"),
+                EmbedPaths:              getDbSettingList(dbSettings, "SECURITY_EMBED_PATHS"),
+                EmbedFrameAncestors:     getDbSetting(dbSettings, "SECURITY_EMBED_FRAME_ANCESTORS", "'self'"),
+
+                // IP access control (proxies default to loopback and private networks)
+                TrustedProxies: splitSettingList(getDbSetting(dbSettings, "TRUSTED_PROXIES",
+                        "127.0.0.0/8,::1/128,10.0.0.0/8,172.16.0.0/12,192.168.0.0/16")),
+                AdminIPAllowlist:     getDbSettingList(dbSettings, "ADMIN_IP_ALLOWLIST"),
+                AdminIPPaths:         splitSettingList(getDbSetting(dbSettings, "ADMIN_IP_PATHS", "/api/v1/admin/")),
+                IPDenyEventThreshold: getDbSettingInt(dbSettings, "IP_DENY_EVENT_THRESHOLD", 5),
+                IPDenyEventWindow:    time.Duration(getDbSettingInt(dbSettings, "IP_DENY_EVENT_WINDOW_MINUTES", 60)) * time.Minute,
+                IPDenyDuration:       time.Duration(getDbSettingInt(dbSettings, "IP_DENY_HOURS", 24)) * time.Hour,
+
+                // Request limits
+                MaxRequestBodyBytes:       int64(getDbSettingInt(dbSettings, "REQUEST_MAX_BODY_MB", 10)) << 20,
+                UploadMaxRequestBodyBytes: int64(getDbSettingInt(dbSettings, "REQUEST_UPLOAD_MAX_BODY_MB", 100)) << 20,
+                RequestTimeout:            time.Duration(getDbSettingInt(dbSettings, "REQUEST_TIMEOUT_SECONDS", 30)) * time.Second,
+                UploadRequestTimeout:      time.Duration(getDbSettingInt(dbSettings, "REQUEST_UPLOAD_TIMEOUT_SECONDS", 300)) * time.Second,
+                UploadPaths: splitSettingList(getDbSetting(dbSettings, "REQUEST_UPLOAD_PATHS",
+                        "/api/migration/,/api/leads/import,/api/v1/compliance/dnc/import,/api/webhooks/inbound-email")),
+
+                // Compliance alert notifications
+                ComplianceAlertChannels: getDbSettingSeverityLists(dbSettings, "COMPLIANCE_ALERT_CHANNELS", map[string]string{
+                        "critical": "email,sms,webhook",
+                        "high":     "email",
+                }),
+                ComplianceAlertEmails: getDbSettingSeverityLists(dbSettings, "COMPLIANCE_ALERT_EMAILS", map[string]string{
+                        "critical": getDbSetting(dbSettings, "BUSINESS_EMAIL", "info@propertyhub.com"),
+                        "high":     getDbSetting(dbSettings, "BUSINESS_EMAIL", "info@propertyhub.com"),
+                }),
+                ComplianceAlertPhones:   getDbSettingSeverityLists(dbSettings, "COMPLIANCE_ALERT_PHONES", nil),
+                ComplianceAlertCooldown: time.Duration(getDbSettingInt(dbSettings, "COMPLIANCE_ALERT_COOLDOWN_MINUTES", 30)) * time.Minute,
+
+                // Scheduled compliance checks
+                ComplianceCheckInterval: time.Duration(getDbSettingInt(dbSettings, "COMPLIANCE_CHECK_INTERVAL_MINUTES", 60)) * time.Minute,
+
+                // Idle behavioral session closing
+                BehavioralSessionIdleTimeout:   time.Duration(getDbSettingInt(dbSettings, "BEHAVIORAL_SESSION_IDLE_MINUTES", 30)) * time.Minute,
+                BehavioralSessionCloseInterval: time.Duration(getDbSettingInt(dbSettings, "BEHAVIORAL_SESSION_CLOSE_INTERVAL_MINUTES", 5)) * time.Minute,
+
+                // Saved search alerts
+                SavedSearchAlertInterval:   time.Duration(getDbSettingInt(dbSettings, "SAVED_SEARCH_ALERT_INTERVAL_MINUTES", 5)) * time.Minute,
+                SavedSearchQuietHoursStart: getDbSettingInt(dbSettings, "SAVED_SEARCH_QUIET_HOURS_START", 21),
+                SavedSearchQuietHoursEnd:   getDbSettingInt(dbSettings, "SAVED_SEARCH_QUIET_HOURS_END", 8),
+                SavedSearchTimezone:        getDbSetting(dbSettings, "SAVED_SEARCH_TIMEZONE", "America/Chicago"),
+
+                // Daily digest
+                DailyDigestHour:          getDbSettingInt(dbSettings, "DAILY_DIGEST_HOUR", 7),
+                DailyDigestTimezone:      getDbSetting(dbSettings, "DAILY_DIGEST_TIMEZONE", "America/Chicago"),
+                DailyDigestCheckInterval: time.Duration(getDbSettingInt(dbSettings, "DAILY_DIGEST_CHECK_INTERVAL_MINUTES", 15)) * time.Minute,
+
+                // Command center tasks
+                CommandCenterTaskInterval: time.Duration(getDbSettingInt(dbSettings, "COMMAND_CENTER_TASK_INTERVAL_MINUTES", 5)) * time.Minute,
+                CommandCenterTaskMinScore: getDbSettingInt(dbSettings, "COMMAND_CENTER_TASK_MIN_SCORE", 80),
+
+                // Do-not-contact list recheck
+                DNCRecheckInterval: time.Duration(getDbSettingInt(dbSettings, "DNC_RECHECK_INTERVAL_MINUTES", 60)) * time.Minute,
+
+                // FUB push retries
+                FUBPushRetryInterval: time.Duration(getDbSettingInt(dbSettings, "FUB_PUSH_RETRY_INTERVAL_MINUTES", 5)) * time.Minute,
+
+                // FUB API rate limit
+                FUBRateLimitPerMinute: getDbSettingInt(dbSettings, "FUB_RATE_LIMIT_PER_MINUTE", 120),
+                FUBRateLimitBurst:     getDbSettingInt(dbSettings, "FUB_RATE_LIMIT_BURST", 10),
+
+                // Unfinished application reminders
+                ApplicationRecoveryDelay:         time.Duration(getDbSettingInt(dbSettings, "APPLICATION_RECOVERY_DELAY_HOURS", 24)) * time.Hour,
+                ApplicationRecoveryInterval:      time.Duration(getDbSettingInt(dbSettings, "APPLICATION_RECOVERY_INTERVAL_HOURS", 48)) * time.Hour,
+                ApplicationRecoveryMaxReminders:  getDbSettingInt(dbSettings, "APPLICATION_RECOVERY_MAX_REMINDERS", 3),
+                ApplicationRecoveryCheckInterval: time.Duration(getDbSettingInt(dbSettings, "APPLICATION_RECOVERY_CHECK_INTERVAL_MINUTES", 15)) * time.Minute,
+
                 // Business info
                 BusinessName:    getDbSetting(dbSettings, "BUSINESS_NAME", "PropertyHub"),
                 BusinessPhone:   getDbSetting(dbSettings, "BUSINESS_PHONE", "(713) 555-0123"),
@@ -237,6 +553,63 @@ func getDbSettingBool(settings map[string]string, key string, defaultValue bool)
 	return defaultValue
 }
 
+// getDbSettingDate parses a YYYY-MM-DD setting in local time, returning the
+// zero time if it is unset or malformed
+func getDbSettingDate(settings map[string]string, key string) time.Time {
+	if value, exists := settings[key]; exists && value != "" {
+		if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+			return date
+		}
+	}
+	return time.Time{}
+}
+
+// getDbSettingList splits a comma-separated setting, dropping empty entries
+func getDbSettingList(settings map[string]string, key string) []string {
+	return splitSettingList(settings[key])
+}
+
+// getDbSettingIntMap parses a comma-separated list of key=number pairs
+// (e.g. "gmail.com=30,yahoo.com=10"), skipping malformed entries
+func getDbSettingIntMap(settings map[string]string, key string) map[string]int {
+	values := make(map[string]int)
+	for _, entry := range splitSettingList(settings[key]) {
+		name, value, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+		if intVal, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			values[strings.TrimSpace(name)] = intVal
+		}
+	}
+	return values
+}
+
+// getDbSettingSeverityLists reads <prefix>_CRITICAL, <prefix>_HIGH, <prefix>_MEDIUM and
+// <prefix>_LOW as comma-separated lists. A setting that exists but is empty disables
+// that severity; a missing one falls back to the default.
+func getDbSettingSeverityLists(settings map[string]string, prefix string, defaults map[string]string) map[string][]string {
+	lists := make(map[string][]string)
+	for _, severity := range []string{"critical", "high", "medium", "low"} {
+		value, exists := settings[prefix+"_"+strings.ToUpper(severity)]
+		if !exists {
+			value = defaults[severity]
+		}
+		lists[severity] = splitSettingList(value)
+	}
+	return lists
+}
+
+func splitSettingList(setting string) []string {
+	values := []string{}
+	for _, value := range strings.Split(setting, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
 }
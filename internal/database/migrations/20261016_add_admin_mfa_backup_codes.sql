@@ -0,0 +1,7 @@
+-- Migration: Store hashed MFA backup codes on admin users
+-- Date: 2026-10-16
+-- Description: Adds mfa_backup_codes (space-separated SHA-256 hashes of unused
+-- single-use recovery codes) and the time they were last issued.
+
+ALTER TABLE admin_users ADD COLUMN IF NOT EXISTS mfa_backup_codes TEXT;
+ALTER TABLE admin_users ADD COLUMN IF NOT EXISTS mfa_backup_codes_generated_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,8 @@
+-- Migration: Store TOTP secrets on admin users
+-- Date: 2026-10-16
+-- Description: Adds mfa_totp_secret (the admin's TOTP secret, encrypted with the
+-- field encryption key) and the time the admin confirmed their enrollment.
+-- MFA re-verification for sensitive operations checks codes against it.
+
+ALTER TABLE admin_users ADD COLUMN IF NOT EXISTS mfa_totp_secret TEXT;
+ALTER TABLE admin_users ADD COLUMN IF NOT EXISTS mfa_totp_enabled_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,7 @@
+-- Migration: Add applicant screening columns
+-- Date: 2026-10-16
+-- Description: Marks when and by whom each applicant was screened; an
+-- application can only be approved once all its applicants are screened
+
+ALTER TABLE application_applicants ADD COLUMN IF NOT EXISTS screened_at TIMESTAMP;
+ALTER TABLE application_applicants ADD COLUMN IF NOT EXISTS screened_by TEXT;
@@ -0,0 +1,11 @@
+-- Migration: Record FUB lead matches on approvals
+-- Date: 2026-10-16
+-- Description: Application notifications are matched to FUB leads by a scored
+-- name/email matcher. fub_lead_id is set when the match was linked automatically;
+-- fub_match_confidence records the best candidate's score so low-confidence
+-- matches left for review can be triaged.
+
+ALTER TABLE approvals ADD COLUMN IF NOT EXISTS fub_lead_id VARCHAR(255);
+ALTER TABLE approvals ADD COLUMN IF NOT EXISTS fub_match_confidence DOUBLE PRECISION DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_approvals_fub_lead_id ON approvals(fub_lead_id);
@@ -0,0 +1,8 @@
+-- Migration: Add client event IDs to behavioral events
+-- Date: 2026-10-16
+-- Description: Lets clients send an idempotency ID with each behavioral event so
+-- retried requests return the stored event instead of double-counting it
+
+ALTER TABLE behavioral_events ADD COLUMN IF NOT EXISTS client_event_id VARCHAR(100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_behavioral_events_client_event_id ON behavioral_events(client_event_id);
@@ -0,0 +1,11 @@
+-- Migration: Track last event time on behavioral sessions
+-- Date: 2026-10-16
+-- Description: Records when each session last saw an event so a background job
+-- can close sessions that have gone idle instead of leaving them open forever
+
+ALTER TABLE behavioral_sessions ADD COLUMN IF NOT EXISTS last_event_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_behavioral_sessions_last_event_at ON behavioral_sessions(last_event_at);
+
+-- Existing open sessions count as last active when they started
+UPDATE behavioral_sessions SET last_event_at = start_time WHERE last_event_at IS NULL AND end_time IS NULL;
@@ -0,0 +1,6 @@
+-- Migration: Add composite index for active behavioral session lookups
+-- Date: 2026-10-16
+-- Description: Supports the active-session count (end_time IS NULL AND start_time >= cutoff)
+-- without a full scan of behavioral_sessions
+
+CREATE INDEX IF NOT EXISTS idx_behavioral_sessions_active ON behavioral_sessions(end_time, start_time);
@@ -0,0 +1,9 @@
+-- Migration: Add failure tracking to campaign executions
+-- Date: 2026-10-16
+-- Description: Records why a campaign send failed and when it was dead-lettered,
+-- so failed executions can be listed by reason and requeued
+
+ALTER TABLE campaign_executions ADD COLUMN IF NOT EXISTS failure_reason VARCHAR(50);
+ALTER TABLE campaign_executions ADD COLUMN IF NOT EXISTS failed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_campaign_executions_failure_reason ON campaign_executions(failure_reason);
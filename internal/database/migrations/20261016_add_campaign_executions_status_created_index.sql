@@ -0,0 +1,6 @@
+-- Migration: Add status/created_at index to campaign executions
+-- Date: 2026-10-16
+-- Description: Backs the paginated campaign list, which filters by status and
+-- sorts by creation date
+
+CREATE INDEX IF NOT EXISTS idx_campaign_executions_status_created ON campaign_executions(status, created_at);
@@ -0,0 +1,7 @@
+-- Migration: Store custom variable values on campaign templates
+-- Date: 2026-10-16
+-- Description: Adds custom_variables (a JSON object of placeholder name -> text)
+-- so {{placeholders}} that are not lead fields render with a stored value
+-- instead of reaching recipients as literal {{key}}.
+
+ALTER TABLE campaign_templates ADD COLUMN IF NOT EXISTS custom_variables JSON;
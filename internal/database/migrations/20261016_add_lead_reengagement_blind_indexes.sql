@@ -0,0 +1,18 @@
+-- Migration: Add blind-index lookup columns to re-engagement leads
+-- Date: 2026-10-16
+-- Description: Adds keyed HMAC hashes of email and phone so encrypted leads can be
+-- deduplicated and looked up without decrypting every row. blind_index_key_id records
+-- which index key produced the hashes.
+--
+-- Backfill: hashes require the application's ENCRYPTION_KEY, so they cannot be computed
+-- in SQL. On startup the server recomputes email_hash, phone_hash and search_index for
+-- every row whose blind_index_key_id is missing or differs from the current key
+-- (models.BackfillLeadBlindIndexes). Changing ENCRYPTION_KEY therefore triggers a full
+-- re-hash; rows that no longer decrypt are left without hashes.
+
+ALTER TABLE lead_reengagements ADD COLUMN IF NOT EXISTS email_hash VARCHAR(16);
+ALTER TABLE lead_reengagements ADD COLUMN IF NOT EXISTS phone_hash VARCHAR(16);
+ALTER TABLE lead_reengagements ADD COLUMN IF NOT EXISTS blind_index_key_id VARCHAR(16);
+
+CREATE INDEX IF NOT EXISTS idx_lead_reengagements_email_hash ON lead_reengagements(email_hash);
+CREATE INDEX IF NOT EXISTS idx_lead_reengagements_phone_hash ON lead_reengagements(phone_hash);
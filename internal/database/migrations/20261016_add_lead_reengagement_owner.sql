@@ -0,0 +1,17 @@
+-- Migration: Add agent ownership to re-engagement leads
+-- Date: 2026-10-16
+-- Description: Adds owner_id so re-engagement queries can be scoped to the owning agent.
+-- Existing leads are assigned to the earliest team admin (main_admin/super_admin).
+
+ALTER TABLE lead_reengagements ADD COLUMN IF NOT EXISTS owner_id VARCHAR(36);
+
+CREATE INDEX IF NOT EXISTS idx_lead_reengagements_owner_id ON lead_reengagements(owner_id);
+
+UPDATE lead_reengagements
+SET owner_id = (
+    SELECT id FROM admin_users
+    WHERE role IN ('main_admin', 'super_admin')
+    ORDER BY created_at ASC
+    LIMIT 1
+)
+WHERE owner_id IS NULL OR owner_id = '';
@@ -0,0 +1,7 @@
+-- Migration: Add blind search index to re-engagement leads
+-- Date: 2026-10-16
+-- Description: Adds search_index, a space-separated list of keyed trigram hashes of
+-- name/email/phone so leads can be searched without decrypting PII. Existing rows are
+-- indexed by the server on startup.
+
+ALTER TABLE lead_reengagements ADD COLUMN IF NOT EXISTS search_index TEXT;
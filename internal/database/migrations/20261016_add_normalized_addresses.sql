@@ -0,0 +1,15 @@
+-- Migration: Add normalized address columns to pre-listing items and approvals
+-- Date: 2026-10-16
+-- Description: Stores addressutil.Normalize output alongside the raw address so
+-- vendor and lease emails match properties on an exact, indexed value instead of
+-- LIKE '%address%'.
+--
+-- Backfill: normalization runs in Go, so existing rows are filled on startup by
+-- models.BackfillNormalizedAddresses. New and saved rows are kept in sync by the
+-- models' BeforeSave hooks.
+
+ALTER TABLE pre_listing_items ADD COLUMN IF NOT EXISTS normalized_address TEXT;
+ALTER TABLE approvals ADD COLUMN IF NOT EXISTS normalized_property_address TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_pre_listing_items_normalized_address ON pre_listing_items(normalized_address);
+CREATE INDEX IF NOT EXISTS idx_approvals_normalized_property_address ON approvals(normalized_property_address);
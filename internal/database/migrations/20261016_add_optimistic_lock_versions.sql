@@ -0,0 +1,9 @@
+-- Migration: Optimistic concurrency for lead, pre-listing, and property updates
+-- Date: 2026-10-16
+-- Description: Updates must send the version they read and are rejected with
+-- 409 Conflict when the stored version differs. Each successful update bumps
+-- the version. Existing rows start at version 1.
+
+ALTER TABLE leads ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE pre_listing_items ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,8 @@
+-- Migration: Add sender authentication policy to trusted email senders
+-- Date: 2026-10-16
+-- Description: Adds auth_policy (none, relaxed, strict), which controls the SPF/DKIM/DMARC
+-- results required before an email from a trusted sender is processed automatically.
+
+ALTER TABLE trusted_email_senders ADD COLUMN IF NOT EXISTS auth_policy VARCHAR(20) DEFAULT 'relaxed';
+
+UPDATE trusted_email_senders SET auth_policy = 'relaxed' WHERE auth_policy IS NULL OR auth_policy = '';
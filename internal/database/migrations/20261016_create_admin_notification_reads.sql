@@ -0,0 +1,14 @@
+-- Migration: Create admin notification reads
+-- Date: 2026-10-16
+-- Description: Per-admin read state for admin notifications. A notification
+-- without a row for an admin is unread for that admin.
+
+CREATE TABLE IF NOT EXISTS admin_notification_reads (
+    id SERIAL PRIMARY KEY,
+    notification_id INTEGER NOT NULL,
+    admin_user_id TEXT NOT NULL,
+    read_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_admin_notification_reads_admin_notification ON admin_notification_reads(notification_id, admin_user_id);
+CREATE INDEX IF NOT EXISTS idx_admin_notification_reads_admin_user_id ON admin_notification_reads(admin_user_id);
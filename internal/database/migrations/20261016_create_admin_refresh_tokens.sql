@@ -0,0 +1,24 @@
+-- Migration: Server-side admin refresh tokens
+-- Date: 2026-10-16
+-- Description: Stores hashed, rotating refresh tokens for admin JWT sessions.
+-- Tokens from one login share a family_id so reuse of a rotated token can revoke the whole chain.
+
+CREATE TABLE IF NOT EXISTS admin_refresh_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    admin_user_id VARCHAR(36) NOT NULL,
+    family_id VARCHAR(36) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    rotated_at TIMESTAMP WITH TIME ZONE,
+    replaced_by_id BIGINT,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_reason VARCHAR(50),
+    ip_address VARCHAR(64),
+    user_agent TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_admin_refresh_tokens_token_hash ON admin_refresh_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_admin_refresh_tokens_admin_user_id ON admin_refresh_tokens(admin_user_id);
+CREATE INDEX IF NOT EXISTS idx_admin_refresh_tokens_family_id ON admin_refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_admin_refresh_tokens_expires_at ON admin_refresh_tokens(expires_at);
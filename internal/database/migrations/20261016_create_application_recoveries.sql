@@ -0,0 +1,27 @@
+-- Migration: Create application recoveries
+-- Date: 2026-10-16
+-- Description: Applicants who start an application on the site are tracked as
+-- "started" until they submit. Those who stall get reminders; one row per
+-- applicant records the reminders sent and whether they went on to finish.
+
+ALTER TABLE application_applicants ADD COLUMN IF NOT EXISTS completion_status VARCHAR(20) DEFAULT 'submitted';
+CREATE INDEX IF NOT EXISTS idx_application_applicants_completion_status ON application_applicants(completion_status);
+
+CREATE TABLE IF NOT EXISTS application_recoveries (
+    id SERIAL PRIMARY KEY,
+    applicant_id INTEGER NOT NULL,
+    applicant_email TEXT,
+    property_address TEXT,
+    reminders_sent INTEGER DEFAULT 0,
+    last_channels VARCHAR(20),
+    last_reminder_at TIMESTAMP,
+    outcome VARCHAR(20) DEFAULT 'pending',
+    outcome_reason TEXT,
+    outcome_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_application_recoveries_applicant_id ON application_recoveries(applicant_id);
+CREATE INDEX IF NOT EXISTS idx_application_recoveries_applicant_email ON application_recoveries(applicant_email);
+CREATE INDEX IF NOT EXISTS idx_application_recoveries_outcome ON application_recoveries(outcome);
@@ -0,0 +1,31 @@
+-- Migration: Admin audit trail
+-- Date: 2026-10-16
+-- Description: Every POST, PUT, PATCH and DELETE made by a signed-in admin is
+-- recorded with the actor, route, affected entity, redacted request body and,
+-- for key entities, the row as it was before and after the write.
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id SERIAL PRIMARY KEY,
+    actor_id VARCHAR(64),
+    actor_name TEXT,
+    actor_role VARCHAR(32),
+    method VARCHAR(10),
+    route TEXT,
+    path TEXT,
+    entity_type VARCHAR(64),
+    entity_id VARCHAR(64),
+    status_code INTEGER,
+    request JSON,
+    before JSON,
+    after JSON,
+    ip_address TEXT,
+    user_agent TEXT,
+    duration_ms BIGINT DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_method ON audit_logs(method);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity_type ON audit_logs(entity_type);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity_id ON audit_logs(entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(created_at);
@@ -0,0 +1,13 @@
+-- Migration: Persist the campaign emergency stop
+-- Date: 2026-10-16
+-- Description: Single-row table (id = 1) recording whether the campaign
+-- emergency stop is active, why and by whom, restored when the server starts.
+
+CREATE TABLE IF NOT EXISTS campaign_emergency_stops (
+    id BIGSERIAL PRIMARY KEY,
+    active BOOLEAN NOT NULL DEFAULT FALSE,
+    reason TEXT,
+    activated_by VARCHAR(255),
+    activated_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1,19 @@
+-- Migration: Create campaign suppressions table
+-- Date: 2026-10-16
+-- Description: Campaign-specific suppression lists, either lead IDs or a saved
+-- lead query, excluded from campaigns of the given name on prepare and activate
+
+CREATE TABLE IF NOT EXISTS campaign_suppressions (
+    id SERIAL PRIMARY KEY,
+    campaign_name VARCHAR(200) NOT NULL,
+    reason VARCHAR(255),
+    lead_ids TEXT,
+    query TEXT,
+    active BOOLEAN DEFAULT TRUE,
+    created_by TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_suppressions_campaign_name ON campaign_suppressions(campaign_name);
+CREATE INDEX IF NOT EXISTS idx_campaign_suppressions_active ON campaign_suppressions(active);
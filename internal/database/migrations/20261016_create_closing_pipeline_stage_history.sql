@@ -0,0 +1,17 @@
+-- Migration: Create closing pipeline stage history table
+-- Date: 2026-10-16
+-- Description: Records every closing pipeline stage transition with the
+-- previous and new stage, who made it and when
+
+CREATE TABLE IF NOT EXISTS closing_pipeline_stage_history (
+    id SERIAL PRIMARY KEY,
+    closing_pipeline_id INTEGER NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor VARCHAR(100),
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_closing_pipeline_stage_history_closing_pipeline_id ON closing_pipeline_stage_history(closing_pipeline_id);
+CREATE INDEX IF NOT EXISTS idx_closing_pipeline_stage_history_created_at ON closing_pipeline_stage_history(created_at);
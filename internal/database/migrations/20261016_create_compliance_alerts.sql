@@ -0,0 +1,28 @@
+-- Migration: Persist compliance alerts
+-- Date: 2026-10-16
+-- Description: Stores compliance alerts so acknowledge/resolve state survives restarts
+-- and is shared across instances.
+
+CREATE TABLE IF NOT EXISTS compliance_alerts (
+    id VARCHAR(64) PRIMARY KEY,
+    type VARCHAR(100) NOT NULL,
+    severity VARCHAR(20),
+    title VARCHAR(255),
+    message TEXT,
+    action TEXT,
+    acknowledged BOOLEAN DEFAULT FALSE,
+    acknowledged_by VARCHAR(255),
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    resolved BOOLEAN DEFAULT FALSE,
+    resolved_by VARCHAR(255),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_compliance_alerts_type ON compliance_alerts(type);
+CREATE INDEX IF NOT EXISTS idx_compliance_alerts_severity ON compliance_alerts(severity);
+CREATE INDEX IF NOT EXISTS idx_compliance_alerts_resolved ON compliance_alerts(resolved);
+
+-- Only one unresolved alert per type, even when several instances trigger at once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_compliance_alerts_unresolved_type ON compliance_alerts(type) WHERE resolved = FALSE;
@@ -0,0 +1,20 @@
+-- Migration: Scheduled compliance check history
+-- Date: 2026-10-16
+-- Description: One row per scheduled compliance check, used to chart the overall
+-- score over time. status holds the full ComplianceStatus.
+
+CREATE TABLE IF NOT EXISTS compliance_check_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    is_compliant BOOLEAN DEFAULT FALSE,
+    overall_score DOUBLE PRECISION DEFAULT 0,
+    reputation_score DOUBLE PRECISION DEFAULT 0,
+    legal_score DOUBLE PRECISION DEFAULT 0,
+    volume_utilization DOUBLE PRECISION DEFAULT 0,
+    risk_factors INTEGER DEFAULT 0,
+    active_alerts INTEGER DEFAULT 0,
+    status JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_compliance_check_snapshots_checked_at ON compliance_check_snapshots(checked_at);
@@ -0,0 +1,21 @@
+-- Migration: Configurable compliance weights and thresholds
+-- Date: 2026-10-16
+-- Description: Single-row table (id = 1) holding overall score weights and
+-- reputation thresholds. Weights must sum to 1.0; the application validates this.
+
+CREATE TABLE IF NOT EXISTS compliance_configs (
+    id BIGSERIAL PRIMARY KEY,
+    volume_weight DOUBLE PRECISION NOT NULL DEFAULT 0.25,
+    reputation_weight DOUBLE PRECISION NOT NULL DEFAULT 0.35,
+    legal_weight DOUBLE PRECISION NOT NULL DEFAULT 0.25,
+    emergency_weight DOUBLE PRECISION NOT NULL DEFAULT 0.15,
+    min_overall_score DOUBLE PRECISION NOT NULL DEFAULT 70.0,
+    max_bounce_rate DOUBLE PRECISION NOT NULL DEFAULT 5.0,
+    max_complaint_rate DOUBLE PRECISION NOT NULL DEFAULT 0.1,
+    max_unsubscribe_rate DOUBLE PRECISION NOT NULL DEFAULT 5.0,
+    min_open_rate DOUBLE PRECISION NOT NULL DEFAULT 15.0,
+    min_click_rate DOUBLE PRECISION NOT NULL DEFAULT 1.0,
+    updated_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
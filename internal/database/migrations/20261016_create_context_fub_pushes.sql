@@ -0,0 +1,30 @@
+-- Migration: Create context FUB pushes
+-- Date: 2026-10-16
+-- Description: One row per HIGH/CRITICAL context trigger pushed to Follow Up
+-- Boss as a contact with a follow-up task. Pushes the FUB API rejected stay
+-- 'pending_retry' with their payload until a retry succeeds or they are
+-- marked 'failed'.
+
+CREATE TABLE IF NOT EXISTS context_fub_pushes (
+    id SERIAL PRIMARY KEY,
+    trigger_id TEXT NOT NULL,
+    session_id TEXT,
+    email TEXT,
+    priority VARCHAR(20),
+    workflow_type TEXT,
+    payload TEXT,
+    status VARCHAR(20),
+    contact_id TEXT,
+    automation_id TEXT,
+    retry_count INTEGER DEFAULT 0,
+    next_retry TIMESTAMP,
+    last_error TEXT,
+    pushed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_context_fub_pushes_trigger_id ON context_fub_pushes(trigger_id);
+CREATE INDEX IF NOT EXISTS idx_context_fub_pushes_session_id ON context_fub_pushes(session_id);
+CREATE INDEX IF NOT EXISTS idx_context_fub_pushes_status ON context_fub_pushes(status);
+CREATE INDEX IF NOT EXISTS idx_context_fub_pushes_next_retry ON context_fub_pushes(next_retry);
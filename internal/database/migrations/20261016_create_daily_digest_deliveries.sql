@@ -0,0 +1,17 @@
+-- Migration: Create daily digest deliveries
+-- Date: 2026-10-16
+-- Description: One row per admin and day the daily digest email was sent (or
+-- failed), so each admin gets at most one digest a day. Admins opt in with a
+-- 'daily_digest' row in notification_preferences with email set.
+
+CREATE TABLE IF NOT EXISTS daily_digest_deliveries (
+    id SERIAL PRIMARY KEY,
+    admin_user_id TEXT NOT NULL,
+    digest_date VARCHAR(10) NOT NULL,
+    status VARCHAR(20),
+    error TEXT,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_daily_digest_deliveries_admin_date ON daily_digest_deliveries(admin_user_id, digest_date);
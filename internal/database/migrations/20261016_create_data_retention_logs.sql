@@ -0,0 +1,21 @@
+-- Migration: Data retention audit trail
+-- Date: 2026-10-16
+-- Description: The data retention job purges behavioral events older than the
+-- configured retention period and anonymizes leads with no recent activity.
+-- data_retention_logs records every purge and anonymization it performs.
+
+CREATE TABLE IF NOT EXISTS data_retention_logs (
+    id SERIAL PRIMARY KEY,
+    run_id VARCHAR(36),
+    action VARCHAR(30),
+    lead_id INTEGER,
+    record_count BIGINT DEFAULT 0,
+    cutoff TIMESTAMP WITH TIME ZONE,
+    details TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_retention_logs_run_id ON data_retention_logs(run_id);
+CREATE INDEX IF NOT EXISTS idx_data_retention_logs_action ON data_retention_logs(action);
+CREATE INDEX IF NOT EXISTS idx_data_retention_logs_lead_id ON data_retention_logs(lead_id);
+CREATE INDEX IF NOT EXISTS idx_data_retention_logs_created_at ON data_retention_logs(created_at);
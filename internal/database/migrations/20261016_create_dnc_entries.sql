@@ -0,0 +1,30 @@
+-- Migration: Create do-not-contact list tables
+-- Date: 2026-10-16
+-- Description: Stores the imported do-not-contact list as blind index hashes and
+-- logs every email or SMS refused because the recipient is on it
+
+CREATE TABLE IF NOT EXISTS dnc_entries (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(10) NOT NULL,
+    value_hash VARCHAR(16) NOT NULL,
+    index_key_id VARCHAR(16),
+    source VARCHAR(100),
+    imported_by TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dnc_entries_value ON dnc_entries(kind, value_hash);
+
+CREATE TABLE IF NOT EXISTS dnc_suppression_logs (
+    id SERIAL PRIMARY KEY,
+    channel VARCHAR(10),
+    source VARCHAR(50),
+    recipient_hash VARCHAR(16),
+    lead_reengagement_id INTEGER,
+    reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_dnc_suppression_logs_channel ON dnc_suppression_logs(channel);
+CREATE INDEX IF NOT EXISTS idx_dnc_suppression_logs_lead_reengagement_id ON dnc_suppression_logs(lead_reengagement_id);
+CREATE INDEX IF NOT EXISTS idx_dnc_suppression_logs_created_at ON dnc_suppression_logs(created_at);
@@ -0,0 +1,55 @@
+-- Migration: Drip campaigns
+-- Date: 2026-10-16
+-- Description: A drip campaign is an ordered sequence of campaign templates,
+-- each sent DaysDelay days after the previous one. drip_enrollments tracks
+-- which step each enrolled lead is on. Each due step is queued as a
+-- campaign_executions row, now linked back to its enrollment and step.
+
+CREATE TABLE IF NOT EXISTS drip_campaigns (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    status VARCHAR(32) DEFAULT 'active',
+    daily_limit INTEGER DEFAULT 0,
+    created_by VARCHAR(255)
+);
+
+CREATE INDEX IF NOT EXISTS idx_drip_campaigns_deleted_at ON drip_campaigns(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_drip_campaigns_name ON drip_campaigns(name);
+CREATE INDEX IF NOT EXISTS idx_drip_campaigns_status ON drip_campaigns(status);
+CREATE INDEX IF NOT EXISTS idx_drip_campaigns_created_by ON drip_campaigns(created_by);
+
+CREATE TABLE IF NOT EXISTS drip_campaign_steps (
+    id SERIAL PRIMARY KEY,
+    drip_campaign_id INTEGER NOT NULL REFERENCES drip_campaigns(id),
+    position INTEGER NOT NULL,
+    campaign_template_id INTEGER NOT NULL,
+    days_delay INTEGER DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_drip_campaign_steps_drip_campaign_id ON drip_campaign_steps(drip_campaign_id);
+
+CREATE TABLE IF NOT EXISTS drip_enrollments (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    drip_campaign_id INTEGER NOT NULL,
+    lead_reengagement_id INTEGER NOT NULL,
+    status VARCHAR(32) DEFAULT 'active',
+    current_step INTEGER DEFAULT 0,
+    next_step_at TIMESTAMP WITH TIME ZONE,
+    pending_execution_id INTEGER,
+    stopped_reason TEXT,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_drip_enrollment_lead ON drip_enrollments(drip_campaign_id, lead_reengagement_id);
+CREATE INDEX IF NOT EXISTS idx_drip_enrollments_status ON drip_enrollments(status);
+CREATE INDEX IF NOT EXISTS idx_drip_enrollments_next_step_at ON drip_enrollments(next_step_at);
+
+ALTER TABLE campaign_executions ADD COLUMN IF NOT EXISTS drip_enrollment_id INTEGER;
+ALTER TABLE campaign_executions ADD COLUMN IF NOT EXISTS drip_step INTEGER DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_campaign_executions_drip_enrollment_id ON campaign_executions(drip_enrollment_id);
@@ -0,0 +1,25 @@
+-- Migration: Track encryption key rotation jobs
+-- Date: 2026-10-16
+-- Description: Records progress of re-encrypting a table's PII to the current key.
+
+CREATE TABLE IF NOT EXISTS encryption_rotation_jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    target_table VARCHAR(255) NOT NULL,
+    target_key_id VARCHAR(255),
+    status VARCHAR(20) DEFAULT 'running',
+    total BIGINT DEFAULT 0,
+    processed BIGINT DEFAULT 0,
+    reencrypted BIGINT DEFAULT 0,
+    failed BIGINT DEFAULT 0,
+    skipped BIGINT DEFAULT 0,
+    last_id BIGINT DEFAULT 0,
+    last_error TEXT,
+    requested_by VARCHAR(255),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_encryption_rotation_jobs_target_table ON encryption_rotation_jobs(target_table);
+CREATE INDEX IF NOT EXISTS idx_encryption_rotation_jobs_status ON encryption_rotation_jobs(status);
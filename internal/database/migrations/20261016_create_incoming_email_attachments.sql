@@ -0,0 +1,22 @@
+-- Migration: Incoming email attachments
+-- Date: 2026-10-16
+-- Description: Files received with trusted sender emails (signed leases,
+-- application forms) are validated and stored alongside the incoming email.
+-- File content is kept in the row; listings select metadata only.
+
+CREATE TABLE IF NOT EXISTS incoming_email_attachments (
+    id SERIAL PRIMARY KEY,
+    incoming_email_id INTEGER NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size_bytes INTEGER,
+    sha256 VARCHAR(64),
+    data BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_incoming_email_attachments_incoming_email_id ON incoming_email_attachments(incoming_email_id);
+CREATE INDEX IF NOT EXISTS idx_incoming_email_attachments_sha256 ON incoming_email_attachments(sha256);
+CREATE INDEX IF NOT EXISTS idx_incoming_email_attachments_deleted_at ON incoming_email_attachments(deleted_at);
@@ -0,0 +1,23 @@
+-- Migration: IP allowlist and denylist
+-- Date: 2026-10-16
+-- Description: Allow rules restrict admin routes to the listed networks (e.g.
+-- office IPs); deny rules block a network from every route. Deny rules are
+-- added by hand or from IPs with repeated high severity security events.
+
+CREATE TABLE IF NOT EXISTS ip_access_rules (
+    id SERIAL PRIMARY KEY,
+    list VARCHAR(10) NOT NULL,
+    cidr VARCHAR(64) NOT NULL,
+    reason TEXT,
+    source VARCHAR(20),
+    security_event_id INTEGER,
+    created_by_id VARCHAR(64),
+    created_by TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ip_access_rules_list ON ip_access_rules(list);
+CREATE INDEX IF NOT EXISTS idx_ip_access_rules_cidr ON ip_access_rules(cidr);
+CREATE INDEX IF NOT EXISTS idx_ip_access_rules_security_event_id ON ip_access_rules(security_event_id);
+CREATE INDEX IF NOT EXISTS idx_ip_access_rules_expires_at ON ip_access_rules(expires_at);
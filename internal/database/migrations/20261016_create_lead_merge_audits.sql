@@ -0,0 +1,24 @@
+-- Migration: Lead merge audit trail
+-- Date: 2026-10-16
+-- Description: Duplicate re-engagement leads (same blind-indexed email or phone)
+-- can be merged into a primary lead. Campaign executions, behavioral trigger
+-- events, and application records are moved to the primary and the duplicates
+-- are soft-deleted. Opt-outs on a duplicate (revoked consent, unsubscribe, DNC)
+-- are copied to the primary. lead_merge_audits records each merge, what was
+-- moved and which opt-outs were carried over.
+
+CREATE TABLE IF NOT EXISTS lead_merge_audits (
+    id SERIAL PRIMARY KEY,
+    primary_lead_id INTEGER NOT NULL,
+    merged_lead_ids JSON,
+    merged_fub_ids JSON,
+    reassigned JSON,
+    carried_flags JSON,
+    actor_id VARCHAR(255),
+    actor_name VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_merge_audits_primary_lead_id ON lead_merge_audits(primary_lead_id);
+CREATE INDEX IF NOT EXISTS idx_lead_merge_audits_actor_id ON lead_merge_audits(actor_id);
+CREATE INDEX IF NOT EXISTS idx_lead_merge_audits_created_at ON lead_merge_audits(created_at);
@@ -0,0 +1,38 @@
+-- Migration: Create lead routing
+-- Date: 2026-10-16
+-- Description: Single-row config choosing how new leads are assigned
+-- (round_robin, weighted or territory), per-agent routing profiles, and the
+-- history of every assignment made by the router or by hand.
+
+CREATE TABLE IF NOT EXISTS lead_routing_configs (
+    id SERIAL PRIMARY KEY,
+    strategy VARCHAR(20),
+    auto_route BOOLEAN,
+    updated_by TEXT,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS agent_routing_profiles (
+    agent_id TEXT PRIMARY KEY,
+    accepting BOOLEAN DEFAULT TRUE,
+    weight INTEGER DEFAULT 1,
+    territories TEXT,
+    max_daily_leads INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS lead_assignments (
+    id SERIAL PRIMARY KEY,
+    lead_id INTEGER NOT NULL,
+    agent_id TEXT NOT NULL,
+    previous_agent_id TEXT,
+    strategy VARCHAR(20),
+    reason TEXT,
+    assigned_by TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_assignments_lead_id ON lead_assignments(lead_id);
+CREATE INDEX IF NOT EXISTS idx_lead_assignments_agent_id ON lead_assignments(agent_id);
+CREATE INDEX IF NOT EXISTS idx_lead_assignments_created_at ON lead_assignments(created_at);
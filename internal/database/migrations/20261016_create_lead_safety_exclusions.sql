@@ -0,0 +1,17 @@
+-- Migration: Create lead safety exclusions
+-- Date: 2026-10-16
+-- Description: One row per lead left out of a campaign or FUB push because it
+-- failed the lead safety filter (Do Not Contact, litigation flag, pause, FUB
+-- Do Not Contact status or a recent complaint), with the reason.
+
+CREATE TABLE IF NOT EXISTS lead_safety_exclusions (
+    id SERIAL PRIMARY KEY,
+    lead_id TEXT,
+    channel TEXT,
+    context TEXT,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_safety_exclusions_lead_id ON lead_safety_exclusions(lead_id);
+CREATE INDEX IF NOT EXISTS idx_lead_safety_exclusions_created_at ON lead_safety_exclusions(created_at);
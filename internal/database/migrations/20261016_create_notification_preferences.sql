@@ -0,0 +1,39 @@
+-- Migration: Create admin notification preferences and queued notifications
+-- Date: 2026-10-16
+-- Description: Per-admin, per-event-type delivery channels (in-app, email, SMS)
+-- and quiet hours. Event type '*' is an admin's default. Non-urgent
+-- notifications arriving in quiet hours are queued until they end.
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id SERIAL PRIMARY KEY,
+    admin_user_id TEXT NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    in_app BOOLEAN DEFAULT true,
+    email BOOLEAN DEFAULT false,
+    sms BOOLEAN DEFAULT false,
+    sms_phone VARCHAR(50),
+    quiet_hours_start INTEGER DEFAULT 0,
+    quiet_hours_end INTEGER DEFAULT 0,
+    timezone VARCHAR(64),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_preferences_admin_type ON notification_preferences(admin_user_id, event_type);
+
+CREATE TABLE IF NOT EXISTS queued_notifications (
+    id SERIAL PRIMARY KEY,
+    notification_id INTEGER NOT NULL,
+    admin_user_id TEXT NOT NULL,
+    channel VARCHAR(20),
+    deliver_after TIMESTAMP,
+    status VARCHAR(20) DEFAULT 'pending',
+    error TEXT,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_queued_notifications_notification_id ON queued_notifications(notification_id);
+CREATE INDEX IF NOT EXISTS idx_queued_notifications_admin_user_id ON queued_notifications(admin_user_id);
+CREATE INDEX IF NOT EXISTS idx_queued_notifications_deliver_after ON queued_notifications(deliver_after);
+CREATE INDEX IF NOT EXISTS idx_queued_notifications_status ON queued_notifications(status);
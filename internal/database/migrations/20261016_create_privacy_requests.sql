@@ -0,0 +1,26 @@
+-- Migration: Data subject privacy requests
+-- Date: 2026-10-16
+-- Description: privacy_requests logs each verified request to export or erase
+-- a person's data (GDPR/CCPA) and its fulfillment. The subject is stored as an
+-- email blind index only.
+
+CREATE TABLE IF NOT EXISTS privacy_requests (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(10),
+    subject_hash VARCHAR(16),
+    index_key_id VARCHAR(16),
+    verification_method VARCHAR(255),
+    requested_by_id VARCHAR(255),
+    requested_by VARCHAR(255),
+    status VARCHAR(20),
+    record_counts JSON,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    fulfilled_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_kind ON privacy_requests(kind);
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_subject_hash ON privacy_requests(subject_hash);
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_requested_by_id ON privacy_requests(requested_by_id);
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_status ON privacy_requests(status);
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_created_at ON privacy_requests(created_at);
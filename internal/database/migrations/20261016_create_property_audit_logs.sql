@@ -0,0 +1,27 @@
+-- Migration: Property soft-delete audit trail
+-- Date: 2026-10-16
+-- Description: Deleting a property now soft-deletes it (deleted_at) so it can be
+-- restored from the trash. property_audit_logs records who deleted or restored
+-- each property and when.
+-- Backfill: properties previously "deleted" by setting status = 'deleted' are
+-- moved to the trash.
+
+CREATE TABLE IF NOT EXISTS property_audit_logs (
+    id SERIAL PRIMARY KEY,
+    property_id INTEGER NOT NULL,
+    mls_id VARCHAR(255),
+    action VARCHAR(32) NOT NULL,
+    actor_id VARCHAR(255),
+    actor_name VARCHAR(255),
+    ip_address VARCHAR(64),
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_property_audit_logs_property_id ON property_audit_logs(property_id);
+CREATE INDEX IF NOT EXISTS idx_property_audit_logs_mls_id ON property_audit_logs(mls_id);
+CREATE INDEX IF NOT EXISTS idx_property_audit_logs_action ON property_audit_logs(action);
+CREATE INDEX IF NOT EXISTS idx_property_audit_logs_actor_id ON property_audit_logs(actor_id);
+CREATE INDEX IF NOT EXISTS idx_property_audit_logs_created_at ON property_audit_logs(created_at);
+
+UPDATE properties SET deleted_at = updated_at WHERE status = 'deleted' AND deleted_at IS NULL;
@@ -0,0 +1,26 @@
+-- Migration: Received webhook log
+-- Date: 2026-10-16
+-- Description: Stores inbound context intelligence webhook payloads so they can
+-- be replayed through POST /api/v1/context-fub/replay after a processing fix.
+-- Replays are stored as separate rows with is_replay set and replay_of_id
+-- pointing at the original, so analytics can separate them from live events.
+
+CREATE TABLE IF NOT EXISTS received_webhooks (
+    id SERIAL PRIMARY KEY,
+    source VARCHAR(50) NOT NULL,
+    event_type VARCHAR(100),
+    payload TEXT,
+    received_at TIMESTAMP WITH TIME ZONE,
+    triggered BOOLEAN DEFAULT FALSE,
+    trigger_type VARCHAR(255),
+    is_replay BOOLEAN DEFAULT FALSE,
+    replay_of_id INTEGER,
+    replayed_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_received_webhooks_source ON received_webhooks(source);
+CREATE INDEX IF NOT EXISTS idx_received_webhooks_event_type ON received_webhooks(event_type);
+CREATE INDEX IF NOT EXISTS idx_received_webhooks_received_at ON received_webhooks(received_at);
+CREATE INDEX IF NOT EXISTS idx_received_webhooks_is_replay ON received_webhooks(is_replay);
+CREATE INDEX IF NOT EXISTS idx_received_webhooks_replay_of_id ON received_webhooks(replay_of_id);
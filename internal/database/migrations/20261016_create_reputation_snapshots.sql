@@ -0,0 +1,22 @@
+-- Migration: Daily sender reputation snapshots
+-- Date: 2026-10-16
+-- Description: One row per day written by the compliance check, used for the
+-- reputation trend in compliance reports. No backfill; the trend fills in as days pass.
+
+CREATE TABLE IF NOT EXISTS reputation_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    snapshot_date DATE NOT NULL,
+    overall_score DOUBLE PRECISION DEFAULT 0,
+    deliverability_score DOUBLE PRECISION DEFAULT 0,
+    engagement_score DOUBLE PRECISION DEFAULT 0,
+    complaint_score DOUBLE PRECISION DEFAULT 0,
+    bounce_rate DOUBLE PRECISION DEFAULT 0,
+    spam_complaint_rate DOUBLE PRECISION DEFAULT 0,
+    unsubscribe_rate DOUBLE PRECISION DEFAULT 0,
+    open_rate DOUBLE PRECISION DEFAULT 0,
+    click_rate DOUBLE PRECISION DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reputation_snapshots_snapshot_date ON reputation_snapshots(snapshot_date);
@@ -0,0 +1,41 @@
+-- Migration: Create saved searches and saved search alerts
+-- Date: 2026-10-16
+-- Description: Saved listing searches whose owners are alerted by email or SMS
+-- when a new or re-priced listing matches. One alert per search and listing.
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(200),
+    owner_email VARCHAR(255),
+    owner_phone VARCHAR(50),
+    lead_id INTEGER,
+    channel VARCHAR(20) DEFAULT 'email',
+    criteria TEXT,
+    active BOOLEAN DEFAULT true,
+    created_by TEXT,
+    last_alerted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_owner_email ON saved_searches(owner_email);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_lead_id ON saved_searches(lead_id);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_active ON saved_searches(active);
+
+CREATE TABLE IF NOT EXISTS saved_search_alerts (
+    id SERIAL PRIMARY KEY,
+    saved_search_id INTEGER NOT NULL,
+    property_id INTEGER NOT NULL,
+    trigger VARCHAR(20),
+    channel VARCHAR(20),
+    status VARCHAR(20) DEFAULT 'pending',
+    error TEXT,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_search_alerts_listing ON saved_search_alerts(saved_search_id, property_id);
+CREATE INDEX IF NOT EXISTS idx_saved_search_alerts_status ON saved_search_alerts(status);
+
+ALTER TABLE price_change_events ADD COLUMN IF NOT EXISTS search_alerts_checked_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_price_change_events_search_alerts_checked_at ON price_change_events(search_alerts_checked_at);
@@ -0,0 +1,48 @@
+-- Migration: Configurable context automation scoring thresholds
+-- Date: 2026-10-16
+-- Description: Single-row table (id = 1) holding the score multipliers and
+-- thresholds context-driven FUB automation uses to pick workflows, recommended
+-- actions, and priorities. The application validates ranges before saving.
+
+CREATE TABLE IF NOT EXISTS scoring_configs (
+    id BIGSERIAL PRIMARY KEY,
+    rental_engagement_multiplier DOUBLE PRECISION NOT NULL DEFAULT 1.1,
+    sales_engagement_multiplier DOUBLE PRECISION NOT NULL DEFAULT 0.9,
+    rental_urgency_multiplier DOUBLE PRECISION NOT NULL DEFAULT 1.2,
+    sales_urgency_multiplier DOUBLE PRECISION NOT NULL DEFAULT 0.8,
+    pre_approved_financial_boost DOUBLE PRECISION NOT NULL DEFAULT 0.3,
+    income_verified_financial_boost DOUBLE PRECISION NOT NULL DEFAULT 0.2,
+    high_intent_urgency DOUBLE PRECISION NOT NULL DEFAULT 0.8,
+    high_intent_engagement DOUBLE PRECISION NOT NULL DEFAULT 0.7,
+    qualified_nurture_conversion DOUBLE PRECISION NOT NULL DEFAULT 0.6,
+    qualified_nurture_engagement DOUBLE PRECISION NOT NULL DEFAULT 0.5,
+    engagement_building_engagement DOUBLE PRECISION NOT NULL DEFAULT 0.4,
+    immediate_call_urgency DOUBLE PRECISION NOT NULL DEFAULT 0.8,
+    personalized_follow_up_conversion DOUBLE PRECISION NOT NULL DEFAULT 0.6,
+    educational_content_engagement DOUBLE PRECISION NOT NULL DEFAULT 0.4,
+    rental_immediate_urgency DOUBLE PRECISION NOT NULL DEFAULT 0.8,
+    rental_immediate_financial DOUBLE PRECISION NOT NULL DEFAULT 0.6,
+    rental_urgent_urgency DOUBLE PRECISION NOT NULL DEFAULT 0.6,
+    rental_urgent_engagement DOUBLE PRECISION NOT NULL DEFAULT 0.5,
+    rental_qualified_financial DOUBLE PRECISION NOT NULL DEFAULT 0.5,
+    rental_showing_call_urgency DOUBLE PRECISION NOT NULL DEFAULT 0.8,
+    rental_application_financial DOUBLE PRECISION NOT NULL DEFAULT 0.6,
+    rental_application_urgency DOUBLE PRECISION NOT NULL DEFAULT 0.5,
+    rental_tour_engagement DOUBLE PRECISION NOT NULL DEFAULT 0.5,
+    sales_pre_approved_financial DOUBLE PRECISION NOT NULL DEFAULT 0.8,
+    sales_pre_approved_urgency DOUBLE PRECISION NOT NULL DEFAULT 0.6,
+    sales_engaged_engagement DOUBLE PRECISION NOT NULL DEFAULT 0.7,
+    sales_engaged_financial DOUBLE PRECISION NOT NULL DEFAULT 0.5,
+    sales_urgent_urgency DOUBLE PRECISION NOT NULL DEFAULT 0.6,
+    sales_consultation_financial DOUBLE PRECISION NOT NULL DEFAULT 0.8,
+    sales_market_analysis_urgency DOUBLE PRECISION NOT NULL DEFAULT 0.7,
+    sales_market_analysis_engagement DOUBLE PRECISION NOT NULL DEFAULT 0.5,
+    sales_financing_engagement DOUBLE PRECISION NOT NULL DEFAULT 0.6,
+    mixed_high_intent_average DOUBLE PRECISION NOT NULL DEFAULT 0.7,
+    mixed_qualified_average DOUBLE PRECISION NOT NULL DEFAULT 0.5,
+    high_priority_average DOUBLE PRECISION NOT NULL DEFAULT 0.7,
+    medium_priority_average DOUBLE PRECISION NOT NULL DEFAULT 0.4,
+    updated_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
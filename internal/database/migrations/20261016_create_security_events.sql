@@ -0,0 +1,32 @@
+-- Migration: Security event threat feed
+-- Date: 2026-10-16
+-- Description: SQL injection, XSS and brute force detections from the security
+-- middlewares are recorded in security_events and resolved from the security
+-- dashboard with a note. Existing tables created by create_admin_tables.sql
+-- keep their event_type, event_data and created_at columns and gain the rest.
+
+CREATE TABLE IF NOT EXISTS security_events (
+    id SERIAL PRIMARY KEY,
+    user_id UUID,
+    event_type VARCHAR(100) NOT NULL,
+    event_data JSONB,
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE security_events ADD COLUMN IF NOT EXISTS severity VARCHAR(20);
+ALTER TABLE security_events ADD COLUMN IF NOT EXISTS user_agent TEXT;
+ALTER TABLE security_events ADD COLUMN IF NOT EXISTS endpoint TEXT;
+ALTER TABLE security_events ADD COLUMN IF NOT EXISTS method VARCHAR(10);
+ALTER TABLE security_events ADD COLUMN IF NOT EXISTS status_code INTEGER;
+ALTER TABLE security_events ADD COLUMN IF NOT EXISTS payload VARCHAR(500);
+ALTER TABLE security_events ADD COLUMN IF NOT EXISTS resolved BOOLEAN DEFAULT FALSE;
+ALTER TABLE security_events ADD COLUMN IF NOT EXISTS resolved_by UUID;
+ALTER TABLE security_events ADD COLUMN IF NOT EXISTS resolved_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE security_events ADD COLUMN IF NOT EXISTS resolution_note TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_security_events_type ON security_events(event_type);
+CREATE INDEX IF NOT EXISTS idx_security_events_created ON security_events(created_at);
+CREATE INDEX IF NOT EXISTS idx_security_events_severity ON security_events(severity);
+CREATE INDEX IF NOT EXISTS idx_security_events_ip_address ON security_events(ip_address);
+CREATE INDEX IF NOT EXISTS idx_security_events_resolved ON security_events(resolved);
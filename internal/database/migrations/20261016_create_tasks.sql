@@ -0,0 +1,32 @@
+-- Migration: Create command center tasks
+-- Date: 2026-10-16
+-- Description: Assignable command center tasks, created by hand or converted
+-- from high-confidence insights. source_key identifies the insight a task was
+-- generated from so it is not duplicated while the task is open.
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id SERIAL PRIMARY KEY,
+    title TEXT NOT NULL,
+    description TEXT,
+    type VARCHAR(50),
+    source VARCHAR(20) DEFAULT 'manual',
+    source_key VARCHAR(100),
+    lead_id BIGINT,
+    assigned_to TEXT,
+    priority INTEGER,
+    confidence DOUBLE PRECISION,
+    status VARCHAR(20) DEFAULT 'open',
+    due_at TIMESTAMP,
+    snoozed_until TIMESTAMP,
+    completed_at TIMESTAMP,
+    completed_by TEXT,
+    created_by TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_source_key ON tasks(source_key);
+CREATE INDEX IF NOT EXISTS idx_tasks_lead_id ON tasks(lead_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_assigned_to ON tasks(assigned_to);
+CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
+CREATE INDEX IF NOT EXISTS idx_tasks_due_at ON tasks(due_at);
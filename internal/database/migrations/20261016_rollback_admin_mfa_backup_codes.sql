@@ -0,0 +1,3 @@
+-- Rollback script for admin_users MFA backup codes
+ALTER TABLE admin_users DROP COLUMN IF EXISTS mfa_backup_codes;
+ALTER TABLE admin_users DROP COLUMN IF EXISTS mfa_backup_codes_generated_at;
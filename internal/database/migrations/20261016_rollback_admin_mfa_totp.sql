@@ -0,0 +1,3 @@
+-- Rollback script for admin_users TOTP secrets
+ALTER TABLE admin_users DROP COLUMN IF EXISTS mfa_totp_secret;
+ALTER TABLE admin_users DROP COLUMN IF EXISTS mfa_totp_enabled_at;
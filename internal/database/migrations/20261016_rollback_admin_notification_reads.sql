@@ -0,0 +1,2 @@
+-- Rollback script for admin_notification_reads
+DROP TABLE IF EXISTS admin_notification_reads;
@@ -0,0 +1,2 @@
+-- Rollback script for admin_refresh_tokens
+DROP TABLE IF EXISTS admin_refresh_tokens;
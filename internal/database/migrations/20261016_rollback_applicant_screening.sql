@@ -0,0 +1,3 @@
+-- Rollback script for applicant screening columns
+ALTER TABLE application_applicants DROP COLUMN IF EXISTS screened_by;
+ALTER TABLE application_applicants DROP COLUMN IF EXISTS screened_at;
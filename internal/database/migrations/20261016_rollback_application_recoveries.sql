@@ -0,0 +1,4 @@
+-- Rollback script for application recoveries
+DROP TABLE IF EXISTS application_recoveries;
+DROP INDEX IF EXISTS idx_application_applicants_completion_status;
+ALTER TABLE application_applicants DROP COLUMN IF EXISTS completion_status;
@@ -0,0 +1,4 @@
+-- Rollback script for approvals FUB match columns
+DROP INDEX IF EXISTS idx_approvals_fub_lead_id;
+ALTER TABLE approvals DROP COLUMN IF EXISTS fub_lead_id;
+ALTER TABLE approvals DROP COLUMN IF EXISTS fub_match_confidence;
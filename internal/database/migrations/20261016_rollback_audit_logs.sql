@@ -0,0 +1,2 @@
+-- Rollback script for audit_logs
+DROP TABLE IF EXISTS audit_logs;
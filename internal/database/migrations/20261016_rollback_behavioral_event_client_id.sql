@@ -0,0 +1,3 @@
+-- Rollback script for behavioral_events.client_event_id
+DROP INDEX IF EXISTS idx_behavioral_events_client_event_id;
+ALTER TABLE behavioral_events DROP COLUMN IF EXISTS client_event_id;
@@ -0,0 +1,3 @@
+-- Rollback script for behavioral_sessions.last_event_at
+DROP INDEX IF EXISTS idx_behavioral_sessions_last_event_at;
+ALTER TABLE behavioral_sessions DROP COLUMN IF EXISTS last_event_at;
@@ -0,0 +1,2 @@
+-- Rollback script for idx_behavioral_sessions_active
+DROP INDEX IF EXISTS idx_behavioral_sessions_active;
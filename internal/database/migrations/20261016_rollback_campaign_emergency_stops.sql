@@ -0,0 +1,2 @@
+-- Rollback script for campaign_emergency_stops
+DROP TABLE IF EXISTS campaign_emergency_stops;
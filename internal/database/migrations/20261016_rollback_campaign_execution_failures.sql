@@ -0,0 +1,4 @@
+-- Rollback script for campaign_executions failure tracking
+DROP INDEX IF EXISTS idx_campaign_executions_failure_reason;
+ALTER TABLE campaign_executions DROP COLUMN IF EXISTS failed_at;
+ALTER TABLE campaign_executions DROP COLUMN IF EXISTS failure_reason;
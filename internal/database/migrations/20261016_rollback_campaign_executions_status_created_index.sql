@@ -0,0 +1,2 @@
+-- Rollback script for idx_campaign_executions_status_created
+DROP INDEX IF EXISTS idx_campaign_executions_status_created;
@@ -0,0 +1,2 @@
+-- Rollback script for campaign_suppressions
+DROP TABLE IF EXISTS campaign_suppressions;
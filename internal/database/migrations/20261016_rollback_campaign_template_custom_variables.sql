@@ -0,0 +1,2 @@
+-- Rollback script for campaign_templates custom variables
+ALTER TABLE campaign_templates DROP COLUMN IF EXISTS custom_variables;
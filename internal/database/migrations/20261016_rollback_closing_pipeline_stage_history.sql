@@ -0,0 +1,2 @@
+-- Rollback script for closing_pipeline_stage_history
+DROP TABLE IF EXISTS closing_pipeline_stage_history;
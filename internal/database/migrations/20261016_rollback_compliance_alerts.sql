@@ -0,0 +1,2 @@
+-- Rollback script for compliance_alerts
+DROP TABLE IF EXISTS compliance_alerts;
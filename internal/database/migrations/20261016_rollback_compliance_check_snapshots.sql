@@ -0,0 +1,2 @@
+-- Rollback script for compliance_check_snapshots
+DROP TABLE IF EXISTS compliance_check_snapshots;
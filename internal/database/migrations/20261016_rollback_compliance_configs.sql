@@ -0,0 +1,2 @@
+-- Rollback script for compliance_configs
+DROP TABLE IF EXISTS compliance_configs;
@@ -0,0 +1,2 @@
+-- Rollback script for context_fub_pushes
+DROP TABLE IF EXISTS context_fub_pushes;
@@ -0,0 +1,2 @@
+-- Rollback script for daily_digest_deliveries
+DROP TABLE IF EXISTS daily_digest_deliveries;
@@ -0,0 +1,2 @@
+-- Rollback script for data_retention_logs
+DROP TABLE IF EXISTS data_retention_logs;
@@ -0,0 +1,3 @@
+-- Rollback script for dnc_entries and dnc_suppression_logs
+DROP TABLE IF EXISTS dnc_suppression_logs;
+DROP TABLE IF EXISTS dnc_entries;
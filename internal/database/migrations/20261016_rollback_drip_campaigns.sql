@@ -0,0 +1,7 @@
+-- Rollback script for drip_campaigns
+DROP INDEX IF EXISTS idx_campaign_executions_drip_enrollment_id;
+ALTER TABLE campaign_executions DROP COLUMN IF EXISTS drip_step;
+ALTER TABLE campaign_executions DROP COLUMN IF EXISTS drip_enrollment_id;
+DROP TABLE IF EXISTS drip_enrollments;
+DROP TABLE IF EXISTS drip_campaign_steps;
+DROP TABLE IF EXISTS drip_campaigns;
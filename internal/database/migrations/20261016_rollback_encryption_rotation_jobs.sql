@@ -0,0 +1,2 @@
+-- Rollback script for encryption_rotation_jobs
+DROP TABLE IF EXISTS encryption_rotation_jobs;
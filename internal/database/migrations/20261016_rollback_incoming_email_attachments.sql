@@ -0,0 +1,2 @@
+-- Rollback script for incoming_email_attachments
+DROP TABLE IF EXISTS incoming_email_attachments;
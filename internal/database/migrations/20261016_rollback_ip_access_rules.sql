@@ -0,0 +1,2 @@
+-- Rollback script for ip_access_rules
+DROP TABLE IF EXISTS ip_access_rules;
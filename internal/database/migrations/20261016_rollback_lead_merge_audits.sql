@@ -0,0 +1,2 @@
+-- Rollback script for lead_merge_audits
+DROP TABLE IF EXISTS lead_merge_audits;
@@ -0,0 +1,6 @@
+-- Rollback script for lead_reengagements blind-index columns
+DROP INDEX IF EXISTS idx_lead_reengagements_email_hash;
+DROP INDEX IF EXISTS idx_lead_reengagements_phone_hash;
+ALTER TABLE lead_reengagements DROP COLUMN IF EXISTS email_hash;
+ALTER TABLE lead_reengagements DROP COLUMN IF EXISTS phone_hash;
+ALTER TABLE lead_reengagements DROP COLUMN IF EXISTS blind_index_key_id;
@@ -0,0 +1,3 @@
+-- Rollback script for lead_reengagements.owner_id
+DROP INDEX IF EXISTS idx_lead_reengagements_owner_id;
+ALTER TABLE lead_reengagements DROP COLUMN IF EXISTS owner_id;
@@ -0,0 +1,2 @@
+-- Rollback script for lead_reengagements.search_index
+ALTER TABLE lead_reengagements DROP COLUMN IF EXISTS search_index;
@@ -0,0 +1,4 @@
+-- Rollback script for lead routing
+DROP TABLE IF EXISTS lead_assignments;
+DROP TABLE IF EXISTS agent_routing_profiles;
+DROP TABLE IF EXISTS lead_routing_configs;
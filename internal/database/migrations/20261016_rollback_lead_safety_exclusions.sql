@@ -0,0 +1,2 @@
+-- Rollback script for lead safety exclusions
+DROP TABLE IF EXISTS lead_safety_exclusions;
@@ -0,0 +1,5 @@
+-- Rollback script for pre_listing_items and approvals normalized address columns
+DROP INDEX IF EXISTS idx_pre_listing_items_normalized_address;
+DROP INDEX IF EXISTS idx_approvals_normalized_property_address;
+ALTER TABLE pre_listing_items DROP COLUMN IF EXISTS normalized_address;
+ALTER TABLE approvals DROP COLUMN IF EXISTS normalized_property_address;
@@ -0,0 +1,3 @@
+-- Rollback script for notification_preferences and queued_notifications
+DROP TABLE IF EXISTS queued_notifications;
+DROP TABLE IF EXISTS notification_preferences;
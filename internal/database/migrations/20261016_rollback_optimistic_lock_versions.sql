@@ -0,0 +1,4 @@
+-- Rollback script for optimistic lock version columns
+ALTER TABLE leads DROP COLUMN IF EXISTS version;
+ALTER TABLE pre_listing_items DROP COLUMN IF EXISTS version;
+ALTER TABLE properties DROP COLUMN IF EXISTS version;
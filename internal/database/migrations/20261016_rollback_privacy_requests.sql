@@ -0,0 +1,2 @@
+-- Rollback script for privacy_requests
+DROP TABLE IF EXISTS privacy_requests;
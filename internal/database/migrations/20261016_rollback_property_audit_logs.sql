@@ -0,0 +1,2 @@
+-- Rollback script for property_audit_logs
+DROP TABLE IF EXISTS property_audit_logs;
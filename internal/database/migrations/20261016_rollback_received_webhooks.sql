@@ -0,0 +1,2 @@
+-- Rollback script for received_webhooks
+DROP TABLE IF EXISTS received_webhooks;
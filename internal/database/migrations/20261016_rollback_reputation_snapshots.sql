@@ -0,0 +1,2 @@
+-- Rollback script for reputation_snapshots
+DROP TABLE IF EXISTS reputation_snapshots;
@@ -0,0 +1,5 @@
+-- Rollback script for saved_searches and saved_search_alerts
+DROP INDEX IF EXISTS idx_price_change_events_search_alerts_checked_at;
+ALTER TABLE price_change_events DROP COLUMN IF EXISTS search_alerts_checked_at;
+DROP TABLE IF EXISTS saved_search_alerts;
+DROP TABLE IF EXISTS saved_searches;
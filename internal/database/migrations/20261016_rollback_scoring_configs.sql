@@ -0,0 +1,2 @@
+-- Rollback script for scoring_configs
+DROP TABLE IF EXISTS scoring_configs;
@@ -0,0 +1,16 @@
+-- Rollback script for security_events
+-- Drops the threat feed columns; the original login event columns are kept
+DROP INDEX IF EXISTS idx_security_events_severity;
+DROP INDEX IF EXISTS idx_security_events_ip_address;
+DROP INDEX IF EXISTS idx_security_events_resolved;
+
+ALTER TABLE security_events DROP COLUMN IF EXISTS severity;
+ALTER TABLE security_events DROP COLUMN IF EXISTS user_agent;
+ALTER TABLE security_events DROP COLUMN IF EXISTS endpoint;
+ALTER TABLE security_events DROP COLUMN IF EXISTS method;
+ALTER TABLE security_events DROP COLUMN IF EXISTS status_code;
+ALTER TABLE security_events DROP COLUMN IF EXISTS payload;
+ALTER TABLE security_events DROP COLUMN IF EXISTS resolved;
+ALTER TABLE security_events DROP COLUMN IF EXISTS resolved_by;
+ALTER TABLE security_events DROP COLUMN IF EXISTS resolved_at;
+ALTER TABLE security_events DROP COLUMN IF EXISTS resolution_note;
@@ -0,0 +1,2 @@
+-- Rollback script for tasks
+DROP TABLE IF EXISTS tasks;
@@ -0,0 +1,2 @@
+-- Rollback script for trusted_email_senders.auth_policy
+ALTER TABLE trusted_email_senders DROP COLUMN IF EXISTS auth_policy;
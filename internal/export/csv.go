@@ -0,0 +1,44 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CSVWriter streams rows as CSV; output is flushed whenever its buffer fills
+type CSVWriter struct {
+	writer *csv.Writer
+}
+
+// NewCSVWriter creates a CSV row writer
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{writer: csv.NewWriter(w)}
+}
+
+// WriteRow writes one record, neutralizing cells a spreadsheet would run as formulas
+func (cw *CSVWriter) WriteRow(cells []string) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = escapeFormula(cell)
+	}
+	return cw.writer.Write(record)
+}
+
+// Close flushes any buffered output
+func (cw *CSVWriter) Close() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// escapeFormula prefixes cells starting with a formula trigger so spreadsheet
+// apps show them as text (CSV injection)
+func escapeFormula(cell string) string {
+	if cell == "" {
+		return cell
+	}
+	switch cell[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + cell
+	}
+	return cell
+}
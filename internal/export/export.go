@@ -0,0 +1,61 @@
+// Package export streams tabular data as CSV or XLSX so large exports never
+// have to be held in memory.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Supported export formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// RowWriter writes one row of cells at a time. Close must be called to
+// finish the output.
+type RowWriter interface {
+	WriteRow(cells []string) error
+	Close() error
+}
+
+// ParseFormat validates a requested format, defaulting to CSV
+func ParseFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX, "excel":
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q (use csv or xlsx)", format)
+	}
+}
+
+// NewRowWriter returns a streaming writer for the format
+func NewRowWriter(format string, w io.Writer) RowWriter {
+	if format == FormatXLSX {
+		return NewXLSXWriter(w)
+	}
+	return NewCSVWriter(w)
+}
+
+// ContentType is the MIME type for the format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Filename builds a dated attachment name, e.g. leads_2026-10-16.csv
+func Filename(base, format string, now time.Time) string {
+	return fmt.Sprintf("%s_%s.%s", base, now.Format("2006-01-02"), format)
+}
+
+// ContentDisposition is the attachment header value for a filename
+func ContentDisposition(filename string) string {
+	return fmt.Sprintf(`attachment; filename="%s"`, filename)
+}
@@ -0,0 +1,66 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCSVWriter_EscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf)
+	w.WriteRow([]string{"name", "note"})
+	w.WriteRow([]string{"Jane, Doe", "=HYPERLINK(\"http://evil\")"})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	want := "name,note\n\"Jane, Doe\",\"'=HYPERLINK(\"\"http://evil\"\")\"\n"
+	if buf.String() != want {
+		t.Fatalf("Unexpected CSV output:\n%s", buf.String())
+	}
+}
+
+func TestXLSXWriter_WritesReadableWorkbook(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewXLSXWriter(&buf)
+	w.WriteRow([]string{"name", "email"})
+	w.WriteRow([]string{"Tom & Jerry", "<tom@example.com>"})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Output is not a zip archive: %v", err)
+	}
+	parts := map[string]string{}
+	for _, f := range archive.File {
+		rc, _ := f.Open()
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(content)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("Missing workbook part %s", name)
+		}
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{`<c r="A1" t="inlineStr">`, `<c r="B2" t="inlineStr">`, "Tom &amp; Jerry", "&lt;tom@example.com&gt;", "</sheetData></worksheet>"} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("Expected sheet to contain %q", want)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for index, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(index); got != want {
+			t.Errorf("columnName(%d) = %s, want %s", index, got, want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The fixed parts of a single-sheet workbook
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Export" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// XLSXWriter streams rows into a single-sheet workbook. Cells are written as
+// inline strings, so no shared-string table has to be built in memory.
+type XLSXWriter struct {
+	zip     *zip.Writer
+	sheet   *bufio.Writer
+	row     int
+	started bool
+	err     error
+}
+
+// NewXLSXWriter creates an XLSX row writer
+func NewXLSXWriter(w io.Writer) *XLSXWriter {
+	return &XLSXWriter{zip: zip.NewWriter(w)}
+}
+
+// WriteRow appends one row to the sheet
+func (xw *XLSXWriter) WriteRow(cells []string) error {
+	if err := xw.start(); err != nil {
+		return err
+	}
+
+	xw.row++
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, xw.row)
+	for i, cell := range cells {
+		fmt.Fprintf(&b, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(i), xw.row)
+		xml.EscapeText(&b, []byte(cell))
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+
+	if _, err := xw.sheet.WriteString(b.String()); err != nil {
+		xw.err = err
+	}
+	return xw.err
+}
+
+// Close finishes the sheet and the zip archive
+func (xw *XLSXWriter) Close() error {
+	if err := xw.start(); err != nil {
+		return err
+	}
+	if _, err := xw.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := xw.sheet.Flush(); err != nil {
+		return err
+	}
+	return xw.zip.Close()
+}
+
+// start writes the static parts and opens the sheet on first use
+func (xw *XLSXWriter) start() error {
+	if xw.started || xw.err != nil {
+		return xw.err
+	}
+	xw.started = true
+
+	for _, part := range xlsxStaticParts {
+		f, err := xw.zip.Create(part.name)
+		if err == nil {
+			_, err = io.WriteString(f, part.content)
+		}
+		if err != nil {
+			xw.err = err
+			return err
+		}
+	}
+
+	f, err := xw.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		xw.err = err
+		return err
+	}
+	xw.sheet = bufio.NewWriter(f)
+	_, xw.err = xw.sheet.WriteString(xml.Header +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return xw.err
+}
+
+// columnName converts a zero-based column index to its letter name (0 -> A, 26 -> AA)
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
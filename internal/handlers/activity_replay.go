@@ -0,0 +1,64 @@
+package handlers
+
+// Activity feed delivery semantics
+//
+// Every activity event gets a cursor, one higher than the event before it.
+// Live delivery is at-most-once: a client whose send buffer is full is
+// disconnected rather than slowing the hub down. A client that reconnects
+// with ?last_cursor=N is first sent the buffered events after N, in order,
+// and then live events, which makes delivery at-least-once for as long as
+// the missed events are still buffered. Clients should ignore cursors they
+// have already seen. When the missed events have left the buffer (or the
+// cursor is from before a server restart) the client is sent a
+// "replay_truncated" message and should reload the feed.
+
+// activityReplayBufferSize bounds how many recent events are kept for
+// replay. It stays below the client send buffer so a full replay never
+// overflows it.
+const activityReplayBufferSize = 200
+
+// activityRecord is a broadcast activity message and its cursor
+type activityRecord struct {
+	cursor  uint64
+	message WebSocketMessage
+}
+
+// activityReplayBuffer is a fixed-size ring of the most recent activity messages
+type activityReplayBuffer struct {
+	records []activityRecord
+	next    int
+	full    bool
+}
+
+func newActivityReplayBuffer(size int) *activityReplayBuffer {
+	return &activityReplayBuffer{records: make([]activityRecord, size)}
+}
+
+func (b *activityReplayBuffer) add(record activityRecord) {
+	b.records[b.next] = record
+	b.next = (b.next + 1) % len(b.records)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// since returns the buffered records after cursor, oldest first, and whether
+// every event after cursor up to latest is among them
+func (b *activityReplayBuffer) since(cursor, latest uint64) ([]activityRecord, bool) {
+	ordered := b.records[:b.next]
+	if b.full {
+		ordered = append(append([]activityRecord{}, b.records[b.next:]...), b.records[:b.next]...)
+	}
+	if cursor > latest {
+		// The client's cursor is from before a restart
+		return ordered, false
+	}
+	var missed []activityRecord
+	for _, record := range ordered {
+		if record.cursor > cursor {
+			missed = append(missed, record)
+		}
+	}
+	complete := uint64(len(missed)) == latest-cursor
+	return missed, complete
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestActivityHubReplay(t *testing.T) {
+	hub := &ActivityHub{clients: make(map[*WebSocketClient]bool), replay: newActivityReplayBuffer(3)}
+	for i := 0; i < 5; i++ {
+		hub.cursor++
+		hub.replay.add(activityRecord{cursor: hub.cursor, message: WebSocketMessage{Type: "activity_event", Cursor: hub.cursor}})
+	}
+
+	backlog := func(sub activitySubscription) []WebSocketMessage {
+		sub.client = &WebSocketClient{send: make(chan WebSocketMessage, 16)}
+		hub.sendBacklog(sub)
+		close(sub.client.send)
+		var messages []WebSocketMessage
+		for message := range sub.client.send {
+			messages = append(messages, message)
+		}
+		return messages
+	}
+	describe := func(messages []WebSocketMessage) []string {
+		var got []string
+		for _, m := range messages {
+			if m.Type == "activity_event" {
+				got = append(got, fmt.Sprintf("%s:%d", m.Type, m.Cursor))
+			} else {
+				got = append(got, m.Type)
+			}
+		}
+		return got
+	}
+
+	tests := []struct {
+		name string
+		sub  activitySubscription
+		want []string
+	}{
+		{"new client", activitySubscription{}, []string{"activity_cursor"}},
+		{"missed two", activitySubscription{lastCursor: 3, resume: true}, []string{"activity_event:4", "activity_event:5", "activity_cursor"}},
+		{"up to date", activitySubscription{lastCursor: 5, resume: true}, []string{"activity_cursor"}},
+		{"missed more than buffered", activitySubscription{lastCursor: 1, resume: true}, []string{"replay_truncated", "activity_event:3", "activity_event:4", "activity_event:5", "activity_cursor"}},
+		{"cursor from before a restart", activitySubscription{lastCursor: 40, resume: true}, []string{"replay_truncated", "activity_event:3", "activity_event:4", "activity_event:5", "activity_cursor"}},
+	}
+	for _, tt := range tests {
+		got := describe(backlog(tt.sub))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
 	"time"
 
 	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/crypto/bcrypt"
@@ -15,8 +17,10 @@ import (
 )
 
 type AdminAuthHandlers struct {
-	db        *gorm.DB
-	jwtSecret string
+	db             *gorm.DB
+	jwtSecret      string
+	accessTokenTTL time.Duration
+	refreshTokens  *services.AdminRefreshTokenService
 }
 
 type LoginRequest struct {
@@ -26,15 +30,26 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	Success bool   `json:"success"`
-	Token   string `json:"token,omitempty"`
-	Message string `json:"message,omitempty"`
+	Success      bool   `json:"success"`
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int    `json:"expires_in,omitempty"` // Access token lifetime in seconds
+	Message      string `json:"message,omitempty"`
 }
 
-func NewAdminAuthHandlers(db *gorm.DB, jwtSecret string) *AdminAuthHandlers {
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func NewAdminAuthHandlers(db *gorm.DB, jwtSecret string, accessTokenTTL, refreshTokenTTL time.Duration) *AdminAuthHandlers {
+	if accessTokenTTL <= 0 {
+		accessTokenTTL = 15 * time.Minute
+	}
 	return &AdminAuthHandlers{
-		db:        db,
-		jwtSecret: jwtSecret,
+		db:             db,
+		jwtSecret:      jwtSecret,
+		accessTokenTTL: accessTokenTTL,
+		refreshTokens:  services.NewAdminRefreshTokenService(db, refreshTokenTTL),
 	}
 }
 
@@ -95,16 +110,20 @@ func (h *AdminAuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id":  user.ID,
-		"username": user.Username,
-		"email":    user.Email,
-		"role":     user.Role,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(),
-	})
+	tokenString, err := h.signAccessToken(&user)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(LoginResponse{
+			Success: false,
+			Message: "Failed to generate token",
+		})
+		return
+	}
 
-	tokenString, err := token.SignedString([]byte(h.jwtSecret))
+	refreshToken, err := h.refreshTokens.Issue(user.ID, extractIPAddress(r), r.UserAgent())
 	if err != nil {
+		log.Printf("⚠️ Failed to issue refresh token for %s: %v", user.Email, err)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(LoginResponse{
@@ -124,11 +143,116 @@ func (h *AdminAuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 	log.Printf("✅ Successful login for user: %s (Role: %s)", user.Email, user.Role)
 
 	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(LoginResponse{
+		Success:      true,
+		Token:        tokenString,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(h.accessTokenTTL.Seconds()),
+		Message:      "Login successful",
+	})
+}
+
+// Refresh exchanges a refresh token for a new access token and rotates the
+// refresh token. Presenting an already-rotated token revokes the whole session.
+func (h *AdminAuthHandlers) Refresh(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(LoginResponse{
+			Success: false,
+			Message: "refresh_token is required",
+		})
+		return
+	}
+
+	refreshToken, previous, err := h.refreshTokens.Rotate(req.RefreshToken, extractIPAddress(r), r.UserAgent())
+	if err != nil {
+		message := "Invalid or expired refresh token"
+		if errors.Is(err, services.ErrRefreshTokenReused) {
+			message = "Refresh token has already been used; session revoked"
+		} else if !errors.Is(err, services.ErrRefreshTokenInvalid) {
+			log.Printf("⚠️ Refresh token rotation failed: %v", err)
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(LoginResponse{
+			Success: false,
+			Message: message,
+		})
+		return
+	}
+
+	var user models.AdminUser
+	if err := h.db.Where("id = ? AND active = ?", previous.AdminUserID, true).First(&user).Error; err != nil {
+		h.refreshTokens.RevokeFamily(previous.FamilyID, "account_inactive")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(LoginResponse{
+			Success: false,
+			Message: "Account is no longer active",
+		})
+		return
+	}
+
+	tokenString, err := h.signAccessToken(&user)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(LoginResponse{
+			Success: false,
+			Message: "Failed to generate token",
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(LoginResponse{
+		Success:      true,
+		Token:        tokenString,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(h.accessTokenTTL.Seconds()),
+		Message:      "Token refreshed",
+	})
+}
+
+// Logout revokes the presented refresh token and every token rotated from it
+func (h *AdminAuthHandlers) Logout(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(LoginResponse{
+			Success: false,
+			Message: "refresh_token is required",
+		})
+		return
+	}
+
+	// Unknown tokens are treated as already logged out
+	if err := h.refreshTokens.Revoke(req.RefreshToken); err != nil && !errors.Is(err, services.ErrRefreshTokenInvalid) {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(LoginResponse{
+			Success: false,
+			Message: "Failed to revoke session",
+		})
+		return
+	}
+
 	json.NewEncoder(w).Encode(LoginResponse{
 		Success: true,
-		Token:   tokenString,
-		Message: "Login successful",
+		Message: "Logged out",
+	})
+}
+
+// signAccessToken issues a short-lived access token for the user
+func (h *AdminAuthHandlers) signAccessToken(user *models.AdminUser) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":  user.ID,
+		"username": user.Username,
+		"email":    user.Email,
+		"role":     user.Role,
+		"exp":      time.Now().Add(h.accessTokenTTL).Unix(),
 	})
+	return token.SignedString([]byte(h.jwtSecret))
 }
 
 func (h *AdminAuthHandlers) AuthStatus(w http.ResponseWriter, r *http.Request) {
@@ -166,9 +290,11 @@ func (h *AdminAuthHandlers) AuthStatus(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func RegisterAdminAuthRoutes(r *gin.Engine, db *gorm.DB, jwtSecret string) {
-	h := NewAdminAuthHandlers(db, jwtSecret)
+func RegisterAdminAuthRoutes(r *gin.Engine, db *gorm.DB, jwtSecret string, accessTokenTTL, refreshTokenTTL time.Duration) {
+	h := NewAdminAuthHandlers(db, jwtSecret, accessTokenTTL, refreshTokenTTL)
 	r.POST("/api/v1/admin/login", gin.WrapF(h.Login))
+	r.POST("/api/v1/admin/refresh", gin.WrapF(h.Refresh))
+	r.POST("/api/v1/admin/logout", gin.WrapF(h.Logout))
 	r.GET("/admin/auth/status", gin.WrapF(h.AuthStatus))
 
 	log.Println("✅ Enterprise admin authentication routes registered successfully")
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"time"
 
+	"chrisgross-ctrl-project/internal/middleware"
+	"chrisgross-ctrl-project/internal/models"
 	"chrisgross-ctrl-project/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -25,11 +28,10 @@ func NewAdminNotificationHandler(hub *services.AdminNotificationHub, db *gorm.DB
 }
 
 var notificationUpgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
+	CheckOrigin:     middleware.CheckWebSocketOrigin,
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
+	Subprotocols:    []string{middleware.WebSocketSubprotocol},
 }
 
 func (h *AdminNotificationHandler) HandleWebSocket(c *gin.Context) {
@@ -39,9 +41,18 @@ func (h *AdminNotificationHandler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
-	h.hub.Register(conn)
+	h.hub.Register(conn, c.GetString("user_id"))
+
+	// The reader drops the connection when pongs stop arriving; pings are
+	// control frames, which are safe to send alongside the hub's writes
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	done := make(chan struct{})
 
 	go func() {
+		defer close(done)
 		defer h.hub.Unregister(conn)
 		for {
 			_, _, err := conn.ReadMessage()
@@ -54,13 +65,15 @@ func (h *AdminNotificationHandler) HandleWebSocket(c *gin.Context) {
 		}
 	}()
 
-	ticker := time.NewTicker(54 * time.Second)
+	ticker := time.NewTicker(wsPingPeriod)
 	defer ticker.Stop()
 
 	for {
 		select {
+		case <-done:
+			return
 		case <-ticker.C:
-			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
 				return
 			}
 		}
@@ -126,3 +139,194 @@ func (h *AdminNotificationHandler) MarkAllAsRead(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
+
+// ListAdminNotifications returns the signed-in admin's recent notifications
+// with their own read state; ?unread=true returns only unread ones
+func (h *AdminNotificationHandler) ListAdminNotifications(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, err := h.hub.ListNotificationsFor(admin.ID, unreadOnly, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications", "details": err.Error()})
+		return
+	}
+	unread, err := h.hub.UnreadCountFor(admin.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get unread count", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"count":         len(notifications),
+		"unread_count":  unread,
+	})
+}
+
+// GetAdminUnreadCount returns how many notifications the signed-in admin has
+// not read. The notification WebSocket pushes the same count as an
+// "unread_count" message whenever the admin marks notifications read.
+func (h *AdminNotificationHandler) GetAdminUnreadCount(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	count, err := h.hub.UnreadCountFor(admin.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get unread count", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"unread_count": count})
+}
+
+// MarkAdminNotificationRead marks one notification read for the signed-in admin
+func (h *AdminNotificationHandler) MarkAdminNotificationRead(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+	if err := h.db.Select("id").First(&models.AdminNotification{}, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	if err := h.hub.MarkReadFor(admin.ID, []uint{uint(id)}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark as read", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// MarkAllAdminNotificationsRead dismisses notifications for the signed-in
+// admin: the IDs in an optional {"ids": [...]} body, or every unread one
+func (h *AdminNotificationHandler) MarkAllAdminNotificationsRead(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var req struct {
+		IDs []uint `json:"ids"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+			return
+		}
+	}
+	var ids []uint
+	if len(req.IDs) > 0 {
+		// Ignore IDs of notifications that do not exist
+		if err := h.db.Model(&models.AdminNotification{}).Where("id IN ?", req.IDs).Pluck("id", &ids).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark as read", "details": err.Error()})
+			return
+		}
+		if len(ids) == 0 {
+			c.JSON(http.StatusOK, gin.H{"success": true})
+			return
+		}
+	}
+
+	if err := h.hub.MarkReadFor(admin.ID, ids); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark all as read", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// GetNotificationPreferences returns the signed-in admin's notification preferences
+func (h *AdminNotificationHandler) GetNotificationPreferences(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	prefs, err := h.hub.GetPreferences(admin.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notification preferences", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"preferences": prefs,
+		"defaults":    models.DefaultNotificationPreference(admin.ID, models.NotificationEventTypeDefault),
+	})
+}
+
+// UpdateNotificationPreferences creates or replaces the signed-in admin's
+// preferences for the event types in the request
+func (h *AdminNotificationHandler) UpdateNotificationPreferences(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var req struct {
+		Preferences []models.NotificationPreference `json:"preferences" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	for _, pref := range req.Preferences {
+		if err := validateNotificationPreference(pref); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification preference", "details": err.Error()})
+			return
+		}
+	}
+
+	prefs, err := h.hub.SavePreferences(admin.ID, req.Preferences)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save notification preferences", "details": err.Error()})
+		return
+	}
+
+	log.Printf("🔔 Notification preferences updated for %s", admin.Username)
+	c.JSON(http.StatusOK, gin.H{"success": true, "preferences": prefs})
+}
+
+func validateNotificationPreference(pref models.NotificationPreference) error {
+	if pref.EventType == "" {
+		return fmt.Errorf("event_type is required; use %q for the default", models.NotificationEventTypeDefault)
+	}
+	if pref.QuietHoursStart < 0 || pref.QuietHoursStart > 23 || pref.QuietHoursEnd < 0 || pref.QuietHoursEnd > 23 {
+		return fmt.Errorf("%s: quiet hours must be between 0 and 23", pref.EventType)
+	}
+	if pref.Timezone != "" {
+		if _, err := time.LoadLocation(pref.Timezone); err != nil {
+			return fmt.Errorf("%s: unknown timezone %q", pref.EventType, pref.Timezone)
+		}
+	}
+	if pref.SMS && pref.SMSPhone == "" {
+		return fmt.Errorf("%s: sms_phone is required for SMS delivery", pref.EventType)
+	}
+	return nil
+}
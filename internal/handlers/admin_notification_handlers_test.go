@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+func TestAdminNotifications_ReadStateIsPerAdmin(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AdminNotification{}, &models.AdminNotificationRead{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	for _, title := range []string{"New Booking", "Hot Lead", "Application"} {
+		db.Create(&models.AdminNotification{Type: "booking_created", Title: title, Priority: "normal"})
+	}
+
+	handler := NewAdminNotificationHandler(services.NewAdminNotificationHub(db), db)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &models.AdminUser{ID: c.GetHeader("X-Test-Admin"), Username: "agent"})
+	})
+	router.GET("/admin/notifications", handler.ListAdminNotifications)
+	router.GET("/admin/notifications/unread-count", handler.GetAdminUnreadCount)
+	router.POST("/admin/notifications/:id/read", handler.MarkAdminNotificationRead)
+	router.POST("/admin/notifications/mark-all-read", handler.MarkAllAdminNotificationsRead)
+
+	do := func(admin, method, path string, body []byte) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("X-Test-Admin", admin)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		router.ServeHTTP(w, req)
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+	unread := func(admin string) float64 {
+		_, resp := do(admin, http.MethodGet, "/admin/notifications/unread-count", nil)
+		count, _ := resp["unread_count"].(float64)
+		return count
+	}
+
+	if code, _ := do("admin-1", http.MethodPost, "/admin/notifications/1/read", nil); code != http.StatusOK {
+		t.Fatalf("Expected 200 marking a notification read, got %d", code)
+	}
+	if code, _ := do("admin-1", http.MethodPost, "/admin/notifications/99/read", nil); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown notification, got %d", code)
+	}
+	if got := unread("admin-1"); got != 2 {
+		t.Errorf("Expected 2 unread for admin-1, got %v", got)
+	}
+	if got := unread("admin-2"); got != 3 {
+		t.Errorf("Expected another admin's reads not to count, got %v unread", got)
+	}
+
+	_, resp := do("admin-1", http.MethodGet, "/admin/notifications?unread=true", nil)
+	if notifications, _ := resp["notifications"].([]interface{}); len(notifications) != 2 {
+		t.Errorf("Expected 2 unread notifications listed, got %v", resp["notifications"])
+	}
+
+	// Bulk dismiss a selection, then everything
+	do("admin-2", http.MethodPost, "/admin/notifications/mark-all-read", []byte(`{"ids":[2,3,99]}`))
+	if got := unread("admin-2"); got != 1 {
+		t.Errorf("Expected 1 unread after dismissing two, got %v", got)
+	}
+	do("admin-2", http.MethodPost, "/admin/notifications/mark-all-read", nil)
+	if got := unread("admin-2"); got != 0 {
+		t.Errorf("Expected 0 unread after mark-all-read, got %v", got)
+	}
+	if got := unread("admin-1"); got != 2 {
+		t.Errorf("Expected admin-1 to still have 2 unread, got %v", got)
+	}
+}
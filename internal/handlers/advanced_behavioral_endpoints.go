@@ -92,7 +92,7 @@ func (e *AdvancedBehavioralEndpoints) ProcessAdvancedBehavioralAutomation(c *gin
 		PropertyContext: request.PropertyContext,
 	}
 
-	basicResponse := e.contextHandler.processHybridContextTrigger(contextTrigger)
+	basicResponse := e.contextHandler.processHybridContextTrigger(c.Request.Context(), contextTrigger)
 
 	advancedResponse := AdvancedContextFUBTriggerResponse{
 		Success:           basicResponse.Success,
@@ -27,7 +27,7 @@ func (h *ContextFUBIntegrationHandlers) ProcessAdvancedBehavioralTriggers(c *gin
 
 	propertyCategory := h.detectAdvancedPropertyCategory(request.PropertyContext, request.BehaviorContext)
 	
-	behavioralScore := h.calculatePropertySpecificBehavioralScore(propertyCategory, request.BehaviorContext)
+	behavioralScore, behavioralFactors := h.explainPropertySpecificBehavioralScore(propertyCategory, request.BehaviorContext)
 	
 	historicalContext := []map[string]interface{}{
 		request.UserContext,
@@ -55,7 +55,10 @@ func (h *ContextFUBIntegrationHandlers) ProcessAdvancedBehavioralTriggers(c *gin
 	
 	patternAnalysis := h.generatePatternAnalysis(request.BehaviorContext, historicalPatterns)
 	
-	advancedPriority := h.calculateAdvancedPriority(behavioralScore, 0.6, 0.5, 0.7, propertyCategory)
+	priorityExplanation := h.explainAdvancedPriority(behavioralScore, 0.6, 0.5, 0.7, propertyCategory)
+	// The engagement input is the behavioral score, so show its breakdown under it
+	priorityExplanation.Factors[0].Factors = behavioralFactors
+	advancedPriority := priorityExplanation.Label
 	
 	triggerMessage := h.generateAdvancedTriggerMessage(request.TriggerData, patternAnalysis)
 
@@ -75,6 +78,7 @@ func (h *ContextFUBIntegrationHandlers) ProcessAdvancedBehavioralTriggers(c *gin
 		TriggerID:         fmt.Sprintf("adv_trig_%d", time.Now().UnixNano()),
 		WorkflowType:      workflowType,
 		ScheduledAt:       nextActionTime,
+		ScoreExplanation:  &priorityExplanation,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 	"strconv"
 	"time"
 
+	"chrisgross-ctrl-project/internal/models"
 	"chrisgross-ctrl-project/internal/security"
+	"chrisgross-ctrl-project/internal/services"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -14,6 +17,7 @@ import (
 type AdvancedSecurityAPIHandlers struct {
 	db              *gorm.DB
 	securityManager *security.EncryptionManager
+	rotation        *services.EncryptionRotationService
 }
 
 // NewAdvancedSecurityAPIHandlers creates new advanced security API handlers
@@ -24,6 +28,11 @@ func NewAdvancedSecurityAPIHandlers(db *gorm.DB, securityManager *security.Encry
 	}
 }
 
+// SetEncryptionRotation enables the encryption status and rotation endpoints
+func (h *AdvancedSecurityAPIHandlers) SetEncryptionRotation(rotation *services.EncryptionRotationService) {
+	h.rotation = rotation
+}
+
 // SecurityThreatRequest represents a security threat analysis request
 type SecurityThreatRequest struct {
 	IPAddress   string                 `json:"ip_address" binding:"required"`
@@ -66,23 +75,8 @@ type SecuritySession struct {
 	UpdatedAt    time.Time              `json:"updated_at"`
 }
 
-// SecurityEvent represents a security event (centralized definition)
-type SecurityEvent struct {
-	ID         uint                   `json:"id" gorm:"primaryKey"`
-	Type       string                 `json:"type"`
-	Severity   string                 `json:"severity"`
-	UserID     *uint                  `json:"user_id,omitempty"`
-	IPAddress  string                 `json:"ip_address"`
-	UserAgent  string                 `json:"user_agent"`
-	Endpoint   string                 `json:"endpoint"`
-	Method     string                 `json:"method"`
-	StatusCode int                    `json:"status_code"`
-	Metadata   map[string]interface{} `json:"metadata" gorm:"type:jsonb"`
-	Timestamp  time.Time              `json:"timestamp"`
-	Resolved   bool                   `json:"resolved"`
-	ResolvedBy *uint                  `json:"resolved_by,omitempty"`
-	ResolvedAt *time.Time             `json:"resolved_at,omitempty"`
-}
+// SecurityEvent is the security_events row shared with the security middlewares
+type SecurityEvent = models.SecurityEvent
 
 // AnalyzeThreat handles POST /api/v1/security/analyze-threat
 func (h *AdvancedSecurityAPIHandlers) AnalyzeThreat(c *gin.Context) {
@@ -329,13 +323,13 @@ func (h *AdvancedSecurityAPIHandlers) GetSecurityMetrics(c *gin.Context) {
 
 	h.db.Model(&SecurityEvent{}).
 		Select("severity as risk_level, COUNT(*) as count").
-		Where("type = ? AND timestamp >= ?", "THREAT_ANALYSIS", since).
+		Where("event_type = ? AND created_at >= ?", "THREAT_ANALYSIS", since).
 		Group("severity").
 		Scan(&threatStats)
 
 	var blockedCount int64
 	h.db.Model(&SecurityEvent{}).
-		Where("type = ? AND timestamp >= ? AND metadata->>'action_taken' = ?", "THREAT_ANALYSIS", since, "BLOCK_REQUEST").
+		Where("event_type = ? AND created_at >= ? AND event_data->>'action_taken' = ?", "THREAT_ANALYSIS", since, "BLOCK_REQUEST").
 		Count(&blockedCount)
 
 	var topThreats []struct {
@@ -345,7 +339,7 @@ func (h *AdvancedSecurityAPIHandlers) GetSecurityMetrics(c *gin.Context) {
 
 	h.db.Model(&SecurityEvent{}).
 		Select("ip_address, COUNT(*) as count").
-		Where("type = ? AND timestamp >= ?", "THREAT_ANALYSIS", since).
+		Where("event_type = ? AND created_at >= ?", "THREAT_ANALYSIS", since).
 		Group("ip_address").
 		Order("count DESC").
 		Limit(10).
@@ -403,6 +397,99 @@ func (h *AdvancedSecurityAPIHandlers) CreateSecuritySession(c *gin.Context) {
 	})
 }
 
+// GetEncryptionStatus handles GET /api/v1/security/encryption/status, reporting
+// the current key version, the encrypted fields, rows still under an older key
+// and the last rotation
+func (h *AdvancedSecurityAPIHandlers) GetEncryptionStatus(c *gin.Context) {
+	if _, ok := h.encryptionAdmin(c); !ok {
+		return
+	}
+
+	status, err := h.rotation.Status()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load encryption status", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "status": status})
+}
+
+// RotateEncryption handles POST /api/v1/security/encryption/rotate, starting the
+// backfill that re-encrypts stored PII to the current key. The key itself is
+// rotated by deploying a new ENCRYPTION_KEY with the old one in
+// ENCRYPTION_PREVIOUS_KEYS. Tables default to every rotatable table; tables
+// with a job already running are skipped.
+func (h *AdvancedSecurityAPIHandlers) RotateEncryption(c *gin.Context) {
+	admin, ok := h.encryptionAdmin(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Tables []string `json:"tables"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+			return
+		}
+	}
+	if len(request.Tables) == 0 {
+		request.Tables = services.RotatableTables()
+	}
+	for _, table := range request.Tables {
+		if _, ok := services.EncryptedColumns[table]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Unknown table",
+				"details": table,
+				"tables":  services.RotatableTables(),
+			})
+			return
+		}
+	}
+
+	jobs := []*models.EncryptionRotationJob{}
+	skipped := gin.H{}
+	for _, table := range request.Tables {
+		job, err := h.rotation.StartRotation(table, admin.Username)
+		if err != nil {
+			skipped[table] = err.Error()
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	if len(jobs) == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "No key rotation started", "skipped": skipped})
+		return
+	}
+
+	log.Printf("🔑 %s started key rotation for %d tables", admin.Username, len(jobs))
+	c.JSON(http.StatusAccepted, gin.H{
+		"success":        true,
+		"current_key_id": h.securityManager.CurrentKeyID(),
+		"jobs":           jobs,
+		"skipped":        skipped,
+	})
+}
+
+// encryptionAdmin returns the signed-in admin when they may manage encryption keys
+func (h *AdvancedSecurityAPIHandlers) encryptionAdmin(c *gin.Context) (*models.AdminUser, bool) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return nil, false
+	}
+	if !models.IsTeamAdminRole(admin.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Encryption management requires a team admin"})
+		return nil, false
+	}
+	if h.rotation == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Encryption management is not available"})
+		return nil, false
+	}
+	return admin, true
+}
+
 // RegisterAdvancedSecurityRoutes registers advanced security API routes
 func RegisterAdvancedSecurityRoutes(r *gin.Engine, db *gorm.DB, securityManager *security.EncryptionManager) {
 	handlers := NewAdvancedSecurityAPIHandlers(db, securityManager)
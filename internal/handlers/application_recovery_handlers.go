@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ApplicationRecoveryHandlers tracks applications started on the site and
+// reports how reminders to finish them turned out
+type ApplicationRecoveryHandlers struct {
+	workflow *services.ApplicationWorkflowService
+	recovery *services.AbandonmentRecoveryService
+}
+
+// NewApplicationRecoveryHandlers creates application recovery handlers
+func NewApplicationRecoveryHandlers(workflow *services.ApplicationWorkflowService, recovery *services.AbandonmentRecoveryService) *ApplicationRecoveryHandlers {
+	return &ApplicationRecoveryHandlers{workflow: workflow, recovery: recovery}
+}
+
+// RecordApplicationStarted handles POST /api/v1/applications/started, called
+// when an applicant begins an application so it can be recovered if they stall
+func (h *ApplicationRecoveryHandlers) RecordApplicationStarted(c *gin.Context) {
+	var start services.ApplicationStart
+	if err := c.ShouldBindJSON(&start); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	applicant, err := h.workflow.RecordApplicationStarted(start)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record application", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "applicant_id": applicant.ID})
+}
+
+// GetRecoveryReport handles GET /api/v1/application-recovery/report, counting
+// recoveries by outcome over the last ?days= days (default 30)
+func (h *ApplicationRecoveryHandlers) GetRecoveryReport(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days"})
+		return
+	}
+
+	report, err := h.recovery.ApplicationRecoveryReport(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build recovery report", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -146,9 +147,7 @@ func (awh *ApplicationWorkflowHandlers) MoveApplicantToApplication(c *gin.Contex
 	err := awh.service.MoveApplicantToApplication(request.ApplicantID, request.TargetApplicationID, 
 		request.MovedBy, request.Reason)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to move applicant",
-		})
+		awh.respondApplicationError(c, err, "Failed to move applicant")
 		return
 	}
 	
@@ -229,34 +228,32 @@ func (awh *ApplicationWorkflowHandlers) UpdateApplicationStatus(c *gin.Context)
 		return
 	}
 	
-	// Get application number
-	var appNumber models.ApplicationNumber
-	if err := awh.db.First(&appNumber, request.ApplicationNumberID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Application number not found",
-		})
-		return
+	if request.ApplicationNumberID == 0 {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid application ID",
+			})
+			return
+		}
+		request.ApplicationNumberID = uint(id)
 	}
 	
-	// Update status
-	err := appNumber.UpdateStatus(awh.db, request.Status, request.UpdatedBy, request.Reason)
+	transition, err := awh.service.TransitionApplication(request.ApplicationNumberID, request.Status,
+		request.UpdatedBy, request.Reason, request.Notes)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update status",
-		})
+		awh.respondApplicationError(c, err, "Failed to update status")
 		return
 	}
-	
-	// Add notes if provided
-	if request.Notes != "" {
-		appNumber.InternalNotes = appendNote(appNumber.InternalNotes, 
-			fmt.Sprintf("Notes added by %s: %s", request.UpdatedBy, request.Notes))
-		awh.db.Save(&appNumber)
-	}
+	awh.notifyStatusChange(transition)
 	
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": fmt.Sprintf("%s status updated to %s", appNumber.ApplicationName, request.Status),
+		"message": fmt.Sprintf("%s status updated to %s", transition.Application.ApplicationName, request.Status),
+		"data": gin.H{
+			"application": transition.Application,
+			"transition":  transition.Log,
+		},
 	})
 }
 
@@ -382,41 +379,24 @@ func (awh *ApplicationWorkflowHandlers) ApproveApplication(c *gin.Context) {
 		return
 	}
 
-	var appNumber models.ApplicationNumber
-	if err := awh.db.First(&appNumber, uint(applicationID)).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Application not found",
-		})
-		return
-	}
-
 	updatedBy := c.GetString("user_email")
 	if updatedBy == "" {
 		updatedBy = "system"
 	}
 
-	if err := appNumber.UpdateStatus(awh.db, models.AppStatusApproved, updatedBy, "Application approved"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to approve application",
-		})
+	transition, err := awh.service.TransitionApplication(uint(applicationID), models.AppStatusApproved, updatedBy, "Application approved", "")
+	if err != nil {
+		awh.respondApplicationError(c, err, "Failed to approve application")
 		return
 	}
-
-	if awh.notificationHub != nil {
-		var propertyGroup models.PropertyApplicationGroup
-		if err := awh.db.Preload("Property").First(&propertyGroup, appNumber.PropertyApplicationGroupID).Error; err == nil {
-			propertyAddress := propertyGroup.PropertyAddress
-			applicantName := fmt.Sprintf("Application %d", appNumber.ApplicationNumber)
-			awh.notificationHub.SendApplicationAlert(propertyAddress, applicantName, appNumber.ID)
-		}
-	}
+	awh.notifyStatusChange(transition)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Application approved successfully",
 		"data": gin.H{
-			"application_id": appNumber.ID,
-			"status":         appNumber.Status,
+			"application_id": transition.Application.ID,
+			"status":         transition.Application.Status,
 		},
 	})
 }
@@ -442,38 +422,25 @@ func (awh *ApplicationWorkflowHandlers) DenyApplication(c *gin.Context) {
 		return
 	}
 
-	var appNumber models.ApplicationNumber
-	if err := awh.db.First(&appNumber, uint(applicationID)).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Application not found",
-		})
-		return
-	}
-
 	updatedBy := c.GetString("user_email")
 	if updatedBy == "" {
 		updatedBy = "system"
 	}
 
-	reason := request.Reason
-	if reason == "" {
-		reason = "Application denied"
-	}
-
-	if err := appNumber.UpdateStatus(awh.db, models.AppStatusDenied, updatedBy, reason); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to deny application",
-		})
+	transition, err := awh.service.TransitionApplication(uint(applicationID), models.AppStatusDenied, updatedBy, request.Reason, "")
+	if err != nil {
+		awh.respondApplicationError(c, err, "Failed to deny application")
 		return
 	}
+	awh.notifyStatusChange(transition)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Application denied",
 		"data": gin.H{
-			"application_id": appNumber.ID,
-			"status":         appNumber.Status,
-			"reason":         reason,
+			"application_id": transition.Application.ID,
+			"status":         transition.Application.Status,
+			"reason":         request.Reason,
 		},
 	})
 }
@@ -535,3 +502,123 @@ func (awh *ApplicationWorkflowHandlers) RequestMoreInfo(c *gin.Context) {
 func (awh *ApplicationWorkflowHandlers) SetNotificationHub(hub *services.AdminNotificationHub) {
 	awh.notificationHub = hub
 }
+
+// ScreenApplicant records an applicant's screening results; every applicant
+// must be screened before their application can be approved
+func (awh *ApplicationWorkflowHandlers) ScreenApplicant(c *gin.Context) {
+	applicantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid applicant ID",
+		})
+		return
+	}
+
+	var request services.ApplicantScreening
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	screenedBy := c.GetString("user_email")
+	if screenedBy == "" {
+		screenedBy = "system"
+	}
+
+	applicant, err := awh.service.ScreenApplicant(uint(applicantID), request, screenedBy)
+	if err != nil {
+		awh.respondApplicationError(c, err, "Failed to screen applicant")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": fmt.Sprintf("%s screened", applicant.ApplicantName),
+		"data": gin.H{
+			"applicant": applicant,
+		},
+	})
+}
+
+// GetApplicationHistory returns an application's status transitions, oldest
+// first, with the statuses it may move to next
+func (awh *ApplicationWorkflowHandlers) GetApplicationHistory(c *gin.Context) {
+	applicationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid application ID",
+		})
+		return
+	}
+
+	history, err := awh.service.GetStatusHistory(uint(applicationID))
+	if err != nil {
+		awh.respondApplicationError(c, err, "Failed to fetch application history")
+		return
+	}
+
+	var application models.ApplicationNumber
+	awh.db.Select("id", "status").First(&application, uint(applicationID))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"application_id":      application.ID,
+			"status":              application.Status,
+			"allowed_transitions": models.AllowedApplicationTransitions(application.Status),
+			"history":             history,
+		},
+	})
+}
+
+// notifyStatusChange announces the start of screening and every decision to admins
+func (awh *ApplicationWorkflowHandlers) notifyStatusChange(transition *services.ApplicationTransition) {
+	if awh.notificationHub == nil {
+		return
+	}
+	switch transition.Log.NewStatus {
+	case models.AppStatusReview, models.AppStatusApproved, models.AppStatusDenied, models.AppStatusCancelled:
+	default:
+		return
+	}
+
+	var propertyGroup models.PropertyApplicationGroup
+	awh.db.First(&propertyGroup, transition.Application.PropertyApplicationGroupID)
+	awh.notificationHub.SendApplicationStatusAlert(transition.Application, propertyGroup.PropertyAddress, transition.Log)
+}
+
+// respondApplicationError maps application workflow errors to responses
+func (awh *ApplicationWorkflowHandlers) respondApplicationError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrApplicationNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Application not found",
+		})
+	case errors.Is(err, services.ErrApplicantNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Applicant not found",
+		})
+	case errors.Is(err, models.ErrInvalidApplicationTransition):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Invalid status transition",
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrApplicationRequirementsNotMet):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Application requirements not met",
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrApplicationDecided), errors.Is(err, services.ErrApplicationStatusChanged):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   fallback,
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fallback,
+		})
+	}
+}
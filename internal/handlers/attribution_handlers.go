@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AttributionHandlers reports which lead sources and campaigns drive conversions
+type AttributionHandlers struct {
+	attribution *services.AttributionService
+}
+
+// NewAttributionHandlers creates attribution handlers
+func NewAttributionHandlers(attribution *services.AttributionService) *AttributionHandlers {
+	return &AttributionHandlers{attribution: attribution}
+}
+
+// GetAttribution handles GET /api/v1/analytics/attribution, crediting
+// conversions over the last ?days= days (default 90) to sources and campaigns
+// by ?model=first_touch, last_touch or linear (default)
+func (h *AttributionHandlers) GetAttribution(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "90"))
+	if err != nil || days < 1 || days > 730 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days", "details": "days must be between 1 and 730"})
+		return
+	}
+
+	now := time.Now()
+	report, err := h.attribution.BuildReport(c.DefaultQuery("model", services.AttributionLinear), now.AddDate(0, 0, -days), now)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownAttributionModel) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid model", "details": "model must be first_touch, last_touch or linear"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build attribution report", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "report": report})
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"chrisgross-ctrl-project/internal/models"
 	"chrisgross-ctrl-project/internal/services"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -13,6 +14,7 @@ type BehavioralEventHandler struct {
 	db                    *gorm.DB
 	eventService          *services.BehavioralEventService
 	activityBroadcaster   *services.ActivityBroadcastService
+	campaignAutomation    *services.BehavioralCampaignAutomation
 }
 
 func NewBehavioralEventHandler(db *gorm.DB, eventService *services.BehavioralEventService, activityBroadcaster *services.ActivityBroadcastService) *BehavioralEventHandler {
@@ -23,11 +25,25 @@ func NewBehavioralEventHandler(db *gorm.DB, eventService *services.BehavioralEve
 	}
 }
 
+// SetCampaignAutomation feeds newly tracked events to the campaign automations
+func (h *BehavioralEventHandler) SetCampaignAutomation(automation *services.BehavioralCampaignAutomation) {
+	h.campaignAutomation = automation
+}
+
+// triggerCampaigns runs a newly tracked event through the campaign automations in the background
+func (h *BehavioralEventHandler) triggerCampaigns(event *models.BehavioralEvent) {
+	if h.campaignAutomation != nil {
+		go h.campaignAutomation.HandleEvent(event)
+	}
+}
+
 func (h *BehavioralEventHandler) TrackPropertyView(c *gin.Context) {
 	var req struct {
 		LeadID     int64  `json:"lead_id" binding:"required"`
 		PropertyID int64  `json:"property_id" binding:"required"`
 		SessionID  string `json:"session_id" binding:"required"`
+		// ClientEventID is an optional idempotency key; retries with the same ID are not double-counted
+		ClientEventID string `json:"client_event_id" binding:"max=100"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -38,18 +54,24 @@ func (h *BehavioralEventHandler) TrackPropertyView(c *gin.Context) {
 	ipAddress := c.ClientIP()
 	userAgent := c.Request.UserAgent()
 
-	if err := h.eventService.TrackPropertyView(req.LeadID, req.PropertyID, req.SessionID, ipAddress, userAgent); err != nil {
+	event, duplicate, err := h.eventService.TrackPropertyView(req.LeadID, req.PropertyID, req.SessionID, req.ClientEventID, ipAddress, userAgent)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to track event"})
 		return
 	}
+	if duplicate {
+		c.JSON(http.StatusOK, gin.H{"success": true, "event_id": event.ID, "duplicate": true})
+		return
+	}
 
 	eventData := map[string]interface{}{
 		"property_id": req.PropertyID,
 		"action":      "view",
 	}
 	h.activityBroadcaster.BroadcastPropertyView(req.LeadID, req.PropertyID, req.SessionID, eventData)
+	h.triggerCampaigns(event)
 
-	c.JSON(http.StatusOK, gin.H{"success": true})
+	c.JSON(http.StatusOK, gin.H{"success": true, "event_id": event.ID, "duplicate": false})
 }
 
 func (h *BehavioralEventHandler) TrackPropertySave(c *gin.Context) {
@@ -57,6 +79,7 @@ func (h *BehavioralEventHandler) TrackPropertySave(c *gin.Context) {
 		LeadID     int64  `json:"lead_id" binding:"required"`
 		PropertyID int64  `json:"property_id" binding:"required"`
 		SessionID  string `json:"session_id" binding:"required"`
+		ClientEventID string `json:"client_event_id" binding:"max=100"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -67,18 +90,24 @@ func (h *BehavioralEventHandler) TrackPropertySave(c *gin.Context) {
 	ipAddress := c.ClientIP()
 	userAgent := c.Request.UserAgent()
 
-	if err := h.eventService.TrackPropertySave(req.LeadID, req.PropertyID, req.SessionID, ipAddress, userAgent); err != nil {
+	event, duplicate, err := h.eventService.TrackPropertySave(req.LeadID, req.PropertyID, req.SessionID, req.ClientEventID, ipAddress, userAgent)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to track event"})
 		return
 	}
+	if duplicate {
+		c.JSON(http.StatusOK, gin.H{"success": true, "event_id": event.ID, "duplicate": true})
+		return
+	}
 
 	eventData := map[string]interface{}{
 		"property_id": req.PropertyID,
 		"action":      "save",
 	}
 	h.activityBroadcaster.BroadcastPropertySave(req.LeadID, req.PropertyID, req.SessionID, eventData)
+	h.triggerCampaigns(event)
 
-	c.JSON(http.StatusOK, gin.H{"success": true})
+	c.JSON(http.StatusOK, gin.H{"success": true, "event_id": event.ID, "duplicate": false})
 }
 
 func (h *BehavioralEventHandler) TrackInquiry(c *gin.Context) {
@@ -87,6 +116,7 @@ func (h *BehavioralEventHandler) TrackInquiry(c *gin.Context) {
 		PropertyID   *int64  `json:"property_id"`
 		InquiryType  string  `json:"inquiry_type" binding:"required"`
 		SessionID    string  `json:"session_id" binding:"required"`
+		ClientEventID string `json:"client_event_id" binding:"max=100"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -97,10 +127,15 @@ func (h *BehavioralEventHandler) TrackInquiry(c *gin.Context) {
 	ipAddress := c.ClientIP()
 	userAgent := c.Request.UserAgent()
 
-	if err := h.eventService.TrackInquiry(req.LeadID, req.PropertyID, req.InquiryType, req.SessionID, ipAddress, userAgent); err != nil {
+	event, duplicate, err := h.eventService.TrackInquiry(req.LeadID, req.PropertyID, req.InquiryType, req.SessionID, req.ClientEventID, ipAddress, userAgent)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to track event"})
 		return
 	}
+	if duplicate {
+		c.JSON(http.StatusOK, gin.H{"success": true, "event_id": event.ID, "duplicate": true})
+		return
+	}
 
 	eventData := map[string]interface{}{
 		"inquiry_type": req.InquiryType,
@@ -110,8 +145,9 @@ func (h *BehavioralEventHandler) TrackInquiry(c *gin.Context) {
 		eventData["property_id"] = *req.PropertyID
 	}
 	h.activityBroadcaster.BroadcastInquiry(req.LeadID, req.PropertyID, req.InquiryType, req.SessionID, eventData)
+	h.triggerCampaigns(event)
 
-	c.JSON(http.StatusOK, gin.H{"success": true})
+	c.JSON(http.StatusOK, gin.H{"success": true, "event_id": event.ID, "duplicate": false})
 }
 
 func (h *BehavioralEventHandler) TrackSearch(c *gin.Context) {
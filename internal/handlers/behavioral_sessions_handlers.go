@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"chrisgross-ctrl-project/internal/models"
@@ -11,12 +12,61 @@ import (
 	"gorm.io/gorm"
 )
 
+// activeSessionWindow is how recently a session must have started to count as active
+const activeSessionWindow = 15 * time.Minute
+
 type BehavioralSessionsHandler struct {
 	db *gorm.DB
+
+	// Cached active session count, recomputed only when sessions change
+	// or the oldest counted session ages out of the window
+	activeCountMutex  sync.Mutex
+	activeCount       int
+	activeCountDirty  bool
+	activeCountExpiry time.Time
 }
 
 func NewBehavioralSessionsHandler(db *gorm.DB) *BehavioralSessionsHandler {
-	return &BehavioralSessionsHandler{db: db}
+	return &BehavioralSessionsHandler{db: db, activeCountDirty: true}
+}
+
+// MarkSessionsChanged invalidates the cached active session count
+func (h *BehavioralSessionsHandler) MarkSessionsChanged() {
+	h.activeCountMutex.Lock()
+	h.activeCountDirty = true
+	h.activeCountMutex.Unlock()
+}
+
+// ActiveSessionCount returns the number of open sessions started within the active window
+func (h *BehavioralSessionsHandler) ActiveSessionCount() int {
+	h.activeCountMutex.Lock()
+	defer h.activeCountMutex.Unlock()
+
+	now := time.Now()
+	if !h.activeCountDirty && (h.activeCountExpiry.IsZero() || now.Before(h.activeCountExpiry)) {
+		return h.activeCount
+	}
+
+	cutoff := now.Add(-activeSessionWindow)
+	query := h.db.Model(&models.BehavioralSession{}).Where("end_time IS NULL AND start_time >= ?", cutoff)
+
+	var count int64
+	if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return h.activeCount
+	}
+
+	// The count next changes on its own when the oldest active session leaves the window
+	h.activeCountExpiry = time.Time{}
+	if count > 0 {
+		var oldest models.BehavioralSession
+		if err := query.Session(&gorm.Session{}).Select("start_time").Order("start_time ASC").Take(&oldest).Error; err == nil {
+			h.activeCountExpiry = oldest.StartTime.Add(activeSessionWindow)
+		}
+	}
+
+	h.activeCount = int(count)
+	h.activeCountDirty = false
+	return h.activeCount
 }
 
 // ActiveSessionResponse represents the response for active sessions
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -11,11 +13,20 @@ import (
 )
 
 type BulkOperationsHandler struct {
-	db *gorm.DB
+	db        *gorm.DB
+	leadsBulk *services.LeadBulkService
 }
 
 func NewBulkOperationsHandler(db *gorm.DB) *BulkOperationsHandler {
-	return &BulkOperationsHandler{db: db}
+	return &BulkOperationsHandler{
+		db:        db,
+		leadsBulk: services.NewLeadBulkService(db),
+	}
+}
+
+// SetAgentScoping limits lead bulk operations to the caller's own leads
+func (h *BulkOperationsHandler) SetAgentScoping(enabled bool) {
+	h.leadsBulk.SetAgentScoping(enabled)
 }
 
 type BulkRequest struct {
@@ -25,6 +36,7 @@ type BulkRequest struct {
 type BulkLeadStatusRequest struct {
 	IDs    []int64 `json:"ids" binding:"required,min=1"`
 	Status string  `json:"status" binding:"required"`
+	DryRun bool    `json:"dry_run"`
 }
 
 type BulkLeadAssignRequest struct {
@@ -65,6 +77,9 @@ type BulkPropertyPriceRequest struct {
 	Amount     float64 `json:"amount" binding:"required"`
 }
 
+// BulkUpdateLeadStatus moves many leads to one status in a single transaction
+// and reports the outcome per ID
+// POST /api/v1/leads/bulk/status
 func (h *BulkOperationsHandler) BulkUpdateLeadStatus(c *gin.Context) {
 	var req BulkLeadStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -72,35 +87,35 @@ func (h *BulkOperationsHandler) BulkUpdateLeadStatus(c *gin.Context) {
 		return
 	}
 
-	validStatuses := []string{"new", "contacted", "qualified", "negotiating", "closed", "lost", "archived"}
-	isValid := false
-	for _, status := range validStatuses {
-		if req.Status == status {
-			isValid = true
-			break
-		}
-	}
-	if !isValid {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
-		return
+	var actor *models.AdminUser
+	if value, exists := c.Get("user"); exists {
+		actor, _ = value.(*models.AdminUser)
 	}
 
-	result := h.db.Model(&models.Lead{}).
-		Where("id IN ?", req.IDs).
-		Updates(map[string]interface{}{
-			"status":     req.Status,
-			"updated_at": time.Now(),
-		})
-
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update leads", "details": result.Error.Error()})
+	result, err := h.leadsBulk.UpdateStatus(actor, req.IDs, req.Status, req.DryRun)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrBulkBatchTooLarge), errors.Is(err, services.ErrInvalidLeadStatus):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		case errors.Is(err, services.ErrMixedLeadOwnership):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Mixed lead ownership", "details": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update leads", "details": err.Error()})
+		}
 		return
 	}
 
+	message := fmt.Sprintf("Updated %d leads to status: %s", result.Updated, req.Status)
+	if req.DryRun {
+		message = fmt.Sprintf("Dry run: %d leads would be updated to status: %s", result.Updated, req.Status)
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"updated": result.RowsAffected,
-		"message": fmt.Sprintf("Updated %d leads to status: %s", result.RowsAffected, req.Status),
+		"updated": result.Updated,
+		"failed":  result.Failed,
+		"dry_run": result.DryRun,
+		"results": result.Results,
+		"message": message,
 	})
 }
 
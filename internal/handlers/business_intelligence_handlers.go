@@ -176,6 +176,41 @@ func (bih *BusinessIntelligenceHandlers) GetBookingTrends(c *gin.Context) {
 	})
 }
 
+// GetCohortRetention handles GET /api/v1/analytics/cohorts, a heatmap-ready
+// matrix of lead cohorts by acquisition ?granularity=week|month against the
+// share engaged or converted (?metric=engagement|conversion) in each later period
+func (bih *BusinessIntelligenceHandlers) GetCohortRetention(c *gin.Context) {
+	granularity := c.DefaultQuery("granularity", services.CohortWeekly)
+	if granularity != services.CohortWeekly && granularity != services.CohortMonthly {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid granularity", "details": "granularity must be week or month"})
+		return
+	}
+	metric := c.DefaultQuery("metric", services.CohortMetricEngagement)
+	if metric != services.CohortMetricEngagement && metric != services.CohortMetricConversion {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid metric", "details": "metric must be engagement or conversion"})
+		return
+	}
+	defaultCohorts := "8"
+	if granularity == services.CohortMonthly {
+		defaultCohorts = "6"
+	}
+	cohorts, err := strconv.Atoi(c.DefaultQuery("cohorts", defaultCohorts))
+	if err != nil || cohorts < 1 || cohorts > 52 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cohorts", "details": "cohorts must be between 1 and 52"})
+		return
+	}
+
+	retention, err := bih.biService.GetCohortRetention(granularity, metric, cohorts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to build cohort retention",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "retention": retention})
+}
+
 // GetLeadAnalytics returns lead management analytics
 func (bih *BusinessIntelligenceHandlers) GetLeadAnalytics(c *gin.Context) {
 	metrics, err := bih.biService.GetDashboardMetrics()
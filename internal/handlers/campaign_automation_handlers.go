@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// CampaignAutomationHandlers controls the campaign automations fed by behavioral events
+type CampaignAutomationHandlers struct {
+	automation *services.BehavioralCampaignAutomation
+}
+
+// NewCampaignAutomationHandlers creates campaign automation handlers
+func NewCampaignAutomationHandlers(automation *services.BehavioralCampaignAutomation) *CampaignAutomationHandlers {
+	return &CampaignAutomationHandlers{automation: automation}
+}
+
+// GetStatus handles GET /api/v1/campaign-automation/status, listing each
+// automation's triggers and the campaigns recently fired
+func (h *CampaignAutomationHandlers) GetStatus(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"automations":    h.automation.Status(),
+		"recent_firings": h.automation.RecentFirings(limit),
+	})
+}
+
+// EnableAutomation handles POST /api/v1/campaign-automation/:name/enable
+func (h *CampaignAutomationHandlers) EnableAutomation(c *gin.Context) {
+	h.setEnabled(c, true)
+}
+
+// DisableAutomation handles POST /api/v1/campaign-automation/:name/disable
+func (h *CampaignAutomationHandlers) DisableAutomation(c *gin.Context) {
+	h.setEnabled(c, false)
+}
+
+func (h *CampaignAutomationHandlers) setEnabled(c *gin.Context, enabled bool) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	if !models.IsTeamAdminRole(admin.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Changing campaign automation requires a team admin"})
+		return
+	}
+
+	name := c.Param("name")
+	if err := h.automation.SetEnabled(name, enabled); err != nil {
+		if errors.Is(err, services.ErrUnknownCampaignAutomation) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Campaign automation not found", "details": name})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update campaign automation", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "automation": name, "enabled": enabled})
+}
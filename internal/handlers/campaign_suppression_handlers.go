@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+// suppressionOptions turns the automatic approval and closing pipeline
+// exclusions on unless the request explicitly turns them off
+func suppressionOptions(excludeOpenApprovals, excludeActivePipelines *bool) services.CampaignSuppressionOptions {
+	return services.CampaignSuppressionOptions{
+		ExcludeOpenApprovals:   excludeOpenApprovals == nil || *excludeOpenApprovals,
+		ExcludeActivePipelines: excludeActivePipelines == nil || *excludeActivePipelines,
+	}
+}
+
+// GetCampaignSuppressions lists active campaign suppressions, optionally for one campaign
+// GET /api/v1/reengagement/campaigns/suppressions?campaign_name=
+func (h *LeadReengagementHandler) GetCampaignSuppressions(c *gin.Context) {
+	query := h.db.Where("active = ?", true)
+	if name := strings.TrimSpace(c.Query("campaign_name")); name != "" {
+		query = query.Where("campaign_name = ?", name)
+	}
+
+	suppressions := []models.CampaignSuppression{}
+	if err := query.Order("created_at DESC").Find(&suppressions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve campaign suppressions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"suppressions": suppressions,
+		"total":        len(suppressions),
+	})
+}
+
+// CreateCampaignSuppression excludes a set of leads or a saved query's matches from a campaign
+// POST /api/v1/reengagement/campaigns/suppressions
+func (h *LeadReengagementHandler) CreateCampaignSuppression(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		CampaignName string                          `json:"campaign_name" binding:"required,max=200"`
+		Reason       string                          `json:"reason" binding:"max=255"`
+		LeadIDs      []uint                          `json:"lead_ids"`
+		Query        models.CampaignSuppressionQuery `json:"query"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	suppression := models.CampaignSuppression{
+		CampaignName: strings.TrimSpace(request.CampaignName),
+		Reason:       request.Reason,
+		LeadIDs:      request.LeadIDs,
+		Query:        request.Query,
+		Active:       true,
+		CreatedBy:    admin.Username,
+	}
+	if err := suppression.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid campaign suppression",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.db.Create(&suppression).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create campaign suppression",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Campaign suppression created successfully",
+		"suppression": suppression,
+	})
+}
+
+// DeleteCampaignSuppression deactivates a campaign suppression
+// DELETE /api/v1/reengagement/campaigns/suppressions/:id
+func (h *LeadReengagementHandler) DeleteCampaignSuppression(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid campaign suppression ID",
+		})
+		return
+	}
+
+	result := h.db.Model(&models.CampaignSuppression{}).Where("id = ? AND active = ?", id, true).Update("active", false)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete campaign suppression",
+			"details": result.Error.Error(),
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Campaign suppression not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Campaign suppression deleted successfully",
+	})
+}
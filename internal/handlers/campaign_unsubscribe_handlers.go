@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+// HandleTokenUnsubscribe opts a re-engagement lead out using the signed token
+// from a campaign email. GET serves the link in the email body; POST is the
+// RFC 8058 one-click request mail clients send from the List-Unsubscribe header.
+// GET|POST /unsubscribe?token=
+func (u *UnsubscribeHandlers) HandleTokenUnsubscribe(c *gin.Context) {
+	oneClick := c.Request.Method == http.MethodPost
+
+	var token services.UnsubscribeToken
+	err := services.ErrInvalidUnsubscribeToken
+	if u.tracker != nil {
+		token, err = u.tracker.ParseUnsubscribeToken(c.Query("token"))
+	}
+	if err != nil {
+		u.respondTokenUnsubscribeError(c, oneClick, http.StatusBadRequest, "Invalid unsubscribe link. Please contact us directly.")
+		return
+	}
+
+	var lead models.LeadReengagement
+	if err := u.db.Unscoped().First(&lead, "id = ?", token.LeadID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.respondTokenUnsubscribeError(c, oneClick, http.StatusNotFound, "We could not find your subscription. Please contact us directly.")
+		} else {
+			u.respondTokenUnsubscribeError(c, oneClick, http.StatusInternalServerError, "Unable to process your unsubscribe request. Please try again or contact us directly.")
+		}
+		return
+	}
+
+	email := string(lead.Email)
+	if u.encryptionManager != nil {
+		if decrypted, err := u.encryptionManager.Decrypt(lead.Email); err == nil {
+			email = decrypted
+		}
+	}
+
+	source := "email_link"
+	if oneClick {
+		source = "one_click"
+	}
+
+	err = u.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.LeadReengagement{}).Unscoped().Where("id = ?", lead.ID).Updates(map[string]interface{}{
+			"previous_unsubscribe": true,
+			"consent_status":       models.ConsentRevoked,
+			"opted_in":             false,
+		}).Error; err != nil {
+			return err
+		}
+
+		if token.ExecutionID != 0 {
+			if err := tx.Model(&models.CampaignExecution{}).
+				Where("id = ? AND lead_reengagement_id = ?", token.ExecutionID, lead.ID).
+				Updates(map[string]interface{}{"responded": true, "response_type": "opt_out"}).Error; err != nil {
+				return err
+			}
+		}
+
+		if email == "" {
+			return nil
+		}
+		var existing int64
+		tx.Model(&UnsubscribeRecord{}).
+			Where("LOWER(email) = ? AND unsubscribe_type = ? AND is_active = ?", strings.ToLower(email), "all", true).
+			Count(&existing)
+		if existing > 0 {
+			return nil
+		}
+		return tx.Create(&UnsubscribeRecord{
+			Email:           email,
+			UnsubscribeID:   newUnsubscribeID(),
+			UnsubscribeType: "all",
+			IPAddress:       c.ClientIP(),
+			UserAgent:       c.GetHeader("User-Agent"),
+			UnsubscribeDate: time.Now(),
+			OriginalEmailID: strconv.FormatUint(uint64(token.ExecutionID), 10),
+			Source:          source,
+			IsActive:        true,
+		}).Error
+	})
+	if err != nil {
+		log.Printf("❌ Failed to record unsubscribe for lead %d: %v", lead.ID, err)
+		u.respondTokenUnsubscribeError(c, oneClick, http.StatusInternalServerError, "Unable to process your unsubscribe request. Please try again or contact us directly.")
+		return
+	}
+
+	log.Printf("🚫 Lead %d unsubscribed via %s", lead.ID, source)
+
+	if oneClick {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Successfully unsubscribed",
+		})
+		return
+	}
+	c.HTML(http.StatusOK, "consumer/pages/unsubscribe_success.html", gin.H{
+		"Title":           "Successfully Unsubscribed",
+		"Email":           maskEmailAddress(email),
+		"UnsubscribeType": u.getUnsubscribeTypeDisplay("all"),
+		"Message":         u.getUnsubscribeSuccessMessage("all"),
+		"ShowResubscribe": false,
+	})
+}
+
+func (u *UnsubscribeHandlers) respondTokenUnsubscribeError(c *gin.Context, oneClick bool, status int, message string) {
+	if oneClick {
+		c.JSON(status, gin.H{
+			"error": message,
+		})
+		return
+	}
+	c.HTML(status, "consumer/pages/unsubscribe_error.html", gin.H{
+		"Title": "Unsubscribe Error",
+		"Error": message,
+	})
+}
+
+// newUnsubscribeID returns a random identifier for an unsubscribe record
+func newUnsubscribeID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// maskEmailAddress hides most of the local part, so a forwarded unsubscribe
+// link does not reveal the full address (jane@example.com -> j***@example.com)
+func maskEmailAddress(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return ""
+	}
+	return email[:1] + "***" + email[at:]
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"os"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -67,6 +68,10 @@ func (cph *CentralPropertyHandler) CreateOrUpdateProperty(w http.ResponseWriter,
 	// Create or update property
 	property, err := cph.stateManager.CreateOrUpdateProperty(updateReq)
 	if err != nil {
+		if errors.Is(err, services.ErrPropertyDeleted) {
+			cph.sendErrorResponse(w, http.StatusConflict, "PROPERTY_DELETED", "Property is deleted; restore it before syncing")
+			return
+		}
 		log.Printf("Error creating/updating property: %v", err)
 		cph.sendErrorResponse(w, http.StatusInternalServerError, "PROPERTY_ERROR", fmt.Sprintf("Failed to process property: %v", err))
 		return
@@ -235,6 +240,10 @@ func (cph *CentralPropertyHandler) UpdatePropertyStatus(w http.ResponseWriter, r
 
 	// Update property status
 	if err := cph.stateManager.UpdatePropertyStatus(mlsID, statusReq.Status, statusReq.Source); err != nil {
+		if errors.Is(err, services.ErrPropertyDeleted) {
+			cph.sendErrorResponse(w, http.StatusConflict, "PROPERTY_DELETED", "Property is deleted; restore it before syncing")
+			return
+		}
 		log.Printf("Error updating property status: %v", err)
 		cph.sendErrorResponse(w, http.StatusInternalServerError, "STATUS_UPDATE_ERROR", fmt.Sprintf("Failed to update status: %v", err))
 		return
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -90,6 +91,13 @@ func (cpsh *CentralPropertySyncHandlers) CreateOrUpdateProperty(c *gin.Context)
 
 	property, err := cpsh.centralStateManager.CreateOrUpdateProperty(request)
 	if err != nil {
+		if errors.Is(err, services.ErrPropertyDeleted) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Property is deleted",
+				"details": "Restore the property before syncing it",
+			})
+			return
+		}
 		log.Printf("❌ Failed to create/update property: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to process property",
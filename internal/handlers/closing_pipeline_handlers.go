@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -10,6 +11,7 @@ import (
 	"gorm.io/gorm"
 	"chrisgross-ctrl-project/internal/models"
 	"chrisgross-ctrl-project/internal/security"
+	"chrisgross-ctrl-project/internal/services"
 	"chrisgross-ctrl-project/internal/utils"
 )
 
@@ -17,8 +19,13 @@ import (
 type ClosingPipelineHandlers struct {
 	db                *gorm.DB
 	encryptionManager *security.EncryptionManager
+	notificationHub   *services.AdminNotificationHub
 }
 
+// errClosingStageChanged is returned when another request moved the closing
+// to a different stage between reading and updating it
+var errClosingStageChanged = errors.New("closing pipeline stage changed concurrently")
+
 // NewClosingPipelineHandlers creates new closing pipeline handlers
 func NewClosingPipelineHandlers(db *gorm.DB) *ClosingPipelineHandlers {
 	encryptionManager, err := security.NewEncryptionManager(db)
@@ -31,6 +38,11 @@ func NewClosingPipelineHandlers(db *gorm.DB) *ClosingPipelineHandlers {
 	}
 }
 
+// SetNotificationHub sets the hub that announces stage transitions to admins
+func (h *ClosingPipelineHandlers) SetNotificationHub(hub *services.AdminNotificationHub) {
+	h.notificationHub = hub
+}
+
 // GetClosingPipelines retrieves all closing pipeline items
 // GET /api/v1/admin/closing-pipeline
 func (h *ClosingPipelineHandlers) GetClosingPipelines(c *gin.Context) {
@@ -57,9 +69,11 @@ func (h *ClosingPipelineHandlers) GetClosingPipelines(c *gin.Context) {
 	})
 }
 
-// UpdatePipelineStage updates the stage of a closing pipeline item
-// PUT /api/v1/admin/closing-pipeline/:id/stage
-func (h *ClosingPipelineHandlers) UpdatePipelineStage(c *gin.Context) {
+// PutClosingPipelineStatus moves a closing pipeline item to a new stage,
+// rejecting transitions outside the allowed stage order, and records the
+// transition in its stage history
+// PUT /api/v1/admin/closing-pipeline/:id/status
+func (h *ClosingPipelineHandlers) PutClosingPipelineStatus(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -77,42 +91,102 @@ func (h *ClosingPipelineHandlers) UpdatePipelineStage(c *gin.Context) {
 		return
 	}
 
-	var pipeline models.ClosingPipeline
-	if err := h.db.First(&pipeline, uint(id)).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusNotFound, "Pipeline item not found", err)
+	if !models.IsClosingStage(request.NewStatus) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid status value", nil)
 		return
 	}
 
-	// Validate status
-	validStatuses := []string{"pending", "in_progress", "ready", "completed"}
-	isValid := false
-	for _, status := range validStatuses {
-		if status == request.NewStatus {
-			isValid = true
-			break
+	var pipeline models.ClosingPipeline
+	var history models.ClosingPipelineStageHistory
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&pipeline, uint(id)).Error; err != nil {
+			return err
+		}
+		if err := models.ValidateClosingStageTransition(pipeline.Status, request.NewStatus); err != nil {
+			return err
+		}
+
+		// Guard on the current status so concurrent updates cannot both apply
+		result := tx.Model(&models.ClosingPipeline{}).
+			Where("id = ? AND status = ?", pipeline.ID, pipeline.Status).
+			Update("status", request.NewStatus)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errClosingStageChanged
+		}
+
+		history = models.ClosingPipelineStageHistory{
+			ClosingPipelineID: pipeline.ID,
+			FromStatus:        pipeline.Status,
+			ToStatus:          request.NewStatus,
+			Actor:             closingPipelineActor(c),
+			Notes:             request.Notes,
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return err
+		}
+		pipeline.Status = request.NewStatus
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "Pipeline item not found", err)
+		case errors.Is(err, errClosingStageChanged):
+			utils.ErrorResponse(c, http.StatusConflict, "Pipeline stage changed during update, please retry", nil)
+		case errors.Is(err, models.ErrInvalidClosingStageTransition):
+			c.JSON(http.StatusConflict, gin.H{
+				"success":             false,
+				"error":               "Invalid stage transition",
+				"details":             err.Error(),
+				"current_status":      pipeline.Status,
+				"allowed_transitions": models.AllowedClosingStageTransitions(pipeline.Status),
+			})
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update pipeline stage", err)
 		}
-	}
-	if !isValid {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid status value", nil)
 		return
 	}
 
-	previousStatus := pipeline.Status
-	pipeline.Status = request.NewStatus
+	if h.notificationHub != nil {
+		h.notificationHub.SendClosingStageAlert(&history, pipeline.PropertyAddress)
+	}
 
-	if err := h.db.Save(&pipeline).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update pipeline stage", err)
+	stageHistory, err := h.stageHistory(pipeline.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch stage history", err)
 		return
 	}
 
 	utils.SuccessResponse(c, gin.H{
 		"message":         "Pipeline status updated successfully",
-		"previous_status": previousStatus,
-		"new_status":      request.NewStatus,
+		"previous_status": history.FromStatus,
+		"new_status":      history.ToStatus,
 		"pipeline":        pipeline,
+		"history":         stageHistory,
 	})
 }
 
+// stageHistory returns a closing's stage transitions, oldest first
+func (h *ClosingPipelineHandlers) stageHistory(pipelineID uint) ([]models.ClosingPipelineStageHistory, error) {
+	history := []models.ClosingPipelineStageHistory{}
+	err := h.db.Where("closing_pipeline_id = ?", pipelineID).Order("created_at ASC, id ASC").Find(&history).Error
+	return history, err
+}
+
+// closingPipelineActor names who made a change, falling back to "api" for
+// unauthenticated requests
+func closingPipelineActor(c *gin.Context) string {
+	if value, ok := c.Get("user"); ok {
+		if admin, ok := value.(*models.AdminUser); ok && admin != nil {
+			return admin.Username
+		}
+	}
+	return "api"
+}
+
 // GetPipelineItem retrieves a single closing pipeline item
 // GET /api/v1/admin/closing-pipeline/:id
 func (h *ClosingPipelineHandlers) GetPipelineItem(c *gin.Context) {
@@ -133,8 +207,15 @@ func (h *ClosingPipelineHandlers) GetPipelineItem(c *gin.Context) {
 		return
 	}
 
+	history, err := h.stageHistory(pipeline.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch stage history", err)
+		return
+	}
+
 	utils.SuccessResponse(c, gin.H{
 		"pipeline": pipeline,
+		"history":  history,
 	})
 }
 
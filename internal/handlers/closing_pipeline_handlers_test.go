@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+func TestPutClosingPipelineStatus_EnforcesStageOrder(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.ClosingPipeline{}, &models.ClosingPipelineStageHistory{}, &models.AdminNotification{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	pipeline := models.ClosingPipeline{PropertyAddress: "123 Main St", SoldDate: time.Now(), Status: models.ClosingStagePending}
+	if err := db.Create(&pipeline).Error; err != nil {
+		t.Fatalf("Failed to create pipeline: %v", err)
+	}
+
+	handler := &ClosingPipelineHandlers{db: db}
+	handler.SetNotificationHub(services.NewAdminNotificationHub(db))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &models.AdminUser{ID: "admin-1", Username: "closer"})
+	})
+	router.PUT("/closing-pipeline/:id/status", handler.PutClosingPipelineStatus)
+
+	put := func(status string) (int, map[string]interface{}) {
+		payload, _ := json.Marshal(map[string]string{"new_status": status})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/closing-pipeline/1/status", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		var body map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body
+	}
+
+	// Skipping straight to completed is an illegal jump
+	if code, body := put(models.ClosingStageCompleted); code != http.StatusConflict {
+		t.Fatalf("Expected 409 for pending -> completed, got %d: %v", code, body)
+	}
+	if code, _ := put("closed"); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown stage, got %d", code)
+	}
+
+	for _, stage := range []string{models.ClosingStageInProgress, models.ClosingStageReady, models.ClosingStageCompleted} {
+		code, body := put(stage)
+		if code != http.StatusOK {
+			t.Fatalf("Expected 200 moving to %s, got %d: %v", stage, code, body)
+		}
+		if stage == models.ClosingStageCompleted {
+			data := body["data"].(map[string]interface{})
+			history := data["history"].([]interface{})
+			if len(history) != 3 {
+				t.Fatalf("Expected 3 history entries, got %d", len(history))
+			}
+			last := history[2].(map[string]interface{})
+			if last["from_status"] != models.ClosingStageReady || last["to_status"] != models.ClosingStageCompleted || last["actor"] != "closer" {
+				t.Errorf("Unexpected last history entry %v", last)
+			}
+		}
+	}
+
+	// Completed closings are final
+	if code, _ := put(models.ClosingStageReady); code != http.StatusConflict {
+		t.Errorf("Expected 409 moving a completed closing back, got %d", code)
+	}
+
+	var notifications int64
+	db.Model(&models.AdminNotification{}).Where("type = ?", "closing_stage_changed").Count(&notifications)
+	if notifications != 3 {
+		t.Errorf("Expected 3 stage notifications, got %d", notifications)
+	}
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"chrisgross-ctrl-project/internal/models"
@@ -20,6 +23,7 @@ type CommandCenterHandlers struct {
 	insightGenerator      *services.InsightGeneratorService
 	propertyMatcher       *services.PropertyMatchingService
 	fubIntegrationService *services.BehavioralFUBIntegrationService
+	tasks                 *services.CommandCenterTaskService
 }
 
 func NewCommandCenterHandlers(
@@ -40,6 +44,11 @@ func NewCommandCenterHandlers(
 	}
 }
 
+// SetTaskService enables the command center task endpoints
+func (h *CommandCenterHandlers) SetTaskService(tasks *services.CommandCenterTaskService) {
+	h.tasks = tasks
+}
+
 // CommandCenterItem represents an actionable item in the command center
 type CommandCenterItem struct {
 	ID         string                 `json:"id"`
@@ -577,6 +586,350 @@ func (h *CommandCenterHandlers) GetStats(c *gin.Context) {
 	utils.SuccessResponse(c, stats)
 }
 
+// GetTasks handles GET /api/v1/command-center/tasks. Query parameters:
+// assigned_to (an admin ID, or "me"), status (open or completed; default
+// open), include_snoozed=true and limit.
+func (h *CommandCenterHandlers) GetTasks(c *gin.Context) {
+	admin, ok := commandCenterAdmin(c)
+	if !ok {
+		return
+	}
+
+	filter := services.TaskFilter{
+		AssignedTo:     c.Query("assigned_to"),
+		Status:         c.DefaultQuery("status", models.TaskStatusOpen),
+		IncludeSnoozed: c.Query("include_snoozed") == "true",
+		Limit:          100,
+	}
+	if filter.AssignedTo == "me" {
+		filter.AssignedTo = admin.ID
+	}
+	if filter.Status != models.TaskStatusOpen && filter.Status != models.TaskStatusCompleted {
+		utils.ErrorResponse(c, http.StatusBadRequest, "status must be open or completed", nil)
+		return
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > 500 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "limit must be between 1 and 500", err)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	tasks, err := h.tasks.ListTasks(filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to load tasks", err)
+		return
+	}
+	utils.SuccessResponse(c, gin.H{"tasks": tasks, "count": len(tasks)})
+}
+
+// CreateTask handles POST /api/v1/command-center/tasks, creating a task by
+// hand. Without due_at it is due at the next business-hours slot.
+func (h *CommandCenterHandlers) CreateTask(c *gin.Context) {
+	admin, ok := commandCenterAdmin(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Title       string     `json:"title" binding:"required"`
+		Description string     `json:"description"`
+		Type        string     `json:"type"`
+		LeadID      *int64     `json:"lead_id"`
+		AssignedTo  string     `json:"assigned_to"`
+		Priority    int        `json:"priority"`
+		DueAt       *time.Time `json:"due_at"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request", err)
+		return
+	}
+	if req.Priority < 0 || req.Priority > 10 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "priority must be between 0 and 10", nil)
+		return
+	}
+
+	task := &models.Task{
+		Title:       req.Title,
+		Description: req.Description,
+		Type:        req.Type,
+		LeadID:      req.LeadID,
+		AssignedTo:  req.AssignedTo,
+		Priority:    req.Priority,
+		Confidence:  1,
+		CreatedBy:   admin.ID,
+	}
+	if task.Type == "" {
+		task.Type = "follow_up"
+	}
+	if req.DueAt != nil {
+		task.DueAt = *req.DueAt
+	}
+	if err := h.tasks.CreateTask(task); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to create task", err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": task})
+}
+
+// GenerateTasks handles POST /api/v1/command-center/tasks/generate, turning
+// current high-confidence insights into tasks now rather than on the next run
+func (h *CommandCenterHandlers) GenerateTasks(c *gin.Context) {
+	created, err := h.tasks.GenerateTasks()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate tasks", err)
+		return
+	}
+	utils.SuccessResponse(c, gin.H{"created": created})
+}
+
+// CompleteTask handles POST /api/v1/command-center/tasks/:id/complete
+func (h *CommandCenterHandlers) CompleteTask(c *gin.Context) {
+	admin, ok := commandCenterAdmin(c)
+	if !ok {
+		return
+	}
+	id, ok := taskIDParam(c)
+	if !ok {
+		return
+	}
+
+	task, err := h.tasks.CompleteTask(id, admin.ID)
+	if err != nil {
+		taskErrorResponse(c, "Failed to complete task", err)
+		return
+	}
+	utils.SuccessResponse(c, task)
+}
+
+// SnoozeTask handles POST /api/v1/command-center/tasks/:id/snooze with either
+// {"minutes": n} or {"until": time}
+func (h *CommandCenterHandlers) SnoozeTask(c *gin.Context) {
+	id, ok := taskIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Minutes int        `json:"minutes"`
+		Until   *time.Time `json:"until"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request", err)
+		return
+	}
+	var until time.Time
+	switch {
+	case req.Until != nil:
+		until = *req.Until
+	case req.Minutes > 0:
+		until = time.Now().Add(time.Duration(req.Minutes) * time.Minute)
+	default:
+		utils.ErrorResponse(c, http.StatusBadRequest, "minutes or until is required", nil)
+		return
+	}
+
+	task, err := h.tasks.SnoozeTask(id, until)
+	if err != nil {
+		taskErrorResponse(c, "Failed to snooze task", err)
+		return
+	}
+	utils.SuccessResponse(c, task)
+}
+
+// AssignTask handles POST /api/v1/command-center/tasks/:id/assign with
+// {"assigned_to": admin ID}; an empty ID hands the task to the whole team
+func (h *CommandCenterHandlers) AssignTask(c *gin.Context) {
+	id, ok := taskIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		AssignedTo string `json:"assigned_to"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request", err)
+		return
+	}
+
+	task, err := h.tasks.AssignTask(id, req.AssignedTo)
+	if err != nil {
+		taskErrorResponse(c, "Failed to assign task", err)
+		return
+	}
+	utils.SuccessResponse(c, task)
+}
+
+// commandCenterAdmin returns the signed-in admin, responding 401 without one
+func commandCenterAdmin(c *gin.Context) (*models.AdminUser, bool) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+		return nil, false
+	}
+	return admin, true
+}
+
+// taskIDParam parses the :id task parameter, responding 400 if it is invalid
+func taskIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID", err)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// taskErrorResponse maps task service errors to a status code
+func taskErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "Task not found", nil)
+	case errors.Is(err, services.ErrTaskNotOpen):
+		utils.ErrorResponse(c, http.StatusConflict, message, err)
+	default:
+		utils.ErrorResponse(c, http.StatusBadRequest, message, err)
+	}
+}
+
+// GetScoreExplanation handles GET /api/v1/leads/:id/score-explanation, showing
+// the factors behind a lead's behavioral score and segment
+func (h *CommandCenterHandlers) GetScoreExplanation(c *gin.Context) {
+	leadID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || leadID <= 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid lead ID", err)
+		return
+	}
+
+	var lead models.Lead
+	if err := h.db.First(&lead, leadID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Lead not found", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to load lead", err)
+		return
+	}
+
+	explanation, err := h.scoringEngine.ExplainScore(leadID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to explain score", err)
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"lead_id":     leadID,
+		"explanation": explanation,
+	})
+}
+
+// GetPropertyMatches handles GET /api/v1/leads/:id/property-matches, ranking
+// active properties against the lead's inferred preferences. Query parameters
+// min_price, max_price, min_bedrooms, max_bedrooms, city, zip (comma-separated),
+// property_type, listing_type (rental or sale), min_score, and limit override them.
+func (h *CommandCenterHandlers) GetPropertyMatches(c *gin.Context) {
+	leadID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || leadID <= 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid lead ID", err)
+		return
+	}
+
+	query, err := parseLeadMatchQuery(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid match criteria", err)
+		return
+	}
+
+	criteria, matches, err := h.propertyMatcher.RankMatchesForLead(leadID, query)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Lead not found", nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to match properties", err)
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"lead_id":  leadID,
+		"criteria": criteria,
+		"matches":  matches,
+		"count":    len(matches),
+	})
+}
+
+// parseLeadMatchQuery reads property match overrides from the query string
+func parseLeadMatchQuery(c *gin.Context) (services.LeadMatchQuery, error) {
+	query := services.LeadMatchQuery{
+		PropertyType: c.Query("property_type"),
+		Cities:       splitQueryList(c.Query("city")),
+		Zips:         splitQueryList(c.Query("zip")),
+	}
+
+	if listingType := c.Query("listing_type"); listingType != "" {
+		query.ListingType = services.NormalizeListingType(listingType)
+		if query.ListingType == "" {
+			return query, fmt.Errorf("listing_type must be rental or sale")
+		}
+	}
+
+	floats := map[string]*float64{
+		"min_price": &query.MinPrice,
+		"max_price": &query.MaxPrice,
+		"min_score": &query.MinScore,
+	}
+	for name, target := range floats {
+		if raw := c.Query(name); raw != "" {
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil || value < 0 {
+				return query, fmt.Errorf("%s must be a non-negative number", name)
+			}
+			*target = value
+		}
+	}
+
+	ints := map[string]*int{
+		"min_bedrooms": &query.MinBedrooms,
+		"max_bedrooms": &query.MaxBedrooms,
+		"limit":        &query.Limit,
+	}
+	for name, target := range ints {
+		if raw := c.Query(name); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value < 0 {
+				return query, fmt.Errorf("%s must be a non-negative integer", name)
+			}
+			*target = value
+		}
+	}
+
+	if query.MaxPrice > 0 && query.MinPrice > query.MaxPrice {
+		return query, fmt.Errorf("min_price must not exceed max_price")
+	}
+	if query.MaxBedrooms > 0 && query.MinBedrooms > query.MaxBedrooms {
+		return query, fmt.Errorf("min_bedrooms must not exceed max_bedrooms")
+	}
+	if query.MinScore > 100 {
+		return query, fmt.Errorf("min_score must be at most 100")
+	}
+	return query, nil
+}
+
+// splitQueryList splits a comma-separated query value, dropping blanks
+func splitQueryList(raw string) []string {
+	values := []string{}
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // Action execution methods
 
 func (h *CommandCenterHandlers) executeSendRecommendations(itemID string, params map[string]interface{}) map[string]interface{} {
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+// ComplianceAlertHandlers exposes persisted compliance alerts
+type ComplianceAlertHandlers struct {
+	alerts *services.AlertManager
+}
+
+// NewComplianceAlertHandlers creates new compliance alert handlers
+func NewComplianceAlertHandlers(complianceMonitoring *services.ComplianceMonitoringService) *ComplianceAlertHandlers {
+	return &ComplianceAlertHandlers{
+		alerts: complianceMonitoring.Alerts(),
+	}
+}
+
+// ListAlerts returns unresolved alerts, or all recent alerts with ?include_resolved=true
+// GET /api/v1/compliance/alerts
+func (h *ComplianceAlertHandlers) ListAlerts(c *gin.Context) {
+	includeResolved := c.Query("include_resolved") == "true"
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	alerts, err := h.alerts.ListAlerts(includeResolved, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve compliance alerts",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"alerts":  alerts,
+		"count":   len(alerts),
+	})
+}
+
+// AcknowledgeAlert marks an alert as seen
+// POST /api/v1/compliance/alerts/:id/acknowledge
+func (h *ComplianceAlertHandlers) AcknowledgeAlert(c *gin.Context) {
+	h.updateAlert(c, h.alerts.AcknowledgeAlert, "Alert acknowledged")
+}
+
+// ResolveAlert closes an alert so a new alert of the same type can be raised
+// POST /api/v1/compliance/alerts/:id/resolve
+func (h *ComplianceAlertHandlers) ResolveAlert(c *gin.Context) {
+	h.updateAlert(c, h.alerts.ResolveAlert, "Alert resolved")
+}
+
+func (h *ComplianceAlertHandlers) updateAlert(c *gin.Context, update func(alertID, by string) error, message string) {
+	actor := "admin"
+	if value, exists := c.Get("user"); exists {
+		if admin, ok := value.(*models.AdminUser); ok && admin != nil {
+			actor = admin.Username
+		}
+	}
+
+	alertID := c.Param("id")
+	if err := update(alertID, actor); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Alert not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to update alert",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	alert, err := h.alerts.GetAlert(alertID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve alert",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+		"alert":   alert,
+	})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+// ComplianceConfigHandlers exposes compliance scoring weights and reputation thresholds
+type ComplianceConfigHandlers struct {
+	monitoring *services.ComplianceMonitoringService
+}
+
+// NewComplianceConfigHandlers creates new compliance config handlers
+func NewComplianceConfigHandlers(monitoring *services.ComplianceMonitoringService) *ComplianceConfigHandlers {
+	return &ComplianceConfigHandlers{monitoring: monitoring}
+}
+
+// GetConfig returns the weights and thresholds in use
+// GET /api/v1/compliance/config
+func (h *ComplianceConfigHandlers) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"config":  h.monitoring.GetConfig(),
+	})
+}
+
+// UpdateConfig changes weights and thresholds. Omitted fields keep their current values.
+// PUT /api/v1/compliance/config
+func (h *ComplianceConfigHandlers) UpdateConfig(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	if !models.IsTeamAdminRole(admin.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Changing compliance config requires a team admin"})
+		return
+	}
+
+	config := h.monitoring.GetConfig()
+	if err := c.ShouldBindJSON(&config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err := config.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid compliance config",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	saved, err := h.monitoring.UpdateConfig(config, admin.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update compliance config",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Compliance config updated",
+		"config":  saved,
+	})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+// maxDNCImportSize caps the size of an uploaded do-not-contact list
+const maxDNCImportSize = 20 << 20
+
+// ComplianceDNCHandlers manages the do-not-contact list and its suppression log
+type ComplianceDNCHandlers struct {
+	db  *gorm.DB
+	dnc *services.DNCService
+}
+
+// NewComplianceDNCHandlers creates new do-not-contact list handlers
+func NewComplianceDNCHandlers(db *gorm.DB, encryptionManager *security.EncryptionManager) *ComplianceDNCHandlers {
+	return &ComplianceDNCHandlers{db: db, dnc: services.NewDNCService(db, encryptionManager)}
+}
+
+// ImportList adds the phone numbers and emails in a CSV (multipart "file" or
+// a text/csv body) to the do-not-contact list and flags matching leads
+// POST /api/v1/compliance/dnc/import?source=
+func (h *ComplianceDNCHandlers) ImportList(c *gin.Context) {
+	var body io.Reader
+	if file, err := c.FormFile("file"); err == nil {
+		src, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to open CSV file", "details": err.Error()})
+			return
+		}
+		defer src.Close()
+		body = io.LimitReader(src, maxDNCImportSize)
+	} else {
+		body = http.MaxBytesReader(c.Writer, c.Request.Body, maxDNCImportSize)
+	}
+
+	importedBy := ""
+	if value, ok := c.Get("user"); ok {
+		if admin, ok := value.(*models.AdminUser); ok {
+			importedBy = admin.Username
+		}
+	}
+
+	result, err := h.dnc.ImportCSV(body, c.DefaultQuery("source", "manual_import"), importedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to import DNC list", "details": err.Error()})
+		return
+	}
+	if result.Phones+result.Emails == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No phone numbers or email addresses found", "result": result})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "result": result})
+}
+
+// ListSuppressions returns recent contact attempts refused by the do-not-contact list
+// GET /api/v1/compliance/dnc/suppressions?channel=&limit=
+func (h *ComplianceDNCHandlers) ListSuppressions(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit < 1 || limit > 500 {
+		limit = 100
+	}
+
+	query := h.db.Model(&models.DNCSuppressionLog{})
+	if channel := c.Query("channel"); channel != "" {
+		query = query.Where("channel = ?", channel)
+	}
+
+	suppressions := []models.DNCSuppressionLog{}
+	if err := query.Order("created_at DESC").Limit(limit).Find(&suppressions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load DNC suppressions", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "suppressions": suppressions, "count": len(suppressions)})
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"chrisgross-ctrl-project/internal/services"
+)
+
+// maxComplianceHistoryRange caps how far back compliance history can be requested
+const maxComplianceHistoryRange = 365 * 24 * time.Hour
+
+// ComplianceHistoryHandlers exposes scheduled compliance check results
+type ComplianceHistoryHandlers struct {
+	monitoring *services.ComplianceMonitoringService
+}
+
+// NewComplianceHistoryHandlers creates new compliance history handlers
+func NewComplianceHistoryHandlers(monitoring *services.ComplianceMonitoringService) *ComplianceHistoryHandlers {
+	return &ComplianceHistoryHandlers{monitoring: monitoring}
+}
+
+// GetHistory returns compliance check results for charting, e.g. ?range=24h, 7d or 30d
+// GET /api/v1/compliance/history
+func (h *ComplianceHistoryHandlers) GetHistory(c *gin.Context) {
+	rangeParam := c.DefaultQuery("range", "7d")
+	window, ok := parseComplianceHistoryRange(rangeParam)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid range",
+			"details": "range must be a number of hours or days, e.g. 24h or 30d, up to 365d",
+		})
+		return
+	}
+
+	history, err := h.monitoring.GetCheckHistory(time.Now().Add(-window))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve compliance history",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"range":   rangeParam,
+		"history": history,
+		"count":   len(history),
+	})
+}
+
+// parseComplianceHistoryRange parses "<n>h" or "<n>d"
+func parseComplianceHistoryRange(value string) (time.Duration, bool) {
+	if len(value) < 2 {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+
+	var window time.Duration
+	switch strings.ToLower(value[len(value)-1:]) {
+	case "h":
+		window = time.Duration(n) * time.Hour
+	case "d":
+		window = time.Duration(n) * 24 * time.Hour
+	default:
+		return 0, false
+	}
+	return window, window <= maxComplianceHistoryRange
+}
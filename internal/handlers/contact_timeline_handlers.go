@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+// ContactTimelineHandlers serves the merged activity timeline for a contact
+type ContactTimelineHandlers struct {
+	timeline *services.ContactTimelineService
+}
+
+// NewContactTimelineHandlers creates new contact timeline handlers
+func NewContactTimelineHandlers(db *gorm.DB, encryptionManager *security.EncryptionManager) *ContactTimelineHandlers {
+	return &ContactTimelineHandlers{timeline: services.NewContactTimelineService(db, encryptionManager)}
+}
+
+// GetContactTimeline returns the contact's leads, behavioral events, campaign
+// executions, incoming emails and approvals as one list, newest first
+// GET /api/v1/contacts/:id/timeline?types=email_sent,property_viewed&page=&limit=
+func (h *ContactTimelineHandlers) GetContactTimeline(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contact ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultTimelineLimit)))
+	if limit < 1 || limit > services.MaxTimelineLimit {
+		limit = services.DefaultTimelineLimit
+	}
+	query := services.TimelineQuery{Page: page, Limit: limit}
+	if types := c.Query("types"); types != "" {
+		known := make(map[string]bool, len(services.TimelineEventTypes))
+		for _, t := range services.TimelineEventTypes {
+			known[t] = true
+		}
+		for _, t := range strings.Split(types, ",") {
+			t = strings.TrimSpace(t)
+			if !known[t] {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid timeline type",
+					"details": "types must be from: " + strings.Join(services.TimelineEventTypes, ", "),
+				})
+				return
+			}
+			query.Types = append(query.Types, t)
+		}
+	}
+
+	timeline, err := h.timeline.Timeline(uint(id), admin, query)
+	if errors.Is(err, services.ErrContactNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build contact timeline", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"contact": timeline.Contact,
+		"events":  timeline.Events,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": timeline.Total,
+			"pages": (timeline.Total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
+	"chrisgross-ctrl-project/internal/logging"
+	"chrisgross-ctrl-project/internal/models"
 	"chrisgross-ctrl-project/internal/services"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -16,16 +22,58 @@ import (
 type ContextFUBIntegrationHandlers struct {
 	db               *gorm.DB
 	behavioralBridge *services.BehavioralFUBBridge
+	fubBatch         *services.FUBBatchService
+	fubErrors        *services.FUBErrorHandler
+
+	scoringMutex sync.RWMutex
+	scoring      models.ScoringConfig
 }
 
 // NewContextFUBIntegrationHandlers creates new context-driven FUB integration handlers
 func NewContextFUBIntegrationHandlers(db *gorm.DB, fubAPIKey string) *ContextFUBIntegrationHandlers {
+	scoring, err := models.LoadScoringConfig(db)
+	if err != nil {
+		log.Printf("⚠️ Failed to load scoring config, using defaults: %v", err)
+	}
 	return &ContextFUBIntegrationHandlers{
 		db:               db,
 		behavioralBridge: services.NewBehavioralFUBBridge(db, fubAPIKey),
+		scoring:          scoring,
 	}
 }
 
+// StartFUBPushRetries re-sends high-intent FUB pushes left pending retry on
+// an interval until ctx is cancelled
+func (h *ContextFUBIntegrationHandlers) StartFUBPushRetries(ctx context.Context, interval time.Duration) {
+	h.behavioralBridge.StartPushRetries(ctx, interval)
+}
+
+// SetFUBErrorHandler shares the FUB error handler with the bridge so its
+// failures are classified and reported on the status endpoint
+func (h *ContextFUBIntegrationHandlers) SetFUBErrorHandler(errorHandler *services.FUBErrorHandler) {
+	h.fubErrors = errorHandler
+	h.behavioralBridge.SetErrorHandler(errorHandler)
+}
+
+// SetFUBBatchService rate limits and batches FUB writes through the batch
+// service; without it, writes call the FUB API directly
+func (h *ContextFUBIntegrationHandlers) SetFUBBatchService(batch *services.FUBBatchService) {
+	h.fubBatch = batch
+	h.behavioralBridge.SetBatchService(batch)
+}
+
+// SetLeadSafetyFilter keeps contacts that fail the lead safety check out of FUB
+func (h *ContextFUBIntegrationHandlers) SetLeadSafetyFilter(safety *services.LeadSafetyFilter) {
+	h.behavioralBridge.SetSafetyFilter(safety)
+}
+
+// scoringConfig returns the multipliers and thresholds currently in use
+func (h *ContextFUBIntegrationHandlers) scoringConfig() models.ScoringConfig {
+	h.scoringMutex.RLock()
+	defer h.scoringMutex.RUnlock()
+	return h.scoring
+}
+
 // ContextFUBTriggerRequest represents a property-type aware context-driven FUB automation trigger
 type ContextFUBTriggerRequest struct {
 	SessionID             string                 `json:"session_id" binding:"required"`
@@ -62,6 +110,9 @@ type ContextFUBTriggerResponse struct {
 	TriggerID         string    `json:"trigger_id,omitempty"`
 	WorkflowType      string    `json:"workflow_type,omitempty"`
 	ScheduledAt       time.Time `json:"scheduled_at,omitempty"`
+	FUBPushStatus     string    `json:"fub_push_status,omitempty"` // pushed, pending_retry or blocked for HIGH/CRITICAL triggers
+	Replay            bool      `json:"replay,omitempty"` // Produced by replaying a stored webhook
+	ScoreExplanation  *services.ScoreExplanation `json:"score_explanation,omitempty"`
 }
 
 // TriggerContextDrivenFUBAutomation handles POST /api/v1/context-fub/trigger
@@ -73,23 +124,40 @@ func (h *ContextFUBIntegrationHandlers) TriggerContextDrivenFUBAutomation(c *gin
 		return
 	}
 
-	log.Printf("🎯 Processing context-driven FUB trigger for session: %s", trigger.SessionID)
+	ctx := c.Request.Context()
+	logging.FromContext(ctx).Info("processing context-driven FUB trigger",
+		"session_id", trigger.SessionID, "trigger_type", trigger.TriggerType)
 
-	result := h.processHybridContextTrigger(trigger)
+	result := h.processHybridContextTrigger(ctx, trigger)
 
 	c.JSON(http.StatusOK, result)
 }
 
 // ProcessContextIntelligenceWebhook handles POST /api/v1/context-fub/webhook
 func (h *ContextFUBIntegrationHandlers) ProcessContextIntelligenceWebhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook payload"})
+		return
+	}
 	var webhookData map[string]interface{}
-
-	if err := c.ShouldBindJSON(&webhookData); err != nil {
+	if err := json.Unmarshal(body, &webhookData); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook payload"})
 		return
 	}
 
-	shouldTrigger, triggerType := h.shouldTriggerContextAutomation(c.GetHeader("X-Event-Type"), webhookData)
+	eventType := c.GetHeader("X-Event-Type")
+	shouldTrigger, triggerType := h.shouldTriggerContextAutomation(eventType, webhookData)
+
+	// Keep every payload, including ignored ones, so it can be replayed later
+	h.recordReceivedWebhook(c.Request.Context(), &models.ReceivedWebhook{
+		Source:      models.WebhookSourceContextFUB,
+		EventType:   eventType,
+		Payload:     string(body),
+		ReceivedAt:  time.Now(),
+		Triggered:   shouldTrigger,
+		TriggerType: triggerType,
+	})
 
 	if !shouldTrigger {
 		c.JSON(http.StatusOK, gin.H{"processed": true, "action": "ignored"})
@@ -97,7 +165,9 @@ func (h *ContextFUBIntegrationHandlers) ProcessContextIntelligenceWebhook(c *gin
 	}
 
 	contextTrigger := h.extractContextFromWebhook(webhookData, triggerType)
-	result := h.processHybridContextTrigger(contextTrigger)
+	logging.FromContext(c.Request.Context()).Info("context intelligence webhook triggered automation",
+		"event_type", eventType, "trigger_type", triggerType, "session_id", contextTrigger.SessionID)
+	result := h.processHybridContextTrigger(c.Request.Context(), contextTrigger)
 
 	c.JSON(http.StatusOK, gin.H{
 		"processed": true,
@@ -133,6 +203,7 @@ func (h *ContextFUBIntegrationHandlers) GetContextFUBAnalytics(c *gin.Context) {
 		"property_breakdown":  h.getPropertyTypeBreakdown(since),
 		"conversion_metrics":  h.getConversionMetrics(since, propertyType),
 		"behavioral_insights": h.getBehavioralInsights(since, propertyType),
+		"webhooks":            h.getWebhookCounts(since),
 	}
 
 	c.JSON(http.StatusOK, analytics)
@@ -146,6 +217,19 @@ func (h *ContextFUBIntegrationHandlers) GetContextFUBStatus(c *gin.Context) {
 		"context_intelligence": "active",
 		"last_updated":         time.Now(),
 		"version":              "2.1.0-enterprise",
+		"fub_write_mode":       "direct",
+	}
+	if h.fubBatch != nil {
+		status["fub_write_mode"] = "batched"
+		status["fub_batch"] = h.fubBatch.GetBatchStats()
+	}
+	if h.fubErrors != nil {
+		errorStats := h.fubErrors.Stats()
+		status["fub_errors"] = errorStats
+		if errorStats.LastAuthFailure != nil && time.Since(*errorStats.LastAuthFailure) < time.Hour {
+			status["service_status"] = "misconfigured"
+			status["config_error"] = services.ErrFUBAuthConfig.Error()
+		}
 	}
 
 	c.JSON(http.StatusOK, status)
@@ -376,12 +460,12 @@ func (h *ContextFUBIntegrationHandlers) formatMarketInsightsForResponse(intellig
 	return strings.Join(insights, ". ")
 }
 
-// processHybridContextTrigger processes the trigger with full context intelligence
-func (h *ContextFUBIntegrationHandlers) processHybridContextTrigger(trigger ContextFUBTriggerRequest) ContextFUBTriggerResponse {
-	log.Printf("🧠 Processing hybrid context trigger for property type: %s", trigger.PropertyType)
-
+// processHybridContextTrigger processes the trigger with full context intelligence,
+// logging through the request-scoped logger carried by ctx
+func (h *ContextFUBIntegrationHandlers) processHybridContextTrigger(ctx context.Context, trigger ContextFUBTriggerRequest) ContextFUBTriggerResponse {
 	triggerID := fmt.Sprintf("trig_%d", time.Now().UnixNano())
-	contactID := fmt.Sprintf("contact_%s_%d", trigger.SessionID, time.Now().UnixNano()%10000)
+	logger := logging.FromContext(ctx).With("trigger_id", triggerID, "session_id", trigger.SessionID)
+	logger.Debug("processing hybrid context trigger", "property_type", trigger.PropertyType)
 
 	workflowType := h.determineAdaptiveWorkflowType(
 		trigger.EngagementScore,
@@ -419,7 +503,13 @@ func (h *ContextFUBIntegrationHandlers) processHybridContextTrigger(trigger Cont
 	marketInsights := h.formatMarketInsightsForResponse(marketIntelligence)
 	reasoning := h.generateContextReasoning(trigger, workflowType, recommendedAction)
 
-	return ContextFUBTriggerResponse{
+	logger.Info("context trigger processed",
+		"workflow_type", workflowType,
+		"recommended_action", recommendedAction,
+		"priority", priority,
+		"next_follow_up", nextFollowUp)
+
+	response := ContextFUBTriggerResponse{
 		Success:           true,
 		WorkflowTriggered: workflowType,
 		RecommendedAction: recommendedAction,
@@ -430,42 +520,94 @@ func (h *ContextFUBIntegrationHandlers) processHybridContextTrigger(trigger Cont
 		Reasoning:         reasoning,
 		PropertyCategory:  trigger.PropertyType,
 		MarketInsights:    marketInsights,
-		ContactID:         contactID,
 		TriggerID:         triggerID,
 		WorkflowType:      workflowType,
 		ScheduledAt:       scheduledAt,
 	}
+
+	if isHighIntentPriority(priority) && h.behavioralBridge != nil {
+		h.pushHighIntentContact(ctx, trigger, &response)
+	}
+	return response
+}
+
+// isHighIntentPriority reports whether a trigger priority is pushed to FUB
+func isHighIntentPriority(priority string) bool {
+	return priority == "HIGH" || priority == "CRITICAL"
+}
+
+// pushHighIntentContact upserts the trigger's FUB contact with the computed
+// tags and note and schedules the follow-up, filling in the real contact and
+// automation IDs. A FUB failure leaves the push pending retry and the
+// response unsuccessful.
+func (h *ContextFUBIntegrationHandlers) pushHighIntentContact(ctx context.Context, trigger ContextFUBTriggerRequest, response *ContextFUBTriggerResponse) {
+	request := services.BehavioralTriggerRequest(trigger)
+	push := services.FUBContactPush{
+		Tags: []string{
+			"context_" + strings.ToLower(response.WorkflowType),
+			"action_" + response.RecommendedAction,
+			"priority_" + strings.ToLower(response.Priority),
+		},
+		Note:              fmt.Sprintf("%s\n\nRecommended action: %s\n%s", response.Reasoning, response.RecommendedAction, response.MarketInsights),
+		TaskTitle:         fmt.Sprintf("%s priority follow-up: %s", response.Priority, strings.ReplaceAll(response.RecommendedAction, "_", " ")),
+		FollowUpAt:        response.NextFollowUp,
+		Priority:          response.Priority,
+		WorkflowType:      response.WorkflowType,
+		RecommendedAction: response.RecommendedAction,
+	}
+	if trigger.TriggerType != "" {
+		push.Tags = append(push.Tags, trigger.TriggerType)
+	}
+
+	result, err := h.behavioralBridge.PushHighIntentTrigger(ctx, response.TriggerID, &request, push)
+	if errors.Is(err, services.ErrLeadContactBlocked) {
+		response.FUBPushStatus = models.FUBPushStatusBlocked
+		response.Message = fmt.Sprintf("FUB push skipped: %v", err)
+		return
+	}
+	if err != nil {
+		response.Success = false
+		response.FUBPushStatus = models.FUBPushStatusPendingRetry
+		response.Message = fmt.Sprintf("FUB push failed and was queued for retry: %v", err)
+		return
+	}
+	response.FUBPushStatus = models.FUBPushStatusPushed
+	response.ContactID = result.ContactID
+	response.FUBAutomationID = result.AutomationID
 }
 
 // Workflow determination methods
 
 func (h *ContextFUBIntegrationHandlers) determineWorkflowType(engagement, conversion, urgency float64, triggerType string) string {
-	if urgency >= 0.8 && engagement >= 0.7 {
+	cfg := h.scoringConfig()
+	if urgency >= cfg.HighIntentUrgency && engagement >= cfg.HighIntentEngagement {
 		return "HIGH_INTENT_IMMEDIATE"
-	} else if conversion >= 0.6 && engagement >= 0.5 {
+	} else if conversion >= cfg.QualifiedNurtureConversion && engagement >= cfg.QualifiedNurtureEngagement {
 		return "QUALIFIED_NURTURE"
-	} else if engagement >= 0.4 {
+	} else if engagement >= cfg.EngagementBuildingEngagement {
 		return "ENGAGEMENT_BUILDING"
 	}
 	return "AWARENESS_BUILDING"
 }
 
 func (h *ContextFUBIntegrationHandlers) getContextRecommendedAction(engagement, conversion, urgency float64) string {
-	if urgency >= 0.8 {
+	cfg := h.scoringConfig()
+	if urgency >= cfg.ImmediateCallUrgency {
 		return "immediate_call"
-	} else if conversion >= 0.6 {
+	} else if conversion >= cfg.PersonalizedFollowUpConversion {
 		return "personalized_follow_up"
-	} else if engagement >= 0.4 {
+	} else if engagement >= cfg.EducationalContentEngagement {
 		return "educational_content"
 	}
 	return "nurture_sequence"
 }
 
 func (h *ContextFUBIntegrationHandlers) calculatePriority(engagement, conversion, urgency float64) string {
+	cfg := h.scoringConfig()
 	score := (engagement + conversion + urgency) / 3.0
-	if score >= 0.7 {
+	if score >= cfg.HighPriorityAverage {
 		return "HIGH"
-	} else if score >= 0.4 {
+	} else if score >= cfg.MediumPriorityAverage {
 		return "MEDIUM"
 	}
 	return "LOW"
@@ -712,13 +854,14 @@ func (h *ContextFUBIntegrationHandlers) determineLeadType(data map[string]interf
 // Adaptive scoring methods
 
 func (h *ContextFUBIntegrationHandlers) calculateAdaptiveEngagementScore(data map[string]interface{}, propertyMarketType string) float64 {
+	cfg := h.scoringConfig()
 	baseScore := h.calculateEngagementFromWebhook(data)
 
 	switch propertyMarketType {
 	case "rental":
-		return baseScore * 1.1
+		return baseScore * cfg.RentalEngagementMultiplier
 	case "sales":
-		return baseScore * 0.9
+		return baseScore * cfg.SalesEngagementMultiplier
 	case "mixed":
 		return baseScore
 	}
@@ -727,12 +870,13 @@ func (h *ContextFUBIntegrationHandlers) calculateAdaptiveEngagementScore(data ma
 }
 
 func (h *ContextFUBIntegrationHandlers) calculateAdaptiveFinancialScore(data map[string]interface{}, propertyMarketType string) float64 {
+	cfg := h.scoringConfig()
 	baseScore := h.calculateConversionFromWebhook(data)
 
 	if propertyMarketType == "sales" {
 		if financing, exists := data["pre_approved"]; exists {
 			if approved, ok := financing.(bool); ok && approved {
-				baseScore += 0.3
+				baseScore += cfg.PreApprovedFinancialBoost
 			}
 		}
 	}
@@ -740,7 +884,7 @@ func (h *ContextFUBIntegrationHandlers) calculateAdaptiveFinancialScore(data map
 	if propertyMarketType == "rental" {
 		if income, exists := data["income_verified"]; exists {
 			if verified, ok := income.(bool); ok && verified {
-				baseScore += 0.2
+				baseScore += cfg.IncomeVerifiedFinancialBoost
 			}
 		}
 	}
@@ -753,13 +897,14 @@ func (h *ContextFUBIntegrationHandlers) calculateAdaptiveFinancialScore(data map
 }
 
 func (h *ContextFUBIntegrationHandlers) calculateAdaptiveUrgencyScore(data map[string]interface{}, propertyMarketType string) float64 {
+	cfg := h.scoringConfig()
 	baseScore := h.calculateUrgencyFromWebhook(data)
 
 	switch propertyMarketType {
 	case "rental":
-		return baseScore * 1.2
+		return baseScore * cfg.RentalUrgencyMultiplier
 	case "sales":
-		return baseScore * 0.8
+		return baseScore * cfg.SalesUrgencyMultiplier
 	}
 
 	if baseScore > 1.0 {
@@ -785,33 +930,36 @@ func (h *ContextFUBIntegrationHandlers) determineAdaptiveWorkflowType(engagement
 }
 
 func (h *ContextFUBIntegrationHandlers) determineRentalWorkflowType(engagement, financial, urgency float64, triggerType string) string {
-	if urgency >= 0.8 && financial >= 0.6 {
+	cfg := h.scoringConfig()
+	if urgency >= cfg.RentalImmediateUrgency && financial >= cfg.RentalImmediateFinancial {
 		return "RENTAL_IMMEDIATE_QUALIFIED"
-	} else if urgency >= 0.6 && engagement >= 0.5 {
+	} else if urgency >= cfg.RentalUrgentUrgency && engagement >= cfg.RentalUrgentEngagement {
 		return "RENTAL_URGENT_NURTURE"
-	} else if financial >= 0.5 {
+	} else if financial >= cfg.RentalQualifiedFinancial {
 		return "RENTAL_QUALIFIED_BUILDING"
 	}
 	return "RENTAL_AWARENESS_BUILDING"
 }
 
 func (h *ContextFUBIntegrationHandlers) determineSalesWorkflowType(engagement, financial, urgency float64, triggerType string) string {
-	if financial >= 0.8 && urgency >= 0.6 {
+	cfg := h.scoringConfig()
+	if financial >= cfg.SalesPreApprovedFinancial && urgency >= cfg.SalesPreApprovedUrgency {
 		return "SALES_PRE_APPROVED_URGENT"
-	} else if engagement >= 0.7 && financial >= 0.5 {
+	} else if engagement >= cfg.SalesEngagedEngagement && financial >= cfg.SalesEngagedFinancial {
 		return "SALES_ENGAGED_QUALIFIED"
-	} else if urgency >= 0.6 {
+	} else if urgency >= cfg.SalesUrgentUrgency {
 		return "SALES_URGENT_NURTURE"
 	}
 	return "SALES_EDUCATION_BUILDING"
 }
 
 func (h *ContextFUBIntegrationHandlers) determineMixedWorkflowType(engagement, financial, urgency float64, triggerType string) string {
+	cfg := h.scoringConfig()
 	avgScore := (engagement + financial + urgency) / 3.0
 	
-	if avgScore >= 0.7 {
+	if avgScore >= cfg.MixedHighIntentAverage {
 		return "MIXED_HIGH_INTENT"
-	} else if avgScore >= 0.5 {
+	} else if avgScore >= cfg.MixedQualifiedAverage {
 		return "MIXED_QUALIFIED_NURTURE"
 	}
 	return "MIXED_EXPLORATION_SUPPORT"
@@ -833,33 +981,36 @@ func (h *ContextFUBIntegrationHandlers) getAdaptiveRecommendedAction(engagement,
 }
 
 func (h *ContextFUBIntegrationHandlers) getRentalRecommendedAction(engagement, financial, urgency float64) string {
-	if urgency >= 0.8 {
+	cfg := h.scoringConfig()
+	if urgency >= cfg.RentalShowingCallUrgency {
 		return "immediate_showing_call"
-	} else if financial >= 0.6 && urgency >= 0.5 {
+	} else if financial >= cfg.RentalApplicationFinancial && urgency >= cfg.RentalApplicationUrgency {
 		return "application_assistance"
-	} else if engagement >= 0.5 {
+	} else if engagement >= cfg.RentalTourEngagement {
 		return "property_tour_booking"
 	}
 	return "rental_market_education"
 }
 
 func (h *ContextFUBIntegrationHandlers) getSalesRecommendedAction(engagement, financial, urgency float64) string {
-	if financial >= 0.8 {
+	cfg := h.scoringConfig()
+	if financial >= cfg.SalesConsultationFinancial {
 		return "buyer_consultation_call"
-	} else if urgency >= 0.7 && engagement >= 0.5 {
+	} else if urgency >= cfg.SalesMarketAnalysisUrgency && engagement >= cfg.SalesMarketAnalysisEngagement {
 		return "market_analysis_presentation"
-	} else if engagement >= 0.6 {
+	} else if engagement >= cfg.SalesFinancingEngagement {
 		return "financing_pre_approval_guidance"
 	}
 	return "buyer_education_sequence"
 }
 
 func (h *ContextFUBIntegrationHandlers) getMixedRecommendedAction(engagement, financial, urgency float64) string {
+	cfg := h.scoringConfig()
 	avgScore := (engagement + financial + urgency) / 3.0
 	
-	if avgScore >= 0.7 {
+	if avgScore >= cfg.MixedHighIntentAverage {
 		return "comprehensive_consultation"
-	} else if avgScore >= 0.5 {
+	} else if avgScore >= cfg.MixedQualifiedAverage {
 		return "option_exploration_call"
 	}
 	return "market_opportunity_education"
@@ -1004,67 +1155,83 @@ func (h *ContextFUBIntegrationHandlers) detectAdvancedPropertyCategory(propertyC
 
 // calculatePropertySpecificBehavioralScore computes behavioral scoring based on property category
 func (h *ContextFUBIntegrationHandlers) calculatePropertySpecificBehavioralScore(propertyCategory string, behaviorData map[string]interface{}) float64 {
+	score, _ := h.explainPropertySpecificBehavioralScore(propertyCategory, behaviorData)
+	return score
+}
+
+// explainPropertySpecificBehavioralScore computes the behavioral score along with
+// the factors that contributed to it
+func (h *ContextFUBIntegrationHandlers) explainPropertySpecificBehavioralScore(propertyCategory string, behaviorData map[string]interface{}) (float64, []services.ScoreFactor) {
 	baseScore := 0.3
-	
+	factors := []services.ScoreFactor{
+		{Name: "base", Value: 1, Weight: baseScore, Points: baseScore, Detail: "Starting score for every lead"},
+	}
+	add := func(name string, value, weight float64, detail string) {
+		points := value * weight
+		baseScore += points
+		factors = append(factors, services.ScoreFactor{Name: name, Value: value, Weight: weight, Points: points, Detail: detail})
+	}
+
 	switch strings.ToLower(propertyCategory) {
 	case "rental":
 		if moveInDate, exists := behaviorData["move_in_urgency"]; exists {
 			if urgency, ok := moveInDate.(string); ok {
 				switch urgency {
 				case "immediate", "asap":
-					baseScore += 0.4
+					add("move_in_urgency", 1, 0.4, urgency)
 				case "30_days":
-					baseScore += 0.3
+					add("move_in_urgency", 1, 0.3, urgency)
 				case "60_days":
-					baseScore += 0.2
+					add("move_in_urgency", 1, 0.2, urgency)
 				}
 			}
 		}
-		
+
 		if applicationReady, exists := behaviorData["application_ready"]; exists {
 			if ready, ok := applicationReady.(bool); ok && ready {
-				baseScore += 0.2
+				add("application_ready", 1, 0.2, "")
 			}
 		}
 
 	case "sales":
 		if preApproval, exists := behaviorData["pre_approval_status"]; exists {
 			if approved, ok := preApproval.(bool); ok && approved {
-				baseScore += 0.3
+				add("pre_approval_status", 1, 0.3, "")
 			}
 		}
-		
+
 		if tourRequests, exists := behaviorData["property_tour_requests"]; exists {
 			if requests, ok := tourRequests.(float64); ok {
-				baseScore += requests * 0.1
+				add("property_tour_requests", requests, 0.1, "Per tour requested")
 			}
 		}
 
 	case "investment":
 		if roiCalculations, exists := behaviorData["roi_calculations_viewed"]; exists {
 			if calculations, ok := roiCalculations.(float64); ok && calculations > 0 {
-				baseScore += 0.25
+				add("roi_calculations_viewed", 1, 0.25, fmt.Sprintf("%.0f viewed", calculations))
 			}
 		}
-		
+
 		if cashFlowAnalysis, exists := behaviorData["cash_flow_analysis_time"]; exists {
 			if analysisTime, ok := cashFlowAnalysis.(float64); ok && analysisTime > 300 {
-				baseScore += 0.15
+				add("cash_flow_analysis_time", 1, 0.15, fmt.Sprintf("%.0f seconds", analysisTime))
 			}
 		}
 	}
 
 	if contactAttempts, exists := behaviorData["contact_attempts"]; exists {
 		if attempts, ok := contactAttempts.(float64); ok {
-			baseScore += attempts * 0.1
+			add("contact_attempts", attempts, 0.1, "Per contact attempt")
 		}
 	}
 
 	if baseScore > 1.0 {
+		factors = append(factors, services.ScoreFactor{Name: "cap", Value: 1, Weight: 1, Points: 1.0 - baseScore, Detail: "Score is capped at 1.0"})
 		baseScore = 1.0
 	}
 
-	return baseScore
+	return baseScore, factors
 }
 
 // evaluateAdvancedTriggerConditions analyzes complex trigger condition patterns
@@ -1177,45 +1344,7 @@ func (h *ContextFUBIntegrationHandlers) calculatePatternMatchConfidence(behavior
 
 // calculateOptimalNextActionTime determines the best timing for next action
 func (h *ContextFUBIntegrationHandlers) calculateOptimalNextActionTime(urgencyScore, engagementScore float64, propertyCategory string, marketConditions map[string]interface{}) time.Time {
-	baseDelay := 24 * time.Hour
-
-	if urgencyScore >= 0.8 {
-		baseDelay = 15 * time.Minute
-	} else if urgencyScore >= 0.6 {
-		baseDelay = 2 * time.Hour
-	} else if urgencyScore >= 0.4 {
-		baseDelay = 8 * time.Hour
-	}
-
-	if engagementScore >= 0.8 {
-		baseDelay = time.Duration(float64(baseDelay) * 0.5)
-	} else if engagementScore >= 0.6 {
-		baseDelay = time.Duration(float64(baseDelay) * 0.7)
-	}
-
-	switch strings.ToLower(propertyCategory) {
-	case "rental":
-		baseDelay = time.Duration(float64(baseDelay) * 0.6)
-	case "investment":
-		baseDelay = time.Duration(float64(baseDelay) * 1.3)
-	case "commercial":
-		baseDelay = time.Duration(float64(baseDelay) * 2.0)
-	}
-
-	if marketConditions != nil {
-		if inventory, exists := marketConditions["inventory_level"]; exists {
-			if inventoryStr, ok := inventory.(string); ok {
-				if inventoryStr == "tight" {
-					baseDelay = time.Duration(float64(baseDelay) * 0.7)
-				} else if inventoryStr == "abundant" {
-					baseDelay = time.Duration(float64(baseDelay) * 1.2)
-				}
-			}
-		}
-	}
-
-	nextTime := time.Now().Add(baseDelay)
-	return h.adjustToBusinessHours(nextTime)
+	return services.OptimalNextActionTime(time.Now(), urgencyScore, engagementScore, propertyCategory, marketConditions)
 }
 
 // generatePatternAnalysis creates comprehensive behavioral pattern analysis
@@ -1278,6 +1407,12 @@ func (h *ContextFUBIntegrationHandlers) generatePatternAnalysis(behaviorData map
 
 // calculateAdvancedPriority computes priority using advanced scoring algorithms
 func (h *ContextFUBIntegrationHandlers) calculateAdvancedPriority(engagementScore, financialScore, urgencyScore, marketFactorScore float64, propertyCategory string) string {
+	return h.explainAdvancedPriority(engagementScore, financialScore, urgencyScore, marketFactorScore, propertyCategory).Label
+}
+
+// explainAdvancedPriority computes the priority along with each score's weight
+// and points for the property category
+func (h *ContextFUBIntegrationHandlers) explainAdvancedPriority(engagementScore, financialScore, urgencyScore, marketFactorScore float64, propertyCategory string) services.ScoreExplanation {
 	var engagementWeight, financialWeight, urgencyWeight, marketWeight float64
 	
 	switch strings.ToLower(propertyCategory) {
@@ -1308,21 +1443,37 @@ func (h *ContextFUBIntegrationHandlers) calculateAdvancedPriority(engagementScor
 		marketWeight = 0.15
 	}
 
-	priorityScore := (engagementScore * engagementWeight) +
-					(financialScore * financialWeight) +
-					(urgencyScore * urgencyWeight) +
-					(marketFactorScore * marketWeight)
+	explanation := services.ScoreExplanation{
+		Factors: []services.ScoreFactor{
+			{Name: "engagement", Value: engagementScore, Weight: engagementWeight, Points: engagementScore * engagementWeight},
+			{Name: "financial", Value: financialScore, Weight: financialWeight, Points: financialScore * financialWeight},
+			{Name: "urgency", Value: urgencyScore, Weight: urgencyWeight, Points: urgencyScore * urgencyWeight},
+			{Name: "market", Value: marketFactorScore, Weight: marketWeight, Points: marketFactorScore * marketWeight},
+		},
+		Thresholds: map[string]float64{
+			"CRITICAL": 0.85,
+			"HIGH":     0.70,
+			"MEDIUM":   0.50,
+			"LOW":      0.30,
+		},
+	}
+	for _, factor := range explanation.Factors {
+		explanation.Score += factor.Points
+	}
 
+	priorityScore := explanation.Score
 	if priorityScore >= 0.85 {
-		return "CRITICAL"
+		explanation.Label = "CRITICAL"
 	} else if priorityScore >= 0.70 {
-		return "HIGH"
+		explanation.Label = "HIGH"
 	} else if priorityScore >= 0.50 {
-		return "MEDIUM"
+		explanation.Label = "MEDIUM"
 	} else if priorityScore >= 0.30 {
-		return "LOW"
+		explanation.Label = "LOW"
+	} else {
+		explanation.Label = "MINIMAL"
 	}
-	return "MINIMAL"
+	return explanation
 }
 
 // generateAdvancedTriggerMessage creates personalized trigger messages
@@ -1456,37 +1607,6 @@ func (h *ContextFUBIntegrationHandlers) calculatePatternConsistency(scores []flo
 	return consistency
 }
 
-func (h *ContextFUBIntegrationHandlers) adjustToBusinessHours(t time.Time) time.Time {
-	central, _ := time.LoadLocation("America/Chicago")
-	adjustedTime := t.In(central)
-
-	hour := adjustedTime.Hour()
-	weekday := adjustedTime.Weekday()
-
-	if weekday == time.Saturday {
-		daysToAdd := 2
-		adjustedTime = adjustedTime.AddDate(0, 0, daysToAdd)
-		adjustedTime = time.Date(adjustedTime.Year(), adjustedTime.Month(), adjustedTime.Day(), 9, 0, 0, 0, central)
-	} else if weekday == time.Sunday {
-		daysToAdd := 1
-		adjustedTime = adjustedTime.AddDate(0, 0, daysToAdd)
-		adjustedTime = time.Date(adjustedTime.Year(), adjustedTime.Month(), adjustedTime.Day(), 9, 0, 0, 0, central)
-	} else {
-		if hour < 9 {
-			adjustedTime = time.Date(adjustedTime.Year(), adjustedTime.Month(), adjustedTime.Day(), 9, 0, 0, 0, central)
-		} else if hour >= 18 {
-			if weekday == time.Friday {
-				adjustedTime = adjustedTime.AddDate(0, 0, 3)
-			} else {
-				adjustedTime = adjustedTime.AddDate(0, 0, 1)
-			}
-			adjustedTime = time.Date(adjustedTime.Year(), adjustedTime.Month(), adjustedTime.Day(), 9, 0, 0, 0, central)
-		}
-	}
-
-	return adjustedTime
-}
-
 // Additional helper methods
 
 func (h *ContextFUBIntegrationHandlers) calculatePredictiveScore(behaviorData map[string]interface{}, comparisonData []map[string]interface{}) float64 {
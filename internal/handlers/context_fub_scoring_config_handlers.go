@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"chrisgross-ctrl-project/internal/models"
+)
+
+// GetScoringConfig returns the multipliers and thresholds used to choose workflows
+// GET /api/v1/context-fub/scoring-config
+func (h *ContextFUBIntegrationHandlers) GetScoringConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"config":  h.scoringConfig(),
+	})
+}
+
+// UpdateScoringConfig changes multipliers and thresholds. Omitted fields keep their current values.
+// PUT /api/v1/context-fub/scoring-config
+func (h *ContextFUBIntegrationHandlers) UpdateScoringConfig(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	if !models.IsTeamAdminRole(admin.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Changing scoring config requires a team admin"})
+		return
+	}
+
+	config := h.scoringConfig()
+	if err := c.ShouldBindJSON(&config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err := config.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid scoring config",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	config.ID = models.ScoringConfigID
+	config.UpdatedBy = admin.Username
+	if err := h.db.Save(&config).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update scoring config",
+			"details": err.Error(),
+		})
+		return
+	}
+	h.setScoringConfig(config)
+	log.Printf("🎯 Scoring config updated by %s", admin.Username)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Scoring config updated",
+		"config":  config,
+	})
+}
+
+// setScoringConfig makes a config the one used for scoring and workflow selection
+func (h *ContextFUBIntegrationHandlers) setScoringConfig(config models.ScoringConfig) {
+	h.scoringMutex.Lock()
+	h.scoring = config
+	h.scoringMutex.Unlock()
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+)
+
+func TestScoringConfig_ThresholdChangeChangesWorkflow(t *testing.T) {
+	h := &ContextFUBIntegrationHandlers{scoring: models.DefaultScoringConfig()}
+
+	engagement, financial, urgency := 0.3, 0.45, 0.5
+	if workflow := h.determineAdaptiveWorkflowType(engagement, financial, urgency, "property_viewed", "rental"); workflow != "RENTAL_AWARENESS_BUILDING" {
+		t.Fatalf("Expected RENTAL_AWARENESS_BUILDING with default thresholds, got %s", workflow)
+	}
+
+	config := models.DefaultScoringConfig()
+	config.RentalQualifiedFinancial = 0.4
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected config to be valid: %v", err)
+	}
+	h.setScoringConfig(config)
+
+	if workflow := h.determineAdaptiveWorkflowType(engagement, financial, urgency, "property_viewed", "rental"); workflow != "RENTAL_QUALIFIED_BUILDING" {
+		t.Errorf("Expected RENTAL_QUALIFIED_BUILDING after lowering the threshold, got %s", workflow)
+	}
+
+	config.MediumPriorityAverage = 0.9
+	if err := config.Validate(); err == nil {
+		t.Error("Expected a medium priority average above the high average to be rejected")
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"chrisgross-ctrl-project/internal/logging"
+	"chrisgross-ctrl-project/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookReplayBatch caps how many stored webhooks one replay request re-runs
+const maxWebhookReplayBatch = 500
+
+// WebhookReplayOutcome reports what happened to one replayed webhook
+type WebhookReplayOutcome struct {
+	WebhookID uint                       `json:"webhook_id"`
+	ReplayID  uint                       `json:"replay_id,omitempty"`
+	EventType string                     `json:"event_type"`
+	Action    string                     `json:"action"` // context_automation_triggered, ignored, failed
+	Error     string                     `json:"error,omitempty"`
+	Result    *ContextFUBTriggerResponse `json:"result,omitempty"`
+}
+
+// recordReceivedWebhook stores a webhook payload; a storage failure is logged
+// but does not stop the webhook from being processed
+func (h *ContextFUBIntegrationHandlers) recordReceivedWebhook(ctx context.Context, webhook *models.ReceivedWebhook) {
+	if err := h.db.Create(webhook).Error; err != nil {
+		logging.FromContext(ctx).Warn("failed to store received webhook",
+			"source", webhook.Source, "event_type", webhook.EventType, "error", err)
+	}
+}
+
+// ReplayWebhooks handles POST /api/v1/context-fub/replay, re-running stored
+// context intelligence webhooks selected by ID or received time range
+func (h *ContextFUBIntegrationHandlers) ReplayWebhooks(c *gin.Context) {
+	var request struct {
+		IDs       []uint     `json:"ids"`
+		From      *time.Time `json:"from"`
+		To        *time.Time `json:"to"`
+		EventType string     `json:"event_type"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid replay request", "details": err.Error()})
+		return
+	}
+	if len(request.IDs) == 0 && request.From == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid replay request", "details": "ids or from is required"})
+		return
+	}
+	if request.From != nil && request.To != nil && request.To.Before(*request.From) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid replay request", "details": "to must not be before from"})
+		return
+	}
+	if len(request.IDs) > maxWebhookReplayBatch {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid replay request", "details": fmt.Sprintf("at most %d ids may be replayed at once", maxWebhookReplayBatch)})
+		return
+	}
+
+	webhooks, err := models.FindReplayableWebhooks(h.db, models.WebhookReplayFilter{
+		Source:    models.WebhookSourceContextFUB,
+		IDs:       request.IDs,
+		From:      request.From,
+		To:        request.To,
+		EventType: request.EventType,
+		Limit:     maxWebhookReplayBatch + 1,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load webhooks", "details": err.Error()})
+		return
+	}
+	if len(webhooks) > maxWebhookReplayBatch {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many webhooks in range", "details": fmt.Sprintf("narrow the range to at most %d webhooks", maxWebhookReplayBatch)})
+		return
+	}
+
+	replayedBy := ""
+	if admin, ok := c.Get("user"); ok {
+		if user, ok := admin.(*models.AdminUser); ok {
+			replayedBy = user.Username
+		}
+	}
+
+	outcomes := make([]WebhookReplayOutcome, 0, len(webhooks))
+	counts := map[string]int{}
+	for _, webhook := range webhooks {
+		outcome := h.replayWebhook(c.Request.Context(), webhook, replayedBy)
+		counts[outcome.Action]++
+		outcomes = append(outcomes, outcome)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"replayed":  len(outcomes),
+		"triggered": counts["context_automation_triggered"],
+		"ignored":   counts["ignored"],
+		"failed":    counts["failed"],
+		"outcomes":  outcomes,
+	})
+}
+
+// replayWebhook re-runs one stored webhook through the current processing code
+// and stores the replay as its own ReceivedWebhook row
+func (h *ContextFUBIntegrationHandlers) replayWebhook(ctx context.Context, webhook models.ReceivedWebhook, replayedBy string) WebhookReplayOutcome {
+	outcome := WebhookReplayOutcome{WebhookID: webhook.ID, EventType: webhook.EventType}
+
+	var webhookData map[string]interface{}
+	if err := json.Unmarshal([]byte(webhook.Payload), &webhookData); err != nil {
+		outcome.Action = "failed"
+		outcome.Error = fmt.Sprintf("stored payload is not valid JSON: %v", err)
+		return outcome
+	}
+
+	replayOf := webhook.ID
+	replay := models.ReceivedWebhook{
+		Source:     webhook.Source,
+		EventType:  webhook.EventType,
+		Payload:    webhook.Payload,
+		ReceivedAt: time.Now(),
+		IsReplay:   true,
+		ReplayOfID: &replayOf,
+		ReplayedBy: replayedBy,
+	}
+	replay.Triggered, replay.TriggerType = h.shouldTriggerContextAutomation(webhook.EventType, webhookData)
+	h.recordReceivedWebhook(ctx, &replay)
+	outcome.ReplayID = replay.ID
+
+	if !replay.Triggered {
+		outcome.Action = "ignored"
+		return outcome
+	}
+
+	contextTrigger := h.extractContextFromWebhook(webhookData, replay.TriggerType)
+	logging.FromContext(ctx).Info("replaying context intelligence webhook",
+		"webhook_id", webhook.ID, "event_type", webhook.EventType, "trigger_type", replay.TriggerType, "replay", true)
+	result := h.processHybridContextTrigger(ctx, contextTrigger)
+	result.Replay = true
+
+	outcome.Action = "context_automation_triggered"
+	outcome.Result = &result
+	return outcome
+}
+
+// getWebhookCounts counts live and replayed context webhooks received since a time
+func (h *ContextFUBIntegrationHandlers) getWebhookCounts(since time.Time) map[string]int64 {
+	counts := map[string]int64{"live": 0, "replayed": 0}
+	var rows []struct {
+		IsReplay bool
+		Count    int64
+	}
+	h.db.Model(&models.ReceivedWebhook{}).
+		Select("is_replay, COUNT(*) AS count").
+		Where("source = ? AND received_at >= ?", models.WebhookSourceContextFUB, since).
+		Group("is_replay").
+		Scan(&rows)
+	for _, row := range rows {
+		if row.IsReplay {
+			counts["replayed"] = row.Count
+		} else {
+			counts["live"] = row.Count
+		}
+	}
+	return counts
+}
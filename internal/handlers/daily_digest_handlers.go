@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+// DailyDigestHandlers serves the daily digest preview and unsubscribe link.
+// Admins opt in through their "daily_digest" notification preference.
+type DailyDigestHandlers struct {
+	digest *services.DailyDigestService
+}
+
+// NewDailyDigestHandlers creates new daily digest handlers
+func NewDailyDigestHandlers(digest *services.DailyDigestService) *DailyDigestHandlers {
+	return &DailyDigestHandlers{digest: digest}
+}
+
+// PreviewDigest returns the signed-in admin's digest as it would be sent now
+// GET /api/v1/admin/digest/preview
+func (h *DailyDigestHandlers) PreviewDigest(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	digest, err := h.digest.Preview(*admin)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build digest", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"digest": digest})
+}
+
+// HandleUnsubscribe turns off an admin's daily digest from the signed link
+// in the email. POST is the one-click form mail clients send.
+// GET|POST /digest/unsubscribe?token=
+func (h *DailyDigestHandlers) HandleUnsubscribe(c *gin.Context) {
+	oneClick := c.Request.Method == http.MethodPost
+
+	adminUserID, err := h.digest.Unsubscribe(c.Query("token"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Unable to turn off the daily digest. Please update your notification preferences instead."
+		if err == services.ErrInvalidUnsubscribeToken {
+			status = http.StatusBadRequest
+			message = "Invalid unsubscribe link. Please update your notification preferences instead."
+		}
+		if oneClick {
+			c.JSON(status, gin.H{"error": message})
+			return
+		}
+		c.HTML(status, "consumer/pages/unsubscribe_error.html", gin.H{"Title": "Unsubscribe Error", "Error": message})
+		return
+	}
+
+	log.Printf("📰 Admin %s unsubscribed from the daily digest", adminUserID)
+	if oneClick {
+		c.JSON(http.StatusOK, gin.H{"message": "Successfully unsubscribed"})
+		return
+	}
+	c.HTML(http.StatusOK, "consumer/pages/unsubscribe_success.html", gin.H{
+		"Title":           "Daily Digest Turned Off",
+		"Message":         "You will no longer receive the daily digest email. You can turn it back on in your notification preferences.",
+		"ShowResubscribe": false,
+	})
+}
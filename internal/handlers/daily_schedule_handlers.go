@@ -10,8 +10,8 @@ import (
 
 	"chrisgross-ctrl-project/internal/models"
 	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
 	"github.com/gorilla/mux"
-	"gorm.io/gorm"
 )
 
 // DailyScheduleHandlers handles daily schedule API endpoints
@@ -20,12 +20,76 @@ type DailyScheduleHandlers struct {
 }
 
 // NewDailyScheduleHandlers creates new daily schedule handlers
-func NewDailyScheduleHandlers(db *gorm.DB) *DailyScheduleHandlers {
+func NewDailyScheduleHandlers(dailyScheduleService *services.DailyScheduleService) *DailyScheduleHandlers {
 	return &DailyScheduleHandlers{
-		dailyScheduleService: services.NewDailyScheduleService(db),
+		dailyScheduleService: dailyScheduleService,
 	}
 }
 
+// GetAgentDailyPlan handles GET /api/v1/schedule/daily?date=YYYY-MM-DD,
+// returning the signed-in agent's generated plan for the date (default today)
+func (dsh *DailyScheduleHandlers) GetAgentDailyPlan(c *gin.Context) {
+	agentID, date, ok := dsh.planRequest(c)
+	if !ok {
+		return
+	}
+
+	plan, err := dsh.dailyScheduleService.GetDailyPlan(agentID, date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get daily plan", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"plan": plan})
+}
+
+// RegenerateDailyPlan handles POST /api/v1/schedule/daily?date=YYYY-MM-DD,
+// rebuilding the agent's plan from current showings and tasks
+func (dsh *DailyScheduleHandlers) RegenerateDailyPlan(c *gin.Context) {
+	agentID, date, ok := dsh.planRequest(c)
+	if !ok {
+		return
+	}
+
+	plan, err := dsh.dailyScheduleService.RegenerateDailyPlan(agentID, date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to regenerate daily plan", "details": err.Error()})
+		return
+	}
+	log.Printf("📅 Daily plan for %s on %s regenerated", agentID, plan.Date)
+	c.JSON(http.StatusOK, gin.H{"success": true, "plan": plan})
+}
+
+// planRequest reads the agent and date of a daily plan request. Dates are
+// business days in the business time zone; team admins may pass agent_id to
+// see another agent's plan.
+func (dsh *DailyScheduleHandlers) planRequest(c *gin.Context) (string, time.Time, bool) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return "", time.Time{}, false
+	}
+	agentID := admin.ID
+	if requested := c.Query("agent_id"); requested != "" && requested != admin.ID {
+		if !models.IsTeamAdminRole(admin.Role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Viewing another agent's schedule requires a team admin"})
+			return "", time.Time{}, false
+		}
+		agentID = requested
+	}
+
+	date := time.Now().In(services.BusinessLocation())
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", dateStr, services.BusinessLocation())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+			return "", time.Time{}, false
+		}
+		date = parsed
+	}
+	return agentID, date, true
+}
+
 // RegisterRoutes registers all daily schedule routes
 func (dsh *DailyScheduleHandlers) RegisterRoutes(mux *http.ServeMux) {
 	// Daily schedule routes
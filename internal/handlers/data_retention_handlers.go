@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// DataRetentionHandlers exposes the data retention policy and its audit trail
+type DataRetentionHandlers struct {
+	retention *services.DataRetentionService
+}
+
+// NewDataRetentionHandlers creates data retention handlers
+func NewDataRetentionHandlers(retention *services.DataRetentionService) *DataRetentionHandlers {
+	return &DataRetentionHandlers{retention: retention}
+}
+
+// GetRetentionPreview handles GET /api/v1/data-retention/preview, reporting what
+// the policy would purge and anonymize if enforced now
+func (h *DataRetentionHandlers) GetRetentionPreview(c *gin.Context) {
+	report, err := h.retention.Preview()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preview data retention", "details": err.Error()})
+		return
+	}
+
+	settings := h.retention.Settings()
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"policy": gin.H{
+			"event_retention_days":   settings.EventRetentionDays,
+			"lead_inactivity_months": settings.LeadInactivityMonths,
+			"dry_run":                settings.DryRun,
+		},
+		"report": report,
+	})
+}
+
+// GetRetentionLogs handles GET /api/v1/data-retention/logs, listing the latest
+// ?limit= purges and anonymizations (default 100)
+func (h *DataRetentionHandlers) GetRetentionLogs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit", "details": "limit must be between 1 and 1000"})
+		return
+	}
+
+	logs, err := h.retention.RecentLogs(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load data retention logs", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "logs": logs, "count": len(logs)})
+}
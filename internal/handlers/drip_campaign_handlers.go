@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+// eligibleLeadQuery selects the admin's leads that can be added to a campaign
+func (h *LeadReengagementHandler) eligibleLeadQuery(admin *models.AdminUser, segments []string) *gorm.DB {
+	query := h.db.Model(&models.LeadReengagement{}).Scopes(models.LeadsVisibleTo(admin)).Where(
+		"has_email = ? AND email_valid = ? AND risk_level != ? AND segment != ? AND hard_bounce = ? AND previous_unsubscribe = ? AND campaign_status = ?",
+		true, true, models.RiskHigh, models.SegmentSuppressed, false, false, models.CampaignPending,
+	)
+	if len(segments) > 0 {
+		query = query.Where("segment IN ?", segments)
+	}
+	return query
+}
+
+// dripCampaignParam loads the drip campaign named by :id, writing the error response on failure
+func (h *LeadReengagementHandler) dripCampaignParam(c *gin.Context) (*models.DripCampaign, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid drip campaign ID",
+		})
+		return nil, false
+	}
+
+	campaign, err := h.drips.GetCampaign(uint(id))
+	if err != nil {
+		h.respondDripError(c, err)
+		return nil, false
+	}
+	return campaign, true
+}
+
+func (h *LeadReengagementHandler) respondDripError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrDripCampaignNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Drip campaign not found",
+		})
+	case errors.Is(err, services.ErrDripCampaignStopped):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Drip campaign is stopped",
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidDripCampaign):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid drip campaign",
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Drip campaign operation failed",
+			"details": err.Error(),
+		})
+	}
+}
+
+// GetDripCampaigns lists drip campaigns with their steps
+// GET /api/v1/reengagement/drip-campaigns
+func (h *LeadReengagementHandler) GetDripCampaigns(c *gin.Context) {
+	query := h.db.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Order("created_at DESC")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var campaigns []models.DripCampaign
+	if err := query.Find(&campaigns).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve drip campaigns",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"drip_campaigns": campaigns,
+		"total":          len(campaigns),
+	})
+}
+
+// CreateDripCampaign creates a drip campaign from an ordered list of templates
+// POST /api/v1/reengagement/drip-campaigns
+func (h *LeadReengagementHandler) CreateDripCampaign(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Name        string                   `json:"name" binding:"required"`
+		Description string                   `json:"description"`
+		DailyLimit  int                      `json:"daily_limit"`
+		Steps       []services.DripStepInput `json:"steps" binding:"required,min=1,dive"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	campaign, err := h.drips.CreateCampaign(request.Name, request.Description, request.DailyLimit, request.Steps, admin.ID)
+	if err != nil {
+		h.respondDripError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":       "Drip campaign created successfully",
+		"drip_campaign": campaign,
+	})
+}
+
+// GetDripCampaign returns a drip campaign with per-step and per-status enrollment counts
+// GET /api/v1/reengagement/drip-campaigns/:id
+func (h *LeadReengagementHandler) GetDripCampaign(c *gin.Context) {
+	campaign, ok := h.dripCampaignParam(c)
+	if !ok {
+		return
+	}
+
+	var counts []struct {
+		Status      string `json:"status"`
+		CurrentStep int    `json:"current_step"`
+		Count       int64  `json:"count"`
+	}
+	h.db.Model(&models.DripEnrollment{}).
+		Select("status, current_step, COUNT(*) AS count").
+		Where("drip_campaign_id = ?", campaign.ID).
+		Group("status, current_step").
+		Order("current_step").
+		Scan(&counts)
+
+	c.JSON(http.StatusOK, gin.H{
+		"drip_campaign": campaign,
+		"enrollments":   counts,
+	})
+}
+
+// EnrollDripCampaign enrolls eligible leads, either by ID or by segment
+// POST /api/v1/reengagement/drip-campaigns/:id/enroll
+func (h *LeadReengagementHandler) EnrollDripCampaign(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+	campaign, ok := h.dripCampaignParam(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		LeadIDs                []uint   `json:"lead_ids"`
+		Segments               []string `json:"segments"`
+		MaxVolume              int      `json:"max_volume"`
+		ExcludeOpenApprovals   *bool    `json:"exclude_open_approvals"`
+		ExcludeActivePipelines *bool    `json:"exclude_active_pipelines"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	query := h.eligibleLeadQuery(admin, request.Segments)
+	if len(request.LeadIDs) > 0 {
+		query = query.Where("id IN ?", request.LeadIDs)
+	}
+	query, suppressed, err := h.suppressions.Apply(campaign.Name, query,
+		suppressionOptions(request.ExcludeOpenApprovals, request.ExcludeActivePipelines))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to apply campaign suppressions",
+			"details": err.Error(),
+		})
+		return
+	}
+	query, blocked, err := h.excludeUnsafeLeads(campaign.Name, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to apply lead safety checks",
+			"details": err.Error(),
+		})
+		return
+	}
+	if request.MaxVolume > 0 {
+		query = query.Limit(request.MaxVolume)
+	}
+
+	result, err := h.drips.Enroll(campaign.ID, query)
+	if err != nil {
+		h.respondDripError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Leads enrolled successfully",
+		"drip_campaign_id": campaign.ID,
+		"enrolled":         result.Enrolled,
+		"skipped":          result.Skipped,
+		"suppressed":       suppressed,
+		"blocked":          blocked,
+	})
+}
+
+// PauseDripCampaign holds every enrolled lead at its current step
+// POST /api/v1/reengagement/drip-campaigns/:id/pause
+func (h *LeadReengagementHandler) PauseDripCampaign(c *gin.Context) {
+	h.changeDripCampaign(c, "paused", h.drips.PauseCampaign)
+}
+
+// ResumeDripCampaign continues a paused drip campaign
+// POST /api/v1/reengagement/drip-campaigns/:id/resume
+func (h *LeadReengagementHandler) ResumeDripCampaign(c *gin.Context) {
+	h.changeDripCampaign(c, "resumed", h.drips.ResumeCampaign)
+}
+
+// StopDripCampaign ends a drip campaign for every enrolled lead
+// POST /api/v1/reengagement/drip-campaigns/:id/stop
+func (h *LeadReengagementHandler) StopDripCampaign(c *gin.Context) {
+	h.changeDripCampaign(c, "stopped", h.drips.StopCampaign)
+}
+
+// changeDripCampaign applies a status change; only the creator or a team admin may change a campaign
+func (h *LeadReengagementHandler) changeDripCampaign(c *gin.Context, action string, change func(uint) error) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+	campaign, ok := h.dripCampaignParam(c)
+	if !ok {
+		return
+	}
+	if campaign.CreatedBy != admin.ID && !models.IsTeamAdminRole(admin.Role) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Only the campaign creator or a team admin can change this drip campaign",
+		})
+		return
+	}
+
+	if err := change(campaign.ID); err != nil {
+		h.respondDripError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Drip campaign " + action + " successfully",
+		"drip_campaign_id": campaign.ID,
+	})
+}
+
+// GetDripEnrollments lists enrolled leads and the step each is on
+// GET /api/v1/reengagement/drip-campaigns/:id/enrollments
+func (h *LeadReengagementHandler) GetDripEnrollments(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+	campaign, ok := h.dripCampaignParam(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	enrollments, total, err := h.drips.ListEnrollments(campaign.ID, models.LeadsVisibleTo(admin), c.Query("status"), limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve enrollments",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enrollments": enrollments,
+		"total_steps": len(campaign.Steps),
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
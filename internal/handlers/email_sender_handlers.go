@@ -3,13 +3,18 @@ package handlers
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"chrisgross-ctrl-project/internal/addressutil"
 	"chrisgross-ctrl-project/internal/models"
 	"chrisgross-ctrl-project/internal/services"
 	"chrisgross-ctrl-project/internal/utils"
@@ -20,6 +25,27 @@ type EmailSenderHandlers struct {
 	db                    *gorm.DB
 	emailProcessor        *services.EmailProcessor
 	senderValidationService *services.EmailSenderValidationService
+	leadMatcher             *services.FUBLeadMatcher
+	attachmentService       *services.EmailAttachmentService
+
+	reprocessJobs  map[string]*EmailReprocessJob
+	reprocessMutex sync.RWMutex
+}
+
+// reprocessAsyncThreshold is the batch size above which reprocessing runs in the background
+const reprocessAsyncThreshold = 50
+
+// reprocessJobRetention is how long a finished reprocess job stays available for status checks
+const reprocessJobRetention = time.Hour
+
+// EmailReprocessJob tracks a background reprocess-failed run
+type EmailReprocessJob struct {
+	JobID      string                    `json:"job_id"`
+	Status     string                    `json:"status"` // running, completed
+	Total      int                       `json:"total"`
+	Summary    services.ReprocessSummary `json:"summary"`
+	StartedAt  time.Time                 `json:"started_at"`
+	FinishedAt *time.Time                `json:"finished_at,omitempty"`
 }
 
 // NewEmailSenderHandlers creates new email sender handlers
@@ -28,6 +54,9 @@ func NewEmailSenderHandlers(db *gorm.DB) *EmailSenderHandlers {
 		db:                    db,
 		emailProcessor:        services.NewEmailProcessor(db),
 		senderValidationService: services.NewEmailSenderValidationService(db),
+		leadMatcher:             services.NewFUBLeadMatcher(db),
+		attachmentService:       services.NewEmailAttachmentService(db),
+		reprocessJobs:           make(map[string]*EmailReprocessJob),
 	}
 }
 
@@ -46,6 +75,8 @@ func (h *EmailSenderHandlers) CreateTrustedSender(c *gin.Context) {
 		BusinessPurpose string `json:"business_purpose"`
 		Notes           string `json:"notes"`
 		ParsingTemplate string `json:"parsing_template"`
+		AuthPolicy      string `json:"auth_policy"` // none, relaxed (default), strict
+		Restore         bool   `json:"restore"` // Restore a previously deleted sender with this email
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -53,12 +84,11 @@ func (h *EmailSenderHandlers) CreateTrustedSender(c *gin.Context) {
 		return
 	}
 
-	// Check if sender already exists
-	var existingSender models.TrustedEmailSender
-	if err := h.db.Where("sender_email = ?", request.SenderEmail).First(&existingSender).Error; err == nil {
-		utils.ErrorResponse(c, http.StatusConflict, "Email sender already exists", nil)
+	template, ok := bindParsingTemplate(c, request.ParsingTemplate)
+	if !ok {
 		return
 	}
+	request.ParsingTemplate = template
 
 	// Create new trusted sender
 	sender := models.TrustedEmailSender{
@@ -73,6 +103,7 @@ func (h *EmailSenderHandlers) CreateTrustedSender(c *gin.Context) {
 		BusinessPurpose: request.BusinessPurpose,
 		Notes:           request.Notes,
 		ParsingTemplate: request.ParsingTemplate,
+		AuthPolicy:      request.AuthPolicy,
 		AddedBy:         c.GetString("admin_username"), // From auth middleware
 		IsVerified:      true, // Auto-verify for now, could require manual verification
 	}
@@ -87,10 +118,32 @@ func (h *EmailSenderHandlers) CreateTrustedSender(c *gin.Context) {
 	now := time.Now()
 	sender.ApprovalDate = &now
 
-	// Save to database
-	if err := h.db.Create(&sender).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create trusted sender", err)
+	// Check if sender already exists, including soft-deleted senders
+	message := "Trusted email sender created successfully"
+	existingSender, err := models.FindTrustedSenderIncludingDeleted(h.db, request.SenderEmail)
+	switch {
+	case err == nil && !existingSender.DeletedAt.Valid:
+		utils.ErrorResponse(c, http.StatusConflict, "Email sender already exists", nil)
+		return
+	case err == nil && !request.Restore:
+		utils.ErrorResponse(c, http.StatusConflict, "Email sender was previously deleted",
+			fmt.Errorf("resend with restore=true to restore sender %d with these settings", existingSender.ID))
+		return
+	case err == nil:
+		if err := models.RestoreTrustedSender(h.db, existingSender, sender); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to restore trusted sender", err)
+			return
+		}
+		sender = *existingSender
+		message = "Trusted email sender restored successfully"
+	case err != gorm.ErrRecordNotFound:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to check for existing sender", err)
 		return
+	default:
+		if err := h.db.Create(&sender).Error; err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create trusted sender", err)
+			return
+		}
 	}
 
 	// Create default parsing rule if template provided
@@ -99,7 +152,7 @@ func (h *EmailSenderHandlers) CreateTrustedSender(c *gin.Context) {
 	}
 
 	utils.SuccessResponse(c, gin.H{
-		"message": "Trusted email sender created successfully",
+		"message": message,
 		"sender":  sender,
 	})
 }
@@ -207,6 +260,7 @@ func (h *EmailSenderHandlers) UpdateTrustedSender(c *gin.Context) {
 		BusinessPurpose string `json:"business_purpose"`
 		Notes           string `json:"notes"`
 		ParsingTemplate string `json:"parsing_template"`
+		AuthPolicy      string `json:"auth_policy"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -214,6 +268,12 @@ func (h *EmailSenderHandlers) UpdateTrustedSender(c *gin.Context) {
 		return
 	}
 
+	template, ok := bindParsingTemplate(c, request.ParsingTemplate)
+	if !ok {
+		return
+	}
+	request.ParsingTemplate = template
+
 	// Update fields
 	sender.SenderEmail = request.SenderEmail
 	sender.SenderName = request.SenderName
@@ -226,6 +286,9 @@ func (h *EmailSenderHandlers) UpdateTrustedSender(c *gin.Context) {
 	sender.BusinessPurpose = request.BusinessPurpose
 	sender.Notes = request.Notes
 	sender.ParsingTemplate = request.ParsingTemplate
+	if request.AuthPolicy != "" {
+		sender.AuthPolicy = request.AuthPolicy
+	}
 
 	// Validate updated data
 	if err := sender.Validate(); err != nil {
@@ -282,6 +345,8 @@ func (h *EmailSenderHandlers) ProcessIncomingEmail(c *gin.Context) {
 		Content    string            `json:"content" binding:"required"`
 		Headers    map[string]string `json:"headers"`
 		ReceivedAt time.Time         `json:"received_at"`
+
+		Attachments []services.EmailAttachmentInput `json:"attachments"`
 	}
 
 	if err := c.ShouldBindJSON(&emailRequest); err != nil {
@@ -289,6 +354,13 @@ func (h *EmailSenderHandlers) ProcessIncomingEmail(c *gin.Context) {
 		return
 	}
 
+	// Reject the whole email before storing anything if an attachment is unsafe
+	attachments, err := h.attachmentService.ValidateAttachments(emailRequest.Attachments)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid attachment", err)
+		return
+	}
+
 	// Set received time if not provided
 	if emailRequest.ReceivedAt.IsZero() {
 		emailRequest.ReceivedAt = time.Now()
@@ -317,6 +389,30 @@ func (h *EmailSenderHandlers) ProcessIncomingEmail(c *gin.Context) {
 		return
 	}
 
+	// A trusted From address is only processed automatically when the email's
+	// SPF/DKIM/DMARC results show it really came from that sender's domain
+	authCheck := h.senderValidationService.VerifySenderAuthentication(trustedSender, emailRequest.From, emailRequest.Headers)
+	if !authCheck.Passed {
+		incomingEmailID, err := h.holdEmailForReview(emailRequest.From, emailRequest.To, emailRequest.Subject, emailRequest.Content, emailRequest.ReceivedAt, trustedSender, authCheck)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to store email for review", err)
+			return
+		}
+		if err := h.attachmentService.SaveAttachments(incomingEmailID, attachments); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to store attachments", err)
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{
+			"success": true,
+			"data": gin.H{
+				"message":           "Email held for review: sender authentication failed",
+				"incoming_email_id": incomingEmailID,
+				"authentication":    authCheck,
+			},
+		})
+		return
+	}
+
 	// Process email using trusted sender configuration - convert struct type
 	result, err := h.processEmailWithSender(struct {
 		From       string
@@ -337,6 +433,11 @@ func (h *EmailSenderHandlers) ProcessIncomingEmail(c *gin.Context) {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process email", err)
 		return
 	}
+	if err := h.attachmentService.SaveAttachments(result["incoming_email_id"].(uint), attachments); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to store attachments", err)
+		return
+	}
+	result["attachments"] = len(attachments)
 
 	// Log processing result
 	// Log processing result - convert struct type
@@ -364,11 +465,59 @@ func (h *EmailSenderHandlers) ProcessIncomingEmail(c *gin.Context) {
 	})
 }
 
-// TestEmailParsing tests parsing configuration with sample content
+// GetIncomingEmailAttachments lists the attachments received with an email
+// GET /api/v1/email/incoming/:id/attachments
+func (h *EmailSenderHandlers) GetIncomingEmailAttachments(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid email ID", err)
+		return
+	}
+
+	byEmail, err := models.AttachmentsForEmails(h.db, []uint{uint(id)})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch attachments", err)
+		return
+	}
+	attachments := byEmail[uint(id)]
+	if attachments == nil {
+		attachments = []models.IncomingEmailAttachment{}
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"attachments": attachments,
+		"total":       len(attachments),
+	})
+}
+
+// DownloadEmailAttachment serves an attachment's file content
+// GET /api/v1/email/attachments/:id
+func (h *EmailSenderHandlers) DownloadEmailAttachment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid attachment ID", err)
+		return
+	}
+
+	attachment, err := h.attachmentService.GetAttachment(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Attachment not found", err)
+		return
+	}
+
+	// Always download rather than render, so a crafted file can't run in the admin's browser
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, attachment.ContentType, attachment.Data)
+}
+
+// TestEmailParsing runs a parsing template against sample content. Without a
+// template, the active parsing rule for the email type is used.
 // POST /api/v1/email/test-parsing
 func (h *EmailSenderHandlers) TestEmailParsing(c *gin.Context) {
 	var testRequest struct {
 		EmailContent    string `json:"email_content" binding:"required"`
+		EmailSubject    string `json:"email_subject"`
 		EmailType       string `json:"email_type" binding:"required"`
 		ParsingTemplate string `json:"parsing_template"`
 	}
@@ -379,23 +528,53 @@ func (h *EmailSenderHandlers) TestEmailParsing(c *gin.Context) {
 	}
 
 	// Parse template
-	var template map[string]interface{}
+	var template *models.ParsingTemplate
+	var err error
 	if testRequest.ParsingTemplate != "" {
-		if err := json.Unmarshal([]byte(testRequest.ParsingTemplate), &template); err != nil {
-			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid parsing template", err)
-			return
-		}
+		template, err = models.ParseParsingTemplate(testRequest.ParsingTemplate)
+	} else {
+		template, err = h.parsingTemplateForType(testRequest.EmailType)
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid parsing template", err)
+		return
 	}
 
 	// Run parsing test
-	result := h.runParsingTest(testRequest.EmailContent, testRequest.EmailType, template)
+	result := template.Extract(testRequest.EmailSubject, testRequest.EmailContent)
 
 	utils.SuccessResponse(c, gin.H{
 		"test_result": result,
+		"template":    template,
 		"timestamp":   time.Now(),
 	})
 }
 
+// parsingTemplateForType rebuilds the template of the active parsing rule for an email type
+func (h *EmailSenderHandlers) parsingTemplateForType(emailType string) (*models.ParsingTemplate, error) {
+	var rule models.EmailProcessingRule
+	if err := h.db.Where("email_type = ? AND is_active = ?", emailType, true).
+		Order("updated_at DESC").
+		First(&rule).Error; err != nil {
+		return nil, fmt.Errorf("no parsing_template given and no active parsing rule for %s", emailType)
+	}
+
+	raw, _ := json.Marshal(map[string]interface{}{
+		"subjectPattern": rule.SubjectPattern,
+		"bodyPatterns":   json.RawMessage(orEmptyJSONArray(rule.BodyPatterns)),
+		"fields":         json.RawMessage(orEmptyJSONArray(rule.RequiredFields)),
+	})
+	return models.ParseParsingTemplate(string(raw))
+}
+
+// orEmptyJSONArray returns "[]" for an empty stored JSON array column
+func orEmptyJSONArray(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "[]"
+	}
+	return value
+}
+
 // GetEmailProcessingStats returns email processing statistics  
 // GET /api/v1/email/processing-stats
 func (h *EmailSenderHandlers) GetEmailProcessingStats(c *gin.Context) {
@@ -418,6 +597,109 @@ func (h *EmailSenderHandlers) GetEmailProcessingStats(c *gin.Context) {
 	utils.SuccessResponse(c, stats)
 }
 
+// ReprocessFailedEmails re-runs parsing for failed and review-pending emails
+// POST /api/v1/email/reprocess-failed
+func (h *EmailSenderHandlers) ReprocessFailedEmails(c *gin.Context) {
+	var request struct {
+		SenderID uint       `json:"sender_id"`
+		Since    *time.Time `json:"since"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil && err != io.EOF {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request data", err)
+		return
+	}
+
+	filter := services.ReprocessFilter{Since: request.Since}
+	if request.SenderID != 0 {
+		var sender models.TrustedEmailSender
+		if err := h.db.First(&sender, request.SenderID).Error; err != nil {
+			utils.ErrorResponse(c, http.StatusNotFound, "Email sender not found", err)
+			return
+		}
+		filter.SenderEmail = sender.SenderEmail
+	}
+
+	emails, err := h.emailProcessor.FindReprocessableEmails(filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to load failed emails", err)
+		return
+	}
+
+	if len(emails) <= reprocessAsyncThreshold {
+		summary := h.emailProcessor.ReprocessEmails(emails, nil)
+		utils.SuccessResponse(c, gin.H{
+			"status":  "completed",
+			"total":   len(emails),
+			"summary": summary,
+		})
+		return
+	}
+
+	job := &EmailReprocessJob{
+		JobID:     fmt.Sprintf("reprocess_%d", time.Now().UnixNano()),
+		Status:    "running",
+		Total:     len(emails),
+		StartedAt: time.Now(),
+	}
+
+	h.reprocessMutex.Lock()
+	h.pruneReprocessJobs(job.StartedAt)
+	h.reprocessJobs[job.JobID] = job
+	h.reprocessMutex.Unlock()
+
+	go func() {
+		summary := h.emailProcessor.ReprocessEmails(emails, func(progress services.ReprocessSummary) {
+			h.reprocessMutex.Lock()
+			job.Summary = progress
+			h.reprocessMutex.Unlock()
+		})
+
+		finishedAt := time.Now()
+		h.reprocessMutex.Lock()
+		job.Summary = summary
+		job.Status = "completed"
+		job.FinishedAt = &finishedAt
+		h.reprocessMutex.Unlock()
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"message": "Reprocessing started",
+		"job_id":  job.JobID,
+		"total":   job.Total,
+	})
+}
+
+// GetReprocessJobStatus returns the progress of a background reprocess run
+// GET /api/v1/email/reprocess-failed/:job_id
+func (h *EmailSenderHandlers) GetReprocessJobStatus(c *gin.Context) {
+	h.reprocessMutex.RLock()
+	job, exists := h.reprocessJobs[c.Param("job_id")]
+	var snapshot EmailReprocessJob
+	if exists {
+		snapshot = *job
+	}
+	h.reprocessMutex.RUnlock()
+
+	if !exists {
+		utils.ErrorResponse(c, http.StatusNotFound, "Reprocess job not found", nil)
+		return
+	}
+
+	utils.SuccessResponse(c, snapshot)
+}
+
+// pruneReprocessJobs drops jobs that finished more than reprocessJobRetention
+// ago. The caller must hold reprocessMutex.
+func (h *EmailSenderHandlers) pruneReprocessJobs(now time.Time) {
+	for jobID, job := range h.reprocessJobs {
+		if job.FinishedAt != nil && now.Sub(*job.FinishedAt) > reprocessJobRetention {
+			delete(h.reprocessJobs, jobID)
+		}
+	}
+}
+
 // AdminEmailSendersPage renders the email sender management page
 func (h *EmailSenderHandlers) AdminEmailSendersPage(c *gin.Context) {
 	c.HTML(http.StatusOK, "admin-email-senders.html", gin.H{
@@ -427,6 +709,20 @@ func (h *EmailSenderHandlers) AdminEmailSendersPage(c *gin.Context) {
 
 // Helper methods
 
+// bindParsingTemplate validates an optional parsing template, writing a 400 with
+// the specific problem if it is invalid, and returns it as normalized JSON
+func bindParsingTemplate(c *gin.Context, raw string) (string, bool) {
+	if strings.TrimSpace(raw) == "" {
+		return "", true
+	}
+	template, err := models.ParseParsingTemplate(raw)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid parsing template", err)
+		return "", false
+	}
+	return template.String(), true
+}
+
 func (h *EmailSenderHandlers) createDefaultParsingRule(senderID uint, emailType, parsingTemplate string) {
 	// Create default parsing rule for the sender
 	rule := models.EmailProcessingRule{
@@ -438,21 +734,17 @@ func (h *EmailSenderHandlers) createDefaultParsingRule(senderID uint, emailType,
 		CreatedBy:       "system",
 	}
 
-	// Parse template to extract patterns
-	var template map[string]interface{}
-	if err := json.Unmarshal([]byte(parsingTemplate), &template); err == nil {
-		if subjectPattern, ok := template["subjectPattern"].(string); ok {
-			rule.SubjectPattern = subjectPattern
-		}
-		if bodyPatterns, ok := template["bodyPatterns"].([]interface{}); ok {
-			bodyPatternsJSON, _ := json.Marshal(bodyPatterns)
-			rule.BodyPatterns = string(bodyPatternsJSON)
-		}
-		if fields, ok := template["fields"].([]interface{}); ok {
-			fieldsJSON, _ := json.Marshal(fields)
-			rule.RequiredFields = string(fieldsJSON)
-		}
+	// Templates are validated when the sender is saved
+	template, err := models.ParseParsingTemplate(parsingTemplate)
+	if err != nil {
+		log.Printf("⚠️ Skipping default parsing rule for sender %d: %v", senderID, err)
+		return
 	}
+	rule.SubjectPattern = template.SubjectPattern
+	bodyPatternsJSON, _ := json.Marshal(template.BodyPatterns)
+	rule.BodyPatterns = string(bodyPatternsJSON)
+	fieldsJSON, _ := json.Marshal(template.Fields)
+	rule.RequiredFields = string(fieldsJSON)
 
 	h.db.Create(&rule)
 }
@@ -584,25 +876,59 @@ func (h *EmailSenderHandlers) processApplicationNotification(extractedData map[s
 		return "insufficient_data", "Could not extract required application data"
 	}
 
-	// Try to match to existing FUB lead
-	var fubLead models.Lead
-	err := h.db.Where("LOWER(first_name || ' ' || last_name) LIKE LOWER(?) OR email = ?", 
-		"%"+applicantName+"%", applicantEmail).First(&fubLead).Error
+	// Score existing FUB leads against the applicant; only confident, unambiguous
+	// matches are linked automatically
+	match, err := h.leadMatcher.Match(applicantName, applicantEmail)
+	if err != nil {
+		log.Printf("⚠️ FUB lead matching failed for %s: %v", applicantName, err)
+		return "manual_matching_required", fmt.Sprintf("Application from %s requires manual FUB lead matching", applicantName)
+	}
+	extractedData["fub_match_confidence"] = match.Confidence
+	extractedData["fub_match_decision"] = match.Decision
 
-	if err == nil {
-		// Found matching FUB lead - create application record
+	switch match.Decision {
+	case services.FUBLeadMatchAutoLink:
 		application := models.Approval{
-			ApprovalType:    "rental_application",
-			Status:          "pending",
-			ApplicantName:   applicantName,
-			PropertyAddress: propertyAddress,
-			Notes:           fmt.Sprintf("Application notification received from %s", email.FromEmail),
+			ApprovalType:       "rental_application",
+			Status:             "pending",
+			ApplicantName:      applicantName,
+			PropertyAddress:    propertyAddress,
+			FUBLeadID:          match.Lead.FUBLeadID,
+			FUBMatchConfidence: match.Confidence,
+			Notes:              fmt.Sprintf("Application notification received from %s", email.FromEmail),
 		}
 
 		if err := h.db.Create(&application).Error; err == nil {
 			// Update FUB lead with application status
-			h.updateFUBLeadApplicationStatus(fubLead.FUBLeadID, "application_submitted", propertyAddress)
-			return "application_matched_to_fub_lead", fmt.Sprintf("Matched application from %s to existing FUB lead %s", applicantName, fubLead.FUBLeadID)
+			h.updateFUBLeadApplicationStatus(match.Lead.FUBLeadID, "application_submitted", propertyAddress)
+			return "application_matched_to_fub_lead", fmt.Sprintf("Matched application from %s to existing FUB lead %s (confidence %.2f)",
+				applicantName, match.Lead.FUBLeadID, match.Confidence)
+		}
+
+	case services.FUBLeadMatchReview:
+		// Record the application unlinked, with the candidates for a person to confirm
+		notes := []string{fmt.Sprintf("Application notification received from %s. Possible FUB lead matches:", email.FromEmail)}
+		for _, candidate := range match.Candidates {
+			notes = append(notes, fmt.Sprintf("- %s <%s> (FUB %s, confidence %.2f)",
+				candidate.Name, candidate.Email, candidate.FUBLeadID, candidate.Confidence))
+		}
+		application := models.Approval{
+			ApprovalType:       "rental_application",
+			Status:             "under_review",
+			Priority:           "high",
+			ApplicantName:      applicantName,
+			PropertyAddress:    propertyAddress,
+			FUBMatchConfidence: match.Confidence,
+			Notes:              strings.Join(notes, "\n"),
+		}
+
+		if err := h.db.Create(&application).Error; err == nil {
+			reason := "low confidence"
+			if match.Ambiguous {
+				reason = "ambiguous"
+			}
+			return "fub_match_review_required", fmt.Sprintf("Application from %s has a %s FUB lead match (confidence %.2f); review task created",
+				applicantName, reason, match.Confidence)
 		}
 	}
 
@@ -646,14 +972,15 @@ func (h *EmailSenderHandlers) processPreListingAlert(extractedData map[string]in
 func (h *EmailSenderHandlers) processVendorCompletion(extractedData map[string]interface{}, email *models.IncomingEmail) (string, string) {
 	propertyAddress, _ := extractedData["property_address"].(string)
 	serviceType, _ := extractedData["service_type"].(string)
+	normalizedAddress := addressutil.Normalize(propertyAddress)
 
-	if propertyAddress == "" {
+	if normalizedAddress == "" {
 		return "insufficient_data", "Could not extract property address from vendor notification"
 	}
 
 	// Find related pre-listing item
 	var preListingItem models.PreListingItem
-	err := h.db.Where("address LIKE ?", "%"+propertyAddress+"%").First(&preListingItem).Error
+	err := h.db.Where("normalized_address = ?", normalizedAddress).First(&preListingItem).Error
 
 	if err != nil {
 		return "no_prelisting_match", fmt.Sprintf("No pre-listing item found for %s", propertyAddress)
@@ -688,15 +1015,16 @@ func (h *EmailSenderHandlers) processLeaseUpdate(extractedData map[string]interf
 	tenantName, _ := extractedData["tenant_name"].(string)
 	propertyAddress, _ := extractedData["property_address"].(string)
 	leaseStatus, _ := extractedData["lease_status"].(string)
+	normalizedAddress := addressutil.Normalize(propertyAddress)
 
-	if tenantName == "" || propertyAddress == "" {
+	if tenantName == "" || normalizedAddress == "" {
 		return "insufficient_data", "Could not extract tenant or property information"
 	}
 
 	// Try to find related application and update status
 	var application models.Approval
-	err := h.db.Where("applicant_name LIKE ? AND property_address LIKE ?", 
-		"%"+tenantName+"%", "%"+propertyAddress+"%").First(&application).Error
+	err := h.db.Where("applicant_name LIKE ? AND normalized_property_address = ?",
+		"%"+tenantName+"%", normalizedAddress).First(&application).Error
 
 	if err == nil {
 		// Update application status based on lease status
@@ -749,6 +1077,29 @@ func (h *EmailSenderHandlers) logUnstrustedEmail(emailRequest struct {
 	fmt.Printf("⚠️ Untrusted email received from %s: %s\n", emailRequest.From, emailRequest.Subject)
 }
 
+// holdEmailForReview stores an email from a trusted address that failed sender
+// authentication as requires_review, without routing it to any system
+func (h *EmailSenderHandlers) holdEmailForReview(from, to, subject, content string, receivedAt time.Time, sender *models.TrustedEmailSender, authCheck services.SenderAuthCheck) (uint, error) {
+	incomingEmail := models.IncomingEmail{
+		FromEmail:        from,
+		ToEmail:          to,
+		Subject:          subject,
+		Content:          content,
+		ReceivedAt:       receivedAt,
+		EmailType:        sender.EmailType,
+		ProcessingStatus: models.ProcessingStatusRequiresReview,
+	}
+	if authJSON, err := json.Marshal(gin.H{"authentication": authCheck}); err == nil {
+		incomingEmail.ExtractedData = string(authJSON)
+	}
+
+	if err := h.db.Create(&incomingEmail).Error; err != nil {
+		return 0, fmt.Errorf("failed to create incoming email record: %v", err)
+	}
+	log.Printf("🚨 Email from trusted sender %s held for review: %s", from, authCheck.Reason)
+	return incomingEmail.ID, nil
+}
+
 func (h *EmailSenderHandlers) logEmailProcessing(emailRequest struct {
 	From       string
 	To         string
@@ -860,20 +1211,6 @@ func (h *EmailSenderHandlers) extractEffectiveDate(content string) *time.Time {
 	return nil
 }
 
-func (h *EmailSenderHandlers) runParsingTest(emailContent, emailType string, template map[string]interface{}) map[string]interface{} {
-	// Mock parsing test - in production this would use actual parsing logic
-	return map[string]interface{}{
-		"extracted_fields": map[string]string{
-			"applicant_name":   "Sarah Martinez",
-			"applicant_email":  "sarah.martinez@email.com", 
-			"property_address": "123 Heights Boulevard",
-		},
-		"confidence":       0.92,
-		"warnings":        []string{},
-		"suggestions":     []string{"Parsing successful with high confidence"},
-	}
-}
-
 // Statistics helper methods
 
 func (h *EmailSenderHandlers) getTotalSenders() int64 {
@@ -950,5 +1287,7 @@ func RegisterEmailSenderRoutes(r *gin.Engine, db *gorm.DB) {
 		api.POST("/process-incoming", handlers.ProcessIncomingEmail)
 		api.POST("/test-parsing", handlers.TestEmailParsing)
 		api.GET("/processing-stats", handlers.GetEmailProcessingStats)
+		api.POST("/reprocess-failed", handlers.ReprocessFailedEmails)
+		api.GET("/reprocess-failed/:job_id", handlers.GetReprocessJobStatus)
 	}
 }
\ No newline at end of file
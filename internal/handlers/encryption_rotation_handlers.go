@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+// EncryptionRotationHandlers exposes re-encryption of stored PII after a key rotation
+type EncryptionRotationHandlers struct {
+	encryptionManager *security.EncryptionManager
+	rotationService   *services.EncryptionRotationService
+}
+
+// NewEncryptionRotationHandlers creates new encryption rotation handlers
+func NewEncryptionRotationHandlers(encryptionManager *security.EncryptionManager, rotationService *services.EncryptionRotationService) *EncryptionRotationHandlers {
+	return &EncryptionRotationHandlers{
+		encryptionManager: encryptionManager,
+		rotationService:   rotationService,
+	}
+}
+
+// teamAdmin returns the authenticated admin if they may run key maintenance
+func (h *EncryptionRotationHandlers) teamAdmin(c *gin.Context) (*models.AdminUser, bool) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return nil, false
+	}
+	if !models.IsTeamAdminRole(admin.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Key rotation requires a team admin"})
+		return nil, false
+	}
+	return admin, true
+}
+
+// StartRotation re-encrypts a table's PII to the current key in the background.
+// Deploy the new ENCRYPTION_KEY with the old key in ENCRYPTION_PREVIOUS_KEYS first.
+// POST /api/v1/admin/encryption/rotate
+func (h *EncryptionRotationHandlers) StartRotation(c *gin.Context) {
+	admin, ok := h.teamAdmin(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Table string `json:"table" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+			"tables":  services.RotatableTables(),
+		})
+		return
+	}
+
+	if _, ok := services.EncryptedColumns[request.Table]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Unknown table",
+			"tables": services.RotatableTables(),
+		})
+		return
+	}
+
+	job, err := h.rotationService.StartRotation(request.Table, admin.Username)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Failed to start key rotation",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Key rotation started",
+		"job":     job,
+	})
+}
+
+// GetRotationJob reports the progress of a key rotation job
+// GET /api/v1/admin/encryption/rotate/:job_id
+func (h *EncryptionRotationHandlers) GetRotationJob(c *gin.Context) {
+	if _, ok := h.teamAdmin(c); !ok {
+		return
+	}
+
+	job, err := h.rotationService.GetRotationJob(c.Param("job_id"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Rotation job not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to retrieve rotation job",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job":            job,
+		"current_key_id": h.encryptionManager.CurrentKeyID(),
+	})
+}
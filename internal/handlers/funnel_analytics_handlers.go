@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// funnelDateLayout is the format of explicit funnel range dates
+const funnelDateLayout = "2006-01-02"
+
+// FunnelAnalyticsHandlers serves the visit → inquiry → application → closing funnel
+type FunnelAnalyticsHandlers struct {
+	funnel *services.FunnelAnalyticsService
+}
+
+// NewFunnelAnalyticsHandlers creates funnel analytics handlers
+func NewFunnelAnalyticsHandlers(funnel *services.FunnelAnalyticsService) *FunnelAnalyticsHandlers {
+	return &FunnelAnalyticsHandlers{funnel: funnel}
+}
+
+// GetConversionFunnel handles GET /api/v1/analytics/funnel. The range is either
+// ?range=30d (hours or days back from now, default 30d) or ?start=&end= dates,
+// inclusive. ?compare=previous compares against the preceding range of the same
+// length; ?compare_start=&compare_end= compares against explicit dates.
+func (h *FunnelAnalyticsHandlers) GetConversionFunnel(c *gin.Context) {
+	start, end, ok := parseFunnelRange(c.Query("start"), c.Query("end"), c.DefaultQuery("range", "30d"), time.Now())
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid range",
+			"details": "use range=<n>h or <n>d up to 365d, or start and end dates as YYYY-MM-DD",
+		})
+		return
+	}
+
+	var previousStart, previousEnd time.Time
+	switch {
+	case c.Query("compare") == "previous":
+		previousStart, previousEnd = start.Add(-end.Sub(start)), start
+	case c.Query("compare_start") != "" || c.Query("compare_end") != "":
+		previousStart, previousEnd, ok = parseFunnelRange(c.Query("compare_start"), c.Query("compare_end"), "", time.Now())
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid comparison range",
+				"details": "compare_start and compare_end must both be dates as YYYY-MM-DD",
+			})
+			return
+		}
+	case c.Query("compare") != "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid compare", "details": "compare must be previous"})
+		return
+	}
+
+	if previousEnd.IsZero() {
+		report, err := h.funnel.BuildConversionFunnel(start, end)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build conversion funnel", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true, "report": report})
+		return
+	}
+
+	comparison, err := h.funnel.CompareConversionFunnels(start, end, previousStart, previousEnd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compare conversion funnels", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "comparison": comparison})
+}
+
+// parseFunnelRange returns explicit start and end dates when given, the end
+// date counting as a whole day, or else the relative range back from now
+func parseFunnelRange(startParam, endParam, rangeParam string, now time.Time) (time.Time, time.Time, bool) {
+	if startParam != "" || endParam != "" {
+		start, err := time.ParseInLocation(funnelDateLayout, startParam, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, false
+		}
+		end, err := time.ParseInLocation(funnelDateLayout, endParam, now.Location())
+		if err != nil || end.Before(start) {
+			return time.Time{}, time.Time{}, false
+		}
+		return start, end.AddDate(0, 0, 1), true
+	}
+
+	window, ok := parseComplianceHistoryRange(rangeParam)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	return now.Add(-window), now, true
+}
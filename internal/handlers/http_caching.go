@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cache-Control policies for cacheable JSON responses
+const (
+	// Public property data may be reused briefly, then revalidated with the ETag
+	propertyCacheControl = "public, max-age=60, must-revalidate"
+
+	// Per-visitor data must always be revalidated and never shared
+	savedPropertiesCacheControl = "private, no-cache"
+)
+
+// respondCachedJSON writes body as a 200 JSON response with a weak ETag over its
+// content, answering 304 Not Modified when the client already holds it. A
+// non-zero lastModified is sent as Last-Modified and honored via
+// If-Modified-Since when the client sent no If-None-Match.
+func respondCachedJSON(c *gin.Context, body interface{}, lastModified time.Time, cacheControl string) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to encode response",
+		})
+		return
+	}
+
+	sum := sha256.Sum256(payload)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", cacheControl)
+	c.Header("Vary", "Accept-Encoding")
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", payload)
+}
+
+// notModified applies the conditional request headers; If-None-Match takes
+// precedence over If-Modified-Since
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	// HTTP dates have second precision
+	return !lastModified.Truncate(time.Second).After(since)
+}
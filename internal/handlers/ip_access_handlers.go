@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// IPAccessHandlers manages the admin IP allowlist and the denylist
+type IPAccessHandlers struct {
+	access *services.IPAccessService
+}
+
+// NewIPAccessHandlers creates IP access list handlers
+func NewIPAccessHandlers(access *services.IPAccessService) *IPAccessHandlers {
+	return &IPAccessHandlers{access: access}
+}
+
+// ipAccessRuleRequest is the body of a new allow or deny rule
+type ipAccessRuleRequest struct {
+	List           string `json:"list"` // allow or deny; set by the path for security event denials
+	CIDR           string `json:"cidr"` // CIDR or single IP
+	Reason         string `json:"reason"`
+	ExpiresInHours int    `json:"expires_in_hours"` // 0 never expires
+}
+
+// GetIPAccessRules handles GET /api/v1/admin/ip-access/rules, listing the
+// stored rules on ?list= (allow or deny; both by default) and the allowlist
+// entries from config
+func (h *IPAccessHandlers) GetIPAccessRules(c *gin.Context) {
+	if _, ok := ipAccessAdmin(c); !ok {
+		return
+	}
+	list := c.Query("list")
+	if list != "" && list != models.IPAccessAllow && list != models.IPAccessDeny {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid list", "details": "list must be allow or deny"})
+		return
+	}
+
+	rules, err := h.access.Rules(list)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load IP access rules", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":            true,
+		"rules":              rules,
+		"count":              len(rules),
+		"config_allowlist":   h.access.ConfiguredAllowlist(),
+		"allowlist_enforced": h.access.HasAllowlist(),
+		"client_ip":          c.ClientIP(),
+	})
+}
+
+// CreateIPAccessRule handles POST /api/v1/admin/ip-access/rules. The first
+// allow rule must cover the caller's own IP so they are not locked out.
+func (h *IPAccessHandlers) CreateIPAccessRule(c *gin.Context) {
+	admin, ok := ipAccessAdmin(c)
+	if !ok {
+		return
+	}
+	req, ok := bindIPAccessRule(c)
+	if !ok {
+		return
+	}
+
+	if req.List == models.IPAccessAllow && !h.access.HasAllowlist() {
+		network, err := services.ParseIPNetwork(req.CIDR)
+		if err == nil && !network.Contains(net.ParseIP(c.ClientIP())) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Allow rule would lock you out",
+				"details": "the first allowlist entry must include your IP " + c.ClientIP(),
+			})
+			return
+		}
+	}
+	if req.List == models.IPAccessDeny {
+		if network, err := services.ParseIPNetwork(req.CIDR); err == nil && network.Contains(net.ParseIP(c.ClientIP())) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Deny rule would lock you out", "details": "the network includes your IP " + c.ClientIP()})
+			return
+		}
+	}
+
+	rule, err := h.access.AddRule(newIPAccessRule(req, admin))
+	if err != nil {
+		ipAccessErrorResponse(c, "Failed to add IP access rule", err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "rule": rule})
+}
+
+// DenySecurityEventIP handles POST /api/v1/admin/ip-access/security-events/:id/deny,
+// denying the IP a security event came from
+func (h *IPAccessHandlers) DenySecurityEventIP(c *gin.Context) {
+	admin, ok := ipAccessAdmin(c)
+	if !ok {
+		return
+	}
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || eventID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+	var req ipAccessRuleRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+			return
+		}
+	}
+	if req.ExpiresInHours < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expires_in_hours", "details": "expires_in_hours cannot be negative"})
+		return
+	}
+
+	rule, err := h.access.DenySecurityEvent(uint(eventID), newIPAccessRule(&req, admin))
+	if err != nil {
+		ipAccessErrorResponse(c, "Failed to deny security event IP", err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "rule": rule})
+}
+
+// DeleteIPAccessRule handles DELETE /api/v1/admin/ip-access/rules/:id
+func (h *IPAccessHandlers) DeleteIPAccessRule(c *gin.Context) {
+	if _, ok := ipAccessAdmin(c); !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	if err := h.access.RemoveRule(uint(id)); err != nil {
+		ipAccessErrorResponse(c, "Failed to remove IP access rule", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func bindIPAccessRule(c *gin.Context) (*ipAccessRuleRequest, bool) {
+	var req ipAccessRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return nil, false
+	}
+	if req.List != models.IPAccessAllow && req.List != models.IPAccessDeny {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid list", "details": "list must be allow or deny"})
+		return nil, false
+	}
+	if _, err := services.ParseIPNetwork(req.CIDR); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cidr", "details": err.Error()})
+		return nil, false
+	}
+	if req.ExpiresInHours < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expires_in_hours", "details": "expires_in_hours cannot be negative"})
+		return nil, false
+	}
+	return &req, true
+}
+
+func newIPAccessRule(req *ipAccessRuleRequest, admin *models.AdminUser) models.IPAccessRule {
+	rule := models.IPAccessRule{
+		List:        req.List,
+		CIDR:        req.CIDR,
+		Reason:      req.Reason,
+		CreatedByID: admin.ID,
+		CreatedBy:   admin.Username,
+	}
+	if req.ExpiresInHours > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		rule.ExpiresAt = &expiresAt
+	}
+	return rule
+}
+
+func ipAccessErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidIPRule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": message, "details": err.Error()})
+	case errors.Is(err, services.ErrIPRuleExists):
+		c.JSON(http.StatusConflict, gin.H{"error": message, "details": err.Error()})
+	case errors.Is(err, services.ErrIPRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": message, "details": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message, "details": err.Error()})
+	}
+}
+
+// ipAccessAdmin returns the signed-in admin when they may change who can
+// reach the site
+func ipAccessAdmin(c *gin.Context) (*models.AdminUser, bool) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return nil, false
+	}
+	if !models.IsTeamAdminRole(admin.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "IP access lists require a team admin"})
+		return nil, false
+	}
+	return admin, true
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+// GetDuplicateLeads lists candidate duplicate clusters for review
+// GET /api/v1/leads/duplicates
+func (h *LeadReengagementHandler) GetDuplicateLeads(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
+	clusters, err := services.NewLeadDeduplicationService(h.db).FindDuplicates(admin)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to find duplicate leads",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response := make([]gin.H, 0, len(clusters))
+	for _, cluster := range clusters {
+		response = append(response, gin.H{
+			"matched_on":           cluster.MatchedOn,
+			"suggested_primary_id": cluster.SuggestedPrimaryID,
+			"leads":                models.DecryptLeadReengagementList(cluster.Leads, h.encryptionManager),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"clusters": response,
+		"total":    len(response),
+	})
+}
+
+// MergeLeads merges duplicate leads into a primary lead
+// POST /api/v1/leads/merge
+func (h *LeadReengagementHandler) MergeLeads(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		PrimaryID    uint   `json:"primary_id" binding:"required"`
+		DuplicateIDs []uint `json:"duplicate_ids" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	audit, err := services.NewLeadDeduplicationService(h.db).Merge(admin, req.PrimaryID, req.DuplicateIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidMerge):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid merge request",
+				"details": err.Error(),
+			})
+		case errors.Is(err, services.ErrMergeLeadNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Lead not found",
+				"details": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to merge leads",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Leads merged successfully",
+		"merge":   audit,
+	})
+}
+
+// GetLeadMerges lists recent lead merges. Restricted to team admins, since an
+// audit row can reference leads owned by several agents.
+// GET /api/v1/leads/merges
+func (h *LeadReengagementHandler) GetLeadMerges(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+	if !models.IsTeamAdminRole(admin.Role) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Only team admins can view the merge history",
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	audits, err := services.NewLeadDeduplicationService(h.db).MergeHistory(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve merge history",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"merges": audits,
+		"total":  len(audits),
+	})
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/export"
+	"chrisgross-ctrl-project/internal/models"
+)
+
+const (
+	// maxExportRows caps a single export; larger result sets are truncated
+	maxExportRows = 50000
+
+	// exportBatchSize is how many rows are loaded from the database at a time
+	exportBatchSize = 500
+)
+
+var leadExportColumns = []string{
+	"ID", "FUB Contact ID", "First Name", "Last Name", "Email", "Phone", "Owner ID",
+	"Segment", "Risk Level", "Consent Status", "Campaign Status", "Emails Sent",
+	"Emails Opened", "Emails Clicked", "Responded", "Opted In", "Last Activity",
+	"Original Source", "Created At",
+}
+
+var campaignExportColumns = []string{
+	"ID", "Campaign Name", "Template", "Lead ID", "Lead Name", "Lead Email",
+	"Status", "Scheduled For", "Executed At", "Email Opened", "Email Clicked",
+	"Responded", "Response Type", "Retry Count", "Error", "Created At",
+}
+
+// ExportLeads streams the leads visible to the admin as CSV or XLSX, with PII
+// decrypted. Accepts the same filters as GET /leads.
+// GET /api/v1/leads/export?format=csv|xlsx
+func (h *LeadReengagementHandler) ExportLeads(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
+	h.streamExport(c, "leads", leadExportColumns, h.leadListQuery(c, admin), func(tx *gorm.DB, rows export.RowWriter) (int, error) {
+		var batch []models.LeadReengagement
+		written := 0
+		result := tx.FindInBatches(&batch, exportBatchSize, func(batchTx *gorm.DB, _ int) error {
+			for _, lead := range batch {
+				if err := rows.WriteRow(leadExportRow(models.DecryptLeadReengagement(lead, h.encryptionManager))); err != nil {
+					return err
+				}
+				written++
+			}
+			c.Writer.Flush()
+			return nil
+		})
+		return written, result.Error
+	})
+}
+
+// ExportCampaigns streams campaign executions for the leads visible to the
+// admin as CSV or XLSX. Accepts the same filters as GET /reengagement/campaigns.
+// GET /api/v1/campaigns/export?format=csv|xlsx
+func (h *LeadReengagementHandler) ExportCampaigns(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
+	query, err := h.campaignListQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid date range",
+			"details": err.Error(),
+		})
+		return
+	}
+	visibleLeads := h.db.Model(&models.LeadReengagement{}).Scopes(models.LeadsVisibleTo(admin)).Select("id")
+	query = query.Where("lead_reengagement_id IN (?)", visibleLeads)
+
+	h.streamExport(c, "campaigns", campaignExportColumns, query, func(tx *gorm.DB, rows export.RowWriter) (int, error) {
+		var batch []models.CampaignExecution
+		written := 0
+		result := tx.Preload("LeadReengagement").Preload("CampaignTemplate").
+			FindInBatches(&batch, exportBatchSize, func(batchTx *gorm.DB, _ int) error {
+				for _, campaign := range batch {
+					lead := models.DecryptLeadReengagement(campaign.LeadReengagement, h.encryptionManager)
+					if err := rows.WriteRow(campaignExportRow(campaign, lead)); err != nil {
+						return err
+					}
+					written++
+				}
+				c.Writer.Flush()
+				return nil
+			})
+		return written, result.Error
+	})
+}
+
+// streamExport counts the rows, sets the download headers (flagging truncation
+// past maxExportRows), then streams the header row and every data row
+func (h *LeadReengagementHandler) streamExport(c *gin.Context, name string, columns []string, query *gorm.DB, writeRows func(tx *gorm.DB, rows export.RowWriter) (int, error)) {
+	format, err := export.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid export format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to count export rows",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	filename := export.Filename(name, format, time.Now())
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", export.ContentDisposition(filename))
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Export-Total-Rows", strconv.FormatInt(total, 10))
+	if total > maxExportRows {
+		c.Header("X-Export-Truncated", "true")
+		c.Header("Warning", fmt.Sprintf(`199 - "export truncated to %d of %d rows"`, maxExportRows, total))
+	}
+	c.Status(http.StatusOK)
+
+	rows := export.NewRowWriter(format, c.Writer)
+	if err := rows.WriteRow(columns); err != nil {
+		log.Printf("❌ Export %s aborted: %v", filename, err)
+		return
+	}
+	written, err := writeRows(query.Limit(maxExportRows), rows)
+	if err != nil {
+		// Headers are already sent; the client sees a short file
+		log.Printf("❌ Export %s failed after %d rows: %v", filename, written, err)
+		return
+	}
+	if err := rows.Close(); err != nil {
+		log.Printf("❌ Export %s failed to finish: %v", filename, err)
+		return
+	}
+	log.Printf("📤 Exported %d %s as %s", written, name, format)
+}
+
+func leadExportRow(lead models.LeadReengagementResponse) []string {
+	return []string{
+		strconv.FormatUint(uint64(lead.ID), 10),
+		lead.FUBContactID,
+		lead.FirstName,
+		lead.LastName,
+		lead.Email,
+		lead.Phone,
+		lead.OwnerID,
+		string(lead.Segment),
+		string(lead.RiskLevel),
+		string(lead.ConsentStatus),
+		string(lead.CampaignStatus),
+		strconv.Itoa(lead.EmailsSent),
+		strconv.Itoa(lead.EmailsOpened),
+		strconv.Itoa(lead.EmailsClicked),
+		strconv.FormatBool(lead.Responded),
+		strconv.FormatBool(lead.OptedIn),
+		formatExportTime(lead.LastActivity),
+		lead.OriginalSource,
+		lead.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func campaignExportRow(campaign models.CampaignExecution, lead models.LeadReengagementResponse) []string {
+	return []string{
+		strconv.FormatUint(uint64(campaign.ID), 10),
+		campaign.CampaignName,
+		campaign.CampaignTemplate.Name,
+		strconv.FormatUint(uint64(campaign.LeadReengagementID), 10),
+		fmt.Sprintf("%s %s", lead.FirstName, lead.LastName),
+		lead.Email,
+		campaign.Status,
+		campaign.ScheduledFor.Format(time.RFC3339),
+		formatExportTime(campaign.ExecutedAt),
+		strconv.FormatBool(campaign.EmailOpened),
+		strconv.FormatBool(campaign.EmailClicked),
+		strconv.FormatBool(campaign.Responded),
+		campaign.ResponseType,
+		strconv.Itoa(campaign.RetryCount),
+		campaign.ErrorMessage,
+		campaign.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+func TestGetCampaigns_PaginatesAndFiltersByDate(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.CampaignExecution{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	day := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		execution := models.CampaignExecution{LeadReengagementID: 1, CampaignTemplateID: 1, Status: "sent", CreatedAt: day.AddDate(0, 0, i)}
+		if err := db.Create(&execution).Error; err != nil {
+			t.Fatalf("Failed to create execution: %v", err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/campaigns", (&LeadReengagementHandler{db: db}).GetCampaigns)
+
+	get := func(url string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		var body map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body
+	}
+
+	code, body := get("/campaigns?from=2026-10-02&to=2026-10-04&limit=2&page=2&sort_order=asc")
+	if code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", code, body)
+	}
+	pagination := body["pagination"].(map[string]interface{})
+	if pagination["total"].(float64) != 3 || pagination["pages"].(float64) != 2 {
+		t.Errorf("Expected 3 campaigns over 2 pages, got %v", pagination)
+	}
+	campaigns := body["campaigns"].([]interface{})
+	if len(campaigns) != 1 || campaigns[0].(map[string]interface{})["id"].(float64) != 4 {
+		t.Errorf("Expected the Oct 4 campaign alone on page 2, got %v", campaigns)
+	}
+
+	if code, _ := get("/campaigns?sort_by=fub_step_id"); code != http.StatusBadRequest {
+		t.Errorf("Expected an unknown sort column to be rejected, got %d", code)
+	}
+	if code, _ := get("/campaigns?from=yesterday"); code != http.StatusBadRequest {
+		t.Errorf("Expected an invalid date to be rejected, got %d", code)
+	}
+}
+
+func TestActivateCampaign_ExcludesLeadsFailingSafetyChecks(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.LeadReengagement{}, &models.CampaignTemplate{}, &models.CampaignExecution{},
+		&models.CampaignSuppression{}, &models.DripCampaign{}, &models.DripCampaignStep{}, &models.DripEnrollment{}, &models.Lead{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	// fub_leads uses postgres array and jsonb columns, so create it by hand for sqlite
+	if err := db.Exec(`CREATE TABLE fub_leads (id INTEGER PRIMARY KEY, fub_lead_id TEXT, email TEXT, status TEXT, stage TEXT)`).Error; err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	safety := services.NewLeadSafetyFilter(db, nil)
+	if err := safety.AutoMigrate(); err != nil {
+		t.Fatalf("Failed to migrate safety tables: %v", err)
+	}
+
+	template := models.CampaignTemplate{Name: "Spring", EmailNumber: 1, Subject: "Hi", Body: "Hello"}
+	db.Create(&template)
+	leads := map[string]*models.LeadReengagement{}
+	for _, fubID := range []string{"fub-ok", "fub-dnc", "fub-legal", "fub-status", "fub-complained"} {
+		lead := &models.LeadReengagement{FUBContactID: fubID, OwnerID: "admin-1", HasEmail: true, EmailValid: true, Segment: models.SegmentActive,
+			RiskLevel: models.RiskLow, ConsentStatus: models.ConsentImplied, CampaignStatus: models.CampaignPending}
+		db.Create(lead)
+		leads[fubID] = lead
+	}
+	db.Create(&services.LeadOverride{LeadID: "fub-dnc", DoNotContact: true})
+	db.Create(&services.LeadOverride{LeadID: "fub-legal", Litigation: true})
+	db.Exec(`INSERT INTO fub_leads (fub_lead_id, status, stage) VALUES ('fub-status', 'Do Not Contact', 'Active')`)
+	db.Create(&models.CampaignExecution{LeadReengagementID: leads["fub-complained"].ID, CampaignTemplateID: template.ID,
+		Status: "sent", Responded: true, ResponseType: "complaint"})
+
+	handler := NewLeadReengagementHandler(db, nil)
+	handler.SetSafetyFilter(safety)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &models.AdminUser{ID: "admin-1", Role: "admin"})
+	})
+	router.POST("/activate", handler.ActivateCampaign)
+
+	body := `{"name": "Spring", "template_id": 1, "max_volume": 10, "exclude_open_approvals": false, "exclude_active_pipelines": false}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/activate", strings.NewReader(body)))
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", w.Code, response)
+	}
+	if response["leads_activated"].(float64) != 1 || response["leads_blocked"].(float64) != 4 {
+		t.Errorf("Expected only the safe lead activated and 4 blocked, got %v", response)
+	}
+
+	var enrolled []models.DripEnrollment
+	db.Find(&enrolled)
+	if len(enrolled) != 1 || enrolled[0].LeadReengagementID != leads["fub-ok"].ID {
+		t.Errorf("Expected only the safe lead enrolled, got %+v", enrolled)
+	}
+	var exclusions []services.LeadSafetyExclusion
+	db.Order("lead_id").Find(&exclusions)
+	if len(exclusions) != 4 || exclusions[0].LeadID != "fub-complained" || exclusions[0].Context != "Spring" || exclusions[0].Reason == "" {
+		t.Errorf("Expected each blocked lead recorded with a reason, got %+v", exclusions)
+	}
+}
+
+func TestComplianceReportAndRiskAssessment_ScopedToOwner(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.LeadReengagement{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	for i, owner := range []string{"agent-1", "agent-2", "agent-2"} {
+		db.Create(&models.LeadReengagement{FUBContactID: fmt.Sprintf("fub-%d", i), OwnerID: owner, OnDNCList: true,
+			Segment: models.SegmentActive, RiskLevel: models.RiskLow, ConsentStatus: models.ConsentRevoked})
+	}
+
+	handler := NewLeadReengagementHandler(db, nil)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &models.AdminUser{ID: c.GetHeader("X-Test-Admin"), Role: c.GetHeader("X-Test-Role")})
+	})
+	router.GET("/compliance/report", handler.GetComplianceReport)
+	router.POST("/risk/assess", handler.AssessRisk)
+
+	do := func(admin, role, method, path, body string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-Test-Admin", admin)
+		req.Header.Set("X-Test-Role", role)
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200 from %s, got %d: %s", path, w.Code, w.Body.String())
+		}
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response
+	}
+
+	report := do("agent-1", "agent", http.MethodGet, "/compliance/report", "")
+	if total := report["compliance_report"].(map[string]interface{})["total_leads"].(float64); total != 1 {
+		t.Errorf("Expected an agent's report to count only their lead, got %v", total)
+	}
+	if flagged := report["flagged_leads"].([]interface{}); len(flagged) != 1 {
+		t.Errorf("Expected an agent to see only their flagged lead, got %d", len(flagged))
+	}
+	report = do("admin-1", models.RoleSuperAdmin, http.MethodGet, "/compliance/report", "")
+	if total := report["compliance_report"].(map[string]interface{})["total_leads"].(float64); total != 3 {
+		t.Errorf("Expected a team admin's report to count every lead, got %v", total)
+	}
+
+	assessed := do("agent-1", "agent", http.MethodPost, "/risk/assess", `{"lead_ids": [1, 2, 3]}`)
+	if assessed["leads_assessed"].(float64) != 1 {
+		t.Errorf("Expected an agent to assess only their lead, got %v", assessed["leads_assessed"])
+	}
+	var others int64
+	db.Model(&models.LeadReengagement{}).Where("owner_id = ? AND risk_level = ?", "agent-2", models.RiskLow).Count(&others)
+	if others != 2 {
+		t.Errorf("Expected other agents' leads left unchanged, got %d still low risk", others)
+	}
+}
+
+func TestTemplates_StoreCustomVariableValues(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.CampaignTemplate{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	handler := NewLeadReengagementHandler(db, nil)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/templates", handler.CreateTemplate)
+	router.PUT("/templates/:id", handler.UpdateTemplate)
+
+	do := func(method, path, body string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w.Code
+	}
+
+	for body, reason := range map[string]string{
+		`{"name": "A", "email_number": 1, "subject": "Hi", "body": "Call {{agent_phone}}"}`:                                         "an undeclared variable",
+		`{"name": "A", "email_number": 1, "subject": "Hi", "body": "Call {{agent_phone}}", "custom_variables": {"agent_phone": 5}}`: "a value that is not text",
+		`{"name": "A", "email_number": 1, "subject": "Hi", "body": "Hi {{email}}", "custom_variables": {"email": "x@example.com"}}`: "a custom key shadowing a lead field",
+	} {
+		if code := do(http.MethodPost, "/templates", body); code != http.StatusBadRequest {
+			t.Errorf("Expected a template with %s to be rejected, got %d", reason, code)
+		}
+	}
+
+	body := `{"name": "A", "email_number": 1, "subject": "Hi {{first_name}}", "body": "Call {{agent_phone}}", "custom_variables": {"agent_phone": "555-0100"}}`
+	if code := do(http.MethodPost, "/templates", body); code != http.StatusCreated {
+		t.Fatalf("Expected the template created, got %d", code)
+	}
+	var template models.CampaignTemplate
+	db.First(&template)
+	if values := template.CustomValues(); values["agent_phone"] != "555-0100" {
+		t.Fatalf("Expected the custom value stored on the template, got %v", values)
+	}
+
+	// Stored custom variables stay valid across updates that do not replace them
+	if code := do(http.MethodPut, "/templates/1", `{"subject": "Hello {{first_name}}"}`); code != http.StatusOK {
+		t.Errorf("Expected an update keeping the custom variables to succeed, got %d", code)
+	}
+	if code := do(http.MethodPut, "/templates/1", `{"custom_variables": {}}`); code != http.StatusBadRequest {
+		t.Errorf("Expected removing a variable the body uses to be rejected, got %d", code)
+	}
+}
@@ -1,9 +1,17 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/mail"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,28 +19,64 @@ import (
 
 	"chrisgross-ctrl-project/internal/models"
 	"chrisgross-ctrl-project/internal/security"
+	"chrisgross-ctrl-project/internal/services"
 )
 
 type LeadReengagementHandler struct {
 	db                *gorm.DB
 	encryptionManager *security.EncryptionManager
+	tracker           *services.CampaignTracker
+	emergencyControls *services.EmergencyControls
+	drips             *services.DripCampaignService
+	suppressions      *services.CampaignSuppressionService
+	rateShaper        *services.DomainRateShaper
+	safety            *services.LeadSafetyFilter
 }
 
 func NewLeadReengagementHandler(db *gorm.DB, encryptionManager *security.EncryptionManager) *LeadReengagementHandler {
 	return &LeadReengagementHandler{
 		db:                db,
 		encryptionManager: encryptionManager,
+		emergencyControls: services.NewEmergencyControls(db),
+		drips:             services.NewDripCampaignService(db),
+		suppressions:      services.NewCampaignSuppressionService(db, encryptionManager),
 	}
 }
 
+// SetCampaignTracker sets the tracker used to verify signed click-tracking links
+func (h *LeadReengagementHandler) SetCampaignTracker(tracker *services.CampaignTracker) {
+	h.tracker = tracker
+}
+
+// SetRateShaper sets the dispatcher's rate shaper whose counters are exposed by GetRateShapingStats
+func (h *LeadReengagementHandler) SetRateShaper(shaper *services.DomainRateShaper) {
+	h.rateShaper = shaper
+}
+
+// SetSafetyFilter excludes leads failing the contact safety check from campaign activation
+func (h *LeadReengagementHandler) SetSafetyFilter(safety *services.LeadSafetyFilter) {
+	h.safety = safety
+}
+
+// excludeUnsafeLeads narrows a campaign audience to leads passing the contact
+// safety check, returning how many were excluded
+func (h *LeadReengagementHandler) excludeUnsafeLeads(campaignName string, eligible *gorm.DB) (*gorm.DB, int64, error) {
+	if h.safety == nil {
+		return eligible, 0, nil
+	}
+	return h.safety.ExcludeUnsafeCampaignLeads(campaignName, eligible)
+}
+
 // RegisterRoutes registers all lead re-engagement routes
 func (h *LeadReengagementHandler) RegisterRoutes(r *gin.RouterGroup) {
 	reengagement := r.Group("/reengagement")
 	{
 		// Lead Management
 		reengagement.GET("/leads", h.GetLeads)
+		reengagement.GET("/leads/search", h.SearchLeads)
 		reengagement.GET("/leads/:id", h.GetLead)
 		reengagement.POST("/leads/import", h.ImportLeads)
+		reengagement.POST("/leads/import-csv", h.ImportLeadsCSV)
 		reengagement.PUT("/leads/:id", h.UpdateLead)
 		reengagement.DELETE("/leads/:id", h.DeleteLead)
 
@@ -47,47 +91,69 @@ func (h *LeadReengagementHandler) RegisterRoutes(r *gin.RouterGroup) {
 		reengagement.POST("/campaigns/activate", h.ActivateCampaign)
 		reengagement.PUT("/campaigns/:id/pause", h.PauseCampaign)
 		reengagement.GET("/campaigns/:id/status", h.GetCampaignStatus)
+		reengagement.GET("/campaigns/suppressions", h.GetCampaignSuppressions)
+		reengagement.POST("/campaigns/suppressions", h.CreateCampaignSuppression)
+		reengagement.DELETE("/campaigns/suppressions/:id", h.DeleteCampaignSuppression)
+
+		// Drip Campaigns
+		reengagement.GET("/drip-campaigns", h.GetDripCampaigns)
+		reengagement.POST("/drip-campaigns", h.CreateDripCampaign)
+		reengagement.GET("/drip-campaigns/:id", h.GetDripCampaign)
+		reengagement.POST("/drip-campaigns/:id/enroll", h.EnrollDripCampaign)
+		reengagement.POST("/drip-campaigns/:id/pause", h.PauseDripCampaign)
+		reengagement.POST("/drip-campaigns/:id/resume", h.ResumeDripCampaign)
+		reengagement.POST("/drip-campaigns/:id/stop", h.StopDripCampaign)
+		reengagement.GET("/drip-campaigns/:id/enrollments", h.GetDripEnrollments)
 
 		// Templates
 		reengagement.GET("/templates", h.GetTemplates)
 		reengagement.POST("/templates", h.CreateTemplate)
 		reengagement.PUT("/templates/:id", h.UpdateTemplate)
 		reengagement.DELETE("/templates/:id", h.DeleteTemplate)
+		reengagement.POST("/templates/:id/preview", h.PreviewTemplate)
 
 		// Metrics and Reporting
 		reengagement.GET("/metrics", h.GetMetrics)
 		reengagement.GET("/metrics/daily", h.GetDailyMetrics)
+		reengagement.GET("/metrics/rate-shaping", h.GetRateShapingStats)
 		reengagement.GET("/compliance/report", h.GetComplianceReport)
 
 		// Emergency Controls
 		reengagement.POST("/emergency/stop", h.EmergencyStopAll)
 		reengagement.POST("/emergency/stop/:campaignId", h.EmergencyStop)
+		reengagement.GET("/emergency/status", h.GetEmergencyStatus)
+		reengagement.POST("/emergency/deactivate", h.DeactivateEmergencyStop)
+	}
+}
+
+// leadOwner returns the admin set by the auth middleware, whose ID scopes lead queries
+func (h *LeadReengagementHandler) leadOwner(c *gin.Context) (*models.AdminUser, bool) {
+	value, exists := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !exists || !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return nil, false
 	}
+	return admin, true
 }
 
 // GetLeads retrieves leads with filtering and pagination
 func (h *LeadReengagementHandler) GetLeads(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
 	var leads []models.LeadReengagement
 
 	// Parse query parameters
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
-	segment := c.Query("segment")
-	riskLevel := c.Query("risk_level")
-	campaignStatus := c.Query("campaign_status")
 
 	// Build query
-	query := h.db.Model(&models.LeadReengagement{})
-
-	if segment != "" {
-		query = query.Where("segment = ?", segment)
-	}
-	if riskLevel != "" {
-		query = query.Where("risk_level = ?", riskLevel)
-	}
-	if campaignStatus != "" {
-		query = query.Where("campaign_status = ?", campaignStatus)
-	}
+	query := h.leadListQuery(c, admin)
 
 	// Get total count
 	var total int64
@@ -106,7 +172,7 @@ func (h *LeadReengagementHandler) GetLeads(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"leads": leads,
+		"leads": models.DecryptLeadReengagementList(leads, h.encryptionManager),
 		"pagination": gin.H{
 			"page":  page,
 			"limit": limit,
@@ -116,8 +182,30 @@ func (h *LeadReengagementHandler) GetLeads(c *gin.Context) {
 	})
 }
 
+// leadListQuery applies the lead list filters (segment, risk_level,
+// campaign_status) to the leads visible to admin
+func (h *LeadReengagementHandler) leadListQuery(c *gin.Context, admin *models.AdminUser) *gorm.DB {
+	query := h.db.Model(&models.LeadReengagement{}).Scopes(models.LeadsVisibleTo(admin))
+
+	if segment := c.Query("segment"); segment != "" {
+		query = query.Where("segment = ?", segment)
+	}
+	if riskLevel := c.Query("risk_level"); riskLevel != "" {
+		query = query.Where("risk_level = ?", riskLevel)
+	}
+	if campaignStatus := c.Query("campaign_status"); campaignStatus != "" {
+		query = query.Where("campaign_status = ?", campaignStatus)
+	}
+	return query
+}
+
 // ImportLeads imports leads from FUB for re-engagement analysis
 func (h *LeadReengagementHandler) ImportLeads(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
 	var request struct {
 		FUBContactIDs []string `json:"fub_contact_ids"`
 		DryRun        bool     `json:"dry_run"`
@@ -167,24 +255,44 @@ func (h *LeadReengagementHandler) ImportLeads(c *gin.Context) {
 			continue
 		}
 
+		encryptedPhone, err := h.encryptionManager.EncryptPhone(fubLead.Phone)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Failed to encrypt phone for contact %s: %v", contactID, err))
+			skipped++
+			continue
+		}
+
 		lead := &models.LeadReengagement{
-			FUBContactID: contactID,
-			Email:        encryptedEmail,
-			FirstName:    encryptedFirstName,
-			LastName:     encryptedLastName,
-			HasEmail:     true,
-			EmailValid:   true,
+			FUBContactID:   contactID,
+			OwnerID:        admin.ID,
+			Email:          encryptedEmail,
+			Phone:          encryptedPhone,
+			FirstName:      encryptedFirstName,
+			LastName:       encryptedLastName,
+			HasEmail:       fubLead.Email != "",
+			EmailValid:     isValidLeadEmail(fubLead.Email),
+			LastActivity:   fubLead.LastActivity,
+			OriginalSource: fubLead.Source,
+			ConsentStatus:  models.ConsentUnknown,
+		}
+		lead.SetBlindIndexes(h.encryptionManager, fubLead.FirstName, fubLead.LastName, fubLead.Email, fubLead.Phone)
+		if !fubLead.FUBCreatedAt.IsZero() {
+			firstContact := fubLead.FUBCreatedAt
+			lead.FirstContact = &firstContact
 		}
 
-		// Calculate segment and risk
+		// Calculate segment and risk from activity, bounce, and consent data
 		lead.Segment = lead.CalculateSegment()
 		lead.RiskLevel = lead.CalculateRiskLevel()
-		lead.ConsentStatus = models.ConsentUnknown
 
 		if !request.DryRun {
-			// Check if lead already exists
+			// Check if lead already exists, by FUB contact or by email
 			var existing models.LeadReengagement
-			result := h.db.Where("fub_contact_id = ?", contactID).First(&existing)
+			query := h.db.Where("fub_contact_id = ?", contactID)
+			if lead.EmailHash != "" {
+				query = query.Or("email_hash = ?", lead.EmailHash)
+			}
+			result := query.First(&existing)
 
 			if result.Error == gorm.ErrRecordNotFound {
 				// Create new lead
@@ -210,6 +318,220 @@ func (h *LeadReengagementHandler) ImportLeads(c *gin.Context) {
 	})
 }
 
+// csvImportColumns maps accepted CSV header names to lead fields
+var csvImportColumns = map[string]string{
+	"first_name":    "first_name",
+	"firstname":     "first_name",
+	"first name":    "first_name",
+	"last_name":     "last_name",
+	"lastname":      "last_name",
+	"last name":     "last_name",
+	"email":         "email",
+	"email_address": "email",
+	"email address": "email",
+	"phone":         "phone",
+	"phone_number":  "phone",
+	"phone number":  "phone",
+	"mobile":        "phone",
+}
+
+// CSVImportRowResult reports the outcome of importing a single CSV row
+type CSVImportRowResult struct {
+	Row    int    `json:"row"`
+	Email  string `json:"email,omitempty"`
+	Status string `json:"status"` // imported, skipped, error
+	Reason string `json:"reason,omitempty"`
+}
+
+// ImportLeadsCSV imports leads from an uploaded spreadsheet export
+func (h *LeadReengagementHandler) ImportLeadsCSV(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "No CSV file provided",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !strings.HasSuffix(strings.ToLower(file.Filename), ".csv") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "File must be a CSV file",
+		})
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to open CSV file",
+		})
+		return
+	}
+	defer src.Close()
+
+	reader := csv.NewReader(src)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to read CSV header",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := csvImportColumns[key]; ok {
+			if _, exists := columns[field]; !exists {
+				columns[field] = i
+			}
+		}
+	}
+
+	if _, ok := columns["email"]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "CSV must include an email column",
+		})
+		return
+	}
+
+	// Emails seen earlier in this file; stored leads are checked through the email blind index
+	existingEmails := map[string]bool{}
+
+	results := []CSVImportRowResult{}
+	imported, skipped, failed := 0, 0, 0
+	rowNumber := 1
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		rowNumber++
+
+		if err != nil {
+			results = append(results, CSVImportRowResult{Row: rowNumber, Status: "error", Reason: fmt.Sprintf("Malformed row: %v", err)})
+			failed++
+			continue
+		}
+
+		value := func(field string) string {
+			if idx, ok := columns[field]; ok && idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+
+		email := strings.ToLower(value("email"))
+		if !isValidLeadEmail(email) {
+			results = append(results, CSVImportRowResult{Row: rowNumber, Email: email, Status: "skipped", Reason: "Invalid or missing email address"})
+			skipped++
+			continue
+		}
+
+		duplicate, err := h.leadEmailExists(email)
+		if err != nil {
+			results = append(results, CSVImportRowResult{Row: rowNumber, Email: email, Status: "error", Reason: fmt.Sprintf("Failed to check for duplicates: %v", err)})
+			failed++
+			continue
+		}
+		if duplicate || existingEmails[email] {
+			results = append(results, CSVImportRowResult{Row: rowNumber, Email: email, Status: "skipped", Reason: "Duplicate email"})
+			skipped++
+			continue
+		}
+
+		lead, err := h.buildCSVLead(email, value("first_name"), value("last_name"), value("phone"))
+		if err != nil {
+			results = append(results, CSVImportRowResult{Row: rowNumber, Email: email, Status: "error", Reason: err.Error()})
+			failed++
+			continue
+		}
+		lead.OwnerID = admin.ID
+
+		if !dryRun {
+			if err := h.db.Create(lead).Error; err != nil {
+				results = append(results, CSVImportRowResult{Row: rowNumber, Email: email, Status: "error", Reason: fmt.Sprintf("Failed to create lead: %v", err)})
+				failed++
+				continue
+			}
+		}
+
+		existingEmails[email] = true
+		results = append(results, CSVImportRowResult{Row: rowNumber, Email: email, Status: "imported"})
+		imported++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "CSV import completed",
+		"imported": imported,
+		"skipped":  skipped,
+		"errors":   failed,
+		"rows":     results,
+		"dry_run":  dryRun,
+	})
+}
+
+// buildCSVLead creates an encrypted, segmented lead from CSV values
+func (h *LeadReengagementHandler) buildCSVLead(email, firstName, lastName, phone string) (*models.LeadReengagement, error) {
+	encryptedEmail, err := h.encryptionManager.EncryptEmail(email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt email: %v", err)
+	}
+	encryptedFirstName, err := h.encryptionManager.Encrypt(firstName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt first name: %v", err)
+	}
+	encryptedLastName, err := h.encryptionManager.Encrypt(lastName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt last name: %v", err)
+	}
+	encryptedPhone, err := h.encryptionManager.EncryptPhone(phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt phone: %v", err)
+	}
+
+	// CSV leads have no FUB contact, so derive a stable identifier from the
+	// keyed email blind index rather than a hash anyone can recompute
+	lead := &models.LeadReengagement{
+		FUBContactID:   "csv-" + h.encryptionManager.EmailBlindIndex(email),
+		Email:          encryptedEmail,
+		Phone:          encryptedPhone,
+		FirstName:      encryptedFirstName,
+		LastName:       encryptedLastName,
+		HasEmail:       true,
+		EmailValid:     true,
+		OriginalSource: "csv_import",
+		ConsentStatus:  models.ConsentUnknown,
+	}
+	lead.SetBlindIndexes(h.encryptionManager, firstName, lastName, email, phone)
+	lead.Segment = lead.CalculateSegment()
+	lead.RiskLevel = lead.CalculateRiskLevel()
+
+	return lead, nil
+}
+
+// leadEmailExists reports whether any stored lead, including deleted ones, has the email
+func (h *LeadReengagementHandler) leadEmailExists(email string) (bool, error) {
+	var count int64
+	err := h.db.Unscoped().Model(&models.LeadReengagement{}).
+		Scopes(models.LeadsWithEmail(h.encryptionManager, email)).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // SegmentLeads performs segmentation analysis on all leads
 func (h *LeadReengagementHandler) SegmentLeads(c *gin.Context) {
 	var request struct {
@@ -265,6 +587,12 @@ func (h *LeadReengagementHandler) SegmentLeads(c *gin.Context) {
 
 // GetSegmentStats returns statistics about lead segments
 func (h *LeadReengagementHandler) GetSegmentStats(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+	visible := models.LeadsVisibleTo(admin)
+
 	type SegmentStat struct {
 		Segment string `json:"segment"`
 		Count   int64  `json:"count"`
@@ -277,7 +605,7 @@ func (h *LeadReengagementHandler) GetSegmentStats(c *gin.Context) {
 
 	var stats []SegmentStat
 
-	result := h.db.Model(&models.LeadReengagement{}).
+	result := h.db.Model(&models.LeadReengagement{}).Scopes(visible).
 		Select("segment, COUNT(*) as count").
 		Group("segment").
 		Scan(&stats)
@@ -304,14 +632,14 @@ func (h *LeadReengagementHandler) GetSegmentStats(c *gin.Context) {
 
 	// Get risk level stats
 	var riskStats []RiskStat
-	h.db.Model(&models.LeadReengagement{}).
+	h.db.Model(&models.LeadReengagement{}).Scopes(visible).
 		Select("risk_level, COUNT(*) as count").
 		Group("risk_level").
 		Scan(&riskStats)
 
 	// Get campaign eligibility
 	var eligible int64
-	h.db.Model(&models.LeadReengagement{}).
+	h.db.Model(&models.LeadReengagement{}).Scopes(visible).
 		Where("has_email = ? AND email_valid = ? AND risk_level != ? AND segment != ? AND hard_bounce = ? AND previous_unsubscribe = ?",
 			true, true, models.RiskHigh, models.SegmentSuppressed, false, false).
 		Count(&eligible)
@@ -326,13 +654,20 @@ func (h *LeadReengagementHandler) GetSegmentStats(c *gin.Context) {
 
 // PrepareCampaign prepares a re-engagement campaign without activating it
 func (h *LeadReengagementHandler) PrepareCampaign(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
 	var request struct {
-		Name       string   `json:"name"`
-		Segments   []string `json:"segments"`
-		MaxVolume  int      `json:"max_volume"`
-		DailyLimit int      `json:"daily_limit"`
-		StartDate  string   `json:"start_date"`
-		TestMode   bool     `json:"test_mode"`
+		Name                   string   `json:"name"`
+		Segments               []string `json:"segments"`
+		MaxVolume              int      `json:"max_volume"`
+		DailyLimit             int      `json:"daily_limit"`
+		StartDate              string   `json:"start_date"`
+		TestMode               bool     `json:"test_mode"`
+		ExcludeOpenApprovals   *bool    `json:"exclude_open_approvals"`
+		ExcludeActivePipelines *bool    `json:"exclude_active_pipelines"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -343,13 +678,15 @@ func (h *LeadReengagementHandler) PrepareCampaign(c *gin.Context) {
 		return
 	}
 
-	// Get eligible leads
-	query := h.db.Model(&models.LeadReengagement{}).
-		Where("has_email = ? AND email_valid = ? AND risk_level != ? AND segment != ? AND hard_bounce = ? AND previous_unsubscribe = ? AND campaign_status = ?",
-			true, true, models.RiskHigh, models.SegmentSuppressed, false, false, models.CampaignPending)
-
-	if len(request.Segments) > 0 {
-		query = query.Where("segment IN ?", request.Segments)
+	// Get eligible leads, minus the campaign's suppressions
+	query, suppressed, err := h.suppressions.Apply(request.Name, h.eligibleLeadQuery(admin, request.Segments),
+		suppressionOptions(request.ExcludeOpenApprovals, request.ExcludeActivePipelines))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to apply campaign suppressions",
+			"details": err.Error(),
+		})
+		return
 	}
 
 	var eligibleLeads []models.LeadReengagement
@@ -359,6 +696,7 @@ func (h *LeadReengagementHandler) PrepareCampaign(c *gin.Context) {
 	summary := gin.H{
 		"campaign_name":      request.Name,
 		"eligible_leads":     len(eligibleLeads),
+		"suppressed_leads":   suppressed,
 		"segments_included":  request.Segments,
 		"max_volume":         request.MaxVolume,
 		"daily_limit":        request.DailyLimit,
@@ -411,7 +749,6 @@ func (h *LeadReengagementHandler) GetTemplates(c *gin.Context) {
 // CreateTemplate creates a new email template
 func (h *LeadReengagementHandler) CreateTemplate(c *gin.Context) {
 	var template models.CampaignTemplate
-
 	if err := c.ShouldBindJSON(&template); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid template format",
@@ -420,6 +757,10 @@ func (h *LeadReengagementHandler) CreateTemplate(c *gin.Context) {
 		return
 	}
 
+	if !applyTemplateVariables(c, &template) {
+		return
+	}
+
 	if err := h.db.Create(&template).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create template",
@@ -434,6 +775,47 @@ func (h *LeadReengagementHandler) CreateTemplate(c *gin.Context) {
 	})
 }
 
+// applyTemplateVariables rejects templates referencing unknown placeholders or with invalid
+// custom variables with a 400 and records the detected variables on the template. Custom
+// variables are stored on the template and rendered by the dispatcher, so each needs a
+// text value. Returns false if a response was written.
+func applyTemplateVariables(c *gin.Context, template *models.CampaignTemplate) bool {
+	customKeys := make([]string, 0, len(template.CustomVariables))
+	for key, value := range template.CustomVariables {
+		if _, ok := value.(string); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid custom variable",
+				"details": fmt.Sprintf("custom variable %q must have a text value", key),
+			})
+			return false
+		}
+		if !services.IsCampaignVariableName(key) || len(services.UnknownTemplateVariables([]string{key}, nil)) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid custom variable",
+				"details": fmt.Sprintf("custom variable %q must be a new name of letters, digits, '_' or '.'", key),
+			})
+			return false
+		}
+		customKeys = append(customKeys, key)
+	}
+	sort.Strings(customKeys)
+
+	variables := services.ExtractTemplateVariables(template.Subject, template.Body)
+
+	if unknown := services.UnknownTemplateVariables(variables, customKeys); len(unknown) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":             "Template references unknown variables",
+			"unknown_variables": unknown,
+			"allowed_variables": append(append([]string{}, services.CampaignLeadVariables...), customKeys...),
+		})
+		return false
+	}
+
+	encoded, _ := json.Marshal(variables)
+	template.Variables = string(encoded)
+	return true
+}
+
 // GetMetrics returns re-engagement campaign metrics
 func (h *LeadReengagementHandler) GetMetrics(c *gin.Context) {
 	// Get latest metrics
@@ -496,6 +878,14 @@ func (h *LeadReengagementHandler) GetSafetyStatus(c *gin.Context) {
 
 // Helper functions
 
+func isValidLeadEmail(email string) bool {
+	if email == "" {
+		return false
+	}
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
+
 func calculateCampaignDuration(totalLeads, dailyLimit int) int {
 	if dailyLimit <= 0 {
 		return 0
@@ -552,10 +942,14 @@ func calculateSafetyScore(totalLeads, highRiskLeads, todayEmails int64) float64
 }
 
 func (h *LeadReengagementHandler) GetLead(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
 
 	var lead models.LeadReengagement
-	if err := h.db.First(&lead, "id = ?", id).Error; err != nil {
+	if err := h.db.Scopes(models.LeadsVisibleTo(admin)).First(&lead, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error": "Lead not found",
@@ -570,15 +964,19 @@ func (h *LeadReengagementHandler) GetLead(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"lead": lead,
+		"lead": models.DecryptLeadReengagement(lead, h.encryptionManager),
 	})
 }
 
 func (h *LeadReengagementHandler) UpdateLead(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
 
 	var lead models.LeadReengagement
-	if err := h.db.First(&lead, "id = ?", id).Error; err != nil {
+	if err := h.db.Scopes(models.LeadsVisibleTo(admin)).First(&lead, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error": "Lead not found",
@@ -634,15 +1032,19 @@ func (h *LeadReengagementHandler) UpdateLead(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Lead updated successfully",
-		"lead":    lead,
+		"lead":    models.DecryptLeadReengagement(lead, h.encryptionManager),
 	})
 }
 
 func (h *LeadReengagementHandler) DeleteLead(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
 
 	var lead models.LeadReengagement
-	if err := h.db.First(&lead, "id = ?", id).Error; err != nil {
+	if err := h.db.Scopes(models.LeadsVisibleTo(admin)).First(&lead, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error": "Lead not found",
@@ -670,6 +1072,11 @@ func (h *LeadReengagementHandler) DeleteLead(c *gin.Context) {
 }
 
 func (h *LeadReengagementHandler) AssessRisk(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
 	var request struct {
 		LeadIDs []uint `json:"lead_ids"`
 		DryRun  bool   `json:"dry_run"`
@@ -684,7 +1091,7 @@ func (h *LeadReengagementHandler) AssessRisk(c *gin.Context) {
 	}
 
 	var leads []models.LeadReengagement
-	query := h.db.Model(&models.LeadReengagement{})
+	query := h.db.Model(&models.LeadReengagement{}).Scopes(models.LeadsVisibleTo(admin))
 
 	if len(request.LeadIDs) > 0 {
 		query = query.Where("id IN ?", request.LeadIDs)
@@ -726,20 +1133,73 @@ func (h *LeadReengagementHandler) AssessRisk(c *gin.Context) {
 	})
 }
 
+// campaignSortColumns maps the sort_by values GetCampaigns accepts to columns
+var campaignSortColumns = map[string]string{
+	"created_at":    "created_at",
+	"scheduled_for": "scheduled_for",
+	"executed_at":   "executed_at",
+	"status":        "status",
+	"campaign_name": "campaign_name",
+}
+
+// GetCampaigns retrieves campaign executions with filtering, sorting and pagination.
+// The lead and template are only preloaded when asked for with include=lead,template.
+// GET /api/v1/reengagement/campaigns?page=&limit=&status=&from=&to=&sort_by=&sort_order=&include=
 func (h *LeadReengagementHandler) GetCampaigns(c *gin.Context) {
-	var campaigns []models.CampaignExecution
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
 
-	status := c.Query("status")
+	query, err := h.campaignListQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid date range",
+			"details": err.Error(),
+		})
+		return
+	}
 
-	query := h.db.Preload("LeadReengagement").Preload("CampaignTemplate")
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to count campaigns",
+			"details": err.Error(),
+		})
+		return
+	}
 
-	if status != "" {
-		query = query.Where("status = ?", status)
+	sortColumn, ok := campaignSortColumns[c.DefaultQuery("sort_by", "created_at")]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid sort_by",
+			"details": "sort_by must be created_at, scheduled_for, executed_at, status or campaign_name",
+		})
+		return
+	}
+	sortOrder := "DESC"
+	if strings.EqualFold(c.Query("sort_order"), "asc") {
+		sortOrder = "ASC"
 	}
 
-	result := query.Order("created_at DESC").Find(&campaigns)
+	for _, include := range strings.Split(c.Query("include"), ",") {
+		switch strings.TrimSpace(include) {
+		case "lead":
+			query = query.Preload("LeadReengagement")
+		case "template":
+			query = query.Preload("CampaignTemplate")
+		}
+	}
 
-	if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
+	campaigns := []models.CampaignExecution{}
+	result := query.Order(sortColumn + " " + sortOrder).Order("id " + sortOrder).
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&campaigns)
+	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to retrieve campaigns",
 			"details": result.Error.Error(),
@@ -747,27 +1207,196 @@ func (h *LeadReengagementHandler) GetCampaigns(c *gin.Context) {
 		return
 	}
 
-	if len(campaigns) == 0 {
-		c.JSON(http.StatusOK, gin.H{
-			"campaigns": []models.CampaignExecution{},
-			"total":     0,
+	c.JSON(http.StatusOK, gin.H{
+		"campaigns": campaigns,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// campaignListQuery applies the campaign list filters (status, and a from/to
+// creation date range given as YYYY-MM-DD or RFC3339; a bare to date is inclusive)
+func (h *LeadReengagementHandler) campaignListQuery(c *gin.Context) (*gorm.DB, error) {
+	query := h.db.Model(&models.CampaignExecution{})
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if from := c.Query("from"); from != "" {
+		start, _, err := parseCampaignDate(from)
+		if err != nil {
+			return nil, fmt.Errorf("from: %w", err)
+		}
+		query = query.Where("created_at >= ?", start)
+	}
+	if to := c.Query("to"); to != "" {
+		end, dateOnly, err := parseCampaignDate(to)
+		if err != nil {
+			return nil, fmt.Errorf("to: %w", err)
+		}
+		if dateOnly {
+			query = query.Where("created_at < ?", end.AddDate(0, 0, 1))
+		} else {
+			query = query.Where("created_at <= ?", end)
+		}
+	}
+	return query, nil
+}
+
+// parseCampaignDate parses an RFC3339 timestamp or a YYYY-MM-DD date,
+// reporting whether the value was a bare date
+func parseCampaignDate(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("expected YYYY-MM-DD or RFC3339, got %q", value)
+	}
+	return t, true, nil
+}
+
+// GetFailedExecutions lists dead-lettered campaign executions for the leads
+// visible to the admin, with counts by failure reason
+// GET /api/v1/campaigns/executions/failed?campaign_name=&reason=&page=&limit=
+func (h *LeadReengagementHandler) GetFailedExecutions(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	visibleLeads := h.db.Model(&models.LeadReengagement{}).Scopes(models.LeadsVisibleTo(admin)).Select("id")
+	query := h.db.Model(&models.CampaignExecution{}).
+		Where("status = ? AND lead_reengagement_id IN (?)", "failed", visibleLeads)
+	if campaignName := c.Query("campaign_name"); campaignName != "" {
+		query = query.Where("campaign_name = ?", campaignName)
+	}
+
+	var reasons []struct {
+		FailureReason string
+		Count         int64
+	}
+	if err := query.Session(&gorm.Session{}).Select("failure_reason, COUNT(*) AS count").
+		Group("failure_reason").Scan(&reasons).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to count failed executions",
+			"details": err.Error(),
+		})
+		return
+	}
+	byReason := make(map[string]int64, len(reasons))
+	for _, reason := range reasons {
+		byReason[reason.FailureReason] = reason.Count
+	}
+
+	if reason := c.Query("reason"); reason != "" {
+		query = query.Where("failure_reason = ?", reason)
+	}
+	var total int64
+	query.Session(&gorm.Session{}).Count(&total)
+
+	executions := []models.CampaignExecution{}
+	if err := query.Order("failed_at DESC").Order("id DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&executions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve failed executions",
+			"details": err.Error(),
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"campaigns": campaigns,
-		"total":     len(campaigns),
+		"executions": executions,
+		"by_reason":  byReason,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// RequeueExecution reschedules a failed campaign execution for an immediate send
+// POST /api/v1/campaigns/executions/:id/requeue
+func (h *LeadReengagementHandler) RequeueExecution(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid execution ID",
+		})
+		return
+	}
+
+	visibleLeads := h.db.Model(&models.LeadReengagement{}).Scopes(models.LeadsVisibleTo(admin)).Select("id")
+	var visible int64
+	h.db.Model(&models.CampaignExecution{}).Where("id = ? AND lead_reengagement_id IN (?)", id, visibleLeads).Count(&visible)
+	if visible == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Campaign execution not found",
+		})
+		return
+	}
+
+	execution, err := services.RequeueFailedExecution(h.db, uint(id))
+	switch {
+	case errors.Is(err, services.ErrCampaignExecutionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Campaign execution not found",
+		})
+		return
+	case errors.Is(err, services.ErrCampaignExecutionNotFailed):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Campaign execution cannot be requeued",
+			"details": err.Error(),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to requeue campaign execution",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Campaign execution requeued",
+		"execution": execution,
 	})
 }
 
 func (h *LeadReengagementHandler) ActivateCampaign(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
 	var request struct {
-		Name       string   `json:"name"`
-		Segments   []string `json:"segments"`
-		TemplateID uint     `json:"template_id"`
-		MaxVolume  int      `json:"max_volume"`
-		DailyLimit int      `json:"daily_limit"`
+		Name                   string   `json:"name"`
+		Segments               []string `json:"segments"`
+		TemplateID             uint     `json:"template_id"`
+		MaxVolume              int      `json:"max_volume"`
+		DailyLimit             int      `json:"daily_limit"`
+		ExcludeOpenApprovals   *bool    `json:"exclude_open_approvals"`
+		ExcludeActivePipelines *bool    `json:"exclude_active_pipelines"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -786,43 +1415,53 @@ func (h *LeadReengagementHandler) ActivateCampaign(c *gin.Context) {
 		return
 	}
 
-	query := h.db.Model(&models.LeadReengagement{}).Where(
-		"has_email = ? AND email_valid = ? AND risk_level != ? AND segment != ? AND hard_bounce = ? AND previous_unsubscribe = ? AND campaign_status = ?",
-		true, true, models.RiskHigh, models.SegmentSuppressed, false, false, models.CampaignPending,
-	)
+	if request.Name == "" {
+		request.Name = template.Name
+	}
 
-	if len(request.Segments) > 0 {
-		query = query.Where("segment IN ?", request.Segments)
+	eligible, suppressed, err := h.suppressions.Apply(request.Name, h.eligibleLeadQuery(admin, request.Segments),
+		suppressionOptions(request.ExcludeOpenApprovals, request.ExcludeActivePipelines))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to apply campaign suppressions",
+			"details": err.Error(),
+		})
+		return
+	}
+	eligible, blocked, err := h.excludeUnsafeLeads(request.Name, eligible)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to apply lead safety checks",
+			"details": err.Error(),
+		})
+		return
 	}
 
-	var leads []models.LeadReengagement
-	query.Limit(request.MaxVolume).Find(&leads)
+	// A single-shot campaign is a one-step drip sent immediately
+	sendNow := 0
+	campaign, err := h.drips.CreateCampaign(request.Name, "", request.DailyLimit,
+		[]services.DripStepInput{{TemplateID: template.ID, DaysDelay: &sendNow}}, admin.ID)
+	if err != nil {
+		h.respondDripError(c, err)
+		return
+	}
 
-	activated := 0
 	now := time.Now()
-
-	for i := range leads {
-		leads[i].CampaignStatus = models.CampaignActive
-		leads[i].CampaignStarted = &now
-		h.db.Save(&leads[i])
-
-		execution := models.CampaignExecution{
-			LeadReengagementID: leads[i].ID,
-			CampaignTemplateID: template.ID,
-			ScheduledFor:       now,
-			Status:             "scheduled",
-		}
-		h.db.Create(&execution)
-
-		activated++
+	result, err := h.drips.Enroll(campaign.ID, eligible.Limit(request.MaxVolume))
+	if err != nil {
+		h.respondDripError(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":         "Campaign activated successfully",
-		"campaign_name":   request.Name,
-		"leads_activated": activated,
-		"template_used":   template.Name,
-		"activation_time": now,
+		"message":          "Campaign activated successfully",
+		"campaign_name":    request.Name,
+		"drip_campaign_id": campaign.ID,
+		"leads_activated":  result.Enrolled,
+		"leads_suppressed": suppressed,
+		"leads_blocked":    blocked,
+		"template_used":    template.Name,
+		"activation_time":  now,
 	})
 }
 
@@ -922,10 +1561,11 @@ func (h *LeadReengagementHandler) UpdateTemplate(c *gin.Context) {
 	}
 
 	var updates struct {
-		Name      *string `json:"name"`
-		Subject   *string `json:"subject"`
-		Body      *string `json:"body"`
-		DaysDelay *int    `json:"days_delay"`
+		Name            *string  `json:"name"`
+		Subject         *string  `json:"subject"`
+		Body            *string  `json:"body"`
+		DaysDelay       *int               `json:"days_delay"`
+		CustomVariables *map[string]string `json:"custom_variables"` // Replaces the stored custom variables
 	}
 
 	if err := c.ShouldBindJSON(&updates); err != nil {
@@ -949,6 +1589,16 @@ func (h *LeadReengagementHandler) UpdateTemplate(c *gin.Context) {
 		template.DaysDelay = *updates.DaysDelay
 	}
 
+	if updates.CustomVariables != nil {
+		template.CustomVariables = models.JSONB{}
+		for key, value := range *updates.CustomVariables {
+			template.CustomVariables[key] = value
+		}
+	}
+	if !applyTemplateVariables(c, &template) {
+		return
+	}
+
 	if err := h.db.Save(&template).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to update template",
@@ -963,6 +1613,84 @@ func (h *LeadReengagementHandler) UpdateTemplate(c *gin.Context) {
 	})
 }
 
+// PreviewTemplate renders a template for a lead or sample data exactly as the dispatcher would
+// POST /api/v1/reengagement/templates/:id/preview
+func (h *LeadReengagementHandler) PreviewTemplate(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
+	var template models.CampaignTemplate
+	if err := h.db.First(&template, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Template not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to retrieve template",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	var request struct {
+		LeadID     uint              `json:"lead_id"`
+		SampleData map[string]string `json:"sample_data"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	variables := template.CustomValues()
+	if request.LeadID != 0 {
+		var lead models.LeadReengagement
+		if err := h.db.Scopes(models.LeadsVisibleTo(admin)).First(&lead, "id = ?", request.LeadID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Lead not found",
+			})
+			return
+		}
+		decrypted := models.DecryptLeadReengagement(lead, h.encryptionManager)
+		for key, value := range services.CampaignTemplateVariables(decrypted.FirstName, decrypted.LastName, decrypted.Email) {
+			variables[key] = value
+		}
+		if h.tracker != nil {
+			variables["unsubscribe_url"] = h.tracker.UnsubscribeURL(lead.ID, 0)
+		}
+	}
+
+	// Sample data overrides lead and custom values
+	for key, value := range request.SampleData {
+		variables[key] = value
+	}
+
+	unresolved := []string{}
+	empty := []string{}
+	for _, name := range services.ExtractTemplateVariables(template.Subject, template.Body) {
+		value, ok := variables[name]
+		if !ok {
+			unresolved = append(unresolved, name)
+		} else if strings.TrimSpace(value) == "" {
+			empty = append(empty, name)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"template_id":          template.ID,
+		"subject":              services.RenderCampaignTemplate(template.Subject, variables),
+		"body":                 services.RenderCampaignHTML(template.Body, variables),
+		"unresolved_variables": unresolved,
+		"empty_variables":      empty,
+	})
+}
+
 func (h *LeadReengagementHandler) DeleteTemplate(c *gin.Context) {
 	id := c.Param("id")
 
@@ -994,6 +1722,18 @@ func (h *LeadReengagementHandler) DeleteTemplate(c *gin.Context) {
 	})
 }
 
+// GetRateShapingStats returns per-domain send counts from the campaign dispatcher's rate shaper
+func (h *LeadReengagementHandler) GetRateShapingStats(c *gin.Context) {
+	if h.rateShaper == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Campaign dispatcher is not running",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.rateShaper.Stats())
+}
+
 func (h *LeadReengagementHandler) GetDailyMetrics(c *gin.Context) {
 	date := c.Query("date")
 
@@ -1033,27 +1773,40 @@ func (h *LeadReengagementHandler) GetDailyMetrics(c *gin.Context) {
 }
 
 func (h *LeadReengagementHandler) GetComplianceReport(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+	visible := models.LeadsVisibleTo(admin)
+
 	var consentStats []struct {
 		ConsentStatus string `json:"consent_status"`
 		Count         int64  `json:"count"`
 	}
 
-	h.db.Model(&models.LeadReengagement{}).Select("consent_status, COUNT(*) as count").Group("consent_status").Scan(&consentStats)
+	h.db.Model(&models.LeadReengagement{}).Scopes(visible).Select("consent_status, COUNT(*) as count").Group("consent_status").Scan(&consentStats)
 
 	var totalLeads, consentedLeads, revokedLeads, unknownConsent int64
-	h.db.Model(&models.LeadReengagement{}).Count(&totalLeads)
-	h.db.Model(&models.LeadReengagement{}).Where("consent_status = ?", models.ConsentExpress).Count(&consentedLeads)
-	h.db.Model(&models.LeadReengagement{}).Where("consent_status = ?", models.ConsentRevoked).Count(&revokedLeads)
-	h.db.Model(&models.LeadReengagement{}).Where("consent_status = ?", models.ConsentUnknown).Count(&unknownConsent)
+	h.db.Model(&models.LeadReengagement{}).Scopes(visible).Count(&totalLeads)
+	h.db.Model(&models.LeadReengagement{}).Scopes(visible).Where("consent_status = ?", models.ConsentExpress).Count(&consentedLeads)
+	h.db.Model(&models.LeadReengagement{}).Scopes(visible).Where("consent_status = ?", models.ConsentRevoked).Count(&revokedLeads)
+	h.db.Model(&models.LeadReengagement{}).Scopes(visible).Where("consent_status = ?", models.ConsentUnknown).Count(&unknownConsent)
 
 	var suppressedCount int64
-	h.db.Model(&models.LeadReengagement{}).Where("segment = ?", models.SegmentSuppressed).Count(&suppressedCount)
+	h.db.Model(&models.LeadReengagement{}).Scopes(visible).Where("segment = ?", models.SegmentSuppressed).Count(&suppressedCount)
 
 	var dncCount int64
-	h.db.Model(&models.LeadReengagement{}).Where("on_dnc_list = ?", true).Count(&dncCount)
+	h.db.Model(&models.LeadReengagement{}).Scopes(visible).Where("on_dnc_list = ?", true).Count(&dncCount)
 
 	var unsubscribedCount int64
-	h.db.Model(&models.LeadReengagement{}).Where("previous_unsubscribe = ?", true).Count(&unsubscribedCount)
+	h.db.Model(&models.LeadReengagement{}).Scopes(visible).Where("previous_unsubscribe = ?", true).Count(&unsubscribedCount)
+
+	// Leads that must never be contacted, decrypted so admins can verify them
+	var flaggedLeads []models.LeadReengagement
+	h.db.Scopes(visible).Where("on_dnc_list = ? OR consent_status = ? OR previous_unsubscribe = ?", true, models.ConsentRevoked, true).
+		Order("updated_at DESC").
+		Limit(100).
+		Find(&flaggedLeads)
 
 	complianceScore := 100.0
 	if totalLeads > 0 {
@@ -1073,6 +1826,7 @@ func (h *LeadReengagementHandler) GetComplianceReport(c *gin.Context) {
 			"compliance_score":   complianceScore,
 		},
 		"consent_breakdown": consentStats,
+		"flagged_leads":     models.DecryptLeadReengagementList(flaggedLeads, h.encryptionManager),
 		"generated_at":      time.Now(),
 		"recommendations": []string{
 			"Document consent for all unknown leads",
@@ -1140,6 +1894,21 @@ func (h *LeadReengagementHandler) EmergencyStop(c *gin.Context) {
 }
 
 func (h *LeadReengagementHandler) EmergencyStopAll(c *gin.Context) {
+	var request struct {
+		Reason      string `json:"reason"`
+		ActivatedBy string `json:"activated_by"`
+	}
+	c.ShouldBindJSON(&request)
+	if request.Reason == "" {
+		request.Reason = "Manual emergency stop"
+	}
+	if request.ActivatedBy == "" {
+		request.ActivatedBy = "admin"
+	}
+
+	// Halt the dispatcher first so nothing else goes out while rows are updated
+	h.emergencyControls.ActivateEmergencyStop(request.Reason, request.ActivatedBy)
+
 	result := h.db.Model(&models.LeadReengagement{}).Where("campaign_status = ?", models.CampaignActive).Update("campaign_status", models.CampaignSuppressed)
 
 	if result.Error != nil {
@@ -1158,6 +1927,96 @@ func (h *LeadReengagementHandler) EmergencyStopAll(c *gin.Context) {
 	})
 }
 
+// GetEmergencyStatus reports whether the campaign emergency stop is active
+func (h *LeadReengagementHandler) GetEmergencyStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"status":  h.emergencyControls.GetStatus(),
+	})
+}
+
+// DeactivateEmergencyStop clears the emergency stop so the dispatcher resumes sending.
+// Executions skipped during the stop are not rescheduled automatically.
+func (h *LeadReengagementHandler) DeactivateEmergencyStop(c *gin.Context) {
+	if !h.emergencyControls.IsEmergencyActive() {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Emergency stop is not active",
+		})
+		return
+	}
+
+	h.emergencyControls.DeactivateEmergencyStop()
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Emergency stop deactivated",
+		"timestamp": time.Now(),
+	})
+}
+
+// TrackOpen serves the open-tracking pixel and records the first open per execution
+func (h *LeadReengagementHandler) TrackOpen(c *gin.Context) {
+	executionID, err := strconv.ParseUint(strings.TrimSuffix(c.Param("executionID"), ".gif"), 10, 64)
+	if err == nil {
+		h.recordFirstEngagement(uint(executionID), "email_opened", "emails_opened")
+	}
+
+	// Always serve the pixel so mail clients never show a broken image
+	c.Header("Cache-Control", "no-store, no-cache, must-revalidate, private")
+	c.Data(http.StatusOK, "image/gif", services.TrackingPixelGIF)
+}
+
+// TrackClick records the first click per execution and redirects to the original link
+func (h *LeadReengagementHandler) TrackClick(c *gin.Context) {
+	target := c.Query("u")
+	parsed, err := url.Parse(target)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid redirect URL",
+		})
+		return
+	}
+
+	executionID, err := strconv.ParseUint(c.Param("executionID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid execution ID",
+		})
+		return
+	}
+
+	if h.tracker == nil || !h.tracker.VerifyClick(uint(executionID), target, c.Query("s")) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Invalid tracking signature",
+		})
+		return
+	}
+
+	h.recordFirstEngagement(uint(executionID), "email_clicked", "emails_clicked")
+
+	c.Redirect(http.StatusFound, target)
+}
+
+// recordFirstEngagement flips an engagement flag on an execution only once, so
+// repeated opens or clicks never inflate the lead's counters
+func (h *LeadReengagementHandler) recordFirstEngagement(executionID uint, executionField, leadCounter string) {
+	result := h.db.Model(&models.CampaignExecution{}).
+		Where("id = ? AND "+executionField+" = ?", executionID, false).
+		Update(executionField, true)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return
+	}
+
+	var execution models.CampaignExecution
+	if err := h.db.Select("id", "lead_reengagement_id").First(&execution, executionID).Error; err != nil {
+		return
+	}
+
+	h.db.Model(&models.LeadReengagement{}).
+		Where("id = ?", execution.LeadReengagementID).
+		Update(leadCounter, gorm.Expr(leadCounter+" + 1"))
+}
+
 func generateDailyMetrics(db *gorm.DB, date time.Time) models.ReengagementMetrics {
 	var metrics models.ReengagementMetrics
 	var activeCount, dormantCount, suppressedCount int64
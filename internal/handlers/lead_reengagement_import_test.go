@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+)
+
+func TestImportLeadsCSV_ValidatesDedupsAndEncrypts(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&security.EncryptionKey{}, &models.LeadReengagement{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	key := make([]byte, 32)
+	rand.Read(key)
+	t.Setenv("ENCRYPTION_KEY", base64.StdEncoding.EncodeToString(key))
+	em, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create encryption manager: %v", err)
+	}
+
+	existing := models.LeadReengagement{FUBContactID: "fub-1", OwnerID: "agent-2"}
+	existing.SetBlindIndexes(em, "", "", "bob@example.com", "")
+	db.Create(&existing)
+
+	handler := NewLeadReengagementHandler(db, em)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user", &models.AdminUser{ID: "agent-1"}) })
+	router.POST("/leads/import-csv", handler.ImportLeadsCSV)
+
+	// Header aliases, a byte order mark and a column the importer doesn't know
+	csvData := "\ufeffEmail Address,First Name,Last Name,Mobile,Notes\n" +
+		"Ann@Example.com,Ann,Lee,713-555-0100,met at open house\n" +
+		"not-an-email,Cy,Day,,\n" +
+		"ann@example.com,Ann,Lee,,\n" +
+		"bob@example.com,Bob,Ray,,\n"
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "leads.csv")
+	part.Write([]byte(csvData))
+	form.Close()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/leads/import-csv", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Imported int                  `json:"imported"`
+		Skipped  int                  `json:"skipped"`
+		Errors   int                  `json:"errors"`
+		Rows     []CSVImportRowResult `json:"rows"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.Imported != 1 || response.Skipped != 3 || response.Errors != 0 {
+		t.Fatalf("Expected 1 imported and 3 skipped, got %+v", response)
+	}
+	expected := []CSVImportRowResult{
+		{Row: 2, Email: "ann@example.com", Status: "imported"},
+		{Row: 3, Email: "not-an-email", Status: "skipped", Reason: "Invalid or missing email address"},
+		{Row: 4, Email: "ann@example.com", Status: "skipped", Reason: "Duplicate email"},
+		{Row: 5, Email: "bob@example.com", Status: "skipped", Reason: "Duplicate email"},
+	}
+	for i, row := range expected {
+		if i >= len(response.Rows) || response.Rows[i] != row {
+			t.Errorf("Expected rows %+v, got %+v", expected, response.Rows)
+			break
+		}
+	}
+
+	var lead models.LeadReengagement
+	if err := db.Where("owner_id = ?", "agent-1").First(&lead).Error; err != nil {
+		t.Fatalf("Expected the imported lead owned by the importer: %v", err)
+	}
+	if string(lead.Email) == "ann@example.com" || string(lead.FirstName) == "Ann" || string(lead.Phone) == "713-555-0100" {
+		t.Error("Expected the lead's PII stored encrypted")
+	}
+	for field, want := range map[security.EncryptedString]string{lead.Email: "ann@example.com", lead.FirstName: "Ann", lead.LastName: "Lee"} {
+		if got, err := em.Decrypt(field); err != nil || got != want {
+			t.Errorf("Expected %q after decrypting, got %q (%v)", want, got, err)
+		}
+	}
+	if lead.EmailHash != em.EmailBlindIndex("ann@example.com") || lead.PhoneHash != em.PhoneBlindIndex("(713) 555-0100") {
+		t.Error("Expected blind indexes for the email and phone lookups")
+	}
+	if lead.FUBContactID != "csv-"+lead.EmailHash {
+		t.Errorf("Expected the contact ID derived from the keyed email index, got %q", lead.FUBContactID)
+	}
+	if lead.OriginalSource != "csv_import" {
+		t.Errorf("Expected the lead sourced from the CSV import, got %q", lead.OriginalSource)
+	}
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// LeadRoutingHandlers assigns leads to agents by routing rules or by hand
+type LeadRoutingHandlers struct {
+	db          *gorm.DB
+	assignments *services.LeadAssignmentService
+}
+
+// NewLeadRoutingHandlers creates lead routing handlers
+func NewLeadRoutingHandlers(db *gorm.DB, assignments *services.LeadAssignmentService) *LeadRoutingHandlers {
+	return &LeadRoutingHandlers{db: db, assignments: assignments}
+}
+
+// CreateLead handles POST /api/v1/leads, adding a lead by hand and routing it
+// to an agent when auto-routing is on
+func (h *LeadRoutingHandlers) CreateLead(c *gin.Context) {
+	var input struct {
+		FirstName string `json:"first_name" binding:"required"`
+		LastName  string `json:"last_name" binding:"required"`
+		Email     string `json:"email" binding:"required,email"`
+		Phone     string `json:"phone"`
+		City      string `json:"city"`
+		State     string `json:"state"`
+		ZipCode   string `json:"zip_code"`
+		Source    string `json:"source"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	lead := models.Lead{
+		FirstName: strings.TrimSpace(input.FirstName),
+		LastName:  strings.TrimSpace(input.LastName),
+		Email:     strings.ToLower(strings.TrimSpace(input.Email)),
+		Phone:     input.Phone,
+		City:      input.City,
+		State:     input.State,
+		Source:    input.Source,
+		Status:    "new",
+	}
+	if lead.Source == "" {
+		lead.Source = "Manual"
+	}
+	if input.ZipCode != "" {
+		lead.CustomFields = models.JSONB{"zip_code": input.ZipCode}
+	}
+	if err := h.db.Create(&lead).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create lead", "details": err.Error()})
+		return
+	}
+
+	h.assignments.AutoRoute(&lead)
+	c.JSON(http.StatusCreated, gin.H{"success": true, "lead": lead})
+}
+
+// RouteLead handles POST /api/v1/leads/:id/route, assigning the lead to the
+// agent the configured routing strategy picks
+func (h *LeadRoutingHandlers) RouteLead(c *gin.Context) {
+	admin, ok := routingAdmin(c)
+	if !ok {
+		return
+	}
+	leadID, ok := leadIDParam(c)
+	if !ok {
+		return
+	}
+
+	assignment, err := h.assignments.RouteLead(leadID, admin.ID)
+	if err != nil {
+		leadRoutingErrorResponse(c, "Failed to route lead", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "assignment": assignment})
+}
+
+// ReassignLead handles POST /api/v1/leads/:id/reassign, assigning the lead to
+// the agent an admin picked
+func (h *LeadRoutingHandlers) ReassignLead(c *gin.Context) {
+	admin, ok := routingAdmin(c)
+	if !ok {
+		return
+	}
+	leadID, ok := leadIDParam(c)
+	if !ok {
+		return
+	}
+	var input struct {
+		AgentID string `json:"agent_id" binding:"required"`
+		Reason  string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	assignment, err := h.assignments.ReassignLead(leadID, input.AgentID, admin.ID, input.Reason)
+	if err != nil {
+		leadRoutingErrorResponse(c, "Failed to reassign lead", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "assignment": assignment})
+}
+
+// GetLeadAssignments handles GET /api/v1/leads/:id/assignments, listing the
+// lead's assignment history
+func (h *LeadRoutingHandlers) GetLeadAssignments(c *gin.Context) {
+	leadID, ok := leadIDParam(c)
+	if !ok {
+		return
+	}
+	assignments, err := h.assignments.ListAssignments(leadID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load assignments", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"assignments": assignments})
+}
+
+// GetRoutingConfig handles GET /api/v1/lead-routing/config
+func (h *LeadRoutingHandlers) GetRoutingConfig(c *gin.Context) {
+	config, err := h.assignments.Config()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load routing config", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"config": config})
+}
+
+// UpdateRoutingConfig handles PUT /api/v1/lead-routing/config, choosing round
+// robin, weighted or territory routing. Omitted fields keep their current values.
+func (h *LeadRoutingHandlers) UpdateRoutingConfig(c *gin.Context) {
+	admin, ok := routingAdmin(c)
+	if !ok {
+		return
+	}
+	if !models.IsTeamAdminRole(admin.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Changing lead routing requires a team admin"})
+		return
+	}
+
+	config, err := h.assignments.Config()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load routing config", "details": err.Error()})
+		return
+	}
+	if err := c.ShouldBindJSON(&config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if err := config.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid routing config", "details": err.Error()})
+		return
+	}
+
+	config, err = h.assignments.UpdateConfig(config, admin.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update routing config", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "config": config})
+}
+
+// UpdateAgentProfile handles PUT /api/v1/lead-routing/agents/:agent_id,
+// setting an agent's weight, territories, daily limit and whether they take leads
+func (h *LeadRoutingHandlers) UpdateAgentProfile(c *gin.Context) {
+	admin, ok := routingAdmin(c)
+	if !ok {
+		return
+	}
+	if !models.IsTeamAdminRole(admin.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Changing lead routing requires a team admin"})
+		return
+	}
+
+	var agent models.AdminUser
+	if err := h.db.First(&agent, "id = ?", c.Param("agent_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
+		return
+	}
+
+	profile := models.AgentRoutingProfile{AgentID: agent.ID, Accepting: true, Weight: 1}
+	h.db.Where("agent_id = ?", agent.ID).Limit(1).Find(&profile)
+	if err := c.ShouldBindJSON(&profile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+	profile.AgentID = agent.ID
+	if profile.Weight < 1 || profile.MaxDailyLeads < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid routing profile", "details": "weight must be at least 1 and max_daily_leads not negative"})
+		return
+	}
+
+	if err := h.db.Save(&profile).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update routing profile", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "profile": profile})
+}
+
+// routingAdmin returns the signed-in admin, responding 401 if there is none
+func routingAdmin(c *gin.Context) (*models.AdminUser, bool) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return nil, false
+	}
+	return admin, true
+}
+
+// leadIDParam parses the :id lead parameter, responding 400 if it is invalid
+func leadIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lead ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// leadRoutingErrorResponse maps lead assignment errors to a status code
+func leadRoutingErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrRoutingLeadNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lead not found"})
+	case errors.Is(err, services.ErrRoutingAgentInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": message, "details": err.Error()})
+	default:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": message, "details": err.Error()})
+	}
+}
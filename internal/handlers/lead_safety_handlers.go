@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// leadSafetyExclusionLimit caps the recent exclusions shown with a safety evaluation
+const leadSafetyExclusionLimit = 20
+
+// LeadSafetyHandlers shows whether leads may be contacted by campaigns and FUB pushes
+type LeadSafetyHandlers struct {
+	db     *gorm.DB
+	safety *services.LeadSafetyFilter
+}
+
+// NewLeadSafetyHandlers creates lead safety handlers
+func NewLeadSafetyHandlers(db *gorm.DB, safety *services.LeadSafetyFilter) *LeadSafetyHandlers {
+	return &LeadSafetyHandlers{db: db, safety: safety}
+}
+
+// GetLeadSafety handles GET /api/v1/leads/:id/safety, returning the lead's
+// current safety evaluation and the campaigns and pushes it was excluded from
+func (h *LeadSafetyHandlers) GetLeadSafety(c *gin.Context) {
+	leadID, ok := leadIDParam(c)
+	if !ok {
+		return
+	}
+	var lead models.Lead
+	if err := h.db.First(&lead, leadID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lead not found"})
+		return
+	}
+
+	key := h.safety.SafetyKeyForLead(&lead)
+	classification := h.safety.ClassifyLead(map[string]interface{}{"id": key})
+	exclusions, err := h.safety.GetLeadExclusions(key, leadSafetyExclusionLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load safety exclusions", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"lead_id":        lead.ID,
+		"safety_key":     key,
+		"blocked":        classification.IsBlocked(),
+		"classification": classification,
+		"exclusions":     exclusions,
+	})
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"html"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+)
+
+// leadSearchCandidateLimit caps how many index matches are decrypted and ranked per search
+const leadSearchCandidateLimit = 500
+
+// LeadSearchMatch describes where a search term matched a lead field
+type LeadSearchMatch struct {
+	Field       string `json:"field"`
+	Value       string `json:"value"`
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	Highlighted string `json:"highlighted"` // HTML-escaped value with the match wrapped in <mark>
+}
+
+// LeadSearchResult is a ranked search hit
+type LeadSearchResult struct {
+	Lead    models.LeadReengagementResponse `json:"lead"`
+	Score   float64                         `json:"score"`
+	Matches []LeadSearchMatch               `json:"matches"`
+}
+
+// leadSearchField is a decrypted lead field considered when ranking
+type leadSearchField struct {
+	name   string
+	value  string
+	weight float64
+}
+
+// SearchLeads finds leads by partial name, email, or phone. Candidates are found
+// through the blind search index, then decrypted and ranked in memory.
+func (h *LeadReengagementHandler) SearchLeads(c *gin.Context) {
+	admin, ok := h.leadOwner(c)
+	if !ok {
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	words := security.SearchWords(query)
+
+	// Full emails and phone numbers are looked up directly by their blind index;
+	// anything else must match every trigram of the query
+	var lookup func(*gorm.DB) *gorm.DB
+	switch {
+	case isValidLeadEmail(query):
+		lookup = models.LeadsWithEmail(h.encryptionManager, query)
+	case isLeadPhoneQuery(query):
+		lookup = models.LeadsWithPhone(h.encryptionManager, query)
+		words = []string{leadPhoneDigits(query)}
+	default:
+		hashes := h.encryptionManager.SearchQueryHashes(query)
+		if len(hashes) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid search query",
+				"details": "q must contain at least one term of 3 or more characters",
+			})
+			return
+		}
+		lookup = models.LeadsMatchingSearch(hashes)
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	var candidates []models.LeadReengagement
+	if err := h.db.Scopes(models.LeadsVisibleTo(admin), lookup).
+		Order("updated_at DESC").
+		Limit(leadSearchCandidateLimit).
+		Find(&candidates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to search leads",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	results := []LeadSearchResult{}
+	for _, lead := range candidates {
+		if result, matched := rankLeadSearch(models.DecryptLeadReengagement(lead, h.encryptionManager), words); matched {
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	total := len(results)
+	if total > limit {
+		results = results[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":     query,
+		"results":   results,
+		"total":     total,
+		"truncated": len(candidates) == leadSearchCandidateLimit,
+	})
+}
+
+// isLeadPhoneQuery reports whether a query is a complete phone number
+func isLeadPhoneQuery(query string) bool {
+	digits := 0
+	for _, r := range query {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case !strings.ContainsRune(" ()+-.", r):
+			return false
+		}
+	}
+	return digits >= 10 && digits <= 11
+}
+
+// leadPhoneDigits returns a phone number's digits without a leading US country code
+func leadPhoneDigits(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+	if len(digits) == 11 && strings.HasPrefix(digits, "1") {
+		return digits[1:]
+	}
+	return digits
+}
+
+// rankLeadSearch scores a decrypted lead against every query word. A lead only
+// matches when each word is found in at least one field; trigram index hits
+// whose grams are not contiguous are dropped here.
+func rankLeadSearch(lead models.LeadReengagementResponse, words []string) (LeadSearchResult, bool) {
+	fields := []leadSearchField{
+		{name: "first_name", value: lead.FirstName, weight: 1.0},
+		{name: "last_name", value: lead.LastName, weight: 1.0},
+		{name: "email", value: lead.Email, weight: 0.8},
+		{name: "phone", value: lead.Phone, weight: 0.8},
+	}
+
+	result := LeadSearchResult{Lead: lead}
+	matchedFields := map[string]int{}
+
+	for _, word := range words {
+		bestScore := 0.0
+		var bestMatch LeadSearchMatch
+		for _, field := range fields {
+			score, start, end := matchLeadSearchField(field.value, word)
+			if score*field.weight > bestScore {
+				bestScore = score * field.weight
+				bestMatch = LeadSearchMatch{Field: field.name, Value: field.value, Start: start, End: end}
+			}
+		}
+		if bestScore == 0 {
+			return result, false
+		}
+
+		result.Score += bestScore
+		if i, seen := matchedFields[bestMatch.Field]; seen {
+			// Keep the longest match per field
+			if bestMatch.End-bestMatch.Start > result.Matches[i].End-result.Matches[i].Start {
+				result.Matches[i] = bestMatch
+			}
+			continue
+		}
+		matchedFields[bestMatch.Field] = len(result.Matches)
+		result.Matches = append(result.Matches, bestMatch)
+	}
+
+	for i := range result.Matches {
+		match := &result.Matches[i]
+		match.Highlighted = html.EscapeString(match.Value[:match.Start]) +
+			"<mark>" + html.EscapeString(match.Value[match.Start:match.End]) + "</mark>" +
+			html.EscapeString(match.Value[match.End:])
+	}
+
+	return result, true
+}
+
+// matchLeadSearchField scores how well a lowercase word matches a field value and
+// returns the byte range of the match. Exact matches rank above prefixes, word
+// prefixes, and plain substrings. Numeric words also match the value's digits.
+func matchLeadSearchField(value, word string) (float64, int, int) {
+	if value == "" || word == "" {
+		return 0, 0, 0
+	}
+
+	lower := strings.ToLower(value)
+	if len(lower) == len(value) {
+		if idx := strings.Index(lower, word); idx >= 0 {
+			return leadSearchPositionScore(lower, idx, len(word)), idx, idx + len(word)
+		}
+	}
+
+	if strings.IndexFunc(word, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0 {
+		return 0, 0, 0
+	}
+
+	// Match digits across formatting, e.g. "5551234" in "(555) 123-4567"
+	positions := []int{}
+	digits := strings.Builder{}
+	for i, r := range value {
+		if unicode.IsDigit(r) {
+			positions = append(positions, i)
+			digits.WriteRune(r)
+		}
+	}
+	idx := strings.Index(digits.String(), word)
+	if idx < 0 {
+		return 0, 0, 0
+	}
+	return leadSearchPositionScore(digits.String(), idx, len(word)), positions[idx], positions[idx+len(word)-1] + 1
+}
+
+// leadSearchPositionScore ranks a match by where it falls in the text
+func leadSearchPositionScore(text string, idx, length int) float64 {
+	switch {
+	case idx == 0 && length == len(text):
+		return 100
+	case idx == 0:
+		return 75
+	case !unicode.IsLetter(rune(text[idx-1])) && !unicode.IsDigit(rune(text[idx-1])):
+		return 50
+	default:
+		return 25
+	}
+}
@@ -3,18 +3,26 @@ package handlers
 import (
 	"log"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"chrisgross-ctrl-project/internal/auth"
+	"chrisgross-ctrl-project/internal/models"
 	"chrisgross-ctrl-project/internal/security"
+	"chrisgross-ctrl-project/internal/services"
 )
 
 type MFAHandler struct {
-	db          *gorm.DB
-	authManager auth.AuthenticationManager
-	totpManager *security.TOTPManager
+	db             *gorm.DB
+	authManager    auth.AuthenticationManager
+	totpManager    *security.TOTPManager
+	backupCodes    *services.MFABackupCodeService
+	adminTOTP      *services.AdminTOTPService
+	reverification *services.MFAReverificationService
 }
 
 func NewMFAHandler(db *gorm.DB, authManager auth.AuthenticationManager) *MFAHandler {
@@ -22,9 +30,20 @@ func NewMFAHandler(db *gorm.DB, authManager auth.AuthenticationManager) *MFAHand
 		db:          db,
 		authManager: authManager,
 		totpManager: security.NewTOTPManager(db),
+		backupCodes: services.NewMFABackupCodeService(db),
 	}
 }
 
+// SetReverification enables MFA re-verification for sensitive operations
+func (h *MFAHandler) SetReverification(reverification *services.MFAReverificationService) {
+	h.reverification = reverification
+}
+
+// SetAdminTOTP enables TOTP enrollment for admin users
+func (h *MFAHandler) SetAdminTOTP(adminTOTP *services.AdminTOTPService) {
+	h.adminTOTP = adminTOTP
+}
+
 // SetupMFA handles MFA setup for users
 func (h *MFAHandler) SetupMFA(w http.ResponseWriter, r *http.Request) {
 	userIDStr := r.Header.Get("X-User-ID")
@@ -53,12 +72,19 @@ func (h *MFAHandler) SetupMFA(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate secret and backup codes
-	key, backupCodes, err := h.totpManager.GenerateSecret(uint(userID), user.Email)
+	key, err := h.totpManager.GenerateSecret(uint(userID), user.Email)
 	if err != nil {
 		http.Error(w, "Failed to generate MFA secret", http.StatusInternalServerError)
 		return
 	}
 
+	// Backup codes are stored hashed, so this is the only time they are returned
+	backupCodes, err := h.backupCodes.IssueBackupCodes(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to generate backup codes", http.StatusInternalServerError)
+		return
+	}
+
 	response := map[string]interface{}{
 		"success":      true,
 		"secret":       key.Secret(),
@@ -104,18 +130,26 @@ func (h *MFAHandler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	usedBackupCode := false
 	if !valid {
-		// Try backup code verification
-		validBackup, err := h.totpManager.VerifyBackupCode(uint(userID), request.Code, ipAddress, userAgent)
+		// Try backup code verification; a matching code is consumed
+		validBackup, err := h.backupCodes.ConsumeBackupCode(userIDStr, request.Code)
+		h.totpManager.LogBackupCodeAttempt(uint(userID), ipAddress, userAgent, err == nil && validBackup)
 		if err != nil || !validBackup {
 			http.Error(w, "Invalid code", http.StatusUnauthorized)
 			return
 		}
+		usedBackupCode = true
 	}
 
 	response := map[string]interface{}{
-		"success": true,
-		"message": "MFA verification successful",
+		"success":          true,
+		"message":          "MFA verification successful",
+		"used_backup_code": usedBackupCode,
+	}
+	if usedBackupCode {
+		remaining, _ := h.backupCodes.RemainingBackupCodes(userIDStr)
+		response["backup_codes_remaining"] = remaining
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -138,7 +172,8 @@ func (h *MFAHandler) GetMFAStatus(w http.ResponseWriter, r *http.Request) {
 
 	// Get MFA status using actual TOTPManager methods
 	status := h.totpManager.GetMFAStatus(uint(userID))
-	
+	status["backup_codes_count"], _ = h.backupCodes.RemainingBackupCodes(userIDStr)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
@@ -166,6 +201,11 @@ func (h *MFAHandler) DisableMFA(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.backupCodes.ClearBackupCodes(userIDStr); err != nil {
+		http.Error(w, "Failed to invalidate backup codes", http.StatusInternalServerError)
+		return
+	}
+
 	response := map[string]interface{}{
 		"success": true,
 		"message": "MFA disabled successfully",
@@ -175,6 +215,256 @@ func (h *MFAHandler) DisableMFA(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// RegenerateBackupCodes replaces the signed-in admin's backup codes, invalidating
+// any unused ones, and returns the new codes once. A current TOTP code or an
+// unused backup code is required, so a session alone cannot mint codes.
+// POST /api/v1/admin/mfa/backup-codes/regenerate
+func (h *MFAHandler) RegenerateBackupCodes(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	if h.adminTOTP == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "MFA is not available"})
+		return
+	}
+
+	var request struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": "a current TOTP code or an unused backup code is required",
+		})
+		return
+	}
+
+	valid, err := h.adminTOTP.Verify(admin.ID, request.Code)
+	if errors.Is(err, services.ErrMFANotConfigured) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "MFA is not set up",
+			"details": "enable TOTP at /api/v1/admin/mfa/totp/enroll first",
+		})
+		return
+	}
+	if err == nil && !valid {
+		valid, err = h.backupCodes.ConsumeBackupCode(admin.ID, request.Code)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to verify code",
+			"details": err.Error(),
+		})
+		return
+	}
+	if !valid {
+		log.Printf("🔐 MFA backup code regeneration refused for %s from %s: invalid code", admin.Username, c.ClientIP())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid code"})
+		return
+	}
+
+	codes, err := h.backupCodes.IssueBackupCodes(admin.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to regenerate backup codes",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Printf("🔐 MFA backup codes regenerated for %s", admin.Username)
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"backup_codes": codes,
+		"message":      "Store these codes securely; they will not be shown again",
+	})
+}
+
+// EnrollTOTP starts TOTP enrollment for the signed-in admin and returns the
+// secret for their authenticator app. The account password is required so a
+// stolen session cannot enroll its own authenticator.
+// POST /api/v1/admin/mfa/totp/enroll
+func (h *MFAHandler) EnrollTOTP(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	if h.adminTOTP == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "TOTP enrollment is not available"})
+		return
+	}
+
+	var request struct {
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+	// The session user is loaded without its password hash
+	var account models.AdminUser
+	if err := h.db.Select("id", "password_hash").First(&account, "id = ?", admin.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to load admin account",
+			"details": err.Error(),
+		})
+		return
+	}
+	if !security.CheckPasswordHash(request.Password, account.PasswordHash) {
+		log.Printf("🔐 TOTP enrollment refused for %s from %s: wrong password", admin.Username, c.ClientIP())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid password"})
+		return
+	}
+
+	key, err := h.adminTOTP.Enroll(admin)
+	if errors.Is(err, services.ErrMFAAlreadyEnabled) {
+		c.JSON(http.StatusConflict, gin.H{"error": "TOTP is already enabled"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to start TOTP enrollment",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"secret":  key.Secret(),
+		"qr_url":  key.URL(),
+		"message": "Add the secret to your authenticator app, then confirm a code at /api/v1/admin/mfa/totp/confirm",
+	})
+}
+
+// ConfirmTOTP enables the signed-in admin's pending TOTP enrollment with a code
+// from their authenticator app and returns their first backup codes
+// POST /api/v1/admin/mfa/totp/confirm
+func (h *MFAHandler) ConfirmTOTP(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	if h.adminTOTP == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "TOTP enrollment is not available"})
+		return
+	}
+
+	var request struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	err := h.adminTOTP.Confirm(admin.ID, request.Code)
+	switch {
+	case errors.Is(err, services.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": "TOTP is already enabled"})
+		return
+	case errors.Is(err, services.ErrMFANotEnrolled):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "No pending TOTP enrollment",
+			"details": "start one at /api/v1/admin/mfa/totp/enroll first",
+		})
+		return
+	case errors.Is(err, services.ErrMFACodeInvalid):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid code"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to enable TOTP",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	codes, err := h.backupCodes.IssueBackupCodes(admin.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "TOTP enabled but backup codes could not be issued",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Printf("🔐 TOTP enabled for %s", admin.Username)
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"backup_codes": codes,
+		"message":      "TOTP enabled. Store these backup codes securely; they will not be shown again",
+	})
+}
+
+// ReverifyMFA confirms the signed-in admin's second factor with a code from
+// their authenticator app, unlocking operations guarded by
+// RequireMFAReverification for a few minutes
+// POST /api/v1/admin/mfa/reverify
+func (h *MFAHandler) ReverifyMFA(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	if h.reverification == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "MFA re-verification is not available"})
+		return
+	}
+
+	var request struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	until, err := h.reverification.Reverify(admin.ID, request.Code)
+	switch {
+	case errors.Is(err, services.ErrMFANotConfigured):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "MFA is not set up",
+			"details": "enable TOTP at /api/v1/admin/mfa/totp/enroll first",
+		})
+		return
+	case errors.Is(err, services.ErrMFACodeInvalid):
+		log.Printf("🔐 MFA re-verification failed for %s from %s", admin.Username, c.ClientIP())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid code"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "MFA re-verification failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Printf("🔐 MFA re-verified for %s", admin.Username)
+	c.JSON(http.StatusOK, gin.H{
+		"success":            true,
+		"verified_until":     until,
+		"expires_in_seconds": int(time.Until(until).Seconds()),
+	})
+}
+
 // RegisterMFARoutes registers all MFA routes
 func RegisterMFARoutes(mux *http.ServeMux, db *gorm.DB, authManager auth.AuthenticationManager) {
 	handler := NewMFAHandler(db, authManager)
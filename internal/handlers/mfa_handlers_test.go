@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pquerna/otp/totp"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+// newAdminUsersTestDB opens an in-memory database with the admin_users table.
+// admin_users uses a postgres uuid default, so it is created by hand for sqlite.
+func newAdminUsersTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	err = db.Exec(`CREATE TABLE admin_users (
+		id TEXT PRIMARY KEY, username TEXT, email TEXT, password_hash TEXT, role TEXT, active BOOLEAN,
+		last_login DATETIME, login_count INTEGER, created_at DATETIME, updated_at DATETIME,
+		mfa_backup_codes TEXT, mfa_backup_codes_generated_at DATETIME, mfa_totp_secret TEXT, mfa_totp_enabled_at DATETIME)`).Error
+	if err != nil {
+		t.Fatalf("Failed to create admin_users table: %v", err)
+	}
+	return db
+}
+
+func TestRegenerateBackupCodes_RequiresSecondFactor(t *testing.T) {
+	db := newAdminUsersTestDB(t)
+	if err := db.AutoMigrate(&security.EncryptionKey{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	admin := &models.AdminUser{ID: "admin-1", Username: "agent", Email: "agent@example.com", PasswordHash: "x"}
+	db.Create(admin)
+
+	key := make([]byte, 32)
+	rand.Read(key)
+	t.Setenv("ENCRYPTION_KEY", base64.StdEncoding.EncodeToString(key))
+	em, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create encryption manager: %v", err)
+	}
+	adminTOTP := services.NewAdminTOTPService(db, em)
+	handler := NewMFAHandler(db, nil)
+	handler.SetAdminTOTP(adminTOTP)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user", admin) })
+	router.POST("/admin/mfa/backup-codes/regenerate", handler.RegenerateBackupCodes)
+
+	regenerate := func(code string) (int, []string) {
+		body, _ := json.Marshal(map[string]string{"code": code})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/admin/mfa/backup-codes/regenerate", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		var resp struct {
+			BackupCodes []string `json:"backup_codes"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.BackupCodes
+	}
+
+	if code, _ := regenerate(""); code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 without a code, got %d", code)
+	}
+	if code, _ := regenerate("123456"); code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 before TOTP is enabled, got %d", code)
+	}
+
+	otpKey, err := adminTOTP.Enroll(admin)
+	if err != nil {
+		t.Fatalf("Enroll failed: %v", err)
+	}
+	enableCode, _ := totp.GenerateCode(otpKey.Secret(), time.Now())
+	if err := adminTOTP.Confirm(admin.ID, enableCode); err != nil {
+		t.Fatalf("Confirm failed: %v", err)
+	}
+	codes, _ := services.NewMFABackupCodeService(db).IssueBackupCodes(admin.ID)
+
+	if code, _ := regenerate("not-a-code"); code != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 for an invalid code, got %d", code)
+	}
+
+	status, fresh := regenerate(codes[0])
+	if status != http.StatusOK || len(fresh) == 0 {
+		t.Fatalf("Expected an unused backup code to allow regeneration, got %d", status)
+	}
+	if code, _ := regenerate(codes[1]); code != http.StatusUnauthorized {
+		t.Errorf("Expected the replaced backup codes to be rejected, got %d", code)
+	}
+
+	// TOTP codes are accepted once, and the enrollment code was already used
+	if code, _ := regenerate(enableCode); code != http.StatusUnauthorized {
+		t.Errorf("Expected a replayed TOTP code to be rejected, got %d", code)
+	}
+}
@@ -1,15 +1,60 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
+	"strconv"
 	"time"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
 	"chrisgross-ctrl-project/internal/services"
 	"chrisgross-ctrl-project/internal/utils"
 )
 
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// parsePagination reads page/limit/offset query params with sane bounds.
+// An explicit offset takes precedence over page.
+func parsePagination(c *gin.Context) (page, limit, offset int) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageLimit)))
+	if err != nil || limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	page, err = strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	offset = (page - 1) * limit
+
+	if rawOffset := c.Query("offset"); rawOffset != "" {
+		if parsed, err := strconv.Atoi(rawOffset); err == nil && parsed >= 0 {
+			offset = parsed
+			page = offset/limit + 1
+		}
+	}
+
+	return page, limit, offset
+}
+
+// paginationMeta builds the pagination object returned with list responses
+func paginationMeta(page, limit int, total int64) gin.H {
+	return gin.H{
+		"page":        page,
+		"limit":       limit,
+		"total":       total,
+		"total_pages": (total + int64(limit) - 1) / int64(limit),
+	}
+}
+
 // ============================================================================
 // CONTEXT FUB HANDLERS (5 endpoints)
 // ============================================================================
@@ -58,23 +103,40 @@ func GetContextFUBLogs(c *gin.Context) {
 func GetCommunicationHistory(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
 	
-	limit := 50
-	offset := 0
+	page, limit, offset := parsePagination(c)
+	query := db.Model(&models.IncomingEmail{})
+	
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to count communication history", err)
+		return
+	}
 	
 	// Get email history from IncomingEmail model
 	var incomingEmails []models.IncomingEmail
-	err := db.Order("created_at DESC").Limit(limit).Offset(offset).Find(&incomingEmails).Error
+	err := query.Session(&gorm.Session{}).Order("created_at DESC").Limit(limit).Offset(offset).Find(&incomingEmails).Error
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch communication history", err)
 		return
 	}
 	
-	var total int64
-	db.Model(&models.IncomingEmail{}).Count(&total)
+	emailIDs := make([]uint, len(incomingEmails))
+	for i, email := range incomingEmails {
+		emailIDs[i] = email.ID
+	}
+	attachments, err := models.AttachmentsForEmails(db, emailIDs)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch attachments", err)
+		return
+	}
 	
 	// Transform to response format
 	history := make([]gin.H, len(incomingEmails))
 	for i, email := range incomingEmails {
+		emailAttachments := attachments[email.ID]
+		if emailAttachments == nil {
+			emailAttachments = []models.IncomingEmailAttachment{}
+		}
 		history[i] = gin.H{
 			"id":          email.ID,
 			"from":        email.FromEmail,
@@ -84,14 +146,16 @@ func GetCommunicationHistory(c *gin.Context) {
 			"status":      email.ProcessingStatus,
 			"received_at": email.ReceivedAt,
 			"created_at":  email.CreatedAt,
+			"attachments": emailAttachments,
 		}
 	}
 	
 	c.JSON(http.StatusOK, gin.H{
-		"history": history,
-		"total":   total,
-		"limit":   limit,
-		"offset":  offset,
+		"history":    history,
+		"total":      total,
+		"limit":      limit,
+		"offset":     offset,
+		"pagination": paginationMeta(page, limit, total),
 	})
 }
 
@@ -186,7 +250,19 @@ func PostCommunicationSendEmail(c *gin.Context) {
 	})
 }
 
-func PostCommunicationSendSMS(c *gin.Context) {
+// CommunicationSMSHandlers sends SMS once the recipient's consent is verified
+type CommunicationSMSHandlers struct {
+	consentService *services.SMSConsentService
+}
+
+// NewCommunicationSMSHandlers creates SMS handlers sharing the server's encryption manager
+func NewCommunicationSMSHandlers(db *gorm.DB, encryptionManager *security.EncryptionManager) *CommunicationSMSHandlers {
+	return &CommunicationSMSHandlers{
+		consentService: services.NewSMSConsentService(db, encryptionManager),
+	}
+}
+
+func (h *CommunicationSMSHandlers) PostCommunicationSendSMS(c *gin.Context) {
 	var request struct {
 		To      string `json:"to" binding:"required"`
 		Message string `json:"message" binding:"required"`
@@ -197,13 +273,36 @@ func PostCommunicationSendSMS(c *gin.Context) {
 		return
 	}
 
+	// TCPA: require documented consent before any outbound SMS
+	decision, err := h.consentService.CheckConsent(request.To)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to verify SMS consent", err)
+		return
+	}
+	if !decision.Allowed {
+		if logErr := h.consentService.RecordSend(request.To, decision, "blocked", decision.Reason, len(request.Message)); logErr != nil {
+			log.Printf("⚠️  Failed to record blocked SMS: %v", logErr)
+		}
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "SMS blocked: consent not verified",
+			"reason":  decision.Reason,
+		})
+		return
+	}
+
+	if logErr := h.consentService.RecordSend(request.To, decision, "sent", "", len(request.Message)); logErr != nil {
+		log.Printf("⚠️  Failed to record SMS send: %v", logErr)
+	}
+
 	// TODO: Integrate with Twilio or other SMS provider
 	// For now, return success with note about provider setup needed
 	c.JSON(http.StatusOK, gin.H{
 		"message": "SMS sent successfully (provider integration pending)",
 		"sms_id":  time.Now().Format("20060102150405"),
-		"to":      request.To,
-		"note":    "SMS provider (Twilio) integration required for actual delivery",
+		"to":            request.To,
+		"consent_basis": decision.Basis,
+		"note":          "SMS provider (Twilio) integration required for actual delivery",
 	})
 }
 
@@ -288,20 +387,23 @@ func GetCommunicationStats(c *gin.Context) {
 func GetCommunicationInbox(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
 	
-	limit := 50
-	offset := 0
+	page, limit, offset := parsePagination(c)
+	query := db.Model(&models.IncomingEmail{}).Where("processing_status IN ?", []string{models.ProcessingStatusPending, models.ProcessingStatusRequiresReview})
+	
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to count inbox", err)
+		return
+	}
 	
 	// Get pending/unprocessed emails
 	var incomingEmails []models.IncomingEmail
-	err := db.Where("processing_status IN ?", []string{models.ProcessingStatusPending, models.ProcessingStatusRequiresReview}).Order("created_at DESC").Limit(limit).Offset(offset).Find(&incomingEmails).Error
+	err := query.Session(&gorm.Session{}).Order("created_at DESC").Limit(limit).Offset(offset).Find(&incomingEmails).Error
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch inbox", err)
 		return
 	}
 	
-	var total int64
-	db.Model(&models.IncomingEmail{}).Where("processing_status IN ?", []string{models.ProcessingStatusPending, models.ProcessingStatusRequiresReview}).Count(&total)
-	
 	// Transform to response format
 	messages := make([]gin.H, len(incomingEmails))
 	for i, email := range incomingEmails {
@@ -319,9 +421,10 @@ func GetCommunicationInbox(c *gin.Context) {
 	}
 	
 	c.JSON(http.StatusOK, gin.H{
-		"messages": messages,
-		"total":    total,
-		"unread":   total,
+		"messages":   messages,
+		"total":      total,
+		"unread":     total,
+		"pagination": paginationMeta(page, limit, total),
 	})
 }
 
@@ -421,6 +524,14 @@ func GetEmailParsedApplications(c *gin.Context) {
 func GetEmailParsingStats(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
 	emailProcessor := services.NewEmailProcessor(db)
+	if raw := c.Query("low_confidence_threshold"); raw != "" {
+		threshold, err := strconv.ParseFloat(raw, 64)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "low_confidence_threshold must be between 0 and 1", err)
+			return
+		}
+		emailProcessor.SetLowConfidenceThreshold(threshold)
+	}
 	
 	// Get processing stats from service
 	stats, err := emailProcessor.GetEmailProcessingStats()
@@ -434,8 +545,10 @@ func GetEmailParsingStats(c *gin.Context) {
 			"total_parsed":   stats.TotalEmails,
 			"successful":     stats.ProcessedEmails,
 			"failed":         stats.FailedEmails,
-			"low_confidence": stats.LowConfidence,
-			"last_processed": stats.LastProcessed,
+			"low_confidence":           stats.LowConfidence,
+			"low_confidence_threshold": stats.LowConfidenceThreshold,
+			"confidence_buckets":       stats.ConfidenceBuckets,
+			"last_processed":           stats.LastProcessed,
 		},
 	})
 }
@@ -487,20 +600,23 @@ func PostEmailRetryParsing(c *gin.Context) {
 func GetEmailParsingLogs(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
 	
-	limit := 50
-	offset := 0
+	page, limit, offset := parsePagination(c)
+	query := db.Model(&models.IncomingEmail{})
+	
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to count parsing logs", err)
+		return
+	}
 	
 	// Get all incoming emails with processing info
 	var incomingEmails []models.IncomingEmail
-	err := db.Order("created_at DESC").Limit(limit).Offset(offset).Find(&incomingEmails).Error
+	err := query.Session(&gorm.Session{}).Order("created_at DESC").Limit(limit).Offset(offset).Find(&incomingEmails).Error
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch parsing logs", err)
 		return
 	}
 	
-	var total int64
-	db.Model(&models.IncomingEmail{}).Count(&total)
-	
 	// Transform to response format
 	logs := make([]gin.H, len(incomingEmails))
 	for i, email := range incomingEmails {
@@ -517,8 +633,9 @@ func GetEmailParsingLogs(c *gin.Context) {
 	}
 	
 	c.JSON(http.StatusOK, gin.H{
-		"logs":  logs,
-		"total": total,
+		"logs":       logs,
+		"total":      total,
+		"pagination": paginationMeta(page, limit, total),
 	})
 }
 
@@ -564,6 +681,7 @@ func PutLead(c *gin.Context) {
 		State     string `json:"state"`
 		Status    string `json:"status"`
 		Source    string `json:"source"`
+		Version   *int   `json:"version"`
 	}
 	
 	if err := c.ShouldBindJSON(&updateReq); err != nil {
@@ -571,6 +689,15 @@ func PutLead(c *gin.Context) {
 		return
 	}
 	
+	if updateReq.Version == nil {
+		respondVersionRequired(c)
+		return
+	}
+	if err := models.CheckVersion(&lead, *updateReq.Version); err != nil {
+		respondVersionConflict(c, lead)
+		return
+	}
+	
 	lead.FirstName = updateReq.FirstName
 	lead.LastName = updateReq.LastName
 	lead.Email = updateReq.Email
@@ -580,7 +707,14 @@ func PutLead(c *gin.Context) {
 	lead.Status = updateReq.Status
 	lead.Source = updateReq.Source
 	
-	if err := db.Save(&lead).Error; err != nil {
+	if err := models.SaveVersioned(db, &lead, *updateReq.Version); err != nil {
+		if err == models.ErrVersionConflict {
+			var current models.Lead
+			if db.First(&current, id).Error == nil {
+				respondVersionConflict(c, current)
+				return
+			}
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update lead", err)
 		return
 	}
@@ -748,7 +882,25 @@ func PutPreListing(c *gin.Context) {
 		return
 	}
 	
-	if err := db.Model(&preListing).Updates(updateReq).Error; err != nil {
+	expectedVersion := updateReq.Version
+	if expectedVersion == 0 {
+		respondVersionRequired(c)
+		return
+	}
+	if err := models.CheckVersion(&preListing, expectedVersion); err != nil {
+		respondVersionConflict(c, preListing)
+		return
+	}
+	
+	updateReq.ID = 0
+	if err := models.UpdateVersioned(db, &preListing, &updateReq, expectedVersion); err != nil {
+		if err == models.ErrVersionConflict {
+			var current models.PreListingItem
+			if db.First(&current, id).Error == nil {
+				respondVersionConflict(c, current)
+				return
+			}
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update pre-listing", err)
 		return
 	}
@@ -802,7 +954,7 @@ func GetPreListingStats(c *gin.Context) {
 }
 
 // ============================================================================
-// VALUATION HANDLERS (5 endpoints)
+// VALUATION HANDLERS (3 endpoints)
 // ============================================================================
 
 func PostValuationRequest(c *gin.Context) {
@@ -825,41 +977,10 @@ func DeleteValuationRequest(c *gin.Context) {
 	})
 }
 
-func GetValuationStats(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"stats": gin.H{
-			"total_requests": 0,
-			"completed": 0,
-			"pending": 0,
-		},
-	})
-}
-
-func PostValuationBulkRequest(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Bulk valuation request initiated",
-		"batch_id": "stub-bulk-1",
-	})
-}
-
 // ============================================================================
 // SECURITY HANDLERS (3 endpoints)
 // ============================================================================
 
-func PostSecurityEventResolve(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Security event resolved",
-		"id": c.Param("id"),
-	})
-}
-
-func GetSecurityAuditLogs(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"logs": []interface{}{},
-		"total": 0,
-	})
-}
-
 func GetSecurityComplianceReport(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"report": gin.H{
@@ -972,7 +1093,7 @@ func DeleteWebhook(c *gin.Context) {
 }
 
 // ============================================================================
-// MISC HANDLERS (4 endpoints)
+// MISC HANDLERS (3 endpoints)
 // ============================================================================
 
 func GetApprovalByID(c *gin.Context) {
@@ -989,13 +1110,6 @@ func GetClosingPipelineByID(c *gin.Context) {
 	})
 }
 
-func PutClosingPipelineStatus(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Status updated",
-		"id": c.Param("id"),
-	})
-}
-
 func GetAgentStats(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"stats": gin.H{
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// PerformanceMonitoringHandlers exposes request latency and throughput metrics
+type PerformanceMonitoringHandlers struct {
+	monitor *services.PerformanceMonitoringService
+}
+
+// NewPerformanceMonitoringHandlers creates performance monitoring handlers; monitor may be nil
+func NewPerformanceMonitoringHandlers(monitor *services.PerformanceMonitoringService) *PerformanceMonitoringHandlers {
+	return &PerformanceMonitoringHandlers{monitor: monitor}
+}
+
+// GetMetrics returns per-route request metrics and collected service/system stats
+// GET /api/v1/performance/metrics
+func (h *PerformanceMonitoringHandlers) GetMetrics(c *gin.Context) {
+	if h.monitor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Performance monitoring not available",
+			"details": "Redis is not configured",
+		})
+		return
+	}
+
+	requests := h.monitor.GetRequestMetrics()
+
+	var totalRequests, totalErrors int64
+	var throughput float64
+	for _, metric := range requests {
+		totalRequests += metric.Count
+		totalErrors += metric.ErrorCount
+		throughput += metric.RequestsPerMinute
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"generated_at": time.Now(),
+		"summary": gin.H{
+			"total_requests":      totalRequests,
+			"total_errors":        totalErrors,
+			"requests_per_minute": throughput,
+			"routes_tracked":      len(requests),
+		},
+		"requests": requests,
+		"services": h.monitor.GetCurrentStats(),
+	})
+}
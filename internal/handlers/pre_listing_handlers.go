@@ -422,6 +422,17 @@ func (h *PreListingHandlers) UpdatePreListingItem(w http.ResponseWriter, r *http
 		return
 	}
 
+	versionValue, ok := updateData["version"].(float64)
+	if !ok {
+		writeVersionRequired(w)
+		return
+	}
+	expectedVersion := int(versionValue)
+	if err := models.CheckVersion(&item, expectedVersion); err != nil {
+		writeVersionConflict(w, item)
+		return
+	}
+
 	// Update allowed fields
 	if status, ok := updateData["status"].(string); ok {
 		item.Status = status
@@ -443,7 +454,14 @@ func (h *PreListingHandlers) UpdatePreListingItem(w http.ResponseWriter, r *http
 		}
 	}
 
-	if err := h.db.Save(&item).Error; err != nil {
+	if err := models.SaveVersioned(h.db, &item, expectedVersion); err != nil {
+		if err == models.ErrVersionConflict {
+			var current models.PreListingItem
+			if h.db.First(&current, id).Error == nil {
+				writeVersionConflict(w, current)
+				return
+			}
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// PrivacyHandlers serves data subject export and erasure requests
+type PrivacyHandlers struct {
+	privacy *services.PrivacyService
+}
+
+// NewPrivacyHandlers creates privacy request handlers
+func NewPrivacyHandlers(privacy *services.PrivacyService) *PrivacyHandlers {
+	return &PrivacyHandlers{privacy: privacy}
+}
+
+// privacySubjectRequest is the body of an export or erasure request. The
+// subject's identity must be verified before the request is made.
+type privacySubjectRequest struct {
+	Email              string `json:"email" binding:"required,email"`
+	Verified           bool   `json:"verified"`
+	VerificationMethod string `json:"verification_method" binding:"required"`
+	Format             string `json:"format"` // export only: json (default) or zip
+	Confirm            bool   `json:"confirm"`
+}
+
+// ExportSubjectData handles POST /api/v1/privacy/export, downloading every
+// record held about the subject as JSON or a ZIP holding the JSON
+func (h *PrivacyHandlers) ExportSubjectData(c *gin.Context) {
+	req, admin, ok := h.bindSubjectRequest(c)
+	if !ok {
+		return
+	}
+	if req.Format != "" && req.Format != "json" && req.Format != "zip" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format", "details": "format must be json or zip"})
+		return
+	}
+
+	export, err := h.privacy.Export(services.PrivacySubjectRequest{
+		Email:              req.Email,
+		VerificationMethod: req.VerificationMethod,
+		RequestedBy:        admin,
+	})
+	if err != nil {
+		h.requestFailed(c, "Failed to export subject data", err)
+		return
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode subject data", "details": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("privacy-export-%d", export.RequestID)
+	contentType := "application/json"
+	if req.Format == "zip" {
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+		file, err := archive.Create(filename + ".json")
+		if err == nil {
+			_, err = file.Write(data)
+		}
+		if err == nil {
+			err = archive.Close()
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build export archive", "details": err.Error()})
+			return
+		}
+		data, contentType = buf.Bytes(), "application/zip"
+		filename += ".zip"
+	} else {
+		filename += ".json"
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// EraseSubjectData handles POST /api/v1/privacy/erasure, anonymizing or
+// deleting every record held about the subject. Requires "confirm": true.
+func (h *PrivacyHandlers) EraseSubjectData(c *gin.Context) {
+	req, admin, ok := h.bindSubjectRequest(c)
+	if !ok {
+		return
+	}
+	if !req.Confirm {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erasure not confirmed", "details": "set confirm to true; erasure cannot be undone"})
+		return
+	}
+
+	erasure, err := h.privacy.Erase(services.PrivacySubjectRequest{
+		Email:              req.Email,
+		VerificationMethod: req.VerificationMethod,
+		RequestedBy:        admin,
+	})
+	if err != nil {
+		h.requestFailed(c, "Failed to erase subject data", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "erasure": erasure})
+}
+
+// GetPrivacyRequests handles GET /api/v1/privacy/requests, listing the latest
+// ?limit= export and erasure requests (default 50)
+func (h *PrivacyHandlers) GetPrivacyRequests(c *gin.Context) {
+	if _, ok := privacyAdmin(c); !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit", "details": "limit must be between 1 and 500"})
+		return
+	}
+
+	requests, err := h.privacy.RecentRequests(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load privacy requests", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "requests": requests, "count": len(requests)})
+}
+
+func (h *PrivacyHandlers) bindSubjectRequest(c *gin.Context) (*privacySubjectRequest, *models.AdminUser, bool) {
+	admin, ok := privacyAdmin(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	var req privacySubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return nil, nil, false
+	}
+	if !req.Verified {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Identity not verified", "details": "verify the subject's identity and set verified to true"})
+		return nil, nil, false
+	}
+	return &req, admin, true
+}
+
+func (h *PrivacyHandlers) requestFailed(c *gin.Context, message string, err error) {
+	if errors.Is(err, services.ErrInvalidPrivacyRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid privacy request", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message, "details": err.Error()})
+}
+
+// privacyAdmin returns the signed-in admin when they may handle privacy
+// requests, which expose and remove PII across every agent's leads
+func privacyAdmin(c *gin.Context) (*models.AdminUser, bool) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return nil, false
+	}
+	if !models.IsTeamAdminRole(admin.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Privacy requests require a team admin"})
+		return nil, false
+	}
+	return admin, true
+}
@@ -21,6 +21,7 @@ type PropertiesHandler struct {
 	repos             *repositories.Repositories
 	encryptionManager *security.EncryptionManager
 	behavioralService *services.BehavioralEventService // ADDED: Behavioral tracking
+	trashService      *services.PropertyTrashService
 }
 
 func NewPropertiesHandler(db *gorm.DB, repos *repositories.Repositories, encryptionManager *security.EncryptionManager) *PropertiesHandler {
@@ -29,6 +30,7 @@ func NewPropertiesHandler(db *gorm.DB, repos *repositories.Repositories, encrypt
 		repos:             repos,
 		encryptionManager: encryptionManager,
 		behavioralService: services.NewBehavioralEventService(db), // ADDED: Initialize tracking service
+		trashService:      services.NewPropertyTrashService(db),
 	}
 }
 
@@ -673,6 +675,7 @@ func (h *PropertiesHandler) UpdateProperty(w http.ResponseWriter, r *http.Reques
 		HarUrl            *string   `json:"har_url"`
 		YearBuilt         *int      `json:"year_built"`
 		ManagementCompany *string   `json:"management_company"`
+		Version           *int      `json:"version"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -680,6 +683,15 @@ func (h *PropertiesHandler) UpdateProperty(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if req.Version == nil {
+		writeVersionRequired(w)
+		return
+	}
+	if err := models.CheckVersion(&property, *req.Version); err != nil {
+		writeVersionConflict(w, models.ToResponse(property, h.encryptionManager))
+		return
+	}
+
 	// Update fields if provided
 	if req.MLSId != nil {
 		property.MLSId = *req.MLSId
@@ -760,7 +772,14 @@ func (h *PropertiesHandler) UpdateProperty(w http.ResponseWriter, r *http.Reques
 
 	property.UpdatedAt = time.Now()
 
-	if err := h.db.Save(&property).Error; err != nil {
+	if err := models.SaveVersioned(h.db, &property, *req.Version); err != nil {
+		if err == models.ErrVersionConflict {
+			var current models.Property
+			if h.db.First(&current, propertyID).Error == nil {
+				writeVersionConflict(w, models.ToResponse(current, h.encryptionManager))
+				return
+			}
+		}
 		log.Printf("Error updating property: %v", err)
 		http.Error(w, "Failed to update property", http.StatusInternalServerError)
 		return
@@ -797,21 +816,12 @@ func (h *PropertiesHandler) DeleteProperty(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	var property models.Property
-	if err := h.db.First(&property, propertyID).Error; err != nil {
+	actor := services.PropertyAuditActor{Name: "admin", IPAddress: r.RemoteAddr}
+	if _, err := h.trashService.DeleteProperty(uint(propertyID), actor, ""); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			http.Error(w, "Property not found", http.StatusNotFound)
 			return
 		}
-		http.Error(w, "Failed to find property", http.StatusInternalServerError)
-		return
-	}
-
-	// Soft delete by setting status to deleted
-	property.Status = "deleted"
-	property.UpdatedAt = time.Now()
-
-	if err := h.db.Save(&property).Error; err != nil {
 		log.Printf("Error deleting property: %v", err)
 		http.Error(w, "Failed to delete property", http.StatusInternalServerError)
 		return
@@ -912,7 +922,8 @@ func (h *PropertiesHandler) GetPropertiesGin(c *gin.Context) {
 	// Convert to response format with decrypted addresses
 	propertyResponses := models.ToResponseList(properties, h.encryptionManager)
 
-	c.JSON(http.StatusOK, gin.H{
+	// Lists can shrink without any row changing, so only the ETag validates them
+	respondCachedJSON(c, gin.H{
 		"success": true,
 		"data": gin.H{
 			"properties":  propertyResponses,
@@ -922,7 +933,7 @@ func (h *PropertiesHandler) GetPropertiesGin(c *gin.Context) {
 			"total_pages": (total + int64(limit) - 1) / int64(limit),
 		},
 		"stats": stats,
-	})
+	}, time.Time{}, propertyCacheControl)
 }
 
 // GetPropertyByIDGin returns a single property by ID with decrypted address (Gin version)
@@ -956,10 +967,10 @@ func (h *PropertiesHandler) GetPropertyByIDGin(c *gin.Context) {
 	// Convert to response format with decrypted address
 	propertyResponse := models.ToResponse(property, h.encryptionManager)
 
-	c.JSON(http.StatusOK, gin.H{
+	respondCachedJSON(c, gin.H{
 		"success": true,
 		"data":    propertyResponse,
-	})
+	}, property.UpdatedAt, propertyCacheControl)
 }
 
 func (h *PropertiesHandler) SearchPropertiesPost(c *gin.Context) {
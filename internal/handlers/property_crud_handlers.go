@@ -11,6 +11,7 @@ import (
 	"gorm.io/gorm"
 	"chrisgross-ctrl-project/internal/models"
 	"chrisgross-ctrl-project/internal/security"
+	"chrisgross-ctrl-project/internal/services"
 )
 
 // CreatePropertyRequest represents the request body for creating a property
@@ -56,6 +57,7 @@ type UpdatePropertyRequest struct {
 	YearBuilt         *int     `json:"year_built,omitempty"`
 	ManagementCompany *string  `json:"management_company,omitempty"`
 	Source            *string  `json:"source,omitempty"`
+	Version           *int     `json:"version"` // version the client read; required
 }
 
 // PropertyCRUDHandler handles property CRUD operations
@@ -168,6 +170,15 @@ func (h *PropertyCRUDHandler) UpdateProperty(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if req.Version == nil {
+		writeVersionRequired(w)
+		return
+	}
+	if err := models.CheckVersion(&property, *req.Version); err != nil {
+		writeVersionConflict(w, property)
+		return
+	}
+
 	// Update fields if provided (fix Address type conversion)
 	if req.Address != nil {
 		property.Address = security.EncryptedString(*req.Address) // Convert string to EncryptedString
@@ -217,7 +228,14 @@ func (h *PropertyCRUDHandler) UpdateProperty(w http.ResponseWriter, r *http.Requ
 
 	property.UpdatedAt = time.Now()
 
-	if err := h.db.Save(&property).Error; err != nil {
+	if err := models.SaveVersioned(h.db, &property, *req.Version); err != nil {
+		if err == models.ErrVersionConflict {
+			var current models.Property
+			if h.db.First(&current, propertyID).Error == nil {
+				writeVersionConflict(w, current)
+				return
+			}
+		}
 		http.Error(w, "Failed to update property", http.StatusInternalServerError)
 		return
 	}
@@ -247,45 +265,14 @@ func (h *PropertyCRUDHandler) DeleteProperty(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Check if property exists
-	var property models.Property
-	if err := h.db.First(&property, propertyID).Error; err != nil {
+	// Soft delete so the property can be restored from the trash; bookings keep
+	// their property reference
+	actor := services.PropertyAuditActor{Name: "admin", IPAddress: r.RemoteAddr}
+	if _, err := services.NewPropertyTrashService(h.db).DeleteProperty(uint(propertyID), actor, ""); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			http.Error(w, "Property not found", http.StatusNotFound)
 			return
 		}
-		http.Error(w, "Failed to find property", http.StatusInternalServerError)
-		return
-	}
-
-	// Check for related bookings
-	var bookingCount int64
-	h.db.Model(&models.Booking{}).Where("property_id = ?", propertyID).Count(&bookingCount)
-
-	if bookingCount > 0 {
-		// Soft delete - change status instead of removing
-		property.Status = "deleted"
-		property.UpdatedAt = time.Now()
-
-		if err := h.db.Save(&property).Error; err != nil {
-			http.Error(w, "Failed to delete property", http.StatusInternalServerError)
-			return
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": true,
-			"message": "Property marked as deleted (has existing bookings)",
-			"data": map[string]interface{}{
-				"property_id": propertyID,
-				"soft_delete": true,
-			},
-		})
-		return
-	}
-
-	// Hard delete if no bookings
-	if err := h.db.Delete(&property).Error; err != nil {
 		http.Error(w, "Failed to delete property", http.StatusInternalServerError)
 		return
 	}
@@ -296,7 +283,7 @@ func (h *PropertyCRUDHandler) DeleteProperty(w http.ResponseWriter, r *http.Requ
 		"message": "Property deleted successfully",
 		"data": map[string]interface{}{
 			"property_id": propertyID,
-			"soft_delete": false,
+			"soft_delete": true,
 		},
 	})
 }
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// DeletePropertyGin moves a property to the trash
+// DELETE /api/v1/properties/:id
+func (h *PropertiesHandler) DeletePropertyGin(c *gin.Context) {
+	propertyID, ok := parsePropertyIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	// The body is optional
+	_ = c.ShouldBindJSON(&req)
+
+	property, err := h.trashService.DeleteProperty(propertyID, propertyAuditActor(c), req.Reason)
+	if err != nil {
+		respondPropertyTrashError(c, err, "Failed to delete property")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Property moved to trash",
+		"data": gin.H{
+			"property_id": property.ID,
+		},
+	})
+}
+
+// ListDeletedProperties returns soft-deleted properties
+// GET /api/v1/properties/trash
+func (h *PropertiesHandler) ListDeletedProperties(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	properties, err := h.trashService.ListTrash(limit)
+	if err != nil {
+		log.Printf("Error listing deleted properties: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch deleted properties",
+		})
+		return
+	}
+
+	items := make([]gin.H, 0, len(properties))
+	for _, property := range properties {
+		items = append(items, gin.H{
+			"property":   models.ToResponse(property, h.encryptionManager),
+			"deleted_at": property.DeletedAt.Time,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"properties": items,
+			"count":      len(items),
+		},
+	})
+}
+
+// RestoreProperty brings a property back from the trash
+// POST /api/v1/properties/:id/restore
+func (h *PropertiesHandler) RestoreProperty(c *gin.Context) {
+	propertyID, ok := parsePropertyIDParam(c)
+	if !ok {
+		return
+	}
+
+	property, err := h.trashService.RestoreProperty(propertyID, propertyAuditActor(c))
+	if err != nil {
+		respondPropertyTrashError(c, err, "Failed to restore property")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Property restored",
+		"data":    models.ToResponse(*property, h.encryptionManager),
+	})
+}
+
+func parsePropertyIDParam(c *gin.Context) (uint, bool) {
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || propertyID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid property ID",
+		})
+		return 0, false
+	}
+	return uint(propertyID), true
+}
+
+// propertyAuditActor identifies the signed-in admin for the property audit trail
+func propertyAuditActor(c *gin.Context) services.PropertyAuditActor {
+	actor := services.PropertyAuditActor{Name: "admin", IPAddress: c.ClientIP()}
+	if value, exists := c.Get("user"); exists {
+		if admin, ok := value.(*models.AdminUser); ok && admin != nil {
+			actor.ID = admin.ID
+			actor.Name = admin.Username
+		}
+	}
+	return actor
+}
+
+func respondPropertyTrashError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Property not found",
+		})
+	case errors.Is(err, services.ErrPropertyNotDeleted):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "Property is not in the trash",
+		})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   message,
+		})
+	}
+}
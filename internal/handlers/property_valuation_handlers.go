@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/scraper"
 	"chrisgross-ctrl-project/internal/services"
 )
 
@@ -35,6 +36,9 @@ func RegisterPropertyValuationRoutes(router *gin.Engine, db *gorm.DB, valuationS
 		// Property valuation
 		valuation.POST("/estimate", handlers.GetPropertyValuation)
 		valuation.POST("/bulk-estimate", handlers.GetBulkValuations)
+		valuation.POST("/bulk", handlers.PostValuationBulkRequest)
+		valuation.GET("/bulk/:batch_id", handlers.GetValuationBulkStatus)
+		valuation.GET("/stats", handlers.GetValuationStats)
 		valuation.GET("/property/:id", handlers.GetPropertyValuationByID)
 		
 		// Market analysis
@@ -59,6 +63,8 @@ func RegisterPropertyValuationRoutes(router *gin.Engine, db *gorm.DB, valuationS
 		valuation.POST("/config", handlers.UpdateValuationConfig)
 		valuation.POST("/calibrate", handlers.CalibrateValuationModel)
 		valuation.POST("/test", handlers.TestValuationAccuracy)
+		valuation.GET("/cache/stats", handlers.GetValuationCacheStats)
+		valuation.GET("/scraper/health", handlers.GetScraperHealth)
 	}
 }
 
@@ -73,6 +79,9 @@ func (h *PropertyValuationHandlers) GetPropertyValuation(c *gin.Context) {
 		})
 		return
 	}
+	if c.Query("force_refresh") == "true" {
+		request.ForceRefresh = true
+	}
 
 	valuation, err := h.valuationService.ValuateProperty(request)
 	if err != nil {
@@ -91,6 +100,128 @@ func (h *PropertyValuationHandlers) GetPropertyValuation(c *gin.Context) {
 	})
 }
 
+// GetValuationCacheStats returns valuation cache hit/miss counters
+func (h *PropertyValuationHandlers) GetValuationCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.valuationService.GetValuationCacheStats(),
+	})
+}
+
+// PostValuationBulkRequest queues a batch of properties for background valuation
+func (h *PropertyValuationHandlers) PostValuationBulkRequest(c *gin.Context) {
+	var request struct {
+		Properties []services.PropertyValuationRequest `json:"properties" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid bulk valuation request format",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	requestedBy := "api"
+	if user, exists := c.Get("user"); exists {
+		if admin, ok := user.(*models.AdminUser); ok && admin != nil {
+			requestedBy = admin.Username
+		}
+	}
+
+	batch, err := h.valuationService.StartBulkValuation(request.Properties, requestedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Failed to start bulk valuation",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success":  true,
+		"message":  "Bulk valuation request initiated",
+		"batch_id": batch.ID,
+		"data":     batch,
+	})
+}
+
+// GetValuationBulkStatus returns batch progress and the results completed so far
+func (h *PropertyValuationHandlers) GetValuationBulkStatus(c *gin.Context) {
+	batch, results, err := h.valuationService.GetBulkValuation(c.Param("batch_id"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": "Valuation batch not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to retrieve valuation batch",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	progress := 0.0
+	if batch.Total > 0 {
+		progress = float64(batch.Completed+batch.Failed) / float64(batch.Total) * 100
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"batch":    batch,
+			"progress": progress,
+			"results":  results,
+		},
+	})
+}
+
+// GetValuationStats reports real totals across bulk valuations
+func (h *PropertyValuationHandlers) GetValuationStats(c *gin.Context) {
+	stats, err := h.valuationService.GetValuationStats()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to retrieve valuation stats",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"stats":   stats,
+	})
+}
+
+// GetScraperHealth reports the scraper API circuit breaker state.
+// Returns 503 while the breaker is open so monitors can alert on it.
+func (h *PropertyValuationHandlers) GetScraperHealth(c *gin.Context) {
+	health, ok := h.valuationService.ScraperHealth()
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"message": "Scraper service not configured",
+		})
+		return
+	}
+
+	status := http.StatusOK
+	if health.CircuitBreaker.State == scraper.CircuitOpen {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, gin.H{
+		"success": status == http.StatusOK,
+		"data":    health,
+	})
+}
+
 // GetBulkValuations handles bulk property valuations
 func (h *PropertyValuationHandlers) GetBulkValuations(c *gin.Context) {
 	var requests []services.PropertyValuationRequest
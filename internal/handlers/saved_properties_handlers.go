@@ -135,10 +135,10 @@ func (h *SavedPropertiesHandler) GetSavedProperties(c *gin.Context) {
 		return
 	}
 	
-	c.JSON(http.StatusOK, gin.H{
+	respondCachedJSON(c, gin.H{
 		"saved_properties": savedProperties,
 		"count": len(savedProperties),
-	})
+	}, time.Time{}, savedPropertiesCacheControl)
 }
 
 func (h *SavedPropertiesHandler) CheckIfSaved(c *gin.Context) {
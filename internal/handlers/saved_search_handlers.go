@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+)
+
+// SavedSearchHandlers manages saved searches that alert their owners about
+// new and re-priced listings
+type SavedSearchHandlers struct {
+	db *gorm.DB
+}
+
+// NewSavedSearchHandlers creates new saved search handlers
+func NewSavedSearchHandlers(db *gorm.DB) *SavedSearchHandlers {
+	return &SavedSearchHandlers{db: db}
+}
+
+// CreateSavedSearch saves a search for an owner
+// POST /api/v1/saved-searches
+func (h *SavedSearchHandlers) CreateSavedSearch(c *gin.Context) {
+	var request struct {
+		Name       string                     `json:"name" binding:"required,max=200"`
+		OwnerEmail string                     `json:"owner_email" binding:"omitempty,email"`
+		OwnerPhone string                     `json:"owner_phone"`
+		LeadID     *uint                      `json:"lead_id"`
+		Channel    string                     `json:"channel"`
+		Criteria   models.SavedSearchCriteria `json:"criteria"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	search := models.SavedSearch{
+		Name:       request.Name,
+		OwnerEmail: strings.ToLower(strings.TrimSpace(request.OwnerEmail)),
+		OwnerPhone: strings.TrimSpace(request.OwnerPhone),
+		LeadID:     request.LeadID,
+		Channel:    request.Channel,
+		Criteria:   request.Criteria,
+		Active:     true,
+	}
+	if search.Channel == "" {
+		search.Channel = models.SavedSearchChannelEmail
+	}
+	if search.Criteria.ListingType != "" {
+		search.Criteria.ListingType = services.NormalizeListingType(search.Criteria.ListingType)
+		if search.Criteria.ListingType == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search", "details": "listing_type must be rental or sale"})
+			return
+		}
+	}
+	if err := search.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search", "details": err.Error()})
+		return
+	}
+	if admin, ok := c.Get("user"); ok {
+		if user, ok := admin.(*models.AdminUser); ok {
+			search.CreatedBy = user.Username
+		}
+	}
+
+	if err := h.db.Create(&search).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save search", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "saved_search": search})
+}
+
+// ListSavedSearches returns active saved searches, optionally for one owner email or lead
+// GET /api/v1/saved-searches?email=&lead_id=
+func (h *SavedSearchHandlers) ListSavedSearches(c *gin.Context) {
+	query := h.db.Where("active = ?", true)
+	if email := c.Query("email"); email != "" {
+		query = query.Where("owner_email = ?", strings.ToLower(strings.TrimSpace(email)))
+	}
+	if leadID := c.Query("lead_id"); leadID != "" {
+		id, err := strconv.ParseUint(leadID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lead_id"})
+			return
+		}
+		query = query.Where("lead_id = ?", id)
+	}
+
+	searches := []models.SavedSearch{}
+	if err := query.Order("created_at DESC").Find(&searches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load saved searches", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "saved_searches": searches, "count": len(searches)})
+}
+
+// DeleteSavedSearch deactivates a saved search so it no longer alerts
+// DELETE /api/v1/saved-searches/:id
+func (h *SavedSearchHandlers) DeleteSavedSearch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search ID"})
+		return
+	}
+
+	result := h.db.Model(&models.SavedSearch{}).Where("id = ? AND active = ?", id, true).Update("active", false)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete saved search", "details": result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Saved search deleted"})
+}
@@ -228,7 +228,7 @@ func RegisterSecurityMiddlewareRoutes(mux *http.ServeMux, db *gorm.DB, authManag
 func (h *SecurityMiddlewareHandlers) handleGetSecurityEvents(w http.ResponseWriter, r *http.Request) {
 	var events []SecurityEvent
 	
-	query := h.db.Order("timestamp DESC")
+	query := h.db.Order("created_at DESC")
 	
 	// Filter by severity if provided
 	if severity := r.URL.Query().Get("severity"); severity != "" {
@@ -264,8 +264,8 @@ func (h *SecurityMiddlewareHandlers) handleGetSecurityEvents(w http.ResponseWrit
 // handleResolveSecurityEvent handles POST /security/events/resolve
 func (h *SecurityMiddlewareHandlers) handleResolveSecurityEvent(w http.ResponseWriter, r *http.Request) {
 	var request struct {
-		EventID    uint `json:"event_id" binding:"required"`
-		ResolvedBy uint `json:"resolved_by" binding:"required"`
+		EventID    uint   `json:"event_id" binding:"required"`
+		ResolvedBy string `json:"resolved_by" binding:"required"`
 	}
 	
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
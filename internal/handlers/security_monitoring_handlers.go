@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"chrisgross-ctrl-project/internal/models"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -22,92 +25,81 @@ func NewSecurityMonitoringHandlers(db *gorm.DB) *SecurityMonitoringHandlers {
 
 // SecurityEventData represents incoming security event data
 type SecurityEventData struct {
-	Type        string `json:"type"`
-	Severity    string `json:"severity"`
-	UserID      *uint  `json:"user_id,omitempty"`
-	IPAddress   string `json:"ip_address"`
-	UserAgent   string `json:"user_agent"`
-	Endpoint    string `json:"endpoint"`
-	Method      string `json:"method"`
-	StatusCode  int    `json:"status_code"`
-	Metadata    string `json:"metadata"` // Raw JSON string
-	Description string `json:"description"`
+	Type        string  `json:"type"`
+	Severity    string  `json:"severity"`
+	UserID      *string `json:"user_id,omitempty"`
+	IPAddress   string  `json:"ip_address"`
+	UserAgent   string  `json:"user_agent"`
+	Endpoint    string  `json:"endpoint"`
+	Method      string  `json:"method"`
+	StatusCode  int     `json:"status_code"`
+	Metadata    string  `json:"metadata"` // Raw JSON string
+	Description string  `json:"description"`
 }
 
-// GetSecurityEvents handles GET /api/v1/security/events
+// GetSecurityEvents handles GET /api/security/events, the threat feed,
+// newest first. Filters: ?severity=, ?type=, ?resolved=, ?ip=, ?endpoint=
+// (path prefix), ?since= and ?until= (RFC3339), ?limit= and ?offset=.
 func (h *SecurityMonitoringHandlers) GetSecurityEvents(c *gin.Context) {
-	var events []SecurityEvent
+	query := h.db.Model(&SecurityEvent{})
 
-	query := h.db.Order("timestamp DESC")
-
-	// Apply filters
 	if severity := c.Query("severity"); severity != "" {
-		query = query.Where("severity = ?", severity)
+		query = query.Where("severity = ?", strings.ToUpper(severity))
 	}
-
 	if eventType := c.Query("type"); eventType != "" {
-		query = query.Where("type = ?", eventType)
+		query = query.Where("event_type = ?", eventType)
 	}
-
 	if resolved := c.Query("resolved"); resolved != "" {
-		if resolvedBool, err := strconv.ParseBool(resolved); err == nil {
-			query = query.Where("resolved = ?", resolvedBool)
+		resolvedBool, err := strconv.ParseBool(resolved)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resolved filter", "details": "resolved must be true or false"})
+			return
 		}
+		query = query.Where("resolved = ?", resolvedBool)
 	}
-
-	// Date range filtering
-	if since := c.Query("since"); since != "" {
-		if sinceTime, err := time.Parse(time.RFC3339, since); err == nil {
-			query = query.Where("timestamp >= ?", sinceTime)
+	if ip := c.Query("ip"); ip != "" {
+		query = query.Where("ip_address = ?", ip)
+	}
+	if endpoint := c.Query("endpoint"); endpoint != "" {
+		query = query.Where("endpoint LIKE ?", strings.NewReplacer("%", `\%`, "_", `\_`).Replace(endpoint)+"%")
+	}
+	for param, clause := range map[string]string{"since": "created_at >= ?", "until": "created_at <= ?"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " time", "details": "use RFC3339, e.g. 2026-01-02T15:04:05Z"})
+			return
 		}
+		query = query.Where(clause, parsed)
 	}
 
-	// Pagination
 	limit := 50
 	offset := 0
-
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 1000 {
 			limit = parsedLimit
 		}
 	}
-
 	if offsetStr := c.Query("offset"); offsetStr != "" {
 		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
 			offset = parsedOffset
 		}
 	}
 
-	query = query.Limit(limit).Offset(offset)
-
-	result := query.Find(&events)
-
-	// Handle empty results gracefully
-	if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch security events"})
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch security events", "details": err.Error()})
 		return
 	}
-
-	// Return empty array if no events
-	if len(events) == 0 {
-		c.JSON(http.StatusOK, gin.H{
-			"events": []SecurityEvent{},
-			"total":  0,
-		})
+	events := []SecurityEvent{}
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch security events", "details": err.Error()})
 		return
 	}
 
-	// Get total count for pagination
-	var totalCount int64
-	countQuery := h.db.Model(&SecurityEvent{})
-	if severity := c.Query("severity"); severity != "" {
-		countQuery = countQuery.Where("severity = ?", severity)
-	}
-	if eventType := c.Query("type"); eventType != "" {
-		countQuery = countQuery.Where("type = ?", eventType)
-	}
-	countQuery.Count(&totalCount)
-
 	c.JSON(http.StatusOK, gin.H{
 		"events":   events,
 		"total":    totalCount,
@@ -172,42 +164,70 @@ func (h *SecurityMonitoringHandlers) CreateSecurityEvent(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"event": event})
 }
 
-// ResolveSecurityEvent handles POST /api/v1/security/events/:id/resolve
-func (h *SecurityMonitoringHandlers) ResolveSecurityEvent(c *gin.Context) {
-	eventID := c.Param("id")
-	if eventID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Event ID is required"})
+// PostSecurityEventResolve handles POST /api/security/events/:id/resolve,
+// marking the event resolved by the signed-in admin with a note on what was
+// done about it
+func (h *SecurityMonitoringHandlers) PostSecurityEventResolve(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || eventID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
 		return
 	}
 
 	var request struct {
-		ResolvedBy uint   `json:"resolved_by" binding:"required"`
-		Notes      string `json:"notes"`
+		Note string `json:"note" binding:"required"`
 	}
-
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+	note := strings.TrimSpace(request.Note)
+	if note == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A resolution note is required"})
+		return
+	}
+
+	var event SecurityEvent
+	if err := h.db.First(&event, eventID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Security event not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch security event", "details": err.Error()})
+		return
+	}
+	if event.Resolved {
+		c.JSON(http.StatusConflict, gin.H{"error": "Security event already resolved", "event": event})
 		return
 	}
 
 	now := time.Now()
-	result := h.db.Model(&SecurityEvent{}).Where("id = ? AND resolved = false", eventID).Updates(map[string]interface{}{
-		"resolved":    true,
-		"resolved_by": request.ResolvedBy,
-		"resolved_at": &now,
+	result := h.db.Model(&SecurityEvent{}).Where("id = ? AND resolved = ?", event.ID, false).Updates(map[string]interface{}{
+		"resolved":        true,
+		"resolved_by":     admin.ID,
+		"resolved_at":     now,
+		"resolution_note": note,
 	})
-
 	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve security event"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve security event", "details": result.Error.Error()})
 		return
 	}
-
 	if result.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Security event not found or already resolved"})
+		c.JSON(http.StatusConflict, gin.H{"error": "Security event already resolved"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Security event resolved"})
+	event.Resolved = true
+	event.ResolvedBy = &admin.ID
+	event.ResolvedAt = &now
+	event.ResolutionNote = note
+	c.JSON(http.StatusOK, gin.H{"success": true, "event": event})
 }
 
 // GetSecurityMetrics handles GET /api/v1/security/metrics
@@ -230,9 +250,9 @@ func (h *SecurityMonitoringHandlers) GetSecurityMetrics(c *gin.Context) {
 
 	filter := c.Query("filter")
 
-	baseQuery := h.db.Model(&SecurityEvent{}).Where("timestamp >= ?", since)
+	baseQuery := h.db.Model(&SecurityEvent{}).Where("created_at >= ?", since)
 	if filter == "threat_analysis" {
-		baseQuery = baseQuery.Where("type = ?", "THREAT_ANALYSIS")
+		baseQuery = baseQuery.Where("event_type = ?", "THREAT_ANALYSIS")
 	}
 
 	var severityStats []struct {
@@ -251,9 +271,9 @@ func (h *SecurityMonitoringHandlers) GetSecurityMetrics(c *gin.Context) {
 	}
 
 	h.db.Model(&SecurityEvent{}).
-		Select("type, COUNT(*) as count").
-		Where("timestamp >= ?", since).
-		Group("type").
+		Select("event_type AS type, COUNT(*) as count").
+		Where("created_at >= ?", since).
+		Group("event_type").
 		Order("count DESC").
 		Limit(10).
 		Scan(&typeStats)
@@ -264,8 +284,8 @@ func (h *SecurityMonitoringHandlers) GetSecurityMetrics(c *gin.Context) {
 		Unresolved int64 `json:"unresolved"`
 	}
 
-	h.db.Model(&SecurityEvent{}).Where("timestamp >= ?", since).Count(&resolutionStats.Total)
-	h.db.Model(&SecurityEvent{}).Where("timestamp >= ? AND resolved = true", since).Count(&resolutionStats.Resolved)
+	h.db.Model(&SecurityEvent{}).Where("created_at >= ?", since).Count(&resolutionStats.Total)
+	h.db.Model(&SecurityEvent{}).Where("created_at >= ? AND resolved = true", since).Count(&resolutionStats.Resolved)
 	resolutionStats.Unresolved = resolutionStats.Total - resolutionStats.Resolved
 
 	var ipStats []struct {
@@ -275,7 +295,7 @@ func (h *SecurityMonitoringHandlers) GetSecurityMetrics(c *gin.Context) {
 
 	h.db.Model(&SecurityEvent{}).
 		Select("ip_address, COUNT(*) as count").
-		Where("timestamp >= ? AND ip_address != ''", since).
+		Where("created_at >= ? AND ip_address != ''", since).
 		Group("ip_address").
 		Order("count DESC").
 		Limit(10).
@@ -283,7 +303,7 @@ func (h *SecurityMonitoringHandlers) GetSecurityMetrics(c *gin.Context) {
 
 	var blockedCount int64
 	h.db.Model(&SecurityEvent{}).
-		Where("type = ? AND timestamp >= ? AND metadata->>'action_taken' = ?", "THREAT_ANALYSIS", since, "BLOCK_REQUEST").
+		Where("event_type = ? AND created_at >= ? AND event_data->>'action_taken' = ?", "THREAT_ANALYSIS", since, "BLOCK_REQUEST").
 		Count(&blockedCount)
 
 	var threatStats []struct {
@@ -293,7 +313,7 @@ func (h *SecurityMonitoringHandlers) GetSecurityMetrics(c *gin.Context) {
 
 	h.db.Model(&SecurityEvent{}).
 		Select("severity as risk_level, COUNT(*) as count").
-		Where("type = ? AND timestamp >= ?", "THREAT_ANALYSIS", since).
+		Where("event_type = ? AND created_at >= ?", "THREAT_ANALYSIS", since).
 		Group("severity").
 		Scan(&threatStats)
 
@@ -304,7 +324,7 @@ func (h *SecurityMonitoringHandlers) GetSecurityMetrics(c *gin.Context) {
 
 	h.db.Model(&SecurityEvent{}).
 		Select("ip_address, COUNT(*) as count").
-		Where("type = ? AND timestamp >= ?", "THREAT_ANALYSIS", since).
+		Where("event_type = ? AND created_at >= ?", "THREAT_ANALYSIS", since).
 		Group("ip_address").
 		Order("count DESC").
 		Limit(10).
@@ -353,7 +373,7 @@ func (h *SecurityMonitoringHandlers) GetSecurityEventDetails(c *gin.Context) {
 // CreateSecuritySession handles POST /api/v1/security/sessions
 func (h *SecurityMonitoringHandlers) CreateSecuritySession(c *gin.Context) {
 	var request struct {
-		UserID      uint   `json:"user_id" binding:"required"`
+		UserID      string `json:"user_id" binding:"required"`
 		IPAddress   string `json:"ip_address" binding:"required"`
 		UserAgent   string `json:"user_agent"`
 		SessionType string `json:"session_type"`
@@ -401,6 +421,76 @@ func (h *SecurityMonitoringHandlers) CreateSecuritySession(c *gin.Context) {
 	})
 }
 
+// GetSecurityAuditLogs handles GET /api/security/audit-logs, listing admin
+// writes newest first. Filters: ?actor= (ID or username), ?entity=,
+// ?entity_id=, ?method=, ?start= and ?end= (YYYY-MM-DD, inclusive).
+func (h *SecurityMonitoringHandlers) GetSecurityAuditLogs(c *gin.Context) {
+	value, _ := c.Get("user")
+	admin, ok := value.(*models.AdminUser)
+	if !ok || admin == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	if !models.IsTeamAdminRole(admin.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Audit logs require a team admin"})
+		return
+	}
+
+	query := h.db.Model(&models.AuditLog{})
+	if actor := c.Query("actor"); actor != "" {
+		query = query.Where("actor_id = ? OR actor_name = ?", actor, actor)
+	}
+	if entity := c.Query("entity"); entity != "" {
+		query = query.Where("entity_type = ?", entity)
+	}
+	if entityID := c.Query("entity_id"); entityID != "" {
+		query = query.Where("entity_id = ?", entityID)
+	}
+	if method := c.Query("method"); method != "" {
+		query = query.Where("method = ?", strings.ToUpper(method))
+	}
+	if start := c.Query("start"); start != "" {
+		startDate, err := time.Parse("2006-01-02", start)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date", "details": "use YYYY-MM-DD"})
+			return
+		}
+		query = query.Where("created_at >= ?", startDate)
+	}
+	if end := c.Query("end"); end != "" {
+		endDate, err := time.Parse("2006-01-02", end)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date", "details": "use YYYY-MM-DD"})
+			return
+		}
+		query = query.Where("created_at < ?", endDate.AddDate(0, 0, 1))
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit", "details": "limit must be between 1 and 500"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count audit logs", "details": err.Error()})
+		return
+	}
+	logs := []models.AuditLog{}
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit logs", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"logs": logs, "total": total, "limit": limit, "offset": offset})
+}
+
 // generateSessionToken generates a simple session token
 func generateSessionToken() string {
 	// Simplified token generation - in production, use crypto/rand
@@ -417,7 +507,7 @@ func RegisterSecurityMonitoringRoutes(r *gin.Engine, db *gorm.DB) {
 		securityGroup.GET("/events", handlers.GetSecurityEvents)
 		securityGroup.POST("/events", handlers.CreateSecurityEvent)
 		securityGroup.GET("/events/:id", handlers.GetSecurityEventDetails)
-		securityGroup.POST("/events/:id/resolve", handlers.ResolveSecurityEvent)
+		securityGroup.POST("/events/:id/resolve", handlers.PostSecurityEventResolve)
 
 		// Security metrics and monitoring
 		securityGroup.GET("/metrics", handlers.GetSecurityMetrics)
@@ -67,6 +67,23 @@ func (h *TieredStatsHandlers) GetDailyStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// InvalidateDashboardCache forces dashboard stats to recompute after bulk changes
+// POST /api/v1/dashboard/cache/invalidate
+func (h *TieredStatsHandlers) InvalidateDashboardCache(c *gin.Context) {
+	if err := h.dashboardStatsService.InvalidateCache(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to invalidate dashboard cache",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"message":        "Dashboard cache invalidated",
+		"invalidated_at": time.Now(),
+	})
+}
+
 // Helper: Get top AI opportunities
 func (h *TieredStatsHandlers) getTopOpportunities() []map[string]interface{} {
 	// This should call the AI orchestrator service
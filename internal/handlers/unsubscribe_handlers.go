@@ -8,11 +8,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"chrisgross-ctrl-project/internal/services"
 )
 
 // UnsubscribeHandlers handles CAN-SPAM compliant unsubscribe functionality
 type UnsubscribeHandlers struct {
-	db *gorm.DB
+	db                *gorm.DB
+	tracker           *services.CampaignTracker
+	encryptionManager *security.EncryptionManager
 }
 
 // UnsubscribeRecord tracks email unsubscriptions for CAN-SPAM compliance
@@ -41,6 +45,16 @@ func NewUnsubscribeHandlers(db *gorm.DB) *UnsubscribeHandlers {
 	}
 }
 
+// SetCampaignTracker sets the tracker used to verify signed unsubscribe tokens
+func (u *UnsubscribeHandlers) SetCampaignTracker(tracker *services.CampaignTracker) {
+	u.tracker = tracker
+}
+
+// SetEncryptionManager sets the manager used to decrypt lead emails for the unsubscribe list
+func (u *UnsubscribeHandlers) SetEncryptionManager(encryptionManager *security.EncryptionManager) {
+	u.encryptionManager = encryptionManager
+}
+
 // HandleUnsubscribe processes unsubscribe requests with proper CAN-SPAM compliance
 func (u *UnsubscribeHandlers) HandleUnsubscribe(c *gin.Context) {
 	// Get parameters
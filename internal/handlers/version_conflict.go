@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	versionRequiredMessage = "version is required; send the version you last read"
+	versionConflictMessage = "Record was modified by someone else; merge with the current version and retry"
+)
+
+// respondVersionRequired rejects an update that did not say which version it was based on
+func respondVersionRequired(c *gin.Context) {
+	c.JSON(http.StatusPreconditionRequired, gin.H{
+		"success": false,
+		"error":   versionRequiredMessage,
+	})
+}
+
+// respondVersionConflict returns the current record so the client can merge its changes
+func respondVersionConflict(c *gin.Context, current interface{}) {
+	c.JSON(http.StatusConflict, gin.H{
+		"success": false,
+		"error":   versionConflictMessage,
+		"current": current,
+	})
+}
+
+// writeVersionRequired is respondVersionRequired for net/http handlers
+func writeVersionRequired(w http.ResponseWriter) {
+	writeJSONStatus(w, http.StatusPreconditionRequired, map[string]interface{}{
+		"success": false,
+		"error":   versionRequiredMessage,
+	})
+}
+
+// writeVersionConflict is respondVersionConflict for net/http handlers
+func writeVersionConflict(w http.ResponseWriter, current interface{}) {
+	writeJSONStatus(w, http.StatusConflict, map[string]interface{}{
+		"success": false,
+		"error":   versionConflictMessage,
+		"current": current,
+	})
+}
+
+func writeJSONStatus(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
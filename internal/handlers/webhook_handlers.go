@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"crypto/hmac"
+	"crypto/sha1"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -10,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -153,9 +156,9 @@ func (w *WebhookHandlers) ProcessFUBWebhook(c *gin.Context) {
 		return
 	}
 
-	// Verify FUB signature if configured
+	// Verify FUB signature
 	signature := c.GetHeader("X-FUB-Signature")
-	if w.fubAPIKey != "" && !w.verifyFUBSignature(body, signature) {
+	if !w.verifyFUBSignature(body, signature) {
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid FUB signature", nil)
 		return
 	}
@@ -368,34 +371,53 @@ func (w *WebhookHandlers) processInboundEmail(eventID uint, emailData InboundEma
 
 // Verification functions
 
+// verifyTwilioSignature checks X-Twilio-Signature: the base64 HMAC-SHA1 of
+// the full request URL followed by each POST parameter's name and value,
+// sorted by name
 func (w *WebhookHandlers) verifyTwilioSignature(req *http.Request, signature string) bool {
 	if w.twilioToken == "" || signature == "" {
-		return false // Skip verification in development if token not set
+		return false
 	}
 
-	// Build the signature base string
-	url := req.URL.String()
-	if req.Method == "POST" {
-		req.ParseForm()
-		params := req.PostForm
-		for key, values := range params {
-			for _, value := range values {
-				url += key + value
+	data := twilioRequestURL(req)
+	if req.Method == http.MethodPost {
+		if err := req.ParseForm(); err != nil {
+			return false
+		}
+		keys := make([]string, 0, len(req.PostForm))
+		for key := range req.PostForm {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			for _, value := range req.PostForm[key] {
+				data += key + value
 			}
 		}
 	}
 
-	// Calculate expected signature
-	mac := hmac.New(sha256.New, []byte(w.twilioToken))
-	mac.Write([]byte(url))
-	expected := hex.EncodeToString(mac.Sum(nil))
+	mac := hmac.New(sha1.New, []byte(w.twilioToken))
+	mac.Write([]byte(data))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
 
 	return hmac.Equal([]byte(signature), []byte(expected))
 }
 
+// twilioRequestURL rebuilds the public URL Twilio posted to, which behind a
+// TLS-terminating proxy differs from the scheme the server sees
+func twilioRequestURL(req *http.Request) string {
+	scheme := "http"
+	if req.TLS != nil || req.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + req.Host + req.URL.RequestURI()
+}
+
+// verifyFUBSignature fails closed: without an API key or a signature the
+// webhook can't be authenticated
 func (w *WebhookHandlers) verifyFUBSignature(payload []byte, signature string) bool {
 	if w.fubAPIKey == "" || signature == "" {
-		return true // Skip verification in development
+		return false
 	}
 
 	mac := hmac.New(sha256.New, []byte(w.fubAPIKey))
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestVerifyTwilioSignature_MatchesTwilioScheme(t *testing.T) {
+	// Example request and signature from Twilio's webhook security docs
+	form := url.Values{
+		"CallSid": {"CA1234567890ABCDE"},
+		"Caller":  {"+12349013030"},
+		"Digits":  {"1234"},
+		"From":    {"+12349013030"},
+		"To":      {"+18005551212"},
+	}
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "https://mycompany.com/myapp.php?foo=1&bar=2", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	w := &WebhookHandlers{twilioToken: "12345"}
+	if !w.verifyTwilioSignature(newRequest(), "0/KCTR6DLpKmkAf8muzZqo1nDgQ=") {
+		t.Error("Expected Twilio's signature to verify")
+	}
+	if w.verifyTwilioSignature(newRequest(), "") {
+		t.Error("Expected a missing signature to be rejected")
+	}
+
+	// The public URL is https when a proxy terminates TLS
+	proxied := newRequest()
+	proxied.TLS = nil
+	proxied.Header.Set("X-Forwarded-Proto", "https")
+	if !w.verifyTwilioSignature(proxied, "0/KCTR6DLpKmkAf8muzZqo1nDgQ=") {
+		t.Error("Expected the signature to verify against the forwarded scheme")
+	}
+
+	tampered := newRequest()
+	tampered.Body = http.NoBody
+	tampered.Form, tampered.PostForm = nil, nil
+	if w.verifyTwilioSignature(tampered, "0/KCTR6DLpKmkAf8muzZqo1nDgQ=") {
+		t.Error("Expected a request with different parameters to be rejected")
+	}
+}
+
+func TestVerifyFUBSignature_FailsClosed(t *testing.T) {
+	payload := []byte(`{"event":"peopleCreated"}`)
+	mac := hmac.New(sha256.New, []byte("fub-key"))
+	mac.Write(payload)
+	valid := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	w := &WebhookHandlers{fubAPIKey: "fub-key"}
+	if !w.verifyFUBSignature(payload, valid) {
+		t.Error("Expected a valid signature to verify")
+	}
+	if w.verifyFUBSignature(payload, "") {
+		t.Error("Expected a missing signature to be rejected")
+	}
+	if w.verifyFUBSignature([]byte(`{"event":"peopleDeleted"}`), valid) {
+		t.Error("Expected a signature over a different payload to be rejected")
+	}
+	if (&WebhookHandlers{}).verifyFUBSignature(payload, valid) {
+		t.Error("Expected verification to fail without a configured API key")
+	}
+}
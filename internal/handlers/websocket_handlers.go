@@ -3,10 +3,12 @@ package handlers
 import (
 	"encoding/json"
 	"log"
-	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
+	"chrisgross-ctrl-project/internal/middleware"
+	"chrisgross-ctrl-project/internal/models"
 	"chrisgross-ctrl-project/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -14,22 +16,39 @@ import (
 )
 
 var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
+	CheckOrigin:     middleware.CheckWebSocketOrigin,
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
+	Subprotocols:    []string{middleware.WebSocketSubprotocol},
 }
 
+// Keepalive timing shared by the admin WebSockets. A connection that has not
+// answered a ping (or sent anything) within wsPongWait is dropped.
+const (
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = wsPongWait * 9 / 10
+)
+
 type WebSocketMessage struct {
-	Type string                 `json:"type"`
-	Data map[string]interface{} `json:"data"`
+	Type   string                 `json:"type"`
+	Cursor uint64                 `json:"cursor,omitempty"` // Set on activity events; see activity_replay.go
+	Data   map[string]interface{} `json:"data"`
 }
 
 type WebSocketClient struct {
-	conn *websocket.Conn
-	send chan WebSocketMessage
-	hub  *WebSocketHub
+	conn       *websocket.Conn
+	send       chan WebSocketMessage
+	unregister chan *WebSocketClient
+	role       string // Role of the authenticated admin; see scopeForRole
+}
+
+// activitySubscription registers an activity feed client, resuming after
+// lastCursor when resume is set
+type activitySubscription struct {
+	client     *WebSocketClient
+	lastCursor uint64
+	resume     bool
 }
 
 type WebSocketHub struct {
@@ -56,10 +75,14 @@ type ActivityEvent struct {
 type ActivityHub struct {
 	clients    map[*WebSocketClient]bool
 	broadcast  chan ActivityEvent
-	register   chan *WebSocketClient
+	register   chan activitySubscription
 	unregister chan *WebSocketClient
 	mu         sync.RWMutex
 	db         *gorm.DB
+
+	// Only touched by run
+	cursor uint64
+	replay *activityReplayBuffer
 }
 
 func NewWebSocketHub(statsService *services.DashboardStatsService) *WebSocketHub {
@@ -151,13 +174,13 @@ func (h *WebSocketHub) Broadcast(message WebSocketMessage) {
 
 func (c *WebSocketClient) readPump() {
 	defer func() {
-		c.hub.unregister <- c
+		c.unregister <- c
 		c.conn.Close()
 	}()
 	
-	c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+	c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
 	c.conn.SetPongHandler(func(string) error {
-		c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+		c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
 		return nil
 	})
 	
@@ -173,7 +196,7 @@ func (c *WebSocketClient) readPump() {
 }
 
 func (c *WebSocketClient) writePump() {
-	ticker := time.NewTicker(54 * time.Second)
+	ticker := time.NewTicker(wsPingPeriod)
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
@@ -182,7 +205,7 @@ func (c *WebSocketClient) writePump() {
 	for {
 		select {
 		case message, ok := <-c.send:
-			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
 			if !ok {
 				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
@@ -193,7 +216,7 @@ func (c *WebSocketClient) writePump() {
 				return
 			}
 			
-			data, err := json.Marshal(message)
+			data, err := json.Marshal(scopeForRole(message, c.role))
 			if err != nil {
 				log.Printf("Error marshaling WebSocket message: %v", err)
 				return
@@ -205,7 +228,7 @@ func (c *WebSocketClient) writePump() {
 			}
 			
 		case <-ticker.C:
-			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
@@ -213,6 +236,27 @@ func (c *WebSocketClient) writePump() {
 	}
 }
 
+// scopeForRole removes visitor contact details (email, user ID and raw event
+// data) from activity events sent to roles below admin
+func scopeForRole(message WebSocketMessage, role string) WebSocketMessage {
+	if message.Type != "activity_event" || canSeeVisitorDetails(role) {
+		return message
+	}
+	event, ok := message.Data["event"].(ActivityEvent)
+	if !ok {
+		return message
+	}
+	event.UserID = 0
+	event.UserEmail = ""
+	event.EventData = nil
+	message.Data = map[string]interface{}{"event": event}
+	return message
+}
+
+func canSeeVisitorDetails(role string) bool {
+	return role == models.RoleAdmin || models.IsTeamAdminRole(role)
+}
+
 type WebSocketHandler struct {
 	hub         *WebSocketHub
 	activityHub *ActivityHub
@@ -235,12 +279,13 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	}
 	
 	client := &WebSocketClient{
-		conn: conn,
-		send: make(chan WebSocketMessage, 256),
-		hub:  h.hub,
+		conn:       conn,
+		send:       make(chan WebSocketMessage, 256),
+		unregister: h.hub.unregister,
+		role:       c.GetString("user_role"),
 	}
 	
-	client.hub.register <- client
+	h.hub.register <- client
 	
 	go client.writePump()
 	go client.readPump()
@@ -254,9 +299,10 @@ func NewActivityHub(db *gorm.DB) *ActivityHub {
 	hub := &ActivityHub{
 		clients:    make(map[*WebSocketClient]bool),
 		broadcast:  make(chan ActivityEvent, 256),
-		register:   make(chan *WebSocketClient),
+		register:   make(chan activitySubscription),
 		unregister: make(chan *WebSocketClient),
 		db:         db,
+		replay:     newActivityReplayBuffer(activityReplayBufferSize),
 	}
 	go hub.run()
 	return hub
@@ -265,11 +311,12 @@ func NewActivityHub(db *gorm.DB) *ActivityHub {
 func (h *ActivityHub) run() {
 	for {
 		select {
-		case client := <-h.register:
+		case sub := <-h.register:
 			h.mu.Lock()
-			h.clients[client] = true
+			h.clients[sub.client] = true
 			h.mu.Unlock()
 			log.Printf("Activity client registered. Total clients: %d", len(h.clients))
+			h.sendBacklog(sub)
 
 		case client := <-h.unregister:
 			h.mu.Lock()
@@ -281,13 +328,17 @@ func (h *ActivityHub) run() {
 			log.Printf("Activity client unregistered. Total clients: %d", len(h.clients))
 
 		case event := <-h.broadcast:
-			h.mu.RLock()
+			h.cursor++
 			message := WebSocketMessage{
-				Type: "activity_event",
+				Type:   "activity_event",
+				Cursor: h.cursor,
 				Data: map[string]interface{}{
 					"event": event,
 				},
 			}
+			h.replay.add(activityRecord{cursor: h.cursor, message: message})
+
+			h.mu.Lock()
 			for client := range h.clients {
 				select {
 				case client.send <- message:
@@ -296,7 +347,45 @@ func (h *ActivityHub) run() {
 					delete(h.clients, client)
 				}
 			}
-			h.mu.RUnlock()
+			h.mu.Unlock()
+		}
+	}
+}
+
+// sendBacklog sends a newly registered client the events it missed since its
+// last cursor, then the current cursor so it can resume from there later.
+// It runs on the hub goroutine, so no live event can overtake the replay.
+func (h *ActivityHub) sendBacklog(sub activitySubscription) {
+	var backlog []WebSocketMessage
+	if sub.resume {
+		missed, complete := h.replay.since(sub.lastCursor, h.cursor)
+		if !complete {
+			backlog = append(backlog, WebSocketMessage{
+				Type: "replay_truncated",
+				Data: map[string]interface{}{
+					"last_cursor":    sub.lastCursor,
+					"current_cursor": h.cursor,
+				},
+			})
+		}
+		for _, record := range missed {
+			backlog = append(backlog, record.message)
+		}
+	}
+	backlog = append(backlog, WebSocketMessage{
+		Type: "activity_cursor",
+		Data: map[string]interface{}{
+			"cursor": h.cursor,
+		},
+	})
+
+	for _, message := range backlog {
+		select {
+		case sub.client.send <- message:
+		default:
+			// The replay buffer is smaller than the send buffer, so only a
+			// client that is already stalled gets here
+			return
 		}
 	}
 }
@@ -306,8 +395,8 @@ func (h *ActivityHub) BroadcastEvent(event ActivityEvent) {
 }
 
 func (h *ActivityHub) BroadcastActiveCount(count int) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	
 	message := WebSocketMessage{
 		Type: "active_count",
@@ -335,12 +424,21 @@ func (h *WebSocketHandler) HandleAdminActivityFeed(c *gin.Context) {
 	}
 	
 	client := &WebSocketClient{
-		conn: conn,
-		send: make(chan WebSocketMessage, 256),
-		hub:  h.hub,
+		conn:       conn,
+		send:       make(chan WebSocketMessage, 256),
+		unregister: h.activityHub.unregister,
+		role:       c.GetString("user_role"),
 	}
 	
-	h.activityHub.register <- client
+	// Clients reconnecting after a drop pass the last cursor they saw
+	sub := activitySubscription{client: client}
+	if lastCursor := c.Query("last_cursor"); lastCursor != "" {
+		if cursor, err := strconv.ParseUint(lastCursor, 10, 64); err == nil {
+			sub.lastCursor = cursor
+			sub.resume = true
+		}
+	}
+	h.activityHub.register <- sub
 	
 	go client.writePump()
 	go client.readPump()
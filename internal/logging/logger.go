@@ -0,0 +1,127 @@
+// Package logging provides the structured logger used to correlate log lines
+// with the request that produced them.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Logger is the structured logging interface handlers and services depend on.
+// Arguments after msg are alternating key/value pairs, as with log/slog.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+	With(args ...any) Logger
+}
+
+// RequestIDKey is the log attribute and gin context key carrying the request ID
+const RequestIDKey = "request_id"
+
+type contextKey int
+
+const (
+	loggerContextKey contextKey = iota
+	requestIDContextKey
+)
+
+var (
+	defaultLogger Logger = New(os.Stdout, "info", "text")
+	defaultMutex  sync.RWMutex
+)
+
+// slogLogger adapts *slog.Logger to Logger
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// New creates a logger writing to w at the given level ("debug", "info",
+// "warn", "error"). format "json" emits JSON lines; anything else emits
+// key=value text.
+func New(w io.Writer, level, format string) Logger {
+	options := &slog.HandlerOptions{Level: parseLevel(level)}
+	var handler slog.Handler
+	if strings.EqualFold(format, "json") {
+		handler = slog.NewJSONHandler(w, options)
+	} else {
+		handler = slog.NewTextHandler(w, options)
+	}
+	return &slogLogger{logger: slog.New(handler)}
+}
+
+func (l *slogLogger) Debug(msg string, args ...any) { l.logger.Debug(msg, args...) }
+func (l *slogLogger) Info(msg string, args ...any)  { l.logger.Info(msg, args...) }
+func (l *slogLogger) Warn(msg string, args ...any)  { l.logger.Warn(msg, args...) }
+func (l *slogLogger) Error(msg string, args ...any) { l.logger.Error(msg, args...) }
+
+func (l *slogLogger) With(args ...any) Logger {
+	return &slogLogger{logger: l.logger.With(args...)}
+}
+
+// parseLevel maps a config level name to a slog level, defaulting to info
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Default returns the process-wide logger
+func Default() Logger {
+	defaultMutex.RLock()
+	defer defaultMutex.RUnlock()
+	return defaultLogger
+}
+
+// SetDefault replaces the process-wide logger
+func SetDefault(logger Logger) {
+	if logger == nil {
+		return
+	}
+	defaultMutex.Lock()
+	defaultLogger = logger
+	defaultMutex.Unlock()
+}
+
+// WithRequestID returns a context carrying the request ID and a logger that
+// tags every line with it
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestIDContextKey, requestID)
+	return WithLogger(ctx, FromContext(ctx).With(RequestIDKey, requestID))
+}
+
+// RequestID returns the request ID carried by ctx, or ""
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
+// WithLogger returns a context carrying logger
+func WithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, logger)
+}
+
+// FromContext returns the logger carried by ctx, falling back to the default
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerContextKey).(Logger); ok {
+			return logger
+		}
+	}
+	return Default()
+}
@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestWithRequestID_TagsContextLogger(t *testing.T) {
+	recorder := NewRecorder()
+	ctx := WithRequestID(WithLogger(context.Background(), recorder), "req-123")
+
+	if got := RequestID(ctx); got != "req-123" {
+		t.Fatalf("Expected request ID req-123, got %q", got)
+	}
+
+	FromContext(ctx).With("trigger_id", "trig_1").Info("trigger processed", "priority", "high")
+
+	entries := recorder.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Level != "info" || entry.Message != "trigger processed" {
+		t.Fatalf("Unexpected entry %+v", entry)
+	}
+	for key, want := range map[string]any{RequestIDKey: "req-123", "trigger_id": "trig_1", "priority": "high"} {
+		if entry.Attrs[key] != want {
+			t.Errorf("Expected %s=%v, got %v", key, want, entry.Attrs[key])
+		}
+	}
+}
+
+func TestNew_JSONOutputCarriesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithRequestID(WithLogger(context.Background(), New(&buf, "info", "json")), "req-456")
+
+	FromContext(ctx).Debug("filtered out below info")
+	FromContext(ctx).Warn("slow request", "latency_ms", 1500)
+
+	var line map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &line); err != nil {
+		t.Fatalf("Expected a single JSON line, got %q: %v", buf.String(), err)
+	}
+	if line["msg"] != "slow request" || line["level"] != "WARN" || line[RequestIDKey] != "req-456" {
+		t.Fatalf("Unexpected log line %v", line)
+	}
+}
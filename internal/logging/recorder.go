@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Entry is a log line captured by a Recorder
+type Entry struct {
+	Level   string
+	Message string
+	Attrs   map[string]any
+}
+
+// Recorder is a Logger that keeps every line in memory so tests can assert on them
+type Recorder struct {
+	mutex   *sync.Mutex
+	entries *[]Entry
+	attrs   []any
+}
+
+// NewRecorder creates an empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{mutex: &sync.Mutex{}, entries: &[]Entry{}}
+}
+
+func (r *Recorder) Debug(msg string, args ...any) { r.record("debug", msg, args) }
+func (r *Recorder) Info(msg string, args ...any)  { r.record("info", msg, args) }
+func (r *Recorder) Warn(msg string, args ...any)  { r.record("warn", msg, args) }
+func (r *Recorder) Error(msg string, args ...any) { r.record("error", msg, args) }
+
+// With returns a Recorder sharing this one's entries that adds args to every line
+func (r *Recorder) With(args ...any) Logger {
+	attrs := append(append([]any{}, r.attrs...), args...)
+	return &Recorder{mutex: r.mutex, entries: r.entries, attrs: attrs}
+}
+
+// Entries returns a copy of the captured lines
+func (r *Recorder) Entries() []Entry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Entry{}, (*r.entries)...)
+}
+
+func (r *Recorder) record(level, msg string, args []any) {
+	entry := Entry{Level: level, Message: msg, Attrs: map[string]any{}}
+	all := append(append([]any{}, r.attrs...), args...)
+	for i := 0; i < len(all); i += 2 {
+		if i+1 == len(all) {
+			entry.Attrs["!BADKEY"] = all[i]
+			break
+		}
+		entry.Attrs[fmt.Sprint(all[i])] = all[i+1]
+	}
+
+	r.mutex.Lock()
+	*r.entries = append(*r.entries, entry)
+	r.mutex.Unlock()
+}
@@ -0,0 +1,247 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AuditTrailConfig scopes the audit trail of admin writes
+type AuditTrailConfig struct {
+	// EntityTables maps a route's resource, e.g. "closing-pipeline", to the
+	// table whose row is recorded before and after a write to /<resource>/:id
+	EntityTables map[string]string
+	// RedactFields are field name fragments (any depth, case-insensitive)
+	// whose values are replaced before a body or row is stored
+	RedactFields []string
+	// SkipPaths are path prefixes that are not recorded
+	SkipPaths []string
+	// MaxBodyBytes limits how much of a JSON body is recorded
+	MaxBodyBytes int64
+}
+
+// DefaultAuditTrailConfig snapshots the entities admins most often change
+func DefaultAuditTrailConfig() AuditTrailConfig {
+	return AuditTrailConfig{
+		EntityTables: map[string]string{
+			"properties":       "properties",
+			"bookings":         "bookings",
+			"approvals":        "approvals",
+			"closing-pipeline": "closing_pipelines",
+			"leads":            "leads",
+			"team":             "admin_users",
+			"saved-searches":   "saved_searches",
+		},
+		RedactFields: []string{
+			"password", "token", "secret", "ssn", "social_security", "api_key", "apikey",
+			"authorization", "cookie", "mfa", "card_number", "cvv", "account_number", "routing_number",
+		},
+		SkipPaths:    []string{"/api/webhooks/", "/static/"},
+		MaxBodyBytes: 64 << 10,
+	}
+}
+
+const (
+	auditRedacted  = "[REDACTED]"
+	auditEncrypted = "[ENCRYPTED]"
+)
+
+// Route segments that prefix a resource rather than name one
+var auditRoutePrefixes = map[string]bool{"api": true, "v1": true, "v2": true, "admin": true}
+
+// AuditTrail records every POST, PUT, PATCH and DELETE made by a signed-in
+// admin to the audit_logs table. Request bodies are stored with sensitive
+// fields redacted; rows of the configured entities are stored as they were
+// before and after the write.
+func AuditTrail(db *gorm.DB, config AuditTrailConfig) gin.HandlerFunc {
+	redact := make([]string, len(config.RedactFields))
+	for i, field := range config.RedactFields {
+		redact[i] = strings.ToLower(field)
+	}
+	trail := &auditTrail{db: db, config: config, redact: redact}
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+		route := c.FullPath()
+		if route == "" || hasPathPrefix(c.Request.URL.Path, config.SkipPaths) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		entityType, entityID := auditEntity(c, route)
+		table := config.EntityTables[entityType]
+		var body []byte
+		if strings.HasSuffix(c.ContentType(), "json") && c.Request.Body != nil {
+			body, _ = io.ReadAll(io.LimitReader(c.Request.Body, config.MaxBodyBytes+1))
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+		}
+		var before models.JSONB
+		if table != "" && entityID != "" {
+			before = trail.snapshot(table, entityID)
+		}
+
+		c.Next()
+
+		// Authentication is per route, so the admin is only known afterwards
+		value, _ := c.Get("user")
+		admin, ok := value.(*models.AdminUser)
+		if !ok || admin == nil {
+			return
+		}
+
+		entry := models.AuditLog{
+			ActorID:    admin.ID,
+			ActorName:  admin.Username,
+			ActorRole:  admin.Role,
+			Method:     c.Request.Method,
+			Route:      route,
+			Path:       c.Request.URL.Path,
+			EntityType: entityType,
+			EntityID:   entityID,
+			StatusCode: c.Writer.Status(),
+			Request:    trail.requestBody(c, body),
+			Before:     before,
+			IPAddress:  c.ClientIP(),
+			UserAgent:  c.Request.UserAgent(),
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if table != "" && entityID != "" {
+			entry.After = trail.snapshot(table, entityID)
+		}
+		if err := db.Create(&entry).Error; err != nil {
+			log.Printf("⚠️ Failed to record audit log for %s %s: %v", entry.Method, entry.Path, err)
+		}
+	}
+}
+
+type auditTrail struct {
+	db     *gorm.DB
+	config AuditTrailConfig
+	redact []string
+}
+
+// auditEntity names the resource a route writes to and the ID it was given:
+// the segment before the route's first parameter, or its first resource
+// segment when it has none
+func auditEntity(c *gin.Context, route string) (string, string) {
+	segments := strings.Split(strings.Trim(route, "/"), "/")
+	for len(segments) > 1 && auditRoutePrefixes[segments[0]] {
+		segments = segments[1:]
+	}
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			if i == 0 {
+				break
+			}
+			return segments[i-1], c.Param(segment[1:])
+		}
+	}
+	return segments[0], ""
+}
+
+// snapshot reads the entity's row, redacted, or nil when it does not exist
+func (t *auditTrail) snapshot(table, id string) models.JSONB {
+	row := map[string]interface{}{}
+	if err := t.db.Table(table).Where("id = ?", id).Take(&row).Error; err != nil {
+		return nil
+	}
+	return models.JSONB(t.redactValue(row, false).(map[string]interface{}))
+}
+
+// requestBody returns the redacted JSON or form body of the request
+func (t *auditTrail) requestBody(c *gin.Context, body []byte) models.JSONB {
+	if len(body) > 0 {
+		if int64(len(body)) > t.config.MaxBodyBytes {
+			return models.JSONB{"truncated": true, "bytes": len(body)}
+		}
+		var payload interface{}
+		if json.Unmarshal(body, &payload) != nil {
+			return nil
+		}
+		if fields, ok := t.redactValue(payload, false).(map[string]interface{}); ok {
+			return models.JSONB(fields)
+		}
+		return models.JSONB{"items": t.redactValue(payload, false)}
+	}
+
+	// Form bodies were parsed by the handler or input scanning; files are not recorded
+	if len(c.Request.PostForm) == 0 {
+		return nil
+	}
+	fields := make(map[string]interface{}, len(c.Request.PostForm))
+	for key, values := range c.Request.PostForm {
+		if len(values) == 1 {
+			fields[key] = values[0]
+			continue
+		}
+		list := make([]interface{}, len(values))
+		for i, value := range values {
+			list[i] = value
+		}
+		fields[key] = list
+	}
+	return models.JSONB(t.redactValue(fields, false).(map[string]interface{}))
+}
+
+// redactValue replaces sensitive fields and encrypted values in a decoded
+// JSON value or database row
+func (t *auditTrail) redactValue(value interface{}, sensitive bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			redacted[key] = t.redactValue(child, sensitive || t.isSensitive(key))
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, child := range v {
+			redacted[i] = t.redactValue(child, sensitive)
+		}
+		return redacted
+	case []byte:
+		return t.redactValue(string(v), sensitive)
+	case nil:
+		return nil
+	case string:
+		if v == "" {
+			return v
+		}
+		if sensitive {
+			return auditRedacted
+		}
+		if strings.HasPrefix(v, "{") && strings.Contains(v, `"ciphertext"`) {
+			return auditEncrypted
+		}
+		return v
+	default:
+		if sensitive {
+			return auditRedacted
+		}
+		return v
+	}
+}
+
+func (t *auditTrail) isSensitive(field string) bool {
+	field = strings.ToLower(field)
+	for _, fragment := range t.redact {
+		if strings.Contains(field, fragment) {
+			return true
+		}
+	}
+	return false
+}
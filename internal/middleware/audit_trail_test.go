@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAuditTrail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AuditLog{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	db.Exec(`CREATE TABLE admin_users (id TEXT PRIMARY KEY, username TEXT, email TEXT, password_hash TEXT, role TEXT)`)
+	db.Exec(`INSERT INTO admin_users (id, username, email, password_hash, role) VALUES ('agent-1', 'ann', 'ann@example.com', 'hash', 'agent')`)
+
+	admin := &models.AdminUser{ID: "admin-1", Username: "chris", Role: "admin"}
+	signIn := func(c *gin.Context) {
+		if c.GetHeader("X-Test-User") != "" {
+			c.Set("user", admin)
+		}
+	}
+	r := gin.New()
+	r.Use(AuditTrail(db, DefaultAuditTrailConfig()))
+	r.PUT("/api/admin/team/:id", signIn, func(c *gin.Context) {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		db.Exec("UPDATE admin_users SET role = ? WHERE id = ?", body["role"], c.Param("id"))
+		c.Status(http.StatusOK)
+	})
+	r.GET("/api/admin/team/:id", signIn, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(method, user, body string) int {
+		req := httptest.NewRequest(method, "/api/admin/team/agent-1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if user != "" {
+			req.Header.Set("X-Test-User", user)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := send(http.MethodPut, "chris", `{"role":"team_lead","password":"hunter2","profile":{"api_key":"k"}}`); code != http.StatusOK {
+		t.Fatalf("Expected the handler to still read the body, got %d", code)
+	}
+	send(http.MethodGet, "chris", "")
+	send(http.MethodPut, "", `{"role":"admin"}`)
+
+	var logs []models.AuditLog
+	db.Find(&logs)
+	if len(logs) != 1 {
+		t.Fatalf("Expected only the signed-in write recorded, got %d", len(logs))
+	}
+	entry := logs[0]
+	if entry.ActorID != "admin-1" || entry.EntityType != "team" || entry.EntityID != "agent-1" || entry.Route != "/api/admin/team/:id" {
+		t.Errorf("Unexpected audit entry %+v", entry)
+	}
+	if entry.Request["password"] != auditRedacted || entry.Request["role"] != "team_lead" {
+		t.Errorf("Expected the password redacted and the role kept, got %v", entry.Request)
+	}
+	if profile, _ := entry.Request["profile"].(map[string]interface{}); profile["api_key"] != auditRedacted {
+		t.Errorf("Expected nested secrets redacted, got %v", entry.Request["profile"])
+	}
+	if entry.Before["role"] != "agent" || entry.After["role"] != "team_lead" {
+		t.Errorf("Expected the role change captured, got before %v after %v", entry.Before["role"], entry.After["role"])
+	}
+	if entry.Before["password_hash"] != auditRedacted {
+		t.Errorf("Expected the password hash redacted from the snapshot, got %v", entry.Before["password_hash"])
+	}
+}
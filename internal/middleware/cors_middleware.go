@@ -3,6 +3,7 @@ package middleware
 import (
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
@@ -14,6 +15,7 @@ type CORSMiddleware struct {
 	exposedHeaders   []string
 	maxAge           int
 	allowCredentials bool
+	credentialPaths  []string // when set, credentials are only allowed under these path prefixes
 	logger           *log.Logger
 }
 
@@ -25,6 +27,7 @@ type CORSConfig struct {
 	ExposedHeaders   []string
 	MaxAge           int
 	AllowCredentials bool
+	CredentialPaths  []string
 }
 
 // NewCORSMiddleware creates a new CORS middleware with secure defaults
@@ -42,6 +45,7 @@ func NewCORSMiddleware(logger *log.Logger) *CORSMiddleware {
 			"GET",
 			"POST",
 			"PUT",
+			"PATCH",
 			"DELETE",
 			"OPTIONS",
 			"HEAD",
@@ -60,6 +64,7 @@ func NewCORSMiddleware(logger *log.Logger) *CORSMiddleware {
 			"X-RateLimit-Limit",
 			"X-RateLimit-Remaining",
 			"X-RateLimit-Reset",
+			"X-Request-ID",
 		},
 		maxAge:           86400, // 24 hours
 		allowCredentials: false, // More secure default
@@ -67,13 +72,12 @@ func NewCORSMiddleware(logger *log.Logger) *CORSMiddleware {
 	}
 }
 
-// NewCORSMiddlewareWithConfig creates CORS middleware with custom configuration
+// NewCORSMiddlewareWithConfig creates CORS middleware with custom configuration.
+// Unlike NewCORSMiddleware, no configured origins means no cross-origin access:
+// only same-origin requests are served.
 func NewCORSMiddlewareWithConfig(config CORSConfig, logger *log.Logger) *CORSMiddleware {
 	cors := NewCORSMiddleware(logger)
-
-	if len(config.AllowedOrigins) > 0 {
-		cors.allowedOrigins = config.AllowedOrigins
-	}
+	cors.allowedOrigins = config.AllowedOrigins
 	if len(config.AllowedMethods) > 0 {
 		cors.allowedMethods = config.AllowedMethods
 	}
@@ -87,6 +91,7 @@ func NewCORSMiddlewareWithConfig(config CORSConfig, logger *log.Logger) *CORSMid
 		cors.maxAge = config.MaxAge
 	}
 	cors.allowCredentials = config.AllowCredentials
+	cors.credentialPaths = config.CredentialPaths
 
 	return cors
 }
@@ -95,9 +100,13 @@ func NewCORSMiddlewareWithConfig(config CORSConfig, logger *log.Logger) *CORSMid
 func (c *CORSMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		origin := r.Header.Get("Origin")
+		if isSameOrigin(r, origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
 
 		// Handle preflight requests
-		if r.Method == "OPTIONS" {
+		if isPreflight(r) {
 			c.handlePreflight(w, r, origin)
 			return
 		}
@@ -138,9 +147,9 @@ func (c *CORSMiddleware) handlePreflight(w http.ResponseWriter, r *http.Request,
 	w.Header().Set("Access-Control-Allow-Origin", origin)
 	w.Header().Set("Access-Control-Allow-Methods", strings.Join(c.allowedMethods, ", "))
 	w.Header().Set("Access-Control-Allow-Headers", strings.Join(c.allowedHeaders, ", "))
-	w.Header().Set("Access-Control-Max-Age", string(rune(c.maxAge)))
+	w.Header().Set("Access-Control-Max-Age", strconv.Itoa(c.maxAge))
 
-	if c.allowCredentials {
+	if c.credentialsAllowed(r, origin) {
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 	}
 
@@ -169,7 +178,7 @@ func (c *CORSMiddleware) handleActualRequest(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Set credentials header if enabled
-	if c.allowCredentials {
+	if c.credentialsAllowed(r, origin) {
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 	}
 
@@ -177,6 +186,58 @@ func (c *CORSMiddleware) handleActualRequest(w http.ResponseWriter, r *http.Requ
 	w.Header().Set("Vary", "Origin")
 }
 
+// credentialsAllowed reports whether a request from origin may carry cookies.
+// Credentials are never granted through a "*" origin entry.
+func (c *CORSMiddleware) credentialsAllowed(r *http.Request, origin string) bool {
+	if !c.allowCredentials || !c.isOriginListed(origin) {
+		return false
+	}
+	if len(c.credentialPaths) == 0 {
+		return true
+	}
+	for _, prefix := range c.credentialPaths {
+		if r.URL.Path == prefix || strings.HasPrefix(r.URL.Path, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isOriginListed reports whether an origin is allowed by an explicit or
+// subdomain-wildcard entry rather than "*"
+func (c *CORSMiddleware) isOriginListed(origin string) bool {
+	return originListed(c.allowedOrigins, origin)
+}
+
+// originListed reports whether an origin matches an explicit or
+// subdomain-wildcard entry of allowedOrigins, ignoring "*"
+func originListed(allowedOrigins []string, origin string) bool {
+	for _, allowedOrigin := range allowedOrigins {
+		if allowedOrigin == origin {
+			return true
+		}
+		if strings.HasPrefix(allowedOrigin, "*.") && strings.HasSuffix(origin, strings.TrimPrefix(allowedOrigin, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPreflight reports whether a request is a CORS preflight rather than a plain OPTIONS call
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// isSameOrigin reports whether the Origin header names the host being requested,
+// in which case no CORS handling is needed
+func isSameOrigin(r *http.Request, origin string) bool {
+	if origin == "" {
+		return true
+	}
+	host := strings.TrimPrefix(strings.TrimPrefix(origin, "https://"), "http://")
+	return host == r.Host
+}
+
 // isOriginAllowed checks if an origin is in the allowed list
 func (c *CORSMiddleware) isOriginAllowed(origin string) bool {
 	if origin == "" {
@@ -243,6 +304,9 @@ func (c *CORSMiddleware) isHeaderAllowed(header string) bool {
 	header = strings.ToLower(strings.TrimSpace(header))
 
 	// Always allow simple headers
+	if header == "" {
+		return true
+	}
 	simpleHeaders := []string{
 		"accept",
 		"accept-language",
@@ -258,7 +322,7 @@ func (c *CORSMiddleware) isHeaderAllowed(header string) bool {
 
 	// Check against allowed headers list
 	for _, allowedHeader := range c.allowedHeaders {
-		if strings.ToLower(allowedHeader) == header {
+		if allowedHeader == "*" || strings.ToLower(allowedHeader) == header {
 			return true
 		}
 	}
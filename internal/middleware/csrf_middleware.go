@@ -9,6 +9,16 @@ import (
 	"time"
 )
 
+// CSRF token names. Browsers get the token in the csrf_token cookie and send
+// it back in the X-CSRF-Token header (or the csrf_token form field for plain
+// HTML forms); a request is accepted when the two match (double-submit cookie).
+const (
+	CSRFCookieName = "csrf_token"
+	CSRFHeaderName = "X-CSRF-Token"
+	CSRFFormField  = "csrf_token"
+	csrfCookieTTL  = 3600
+)
+
 // CSRFProtection middleware prevents cross-site request forgery attacks
 // Uses constant-time comparison to prevent timing attacks
 func CSRFProtection() gin.HandlerFunc {
@@ -22,18 +32,13 @@ func CSRFProtection() gin.HandlerFunc {
 
 		// Skip CSRF for safe methods - but still set token for forms
 		if c.Request.Method == "GET" || c.Request.Method == "HEAD" || c.Request.Method == "OPTIONS" {
-			// Generate and set CSRF token for forms
-			token := generateCSRFToken()
-			c.SetCookie("csrf_token", token, 3600, "/", "", false, false)
-			c.Header("X-CSRF-Token", token)
-			// ISSUE #4 FIX: Set token in context so handlers can retrieve it
-			c.Set("csrf_token", token)
+			IssueCSRFToken(c)
 			c.Next()
 			return
 		}
 
 		// For unsafe methods (POST, PUT, DELETE, etc.), verify CSRF token
-		expectedToken, err := c.Cookie("csrf_token")
+		expectedToken, err := c.Cookie(CSRFCookieName)
 		if err != nil || expectedToken == "" {
 			c.JSON(http.StatusForbidden, gin.H{
 				"error":   "CSRF token required",
@@ -44,9 +49,9 @@ func CSRFProtection() gin.HandlerFunc {
 		}
 
 		// Get token from request (header or form)
-		providedToken := c.GetHeader("X-CSRF-Token")
+		providedToken := c.GetHeader(CSRFHeaderName)
 		if providedToken == "" {
-			providedToken = c.PostForm("csrf_token")
+			providedToken = c.PostForm(CSRFFormField)
 		}
 
 		if providedToken == "" {
@@ -69,10 +74,50 @@ func CSRFProtection() gin.HandlerFunc {
 		}
 
 		// Token is valid, continue processing
+		c.Set("csrf_token", expectedToken)
 		c.Next()
 	}
 }
 
+// IssueCSRFToken returns the request's CSRF token, reusing the one in the
+// cookie so a single-page app's token stays valid across requests, and
+// refreshes the cookie, the X-CSRF-Token response header and the context
+func IssueCSRFToken(c *gin.Context) string {
+	if token := c.GetString("csrf_token"); token != "" {
+		return token
+	}
+	token, err := c.Cookie(CSRFCookieName)
+	if err != nil || !isCSRFToken(token) {
+		token = generateCSRFToken()
+	}
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	// Not HttpOnly: scripts read the cookie to echo it in the header
+	c.SetCookie(CSRFCookieName, token, csrfCookieTTL, "/", "", secure, false)
+	c.Header(CSRFHeaderName, token)
+	// ISSUE #4 FIX: Set token in context so handlers can retrieve it
+	c.Set("csrf_token", token)
+	return token
+}
+
+// CSRFTokenHandler issues a CSRF token for JavaScript clients. Send it back
+// in the X-CSRF-Token header on POST, PUT, PATCH and DELETE requests.
+func CSRFTokenHandler(c *gin.Context) {
+	token := IssueCSRFToken(c)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"csrf_token":  token,
+		"header_name": CSRFHeaderName,
+		"expires_in":  csrfCookieTTL,
+	})
+}
+
+// isCSRFToken reports whether token has the shape generateCSRFToken produces
+func isCSRFToken(token string) bool {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	return err == nil && len(decoded) == 32
+}
+
 // constantTimeEquals performs constant-time string comparison
 func constantTimeEquals(a, b string) bool {
 	if len(a) != len(b) {
@@ -86,9 +131,10 @@ func generateCSRFToken() string {
 	token := make([]byte, 32)
 	if _, err := rand.Read(token); err != nil {
 		// Fallback: use timestamp-based token (less secure)
-		return base64.URLEncoding.EncodeToString([]byte(time.Now().String()))
+		return base64.RawURLEncoding.EncodeToString([]byte(time.Now().String()))
 	}
-	return base64.URLEncoding.EncodeToString(token)
+	// Unpadded so the cookie value needs no escaping and scripts can echo it as-is
+	return base64.RawURLEncoding.EncodeToString(token)
 }
 
 // isCSRFExemptPath checks if a path should be exempt from CSRF protection
@@ -99,6 +145,7 @@ func generateCSRFToken() string {
 // 1. Internal automation/sync endpoints (should use API auth instead)
 // 2. Read-only analytics/reporting endpoints (no state mutation)
 // 3. Webhook receivers (external systems can't provide CSRF tokens)
+// 4. RFC 8058 one-click unsubscribe (mail providers POST without a CSRF token)
 //
 // User-facing form submissions (bookings, contact, lead capture) KEEP CSRF protection.
 func isCSRFExemptPath(path string) bool {
@@ -123,6 +170,19 @@ func isCSRFExemptPath(path string) bool {
 		"/api/v1/fub/webhook",           // Follow Up Boss webhooks
 	}
 
+	// Webhooks that verify their own request signatures (exact paths only)
+	switch path {
+	case "/api/webhooks/fub", // X-FUB-Signature
+		"/api/webhooks/twilio": // X-Twilio-Signature
+		return true
+	}
+
+	// Category 4: RFC 8058 one-click unsubscribe, POSTed by mail providers and
+	// authenticated by its signed token (exact path only)
+	if path == "/unsubscribe" || path == "/digest/unsubscribe" {
+		return true
+	}
+
 	// Combine all exempt paths
 	exemptPrefixes := append(internalAutomation, readOnly...)
 	exemptPrefixes = append(exemptPrefixes, webhooks...)
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCSRFProtection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CSRFProtection())
+	r.GET("/api/v1/csrf-token", CSRFTokenHandler)
+	r.POST("/api/v1/bookings", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	r.POST("/api/webhooks/twilio", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/webhooks/inbound-email", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/csrf-token", nil))
+	var issued struct {
+		Token      string `json:"csrf_token"`
+		HeaderName string `json:"header_name"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &issued); err != nil || issued.Token == "" {
+		t.Fatalf("Expected a token, got %s", w.Body.String())
+	}
+	if issued.HeaderName != CSRFHeaderName {
+		t.Errorf("Expected header name %s, got %s", CSRFHeaderName, issued.HeaderName)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != CSRFCookieName || cookies[0].Value != issued.Token {
+		t.Fatalf("Expected the token in the %s cookie, got %v", CSRFCookieName, cookies)
+	}
+	cookie := cookies[0]
+
+	// The cookie's token is reused rather than rotated on later GETs
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/csrf-token", nil)
+	req.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if got := w.Header().Get(CSRFHeaderName); got != issued.Token {
+		t.Errorf("Expected the existing token to be reused, got %q", got)
+	}
+
+	post := func(path, header string, withCookie bool) int {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		if header != "" {
+			req.Header.Set(CSRFHeaderName, header)
+		}
+		if withCookie {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		header     string
+		withCookie bool
+		want       int
+	}{
+		{"valid token", "/api/v1/bookings", issued.Token, true, http.StatusCreated},
+		{"missing header", "/api/v1/bookings", "", true, http.StatusForbidden},
+		{"missing cookie", "/api/v1/bookings", issued.Token, false, http.StatusForbidden},
+		{"mismatched token", "/api/v1/bookings", generateCSRFToken(), true, http.StatusForbidden},
+		{"signed webhook", "/api/webhooks/twilio", "", false, http.StatusOK},
+		{"unsigned webhook", "/api/webhooks/inbound-email", "", false, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if got := post(tt.path, tt.header, tt.withCookie); got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
@@ -3,9 +3,12 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
+	"chrisgross-ctrl-project/internal/auth"
+	"chrisgross-ctrl-project/internal/models"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,6 +21,27 @@ type EndpointRateLimiter struct {
 	requestsPerMinute int
 	requestsPerHour   int
 	blockDuration     time.Duration
+
+	// adminResolver, when set, buckets authenticated admins by username instead of IP
+	adminResolver AdminIdentityResolver
+
+	// onBlock, when set, is told each time a client starts a block
+	onBlock RateLimitBlockHandler
+}
+
+// AdminIdentityResolver returns the authenticated admin username for a request, or "" if anonymous
+type AdminIdentityResolver func(c *gin.Context) string
+
+// RateLimitBlockHandler is told the client key and block expiry when a client is blocked
+type RateLimitBlockHandler func(key string, until time.Time)
+
+// rateLimitStatus is the outcome of evaluating a request against a client's buckets
+type rateLimitStatus struct {
+	blocked      bool
+	remaining    int
+	resetSeconds int64
+	blockStarted bool // This request started the block
+	blockUntil   time.Time
 }
 
 // ClientRateLimit tracks rate limiting for a specific client
@@ -44,20 +68,54 @@ func NewEndpointRateLimiter(requestsPerMinute, requestsPerHour int, blockDuratio
 	return limiter
 }
 
+// KeyByAdmin buckets requests from authenticated admins by username so agents sharing
+// an IP (e.g. behind a corporate NAT) get independent limits. Anonymous requests still
+// fall back to the client IP.
+func (erl *EndpointRateLimiter) KeyByAdmin(resolver AdminIdentityResolver) {
+	erl.mutex.Lock()
+	defer erl.mutex.Unlock()
+	erl.adminResolver = resolver
+}
+
+// OnBlock registers a handler told whenever a client starts a block, e.g. to
+// record repeated admin login attempts as a brute force detection
+func (erl *EndpointRateLimiter) OnBlock(handler RateLimitBlockHandler) {
+	erl.mutex.Lock()
+	defer erl.mutex.Unlock()
+	erl.onBlock = handler
+}
+
+// clientKey returns the bucket key for a request
+func (erl *EndpointRateLimiter) clientKey(c *gin.Context) string {
+	erl.mutex.RLock()
+	resolver := erl.adminResolver
+	erl.mutex.RUnlock()
+
+	if resolver != nil {
+		if username := resolver(c); username != "" {
+			return "admin:" + username
+		}
+	}
+	return c.ClientIP()
+}
+
 // RateLimit returns a Gin middleware function for rate limiting
 func (erl *EndpointRateLimiter) RateLimit() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
+		key := erl.clientKey(c)
+		status := erl.evaluate(key)
+		erl.reportBlock(key, status)
 
-		if blocked, remaining := erl.checkRateLimit(clientIP); blocked {
-			c.Header("X-RateLimit-Limit", fmt.Sprintf("%d per minute, %d per hour", erl.requestsPerMinute, erl.requestsPerHour))
-			c.Header("X-RateLimit-Remaining", "0")
-			c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", remaining))
+		c.Header("X-RateLimit-Limit", strconv.Itoa(erl.requestsPerMinute))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(status.remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(status.resetSeconds, 10))
 
+		if status.blocked {
+			c.Header("Retry-After", strconv.FormatInt(status.resetSeconds, 10))
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":       "rate_limit_exceeded",
 				"message":     "Too many requests. Please try again later.",
-				"retry_after": remaining,
+				"retry_after": status.resetSeconds,
 			})
 			c.Abort()
 			return
@@ -69,6 +127,16 @@ func (erl *EndpointRateLimiter) RateLimit() gin.HandlerFunc {
 
 // checkRateLimit validates if a client can make a request
 func (erl *EndpointRateLimiter) checkRateLimit(clientIP string) (blocked bool, retryAfter int64) {
+	status := erl.evaluate(clientIP)
+	erl.reportBlock(clientIP, status)
+	if status.blocked {
+		return true, status.resetSeconds
+	}
+	return false, 0
+}
+
+// evaluate records a request for the client key and reports the remaining allowance
+func (erl *EndpointRateLimiter) evaluate(clientIP string) rateLimitStatus {
 	erl.mutex.Lock()
 	defer erl.mutex.Unlock()
 
@@ -87,7 +155,7 @@ func (erl *EndpointRateLimiter) checkRateLimit(clientIP string) (blocked bool, r
 
 	// Check if client is still blocked
 	if client.blocked && now.Before(client.blockUntil) {
-		return true, client.blockUntil.Unix() - now.Unix()
+		return rateLimitStatus{blocked: true, resetSeconds: client.blockUntil.Unix() - now.Unix()}
 	}
 
 	// Unblock if block period has passed
@@ -101,18 +169,11 @@ func (erl *EndpointRateLimiter) checkRateLimit(clientIP string) (blocked bool, r
 	client.minuteRequests = erl.filterRecentRequests(client.minuteRequests, now.Add(-time.Minute))
 	client.hourRequests = erl.filterRecentRequests(client.hourRequests, now.Add(-time.Hour))
 
-	// Check minute limit
-	if len(client.minuteRequests) >= erl.requestsPerMinute {
-		client.blocked = true
-		client.blockUntil = now.Add(erl.blockDuration)
-		return true, client.blockUntil.Unix() - now.Unix()
-	}
-
-	// Check hour limit
-	if len(client.hourRequests) >= erl.requestsPerHour {
+	// Check minute and hour limits
+	if len(client.minuteRequests) >= erl.requestsPerMinute || len(client.hourRequests) >= erl.requestsPerHour {
 		client.blocked = true
 		client.blockUntil = now.Add(erl.blockDuration)
-		return true, client.blockUntil.Unix() - now.Unix()
+		return rateLimitStatus{blocked: true, resetSeconds: client.blockUntil.Unix() - now.Unix(), blockStarted: true, blockUntil: client.blockUntil}
 	}
 
 	// Record this request
@@ -120,7 +181,31 @@ func (erl *EndpointRateLimiter) checkRateLimit(clientIP string) (blocked bool, r
 	client.hourRequests = append(client.hourRequests, now)
 	client.lastRequest = now
 
-	return false, 0
+	remaining := erl.requestsPerMinute - len(client.minuteRequests)
+	if hourRemaining := erl.requestsPerHour - len(client.hourRequests); hourRemaining < remaining {
+		remaining = hourRemaining
+	}
+
+	// The minute window frees a slot when its oldest request ages out
+	reset := int64(client.minuteRequests[0].Add(time.Minute).Sub(now).Seconds() + 0.5)
+	if reset < 1 {
+		reset = 1
+	}
+
+	return rateLimitStatus{remaining: remaining, resetSeconds: reset}
+}
+
+// reportBlock tells the block handler, if any, about a block the request started
+func (erl *EndpointRateLimiter) reportBlock(key string, status rateLimitStatus) {
+	if !status.blockStarted {
+		return
+	}
+	erl.mutex.RLock()
+	handler := erl.onBlock
+	erl.mutex.RUnlock()
+	if handler != nil {
+		handler(key, status.blockUntil)
+	}
 }
 
 // filterRecentRequests removes requests older than the cutoff time
@@ -176,6 +261,40 @@ func (erl *EndpointRateLimiter) RecordRequest(clientIP string) (blocked bool, re
 	return erl.checkRateLimit(clientIP)
 }
 
+// SessionAdminResolver resolves the admin username from the session cookie used by AuthRequired,
+// so limiters applied ahead of route-level auth can still key by admin
+func SessionAdminResolver(authManager interface{}) AdminIdentityResolver {
+	return func(c *gin.Context) string {
+		if user, exists := c.Get("user"); exists {
+			if admin, ok := user.(*models.AdminUser); ok && admin != nil {
+				return admin.Username
+			}
+		}
+
+		sessionToken, err := c.Cookie("admin_session_token")
+		if err != nil || sessionToken == "" {
+			sessionToken, err = c.Cookie("admin_session")
+			if err != nil || sessionToken == "" {
+				return ""
+			}
+		}
+
+		var admin *models.AdminUser
+		switch manager := authManager.(type) {
+		case *auth.CachedSessionManager:
+			admin, err = manager.ValidateSessionToken(sessionToken)
+		case *auth.SimpleAuthManager:
+			admin, err = manager.ValidateSessionToken(sessionToken)
+		default:
+			return ""
+		}
+		if err != nil || admin == nil {
+			return ""
+		}
+		return admin.Username
+	}
+}
+
 // FormatRetryTime returns a human-readable string for the retry duration
 func FormatRetryTime(seconds int64) string {
 	if seconds >= 3600 {
@@ -7,14 +7,13 @@ import (
 	"sync"
 	"time"
 
+	"chrisgross-ctrl-project/internal/models"
 	"gorm.io/gorm"
-	"chrisgross-ctrl-project/internal/security"
 )
 
 // SecurityMiddleware provides comprehensive security features
 type SecurityMiddleware struct {
 	db          *gorm.DB
-	auditLogger *security.AuditLogger
 	rateLimiter *RateLimiter
 	ipWhitelist map[string]bool
 	ipBlacklist map[string]bool
@@ -25,7 +24,6 @@ type SecurityMiddleware struct {
 func NewSecurityMiddleware(db *gorm.DB) *SecurityMiddleware {
 	return &SecurityMiddleware{
 		db:          db,
-		auditLogger: security.NewAuditLogger(db),
 		rateLimiter: NewRateLimiter(),
 		ipWhitelist: make(map[string]bool),
 		ipBlacklist: make(map[string]bool),
@@ -105,18 +103,7 @@ func (sm *SecurityMiddleware) RateLimit(config RateLimitConfig) func(http.Handle
 
 			if !sm.rateLimiter.Allow(clientIP, config) {
 				// Log rate limit violation
-				sm.auditLogger.LogSecurityEvent(
-					"rate_limit_exceeded",
-					nil,
-					clientIP,
-					r.Header.Get("User-Agent"),
-					"Rate limit exceeded",
-					map[string]interface{}{
-						"endpoint": r.URL.Path,
-						"method":   r.Method,
-					},
-					60, // Medium-high risk
-				)
+				sm.recordSecurityEvent(r, clientIP, "RATE_LIMIT_EXCEEDED", models.SecuritySeverityMedium, http.StatusTooManyRequests, "", nil)
 
 				w.Header().Set("Retry-After", "60")
 				http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
@@ -141,18 +128,7 @@ func (sm *SecurityMiddleware) IPFiltering(next http.Handler) http.Handler {
 
 		// Block if blacklisted
 		if isBlacklisted {
-			sm.auditLogger.LogSecurityEvent(
-				"blacklisted_ip_access",
-				nil,
-				clientIP,
-				r.Header.Get("User-Agent"),
-				"Access attempt from blacklisted IP",
-				map[string]interface{}{
-					"endpoint": r.URL.Path,
-					"method":   r.Method,
-				},
-				80, // High risk
-			)
+			sm.recordSecurityEvent(r, clientIP, "IP_BLOCKED", models.SecuritySeverityHigh, http.StatusForbidden, "", nil)
 
 			http.Error(w, "Access denied", http.StatusForbidden)
 			return
@@ -160,18 +136,7 @@ func (sm *SecurityMiddleware) IPFiltering(next http.Handler) http.Handler {
 
 		// If whitelist exists, only allow whitelisted IPs
 		if hasWhitelist && !isWhitelisted {
-			sm.auditLogger.LogSecurityEvent(
-				"non_whitelisted_ip_access",
-				nil,
-				clientIP,
-				r.Header.Get("User-Agent"),
-				"Access attempt from non-whitelisted IP",
-				map[string]interface{}{
-					"endpoint": r.URL.Path,
-					"method":   r.Method,
-				},
-				50, // Medium risk
-			)
+			sm.recordSecurityEvent(r, clientIP, "IP_NOT_ALLOWED", models.SecuritySeverityMedium, http.StatusForbidden, "", nil)
 
 			http.Error(w, "Access denied", http.StatusForbidden)
 			return
@@ -187,19 +152,10 @@ func (sm *SecurityMiddleware) RequestValidation(config SecurityConfig) func(http
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Check request size
 			if config.MaxRequestSize > 0 && r.ContentLength > config.MaxRequestSize {
-				sm.auditLogger.LogSecurityEvent(
-					"oversized_request",
-					nil,
-					sm.getClientIP(r),
-					r.Header.Get("User-Agent"),
-					"Request size exceeds limit",
-					map[string]interface{}{
-						"content_length": r.ContentLength,
-						"max_size":       config.MaxRequestSize,
-						"endpoint":       r.URL.Path,
-					},
-					40, // Medium risk
-				)
+				sm.recordSecurityEvent(r, sm.getClientIP(r), "OVERSIZED_REQUEST", models.SecuritySeverityMedium, http.StatusRequestEntityTooLarge, "", map[string]interface{}{
+					"content_length": r.ContentLength,
+					"max_size":       config.MaxRequestSize,
+				})
 
 				http.Error(w, "Request too large", http.StatusRequestEntityTooLarge)
 				return
@@ -209,18 +165,9 @@ func (sm *SecurityMiddleware) RequestValidation(config SecurityConfig) func(http
 			userAgent := r.Header.Get("User-Agent")
 			for _, blockedUA := range config.BlockedUserAgents {
 				if strings.Contains(strings.ToLower(userAgent), strings.ToLower(blockedUA)) {
-					sm.auditLogger.LogSecurityEvent(
-						"blocked_user_agent",
-						nil,
-						sm.getClientIP(r),
-						userAgent,
-						"Blocked user agent detected",
-						map[string]interface{}{
-							"blocked_pattern": blockedUA,
-							"endpoint":        r.URL.Path,
-						},
-						60, // Medium-high risk
-					)
+					sm.recordSecurityEvent(r, sm.getClientIP(r), "BLOCKED_USER_AGENT", models.SecuritySeverityHigh, http.StatusForbidden, userAgent, map[string]interface{}{
+						"blocked_pattern": blockedUA,
+					})
 
 					http.Error(w, "Access denied", http.StatusForbidden)
 					return
@@ -238,18 +185,7 @@ func (sm *SecurityMiddleware) RequestValidation(config SecurityConfig) func(http
 			}
 
 			if !methodAllowed {
-				sm.auditLogger.LogSecurityEvent(
-					"invalid_http_method",
-					nil,
-					sm.getClientIP(r),
-					userAgent,
-					"Invalid HTTP method used",
-					map[string]interface{}{
-						"method":   r.Method,
-						"endpoint": r.URL.Path,
-					},
-					30, // Low-medium risk
-				)
+				sm.recordSecurityEvent(r, sm.getClientIP(r), "INVALID_HTTP_METHOD", models.SecuritySeverityLow, http.StatusMethodNotAllowed, r.Method, nil)
 
 				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 				return
@@ -257,17 +193,7 @@ func (sm *SecurityMiddleware) RequestValidation(config SecurityConfig) func(http
 
 			// Check for suspicious patterns in URL
 			if sm.containsSuspiciousPatterns(r.URL.Path) {
-				sm.auditLogger.LogSecurityEvent(
-					"suspicious_url_pattern",
-					nil,
-					sm.getClientIP(r),
-					userAgent,
-					"Suspicious URL pattern detected",
-					map[string]interface{}{
-						"url": r.URL.Path,
-					},
-					70, // High risk
-				)
+				sm.recordSecurityEvent(r, sm.getClientIP(r), "SUSPICIOUS_URL_PATTERN", models.SecuritySeverityHigh, http.StatusBadRequest, r.URL.Path, nil)
 
 				http.Error(w, "Bad request", http.StatusBadRequest)
 				return
@@ -291,24 +217,15 @@ func (sm *SecurityMiddleware) BruteForceProtection(next http.Handler) http.Handl
 
 		// Check for recent failed attempts
 		var failedAttempts int64
-		sm.db.Model(&security.SecurityEvent{}).
+		sm.db.Model(&models.SecurityEvent{}).
 			Where("event_type = ? AND ip_address = ? AND created_at > ?",
 				"login_failure", clientIP, time.Now().Add(-15*time.Minute)).
 			Count(&failedAttempts)
 
 		if failedAttempts >= 5 {
-			sm.auditLogger.LogSecurityEvent(
-				"brute_force_detected",
-				nil,
-				clientIP,
-				r.Header.Get("User-Agent"),
-				"Brute force attack detected",
-				map[string]interface{}{
-					"failed_attempts": failedAttempts,
-					"endpoint":        r.URL.Path,
-				},
-				90, // Critical risk
-			)
+			sm.recordSecurityEvent(r, clientIP, models.SecurityEventBruteForce, models.SecuritySeverityCritical, http.StatusTooManyRequests, "", map[string]interface{}{
+				"failed_attempts": failedAttempts,
+			})
 
 			// Add to temporary blacklist
 			sm.mutex.Lock()
@@ -338,19 +255,9 @@ func (sm *SecurityMiddleware) SQLInjectionProtection(next http.Handler) http.Han
 		for key, values := range r.URL.Query() {
 			for _, value := range values {
 				if sm.containsSQLInjection(value) {
-					sm.auditLogger.LogSecurityEvent(
-						"sql_injection_attempt",
-						nil,
-						sm.getClientIP(r),
-						r.Header.Get("User-Agent"),
-						"SQL injection attempt detected",
-						map[string]interface{}{
-							"parameter": key,
-							"value":     value,
-							"endpoint":  r.URL.Path,
-						},
-						95, // Critical risk
-					)
+					sm.recordSecurityEvent(r, sm.getClientIP(r), models.SecurityEventSQLInjection, models.SecuritySeverityCritical, http.StatusBadRequest, value, map[string]interface{}{
+						"parameter": key,
+					})
 
 					http.Error(w, "Bad request", http.StatusBadRequest)
 					return
@@ -369,19 +276,9 @@ func (sm *SecurityMiddleware) XSSProtection(next http.Handler) http.Handler {
 		for key, values := range r.URL.Query() {
 			for _, value := range values {
 				if sm.containsXSS(value) {
-					sm.auditLogger.LogSecurityEvent(
-						"xss_attempt",
-						nil,
-						sm.getClientIP(r),
-						r.Header.Get("User-Agent"),
-						"XSS attempt detected",
-						map[string]interface{}{
-							"parameter": key,
-							"value":     value,
-							"endpoint":  r.URL.Path,
-						},
-						85, // High risk
-					)
+					sm.recordSecurityEvent(r, sm.getClientIP(r), models.SecurityEventXSS, models.SecuritySeverityHigh, http.StatusBadRequest, value, map[string]interface{}{
+						"parameter": key,
+					})
 
 					http.Error(w, "Bad request", http.StatusBadRequest)
 					return
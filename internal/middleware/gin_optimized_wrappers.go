@@ -6,9 +6,25 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// GinCORSWrapper provides an optimized Gin wrapper for CORS middleware
+// GinCORSWrapper provides an optimized Gin wrapper for CORS middleware. Preflight
+// requests are answered and aborted; other requests continue down the chain.
 func GinCORSWrapper(corsMiddleware *CORSMiddleware) gin.HandlerFunc {
-	return gin.WrapH(corsMiddleware.Middleware(nil))
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if isSameOrigin(c.Request, origin) {
+			c.Next()
+			return
+		}
+
+		if isPreflight(c.Request) {
+			corsMiddleware.handlePreflight(c.Writer, c.Request, origin)
+			c.Abort()
+			return
+		}
+
+		corsMiddleware.handleActualRequest(c.Writer, c.Request, origin)
+		c.Next()
+	}
 }
 
 // GinSecurityWrapper provides an optimized Gin wrapper for security headers
@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"chrisgross-ctrl-project/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// InputScanConfig scopes SQL injection and XSS scanning to untrusted fields
+type InputScanConfig struct {
+	// RichTextFields are field names (any depth, case-insensitive) whose
+	// values are free text, e.g. property descriptions, and are not scanned
+	RichTextFields []string
+	// SkipPaths are path prefixes that are not scanned, e.g. signed webhooks
+	SkipPaths []string
+	// MaxBodyBytes limits how much of a JSON body is scanned
+	MaxBodyBytes int64
+	// Handler reports a rejected field; nil uses DefaultValidationHandler
+	Handler ValidationHandler
+}
+
+// DefaultInputScanConfig allowlists the free-text fields used across the API
+func DefaultInputScanConfig() InputScanConfig {
+	return InputScanConfig{
+		RichTextFields: []string{
+			"description", "notes", "internal_notes", "message", "body", "content",
+			"html_content", "text_content", "comments", "bio", "subject", "template",
+		},
+		SkipPaths:    []string{"/api/webhooks/", "/static/"},
+		MaxBodyBytes: 1 << 20,
+	}
+}
+
+// Attack patterns matched as syntax rather than bare keywords, so words like
+// "select" or "created_at" and apostrophes in names pass
+var (
+	sqlInjectionPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\bunion\b[\s\S]*\bselect\b`),
+		regexp.MustCompile(`(?i);\s*(drop|delete|insert|update|alter|create|truncate|exec)\b`),
+		regexp.MustCompile(`(?i)'\s*(or|and)\s+'?[\w]+'?\s*(=|like)\s*'?[\w]*`),
+		regexp.MustCompile(`(?i)\b(or|and)\s+(\d+)\s*=\s*(\d+)\b`),
+		regexp.MustCompile(`'\s*(--|#|/\*)`),
+		regexp.MustCompile(`(?i)\b(sleep|benchmark|pg_sleep)\s*\(`),
+		regexp.MustCompile(`(?i)\bwaitfor\s+delay\b`),
+		regexp.MustCompile(`(?i)\b(xp_cmdshell|information_schema)\b`),
+	}
+	xssPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)<\s*/?\s*(script|iframe|object|embed|svg|math|base|meta|link|style)\b`),
+		regexp.MustCompile(`(?i)<[^>]*\bon[a-z]+\s*=`),
+		regexp.MustCompile(`(?i)(javascript|vbscript)\s*:`),
+		regexp.MustCompile(`(?i)data\s*:\s*text/html`),
+		regexp.MustCompile(`(?i)\bdocument\s*\.\s*(cookie|write)\b`),
+	}
+)
+
+// scanInput returns the kind of attack value looks like, or ""
+func scanInput(value string) string {
+	for _, pattern := range sqlInjectionPatterns {
+		if pattern.MatchString(value) {
+			return "sql_injection"
+		}
+	}
+	for _, pattern := range xssPatterns {
+		if pattern.MatchString(value) {
+			return "xss"
+		}
+	}
+	return ""
+}
+
+// InputScanning rejects requests whose untrusted query, form or JSON values
+// look like SQL injection or XSS, logging the attempt. Rich-text fields are
+// left to schema validation and output escaping.
+func (sm *SecurityMiddleware) InputScanning(config InputScanConfig) gin.HandlerFunc {
+	richText := make(map[string]bool, len(config.RichTextFields))
+	for _, field := range config.RichTextFields {
+		richText[strings.ToLower(field)] = true
+	}
+	handler := config.Handler
+	if handler == nil {
+		handler = DefaultValidationHandler
+	}
+
+	return func(c *gin.Context) {
+		if hasPathPrefix(c.Request.URL.Path, config.SkipPaths) {
+			c.Next()
+			return
+		}
+
+		reject := func(source, field, value, attack string) {
+			eventType, severity := models.SecurityEventSQLInjection, models.SecuritySeverityCritical
+			if attack == "xss" {
+				eventType, severity = models.SecurityEventXSS, models.SecuritySeverityHigh
+			}
+			sm.recordSecurityEvent(c.Request, c.ClientIP(), eventType, severity, http.StatusBadRequest, value, map[string]interface{}{
+				"source": source,
+				"field":  field,
+			})
+			c.Abort()
+			handler(c, ValidationFailure{
+				Field:   field,
+				Rule:    attack,
+				Source:  source,
+				Message: field + " contains disallowed content",
+			})
+		}
+
+		for key, values := range c.Request.URL.Query() {
+			if richText[strings.ToLower(key)] {
+				continue
+			}
+			for _, value := range values {
+				if attack := scanInput(value); attack != "" {
+					reject("query", key, value, attack)
+					return
+				}
+			}
+		}
+
+		contentType := c.ContentType()
+		switch {
+		case contentType == "application/x-www-form-urlencoded" || contentType == "multipart/form-data":
+			if err := c.Request.ParseMultipartForm(32 << 20); err != nil && c.Request.PostForm == nil {
+				break
+			}
+			for key, values := range c.Request.PostForm {
+				if richText[strings.ToLower(key)] {
+					continue
+				}
+				for _, value := range values {
+					if attack := scanInput(value); attack != "" {
+						reject("form", key, value, attack)
+						return
+					}
+				}
+			}
+		case strings.HasSuffix(contentType, "json") && c.Request.Body != nil:
+			body, err := io.ReadAll(io.LimitReader(c.Request.Body, config.MaxBodyBytes+1))
+			if err != nil {
+				break
+			}
+			// Hand the body back untouched (including anything past the limit)
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+			if int64(len(body)) > config.MaxBodyBytes {
+				break
+			}
+			var payload interface{}
+			if json.Unmarshal(body, &payload) != nil {
+				break
+			}
+			if field, value, attack := scanJSON(payload, "", richText); attack != "" {
+				reject("body", field, value, attack)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// scanJSON walks a decoded JSON value, returning the path and value of the
+// first untrusted string that looks like an attack
+func scanJSON(value interface{}, path string, richText map[string]bool) (string, string, string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if richText[strings.ToLower(key)] {
+				continue
+			}
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			if field, found, attack := scanJSON(child, childPath, richText); attack != "" {
+				return field, found, attack
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if field, found, attack := scanJSON(child, path, richText); attack != "" {
+				return field, found, attack
+			}
+		}
+	case string:
+		if attack := scanInput(v); attack != "" {
+			return path, v, attack
+		}
+	}
+	return "", "", ""
+}
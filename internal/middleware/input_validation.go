@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidatedBodyKey is the gin context key holding the body ValidateJSON decoded
+const ValidatedBodyKey = "validated_body"
+
+// ValidationFailure describes the first field of a request that failed validation
+type ValidationFailure struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Source  string `json:"source"` // "body", "query" or "form"
+	Message string `json:"message"`
+}
+
+// ValidationHandler responds to a request that failed validation. The
+// request has already been aborted.
+type ValidationHandler func(c *gin.Context, failure ValidationFailure)
+
+// DefaultValidationHandler responds 400 with the failed field
+func DefaultValidationHandler(c *gin.Context, failure ValidationFailure) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":   "Validation failed",
+		"details": failure.Message,
+		"field":   failure.Field,
+		"rule":    failure.Rule,
+	})
+}
+
+// RequestValidator validates request bodies against struct schemas using
+// their binding tags, reporting failures by JSON field name
+type RequestValidator struct {
+	validate *validator.Validate
+	handler  ValidationHandler
+}
+
+// NewRequestValidator creates a request validator reporting through DefaultValidationHandler
+func NewRequestValidator() *RequestValidator {
+	validate := validator.New()
+	validate.SetTagName("binding")
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return &RequestValidator{validate: validate, handler: DefaultValidationHandler}
+}
+
+// SetValidationHandler replaces how validation failures are reported
+func (rv *RequestValidator) SetValidationHandler(handler ValidationHandler) {
+	if handler == nil {
+		handler = DefaultValidationHandler
+	}
+	rv.handler = handler
+}
+
+// DefaultRequestValidator is used by ValidateJSON
+var DefaultRequestValidator = NewRequestValidator()
+
+// ValidateJSON validates the JSON body against schema with the default validator
+func ValidateJSON(schema interface{}) gin.HandlerFunc {
+	return DefaultRequestValidator.JSON(schema)
+}
+
+// JSON returns middleware decoding the JSON body into a new value of
+// schema's type and validating it. The body is left readable for the handler,
+// and the decoded value is stored under ValidatedBodyKey.
+func (rv *RequestValidator) JSON(schema interface{}) gin.HandlerFunc {
+	schemaType := reflect.TypeOf(schema)
+	if schemaType.Kind() == reflect.Ptr {
+		schemaType = schemaType.Elem()
+	}
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			rv.fail(c, ValidationFailure{Source: "body", Rule: "readable", Message: "Failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Set(gin.BodyBytesKey, body)
+
+		value := reflect.New(schemaType).Interface()
+		if err := json.Unmarshal(body, value); err != nil {
+			failure := ValidationFailure{Source: "body", Rule: "json", Message: "Request body is not valid JSON"}
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				failure.Field = typeErr.Field
+				failure.Rule = "type"
+				failure.Message = fmt.Sprintf("%s must be a %s", typeErr.Field, typeErr.Type.Kind())
+			}
+			rv.fail(c, failure)
+			return
+		}
+		if err := rv.validate.Struct(value); err != nil {
+			var fieldErrs validator.ValidationErrors
+			if errors.As(err, &fieldErrs) && len(fieldErrs) > 0 {
+				rv.fail(c, fieldFailure(fieldErrs[0]))
+				return
+			}
+			rv.fail(c, ValidationFailure{Source: "body", Rule: "schema", Message: err.Error()})
+			return
+		}
+
+		c.Set(ValidatedBodyKey, value)
+		c.Next()
+	}
+}
+
+func (rv *RequestValidator) fail(c *gin.Context, failure ValidationFailure) {
+	c.Abort()
+	rv.handler(c, failure)
+}
+
+// fieldFailure describes a validator field error by its JSON path, e.g. "applicants[0].email"
+func fieldFailure(fieldErr validator.FieldError) ValidationFailure {
+	field := fieldErr.Namespace()
+	if _, rest, found := strings.Cut(field, "."); found {
+		field = rest
+	}
+	message := fmt.Sprintf("%s failed the %s rule", field, fieldErr.Tag())
+	switch fieldErr.Tag() {
+	case "required":
+		message = field + " is required"
+	case "email":
+		message = field + " must be a valid email address"
+	case "min", "max", "len", "oneof", "gte", "lte", "gt", "lt":
+		message = fmt.Sprintf("%s must satisfy %s=%s", field, fieldErr.Tag(), fieldErr.Param())
+	}
+	return ValidationFailure{Field: field, Rule: fieldErr.Tag(), Source: "body", Message: message}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type testListingRequest struct {
+	Title       string `json:"title" binding:"required"`
+	Email       string `json:"email" binding:"required,email"`
+	Bedrooms    int    `json:"bedrooms" binding:"min=0,max=20"`
+	Description string `json:"description"`
+}
+
+func TestValidateJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator := NewRequestValidator()
+	var reported ValidationFailure
+	validator.SetValidationHandler(func(c *gin.Context, failure ValidationFailure) {
+		reported = failure
+		c.JSON(http.StatusUnprocessableEntity, failure)
+	})
+
+	r := gin.New()
+	r.POST("/listings", validator.JSON(testListingRequest{}), func(c *gin.Context) {
+		var req testListingRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			t.Errorf("Expected the handler to read the body again: %v", err)
+		}
+		if _, ok := c.Get(ValidatedBodyKey); !ok {
+			t.Error("Expected the validated body in the context")
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	tests := []struct {
+		body      string
+		wantCode  int
+		wantField string
+		wantRule  string
+	}{
+		{`{"title":"Loft","email":"a@example.com","bedrooms":2}`, http.StatusCreated, "", ""},
+		{`{"email":"a@example.com"}`, http.StatusUnprocessableEntity, "title", "required"},
+		{`{"title":"Loft","email":"not-an-email"}`, http.StatusUnprocessableEntity, "email", "email"},
+		{`{"title":"Loft","email":"a@example.com","bedrooms":99}`, http.StatusUnprocessableEntity, "bedrooms", "max"},
+		{`{"title":"Loft","email":"a@example.com","bedrooms":"two"}`, http.StatusUnprocessableEntity, "bedrooms", "type"},
+	}
+	for _, tt := range tests {
+		reported = ValidationFailure{}
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/listings", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		if w.Code != tt.wantCode || reported.Field != tt.wantField || reported.Rule != tt.wantRule {
+			t.Errorf("%s: got %d %+v, want %d %s/%s", tt.body, w.Code, reported, tt.wantCode, tt.wantField, tt.wantRule)
+		}
+	}
+}
+
+func TestInputScanning(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	r := gin.New()
+	r.Use(NewSecurityMiddleware(db).InputScanning(DefaultInputScanConfig()))
+	r.Any("/api/properties", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	allowed := []struct{ target, body string }{
+		{"/api/properties?sort=created_at&q=O'Brien", ""},
+		{"/api/properties?q=select+homes+in+Austin", ""},
+		{"/api/properties", `{"title":"Loft","description":"Read up on <script> tag best practices; select finishes throughout"}`},
+		{"/api/properties", `{"units":[{"notes":"1' OR '1'='1"}]}`},
+	}
+	for _, tt := range allowed {
+		if w := send(http.MethodPost, tt.target, tt.body); w.Code != http.StatusOK {
+			t.Errorf("Expected %s %s to pass, got %d %s", tt.target, tt.body, w.Code, w.Body.String())
+		}
+	}
+
+	rejected := []struct{ target, body, field string }{
+		{"/api/properties?city=Austin'+OR+'1'='1", "", "city"},
+		{"/api/properties?id=1%3B+DROP+TABLE+properties", "", "id"},
+		{"/api/properties", `{"title":"<img src=x onerror=alert(1)>"}`, "title"},
+		{"/api/properties", `{"owner":{"website":"javascript:alert(1)"}}`, "owner.website"},
+	}
+	for _, tt := range rejected {
+		w := send(http.MethodPost, tt.target, tt.body)
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		if w.Code != http.StatusBadRequest || resp["field"] != tt.field {
+			t.Errorf("Expected %s %s to be rejected on %s, got %d %s", tt.target, tt.body, tt.field, w.Code, w.Body.String())
+		}
+	}
+
+}
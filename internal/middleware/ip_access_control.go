@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// IPAccessControl returns 403 to denylisted IPs on every route and, once an
+// allowlist exists, to IPs outside it on adminPaths. The client IP is gin's
+// ClientIP, which only honors X-Forwarded-For and X-Real-IP from the engine's
+// trusted proxies (gin.Engine.SetTrustedProxies), so clients cannot spoof
+// their way onto the allowlist.
+func IPAccessControl(access *services.IPAccessService, adminPaths []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientIP := c.ClientIP()
+
+		if access.Denied(clientIP) {
+			log.Printf("🚫 Denied request from denylisted IP %s to %s", clientIP, c.Request.URL.Path)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		if hasPathPrefix(c.Request.URL.Path, adminPaths) && !access.AdminAllowed(clientIP) {
+			log.Printf("🚫 Denied admin request from non-allowlisted IP %s to %s", clientIP, c.Request.URL.Path)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied", "details": "admin access is restricted to allowlisted networks"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestIPAccessControl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.IPAccessRule{}, &models.SecurityEvent{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	access, err := services.NewIPAccessService(db, services.IPAccessSettings{Allowlist: []string{"203.0.113.0/24"}})
+	if err != nil {
+		t.Fatalf("Failed to create IP access service: %v", err)
+	}
+	if _, err := access.AddRule(models.IPAccessRule{List: models.IPAccessDeny, CIDR: "198.51.100.7"}); err != nil {
+		t.Fatalf("Failed to add deny rule: %v", err)
+	}
+
+	r := gin.New()
+	if err := r.SetTrustedProxies([]string{"10.0.0.0/8"}); err != nil {
+		t.Fatalf("Failed to set trusted proxies: %v", err)
+	}
+	r.Use(IPAccessControl(access, []string{"/api/v1/admin/"}))
+	r.GET("/api/v1/admin/ip-access/rules", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/properties", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	cases := []struct {
+		name      string
+		path      string
+		remote    string
+		forwarded string
+		want      int
+	}{
+		{"allowlisted direct client", "/api/v1/admin/ip-access/rules", "203.0.113.10:4000", "", http.StatusOK},
+		{"other direct client", "/api/v1/admin/ip-access/rules", "192.0.2.1:4000", "", http.StatusForbidden},
+		{"forwarded header from untrusted client", "/api/v1/admin/ip-access/rules", "192.0.2.1:4000", "203.0.113.10", http.StatusForbidden},
+		{"allowlisted client behind trusted proxy", "/api/v1/admin/ip-access/rules", "10.0.0.5:4000", "203.0.113.10", http.StatusOK},
+		{"spoofed hop prepended behind trusted proxy", "/api/v1/admin/ip-access/rules", "10.0.0.5:4000", "203.0.113.10, 192.0.2.1", http.StatusForbidden},
+		{"public route from any client", "/properties", "192.0.2.1:4000", "", http.StatusOK},
+		{"denylisted client on public route", "/properties", "198.51.100.7:4000", "", http.StatusForbidden},
+		{"denylisted client behind trusted proxy", "/properties", "10.0.0.5:4000", "198.51.100.7", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		req.RemoteAddr = tc.remote
+		if tc.forwarded != "" {
+			req.Header.Set("X-Forwarded-For", tc.forwarded)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.want, w.Code)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireMFAReverification rejects admins who have not re-verified MFA within
+// the service's window. Register it after AuthRequired.
+func RequireMFAReverification(reverification *services.MFAReverificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get("user")
+		admin, ok := value.(*models.AdminUser)
+		if !ok || admin == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+		if !reverification.Verified(admin.ID) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":        "MFA re-verification required",
+				"details":      "confirm an MFA code at /api/v1/admin/mfa/reverify and retry",
+				"mfa_required": true,
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"time"
+
+	"chrisgross-ctrl-project/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// PerformanceTracking records per-route latency and status codes in the monitoring service.
+// It is a no-op when monitoring is unavailable (e.g. Redis is not configured).
+func PerformanceTracking(monitor *services.PerformanceMonitoringService) gin.HandlerFunc {
+	if monitor == nil {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		// Use the route pattern so /properties/1 and /properties/2 share a bucket
+		path := c.FullPath()
+		if path == "" {
+			path = "unmatched"
+		}
+
+		status := c.Writer.Status()
+		monitor.TrackRequest(c.Request.Method, path, time.Since(start), status, status >= 500)
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLimitsConfig bounds request body sizes and handler run time. Upload
+// paths (imports, attachments) get their own, larger limits; exempt paths
+// (WebSocket upgrades) get none.
+type RequestLimitsConfig struct {
+	MaxBodyBytes       int64
+	UploadMaxBodyBytes int64
+	Timeout            time.Duration
+	UploadTimeout      time.Duration
+	UploadPaths        []string
+	ExemptPaths        []string
+}
+
+// DefaultRequestLimitsConfig allows 10MB bodies and 30s handlers, and 100MB
+// and 5 minutes on upload paths
+func DefaultRequestLimitsConfig() RequestLimitsConfig {
+	return RequestLimitsConfig{
+		MaxBodyBytes:       10 << 20,
+		UploadMaxBodyBytes: 100 << 20,
+		Timeout:            30 * time.Second,
+		UploadTimeout:      5 * time.Minute,
+		ExemptPaths:        []string{"/api/ws", "/api/notifications/ws"},
+	}
+}
+
+// limitsFor returns the body limit and timeout for a path; zero means none
+func (config RequestLimitsConfig) limitsFor(path string) (int64, time.Duration) {
+	if hasPathPrefix(path, config.ExemptPaths) {
+		return 0, 0
+	}
+	if hasPathPrefix(path, config.UploadPaths) {
+		return config.UploadMaxBodyBytes, config.UploadTimeout
+	}
+	return config.MaxBodyBytes, config.Timeout
+}
+
+// BodySizeLimit rejects bodies over the path's limit with 413. Bodies with a
+// declared length are rejected up front; others are cut off at the limit, and
+// handlers see an error IsBodyTooLarge recognises.
+func BodySizeLimit(config RequestLimitsConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, _ := config.limitsFor(c.Request.URL.Path)
+		if limit <= 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > limit {
+			c.Header("Connection", "close")
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":   "Request body too large",
+				"details": fmt.Sprintf("Request bodies on this endpoint are limited to %d bytes", limit),
+			})
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// IsBodyTooLarge reports whether err came from reading past BodySizeLimit
+func IsBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// RequestTimeout gives each request a context deadline. Handlers and queries
+// using c.Request.Context() are cancelled at the deadline; if a handler
+// returns after the deadline without responding, the client gets 503.
+// Handlers that ignore the context still run to completion, since gin
+// contexts cannot be used safely once the middleware has returned.
+func RequestTimeout(config RequestLimitsConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, timeout := config.limitsFor(c.Request.URL.Path)
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Request timed out",
+				"details": fmt.Sprintf("The request did not complete within %s", timeout),
+			})
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config := RequestLimitsConfig{
+		MaxBodyBytes:       16,
+		UploadMaxBodyBytes: 64,
+		Timeout:            20 * time.Millisecond,
+		UploadTimeout:      time.Second,
+		UploadPaths:        []string{"/api/migration/"},
+		ExemptPaths:        []string{"/api/ws"},
+	}
+
+	r := gin.New()
+	r.Use(BodySizeLimit(config), RequestTimeout(config))
+	readBody := func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			if IsBodyTooLarge(err) {
+				c.Status(http.StatusRequestEntityTooLarge)
+				return
+			}
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusOK)
+	}
+	r.POST("/api/leads", readBody)
+	r.POST("/api/migration/import/properties", readBody)
+	r.POST("/api/ws", readBody)
+	r.GET("/api/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+
+	send := func(method, path string, body io.Reader) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, body))
+		return w.Code
+	}
+	small := strings.Repeat("a", 10)
+	large := strings.Repeat("a", 32)
+
+	tests := []struct {
+		name string
+		path string
+		body io.Reader
+		want int
+	}{
+		{"small body", "/api/leads", strings.NewReader(small), http.StatusOK},
+		{"declared large body", "/api/leads", strings.NewReader(large), http.StatusRequestEntityTooLarge},
+		// Without a declared length the body is cut off while the handler reads it
+		{"streamed large body", "/api/leads", io.MultiReader(strings.NewReader(large)), http.StatusRequestEntityTooLarge},
+		{"upload path", "/api/migration/import/properties", strings.NewReader(large), http.StatusOK},
+		{"upload limit", "/api/migration/import/properties", strings.NewReader(strings.Repeat(large, 3)), http.StatusRequestEntityTooLarge},
+		{"exempt path", "/api/ws", strings.NewReader(strings.Repeat(large, 3)), http.StatusOK},
+	}
+	for _, tt := range tests {
+		if got := send(http.MethodPost, tt.path, tt.body); got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+	// A handler still running at the deadline gets 503
+	if got := send(http.MethodGet, "/api/slow", nil); got != http.StatusServiceUnavailable {
+		t.Errorf("slow handler: expected %d, got %d", http.StatusServiceUnavailable, got)
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"time"
+
+	"chrisgross-ctrl-project/internal/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request ID on requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs
+const maxRequestIDLength = 128
+
+// RequestLogging assigns each request an ID (reusing a sane incoming X-Request-ID),
+// attaches it and a tagged logger to the request context, echoes it in the
+// response, and logs method, path, status, and latency once the request completes.
+func RequestLogging(logger logging.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.New().String()
+		}
+
+		ctx := logging.WithLogger(c.Request.Context(), logger)
+		ctx = logging.WithRequestID(ctx, requestID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(logging.RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		args := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"route", c.FullPath(),
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+		}
+		if len(c.Errors) > 0 {
+			args = append(args, "errors", c.Errors.String())
+		}
+
+		requestLogger := logging.FromContext(ctx)
+		switch {
+		case status >= 500:
+			requestLogger.Error("request completed", args...)
+		case status >= 400:
+			requestLogger.Warn("request completed", args...)
+		default:
+			requestLogger.Info("request completed", args...)
+		}
+	}
+}
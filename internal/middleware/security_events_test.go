@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSecurityEventsRecordDetections(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.SecurityEvent{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	sm := NewSecurityMiddleware(db)
+
+	r := gin.New()
+	r.Use(sm.InputScanning(DefaultInputScanConfig()))
+	r.POST("/api/v1/leads", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/leads", strings.NewReader(`{"email":"x' OR '1'='1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected the injection rejected, got %d", w.Code)
+	}
+
+	limiter := NewEndpointRateLimiter(1, 10, time.Minute)
+	limiter.OnBlock(sm.BruteForceRecorder("/admin/login"))
+	limiter.RecordRequest("203.0.113.9")
+	if blocked, _ := limiter.RecordRequest("203.0.113.9"); !blocked {
+		t.Fatal("Expected the second attempt blocked")
+	}
+	limiter.RecordRequest("203.0.113.9")
+
+	var events []models.SecurityEvent
+	db.Order("id").Find(&events)
+	if len(events) != 2 {
+		t.Fatalf("Expected an injection and one brute force block recorded, got %d", len(events))
+	}
+	injection, bruteForce := events[0], events[1]
+	if injection.Type != models.SecurityEventSQLInjection || injection.Endpoint != "/api/v1/leads" ||
+		injection.Payload != "x' OR '1'='1" || injection.Metadata["field"] != "email" || injection.Resolved {
+		t.Errorf("Unexpected injection event %+v", injection)
+	}
+	if bruteForce.Type != models.SecurityEventBruteForce || bruteForce.IPAddress != "203.0.113.9" || bruteForce.Endpoint != "/admin/login" {
+		t.Errorf("Unexpected brute force event %+v", bruteForce)
+	}
+
+	if snippet := securitySnippet(strings.Repeat("é", securityPayloadLimit)); len(snippet) > securityPayloadLimit+len("…") || !strings.HasSuffix(snippet, "é…") {
+		t.Errorf("Expected the payload cut on a character boundary, got %d bytes", len(snippet))
+	}
+}
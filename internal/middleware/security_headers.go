@@ -0,0 +1,221 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CSPNonceKey is the gin context key holding the request's CSP nonce
+const CSPNonceKey = "csp_nonce"
+
+// cspNoncePlaceholder is replaced by the request's nonce in the CSP, e.g.
+// "script-src 'self' 'nonce-{nonce}'"
+const cspNoncePlaceholder = "{nonce}"
+
+// DefaultContentSecurityPolicy is used when no policy is configured
+const DefaultContentSecurityPolicy = "default-src 'self'; script-src 'self' 'unsafe-inline' 'unsafe-eval'; style-src 'self' 'unsafe-inline' https://fonts.googleapis.com; img-src 'self' data: https:; font-src 'self' https://fonts.gstatic.com; connect-src 'self'; frame-ancestors 'none';"
+
+// SecurityHeadersConfig holds the security headers sent with every response.
+// Empty headers are not sent.
+type SecurityHeadersConfig struct {
+	ContentSecurityPolicy   string
+	FrameOptions            string
+	StrictTransportSecurity string
+	ReferrerPolicy          string
+	PermissionsPolicy       string
+	XSSProtection           string
+	// NoSniffExcludePrefixes are paths that are sent without nosniff
+	NoSniffExcludePrefixes []string
+	// Overrides relax headers for requests under their path prefixes
+	Overrides []SecurityHeaderOverride
+}
+
+// SecurityHeaderOverride changes headers for part of the site, e.g. allowing
+// embeddable widgets to be framed by partner sites
+type SecurityHeaderOverride struct {
+	PathPrefixes []string
+	// CSPDirectives replace directives of the same name; an empty value removes the directive
+	CSPDirectives map[string]string
+	// Headers replace headers of the same name; an empty value removes the header
+	Headers map[string]string
+}
+
+type cspDirective struct {
+	name  string
+	value string
+}
+
+// SecurityHeaders sets the configured security headers and a per-request CSP nonce
+type SecurityHeaders struct {
+	config SecurityHeadersConfig
+	csp    []cspDirective
+}
+
+// NewSecurityHeaders creates the security headers middleware
+func NewSecurityHeaders(config SecurityHeadersConfig) *SecurityHeaders {
+	if config.ContentSecurityPolicy == "" {
+		config.ContentSecurityPolicy = DefaultContentSecurityPolicy
+	}
+	return &SecurityHeaders{
+		config: config,
+		csp:    parseCSP(config.ContentSecurityPolicy),
+	}
+}
+
+// Middleware generates the request's nonce and sets the security headers
+func (sh *SecurityHeaders) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		nonce, err := generateCSPNonce()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate CSP nonce", "details": err.Error()})
+			return
+		}
+		c.Set(CSPNonceKey, nonce)
+		c.Writer = &nonceResponseWriter{ResponseWriter: c.Writer, nonce: nonce}
+
+		path := c.Request.URL.Path
+		var overrides []SecurityHeaderOverride
+		for _, override := range sh.config.Overrides {
+			if hasPathPrefix(path, override.PathPrefixes) {
+				overrides = append(overrides, override)
+			}
+		}
+		sh.apply(c, nonce, overrides...)
+		c.Next()
+	}
+}
+
+// Override relaxes headers for a route group. It must run after Middleware.
+func (sh *SecurityHeaders) Override(override SecurityHeaderOverride) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sh.apply(c, CSPNonce(c), override)
+		c.Next()
+	}
+}
+
+func (sh *SecurityHeaders) apply(c *gin.Context, nonce string, overrides ...SecurityHeaderOverride) {
+	headers := map[string]string{
+		"X-Frame-Options":           sh.config.FrameOptions,
+		"X-XSS-Protection":          sh.config.XSSProtection,
+		"Strict-Transport-Security": sh.config.StrictTransportSecurity,
+		"Referrer-Policy":           sh.config.ReferrerPolicy,
+		"Permissions-Policy":        sh.config.PermissionsPolicy,
+	}
+	// Static files are served without nosniff so their types are still sniffed
+	if !hasPathPrefix(c.Request.URL.Path, sh.config.NoSniffExcludePrefixes) {
+		headers["X-Content-Type-Options"] = "nosniff"
+	}
+
+	directives := sh.csp
+	for _, override := range overrides {
+		directives = mergeCSP(directives, override.CSPDirectives)
+		for name, value := range override.Headers {
+			headers[http.CanonicalHeaderKey(name)] = value
+		}
+	}
+	headers["Content-Security-Policy"] = strings.ReplaceAll(formatCSP(directives), cspNoncePlaceholder, nonce)
+
+	for name, value := range headers {
+		// gin removes the header when the value is empty
+		c.Header(name, value)
+	}
+}
+
+// CSPNonce returns the request's CSP nonce, or "" if the security headers
+// middleware has not run
+func CSPNonce(c *gin.Context) string {
+	return c.GetString(CSPNonceKey)
+}
+
+// nonceResponseWriter carries the request's nonce to the template renderer,
+// which only sees the response writer
+type nonceResponseWriter struct {
+	gin.ResponseWriter
+	nonce string
+}
+
+// CSPNonce returns the nonce for inline scripts in the response
+func (w *nonceResponseWriter) CSPNonce() string {
+	return w.nonce
+}
+
+func generateCSPNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+func parseCSP(policy string) []cspDirective {
+	var directives []cspDirective
+	for _, part := range strings.Split(policy, ";") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		directives = append(directives, cspDirective{
+			name:  strings.ToLower(fields[0]),
+			value: strings.Join(fields[1:], " "),
+		})
+	}
+	return directives
+}
+
+// mergeCSP returns directives with the replacements applied, keeping the
+// original order and appending new directives in name order
+func mergeCSP(directives []cspDirective, replacements map[string]string) []cspDirective {
+	if len(replacements) == 0 {
+		return directives
+	}
+	merged := make([]cspDirective, 0, len(directives)+len(replacements))
+	seen := make(map[string]bool, len(replacements))
+	for _, directive := range directives {
+		value, replaced := replacements[directive.name]
+		if !replaced {
+			merged = append(merged, directive)
+			continue
+		}
+		seen[directive.name] = true
+		if value != "" {
+			merged = append(merged, cspDirective{name: directive.name, value: value})
+		}
+	}
+	var added []string
+	for name, value := range replacements {
+		if !seen[name] && value != "" {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		merged = append(merged, cspDirective{name: name, value: replacements[name]})
+	}
+	return merged
+}
+
+func formatCSP(directives []cspDirective) string {
+	parts := make([]string, 0, len(directives))
+	for _, directive := range directives {
+		if directive.value == "" {
+			parts = append(parts, directive.name)
+		} else {
+			parts = append(parts, directive.name+" "+directive.value)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+func hasPathPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
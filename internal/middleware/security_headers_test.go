@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	headers := NewSecurityHeaders(SecurityHeadersConfig{
+		ContentSecurityPolicy:  "default-src 'self'; script-src 'self' 'nonce-{nonce}'; frame-ancestors 'none'",
+		FrameOptions:           "DENY",
+		NoSniffExcludePrefixes: []string{"/static/"},
+		Overrides: []SecurityHeaderOverride{{
+			PathPrefixes:  []string{"/embed/"},
+			CSPDirectives: map[string]string{"frame-ancestors": "https://partner.example.com"},
+			Headers:       map[string]string{"x-frame-options": ""},
+		}},
+	})
+
+	r := gin.New()
+	r.Use(headers.Middleware())
+	r.GET("/page", func(c *gin.Context) { c.String(http.StatusOK, CSPNonce(c)) })
+	r.GET("/static/app.js", func(c *gin.Context) { c.String(http.StatusOK, "") })
+	r.GET("/embed/search", func(c *gin.Context) { c.String(http.StatusOK, "") })
+	widgets := r.Group("/widgets", headers.Override(SecurityHeaderOverride{
+		CSPDirectives: map[string]string{"frame-ancestors": "*", "script-src": ""},
+	}))
+	widgets.GET("/map", func(c *gin.Context) { c.String(http.StatusOK, "") })
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	page := get("/page")
+	nonce := page.Body.String()
+	if nonce == "" {
+		t.Fatal("Expected a CSP nonce in the context")
+	}
+	if want := "script-src 'self' 'nonce-" + nonce + "'"; !strings.Contains(page.Header().Get("Content-Security-Policy"), want) {
+		t.Errorf("Expected CSP to contain %q, got %q", want, page.Header().Get("Content-Security-Policy"))
+	}
+	if page.Header().Get("X-Content-Type-Options") != "nosniff" || page.Header().Get("X-Frame-Options") != "DENY" {
+		t.Errorf("Expected nosniff and DENY on pages, got %v", page.Header())
+	}
+	if second := get("/page").Body.String(); second == nonce {
+		t.Error("Expected a new nonce per request")
+	}
+
+	if static := get("/static/app.js"); static.Header().Get("X-Content-Type-Options") != "" {
+		t.Error("Expected static files to be sent without nosniff")
+	}
+
+	embed := get("/embed/search")
+	if embed.Header().Get("X-Frame-Options") != "" {
+		t.Error("Expected X-Frame-Options to be removed for embeddable paths")
+	}
+	if csp := embed.Header().Get("Content-Security-Policy"); !strings.HasSuffix(csp, "frame-ancestors https://partner.example.com") {
+		t.Errorf("Expected the embed frame-ancestors, got %q", csp)
+	}
+
+	widget := get("/widgets/map")
+	if csp := widget.Header().Get("Content-Security-Policy"); csp != "default-src 'self'; frame-ancestors *" {
+		t.Errorf("Expected the route group override, got %q", csp)
+	}
+}
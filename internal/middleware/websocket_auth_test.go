@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+type fakeSessionValidator map[string]*models.AdminUser
+
+func (v fakeSessionValidator) ValidateSessionToken(sessionToken string) (*models.AdminUser, error) {
+	if user, ok := v[sessionToken]; ok {
+		return user, nil
+	}
+	return nil, errors.New("session not found or expired")
+}
+
+func TestWebSocketAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const token = "c2Vzc2lvbi10b2tlbg=="
+	validator := fakeSessionValidator{token: {ID: "admin-1", Role: models.RoleAdmin}}
+
+	upgrader := websocket.Upgrader{Subprotocols: []string{WebSocketSubprotocol}, CheckOrigin: CheckWebSocketOrigin}
+	r := gin.New()
+	r.GET("/api/ws", WebSocketAuth(validator, []string{"https://app.example.com", "*"}), func(c *gin.Context) {
+		if c.GetString("user_role") != models.RoleAdmin {
+			t.Errorf("Expected the admin's role in the context, got %q", c.GetString("user_role"))
+		}
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	})
+	server := httptest.NewServer(r)
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/ws"
+
+	rejected := map[string]http.Header{
+		"anonymous":        nil,
+		"invalid cookie":   {"Cookie": {"admin_session_token=forged"}},
+		"invalid protocol": {"Sec-WebSocket-Protocol": {WebSocketSubprotocol + ", token.forged"}},
+	}
+	for name, header := range rejected {
+		conn, resp, err := websocket.DefaultDialer.Dial(wsURL, header)
+		if err == nil {
+			conn.Close()
+			t.Errorf("%s: expected the upgrade to be rejected", name)
+			continue
+		}
+		if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401, got %v (%v)", name, resp, err)
+		}
+	}
+
+	// The token subprotocol carries the token without its padding
+	dialer := websocket.Dialer{Subprotocols: []string{WebSocketSubprotocol, "token." + strings.TrimRight(token, "=")}}
+	conn, resp, err := dialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("Expected the token subprotocol to authenticate, got %v (%v)", err, resp)
+	}
+	if conn.Subprotocol() != WebSocketSubprotocol {
+		t.Errorf("Expected the %s subprotocol, got %q", WebSocketSubprotocol, conn.Subprotocol())
+	}
+	conn.Close()
+
+	conn, _, err = websocket.DefaultDialer.Dial(wsURL+"?token="+token, nil)
+	if err != nil {
+		t.Fatalf("Expected the token query parameter to authenticate, got %v", err)
+	}
+	conn.Close()
+
+	// A page on another site cannot ride the admin's session cookie; "*" does not cover it
+	cookie := "admin_session_token=" + token
+	_, resp, err = websocket.DefaultDialer.Dial(wsURL, http.Header{"Cookie": {cookie}, "Origin": {"https://evil.example"}})
+	if err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected a cross-site upgrade to be rejected with 403, got %v (%v)", resp, err)
+	}
+	for _, origin := range []string{server.URL, "https://app.example.com"} {
+		conn, _, err = websocket.DefaultDialer.Dial(wsURL, http.Header{"Cookie": {cookie}, "Origin": {origin}})
+		if err != nil {
+			t.Errorf("Expected an upgrade from %s to be accepted, got %v", origin, err)
+			continue
+		}
+		conn.Close()
+	}
+
+	// An upgrader using CheckWebSocketOrigin refuses routes without WebSocketAuth
+	unchecked := gin.New()
+	unchecked.GET("/ws", func(c *gin.Context) { upgrader.Upgrade(c.Writer, c.Request, nil) })
+	uncheckedServer := httptest.NewServer(unchecked)
+	defer uncheckedServer.Close()
+	if conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(uncheckedServer.URL, "http")+"/ws", nil); err == nil {
+		conn.Close()
+		t.Error("Expected an upgrade without WebSocketAuth to be refused")
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"chrisgross-ctrl-project/internal/addressutil"
+	"gorm.io/gorm"
+)
+
+// BackfillNormalizedAddresses fills the normalized address columns of pre-listing
+// items and approvals saved before the columns existed
+func BackfillNormalizedAddresses(db *gorm.DB) (int, error) {
+	updated := 0
+
+	var items []PreListingItem
+	result := db.Unscoped().
+		Where("(normalized_address IS NULL OR normalized_address = '') AND address <> ''").
+		FindInBatches(&items, 200, func(tx *gorm.DB, batch int) error {
+			for _, item := range items {
+				if err := tx.Unscoped().Model(&PreListingItem{}).Where("id = ?", item.ID).
+					UpdateColumn("normalized_address", addressutil.Normalize(item.Address)).Error; err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		})
+	if result.Error != nil {
+		return updated, result.Error
+	}
+
+	var approvals []Approval
+	result = db.
+		Where("(normalized_property_address IS NULL OR normalized_property_address = '') AND property_address <> ''").
+		FindInBatches(&approvals, 200, func(tx *gorm.DB, batch int) error {
+			for _, approval := range approvals {
+				if err := tx.Model(&Approval{}).Where("id = ?", approval.ID).
+					UpdateColumn("normalized_property_address", addressutil.Normalize(approval.PropertyAddress)).Error; err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		})
+	return updated, result.Error
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// AdminNotificationRead records that one admin has read (or dismissed) one
+// admin notification. Notifications without a row are unread for that admin.
+type AdminNotificationRead struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	NotificationID uint      `json:"notification_id" gorm:"not null;uniqueIndex:idx_admin_notification_reads_admin_notification"`
+	AdminUserID    string    `json:"admin_user_id" gorm:"not null;uniqueIndex:idx_admin_notification_reads_admin_notification;index"`
+	ReadAt         time.Time `json:"read_at"`
+}
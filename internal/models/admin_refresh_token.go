@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// AdminRefreshToken is a server-side record of an issued admin refresh token.
+// Only the SHA-256 hash of the token is stored. Tokens issued from the same
+// login share a FamilyID so a whole chain can be revoked at once.
+type AdminRefreshToken struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	AdminUserID   string     `gorm:"index;size:36;not null" json:"admin_user_id"`
+	FamilyID      string     `gorm:"index;size:36;not null" json:"family_id"`
+	TokenHash     string     `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	ExpiresAt     time.Time  `gorm:"index" json:"expires_at"`
+	RotatedAt     *time.Time `json:"rotated_at,omitempty"`
+	ReplacedByID  *uint      `json:"replaced_by_id,omitempty"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason string     `json:"revoked_reason,omitempty"`
+	IPAddress     string     `json:"ip_address"`
+	UserAgent     string     `json:"user_agent"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TableName overrides the table name
+func (AdminRefreshToken) TableName() string {
+	return "admin_refresh_tokens"
+}
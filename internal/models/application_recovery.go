@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Applicant completion states
+const (
+	ApplicantStarted   = "started"   // Began the application but hasn't submitted it
+	ApplicantSubmitted = "submitted" // Submitted the application
+)
+
+// Application recovery outcomes
+const (
+	RecoveryPending    = "pending"    // Reminders still being sent
+	RecoveryCompleted  = "completed"  // Applicant submitted after being reminded
+	RecoveryIgnored    = "ignored"    // Every reminder went out without the applicant finishing
+	RecoverySuppressed = "suppressed" // Stopped by an unsubscribe, do-not-contact or safety block
+)
+
+// ApplicationRecovery tracks the reminders sent to an applicant who started
+// an application and didn't finish it, and how the recovery turned out
+type ApplicationRecovery struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	ApplicantID     uint       `json:"applicant_id" gorm:"uniqueIndex;not null"`
+	ApplicantEmail  string     `json:"applicant_email" gorm:"index"`
+	PropertyAddress string     `json:"property_address"`
+	RemindersSent   int        `json:"reminders_sent" gorm:"default:0"`
+	LastChannels    string     `json:"last_channels,omitempty" gorm:"size:20"` // "email", "sms" or "email,sms"
+	LastReminderAt  *time.Time `json:"last_reminder_at,omitempty"`
+	Outcome         string     `json:"outcome" gorm:"size:20;index;default:'pending'"`
+	OutcomeReason   string     `json:"outcome_reason,omitempty"`
+	OutcomeAt       *time.Time `json:"outcome_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (ApplicationRecovery) TableName() string {
+	return "application_recoveries"
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidApplicationTransition is returned for a status change outside the
+// application lifecycle
+var ErrInvalidApplicationTransition = errors.New("invalid application status transition")
+
+// applicationTransitions lists the statuses each application status may move
+// to. Applications are screened (review and its sub-steps) before a decision;
+// denied and cancelled are final, and an approval can only be cancelled.
+var applicationTransitions = map[string][]string{
+	AppStatusSubmitted:             {AppStatusReview, AppStatusCancelled},
+	AppStatusReview:                {AppStatusFurtherReview, AppStatusRentalHistoryReceived, AppStatusApproved, AppStatusDenied, AppStatusBackup, AppStatusCancelled},
+	AppStatusFurtherReview:         {AppStatusReview, AppStatusRentalHistoryReceived, AppStatusApproved, AppStatusDenied, AppStatusBackup, AppStatusCancelled},
+	AppStatusRentalHistoryReceived: {AppStatusReview, AppStatusFurtherReview, AppStatusApproved, AppStatusDenied, AppStatusBackup, AppStatusCancelled},
+	AppStatusBackup:                {AppStatusReview, AppStatusApproved, AppStatusDenied, AppStatusCancelled},
+	AppStatusApproved:              {AppStatusCancelled},
+	AppStatusDenied:                {},
+	AppStatusCancelled:             {},
+}
+
+// IsApplicationStatus reports whether status is a known application status
+func IsApplicationStatus(status string) bool {
+	_, ok := applicationTransitions[status]
+	return ok
+}
+
+// IsApplicationDecided reports whether an application in status has been
+// decided, so its applicants can no longer change
+func IsApplicationDecided(status string) bool {
+	return status == AppStatusApproved || status == AppStatusDenied || status == AppStatusCancelled
+}
+
+// AllowedApplicationTransitions returns the statuses an application in status may move to
+func AllowedApplicationTransitions(status string) []string {
+	return applicationTransitions[status]
+}
+
+// ValidateApplicationTransition returns an error unless an application may
+// move from one status to the other
+func ValidateApplicationTransition(from, to string) error {
+	if !IsApplicationStatus(to) {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidApplicationTransition, to)
+	}
+	if from == "" {
+		from = AppStatusSubmitted
+	}
+	for _, allowed := range applicationTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: cannot move from %q to %q", ErrInvalidApplicationTransition, from, to)
+}
@@ -94,6 +94,13 @@ type ApplicationApplicant struct {
 	EmploymentStatus  string  `json:"employment_status"`
 	RentalHistory     string  `json:"rental_history"`
 	
+	// Screening (required for every applicant before the application is approved)
+	ScreenedAt *time.Time `json:"screened_at,omitempty"`
+	ScreenedBy string     `json:"screened_by,omitempty"`
+	
+	// Completion: "started" until the applicant submits the application
+	CompletionStatus string `json:"completion_status" gorm:"size:20;default:'submitted';index"`
+	
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// AuditLog records one write an authenticated admin made through the API,
+// with the affected entity's state before and after where it is tracked
+type AuditLog struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ActorID    string    `gorm:"size:64;index" json:"actor_id"`
+	ActorName  string    `json:"actor_name"`
+	ActorRole  string    `gorm:"size:32" json:"actor_role"`
+	Method     string    `gorm:"size:10;index" json:"method"`
+	Route      string    `json:"route"` // Route pattern, e.g. /api/v1/properties/:id
+	Path       string    `json:"path"`
+	EntityType string    `gorm:"size:64;index" json:"entity_type"`
+	EntityID   string    `gorm:"size:64;index" json:"entity_id,omitempty"`
+	StatusCode int       `json:"status_code"`
+	Request    JSONB     `gorm:"type:json" json:"request,omitempty"` // Redacted request body
+	Before     JSONB     `gorm:"type:json" json:"before,omitempty"`
+	After      JSONB     `gorm:"type:json" json:"after,omitempty"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName overrides the table name
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -60,6 +60,9 @@ type Property struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
+	// Concurrency control for admin edits
+	OptimisticLock
+
 	// Relationships
 	Bookings []Booking `json:"bookings,omitempty" gorm:"foreignKey:PropertyID"`
 }
@@ -76,6 +79,14 @@ type AdminUser struct {
 	LoginCount   int        `json:"login_count" gorm:"default:0"`
 	CreatedAt    time.Time  `json:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// MFA backup codes: space-separated hashes of unused single-use codes
+	MFABackupCodes            string     `json:"-" gorm:"column:mfa_backup_codes;type:text"`
+	MFABackupCodesGeneratedAt *time.Time `json:"mfa_backup_codes_generated_at,omitempty"`
+
+	// TOTP: encrypted secret, enabled once the admin confirms a code
+	MFATOTPSecret    security.EncryptedString `json:"-" gorm:"column:mfa_totp_secret;type:text"`
+	MFATOTPEnabledAt *time.Time               `json:"mfa_totp_enabled_at,omitempty" gorm:"column:mfa_totp_enabled_at"`
 }
 
 func (AdminUser) TableName() string {
@@ -144,6 +155,8 @@ func (p *Property) BeforeUpdate(tx *gorm.DB) error {
 			NewPrice:        p.Price,
 			ChangeAmount:    p.Price - original.Price,
 			ChangePercent:   percentChange,
+			Direction:       PriceDirection(p.Price - original.Price),
+			Source:          "listing",
 			ChangedAt:       time.Now(),
 		}
 
@@ -164,10 +177,29 @@ type PriceChangeEvent struct {
 	NewPrice        float64    `gorm:"not null" json:"new_price"`
 	ChangeAmount    float64    `json:"change_amount"`
 	ChangePercent   float64    `json:"change_percent"`
+	Direction       string     `json:"direction"`                       // up or down
+	Source          string     `gorm:"default:'listing'" json:"source"` // listing or valuation
 	ChangedAt       time.Time  `gorm:"not null;index" json:"changed_at"`
 	ProcessedAt     *time.Time `json:"processed_at,omitempty"`
 	CampaignSent    bool       `gorm:"default:false" json:"campaign_sent"`
 	CreatedAt       time.Time  `json:"created_at"`
+
+	// SearchAlertsCheckedAt is set once the change has been matched against saved searches
+	SearchAlertsCheckedAt *time.Time `gorm:"index" json:"search_alerts_checked_at,omitempty"`
+}
+
+// Price change directions
+const (
+	PriceDirectionUp   = "up"
+	PriceDirectionDown = "down"
+)
+
+// PriceDirection returns the direction of a signed price change
+func PriceDirection(changeAmount float64) string {
+	if changeAmount < 0 {
+		return PriceDirectionDown
+	}
+	return PriceDirectionUp
 }
 
 type DataImport struct {
@@ -60,6 +60,9 @@ type BehavioralEvent struct {
 	SessionID  string                 `json:"session_id,omitempty"`
 	IPAddress  string                 `json:"ip_address,omitempty"`
 	UserAgent  string                 `json:"user_agent,omitempty"`
+	// ClientEventID is an optional client-generated ID; a retried event with the
+	// same ID returns the stored event instead of inserting a new one
+	ClientEventID *string   `json:"client_event_id,omitempty" gorm:"uniqueIndex;size:100"`
 	CreatedAt  time.Time              `json:"created_at" gorm:"autoCreateTime"`
 }
 
@@ -76,8 +79,9 @@ func (BehavioralEvent) TableName() string {
 type BehavioralSession struct {
 	ID              string     `json:"id" gorm:"primaryKey"` // UUID
 	LeadID          int64      `json:"lead_id"`
-	StartTime       time.Time  `json:"start_time"`
-	EndTime         *time.Time `json:"end_time,omitempty"`
+	StartTime       time.Time  `json:"start_time" gorm:"index:idx_behavioral_sessions_active,priority:2"`
+	EndTime         *time.Time `json:"end_time,omitempty" gorm:"index:idx_behavioral_sessions_active,priority:1"`
+	LastEventAt     *time.Time `json:"last_event_at,omitempty" gorm:"index"` // Used to close sessions that go idle
 	DurationSeconds int        `json:"duration_seconds"`
 	PageViews       int        `json:"page_views"`
 	Interactions    int        `json:"interactions"`
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// CampaignEmergencyStopID is the primary key of the single emergency stop row
+const CampaignEmergencyStopID = 1
+
+// CampaignEmergencyStop persists the campaign emergency stop so a restart
+// does not quietly resume sending
+type CampaignEmergencyStop struct {
+	ID          uint       `gorm:"primaryKey" json:"-"`
+	Active      bool       `json:"active" gorm:"not null;default:false"`
+	Reason      string     `json:"reason,omitempty"`
+	ActivatedBy string     `json:"activated_by,omitempty"`
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (CampaignEmergencyStop) TableName() string {
+	return "campaign_emergency_stops"
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CampaignSuppressionQuery is a saved lead filter whose matches are excluded
+// from a campaign. A lead matches when it meets every non-empty filter.
+type CampaignSuppressionQuery struct {
+	Segments         []string `json:"segments,omitempty"`
+	RiskLevels       []string `json:"risk_levels,omitempty"`
+	CampaignStatuses []string `json:"campaign_statuses,omitempty"`
+	Sources          []string `json:"sources,omitempty"` // original_source values
+}
+
+// IsEmpty reports whether the query has no filters, and so matches nothing
+func (q CampaignSuppressionQuery) IsEmpty() bool {
+	return len(q.Segments) == 0 && len(q.RiskLevels) == 0 && len(q.CampaignStatuses) == 0 && len(q.Sources) == 0
+}
+
+// Scan implements the sql.Scanner interface for CampaignSuppressionQuery
+func (q *CampaignSuppressionQuery) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*q = CampaignSuppressionQuery{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("failed to unmarshal campaign suppression query: %v", value)
+	}
+	if len(data) == 0 {
+		*q = CampaignSuppressionQuery{}
+		return nil
+	}
+	return json.Unmarshal(data, q)
+}
+
+// Value implements the driver.Valuer interface for CampaignSuppressionQuery
+func (q CampaignSuppressionQuery) Value() (driver.Value, error) {
+	return json.Marshal(q)
+}
+
+// LeadIDList stores a list of lead IDs as JSON
+type LeadIDList []uint
+
+// Scan implements the sql.Scanner interface for LeadIDList
+func (l *LeadIDList) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*l = LeadIDList{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("failed to unmarshal lead ID list: %v", value)
+	}
+	if len(data) == 0 {
+		*l = LeadIDList{}
+		return nil
+	}
+	return json.Unmarshal(data, l)
+}
+
+// Value implements the driver.Valuer interface for LeadIDList
+func (l LeadIDList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	return json.Marshal(l)
+}
+
+// CampaignSuppression excludes a set of leads, a saved query's matches, or
+// both from every campaign with the given name, on top of the global DNC list
+type CampaignSuppression struct {
+	ID           uint                     `json:"id" gorm:"primaryKey"`
+	CampaignName string                   `json:"campaign_name" gorm:"index;size:200;not null"`
+	Reason       string                   `json:"reason,omitempty" gorm:"size:255"` // e.g. "already in buyer pipeline"
+	LeadIDs      LeadIDList               `json:"lead_ids" gorm:"type:text"`
+	Query        CampaignSuppressionQuery `json:"query" gorm:"type:text"`
+	Active       bool                     `json:"active" gorm:"index;default:true"`
+	CreatedBy    string                   `json:"created_by,omitempty"`
+	CreatedAt    time.Time                `json:"created_at"`
+	UpdatedAt    time.Time                `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (CampaignSuppression) TableName() string {
+	return "campaign_suppressions"
+}
+
+// Validate checks the suppression names a campaign and excludes someone
+func (s CampaignSuppression) Validate() error {
+	if s.CampaignName == "" {
+		return fmt.Errorf("campaign_name is required")
+	}
+	if len(s.LeadIDs) == 0 && s.Query.IsEmpty() {
+		return fmt.Errorf("lead_ids or query is required")
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidClosingStageTransition is returned for a stage change outside the
+// allowed stage order
+var ErrInvalidClosingStageTransition = errors.New("invalid closing stage transition")
+
+// Closing pipeline stages
+const (
+	ClosingStagePending    = "pending"
+	ClosingStageInProgress = "in_progress"
+	ClosingStageReady      = "ready"
+	ClosingStageCompleted  = "completed"
+)
+
+// closingStageTransitions lists the stages each stage may move to. A closing
+// moves forward one stage at a time and may step back one stage to fix a
+// mistake; completed closings are final.
+var closingStageTransitions = map[string][]string{
+	ClosingStagePending:    {ClosingStageInProgress},
+	ClosingStageInProgress: {ClosingStageReady, ClosingStagePending},
+	ClosingStageReady:      {ClosingStageCompleted, ClosingStageInProgress},
+	ClosingStageCompleted:  {},
+}
+
+// IsClosingStage reports whether stage is a known closing pipeline stage
+func IsClosingStage(stage string) bool {
+	_, ok := closingStageTransitions[stage]
+	return ok
+}
+
+// AllowedClosingStageTransitions returns the stages a closing in stage may move to
+func AllowedClosingStageTransitions(stage string) []string {
+	return closingStageTransitions[stage]
+}
+
+// ValidateClosingStageTransition returns an error unless a closing may move
+// from one stage to the other
+func ValidateClosingStageTransition(from, to string) error {
+	if !IsClosingStage(to) {
+		return fmt.Errorf("%w: unknown stage %q", ErrInvalidClosingStageTransition, to)
+	}
+	if from == "" {
+		from = ClosingStagePending
+	}
+	for _, allowed := range closingStageTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: cannot move from %q to %q", ErrInvalidClosingStageTransition, from, to)
+}
+
+// ClosingPipelineStageHistory records one stage transition of a closing
+type ClosingPipelineStageHistory struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	ClosingPipelineID uint      `json:"closing_pipeline_id" gorm:"index;not null"`
+	FromStatus        string    `json:"from_status" gorm:"size:20"`
+	ToStatus          string    `json:"to_status" gorm:"size:20;not null"`
+	Actor             string    `json:"actor" gorm:"size:100"`
+	Notes             string    `json:"notes,omitempty" gorm:"type:text"`
+	CreatedAt         time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName overrides the table name
+func (ClosingPipelineStageHistory) TableName() string {
+	return "closing_pipeline_stage_history"
+}
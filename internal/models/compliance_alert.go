@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// ComplianceAlert is a persisted compliance alert. At most one unresolved
+// alert of each type exists at a time.
+type ComplianceAlert struct {
+	ID             string     `gorm:"primaryKey;size:64" json:"id"`
+	Type           string     `gorm:"index;not null" json:"type"`
+	Severity       string     `gorm:"index" json:"severity"`
+	Title          string     `json:"title"`
+	Message        string     `gorm:"type:text" json:"message"`
+	Action         string     `gorm:"type:text" json:"action"`
+	Acknowledged   bool       `gorm:"default:false" json:"acknowledged"`
+	AcknowledgedBy string     `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	Resolved       bool       `gorm:"index;default:false" json:"resolved"`
+	ResolvedBy     string     `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (ComplianceAlert) TableName() string {
+	return "compliance_alerts"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ComplianceCheckSnapshot stores the result of one scheduled compliance check
+type ComplianceCheckSnapshot struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	CheckedAt         time.Time `gorm:"index;not null" json:"checked_at"`
+	IsCompliant       bool      `json:"is_compliant"`
+	OverallScore      float64   `json:"overall_score"`
+	ReputationScore   float64   `json:"reputation_score"`
+	LegalScore        float64   `json:"legal_score"`
+	VolumeUtilization float64   `json:"volume_utilization"`
+	RiskFactors       int       `json:"risk_factors"`
+	ActiveAlerts      int       `json:"active_alerts"`
+	Status            JSONB     `gorm:"type:jsonb" json:"status,omitempty"` // Full ComplianceStatus
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// TableName overrides the table name
+func (ComplianceCheckSnapshot) TableName() string {
+	return "compliance_check_snapshots"
+}
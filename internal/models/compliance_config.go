@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ComplianceConfigID is the primary key of the single compliance config row
+const ComplianceConfigID = 1
+
+// ComplianceConfig holds the scoring weights and reputation thresholds used by
+// compliance monitoring. The four weights must sum to 1.0.
+type ComplianceConfig struct {
+	ID uint `gorm:"primaryKey" json:"-"`
+
+	// Overall score weights
+	VolumeWeight     float64 `json:"volume_weight"`
+	ReputationWeight float64 `json:"reputation_weight"`
+	LegalWeight      float64 `json:"legal_weight"`
+	EmergencyWeight  float64 `json:"emergency_weight"`
+
+	// Reputation thresholds (rates are percentages)
+	MinOverallScore    float64 `json:"min_overall_score"`
+	MaxBounceRate      float64 `json:"max_bounce_rate"`
+	MaxComplaintRate   float64 `json:"max_complaint_rate"`
+	MaxUnsubscribeRate float64 `json:"max_unsubscribe_rate"`
+	MinOpenRate        float64 `json:"min_open_rate"`
+	MinClickRate       float64 `json:"min_click_rate"`
+
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (ComplianceConfig) TableName() string {
+	return "compliance_configs"
+}
+
+// DefaultComplianceConfig returns the weights and thresholds used when none are saved
+func DefaultComplianceConfig() ComplianceConfig {
+	return ComplianceConfig{
+		ID:                 ComplianceConfigID,
+		VolumeWeight:       0.25,
+		ReputationWeight:   0.35,
+		LegalWeight:        0.25,
+		EmergencyWeight:    0.15,
+		MinOverallScore:    70.0,
+		MaxBounceRate:      5.0,
+		MaxComplaintRate:   0.1,
+		MaxUnsubscribeRate: 5.0,
+		MinOpenRate:        15.0,
+		MinClickRate:       1.0,
+	}
+}
+
+// Validate checks that weights are non-negative and sum to 1.0 and that
+// thresholds are within range
+func (c ComplianceConfig) Validate() error {
+	weights := map[string]float64{
+		"volume_weight":     c.VolumeWeight,
+		"reputation_weight": c.ReputationWeight,
+		"legal_weight":      c.LegalWeight,
+		"emergency_weight":  c.EmergencyWeight,
+	}
+	sum := 0.0
+	for name, weight := range weights {
+		if weight < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+		sum += weight
+	}
+	if math.Abs(sum-1.0) > 0.001 {
+		return fmt.Errorf("weights must sum to 1.0 (got %.3f)", sum)
+	}
+
+	thresholds := map[string]float64{
+		"min_overall_score":    c.MinOverallScore,
+		"max_bounce_rate":      c.MaxBounceRate,
+		"max_complaint_rate":   c.MaxComplaintRate,
+		"max_unsubscribe_rate": c.MaxUnsubscribeRate,
+		"min_open_rate":        c.MinOpenRate,
+		"min_click_rate":       c.MinClickRate,
+	}
+	for name, value := range thresholds {
+		if value < 0 || value > 100 {
+			return fmt.Errorf("%s must be between 0 and 100", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Context FUB push statuses
+const (
+	FUBPushStatusPushed       = "pushed"
+	FUBPushStatusPendingRetry = "pending_retry"
+	FUBPushStatusFailed       = "failed"  // Gave up after MaxFUBPushRetries
+	FUBPushStatusBlocked      = "blocked" // The lead failed the safety filter; never retried
+)
+
+// MaxFUBPushRetries is how many times a failed push is retried before it is
+// marked failed
+const MaxFUBPushRetries = 5
+
+// ContextFUBPush records pushing a high-intent context trigger to Follow Up
+// Boss as a contact with a follow-up task. A push the FUB API rejected is
+// kept pending retry with its payload so it can be re-sent.
+type ContextFUBPush struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	TriggerID    string     `json:"trigger_id" gorm:"not null;uniqueIndex"`
+	SessionID    string     `json:"session_id" gorm:"index"`
+	Email        string     `json:"email"`
+	Priority     string     `json:"priority" gorm:"size:20"`
+	WorkflowType string     `json:"workflow_type"`
+	Payload      string     `json:"payload" gorm:"type:text"` // JSON trigger request and computed push
+	Status       string     `json:"status" gorm:"size:20;index"`
+	ContactID    string     `json:"contact_id,omitempty"`
+	AutomationID string     `json:"automation_id,omitempty"`
+	RetryCount   int        `json:"retry_count" gorm:"default:0"`
+	NextRetry    *time.Time `json:"next_retry,omitempty" gorm:"index"`
+	LastError    string     `json:"last_error,omitempty" gorm:"type:text"`
+	PushedAt     *time.Time `json:"pushed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
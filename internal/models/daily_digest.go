@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// NotificationEventDailyDigest is the notification preference event type
+// admins opt in to the daily digest email with. Only an explicit preference
+// with Email set opts in; the "*" default does not.
+const NotificationEventDailyDigest = "daily_digest"
+
+// Daily digest delivery statuses
+const (
+	DailyDigestSent   = "sent"
+	DailyDigestFailed = "failed"
+)
+
+// DailyDigestDelivery records the digest sent to one admin on one day, so a
+// restart or a second run never sends the same day's digest twice
+type DailyDigestDelivery struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	AdminUserID string     `json:"admin_user_id" gorm:"not null;uniqueIndex:idx_daily_digest_deliveries_admin_date"`
+	DigestDate  string     `json:"digest_date" gorm:"not null;size:10;uniqueIndex:idx_daily_digest_deliveries_admin_date"` // YYYY-MM-DD in the digest timezone
+	Status      string     `json:"status" gorm:"size:20"`
+	Error       string     `json:"error,omitempty" gorm:"type:text"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
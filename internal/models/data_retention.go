@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Data retention actions
+const (
+	RetentionActionPurgeEvents   = "purge_events"
+	RetentionActionAnonymizeLead = "anonymize_lead"
+)
+
+// LeadStatusAnonymized marks a lead whose personal details the retention job removed
+const LeadStatusAnonymized = "anonymized"
+
+// DataRetentionLog records one action the data retention job took, so purges
+// and anonymizations can be shown to an auditor
+type DataRetentionLog struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	RunID       string    `json:"run_id" gorm:"size:36;index"`
+	Action      string    `json:"action" gorm:"size:30;index"`
+	LeadID      *uint     `json:"lead_id,omitempty" gorm:"index"`
+	RecordCount int64     `json:"record_count"`
+	Cutoff      time.Time `json:"cutoff"` // Records older than this were affected
+	Details     string    `json:"details"`
+	CreatedAt   time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName overrides the table name
+func (DataRetentionLog) TableName() string {
+	return "data_retention_logs"
+}
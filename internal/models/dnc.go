@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// DNC entry kinds
+const (
+	DNCKindPhone = "phone"
+	DNCKindEmail = "email"
+)
+
+// DNCEntry is one phone number or email address on the imported do-not-contact
+// list. Only the blind index hash is stored, so the list can be matched against
+// leads without keeping the plaintext.
+type DNCEntry struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Kind       string    `json:"kind" gorm:"size:10;not null;uniqueIndex:idx_dnc_entries_value"`
+	ValueHash  string    `json:"-" gorm:"size:16;not null;uniqueIndex:idx_dnc_entries_value"`
+	IndexKeyID string    `json:"-" gorm:"size:16"` // Blind index key the hash was computed with
+	Source     string    `json:"source" gorm:"size:100"`
+	ImportedBy string    `json:"imported_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName overrides the table name
+func (DNCEntry) TableName() string {
+	return "dnc_entries"
+}
+
+// DNCSuppressionLog records a contact attempt refused because the recipient is
+// on the do-not-contact list
+type DNCSuppressionLog struct {
+	ID                 uint      `json:"id" gorm:"primaryKey"`
+	Channel            string    `json:"channel" gorm:"size:10;index"`  // email, sms
+	Source             string    `json:"source" gorm:"size:50"`         // campaign_dispatcher, email_batch, sms_service, sms_consent
+	RecipientHash      string    `json:"recipient_hash" gorm:"size:16"` // Blind index of the recipient
+	LeadReengagementID *uint     `json:"lead_reengagement_id,omitempty" gorm:"index"`
+	Reason             string    `json:"reason"`
+	CreatedAt          time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName overrides the table name
+func (DNCSuppressionLog) TableName() string {
+	return "dnc_suppression_logs"
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Drip campaign statuses
+const (
+	DripCampaignActive  = "active"
+	DripCampaignPaused  = "paused"
+	DripCampaignStopped = "stopped"
+)
+
+// Drip enrollment statuses
+const (
+	DripEnrollmentActive    = "active"
+	DripEnrollmentCompleted = "completed"
+	DripEnrollmentStopped   = "stopped"
+)
+
+// DripCampaign is an ordered sequence of campaign templates sent to each
+// enrolled lead, each step waiting DaysDelay days after the previous send
+type DripCampaign struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	Name        string `json:"name" gorm:"not null;index"`
+	Description string `json:"description"`
+	Status      string `json:"status" gorm:"index;default:'active'"` // active, paused, stopped
+	DailyLimit  int    `json:"daily_limit" gorm:"default:0"`         // Max sends per day, 0 = unlimited
+	CreatedBy   string `json:"created_by" gorm:"index"`              // AdminUser ID
+
+	Steps []DripCampaignStep `json:"steps,omitempty" gorm:"foreignKey:DripCampaignID"`
+}
+
+// DripCampaignStep is one email in a drip sequence
+type DripCampaignStep struct {
+	ID             uint `json:"id" gorm:"primaryKey"`
+	DripCampaignID uint `json:"drip_campaign_id" gorm:"not null;index"`
+	Position       int  `json:"position" gorm:"not null"` // 0-based order within the campaign
+
+	CampaignTemplateID uint             `json:"campaign_template_id" gorm:"not null"`
+	CampaignTemplate   CampaignTemplate `json:"campaign_template" gorm:"foreignKey:CampaignTemplateID"`
+
+	DaysDelay int `json:"days_delay" gorm:"default:0"` // Days after the previous step was sent (or enrollment, for the first)
+}
+
+// DripEnrollment tracks a lead's progress through a drip campaign
+type DripEnrollment struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	DripCampaignID     uint             `json:"drip_campaign_id" gorm:"not null;uniqueIndex:idx_drip_enrollment_lead"`
+	LeadReengagementID uint             `json:"lead_reengagement_id" gorm:"not null;uniqueIndex:idx_drip_enrollment_lead"`
+	LeadReengagement   LeadReengagement `json:"-" gorm:"foreignKey:LeadReengagementID"`
+
+	Status             string     `json:"status" gorm:"index;default:'active'"` // active, completed, stopped
+	CurrentStep        int        `json:"current_step" gorm:"default:0"`        // Position of the next step to send
+	NextStepAt         *time.Time `json:"next_step_at,omitempty" gorm:"index"`  // When the next step becomes due
+	PendingExecutionID *uint      `json:"pending_execution_id,omitempty"`       // Execution queued for CurrentStep, not yet sent
+	StoppedReason      string     `json:"stopped_reason,omitempty"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty"`
+}
+
+// TableName overrides the table name
+func (DripEnrollment) TableName() string {
+	return "drip_enrollments"
+}
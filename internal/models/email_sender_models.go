@@ -1,7 +1,11 @@
 package models
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 	"gorm.io/gorm"
 )
@@ -23,6 +27,7 @@ type TrustedEmailSender struct {
 	EmailType       string `json:"email_type" gorm:"not null"` // application_notification, pre_listing_alert, lease_update, vendor_completion, terry_alert
 	ProcessingMode  string `json:"processing_mode" gorm:"default:'automatic'"` // automatic, manual_review, disabled
 	ParsingTemplate string `json:"parsing_template" gorm:"type:text"` // JSON template for extracting data
+	AuthPolicy      string `json:"auth_policy" gorm:"default:'relaxed'"` // none, relaxed, strict: SPF/DKIM checks required for automatic processing
 	
 	// Status and Control
 	IsActive        bool `json:"is_active" gorm:"default:true"`
@@ -107,6 +112,14 @@ type EmailProcessingLog struct {
 	ReviewNotes       string  `json:"review_notes"`
 }
 
+// Sender authentication policies. Relaxed requires DMARC to pass or SPF or DKIM
+// to pass for the From domain; strict requires both SPF and DKIM to pass for it.
+const (
+	SenderAuthPolicyNone    = "none"
+	SenderAuthPolicyRelaxed = "relaxed"
+	SenderAuthPolicyStrict  = "strict"
+)
+
 // Validation methods
 func (tes *TrustedEmailSender) Validate() error {
 	if tes.SenderEmail == "" {
@@ -132,10 +145,211 @@ func (tes *TrustedEmailSender) Validate() error {
 	if !validTypes[tes.EmailType] {
 		return fmt.Errorf("invalid email type: %s", tes.EmailType)
 	}
+
+	switch tes.AuthPolicy {
+	case "", SenderAuthPolicyNone, SenderAuthPolicyRelaxed, SenderAuthPolicyStrict:
+	default:
+		return fmt.Errorf("invalid auth policy: %s", tes.AuthPolicy)
+	}
 	
 	return nil
 }
 
+// ParsingTemplate is the parsed form of a sender's parsing template JSON:
+//
+//	{"subjectPattern": "<regex>", "bodyPatterns": ["<regex>", ...], "fields": ["<name>", ...]}
+type ParsingTemplate struct {
+	SubjectPattern string   `json:"subjectPattern"`
+	BodyPatterns   []string `json:"bodyPatterns"`
+	Fields         []string `json:"fields"`
+}
+
+// ParseParsingTemplate validates parsing template JSON, compiling every pattern,
+// and returns the template with surrounding whitespace trimmed. Errors name the
+// offending key so they can be shown to the user.
+func ParseParsingTemplate(raw string) (*ParsingTemplate, error) {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &keys); err != nil {
+		return nil, fmt.Errorf("template is not a JSON object: %w", err)
+	}
+	for _, key := range []string{"subjectPattern", "bodyPatterns", "fields"} {
+		if _, ok := keys[key]; !ok {
+			return nil, fmt.Errorf("missing required key %q", key)
+		}
+	}
+
+	var template ParsingTemplate
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&template); err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+
+	template.SubjectPattern = strings.TrimSpace(template.SubjectPattern)
+	if template.SubjectPattern == "" {
+		return nil, fmt.Errorf("subjectPattern must not be empty")
+	}
+	if _, err := regexp.Compile(template.SubjectPattern); err != nil {
+		return nil, fmt.Errorf("subjectPattern is not a valid regex: %w", err)
+	}
+
+	if len(template.BodyPatterns) == 0 {
+		return nil, fmt.Errorf("bodyPatterns must contain at least one pattern")
+	}
+	for i, pattern := range template.BodyPatterns {
+		template.BodyPatterns[i] = strings.TrimSpace(pattern)
+		if template.BodyPatterns[i] == "" {
+			return nil, fmt.Errorf("bodyPatterns[%d] must not be empty", i)
+		}
+		if _, err := regexp.Compile(template.BodyPatterns[i]); err != nil {
+			return nil, fmt.Errorf("bodyPatterns[%d] is not a valid regex: %w", i, err)
+		}
+	}
+
+	if len(template.Fields) == 0 {
+		return nil, fmt.Errorf("fields must list at least one field")
+	}
+	seen := make(map[string]bool)
+	for i, field := range template.Fields {
+		template.Fields[i] = strings.TrimSpace(field)
+		if template.Fields[i] == "" {
+			return nil, fmt.Errorf("fields[%d] must not be empty", i)
+		}
+		if seen[template.Fields[i]] {
+			return nil, fmt.Errorf("fields[%d] duplicates %q", i, template.Fields[i])
+		}
+		seen[template.Fields[i]] = true
+	}
+
+	return &template, nil
+}
+
+// String returns the template as normalized JSON
+func (pt *ParsingTemplate) String() string {
+	data, _ := json.Marshal(pt)
+	return string(data)
+}
+
+// ParsingResult is the outcome of running a parsing template against an email
+type ParsingResult struct {
+	ExtractedFields map[string]string  `json:"extracted_fields"`
+	FieldConfidence map[string]float64 `json:"field_confidence"`
+	Confidence      float64            `json:"confidence"`
+	SubjectMatched  *bool              `json:"subject_matched,omitempty"` // nil when no subject was given
+	Warnings        []string           `json:"warnings"`
+	Suggestions     []string           `json:"suggestions"`
+}
+
+// Extract runs the template against an email. A capture group fills the field
+// it is named after ((?P<field>...)); a pattern with a single unnamed group
+// fills the field at the same position in fields. Values that differ between
+// repeated matches are reported as ambiguous and score lower.
+func (pt *ParsingTemplate) Extract(subject, content string) ParsingResult {
+	result := ParsingResult{
+		ExtractedFields: make(map[string]string),
+		FieldConfidence: make(map[string]float64),
+		Warnings:        []string{},
+		Suggestions:     []string{},
+	}
+
+	if subject != "" {
+		matched := regexp.MustCompile(pt.SubjectPattern).MatchString(subject)
+		result.SubjectMatched = &matched
+		if !matched {
+			result.Warnings = append(result.Warnings, "subjectPattern did not match the subject")
+		}
+	}
+
+	declared := make(map[string]bool)
+	for _, field := range pt.Fields {
+		declared[field] = true
+	}
+
+	for i, pattern := range pt.BodyPatterns {
+		re := regexp.MustCompile(pattern)
+		if re.NumSubexp() == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("bodyPatterns[%d] has no capture group, so it cannot extract a field", i))
+			continue
+		}
+
+		matches := re.FindAllStringSubmatch(content, -1)
+		if len(matches) == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("bodyPatterns[%d] matched nothing", i))
+			continue
+		}
+
+		for group, name := range re.SubexpNames() {
+			if group == 0 {
+				continue
+			}
+
+			field, confidence := name, 1.0
+			switch {
+			case name != "" && !declared[name]:
+				result.Warnings = append(result.Warnings, fmt.Sprintf("bodyPatterns[%d] captures %q, which is not listed in fields", i, name))
+				continue
+			case name == "" && re.NumSubexp() == 1 && i < len(pt.Fields):
+				field, confidence = pt.Fields[i], 0.9
+			case name == "":
+				continue
+			}
+
+			values := distinctSubmatches(matches, group)
+			if len(values) == 0 {
+				continue
+			}
+			if len(values) > 1 {
+				confidence *= 0.6
+				result.Warnings = append(result.Warnings, fmt.Sprintf("field %q matched %d different values; using the first", field, len(values)))
+			}
+
+			if existing, ok := result.ExtractedFields[field]; ok {
+				if existing != values[0] {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("field %q was also matched by bodyPatterns[%d] with a different value", field, i))
+				}
+				continue
+			}
+			result.ExtractedFields[field] = values[0]
+			result.FieldConfidence[field] = confidence
+		}
+	}
+
+	total := 0.0
+	for _, field := range pt.Fields {
+		if _, ok := result.ExtractedFields[field]; !ok {
+			result.FieldConfidence[field] = 0
+			result.Warnings = append(result.Warnings, fmt.Sprintf("field %q was not extracted", field))
+		}
+		total += result.FieldConfidence[field]
+	}
+	if len(pt.Fields) > 0 {
+		result.Confidence = total / float64(len(pt.Fields))
+	}
+
+	switch {
+	case len(result.ExtractedFields) == len(pt.Fields) && len(result.Warnings) == 0:
+		result.Suggestions = append(result.Suggestions, "All fields extracted")
+	case len(result.ExtractedFields) < len(pt.Fields):
+		result.Suggestions = append(result.Suggestions, "Name capture groups after their field, e.g. (?P<applicant_name>...), to map them explicitly")
+	}
+	return result
+}
+
+// distinctSubmatches returns the non-empty values of a capture group across
+// matches, in order of first appearance
+func distinctSubmatches(matches [][]string, group int) []string {
+	values := []string{}
+	seen := make(map[string]bool)
+	for _, match := range matches {
+		value := strings.TrimSpace(match[group])
+		if value != "" && !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // Business logic methods
 func (tes *TrustedEmailSender) CanProcessEmail(fromEmail string) bool {
 	return tes.IsActive && tes.IsVerified && tes.SenderEmail == fromEmail
@@ -160,6 +374,42 @@ func (tes *TrustedEmailSender) UpdateLastActivity() {
 	tes.EmailCount++
 }
 
+// FindTrustedSenderIncludingDeleted looks up a sender by email, including soft-deleted
+// rows, since the unique index on sender_email still covers them
+func FindTrustedSenderIncludingDeleted(db *gorm.DB, senderEmail string) (*TrustedEmailSender, error) {
+	var sender TrustedEmailSender
+	if err := db.Unscoped().Where("sender_email = ?", senderEmail).First(&sender).Error; err != nil {
+		return nil, err
+	}
+	return &sender, nil
+}
+
+// RestoreTrustedSender un-deletes a soft-deleted sender and replaces its settings
+// with those of replacement. History such as email counts is kept.
+func RestoreTrustedSender(db *gorm.DB, deleted *TrustedEmailSender, replacement TrustedEmailSender) error {
+	err := db.Unscoped().Model(deleted).Updates(map[string]interface{}{
+		"deleted_at":       nil,
+		"sender_name":      replacement.SenderName,
+		"company_name":     replacement.CompanyName,
+		"contact_person":   replacement.ContactPerson,
+		"email_type":       replacement.EmailType,
+		"processing_mode":  replacement.ProcessingMode,
+		"parsing_template": replacement.ParsingTemplate,
+		"auth_policy":      replacement.AuthPolicy,
+		"is_active":        replacement.IsActive,
+		"is_verified":      replacement.IsVerified,
+		"business_purpose": replacement.BusinessPurpose,
+		"priority":         replacement.Priority,
+		"added_by":         replacement.AddedBy,
+		"notes":            replacement.Notes,
+		"approval_date":    replacement.ApprovalDate,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return db.First(deleted, deleted.ID).Error
+}
+
 // Helper methods for business logic
 func (tes *TrustedEmailSender) IsPreListingSystem() bool {
 	return tes.EmailType == "pre_listing_alert" || tes.EmailType == "broker_alert" || tes.EmailType == "vendor_completion"
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRestoreTrustedSender_RecreateAfterDeleteRestoresRow(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&TrustedEmailSender{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	original := TrustedEmailSender{
+		SenderEmail: "alerts@broker.example.com",
+		SenderName:  "Broker Alerts",
+		EmailType:   "broker_alert",
+		Priority:    "low",
+		IsActive:    true,
+		EmailCount:  12,
+	}
+	if err := db.Create(&original).Error; err != nil {
+		t.Fatalf("Failed to create sender: %v", err)
+	}
+	if err := db.Delete(&original).Error; err != nil {
+		t.Fatalf("Failed to delete sender: %v", err)
+	}
+
+	// A plain lookup misses the soft-deleted row that still holds the unique email
+	if err := db.Where("sender_email = ?", original.SenderEmail).First(&TrustedEmailSender{}).Error; err != gorm.ErrRecordNotFound {
+		t.Fatalf("Expected soft-deleted sender to be hidden, got %v", err)
+	}
+
+	existing, err := FindTrustedSenderIncludingDeleted(db, original.SenderEmail)
+	if err != nil || !existing.DeletedAt.Valid {
+		t.Fatalf("Expected to find the soft-deleted sender, got %+v (%v)", existing, err)
+	}
+
+	replacement := TrustedEmailSender{
+		SenderEmail: original.SenderEmail,
+		SenderName:  "Broker Alerts (new)",
+		EmailType:   "pre_listing_alert",
+		Priority:    "high",
+		IsActive:    false,
+	}
+	if err := RestoreTrustedSender(db, existing, replacement); err != nil {
+		t.Fatalf("Failed to restore sender: %v", err)
+	}
+
+	var restored TrustedEmailSender
+	if err := db.Where("sender_email = ?", original.SenderEmail).First(&restored).Error; err != nil {
+		t.Fatalf("Expected restored sender to be visible again: %v", err)
+	}
+	if restored.ID != original.ID {
+		t.Errorf("Expected the original row %d to be restored, got %d", original.ID, restored.ID)
+	}
+	if restored.SenderName != "Broker Alerts (new)" || restored.EmailType != "pre_listing_alert" || restored.Priority != "high" || restored.IsActive {
+		t.Errorf("Expected restored sender to take the new settings, got %+v", restored)
+	}
+	if restored.EmailCount != 12 {
+		t.Errorf("Expected email history to be kept, got count %d", restored.EmailCount)
+	}
+
+	var count int64
+	db.Unscoped().Model(&TrustedEmailSender{}).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected a single sender row, got %d", count)
+	}
+}
+
+func TestParseParsingTemplate_ReportsSpecificProblems(t *testing.T) {
+	template, err := ParseParsingTemplate(`{"subjectPattern": " New Application: (.+) ", "bodyPatterns": ["Applicant: (\\w+)"], "fields": ["applicant_name"]}`)
+	if err != nil {
+		t.Fatalf("Expected valid template, got %v", err)
+	}
+	if template.SubjectPattern != "New Application: (.+)" {
+		t.Errorf("Expected trimmed subject pattern, got %q", template.SubjectPattern)
+	}
+
+	cases := map[string]string{
+		`not json`: "not a JSON object",
+		`{"bodyPatterns": ["x"], "fields": ["a"]}`:                                    `missing required key "subjectPattern"`,
+		`{"subjectPattern": "x", "bodyPatterns": ["(unclosed"], "fields": ["a"]}`:     "bodyPatterns[0] is not a valid regex",
+		`{"subjectPattern": "[", "bodyPatterns": ["x"], "fields": ["a"]}`:             "subjectPattern is not a valid regex",
+		`{"subjectPattern": "x", "bodyPatterns": [], "fields": ["a"]}`:                "bodyPatterns must contain at least one pattern",
+		`{"subjectPattern": "x", "bodyPatterns": ["x"], "fields": ["a", " a "]}`:      `fields[1] duplicates "a"`,
+		`{"subjectPattern": "x", "bodyPatterns": ["x"], "fields": ["a"], "extra": 1}`: "unknown field",
+	}
+	for raw, want := range cases {
+		if _, err := ParseParsingTemplate(raw); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseParsingTemplate(%s): expected error containing %q, got %v", raw, want, err)
+		}
+	}
+}
+
+func TestParsingTemplateExtract_RunsPatternsAgainstContent(t *testing.T) {
+	template, err := ParseParsingTemplate(`{
+		"subjectPattern": "^New Application",
+		"bodyPatterns": [
+			"Applicant: (?P<applicant_name>[A-Za-z ]+)\\n",
+			"Email: (\\S+@\\S+)",
+			"Property: (?P<property_address>.+)"
+		],
+		"fields": ["applicant_name", "applicant_email", "property_address", "move_in_date"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+
+	content := "Applicant: Jordan Lee\nEmail: jordan@example.com\nProperty: 42 Oak St\nProperty: 7 Elm Ave"
+	result := template.Extract("New Application received", content)
+
+	if result.SubjectMatched == nil || !*result.SubjectMatched {
+		t.Error("Expected subject to match")
+	}
+	want := map[string]string{
+		"applicant_name":   "Jordan Lee",
+		"applicant_email":  "jordan@example.com",
+		"property_address": "42 Oak St",
+	}
+	for field, value := range want {
+		if result.ExtractedFields[field] != value {
+			t.Errorf("Expected %s=%q, got %q", field, value, result.ExtractedFields[field])
+		}
+	}
+
+	if result.FieldConfidence["applicant_name"] != 1.0 || result.FieldConfidence["applicant_email"] != 0.9 {
+		t.Errorf("Expected named and positional confidences of 1.0 and 0.9, got %v", result.FieldConfidence)
+	}
+	if result.FieldConfidence["property_address"] >= 1.0 {
+		t.Error("Expected an ambiguous field to score lower")
+	}
+	if result.FieldConfidence["move_in_date"] != 0 {
+		t.Error("Expected an unmatched field to score 0")
+	}
+
+	warnings := strings.Join(result.Warnings, "\n")
+	if !strings.Contains(warnings, `field "move_in_date" was not extracted`) ||
+		!strings.Contains(warnings, `field "property_address" matched 2 different values`) {
+		t.Errorf("Expected warnings for missing and ambiguous fields, got %v", result.Warnings)
+	}
+}
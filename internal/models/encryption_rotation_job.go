@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Encryption rotation job statuses
+const (
+	RotationStatusRunning   = "running"
+	RotationStatusCompleted = "completed"
+	RotationStatusFailed    = "failed"
+)
+
+// EncryptionRotationJob tracks re-encryption of a table's PII to the current key
+type EncryptionRotationJob struct {
+	ID          string     `gorm:"primaryKey;size:36" json:"id"`
+	TargetTable string     `gorm:"index;not null" json:"table"`
+	TargetKeyID string     `json:"target_key_id"`
+	Status      string     `gorm:"index;default:'running'" json:"status"`
+	Total       int64      `json:"total"`
+	Processed   int64      `json:"processed"`
+	Reencrypted int64      `json:"reencrypted"`
+	Failed      int64      `json:"failed"`
+	Skipped     int64      `json:"skipped"` // Rows changed by someone else mid-rotation, left as written
+	LastID      uint       `json:"last_id"` // Highest row ID processed, for progress and resuming
+	LastError   string     `json:"last_error,omitempty"`
+	RequestedBy string     `json:"requested_by"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (EncryptionRotationJob) TableName() string {
+	return "encryption_rotation_jobs"
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IncomingEmailAttachment is a file received with an IncomingEmail, such as a
+// signed lease or application form. The file content is stored in the row.
+type IncomingEmailAttachment struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	IncomingEmailID uint   `json:"incoming_email_id" gorm:"index;not null"`
+	Filename        string `json:"filename" gorm:"not null"`
+	ContentType     string `json:"content_type" gorm:"not null"`
+	SizeBytes       int    `json:"size_bytes"`
+	SHA256          string `json:"sha256" gorm:"size:64;index"`
+	Data            []byte `json:"-" gorm:"not null"`
+}
+
+// AttachmentsForEmails returns attachment metadata (without file content) for
+// the given incoming emails, keyed by email ID
+func AttachmentsForEmails(db *gorm.DB, emailIDs []uint) (map[uint][]IncomingEmailAttachment, error) {
+	byEmail := make(map[uint][]IncomingEmailAttachment)
+	if len(emailIDs) == 0 {
+		return byEmail, nil
+	}
+
+	var attachments []IncomingEmailAttachment
+	if err := db.Omit("data").Where("incoming_email_id IN ?", emailIDs).Order("id ASC").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	for _, attachment := range attachments {
+		byEmail[attachment.IncomingEmailID] = append(byEmail[attachment.IncomingEmailID], attachment)
+	}
+	return byEmail, nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// IP access lists
+const (
+	IPAccessAllow = "allow"
+	IPAccessDeny  = "deny"
+)
+
+// IP access rule sources
+const (
+	IPAccessSourceManual        = "manual"
+	IPAccessSourceSecurityEvent = "security_event"
+)
+
+// IPAccessRule allows a network to reach admin routes or denies it everywhere
+type IPAccessRule struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	List            string     `json:"list" gorm:"size:10;index"` // allow or deny
+	CIDR            string     `json:"cidr" gorm:"column:cidr;size:64;index"`
+	Reason          string     `json:"reason" gorm:"type:text"`
+	Source          string     `json:"source" gorm:"size:20"`
+	SecurityEventID *uint      `json:"security_event_id,omitempty" gorm:"index"`
+	CreatedByID     string     `json:"created_by_id,omitempty" gorm:"size:64"`
+	CreatedBy       string     `json:"created_by,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty" gorm:"index"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// TableName overrides the table name
+func (IPAccessRule) TableName() string {
+	return "ip_access_rules"
+}
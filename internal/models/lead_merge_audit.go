@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// LeadMergeAudit records a merge of duplicate re-engagement leads into a primary
+// lead, so a mistaken merge can be investigated and undone by hand
+type LeadMergeAudit struct {
+	ID            uint        `gorm:"primaryKey" json:"id"`
+	PrimaryLeadID uint        `gorm:"index;not null" json:"primary_lead_id"`
+	MergedLeadIDs StringArray `gorm:"type:json" json:"merged_lead_ids"`
+	MergedFUBIDs  StringArray `gorm:"column:merged_fub_ids;type:json" json:"merged_fub_ids"` // FUB contact IDs of the merged leads
+	Reassigned    JSONB       `gorm:"type:json" json:"reassigned"`                           // table -> rows moved to the primary
+	CarriedFlags  JSONB       `gorm:"type:json" json:"carried_flags"`                        // opt-out fields copied to the primary from a duplicate
+	ActorID       string      `gorm:"index" json:"actor_id"`
+	ActorName     string      `json:"actor_name"`
+	CreatedAt     time.Time   `gorm:"index" json:"created_at"`
+}
+
+// TableName overrides the table name
+func (LeadMergeAudit) TableName() string {
+	return "lead_merge_audits"
+}
@@ -60,6 +60,9 @@ type LeadReengagement struct {
 	FirstName    security.EncryptedString `json:"first_name"`
 	LastName     security.EncryptedString `json:"last_name"`
 
+	// Ownership
+	OwnerID string `json:"owner_id" gorm:"index;size:36"` // AdminUser ID of the owning agent
+
 	// Segmentation Data
 	Segment       LeadSegment   `json:"segment" gorm:"index;not null"`
 	RiskLevel     RiskLevel     `json:"risk_level" gorm:"index;not null"`
@@ -101,6 +104,102 @@ type LeadReengagement struct {
 	// Notes and Tags
 	Notes string `json:"notes"`
 	Tags  string `json:"tags"` // JSON array of tags
+
+	// Blind indexes (keyed hashes for querying encrypted PII)
+	EmailHash       string `json:"-" gorm:"index;size:16"`
+	PhoneHash       string `json:"-" gorm:"index;size:16"`
+	SearchIndex     string `json:"-" gorm:"type:text"` // Name/email/phone trigram hashes
+	BlindIndexKeyID string `json:"-" gorm:"size:16"`   // Index key the hashes were computed with
+}
+
+// IsTeamAdminRole reports whether a role may see leads owned by every agent
+func IsTeamAdminRole(role string) bool {
+	return role == RoleMainAdmin || role == RoleSuperAdmin
+}
+
+// LeadsOwnedBy scopes LeadReengagement queries to a single owning agent
+func LeadsOwnedBy(ownerID string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("owner_id = ?", ownerID)
+	}
+}
+
+// LeadsVisibleTo scopes LeadReengagement queries to the leads an admin may see.
+// Team admins see every lead; other agents only see leads they own.
+func LeadsVisibleTo(admin *AdminUser) func(db *gorm.DB) *gorm.DB {
+	if IsTeamAdminRole(admin.Role) {
+		return func(db *gorm.DB) *gorm.DB {
+			return db
+		}
+	}
+	return LeadsOwnedBy(admin.ID)
+}
+
+// SetBlindIndexes computes the lookup and search hashes from the lead's plaintext PII
+func (l *LeadReengagement) SetBlindIndexes(encryptionManager *security.EncryptionManager, firstName, lastName, email, phone string) {
+	l.EmailHash = encryptionManager.EmailBlindIndex(email)
+	l.PhoneHash = encryptionManager.PhoneBlindIndex(phone)
+	l.SearchIndex = encryptionManager.SearchIndex(firstName, lastName, email, phone)
+	l.BlindIndexKeyID = encryptionManager.IndexKeyID()
+}
+
+// LeadsWithEmail scopes LeadReengagement queries to leads with the given email
+func LeadsWithEmail(encryptionManager *security.EncryptionManager, email string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("email_hash = ?", encryptionManager.EmailBlindIndex(email))
+	}
+}
+
+// LeadsWithPhone scopes LeadReengagement queries to leads with the given phone number
+func LeadsWithPhone(encryptionManager *security.EncryptionManager, phone string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("phone_hash = ?", encryptionManager.PhoneBlindIndex(phone))
+	}
+}
+
+// LeadsMatchingSearch scopes LeadReengagement queries to leads whose search index
+// contains every given blind index hash
+func LeadsMatchingSearch(hashes []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, hash := range hashes {
+			db = db.Where("search_index LIKE ?", "%"+hash+"%")
+		}
+		return db
+	}
+}
+
+// BackfillLeadBlindIndexes recomputes blind indexes for leads that were never indexed
+// or were indexed with a different index key, decrypting their stored PII
+func BackfillLeadBlindIndexes(db *gorm.DB, encryptionManager *security.EncryptionManager) (int, error) {
+	keyID := encryptionManager.IndexKeyID()
+	updated := 0
+	var leads []LeadReengagement
+	result := db.Unscoped().
+		Where("blind_index_key_id IS NULL OR blind_index_key_id <> ?", keyID).
+		FindInBatches(&leads, 200, func(tx *gorm.DB, batch int) error {
+			for _, lead := range leads {
+				// Fields that fail to decrypt are indexed as empty rather than masked
+				plaintext := make([]string, 4)
+				for i, field := range []security.EncryptedString{lead.FirstName, lead.LastName, lead.Email, lead.Phone} {
+					if value, err := encryptionManager.Decrypt(field); err == nil {
+						plaintext[i] = value
+					}
+				}
+				lead.SetBlindIndexes(encryptionManager, plaintext[0], plaintext[1], plaintext[2], plaintext[3])
+
+				if err := tx.Unscoped().Model(&LeadReengagement{}).Where("id = ?", lead.ID).Updates(map[string]interface{}{
+					"email_hash":         lead.EmailHash,
+					"phone_hash":         lead.PhoneHash,
+					"search_index":       lead.SearchIndex,
+					"blind_index_key_id": lead.BlindIndexKeyID,
+				}).Error; err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		})
+	return updated, result.Error
 }
 
 // CampaignTemplate represents email templates for re-engagement
@@ -117,7 +216,8 @@ type CampaignTemplate struct {
 	DaysDelay   int    `json:"days_delay" gorm:"default:0"` // Days after previous email
 
 	// Template Variables
-	Variables string `json:"variables"` // JSON array of available variables
+	Variables       string `json:"variables"`                                   // JSON array of available variables
+	CustomVariables JSONB  `json:"custom_variables,omitempty" gorm:"type:json"` // Custom key -> value rendered into every email
 
 	// Performance Tracking
 	TimesSent    int     `json:"times_sent" gorm:"default:0"`
@@ -126,10 +226,21 @@ type CampaignTemplate struct {
 	ResponseRate float64 `json:"response_rate" gorm:"default:0"`
 }
 
+// CustomValues returns the template's custom variables that have string values
+func (t CampaignTemplate) CustomValues() map[string]string {
+	values := make(map[string]string, len(t.CustomVariables))
+	for key, value := range t.CustomVariables {
+		if text, ok := value.(string); ok {
+			values[key] = text
+		}
+	}
+	return values
+}
+
 // CampaignExecution represents the execution log of re-engagement campaigns
 type CampaignExecution struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
-	CreatedAt time.Time      `json:"created_at"`
+	CreatedAt time.Time      `json:"created_at" gorm:"index:idx_campaign_executions_status_created,priority:2"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 
@@ -139,10 +250,18 @@ type CampaignExecution struct {
 	CampaignTemplateID uint             `json:"campaign_template_id" gorm:"not null"`
 	CampaignTemplate   CampaignTemplate `json:"campaign_template" gorm:"foreignKey:CampaignTemplateID"`
 
+	// Campaign Grouping
+	CampaignName string `json:"campaign_name" gorm:"index"`
+	DailyLimit   int    `json:"daily_limit" gorm:"default:0"` // Max sends per day for this campaign, 0 = unlimited
+
+	// Drip Campaigns
+	DripEnrollmentID *uint `json:"drip_enrollment_id,omitempty" gorm:"index"`
+	DripStep         int   `json:"drip_step" gorm:"default:0"` // Step position within the drip campaign
+
 	// Execution Details
 	ScheduledFor time.Time  `json:"scheduled_for"`
 	ExecutedAt   *time.Time `json:"executed_at,omitempty"`
-	Status       string     `json:"status" gorm:"default:'scheduled'"` // scheduled, sent, failed, skipped
+	Status       string     `json:"status" gorm:"default:'scheduled';index:idx_campaign_executions_status_created,priority:1"` // scheduled, sent, failed, skipped
 
 	// FUB Integration
 	FUBActionPlanID string `json:"fub_action_plan_id"`
@@ -155,11 +274,21 @@ type CampaignExecution struct {
 	ResponseType string `json:"response_type"` // "opt_in", "opt_out", "inquiry", "complaint"
 
 	// Error Handling
-	ErrorMessage string     `json:"error_message"`
-	RetryCount   int        `json:"retry_count" gorm:"default:0"`
-	NextRetry    *time.Time `json:"next_retry,omitempty"`
+	ErrorMessage  string     `json:"error_message"`
+	FailureReason string     `json:"failure_reason,omitempty" gorm:"size:50;index"` // recipient_unavailable, render_failure, queue_error, smtp_error
+	RetryCount    int        `json:"retry_count" gorm:"default:0"`                  // Failed send attempts
+	NextRetry     *time.Time `json:"next_retry,omitempty"`
+	FailedAt      *time.Time `json:"failed_at,omitempty"`
 }
 
+// Campaign execution failure reasons
+const (
+	CampaignFailureRecipientUnavailable = "recipient_unavailable"
+	CampaignFailureRenderFailure        = "render_failure"
+	CampaignFailureQueueError           = "queue_error"
+	CampaignFailureSMTPError            = "smtp_error"
+)
+
 // ReengagementMetrics represents campaign performance metrics
 type ReengagementMetrics struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
@@ -265,31 +394,95 @@ func (lr *LeadReengagement) GetNextEmailTemplate() int {
 	}
 }
 
-// CalculateSegment determines the appropriate segment for a lead
+// ReengagementThresholds configures the day boundaries and risk weights used
+// to segment leads and assess contact risk
+type ReengagementThresholds struct {
+	ActiveDays        int // Activity within this many days is "active"
+	DormantDays       int // Activity within this many days is "dormant"
+	StaleActivityDays int // Activity older than this adds stale-activity risk
+
+	HighRiskScore   int // Risk score at or above this is high risk
+	MediumRiskScore int // Risk score at or above this is medium risk
+}
+
+// DefaultReengagementThresholds are the thresholds used by CalculateSegment and CalculateRiskLevel
+var DefaultReengagementThresholds = ReengagementThresholds{
+	ActiveDays:        365,  // 12 months
+	DormantDays:       1825, // 5 years
+	StaleActivityDays: 1095, // 3 years
+	HighRiskScore:     8,
+	MediumRiskScore:   3,
+}
+
+// Risk weights applied by CalculateRiskLevel
+const (
+	riskWeightNoEmail        = 10
+	riskWeightInvalidEmail   = 8
+	riskWeightHardBounce     = 10
+	riskWeightUnsubscribed   = 10
+	riskWeightDNC            = 10
+	riskWeightConsentRevoked = 10
+	riskWeightConsentUnknown = 3
+	riskWeightStaleActivity  = 2
+)
+
+// LastEngagement returns the most recent known interaction with the lead,
+// considering recorded activity, email engagement, responses, and opt-ins
+func (lr *LeadReengagement) LastEngagement() *time.Time {
+	var latest *time.Time
+	consider := func(t *time.Time) {
+		if t != nil && (latest == nil || t.After(*latest)) {
+			latest = t
+		}
+	}
+
+	consider(lr.LastActivity)
+	consider(lr.ResponseDate)
+	consider(lr.OptInDate)
+
+	// An open or click means the lead engaged with the last email we sent
+	if lr.EmailsOpened > 0 || lr.EmailsClicked > 0 {
+		consider(lr.LastEmailSent)
+	}
+
+	return latest
+}
+
+// IsSuppressed reports whether the lead must never be contacted
+func (lr *LeadReengagement) IsSuppressed() bool {
+	return !lr.HasEmail || lr.HardBounce || lr.PreviousUnsubscribe || lr.OnDNCList || lr.ConsentStatus == ConsentRevoked
+}
+
+// CalculateSegment determines the appropriate segment for a lead using the default thresholds
 func (lr *LeadReengagement) CalculateSegment() LeadSegment {
+	return lr.CalculateSegmentWithThresholds(DefaultReengagementThresholds)
+}
+
+// CalculateSegmentWithThresholds determines the segment for a lead using the given thresholds
+func (lr *LeadReengagement) CalculateSegmentWithThresholds(thresholds ReengagementThresholds) LeadSegment {
 	now := time.Now()
 
 	// Check for suppression conditions first
-	if !lr.HasEmail || lr.HardBounce || lr.PreviousUnsubscribe || lr.OnDNCList {
+	if lr.IsSuppressed() {
 		return SegmentSuppressed
 	}
 
-	// Determine segment based on last activity
-	if lr.LastActivity != nil {
-		daysSinceActivity := int(now.Sub(*lr.LastActivity).Hours() / 24)
+	// Determine segment based on most recent engagement
+	if lastEngagement := lr.LastEngagement(); lastEngagement != nil {
+		daysSinceActivity := int(now.Sub(*lastEngagement).Hours() / 24)
 
-		if daysSinceActivity <= 365 {
+		if daysSinceActivity <= thresholds.ActiveDays {
 			return SegmentActive
-		} else if daysSinceActivity <= 1825 { // 5 years
+		} else if daysSinceActivity <= thresholds.DormantDays {
 			return SegmentDormant
 		}
 	}
 
-	// If no last activity or very old, check first contact
+	// If no engagement or very old, check first contact
 	if lr.FirstContact != nil {
 		daysSinceFirst := int(now.Sub(*lr.FirstContact).Hours() / 24)
 
-		if daysSinceFirst <= 1825 { // 5 years
+		if daysSinceFirst <= thresholds.DormantDays {
 			return SegmentDormant
 		}
 	}
@@ -298,42 +491,51 @@ func (lr *LeadReengagement) CalculateSegment() LeadSegment {
 	return SegmentUnknown
 }
 
-// CalculateRiskLevel assesses the risk of contacting this lead
+// CalculateRiskLevel assesses the risk of contacting this lead using the default thresholds
 func (lr *LeadReengagement) CalculateRiskLevel() RiskLevel {
+	return lr.CalculateRiskLevelWithThresholds(DefaultReengagementThresholds)
+}
+
+// CalculateRiskLevelWithThresholds assesses contact risk from bounce, unsubscribe,
+// consent, and recency data using the given thresholds
+func (lr *LeadReengagement) CalculateRiskLevelWithThresholds(thresholds ReengagementThresholds) RiskLevel {
 	riskFactors := 0
 
 	// High risk factors
 	if !lr.HasEmail {
-		riskFactors += 10
+		riskFactors += riskWeightNoEmail
 	}
 	if !lr.EmailValid {
-		riskFactors += 8
+		riskFactors += riskWeightInvalidEmail
 	}
 	if lr.HardBounce {
-		riskFactors += 10
+		riskFactors += riskWeightHardBounce
 	}
 	if lr.PreviousUnsubscribe {
-		riskFactors += 10
+		riskFactors += riskWeightUnsubscribed
 	}
 	if lr.OnDNCList {
-		riskFactors += 10
+		riskFactors += riskWeightDNC
+	}
+	if lr.ConsentStatus == ConsentRevoked {
+		riskFactors += riskWeightConsentRevoked
 	}
 
 	// Medium risk factors
-	if lr.ConsentStatus == ConsentUnknown {
-		riskFactors += 3
+	if (lr.ConsentStatus == ConsentUnknown || lr.ConsentStatus == "") && !lr.OptedIn {
+		riskFactors += riskWeightConsentUnknown
 	}
-	if lr.LastActivity != nil {
-		daysSinceActivity := int(time.Now().Sub(*lr.LastActivity).Hours() / 24)
-		if daysSinceActivity > 1095 { // 3 years
-			riskFactors += 2
+	if lastEngagement := lr.LastEngagement(); lastEngagement != nil {
+		daysSinceActivity := int(time.Since(*lastEngagement).Hours() / 24)
+		if daysSinceActivity > thresholds.StaleActivityDays {
+			riskFactors += riskWeightStaleActivity
 		}
 	}
 
 	// Determine risk level
-	if riskFactors >= 8 {
+	if riskFactors >= thresholds.HighRiskScore {
 		return RiskHigh
-	} else if riskFactors >= 3 {
+	} else if riskFactors >= thresholds.MediumRiskScore {
 		return RiskMedium
 	}
 
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"time"
+
+	"chrisgross-ctrl-project/internal/security"
+)
+
+// LeadReengagementResponse represents a re-engagement lead with decrypted PII fields
+type LeadReengagementResponse struct {
+	ID           uint      `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	FUBContactID string    `json:"fub_contact_id"`
+	OwnerID      string    `json:"owner_id"`
+	Email        string    `json:"email"`      // Decrypted
+	Phone        string    `json:"phone"`      // Decrypted
+	FirstName    string    `json:"first_name"` // Decrypted
+	LastName     string    `json:"last_name"`  // Decrypted
+
+	Segment       LeadSegment   `json:"segment"`
+	RiskLevel     RiskLevel     `json:"risk_level"`
+	ConsentStatus ConsentStatus `json:"consent_status"`
+
+	LastActivity   *time.Time `json:"last_activity,omitempty"`
+	FirstContact   *time.Time `json:"first_contact,omitempty"`
+	SourceCampaign string     `json:"source_campaign"`
+	OriginalSource string     `json:"original_source"`
+
+	HasEmail            bool `json:"has_email"`
+	EmailValid          bool `json:"email_valid"`
+	HardBounce          bool `json:"hard_bounce"`
+	PreviousUnsubscribe bool `json:"previous_unsubscribe"`
+	OnDNCList           bool `json:"on_dnc_list"`
+
+	CampaignStatus    CampaignStatus `json:"campaign_status"`
+	CampaignStarted   *time.Time     `json:"campaign_started,omitempty"`
+	CampaignCompleted *time.Time     `json:"campaign_completed,omitempty"`
+	EmailsSent        int            `json:"emails_sent"`
+	LastEmailSent     *time.Time     `json:"last_email_sent,omitempty"`
+
+	EmailsOpened  int        `json:"emails_opened"`
+	EmailsClicked int        `json:"emails_clicked"`
+	Responded     bool       `json:"responded"`
+	ResponseDate  *time.Time `json:"response_date,omitempty"`
+	OptedIn       bool       `json:"opted_in"`
+	OptInDate     *time.Time `json:"opt_in_date,omitempty"`
+
+	ConsentDocumented bool       `json:"consent_documented"`
+	ConsentDate       *time.Time `json:"consent_date,omitempty"`
+	ConsentMethod     string     `json:"consent_method"`
+
+	Notes string `json:"notes"`
+	Tags  string `json:"tags"`
+}
+
+// DecryptLeadReengagement converts a LeadReengagement to a response with decrypted PII.
+// Legacy plaintext values pass through unchanged; values that fail to decrypt are masked.
+func DecryptLeadReengagement(lead LeadReengagement, encryptionManager *security.EncryptionManager) LeadReengagementResponse {
+	return LeadReengagementResponse{
+		ID:                  lead.ID,
+		CreatedAt:           lead.CreatedAt,
+		UpdatedAt:           lead.UpdatedAt,
+		FUBContactID:        lead.FUBContactID,
+		OwnerID:             lead.OwnerID,
+		Email:               decryptLeadField(lead.Email, encryptionManager),
+		Phone:               decryptLeadField(lead.Phone, encryptionManager),
+		FirstName:           decryptLeadField(lead.FirstName, encryptionManager),
+		LastName:            decryptLeadField(lead.LastName, encryptionManager),
+		Segment:             lead.Segment,
+		RiskLevel:           lead.RiskLevel,
+		ConsentStatus:       lead.ConsentStatus,
+		LastActivity:        lead.LastActivity,
+		FirstContact:        lead.FirstContact,
+		SourceCampaign:      lead.SourceCampaign,
+		OriginalSource:      lead.OriginalSource,
+		HasEmail:            lead.HasEmail,
+		EmailValid:          lead.EmailValid,
+		HardBounce:          lead.HardBounce,
+		PreviousUnsubscribe: lead.PreviousUnsubscribe,
+		OnDNCList:           lead.OnDNCList,
+		CampaignStatus:      lead.CampaignStatus,
+		CampaignStarted:     lead.CampaignStarted,
+		CampaignCompleted:   lead.CampaignCompleted,
+		EmailsSent:          lead.EmailsSent,
+		LastEmailSent:       lead.LastEmailSent,
+		EmailsOpened:        lead.EmailsOpened,
+		EmailsClicked:       lead.EmailsClicked,
+		Responded:           lead.Responded,
+		ResponseDate:        lead.ResponseDate,
+		OptedIn:             lead.OptedIn,
+		OptInDate:           lead.OptInDate,
+		ConsentDocumented:   lead.ConsentDocumented,
+		ConsentDate:         lead.ConsentDate,
+		ConsentMethod:       lead.ConsentMethod,
+		Notes:               lead.Notes,
+		Tags:                lead.Tags,
+	}
+}
+
+// DecryptLeadReengagementList converts a slice of LeadReengagements to decrypted responses
+func DecryptLeadReengagementList(leads []LeadReengagement, encryptionManager *security.EncryptionManager) []LeadReengagementResponse {
+	responses := make([]LeadReengagementResponse, len(leads))
+	for i, lead := range leads {
+		responses[i] = DecryptLeadReengagement(lead, encryptionManager)
+	}
+	return responses
+}
+
+// decryptLeadField decrypts a single PII field, masking it if decryption fails
+func decryptLeadField(value security.EncryptedString, encryptionManager *security.EncryptionManager) string {
+	if encryptionManager == nil {
+		return value.String()
+	}
+
+	decrypted, err := encryptionManager.Decrypt(value)
+	if err != nil {
+		return value.String()
+	}
+
+	return decrypted
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupLeadReengagementTestDB(t *testing.T) (*gorm.DB, *security.EncryptionManager) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&security.EncryptionKey{}, &LeadReengagement{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("Failed to generate test key: %v", err)
+	}
+	t.Setenv("ENCRYPTION_KEY", base64.StdEncoding.EncodeToString(key))
+
+	em, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create encryption manager: %v", err)
+	}
+
+	return db, em
+}
+
+func TestDecryptLeadReengagement_RoundTrip(t *testing.T) {
+	db, em := setupLeadReengagementTestDB(t)
+
+	email, _ := em.EncryptEmail("jane@example.com")
+	firstName, _ := em.Encrypt("Jane")
+	lastName, _ := em.Encrypt("Doe")
+
+	lead := LeadReengagement{
+		FUBContactID:  "fub-123",
+		Email:         email,
+		FirstName:     firstName,
+		LastName:      lastName,
+		Segment:       SegmentActive,
+		RiskLevel:     RiskLow,
+		ConsentStatus: ConsentUnknown,
+	}
+	if err := db.Create(&lead).Error; err != nil {
+		t.Fatalf("Failed to store lead: %v", err)
+	}
+
+	var stored LeadReengagement
+	if err := db.First(&stored, lead.ID).Error; err != nil {
+		t.Fatalf("Failed to fetch lead: %v", err)
+	}
+
+	if string(stored.Email) == "jane@example.com" {
+		t.Error("Expected email to be stored encrypted")
+	}
+
+	response := DecryptLeadReengagement(stored, em)
+
+	if response.Email != "jane@example.com" {
+		t.Errorf("Expected decrypted email 'jane@example.com', got '%s'", response.Email)
+	}
+	if response.FirstName != "Jane" || response.LastName != "Doe" {
+		t.Errorf("Expected decrypted name 'Jane Doe', got '%s %s'", response.FirstName, response.LastName)
+	}
+	if response.FUBContactID != "fub-123" {
+		t.Errorf("Expected FUB contact ID 'fub-123', got '%s'", response.FUBContactID)
+	}
+}
+
+func TestDecryptLeadReengagement_LegacyPlaintext(t *testing.T) {
+	_, em := setupLeadReengagementTestDB(t)
+
+	lead := LeadReengagement{
+		FUBContactID: "fub-legacy",
+		Email:        security.EncryptedString("legacy@example.com"),
+		FirstName:    security.EncryptedString("Legacy"),
+	}
+
+	response := DecryptLeadReengagement(lead, em)
+
+	if response.Email != "legacy@example.com" {
+		t.Errorf("Expected legacy email to pass through, got '%s'", response.Email)
+	}
+	if response.FirstName != "Legacy" {
+		t.Errorf("Expected legacy first name to pass through, got '%s'", response.FirstName)
+	}
+}
+
+func TestDecryptLeadReengagement_UndecryptableMasked(t *testing.T) {
+	_, em := setupLeadReengagementTestDB(t)
+
+	lead := LeadReengagement{
+		Email: security.EncryptedString(`{"key_id":"other","nonce":"","ciphertext":""}`),
+	}
+
+	response := DecryptLeadReengagement(lead, em)
+
+	if response.Email != "[ENCRYPTED]" {
+		t.Errorf("Expected undecryptable email to be masked, got '%s'", response.Email)
+	}
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestLeadsVisibleTo_AgentCannotFetchAnotherAgentsLead(t *testing.T) {
+	db, _ := setupLeadReengagementTestDB(t)
+
+	agentA := &AdminUser{ID: "agent-a", Role: RoleAdmin}
+	agentB := &AdminUser{ID: "agent-b", Role: RoleAdmin}
+	teamAdmin := &AdminUser{ID: "team-admin", Role: RoleMainAdmin}
+
+	lead := LeadReengagement{
+		FUBContactID:  "fub-owned-by-a",
+		OwnerID:       agentA.ID,
+		Segment:       SegmentActive,
+		RiskLevel:     RiskLow,
+		ConsentStatus: ConsentUnknown,
+	}
+	if err := db.Create(&lead).Error; err != nil {
+		t.Fatalf("Failed to store lead: %v", err)
+	}
+
+	var fetched LeadReengagement
+	if err := db.Scopes(LeadsVisibleTo(agentA)).First(&fetched, "id = ?", lead.ID).Error; err != nil {
+		t.Fatalf("Expected owner to fetch their lead, got %v", err)
+	}
+
+	err := db.Scopes(LeadsVisibleTo(agentB)).First(&LeadReengagement{}, "id = ?", lead.ID).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected another agent's lookup to return not found, got %v", err)
+	}
+
+	if err := db.Scopes(LeadsVisibleTo(teamAdmin)).First(&LeadReengagement{}, "id = ?", lead.ID).Error; err != nil {
+		t.Errorf("Expected team admin to see every lead, got %v", err)
+	}
+}
+
+func TestLeadsMatchingSearch_FindsPartialTermsViaBlindIndex(t *testing.T) {
+	db, em := setupLeadReengagementTestDB(t)
+
+	jane := LeadReengagement{FUBContactID: "fub-jane", Segment: SegmentActive, RiskLevel: RiskLow, ConsentStatus: ConsentUnknown}
+	jane.SetBlindIndexes(em, "Jane", "Doe", "jane.doe@example.com", "(555) 123-4567")
+	john := LeadReengagement{FUBContactID: "fub-john", Segment: SegmentActive, RiskLevel: RiskLow, ConsentStatus: ConsentUnknown}
+	john.SetBlindIndexes(em, "John", "Smith", "jsmith@example.com", "555-987-0000")
+	for _, lead := range []*LeadReengagement{&jane, &john} {
+		if err := db.Create(lead).Error; err != nil {
+			t.Fatalf("Failed to store lead: %v", err)
+		}
+	}
+
+	if strings.Contains(jane.SearchIndex, "jan") {
+		t.Fatalf("Search index must not contain plaintext: %q", jane.SearchIndex)
+	}
+
+	cases := map[string][]string{
+		"jan":      {"fub-jane"},
+		"DOE":      {"fub-jane"},
+		"smith":    {"fub-john"},
+		"5551234":  {"fub-jane"},
+		"example":  {"fub-jane", "fub-john"},
+		"jane smi": {},
+	}
+	for query, expected := range cases {
+		var found []LeadReengagement
+		if err := db.Scopes(LeadsMatchingSearch(em.SearchQueryHashes(query))).Order("id").Find(&found).Error; err != nil {
+			t.Fatalf("Search %q failed: %v", query, err)
+		}
+		if len(found) != len(expected) {
+			t.Errorf("Search %q: expected %d leads, got %d", query, len(expected), len(found))
+			continue
+		}
+		for i, lead := range found {
+			if lead.FUBContactID != expected[i] {
+				t.Errorf("Search %q: expected %s, got %s", query, expected[i], lead.FUBContactID)
+			}
+		}
+	}
+}
+
+func TestBackfillLeadBlindIndexes_IndexesLegacyRowsForEmailLookup(t *testing.T) {
+	db, em := setupLeadReengagementTestDB(t)
+
+	email, _ := em.EncryptEmail("Legacy.Lead@Example.com")
+	phone, _ := em.EncryptPhone("+1 (555) 222-3333")
+	legacy := LeadReengagement{FUBContactID: "fub-legacy", Email: email, Phone: phone, Segment: SegmentActive, RiskLevel: RiskLow, ConsentStatus: ConsentUnknown}
+	if err := db.Create(&legacy).Error; err != nil {
+		t.Fatalf("Failed to store lead: %v", err)
+	}
+
+	updated, err := BackfillLeadBlindIndexes(db, em)
+	if err != nil || updated != 1 {
+		t.Fatalf("Expected 1 lead backfilled, got %d (%v)", updated, err)
+	}
+
+	var found LeadReengagement
+	if err := db.Scopes(LeadsWithEmail(em, "legacy.lead@example.com")).First(&found).Error; err != nil {
+		t.Fatalf("Expected case-insensitive email lookup to find lead: %v", err)
+	}
+	if err := db.Scopes(LeadsWithPhone(em, "555-222-3333")).First(&found).Error; err != nil {
+		t.Fatalf("Expected phone lookup to ignore formatting and country code: %v", err)
+	}
+
+	if updated, _ := BackfillLeadBlindIndexes(db, em); updated != 0 {
+		t.Errorf("Expected already-indexed leads to be skipped, got %d updated", updated)
+	}
+}
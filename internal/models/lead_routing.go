@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LeadRoutingConfigID is the primary key of the single lead routing config row
+const LeadRoutingConfigID = 1
+
+// Lead routing strategies
+const (
+	LeadRoutingRoundRobin = "round_robin" // Agent with the fewest open leads, longest since last assigned
+	LeadRoutingWeighted   = "weighted"    // Open leads relative to each agent's weight
+	LeadRoutingTerritory  = "territory"   // Agent covering the lead's city or zip code, else round robin
+)
+
+// LeadRoutingManual marks an assignment an admin made by hand
+const LeadRoutingManual = "manual"
+
+// LeadRoutingConfig chooses how new leads are assigned to agents
+type LeadRoutingConfig struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	Strategy  string    `json:"strategy" gorm:"size:20"`
+	AutoRoute bool      `json:"auto_route"` // Route leads as they are created
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultLeadRoutingConfig round-robins new leads as they are created
+func DefaultLeadRoutingConfig() LeadRoutingConfig {
+	return LeadRoutingConfig{ID: LeadRoutingConfigID, Strategy: LeadRoutingRoundRobin, AutoRoute: true}
+}
+
+// Validate checks the strategy is one the router knows
+func (c LeadRoutingConfig) Validate() error {
+	switch c.Strategy {
+	case LeadRoutingRoundRobin, LeadRoutingWeighted, LeadRoutingTerritory:
+		return nil
+	}
+	return fmt.Errorf("strategy must be one of %s, %s or %s", LeadRoutingRoundRobin, LeadRoutingWeighted, LeadRoutingTerritory)
+}
+
+// LoadLeadRoutingConfig returns the saved routing config, or the defaults if none is saved
+func LoadLeadRoutingConfig(db *gorm.DB) (LeadRoutingConfig, error) {
+	config := DefaultLeadRoutingConfig()
+	if err := db.First(&config, LeadRoutingConfigID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return DefaultLeadRoutingConfig(), nil
+		}
+		return DefaultLeadRoutingConfig(), err
+	}
+	return config, nil
+}
+
+// AgentRoutingProfile holds an agent's routing settings. Active admin users
+// without a profile take part in routing with the defaults.
+type AgentRoutingProfile struct {
+	AgentID       string    `json:"agent_id" gorm:"primaryKey"` // AdminUser.ID
+	Accepting     bool      `json:"accepting" gorm:"default:true"`
+	Weight        int       `json:"weight" gorm:"default:1"`
+	Territories   string    `json:"territories" gorm:"type:text"` // Comma-separated cities and zip codes
+	MaxDailyLeads int       `json:"max_daily_leads"`              // 0 means no limit
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TerritoryList returns the profile's territories, trimmed and lowercased
+func (p AgentRoutingProfile) TerritoryList() []string {
+	var territories []string
+	for _, territory := range strings.Split(p.Territories, ",") {
+		if territory = strings.ToLower(strings.TrimSpace(territory)); territory != "" {
+			territories = append(territories, territory)
+		}
+	}
+	return territories
+}
+
+// LeadAssignment records a lead being assigned to an agent, by the router or by hand
+type LeadAssignment struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	LeadID          uint      `json:"lead_id" gorm:"index;not null"`
+	AgentID         string    `json:"agent_id" gorm:"index;not null"`
+	PreviousAgentID string    `json:"previous_agent_id,omitempty"`
+	Strategy        string    `json:"strategy" gorm:"size:20"` // A routing strategy or "manual"
+	Reason          string    `json:"reason"`
+	AssignedBy      string    `json:"assigned_by,omitempty"` // Admin user ID; empty when auto-routed
+	CreatedAt       time.Time `json:"created_at" gorm:"index"`
+}
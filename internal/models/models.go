@@ -1,6 +1,7 @@
 package models
 
 import (
+	"chrisgross-ctrl-project/internal/addressutil"
 	"chrisgross-ctrl-project/internal/security"
 	"database/sql/driver"
 	"encoding/json"
@@ -426,6 +427,8 @@ type Lead struct {
 	Tags            StringArray `json:"tags" gorm:"type:json"`
 	CustomFields    JSONB       `json:"custom_fields" gorm:"type:json"`
 
+	OptimisticLock
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -618,16 +621,25 @@ type PropertyConflict struct {
 
 // Approval represents an approval request in the system
 type Approval struct {
-	ID              uint        `json:"id" gorm:"primaryKey"`
-	ApprovalType    string      `json:"approval_type" gorm:"not null"`    // rental_application, property_listing, document_verification, showing_request
-	Status          string      `json:"status" gorm:"default:'pending'"`  // pending, approved, rejected, under_review
-	Priority        string      `json:"priority" gorm:"default:'medium'"` // low, medium, high
-	ApplicantName   string      `json:"applicant_name"`
-	PropertyAddress string      `json:"property_address"`
-	Documents       StringArray `json:"documents" gorm:"type:json"`
-	Notes           string      `json:"notes" gorm:"type:text"`
-	CreatedAt       time.Time   `json:"created_at"`
-	UpdatedAt       time.Time   `json:"updated_at"`
+	ID                        uint        `json:"id" gorm:"primaryKey"`
+	ApprovalType              string      `json:"approval_type" gorm:"not null"`    // rental_application, property_listing, document_verification, showing_request
+	Status                    string      `json:"status" gorm:"default:'pending'"`  // pending, approved, rejected, under_review
+	Priority                  string      `json:"priority" gorm:"default:'medium'"` // low, medium, high
+	ApplicantName             string      `json:"applicant_name"`
+	PropertyAddress           string      `json:"property_address"`
+	NormalizedPropertyAddress string      `json:"normalized_property_address" gorm:"index"` // addressutil.Normalize(PropertyAddress)
+	FUBLeadID                 string      `json:"fub_lead_id,omitempty" gorm:"index"`       // linked FUB lead, set when matched automatically
+	FUBMatchConfidence        float64     `json:"fub_match_confidence,omitempty"`           // best FUB lead match confidence
+	Documents                 StringArray `json:"documents" gorm:"type:json"`
+	Notes                     string      `json:"notes" gorm:"type:text"`
+	CreatedAt                 time.Time   `json:"created_at"`
+	UpdatedAt                 time.Time   `json:"updated_at"`
+}
+
+// BeforeSave keeps NormalizedPropertyAddress in sync with PropertyAddress
+func (a *Approval) BeforeSave(tx *gorm.DB) error {
+	a.NormalizedPropertyAddress = addressutil.Normalize(a.PropertyAddress)
+	return nil
 }
 
 // Contact represents a contact inquiry from the website
@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// Admin notification delivery channels
+const (
+	NotificationChannelInApp = "in_app"
+	NotificationChannelEmail = "email"
+	NotificationChannelSMS   = "sms"
+)
+
+// NotificationEventTypeDefault is the event type of an admin's default
+// preference, used for notification types without their own preference
+const NotificationEventTypeDefault = "*"
+
+// Queued notification delivery statuses
+const (
+	QueuedNotificationPending = "pending"
+	QueuedNotificationSent    = "sent"
+	QueuedNotificationFailed  = "failed"
+)
+
+// NotificationPreference is how one admin receives one type of admin
+// notification. Non-urgent notifications arriving during quiet hours are
+// queued until quiet hours end; equal start and end hours disable quiet hours.
+type NotificationPreference struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	AdminUserID     string    `json:"admin_user_id" gorm:"not null;uniqueIndex:idx_notification_preferences_admin_type"`
+	EventType       string    `json:"event_type" gorm:"not null;size:64;uniqueIndex:idx_notification_preferences_admin_type"`
+	InApp           bool      `json:"in_app"`
+	Email           bool      `json:"email"`
+	SMS             bool      `json:"sms"`
+	SMSPhone        string    `json:"sms_phone,omitempty" gorm:"size:50"`
+	QuietHoursStart int       `json:"quiet_hours_start"` // Hour, 0-23
+	QuietHoursEnd   int       `json:"quiet_hours_end"`
+	Timezone        string    `json:"timezone,omitempty" gorm:"size:64"` // Empty uses server time
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// DefaultNotificationPreference delivers everything in-app, with no quiet
+// hours, as the hub did before preferences existed
+func DefaultNotificationPreference(adminUserID, eventType string) NotificationPreference {
+	return NotificationPreference{AdminUserID: adminUserID, EventType: eventType, InApp: true}
+}
+
+// QuietUntil returns when the quiet hours containing t end, or the zero time
+// if t is outside quiet hours
+func (p NotificationPreference) QuietUntil(t time.Time) time.Time {
+	if p.QuietHoursStart == p.QuietHoursEnd {
+		return time.Time{}
+	}
+	loc := t.Location()
+	if p.Timezone != "" {
+		if tz, err := time.LoadLocation(p.Timezone); err == nil {
+			loc = tz
+		}
+	}
+	local := t.In(loc)
+	hour := local.Hour()
+	quiet := hour >= p.QuietHoursStart && hour < p.QuietHoursEnd
+	if p.QuietHoursStart > p.QuietHoursEnd {
+		quiet = hour >= p.QuietHoursStart || hour < p.QuietHoursEnd
+	}
+	if !quiet {
+		return time.Time{}
+	}
+	end := time.Date(local.Year(), local.Month(), local.Day(), p.QuietHoursEnd, 0, 0, 0, loc)
+	if !end.After(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+// IsUrgentNotification reports whether a notification priority is delivered
+// during quiet hours
+func IsUrgentNotification(priority string) bool {
+	return priority == "high" || priority == "urgent"
+}
+
+// QueuedNotification is a notification held for one admin and channel until
+// their quiet hours end
+type QueuedNotification struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	NotificationID uint       `json:"notification_id" gorm:"not null;index"`
+	AdminUserID    string     `json:"admin_user_id" gorm:"not null;index"`
+	Channel        string     `json:"channel" gorm:"size:20"`
+	DeliverAfter   time.Time  `json:"deliver_after" gorm:"index"`
+	Status         string     `json:"status" gorm:"size:20;default:'pending';index"`
+	Error          string     `json:"error,omitempty" gorm:"type:text"`
+	SentAt         *time.Time `json:"sent_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrVersionConflict is returned when a record changed after the client read it
+var ErrVersionConflict = errors.New("record was modified by another update")
+
+// OptimisticLock adds a version column to models that reject stale updates.
+// Clients send back the version they read; SaveVersioned only writes when the
+// stored version still matches and bumps it on success.
+type OptimisticLock struct {
+	Version int `json:"version" gorm:"not null;default:1"`
+}
+
+func (l *OptimisticLock) optimisticLock() *OptimisticLock {
+	return l
+}
+
+// VersionedRecord is a model embedding OptimisticLock
+type VersionedRecord interface {
+	optimisticLock() *OptimisticLock
+}
+
+// CheckVersion reports ErrVersionConflict when a loaded record no longer
+// carries the version the client read
+func CheckVersion(record VersionedRecord, expectedVersion int) error {
+	if record.optimisticLock().Version != expectedVersion {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// SaveVersioned writes every field of a loaded, modified record if the stored
+// row still carries expectedVersion, incrementing the version. Returns
+// ErrVersionConflict, leaving the record's version untouched, when another
+// update got there first.
+func SaveVersioned(db *gorm.DB, record VersionedRecord, expectedVersion int) error {
+	lock := record.optimisticLock()
+	lock.Version = expectedVersion + 1
+
+	result := db.Model(record).
+		Where("version = ?", expectedVersion).
+		Select("*").
+		Omit(clause.Associations).
+		Updates(record)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		lock.Version = expectedVersion
+		return result.Error
+	}
+	return nil
+}
+
+// UpdateVersioned applies the non-zero fields of updates, a value of the
+// record's type, with the same version check as SaveVersioned, then reloads
+// the record.
+func UpdateVersioned(db *gorm.DB, record VersionedRecord, updates VersionedRecord, expectedVersion int) error {
+	updates.optimisticLock().Version = expectedVersion + 1
+
+	result := db.Model(record).
+		Where("version = ?", expectedVersion).
+		Omit(clause.Associations).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return db.First(record).Error
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSaveVersioned_RejectsStaleUpdates(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&Lead{}, &PreListingItem{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	lead := Lead{FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", FUBLeadID: "fub-1"}
+	if err := db.Create(&lead).Error; err != nil {
+		t.Fatalf("Failed to create lead: %v", err)
+	}
+	if lead.Version != 1 {
+		t.Fatalf("Expected new lead at version 1, got %d", lead.Version)
+	}
+
+	// Two agents read version 1
+	var first, second Lead
+	db.First(&first, lead.ID)
+	db.First(&second, lead.ID)
+
+	first.Status = "contacted"
+	if err := SaveVersioned(db, &first, 1); err != nil {
+		t.Fatalf("First update failed: %v", err)
+	}
+	if first.Version != 2 {
+		t.Fatalf("Expected version 2 after update, got %d", first.Version)
+	}
+
+	second.Phone = "555-0100"
+	if err := SaveVersioned(db, &second, 1); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("Expected ErrVersionConflict for stale update, got %v", err)
+	}
+	if second.Version != 1 {
+		t.Fatalf("Expected stale record to keep its version, got %d", second.Version)
+	}
+
+	var stored Lead
+	db.First(&stored, lead.ID)
+	if stored.Status != "contacted" || stored.Phone != "" || stored.Version != 2 {
+		t.Fatalf("Stale update overwrote the stored lead: %+v", stored)
+	}
+	if err := CheckVersion(&stored, 1); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("Expected CheckVersion to flag version 1, got %v", err)
+	}
+
+	item := PreListingItem{Address: "123 Main Street"}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatalf("Failed to create pre-listing: %v", err)
+	}
+	if err := UpdateVersioned(db, &item, &PreListingItem{AdminNotes: "lockbox ordered"}, 1); err != nil {
+		t.Fatalf("UpdateVersioned failed: %v", err)
+	}
+	if item.AdminNotes != "lockbox ordered" || item.Address != "123 Main Street" || item.Version != 2 {
+		t.Fatalf("Unexpected pre-listing after update: %+v", item)
+	}
+	if err := UpdateVersioned(db, &item, &PreListingItem{AdminNotes: "stale"}, 1); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("Expected ErrVersionConflict for stale pre-listing update, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"chrisgross-ctrl-project/internal/addressutil"
 	"gorm.io/gorm"
 	"time"
 )
@@ -11,13 +12,15 @@ type PreListingItem struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	OptimisticLock
 
 	// Property Information
-	Address     string `json:"address" gorm:"not null"`
-	FullAddress string `json:"full_address"`
-	City        string `json:"city"`
-	State       string `json:"state"`
-	ZipCode     string `json:"zip_code"`
+	Address           string `json:"address" gorm:"not null"`
+	NormalizedAddress string `json:"normalized_address" gorm:"index"` // addressutil.Normalize(Address), kept in sync on save
+	FullAddress       string `json:"full_address"`
+	City              string `json:"city"`
+	State             string `json:"state"`
+	ZipCode           string `json:"zip_code"`
 
 	// Status and Progress
 	Status        string `json:"status" gorm:"default:'email_received'"` // email_received, lockbox_pending, lockbox_placed, photos_scheduled, photos_complete, pricing_set, listed, confirmed
@@ -63,6 +66,12 @@ type PreListingItem struct {
 	OverrideReason string     `json:"override_reason"`
 }
 
+// BeforeSave keeps NormalizedAddress in sync with Address
+func (p *PreListingItem) BeforeSave(tx *gorm.DB) error {
+	p.NormalizedAddress = addressutil.Normalize(p.Address)
+	return nil
+}
+
 // EmailAlert tracks alerts sent for overdue items
 type EmailAlert struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
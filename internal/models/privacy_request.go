@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Privacy request kinds
+const (
+	PrivacyRequestExport  = "export"
+	PrivacyRequestErasure = "erasure"
+)
+
+// Privacy request statuses
+const (
+	PrivacyRequestReceived  = "received"
+	PrivacyRequestFulfilled = "fulfilled"
+	PrivacyRequestFailed    = "failed"
+)
+
+// PrivacyRequest records a data subject's request to export or erase their
+// data and how it was fulfilled. The subject is kept only as a blind index so
+// the log itself holds no plaintext PII after an erasure.
+type PrivacyRequest struct {
+	ID                 uint       `json:"id" gorm:"primaryKey"`
+	Kind               string     `json:"kind" gorm:"size:10;index"`
+	SubjectHash        string     `json:"subject_hash" gorm:"size:16;index"` // Email blind index of the subject
+	IndexKeyID         string     `json:"-" gorm:"size:16"`                  // Blind index key the hash was computed with
+	VerificationMethod string     `json:"verification_method"`               // How the subject's identity was verified
+	RequestedByID      string     `json:"requested_by_id" gorm:"index"`
+	RequestedBy        string     `json:"requested_by"`
+	Status             string     `json:"status" gorm:"size:20;index"`
+	RecordCounts       JSONB      `json:"record_counts" gorm:"type:json"` // section -> records exported or erased
+	Error              string     `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt          time.Time  `json:"created_at" gorm:"index"`
+	FulfilledAt        *time.Time `json:"fulfilled_at,omitempty"`
+}
+
+// TableName overrides the table name
+func (PrivacyRequest) TableName() string {
+	return "privacy_requests"
+}
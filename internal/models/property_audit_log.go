@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Property audit actions
+const (
+	PropertyAuditActionDeleted  = "deleted"
+	PropertyAuditActionRestored = "restored"
+)
+
+// PropertyAuditLog records who deleted or restored a property and when
+type PropertyAuditLog struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	PropertyID uint      `gorm:"index;not null" json:"property_id"`
+	MLSId      string    `gorm:"column:mls_id;index" json:"mls_id"`
+	Action     string    `gorm:"size:32;index;not null" json:"action"`
+	ActorID    string    `gorm:"index" json:"actor_id"`
+	ActorName  string    `json:"actor_name"`
+	IPAddress  string    `json:"ip_address"`
+	Reason     string    `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName overrides the table name
+func (PropertyAuditLog) TableName() string {
+	return "property_audit_logs"
+}
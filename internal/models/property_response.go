@@ -32,6 +32,7 @@ type PropertyResponse struct {
 	DaysOnMarket      *int     `json:"days_on_market"`
 	YearBuilt         int      `json:"year_built"`
 	ManagementCompany string   `json:"management_company"`
+	Version           int      `json:"version"` // send back on update for conflict detection
 }
 
 // ToResponse converts a Property to PropertyResponse with decrypted address
@@ -69,6 +70,7 @@ func ToResponse(property Property, encryptionManager *security.EncryptionManager
 		DaysOnMarket:      property.DaysOnMarket,
 		YearBuilt:         property.YearBuilt,
 		ManagementCompany: property.ManagementCompany,
+		Version:           property.Version,
 	}
 }
 
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhook sources recorded in ReceivedWebhook
+const (
+	WebhookSourceContextFUB = "context_fub"
+)
+
+// ReceivedWebhook stores an inbound webhook payload so it can be replayed after
+// a downstream processing fix. Replays are stored as their own rows with
+// IsReplay set, so analytics can count live and replayed events separately.
+type ReceivedWebhook struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Source     string    `json:"source" gorm:"index;size:50;not null"`
+	EventType  string    `json:"event_type" gorm:"index;size:100"`
+	Payload    string    `json:"payload" gorm:"type:text"`
+	ReceivedAt time.Time `json:"received_at" gorm:"index"`
+
+	// Processing outcome
+	Triggered   bool   `json:"triggered" gorm:"default:false"`
+	TriggerType string `json:"trigger_type"`
+
+	// Replay tracking
+	IsReplay   bool   `json:"is_replay" gorm:"index;default:false"`
+	ReplayOfID *uint  `json:"replay_of_id,omitempty" gorm:"index"`
+	ReplayedBy string `json:"replayed_by,omitempty"`
+}
+
+// WebhookReplayFilter selects the live webhooks to replay, by ID or by time range
+type WebhookReplayFilter struct {
+	Source    string
+	IDs       []uint
+	From      *time.Time
+	To        *time.Time
+	EventType string
+	Limit     int
+}
+
+// FindReplayableWebhooks returns the originally received webhooks (not replay
+// copies) matching the filter, oldest first so replays run in received order
+func FindReplayableWebhooks(db *gorm.DB, filter WebhookReplayFilter) ([]ReceivedWebhook, error) {
+	query := db.Where("source = ? AND is_replay = ?", filter.Source, false)
+	if len(filter.IDs) > 0 {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	if filter.From != nil {
+		query = query.Where("received_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("received_at <= ?", *filter.To)
+	}
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var webhooks []ReceivedWebhook
+	if err := query.Order("received_at ASC, id ASC").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestFindReplayableWebhooks_SkipsReplaysAndFiltersByRange(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&ReceivedWebhook{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	webhooks := []ReceivedWebhook{
+		{Source: WebhookSourceContextFUB, EventType: "property_viewed", ReceivedAt: base},
+		{Source: WebhookSourceContextFUB, EventType: "inquiry_submitted", ReceivedAt: base.Add(time.Hour)},
+		{Source: WebhookSourceContextFUB, EventType: "property_viewed", ReceivedAt: base.Add(48 * time.Hour)},
+		{Source: "other", EventType: "property_viewed", ReceivedAt: base.Add(time.Hour)},
+	}
+	if err := db.Create(&webhooks).Error; err != nil {
+		t.Fatalf("Failed to create webhooks: %v", err)
+	}
+	replayOf := webhooks[0].ID
+	if err := db.Create(&ReceivedWebhook{Source: WebhookSourceContextFUB, EventType: "property_viewed",
+		ReceivedAt: base.Add(2 * time.Hour), IsReplay: true, ReplayOfID: &replayOf}).Error; err != nil {
+		t.Fatalf("Failed to create replay: %v", err)
+	}
+
+	from, to := base, base.Add(24*time.Hour)
+	found, err := FindReplayableWebhooks(db, WebhookReplayFilter{Source: WebhookSourceContextFUB, From: &from, To: &to})
+	if err != nil {
+		t.Fatalf("FindReplayableWebhooks failed: %v", err)
+	}
+	if len(found) != 2 || found[0].ID != webhooks[0].ID || found[1].ID != webhooks[1].ID {
+		t.Errorf("Expected the two live webhooks in range oldest first, got %+v", found)
+	}
+
+	found, _ = FindReplayableWebhooks(db, WebhookReplayFilter{Source: WebhookSourceContextFUB, IDs: []uint{webhooks[2].ID, webhooks[3].ID}, EventType: "property_viewed"})
+	if len(found) != 1 || found[0].ID != webhooks[2].ID {
+		t.Errorf("Expected only the context webhook with the given ID, got %+v", found)
+	}
+}
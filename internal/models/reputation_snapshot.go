@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// ReputationSnapshot records sender reputation metrics once per day so
+// compliance reports can trend them
+type ReputationSnapshot struct {
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	SnapshotDate        time.Time `gorm:"type:date;uniqueIndex;not null" json:"snapshot_date"`
+	OverallScore        float64   `json:"overall_score"`
+	DeliverabilityScore float64   `json:"deliverability_score"`
+	EngagementScore     float64   `json:"engagement_score"`
+	ComplaintScore      float64   `json:"complaint_score"`
+	BounceRate          float64   `json:"bounce_rate"`
+	SpamComplaintRate   float64   `json:"spam_complaint_rate"`
+	UnsubscribeRate     float64   `json:"unsubscribe_rate"`
+	OpenRate            float64   `json:"open_rate"`
+	ClickRate           float64   `json:"click_rate"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (ReputationSnapshot) TableName() string {
+	return "reputation_snapshots"
+}
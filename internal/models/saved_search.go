@@ -0,0 +1,137 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Saved search alert channels
+const (
+	SavedSearchChannelEmail = "email"
+	SavedSearchChannelSMS   = "sms"
+)
+
+// Saved search alert triggers
+const (
+	SavedSearchTriggerNewListing  = "new_listing"
+	SavedSearchTriggerPriceChange = "price_change"
+)
+
+// Saved search alert delivery statuses
+const (
+	SavedSearchAlertPending = "pending"
+	SavedSearchAlertSent    = "sent"
+	SavedSearchAlertFailed  = "failed"
+)
+
+// SavedSearchCriteria is the listing filter of a saved search. Zero values
+// and empty lists do not filter.
+type SavedSearchCriteria struct {
+	MinPrice      float64  `json:"min_price,omitempty"`
+	MaxPrice      float64  `json:"max_price,omitempty"`
+	MinBedrooms   int      `json:"min_bedrooms,omitempty"`
+	MaxBedrooms   int      `json:"max_bedrooms,omitempty"`
+	MinBathrooms  float64  `json:"min_bathrooms,omitempty"`
+	Cities        []string `json:"cities,omitempty"`
+	Zips          []string `json:"zips,omitempty"`
+	PropertyTypes []string `json:"property_types,omitempty"`
+	ListingType   string   `json:"listing_type,omitempty"` // rental or sale
+}
+
+// Scan implements the sql.Scanner interface for SavedSearchCriteria
+func (c *SavedSearchCriteria) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*c = SavedSearchCriteria{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("failed to unmarshal saved search criteria: %v", value)
+	}
+	if len(data) == 0 {
+		*c = SavedSearchCriteria{}
+		return nil
+	}
+	return json.Unmarshal(data, c)
+}
+
+// Value implements the driver.Valuer interface for SavedSearchCriteria
+func (c SavedSearchCriteria) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// SavedSearch is a listing search whose owner is alerted when a new or
+// re-priced listing matches it
+type SavedSearch struct {
+	ID            uint                `json:"id" gorm:"primaryKey"`
+	Name          string              `json:"name" gorm:"size:200"`
+	OwnerEmail    string              `json:"owner_email" gorm:"index;size:255"`
+	OwnerPhone    string              `json:"owner_phone,omitempty" gorm:"size:50"`
+	LeadID        *uint               `json:"lead_id,omitempty" gorm:"index"`
+	Channel       string              `json:"channel" gorm:"size:20;default:'email'"`
+	Criteria      SavedSearchCriteria `json:"criteria" gorm:"type:text"`
+	Active        bool                `json:"active" gorm:"index;default:true"`
+	CreatedBy     string              `json:"created_by,omitempty"`
+	LastAlertedAt *time.Time          `json:"last_alerted_at,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (SavedSearch) TableName() string {
+	return "saved_searches"
+}
+
+// Validate checks the channel has a recipient and the criteria ranges are ordered
+func (s SavedSearch) Validate() error {
+	switch s.Channel {
+	case SavedSearchChannelEmail:
+		if s.OwnerEmail == "" {
+			return fmt.Errorf("owner_email is required for email alerts")
+		}
+	case SavedSearchChannelSMS:
+		if s.OwnerPhone == "" {
+			return fmt.Errorf("owner_phone is required for sms alerts")
+		}
+	default:
+		return fmt.Errorf("channel must be %q or %q", SavedSearchChannelEmail, SavedSearchChannelSMS)
+	}
+
+	c := s.Criteria
+	if c.MinPrice < 0 || c.MaxPrice < 0 || c.MinBedrooms < 0 || c.MaxBedrooms < 0 || c.MinBathrooms < 0 {
+		return fmt.Errorf("criteria values must not be negative")
+	}
+	if c.MaxPrice > 0 && c.MinPrice > c.MaxPrice {
+		return fmt.Errorf("min_price must not exceed max_price")
+	}
+	if c.MaxBedrooms > 0 && c.MinBedrooms > c.MaxBedrooms {
+		return fmt.Errorf("min_bedrooms must not exceed max_bedrooms")
+	}
+	return nil
+}
+
+// SavedSearchAlert records one listing matched to a saved search. The unique
+// index on search and property keeps an owner from being alerted twice for
+// the same listing, whether it matched as new or after a price change.
+type SavedSearchAlert struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	SavedSearchID uint       `json:"saved_search_id" gorm:"uniqueIndex:idx_saved_search_alerts_listing;not null"`
+	PropertyID    uint       `json:"property_id" gorm:"uniqueIndex:idx_saved_search_alerts_listing;not null"`
+	Trigger       string     `json:"trigger" gorm:"size:20"`
+	Channel       string     `json:"channel" gorm:"size:20"`
+	Status        string     `json:"status" gorm:"index;size:20;default:'pending'"`
+	Error         string     `json:"error,omitempty" gorm:"type:text"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TableName overrides the table name
+func (SavedSearchAlert) TableName() string {
+	return "saved_search_alerts"
+}
@@ -0,0 +1,210 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ScoringConfigID is the primary key of the single scoring config row
+const ScoringConfigID = 1
+
+// ScoringConfig holds the score multipliers and thresholds used by context-driven
+// FUB automation to score leads and choose workflows, actions, and priorities.
+// Scores and thresholds are on a 0-1 scale; a lead meets a threshold when its
+// score is greater than or equal to it.
+type ScoringConfig struct {
+	ID uint `gorm:"primaryKey" json:"-"`
+
+	// Adaptive score adjustments by property market type
+	RentalEngagementMultiplier   float64 `json:"rental_engagement_multiplier"`
+	SalesEngagementMultiplier    float64 `json:"sales_engagement_multiplier"`
+	RentalUrgencyMultiplier      float64 `json:"rental_urgency_multiplier"`
+	SalesUrgencyMultiplier       float64 `json:"sales_urgency_multiplier"`
+	PreApprovedFinancialBoost    float64 `json:"pre_approved_financial_boost"`
+	IncomeVerifiedFinancialBoost float64 `json:"income_verified_financial_boost"`
+
+	// Workflows when the property market type is unknown
+	HighIntentUrgency            float64 `json:"high_intent_urgency"`
+	HighIntentEngagement         float64 `json:"high_intent_engagement"`
+	QualifiedNurtureConversion   float64 `json:"qualified_nurture_conversion"`
+	QualifiedNurtureEngagement   float64 `json:"qualified_nurture_engagement"`
+	EngagementBuildingEngagement float64 `json:"engagement_building_engagement"`
+
+	// Actions when the property market type is unknown
+	ImmediateCallUrgency           float64 `json:"immediate_call_urgency"`
+	PersonalizedFollowUpConversion float64 `json:"personalized_follow_up_conversion"`
+	EducationalContentEngagement   float64 `json:"educational_content_engagement"`
+
+	// Rental workflows
+	RentalImmediateUrgency   float64 `json:"rental_immediate_urgency"`
+	RentalImmediateFinancial float64 `json:"rental_immediate_financial"`
+	RentalUrgentUrgency      float64 `json:"rental_urgent_urgency"`
+	RentalUrgentEngagement   float64 `json:"rental_urgent_engagement"`
+	RentalQualifiedFinancial float64 `json:"rental_qualified_financial"`
+
+	// Rental actions
+	RentalShowingCallUrgency   float64 `json:"rental_showing_call_urgency"`
+	RentalApplicationFinancial float64 `json:"rental_application_financial"`
+	RentalApplicationUrgency   float64 `json:"rental_application_urgency"`
+	RentalTourEngagement       float64 `json:"rental_tour_engagement"`
+
+	// Sales workflows
+	SalesPreApprovedFinancial float64 `json:"sales_pre_approved_financial"`
+	SalesPreApprovedUrgency   float64 `json:"sales_pre_approved_urgency"`
+	SalesEngagedEngagement    float64 `json:"sales_engaged_engagement"`
+	SalesEngagedFinancial     float64 `json:"sales_engaged_financial"`
+	SalesUrgentUrgency        float64 `json:"sales_urgent_urgency"`
+
+	// Sales actions
+	SalesConsultationFinancial    float64 `json:"sales_consultation_financial"`
+	SalesMarketAnalysisUrgency    float64 `json:"sales_market_analysis_urgency"`
+	SalesMarketAnalysisEngagement float64 `json:"sales_market_analysis_engagement"`
+	SalesFinancingEngagement      float64 `json:"sales_financing_engagement"`
+
+	// Mixed-market workflows and actions, by average score
+	MixedHighIntentAverage float64 `json:"mixed_high_intent_average"`
+	MixedQualifiedAverage  float64 `json:"mixed_qualified_average"`
+
+	// Priority, by average score
+	HighPriorityAverage   float64 `json:"high_priority_average"`
+	MediumPriorityAverage float64 `json:"medium_priority_average"`
+
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (ScoringConfig) TableName() string {
+	return "scoring_configs"
+}
+
+// DefaultScoringConfig returns the multipliers and thresholds used when none are saved
+func DefaultScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		ID: ScoringConfigID,
+
+		RentalEngagementMultiplier:   1.1,
+		SalesEngagementMultiplier:    0.9,
+		RentalUrgencyMultiplier:      1.2,
+		SalesUrgencyMultiplier:       0.8,
+		PreApprovedFinancialBoost:    0.3,
+		IncomeVerifiedFinancialBoost: 0.2,
+
+		HighIntentUrgency:            0.8,
+		HighIntentEngagement:         0.7,
+		QualifiedNurtureConversion:   0.6,
+		QualifiedNurtureEngagement:   0.5,
+		EngagementBuildingEngagement: 0.4,
+
+		ImmediateCallUrgency:           0.8,
+		PersonalizedFollowUpConversion: 0.6,
+		EducationalContentEngagement:   0.4,
+
+		RentalImmediateUrgency:   0.8,
+		RentalImmediateFinancial: 0.6,
+		RentalUrgentUrgency:      0.6,
+		RentalUrgentEngagement:   0.5,
+		RentalQualifiedFinancial: 0.5,
+
+		RentalShowingCallUrgency:   0.8,
+		RentalApplicationFinancial: 0.6,
+		RentalApplicationUrgency:   0.5,
+		RentalTourEngagement:       0.5,
+
+		SalesPreApprovedFinancial: 0.8,
+		SalesPreApprovedUrgency:   0.6,
+		SalesEngagedEngagement:    0.7,
+		SalesEngagedFinancial:     0.5,
+		SalesUrgentUrgency:        0.6,
+
+		SalesConsultationFinancial:    0.8,
+		SalesMarketAnalysisUrgency:    0.7,
+		SalesMarketAnalysisEngagement: 0.5,
+		SalesFinancingEngagement:      0.6,
+
+		MixedHighIntentAverage: 0.7,
+		MixedQualifiedAverage:  0.5,
+
+		HighPriorityAverage:   0.7,
+		MediumPriorityAverage: 0.4,
+	}
+}
+
+// Validate checks that multipliers are positive, boosts and thresholds are on
+// the 0-1 scale, and tiered averages are ordered
+func (c ScoringConfig) Validate() error {
+	multipliers := map[string]float64{
+		"rental_engagement_multiplier": c.RentalEngagementMultiplier,
+		"sales_engagement_multiplier":  c.SalesEngagementMultiplier,
+		"rental_urgency_multiplier":    c.RentalUrgencyMultiplier,
+		"sales_urgency_multiplier":     c.SalesUrgencyMultiplier,
+	}
+	for name, value := range multipliers {
+		if value <= 0 || value > 3 {
+			return fmt.Errorf("%s must be greater than 0 and at most 3", name)
+		}
+	}
+
+	unitValues := map[string]float64{
+		"pre_approved_financial_boost":      c.PreApprovedFinancialBoost,
+		"income_verified_financial_boost":   c.IncomeVerifiedFinancialBoost,
+		"high_intent_urgency":               c.HighIntentUrgency,
+		"high_intent_engagement":            c.HighIntentEngagement,
+		"qualified_nurture_conversion":      c.QualifiedNurtureConversion,
+		"qualified_nurture_engagement":      c.QualifiedNurtureEngagement,
+		"engagement_building_engagement":    c.EngagementBuildingEngagement,
+		"immediate_call_urgency":            c.ImmediateCallUrgency,
+		"personalized_follow_up_conversion": c.PersonalizedFollowUpConversion,
+		"educational_content_engagement":    c.EducationalContentEngagement,
+		"rental_immediate_urgency":          c.RentalImmediateUrgency,
+		"rental_immediate_financial":        c.RentalImmediateFinancial,
+		"rental_urgent_urgency":             c.RentalUrgentUrgency,
+		"rental_urgent_engagement":          c.RentalUrgentEngagement,
+		"rental_qualified_financial":        c.RentalQualifiedFinancial,
+		"rental_showing_call_urgency":       c.RentalShowingCallUrgency,
+		"rental_application_financial":      c.RentalApplicationFinancial,
+		"rental_application_urgency":        c.RentalApplicationUrgency,
+		"rental_tour_engagement":            c.RentalTourEngagement,
+		"sales_pre_approved_financial":      c.SalesPreApprovedFinancial,
+		"sales_pre_approved_urgency":        c.SalesPreApprovedUrgency,
+		"sales_engaged_engagement":          c.SalesEngagedEngagement,
+		"sales_engaged_financial":           c.SalesEngagedFinancial,
+		"sales_urgent_urgency":              c.SalesUrgentUrgency,
+		"sales_consultation_financial":      c.SalesConsultationFinancial,
+		"sales_market_analysis_urgency":     c.SalesMarketAnalysisUrgency,
+		"sales_market_analysis_engagement":  c.SalesMarketAnalysisEngagement,
+		"sales_financing_engagement":        c.SalesFinancingEngagement,
+		"mixed_high_intent_average":         c.MixedHighIntentAverage,
+		"mixed_qualified_average":           c.MixedQualifiedAverage,
+		"high_priority_average":             c.HighPriorityAverage,
+		"medium_priority_average":           c.MediumPriorityAverage,
+	}
+	for name, value := range unitValues {
+		if value < 0 || value > 1 {
+			return fmt.Errorf("%s must be between 0 and 1", name)
+		}
+	}
+
+	if c.MixedQualifiedAverage > c.MixedHighIntentAverage {
+		return fmt.Errorf("mixed_qualified_average must not exceed mixed_high_intent_average")
+	}
+	if c.MediumPriorityAverage > c.HighPriorityAverage {
+		return fmt.Errorf("medium_priority_average must not exceed high_priority_average")
+	}
+	return nil
+}
+
+// LoadScoringConfig returns the saved scoring config, or the defaults if none is saved
+func LoadScoringConfig(db *gorm.DB) (ScoringConfig, error) {
+	config := DefaultScoringConfig()
+	if err := db.First(&config, ScoringConfigID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return DefaultScoringConfig(), nil
+		}
+		return DefaultScoringConfig(), err
+	}
+	return config, nil
+}
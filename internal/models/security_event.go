@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Security event types recorded by the security middlewares
+const (
+	SecurityEventSQLInjection = "SQL_INJECTION"
+	SecurityEventXSS          = "XSS"
+	SecurityEventBruteForce   = "BRUTE_FORCE"
+)
+
+// Security event severities
+const (
+	SecuritySeverityLow      = "LOW"
+	SecuritySeverityMedium   = "MEDIUM"
+	SecuritySeverityHigh     = "HIGH"
+	SecuritySeverityCritical = "CRITICAL"
+)
+
+// SecurityEvent is a detection or notable security action shown on the
+// security dashboard. Columns keep the names of the original security_events
+// table, which the auth manager also writes login events to.
+type SecurityEvent struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	Type           string     `json:"type" gorm:"column:event_type;size:100;index"`
+	Severity       string     `json:"severity" gorm:"size:20;index"`
+	UserID         *string    `json:"user_id,omitempty" gorm:"type:uuid;index"`
+	IPAddress      string     `json:"ip_address" gorm:"size:45;index"`
+	UserAgent      string     `json:"user_agent"`
+	Endpoint       string     `json:"endpoint"` // Request path
+	Method         string     `json:"method" gorm:"size:10"`
+	StatusCode     int        `json:"status_code"`
+	Payload        string     `json:"payload,omitempty" gorm:"size:500"` // Snippet of the offending input
+	Metadata       JSONB      `json:"metadata" gorm:"column:event_data;type:jsonb"`
+	Timestamp      time.Time  `json:"timestamp" gorm:"column:created_at;autoCreateTime;index"`
+	Resolved       bool       `json:"resolved" gorm:"default:false;index"`
+	ResolvedBy     *string    `json:"resolved_by,omitempty" gorm:"type:uuid"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	ResolutionNote string     `json:"resolution_note,omitempty" gorm:"type:text"`
+}
+
+// TableName overrides the table name
+func (SecurityEvent) TableName() string {
+	return "security_events"
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"chrisgross-ctrl-project/internal/security"
+)
+
+// SMSSendLog records every outbound SMS attempt with the consent basis used,
+// so TCPA compliance can be demonstrated after the fact
+type SMSSendLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+
+	ToPhone            security.EncryptedString `json:"to_phone"`
+	LeadReengagementID *uint                    `json:"lead_reengagement_id,omitempty" gorm:"index"`
+
+	Status        string `json:"status" gorm:"index"` // sent, blocked, failed
+	Reason        string `json:"reason"`
+	MessageLength int    `json:"message_length"`
+
+	// Consent basis at time of send
+	ConsentBasis  string        `json:"consent_basis"` // express_consent, opt_in
+	ConsentStatus ConsentStatus `json:"consent_status"`
+	ConsentMethod string        `json:"consent_method"`
+	ConsentDate   *time.Time    `json:"consent_date,omitempty"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Task statuses. A snoozed task stays open with SnoozedUntil set.
+const (
+	TaskStatusOpen      = "open"
+	TaskStatusCompleted = "completed"
+)
+
+// Task sources
+const (
+	TaskSourceManual  = "manual"
+	TaskSourceHotLead = "hot_lead"
+	TaskSourceInsight = "insight"
+)
+
+// Task is an assignable to-do in the command center, either created by hand
+// or converted from a high-confidence insight. SourceKey identifies what it
+// was generated from so the same insight never opens two tasks at once.
+type Task struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	Title        string     `json:"title" gorm:"not null"`
+	Description  string     `json:"description" gorm:"type:text"`
+	Type         string     `json:"type" gorm:"size:50"` // call_lead, review_insight, follow_up
+	Source       string     `json:"source" gorm:"size:20;default:'manual'"`
+	SourceKey    string     `json:"source_key,omitempty" gorm:"size:100;index"`
+	LeadID       *int64     `json:"lead_id,omitempty" gorm:"index"`
+	AssignedTo   string     `json:"assigned_to" gorm:"index"` // Admin user ID; empty for the whole team
+	Priority     int        `json:"priority"`                 // 1-10, 10 being highest
+	Confidence   float64    `json:"confidence"`
+	Status       string     `json:"status" gorm:"size:20;default:'open';index"`
+	DueAt        time.Time  `json:"due_at" gorm:"index"`
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	CompletedBy  string     `json:"completed_by,omitempty"`
+	CreatedBy    string     `json:"created_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// IsSnoozed reports whether an open task is hidden until later
+func (t *Task) IsSnoozed(now time.Time) bool {
+	return t.SnoozedUntil != nil && t.SnoozedUntil.After(now)
+}
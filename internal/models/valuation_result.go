@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// Valuation batch and result statuses
+const (
+	ValuationStatusPending    = "pending"
+	ValuationStatusProcessing = "processing"
+	ValuationStatusCompleted  = "completed"
+	ValuationStatusFailed     = "failed"
+)
+
+// ValuationBatch tracks a bulk valuation job
+type ValuationBatch struct {
+	ID          string     `gorm:"primaryKey;size:36" json:"id"`
+	Status      string     `gorm:"index;default:'pending'" json:"status"`
+	Total       int        `json:"total"`
+	Completed   int        `gorm:"default:0" json:"completed"`
+	Failed      int        `gorm:"default:0" json:"failed"`
+	RequestedBy string     `json:"requested_by"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (ValuationBatch) TableName() string {
+	return "valuation_batches"
+}
+
+// ValuationResult stores the outcome of valuing one address in a batch
+type ValuationResult struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	BatchID        string    `gorm:"index;size:36;not null" json:"batch_id"`
+	Address        string    `json:"address"`
+	City           string    `json:"city"`
+	ZipCode        string    `json:"zip_code"`
+	Request        JSONB     `gorm:"type:jsonb" json:"request"`
+	Status         string    `gorm:"index;default:'pending'" json:"status"`
+	EstimatedValue int       `json:"estimated_value"`
+	ValueLow       int       `json:"value_low"`
+	ValueHigh      int       `json:"value_high"`
+	Confidence     float32   `json:"confidence"`
+	CacheHit       bool      `json:"cache_hit"`
+	Error          string    `json:"error,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (ValuationResult) TableName() string {
+	return "valuation_results"
+}
+
+// AddressValuation holds the most recent estimate for an address so re-valuations
+// can be compared against it
+type AddressValuation struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	AddressKey     string    `gorm:"uniqueIndex;not null" json:"address_key"` // normalized address + zip
+	Address        string    `json:"address"`
+	EstimatedValue int       `json:"estimated_value"`
+	ValuedAt       time.Time `json:"valued_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (AddressValuation) TableName() string {
+	return "address_valuations"
+}
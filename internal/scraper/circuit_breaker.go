@@ -0,0 +1,147 @@
+package scraper
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"    // Requests flow normally
+	CircuitOpen     = "open"      // Requests are short-circuited until the cool-down ends
+	CircuitHalfOpen = "half_open" // A single trial request is allowed through
+)
+
+// ErrCircuitOpen is returned when the scraper API is short-circuited
+var ErrCircuitOpen = errors.New("scraper circuit breaker is open")
+
+// CircuitBreaker stops calls to a failing endpoint after consecutive failures
+// and lets a trial request through once the cool-down has passed
+type CircuitBreaker struct {
+	failureThreshold int
+	cooldown         time.Duration
+
+	mutex               sync.Mutex
+	state               string
+	consecutiveFailures int
+	openedAt            time.Time
+	trialInFlight       bool
+	lastFailure         time.Time
+	lastError           string
+	totalTrips          int
+}
+
+// CircuitBreakerStatus is a snapshot of breaker state for health reporting
+type CircuitBreakerStatus struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	FailureThreshold    int        `json:"failure_threshold"`
+	CooldownSeconds     int        `json:"cooldown_seconds"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"`
+	LastFailure         *time.Time `json:"last_failure,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	TotalTrips          int        `json:"total_trips"`
+}
+
+// NewCircuitBreaker creates a breaker that opens after failureThreshold consecutive failures
+func NewCircuitBreaker(failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	if failureThreshold <= 0 {
+		failureThreshold = 5
+	}
+	if cooldown <= 0 {
+		cooldown = time.Minute
+	}
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		state:            CircuitClosed,
+	}
+}
+
+// Allow reports whether a request may proceed, moving an expired open breaker to half-open
+func (cb *CircuitBreaker) Allow() error {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if time.Since(cb.openedAt) < cb.cooldown {
+			return ErrCircuitOpen
+		}
+		cb.state = CircuitHalfOpen
+		cb.trialInFlight = true
+		return nil
+	case CircuitHalfOpen:
+		if cb.trialInFlight {
+			return ErrCircuitOpen
+		}
+		cb.trialInFlight = true
+		return nil
+	}
+	return nil
+}
+
+// RecordSuccess closes the breaker and resets the failure count
+func (cb *CircuitBreaker) RecordSuccess() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.state = CircuitClosed
+	cb.consecutiveFailures = 0
+	cb.trialInFlight = false
+}
+
+// RecordFailure counts a failed request, opening the breaker at the threshold
+// or immediately if the half-open trial failed
+func (cb *CircuitBreaker) RecordFailure(err error) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := time.Now()
+	cb.consecutiveFailures++
+	cb.lastFailure = now
+	if err != nil {
+		cb.lastError = err.Error()
+	}
+
+	if cb.state == CircuitHalfOpen || cb.consecutiveFailures >= cb.failureThreshold {
+		if cb.state != CircuitOpen {
+			cb.totalTrips++
+		}
+		cb.state = CircuitOpen
+		cb.openedAt = now
+	}
+	cb.trialInFlight = false
+}
+
+// Status returns a snapshot of the breaker
+func (cb *CircuitBreaker) Status() CircuitBreakerStatus {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	status := CircuitBreakerStatus{
+		State:               cb.state,
+		ConsecutiveFailures: cb.consecutiveFailures,
+		FailureThreshold:    cb.failureThreshold,
+		CooldownSeconds:     int(cb.cooldown.Seconds()),
+		LastError:           cb.lastError,
+		TotalTrips:          cb.totalTrips,
+	}
+	if cb.state == CircuitOpen && time.Since(cb.openedAt) >= cb.cooldown {
+		// Cool-down elapsed; the next request will be a half-open trial
+		status.State = CircuitHalfOpen
+	}
+	if !cb.openedAt.IsZero() && cb.state != CircuitClosed {
+		openedAt := cb.openedAt
+		retryAt := cb.openedAt.Add(cb.cooldown)
+		status.OpenedAt = &openedAt
+		status.RetryAt = &retryAt
+	}
+	if !cb.lastFailure.IsZero() {
+		lastFailure := cb.lastFailure
+		status.LastFailure = &lastFailure
+	}
+	return status
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"strings"
@@ -19,6 +20,19 @@ type ScraperService struct {
 	client  *http.Client
 	apiKey  string
 	baseURL string
+
+	// Resilience for the external scraper API
+	breaker        *CircuitBreaker
+	maxAttempts    int
+	retryBaseDelay time.Duration
+}
+
+// ScraperHealth reports scraper API availability for ops dashboards
+type ScraperHealth struct {
+	APIKeySet      bool                 `json:"api_key_set"`
+	MaxAttempts    int                  `json:"max_attempts"`
+	CircuitBreaker CircuitBreakerStatus `json:"circuit_breaker"`
+	CheckedAt      time.Time            `json:"checked_at"`
 }
 
 type PropertyListing struct {
@@ -59,11 +73,19 @@ type MLSSearchParams struct {
 }
 
 func NewScraperService(config *config.Config) *ScraperService {
+	maxAttempts := config.ScraperMaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
 	return &ScraperService{
-		config:  config,
-		client:  &http.Client{Timeout: 90 * time.Second},
-		apiKey:  config.ScraperAPIKey,
-		baseURL: "http://api.scraperapi.com",
+		config:         config,
+		client:         &http.Client{Timeout: 90 * time.Second},
+		apiKey:         config.ScraperAPIKey,
+		baseURL:        "http://api.scraperapi.com",
+		breaker:        NewCircuitBreaker(config.ScraperBreakerThreshold, config.ScraperBreakerCooldown),
+		maxAttempts:    maxAttempts,
+		retryBaseDelay: time.Second,
 	}
 }
 
@@ -96,46 +118,81 @@ func (s *ScraperService) fetchWithScraperAPI(targetURL string) (string, error) {
 
 	fullURL := fmt.Sprintf("%s?%s", s.baseURL, params.Encode())
 
-	maxRetries := 3
-	var lastErr error
+	if err := s.breaker.Allow(); err != nil {
+		log.Printf("⚡ Scraper circuit open, skipping fetch for %s", targetURL)
+		return "", err
+	}
 
-	for attempt := 0; attempt < maxRetries; attempt++ {
+	var lastErr error
+	for attempt := 0; attempt < s.maxAttempts; attempt++ {
 		if attempt > 0 {
-			time.Sleep(time.Duration(attempt*2) * time.Second)
-			log.Printf("🔄 Retry attempt %d for %s", attempt+1, targetURL)
+			delay := s.retryDelay(attempt)
+			log.Printf("🔄 Retry attempt %d for %s in %v", attempt+1, targetURL, delay)
+			time.Sleep(delay)
 		}
 
-		req, err := http.NewRequest("GET", fullURL, nil)
-		if err != nil {
-			lastErr = err
-			continue
+		body, retryable, err := s.fetchOnce(fullURL)
+		if err == nil {
+			s.breaker.RecordSuccess()
+			log.Printf("✅ Received %d bytes of HTML", len(body))
+			return body, nil
 		}
 
-		req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; PropertyHub/1.0)")
-
-		resp, err := s.client.Do(req)
-		if err != nil {
-			lastErr = err
-			continue
+		lastErr = err
+		if !retryable {
+			// The API answered, so it is up; the request itself was rejected
+			s.breaker.RecordSuccess()
+			return "", lastErr
 		}
-		defer resp.Body.Close()
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			lastErr = fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
-			continue
-		}
+	s.breaker.RecordFailure(lastErr)
+	return "", lastErr
+}
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			lastErr = err
-			continue
-		}
+// fetchOnce performs a single scraper API request. Network errors, timeouts,
+// 429 and 5xx responses are reported as retryable.
+func (s *ScraperService) fetchOnce(fullURL string) (string, bool, error) {
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return "", false, err
+	}
+
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; PropertyHub/1.0)")
 
-		log.Printf("✅ Received %d bytes of HTML", len(body))
-		return string(body), nil
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", true, err
 	}
+	defer resp.Body.Close()
 
-	return "", lastErr
+	if resp.StatusCode != http.StatusOK {
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return "", retryable, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", true, err
+	}
+
+	return string(body), false, nil
+}
+
+// retryDelay returns an exponential backoff with jitter for the given retry attempt
+func (s *ScraperService) retryDelay(attempt int) time.Duration {
+	backoff := s.retryBaseDelay * time.Duration(1<<uint(attempt-1))
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// Health returns the scraper API circuit breaker state
+func (s *ScraperService) Health() ScraperHealth {
+	return ScraperHealth{
+		APIKeySet:      s.apiKey != "",
+		MaxAttempts:    s.maxAttempts,
+		CircuitBreaker: s.breaker.Status(),
+		CheckedAt:      time.Now(),
+	}
 }
 
 func (s *ScraperService) extractPropertiesFromHTML(html string, sourceURL string) []PropertyListing {
@@ -445,6 +502,7 @@ func (s *ScraperService) GetScrapeStatus() map[string]interface{} {
 		"api_key_set": s.apiKey != "",
 		"base_url":    s.baseURL,
 		"timeout":     s.client.Timeout.Seconds(),
+		"circuit":     s.breaker.Status().State,
 		"timestamp":   time.Now().Unix(),
 	}
 }
@@ -0,0 +1,140 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// searchGramSize is the length of the n-grams stored in blind search indexes
+	searchGramSize = 3
+
+	// minPhoneDigits is the digit count at which a value is also indexed as a phone number
+	minPhoneDigits = 7
+)
+
+// deriveIndexKey derives the blind index HMAC key from the encryption key so
+// index hashes cannot be reproduced without it
+func deriveIndexKey(key []byte) []byte {
+	sum := sha256.Sum256(append([]byte("propertyhub-blind-index:"), key...))
+	return sum[:]
+}
+
+// Blind indexes are keyed HMAC-SHA256 hashes that allow equality and trigram lookups on
+// encrypted fields. The index key is derived from ENCRYPTION_KEY when the manager starts:
+//   - RotateKey does not change the index key, so stored hashes stay valid.
+//   - Changing ENCRYPTION_KEY changes the index key. Each record stores the IndexKeyID it
+//     was hashed with, and records with a different ID must be re-hashed from decrypted
+//     values (models.BackfillLeadBlindIndexes does this at startup). Keep the old key in
+//     ENCRYPTION_PREVIOUS_KEYS so those rows can still be decrypted; rows that cannot be
+//     decrypted drop out of lookups until re-imported.
+
+// IndexKeyID identifies the current blind index key without revealing it
+func (em *EncryptionManager) IndexKeyID() string {
+	sum := sha256.Sum256(em.indexKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+// BlindIndex returns a deterministic keyed hash of a normalized search term
+func (em *EncryptionManager) BlindIndex(term string) string {
+	mac := hmac.New(sha256.New, em.indexKey)
+	mac.Write([]byte(term))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// EmailBlindIndex returns the keyed hash used for exact email lookups, or "" for no email
+func (em *EncryptionManager) EmailBlindIndex(email string) string {
+	normalized := strings.ToLower(strings.TrimSpace(email))
+	if normalized == "" {
+		return ""
+	}
+	return em.BlindIndex("email:" + normalized)
+}
+
+// PhoneBlindIndex returns the keyed hash used for exact phone lookups, or "" for no phone.
+// Formatting and a leading US country code are ignored.
+func (em *EncryptionManager) PhoneBlindIndex(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+	if len(digits) == 11 && strings.HasPrefix(digits, "1") {
+		digits = digits[1:]
+	}
+	if digits == "" {
+		return ""
+	}
+	return em.BlindIndex("phone:" + digits)
+}
+
+// SearchIndex builds a space-separated blind index of the trigrams in the given
+// plaintext values, allowing partial-match lookups without storing plaintext
+func (em *EncryptionManager) SearchIndex(values ...string) string {
+	seen := map[string]bool{}
+	for _, value := range values {
+		for _, gram := range SearchGrams(value) {
+			seen[em.BlindIndex(gram)] = true
+		}
+	}
+
+	hashes := make([]string, 0, len(seen))
+	for hash := range seen {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	return strings.Join(hashes, " ")
+}
+
+// SearchQueryHashes returns the blind index hashes every matching record must contain
+func (em *EncryptionManager) SearchQueryHashes(query string) []string {
+	grams := SearchGrams(query)
+	hashes := make([]string, len(grams))
+	for i, gram := range grams {
+		hashes[i] = em.BlindIndex(gram)
+	}
+	return hashes
+}
+
+// SearchWords splits a value into lowercase alphanumeric words. Values that look
+// like phone numbers also yield their digits joined together, so "(555) 123-4567"
+// can be found by "5551234".
+func SearchWords(value string) []string {
+	words := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, value)
+	if len(digits) >= minPhoneDigits && len(words) > 1 {
+		words = append(words, digits)
+	}
+	return words
+}
+
+// SearchGrams returns the distinct trigrams of every word in a value. Words
+// shorter than a trigram are not indexed.
+func SearchGrams(value string) []string {
+	seen := map[string]bool{}
+	grams := []string{}
+	for _, word := range SearchWords(value) {
+		runes := []rune(word)
+		for i := 0; i+searchGramSize <= len(runes); i++ {
+			gram := string(runes[i : i+searchGramSize])
+			if !seen[gram] {
+				seen[gram] = true
+				grams = append(grams, gram)
+			}
+		}
+	}
+	return grams
+}
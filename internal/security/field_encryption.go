@@ -13,16 +13,22 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"gorm.io/gorm"
 )
 
-// EncryptionManager handles field-level encryption
+// EncryptionManager handles field-level encryption. New values are always encrypted
+// with the current key; values written under a previous key are decrypted with the
+// key named by their key_id, so keys can be rotated without losing old ciphertext.
 type EncryptionManager struct {
-	gcm   cipher.AEAD
-	keyID string
-	db    *gorm.DB
+	mutex    sync.RWMutex
+	gcm      cipher.AEAD
+	keyID    string
+	keys     map[string]cipher.AEAD // Current and previous keys by key ID
+	indexKey []byte                 // HMAC key for blind search indexes
+	db       *gorm.DB
 }
 
 // EncryptionKey represents an encryption key in the database
@@ -36,7 +42,8 @@ type EncryptionKey struct {
 	Description string     `json:"description"`
 }
 
-// EncryptedField represents an encrypted database field
+// EncryptedField represents an encrypted database field. KeyID is the key version
+// the value was encrypted with.
 type EncryptedField struct {
 	KeyID      string `json:"key_id"`
 	Nonce      string `json:"nonce"`
@@ -59,44 +66,108 @@ func NewEncryptionManager(db *gorm.DB) (*EncryptionManager, error) {
 		fmt.Printf("Generated encryption key (set ENCRYPTION_KEY env var): %s\n", encryptionKey)
 	}
 
-	// Decode the key
-	key, err := base64.StdEncoding.DecodeString(encryptionKey)
+	key, gcm, keyID, err := parseEncryptionKey(encryptionKey)
 	if err != nil {
-		return nil, fmt.Errorf("invalid encryption key format: %w", err)
+		return nil, err
+	}
+
+	em := &EncryptionManager{
+		gcm:      gcm,
+		keyID:    keyID,
+		keys:     map[string]cipher.AEAD{keyID: gcm},
+		indexKey: deriveIndexKey(key),
+		db:       db,
+	}
+
+	// Previous keys stay available for decryption until data is re-encrypted
+	previousKeyIDs := []string{}
+	for _, previous := range strings.Split(os.Getenv("ENCRYPTION_PREVIOUS_KEYS"), ",") {
+		if previous = strings.TrimSpace(previous); previous == "" {
+			continue
+		}
+		_, previousGCM, previousKeyID, err := parseEncryptionKey(previous)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key in ENCRYPTION_PREVIOUS_KEYS: %w", err)
+		}
+		if previousKeyID != keyID {
+			em.keys[previousKeyID] = previousGCM
+			previousKeyIDs = append(previousKeyIDs, previousKeyID)
+		}
+	}
+
+	// Store key info in database
+	if err := em.storeKeyInfo(key); err != nil {
+		return nil, fmt.Errorf("failed to store key info: %w", err)
+	}
+	if len(previousKeyIDs) > 0 {
+		db.Model(&EncryptionKey{}).Where("key_id IN ? AND is_active = ?", previousKeyIDs, true).
+			Updates(map[string]interface{}{"is_active": false, "rotated_at": time.Now()})
+	}
+
+	return em, nil
+}
+
+// parseEncryptionKey decodes a base64 AES-256 key and returns its cipher and key ID
+func parseEncryptionKey(encoded string) ([]byte, cipher.AEAD, string, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("invalid encryption key format: %w", err)
 	}
 
 	if len(key) != 32 {
-		return nil, fmt.Errorf("encryption key must be 32 bytes (256 bits)")
+		return nil, nil, "", fmt.Errorf("encryption key must be 32 bytes (256 bits)")
 	}
 
 	// Create AES cipher
 	block, err := aes.NewCipher(key)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
+		return nil, nil, "", fmt.Errorf("failed to create AES cipher: %w", err)
 	}
 
 	// Create GCM mode
 	gcm, err := cipher.NewGCM(block)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create GCM: %w", err)
+		return nil, nil, "", fmt.Errorf("failed to create GCM: %w", err)
 	}
 
 	// Generate key ID
 	keyHash := sha256.Sum256(key)
 	keyID := base64.StdEncoding.EncodeToString(keyHash[:8]) // First 8 bytes as ID
 
-	em := &EncryptionManager{
-		gcm:   gcm,
-		keyID: keyID,
-		db:    db,
-	}
+	return key, gcm, keyID, nil
+}
 
-	// Store key info in database
-	if err := em.storeKeyInfo(key); err != nil {
-		return nil, fmt.Errorf("failed to store key info: %w", err)
+// CurrentKeyID returns the ID of the key new values are encrypted with
+func (em *EncryptionManager) CurrentKeyID() string {
+	em.mutex.RLock()
+	defer em.mutex.RUnlock()
+	return em.keyID
+}
+
+// KeyID returns the ID of the key a value was encrypted with, or "" for plaintext
+func (em *EncryptionManager) KeyID(encrypted EncryptedString) string {
+	var encField EncryptedField
+	if !strings.HasPrefix(string(encrypted), "{") || json.Unmarshal([]byte(encrypted), &encField) != nil {
+		return ""
 	}
+	return encField.KeyID
+}
 
-	return em, nil
+// NeedsReencryption reports whether a stored value is encrypted with a key other
+// than the current one. Legacy plaintext values are left alone.
+func (em *EncryptionManager) NeedsReencryption(encrypted EncryptedString) bool {
+	keyID := em.KeyID(encrypted)
+	return keyID != "" && keyID != em.CurrentKeyID()
+}
+
+// Reencrypt decrypts a value with whichever key it was written under and encrypts
+// it with the current key
+func (em *EncryptionManager) Reencrypt(encrypted EncryptedString) (EncryptedString, error) {
+	plaintext, err := em.Decrypt(encrypted)
+	if err != nil {
+		return "", err
+	}
+	return em.Encrypt(plaintext)
 }
 
 // EncryptedString is a custom type for encrypted string fields
@@ -108,18 +179,22 @@ func (em *EncryptionManager) Encrypt(plaintext string) (EncryptedString, error)
 		return "", nil
 	}
 
+	em.mutex.RLock()
+	gcm, keyID := em.gcm, em.keyID
+	em.mutex.RUnlock()
+
 	// Generate a random nonce
-	nonce := make([]byte, em.gcm.NonceSize())
+	nonce := make([]byte, gcm.NonceSize())
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return "", fmt.Errorf("failed to generate nonce: %w", err)
 	}
 
 	// Encrypt the plaintext
-	ciphertext := em.gcm.Seal(nil, nonce, []byte(plaintext), nil)
+	ciphertext := gcm.Seal(nil, nonce, []byte(plaintext), nil)
 
 	// Create encrypted field structure
 	encField := EncryptedField{
-		KeyID:      em.keyID,
+		KeyID:      keyID,
 		Nonce:      base64.StdEncoding.EncodeToString(nonce),
 		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
 	}
@@ -153,9 +228,12 @@ func (em *EncryptionManager) Decrypt(encrypted EncryptedString) (string, error)
 		return encryptedStr, nil
 	}
 
-	// Verify key ID
-	if encField.KeyID != em.keyID {
-		return "", fmt.Errorf("key ID mismatch: expected %s, got %s", em.keyID, encField.KeyID)
+	// Select the key the value was encrypted with
+	em.mutex.RLock()
+	gcm, exists := em.keys[encField.KeyID]
+	em.mutex.RUnlock()
+	if !exists {
+		return "", fmt.Errorf("unknown key ID %s: add the key to ENCRYPTION_PREVIOUS_KEYS", encField.KeyID)
 	}
 
 	// Decode nonce and ciphertext
@@ -170,7 +248,7 @@ func (em *EncryptionManager) Decrypt(encrypted EncryptedString) (string, error)
 	}
 
 	// Decrypt
-	plaintext, err := em.gcm.Open(nil, nonce, ciphertext, nil)
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to decrypt: %w", err)
 	}
@@ -266,7 +344,7 @@ type EncryptionAuditLog struct {
 func (em *EncryptionManager) LogEncryptionOperation(operation, tableName, fieldName string, recordID, userID uint, ipAddress string, success bool, errorMsg string) {
 	auditLog := &EncryptionAuditLog{
 		Operation: operation,
-		KeyID:     em.keyID,
+		KeyID:     em.CurrentKeyID(),
 		TableName: tableName,
 		FieldName: fieldName,
 		RecordID:  recordID,
@@ -305,8 +383,13 @@ func (em *EncryptionManager) storeKeyInfo(key []byte) error {
 	return em.db.Create(encKey).Error
 }
 
-// RotateKey rotates the encryption key (advanced feature)
+// RotateKey replaces the current key with a generated one for this process. The old
+// key is kept for decryption. Generated keys are not persisted, so deployments should
+// rotate by setting ENCRYPTION_KEY and ENCRYPTION_PREVIOUS_KEYS instead.
 func (em *EncryptionManager) RotateKey() error {
+	em.mutex.Lock()
+	defer em.mutex.Unlock()
+
 	// Mark current key as inactive
 	now := time.Now()
 	if err := em.db.Model(&EncryptionKey{}).Where("key_id = ?", em.keyID).Updates(map[string]interface{}{
@@ -337,9 +420,11 @@ func (em *EncryptionManager) RotateKey() error {
 	keyHash := sha256.Sum256(newKey)
 	newKeyID := base64.StdEncoding.EncodeToString(keyHash[:8])
 
-	// Update manager
+	// Update manager. The blind index key is left unchanged so existing
+	// search indexes stay valid across rotations.
 	em.gcm = gcm
 	em.keyID = newKeyID
+	em.keys[newKeyID] = gcm
 
 	// Store new key info
 	return em.storeKeyInfo(newKey)
@@ -378,7 +463,7 @@ func (em *EncryptionManager) GetEncryptionStatistics() map[string]interface{} {
 		"success_rate":          successRate,
 		"recent_operations_24h": recentOperations,
 		"active_keys":           activeKeys,
-		"current_key_id":        em.keyID,
+		"current_key_id":        em.CurrentKeyID(),
 	}
 }
 
@@ -0,0 +1,140 @@
+package security
+
+import (
+	"html"
+	"net/url"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// HTMLPolicy is an allow-list HTML sanitizer. Only listed tags and their
+// listed attributes are kept; other tags are dropped but their text is kept,
+// except for elements such as script whose content is dropped too.
+type HTMLPolicy struct {
+	allowed    map[string]map[string]bool
+	urlAttrs   map[string]bool
+	urlSchemes map[string]bool
+	dropped    map[string]bool
+}
+
+// NewHTMLPolicy creates a policy allowing the given tags, each with its list
+// of allowed attributes
+func NewHTMLPolicy(tags map[string][]string) *HTMLPolicy {
+	policy := &HTMLPolicy{
+		allowed:    make(map[string]map[string]bool, len(tags)),
+		urlAttrs:   map[string]bool{"href": true, "src": true},
+		urlSchemes: map[string]bool{"http": true, "https": true, "mailto": true},
+		dropped: map[string]bool{
+			"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+			"noscript": true, "template": true, "textarea": true, "title": true, "svg": true, "math": true,
+		},
+	}
+	for tag, attrs := range tags {
+		policy.allowed[tag] = make(map[string]bool, len(attrs))
+		for _, attr := range attrs {
+			policy.allowed[tag][attr] = true
+		}
+	}
+	return policy
+}
+
+// NewMarkdownHTMLPolicy allows the tags the markdown template filter produces
+func NewMarkdownHTMLPolicy() *HTMLPolicy {
+	return NewHTMLPolicy(map[string][]string{
+		"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+		"p": nil, "br": nil, "hr": nil, "blockquote": nil,
+		"strong": nil, "em": nil, "code": nil, "pre": nil,
+		"ul": nil, "ol": nil, "li": nil,
+		"a": {"href", "title"},
+	})
+}
+
+// Sanitize returns input with everything outside the policy removed. Text is
+// re-escaped, unclosed allowed tags are closed and links open without
+// passing a referrer.
+func (p *HTMLPolicy) Sanitize(input string) string {
+	var b strings.Builder
+	var open []string
+	skipping := 0
+
+	tokenizer := xhtml.NewTokenizer(strings.NewReader(input))
+	for {
+		switch tokenizer.Next() {
+		case xhtml.ErrorToken:
+			for i := len(open) - 1; i >= 0; i-- {
+				b.WriteString("</" + open[i] + ">")
+			}
+			return b.String()
+		case xhtml.TextToken:
+			if skipping == 0 {
+				b.WriteString(html.EscapeString(string(tokenizer.Text())))
+			}
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if p.dropped[token.Data] {
+				if token.Type == xhtml.StartTagToken {
+					skipping++
+				}
+				continue
+			}
+			attrs, ok := p.allowed[token.Data]
+			if !ok || skipping > 0 {
+				continue
+			}
+			b.WriteString("<" + token.Data)
+			for _, attr := range token.Attr {
+				if attr.Namespace != "" || !attrs[attr.Key] {
+					continue
+				}
+				if p.urlAttrs[attr.Key] && !p.allowedURL(attr.Val) {
+					continue
+				}
+				b.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+			}
+			if token.Data == "a" {
+				b.WriteString(` rel="nofollow noopener noreferrer"`)
+			}
+			b.WriteString(">")
+			if token.Type == xhtml.StartTagToken && !isVoidElement(token.Data) {
+				open = append(open, token.Data)
+			}
+		case xhtml.EndTagToken:
+			token := tokenizer.Token()
+			if p.dropped[token.Data] {
+				if skipping > 0 {
+					skipping--
+				}
+				continue
+			}
+			// Only close tags that are open, closing any left open inside them
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != token.Data {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					b.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+		}
+	}
+}
+
+// allowedURL reports whether a link target is relative or uses an allowed scheme
+func (p *HTMLPolicy) allowedURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		// Reject scheme-like prefixes url.Parse does not treat as a scheme
+		return !strings.Contains(strings.SplitN(u.Path, "/", 2)[0], ":")
+	}
+	return p.urlSchemes[strings.ToLower(u.Scheme)]
+}
+
+func isVoidElement(tag string) bool {
+	return tag == "br" || tag == "hr" || tag == "img"
+}
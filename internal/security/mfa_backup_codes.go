@@ -0,0 +1,54 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// MFABackupCodeCount is the number of single-use backup codes issued at enrollment
+const MFABackupCodeCount = 10
+
+// GenerateMFABackupCodes creates random backup codes formatted as XXXXX-XXXXX along
+// with their hashes. Only the hashes should be stored; the codes are shown once.
+func GenerateMFABackupCodes(count int) ([]string, []string, error) {
+	codes := make([]string, count)
+	hashes := make([]string, count)
+
+	for i := 0; i < count; i++ {
+		bytes := make([]byte, 8)
+		if _, err := rand.Read(bytes); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate backup code: %w", err)
+		}
+
+		code := base32.StdEncoding.EncodeToString(bytes)[:10]
+		codes[i] = fmt.Sprintf("%s-%s", code[:5], code[5:])
+		hashes[i] = HashMFABackupCode(codes[i])
+	}
+
+	return codes, hashes, nil
+}
+
+// HashMFABackupCode hashes a backup code, ignoring case, spaces, and dashes
+func HashMFABackupCode(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte("mfa-backup-code:" + normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// MatchMFABackupCode returns the index of the stored hash matching the code, or -1.
+// Every hash is compared in constant time so timing does not reveal which, if any, matched.
+func MatchMFABackupCode(hashes []string, code string) int {
+	candidate := []byte(HashMFABackupCode(code))
+	match := -1
+	for i, hash := range hashes {
+		if subtle.ConstantTimeCompare([]byte(hash), candidate) == 1 {
+			match = i
+		}
+	}
+	return match
+}
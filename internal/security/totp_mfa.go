@@ -1,8 +1,6 @@
 package security
 
 import (
-	"crypto/rand"
-	"encoding/base32"
 	"fmt"
 	"image/png"
 	"net/http"
@@ -27,24 +25,13 @@ func NewTOTPManager(db *gorm.DB) *TOTPManager {
 
 // TOTPSecret represents a user's TOTP secret
 type TOTPSecret struct {
-	ID          uint       `json:"id" gorm:"primaryKey"`
-	UserID      uint       `json:"user_id" gorm:"uniqueIndex;not null"`
-	Secret      string     `json:"-" gorm:"not null"`  // Never expose in JSON
-	BackupCodes []string   `json:"-" gorm:"type:json"` // Never expose in JSON
-	IsEnabled   bool       `json:"is_enabled" gorm:"default:false"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	LastUsedAt  *time.Time `json:"last_used_at"`
-}
-
-// BackupCode represents a single-use backup code
-type BackupCode struct {
-	ID        uint       `json:"id" gorm:"primaryKey"`
-	UserID    uint       `json:"user_id" gorm:"not null"`
-	Code      string     `json:"-" gorm:"not null"` // Never expose in JSON
-	IsUsed    bool       `json:"is_used" gorm:"default:false"`
-	UsedAt    *time.Time `json:"used_at"`
-	CreatedAt time.Time  `json:"created_at"`
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"uniqueIndex;not null"`
+	Secret     string     `json:"-" gorm:"not null"` // Never expose in JSON
+	IsEnabled  bool       `json:"is_enabled" gorm:"default:false"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
 }
 
 // DeviceFingerprint represents a trusted device
@@ -74,8 +61,9 @@ type MFAAttempt struct {
 	CreatedAt   time.Time `json:"created_at"`
 }
 
-// GenerateSecret creates a new TOTP secret for a user
-func (tm *TOTPManager) GenerateSecret(userID uint, userEmail string) (*otp.Key, []string, error) {
+// GenerateSecret creates a new TOTP secret for a user. Backup codes are issued
+// separately and stored hashed on the admin user (see GenerateMFABackupCodes).
+func (tm *TOTPManager) GenerateSecret(userID uint, userEmail string) (*otp.Key, error) {
 	// Generate TOTP key
 	key, err := totp.Generate(totp.GenerateOpts{
 		Issuer:      "PropertyHub",
@@ -83,37 +71,21 @@ func (tm *TOTPManager) GenerateSecret(userID uint, userEmail string) (*otp.Key,
 		SecretSize:  32,
 	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to generate TOTP key: %w", err)
-	}
-
-	// Generate backup codes
-	backupCodes, err := tm.generateBackupCodes(8)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to generate backup codes: %w", err)
+		return nil, fmt.Errorf("failed to generate TOTP key: %w", err)
 	}
 
 	// Save to database
 	totpSecret := &TOTPSecret{
-		UserID:      userID,
-		Secret:      key.Secret(),
-		BackupCodes: backupCodes,
-		IsEnabled:   false, // User must verify before enabling
+		UserID:    userID,
+		Secret:    key.Secret(),
+		IsEnabled: false, // User must verify before enabling
 	}
 
 	if err := tm.db.Create(totpSecret).Error; err != nil {
-		return nil, nil, fmt.Errorf("failed to save TOTP secret: %w", err)
-	}
-
-	// Also save individual backup codes for tracking
-	for _, code := range backupCodes {
-		backupCode := &BackupCode{
-			UserID: userID,
-			Code:   code,
-		}
-		tm.db.Create(backupCode)
+		return nil, fmt.Errorf("failed to save TOTP secret: %w", err)
 	}
 
-	return key, backupCodes, nil
+	return key, nil
 }
 
 // VerifyTOTP verifies a TOTP code and enables MFA if first verification
@@ -149,24 +121,13 @@ func (tm *TOTPManager) VerifyTOTP(userID uint, code string, ipAddress, userAgent
 	return valid, nil
 }
 
-// VerifyBackupCode verifies a backup code (single use)
-func (tm *TOTPManager) VerifyBackupCode(userID uint, code string, ipAddress, userAgent string) (bool, error) {
-	var backupCode BackupCode
-	if err := tm.db.Where("user_id = ? AND code = ? AND is_used = false", userID, code).First(&backupCode).Error; err != nil {
-		tm.logMFAAttempt(userID, ipAddress, userAgent, "backup_code", false, "code_not_found_or_used")
-		return false, fmt.Errorf("backup code not found or already used")
+// LogBackupCodeAttempt records a backup code verification attempt
+func (tm *TOTPManager) LogBackupCodeAttempt(userID uint, ipAddress, userAgent string, success bool) {
+	failReason := ""
+	if !success {
+		failReason = "code_not_found_or_used"
 	}
-
-	// Mark code as used
-	now := time.Now()
-	backupCode.IsUsed = true
-	backupCode.UsedAt = &now
-	tm.db.Save(&backupCode)
-
-	// Log successful attempt
-	tm.logMFAAttempt(userID, ipAddress, userAgent, "backup_code", true, "")
-
-	return true, nil
+	tm.logMFAAttempt(userID, ipAddress, userAgent, "backup_code", success, failReason)
 }
 
 // IsMFAEnabled checks if MFA is enabled for a user
@@ -185,48 +146,9 @@ func (tm *TOTPManager) DisableMFA(userID uint) error {
 		return fmt.Errorf("failed to disable TOTP: %w", err)
 	}
 
-	// Mark all backup codes as used
-	now := time.Now()
-	if err := tm.db.Model(&BackupCode{}).Where("user_id = ? AND is_used = false", userID).Updates(map[string]interface{}{
-		"is_used": true,
-		"used_at": now,
-	}).Error; err != nil {
-		return fmt.Errorf("failed to invalidate backup codes: %w", err)
-	}
-
 	return nil
 }
 
-// RegenerateBackupCodes generates new backup codes for a user
-func (tm *TOTPManager) RegenerateBackupCodes(userID uint) ([]string, error) {
-	// Mark existing backup codes as used
-	now := time.Now()
-	tm.db.Model(&BackupCode{}).Where("user_id = ? AND is_used = false", userID).Updates(map[string]interface{}{
-		"is_used": true,
-		"used_at": now,
-	})
-
-	// Generate new backup codes
-	backupCodes, err := tm.generateBackupCodes(8)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate backup codes: %w", err)
-	}
-
-	// Save new backup codes
-	for _, code := range backupCodes {
-		backupCode := &BackupCode{
-			UserID: userID,
-			Code:   code,
-		}
-		tm.db.Create(backupCode)
-	}
-
-	// Update TOTP secret with new backup codes
-	tm.db.Model(&TOTPSecret{}).Where("user_id = ?", userID).Update("backup_codes", backupCodes)
-
-	return backupCodes, nil
-}
-
 // GetQRCode generates a QR code image for TOTP setup
 func (tm *TOTPManager) GetQRCode(key *otp.Key, w http.ResponseWriter) error {
 	// Generate QR code
@@ -248,9 +170,6 @@ func (tm *TOTPManager) GetMFAStatus(userID uint) map[string]interface{} {
 	var totpSecret TOTPSecret
 	mfaEnabled := tm.db.Where("user_id = ? AND is_enabled = true", userID).First(&totpSecret).Error == nil
 
-	var unusedBackupCodes int64
-	tm.db.Model(&BackupCode{}).Where("user_id = ? AND is_used = false", userID).Count(&unusedBackupCodes)
-
 	var trustedDevices int64
 	tm.db.Model(&DeviceFingerprint{}).Where("user_id = ? AND is_trusted = true", userID).Count(&trustedDevices)
 
@@ -259,32 +178,12 @@ func (tm *TOTPManager) GetMFAStatus(userID uint) map[string]interface{} {
 
 	return map[string]interface{}{
 		"mfa_enabled":         mfaEnabled,
-		"backup_codes_count":  unusedBackupCodes,
 		"trusted_devices":     trustedDevices,
 		"recent_attempts_24h": recentAttempts,
 		"last_used":           totpSecret.LastUsedAt,
 	}
 }
 
-// generateBackupCodes creates random backup codes
-func (tm *TOTPManager) generateBackupCodes(count int) ([]string, error) {
-	codes := make([]string, count)
-
-	for i := 0; i < count; i++ {
-		// Generate 8 random bytes
-		bytes := make([]byte, 8)
-		if _, err := rand.Read(bytes); err != nil {
-			return nil, err
-		}
-
-		// Convert to base32 and format
-		code := base32.StdEncoding.EncodeToString(bytes)[:10] // Take first 10 chars
-		codes[i] = fmt.Sprintf("%s-%s", code[:5], code[5:])   // Format as XXXXX-XXXXX
-	}
-
-	return codes, nil
-}
-
 // logMFAAttempt logs an MFA authentication attempt
 func (tm *TOTPManager) logMFAAttempt(userID uint, ipAddress, userAgent, attemptType string, success bool, failReason string) {
 	attempt := &MFAAttempt{
@@ -6,6 +6,8 @@ import (
 	"math/rand"
 	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // AbandonmentRecoveryService handles sophisticated abandonment recovery campaigns
@@ -18,6 +20,14 @@ type AbandonmentRecoveryService struct {
 	recoveryTemplates map[string]*RecoveryTemplate
 	recoverySequences map[string]*RecoverySequence
 	activeRecoveries  map[string]*ActiveRecovery
+
+	// Reminders for applications started and not submitted; see application_recovery.go
+	db          *gorm.DB
+	safety      *LeadSafetyFilter
+	appEmail    alertEmailSender
+	appSMS      alertSMSSender
+	appSettings ApplicationRecoverySettings
+	now         func() time.Time
 }
 
 type RecoveryTemplate struct {
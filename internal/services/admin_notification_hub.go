@@ -2,6 +2,7 @@ package services
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -12,21 +13,34 @@ import (
 )
 
 type AdminNotificationHub struct {
-	clients    map[*websocket.Conn]bool
+	clients    map[*websocket.Conn]string // Connection to the admin user ID it belongs to
 	broadcast  chan *models.AdminNotification
-	register   chan *websocket.Conn
+	register   chan adminConnection
 	unregister chan *websocket.Conn
+	direct     chan adminMessage
 	mu         sync.RWMutex
 	db         *gorm.DB
+
+	email alertEmailSender
+	sms   alertSMSSender
+	now   func() time.Time
+}
+
+// adminConnection is a notification WebSocket and the admin it was opened by
+type adminConnection struct {
+	conn        *websocket.Conn
+	adminUserID string
 }
 
 func NewAdminNotificationHub(db *gorm.DB) *AdminNotificationHub {
 	hub := &AdminNotificationHub{
-		clients:    make(map[*websocket.Conn]bool),
+		clients:    make(map[*websocket.Conn]string),
 		broadcast:  make(chan *models.AdminNotification, 256),
-		register:   make(chan *websocket.Conn),
+		register:   make(chan adminConnection),
 		unregister: make(chan *websocket.Conn),
+		direct:     make(chan adminMessage, 64),
 		db:         db,
+		now:        time.Now,
 	}
 	go hub.run()
 	return hub
@@ -37,7 +51,7 @@ func (h *AdminNotificationHub) run() {
 		select {
 		case client := <-h.register:
 			h.mu.Lock()
-			h.clients[client] = true
+			h.clients[client.conn] = client.adminUserID
 			h.mu.Unlock()
 			log.Printf("🔔 Admin notification client registered. Total: %d", len(h.clients))
 
@@ -51,22 +65,55 @@ func (h *AdminNotificationHub) run() {
 			log.Printf("🔔 Admin notification client unregistered. Total: %d", len(h.clients))
 
 		case notification := <-h.broadcast:
-			h.mu.RLock()
-			for client := range h.clients {
-				err := client.WriteJSON(notification.ToDict())
-				if err != nil {
-					log.Printf("❌ Error sending notification to client: %v", err)
-					client.Close()
-					delete(h.clients, client)
+			prefs := h.preferencesFor(notification.Type)
+			now := h.now()
+			h.mu.Lock()
+			for client, adminUserID := range h.clients {
+				pref := resolvePreference(prefs, adminUserID, notification.Type)
+				if !pref.InApp {
+					continue
+				}
+				if quietUntil := pref.QuietUntil(now); !quietUntil.IsZero() && !models.IsUrgentNotification(notification.Priority) {
+					h.queue(notification, adminUserID, models.NotificationChannelInApp, quietUntil)
+					continue
 				}
+				h.write(client, notification.ToDict())
 			}
-			h.mu.RUnlock()
+			h.mu.Unlock()
+			go h.deliverExternal(notification, prefs, now)
+
+		case direct := <-h.direct:
+			h.mu.Lock()
+			for client, adminUserID := range h.clients {
+				if adminUserID == direct.adminUserID {
+					h.write(client, direct.message)
+				}
+			}
+			h.mu.Unlock()
 		}
 	}
 }
 
-func (h *AdminNotificationHub) Register(conn *websocket.Conn) {
-	h.register <- conn
+// adminMessage is a WebSocket message for one admin's connections
+type adminMessage struct {
+	adminUserID string
+	message     interface{}
+}
+
+// write sends a message to one connection, dropping it on error. The caller
+// holds h.mu.
+func (h *AdminNotificationHub) write(client *websocket.Conn, message interface{}) {
+	if err := client.WriteJSON(message); err != nil {
+		log.Printf("❌ Error sending notification to client: %v", err)
+		client.Close()
+		delete(h.clients, client)
+	}
+}
+
+// Register adds a notification WebSocket opened by an admin; its in-app
+// notifications follow that admin's preferences
+func (h *AdminNotificationHub) Register(conn *websocket.Conn, adminUserID string) {
+	h.register <- adminConnection{conn: conn, adminUserID: adminUserID}
 }
 
 func (h *AdminNotificationHub) Unregister(conn *websocket.Conn) {
@@ -228,6 +275,164 @@ func (h *AdminNotificationHub) SendMultiplePropertiesAlert(leadName string, prop
 	h.Broadcast(notification)
 }
 
+func (h *AdminNotificationHub) SendValuationChangeAlert(event *models.PriceChangeEvent) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"price_change_event_id": event.ID,
+		"property_id":           event.PropertyID,
+		"property_address":      event.PropertyAddress,
+		"old_value":             event.OldPrice,
+		"new_value":             event.NewPrice,
+		"change_amount":         event.ChangeAmount,
+		"change_percent":        event.ChangePercent,
+		"direction":             event.Direction,
+	})
+
+	title := "📈 Valuation Increased"
+	priority := "normal"
+	if event.Direction == models.PriceDirectionDown {
+		title = "📉 Valuation Dropped"
+		priority = "high"
+	}
+
+	notification := &models.AdminNotification{
+		Type:     "valuation_change",
+		Title:    title,
+		Message:  fmt.Sprintf("%s re-valued at $%.0f (%+.1f%%)", event.PropertyAddress, event.NewPrice, event.ChangePercent),
+		Priority: priority,
+		Data:     data,
+	}
+
+	h.Broadcast(notification)
+}
+
+func (h *AdminNotificationHub) SendApplicationStatusAlert(application *models.ApplicationNumber, propertyAddress string, statusLog models.ApplicationStatusLog) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"application_id":   application.ID,
+		"application_name": application.ApplicationName,
+		"property_address": propertyAddress,
+		"previous_status":  statusLog.PreviousStatus,
+		"new_status":       statusLog.NewStatus,
+		"changed_by":       statusLog.ChangedBy,
+		"reason":           statusLog.ChangeReason,
+	})
+
+	title := "🔎 Application Screening Started"
+	priority := "normal"
+	switch statusLog.NewStatus {
+	case models.AppStatusApproved:
+		title = "✅ Application Approved"
+		priority = "high"
+	case models.AppStatusDenied:
+		title = "❌ Application Denied"
+	case models.AppStatusCancelled:
+		title = "🚫 Application Cancelled"
+	}
+
+	notification := &models.AdminNotification{
+		Type:     "application_status_changed",
+		Title:    title,
+		Message:  fmt.Sprintf("%s for %s moved from %s to %s by %s", application.ApplicationName, propertyAddress, statusLog.PreviousStatus, statusLog.NewStatus, statusLog.ChangedBy),
+		Priority: priority,
+		Data:     data,
+	}
+
+	h.Broadcast(notification)
+}
+
+func (h *AdminNotificationHub) SendClosingStageAlert(history *models.ClosingPipelineStageHistory, propertyAddress string) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"closing_pipeline_id": history.ClosingPipelineID,
+		"property_address":    propertyAddress,
+		"from_status":         history.FromStatus,
+		"to_status":           history.ToStatus,
+		"actor":               history.Actor,
+	})
+
+	title := "🏁 Closing Stage Updated"
+	priority := "normal"
+	if history.ToStatus == models.ClosingStageCompleted {
+		title = "🎉 Closing Completed"
+		priority = "high"
+	}
+
+	notification := &models.AdminNotification{
+		Type:     "closing_stage_changed",
+		Title:    title,
+		Message:  fmt.Sprintf("%s moved from %s to %s by %s", propertyAddress, history.FromStatus, history.ToStatus, history.Actor),
+		Priority: priority,
+		Data:     data,
+	}
+
+	h.Broadcast(notification)
+}
+
+// SendTaskAssignedAlert tells the assigned agent's open connections about a
+// command center task; a task for the whole team is broadcast
+func (h *AdminNotificationHub) SendTaskAssignedAlert(task *models.Task) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"task_id":     task.ID,
+		"lead_id":     task.LeadID,
+		"assigned_to": task.AssignedTo,
+		"due_at":      task.DueAt,
+	})
+
+	priority := "normal"
+	if task.Priority >= 8 {
+		priority = "high"
+	}
+
+	notification := &models.AdminNotification{
+		Type:     "task_assigned",
+		Title:    "✅ New Task",
+		Message:  fmt.Sprintf("%s (due %s)", task.Title, task.DueAt.Format("Jan 2 3:04 PM")),
+		Priority: priority,
+		Data:     data,
+	}
+
+	if task.AssignedTo == "" {
+		h.Broadcast(notification)
+		return
+	}
+	if err := h.db.Create(notification).Error; err != nil {
+		log.Printf("❌ Failed to save notification: %v", err)
+		return
+	}
+	select {
+	case h.direct <- adminMessage{adminUserID: task.AssignedTo, message: notification.ToDict()}:
+	default:
+		// The hub is backed up; the agent sees the task when they next load
+	}
+	log.Printf("📢 Task %d assigned to %s: %s", task.ID, task.AssignedTo, task.Title)
+}
+
+// SendLeadAssignedAlert tells an agent's open connections about a lead
+// assigned to them
+func (h *AdminNotificationHub) SendLeadAssignedAlert(lead *models.Lead, assignment *models.LeadAssignment) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"lead_id":  lead.ID,
+		"agent_id": assignment.AgentID,
+		"strategy": assignment.Strategy,
+		"reason":   assignment.Reason,
+	})
+
+	notification := &models.AdminNotification{
+		Type:     "lead_assigned",
+		Title:    "👤 New Lead Assigned",
+		Message:  fmt.Sprintf("%s %s has been assigned to you", lead.FirstName, lead.LastName),
+		Priority: "high",
+		Data:     data,
+	}
+	if err := h.db.Create(notification).Error; err != nil {
+		log.Printf("❌ Failed to save notification: %v", err)
+		return
+	}
+	select {
+	case h.direct <- adminMessage{adminUserID: assignment.AgentID, message: notification.ToDict()}:
+	default:
+		// The hub is backed up; the agent sees the lead when they next load
+	}
+}
+
 func (h *AdminNotificationHub) GetRecentNotifications(limit int) ([]models.AdminNotification, error) {
 	var notifications []models.AdminNotification
 	err := h.db.Order("created_at DESC").Limit(limit).Find(&notifications).Error
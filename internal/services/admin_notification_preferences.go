@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// queuedNotificationBatch caps how many queued notifications one run delivers
+const queuedNotificationBatch = 200
+
+// SetNotifiers sets the services email and SMS notifications are delivered
+// through. Either may be nil, in which case that channel is skipped.
+func (h *AdminNotificationHub) SetNotifiers(email *EmailService, sms *SMSService) {
+	// Avoid storing typed nils so the channel checks in send work
+	if email != nil {
+		h.email = email
+	}
+	if sms != nil {
+		h.sms = sms
+	}
+}
+
+// GetPreferences returns an admin's stored preferences. Types without a
+// stored preference fall back to the "*" preference, then to in-app only.
+func (h *AdminNotificationHub) GetPreferences(adminUserID string) ([]models.NotificationPreference, error) {
+	var prefs []models.NotificationPreference
+	err := h.db.Where("admin_user_id = ?", adminUserID).Order("event_type").Find(&prefs).Error
+	return prefs, err
+}
+
+// SavePreferences creates or replaces an admin's preferences by event type.
+// Event types not in prefs keep their stored preference.
+func (h *AdminNotificationHub) SavePreferences(adminUserID string, prefs []models.NotificationPreference) ([]models.NotificationPreference, error) {
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		for i := range prefs {
+			prefs[i].ID = 0
+			prefs[i].AdminUserID = adminUserID
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "admin_user_id"}, {Name: "event_type"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"in_app", "email", "sms", "sms_phone", "quiet_hours_start", "quiet_hours_end", "timezone", "updated_at",
+				}),
+			}).Create(&prefs[i]).Error
+			if err != nil {
+				return fmt.Errorf("failed to save %s preference: %w", prefs[i].EventType, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return h.GetPreferences(adminUserID)
+}
+
+// preferencesFor loads every admin's preferences that apply to a
+// notification type, keyed by admin and event type. On error every admin
+// gets the default preference.
+func (h *AdminNotificationHub) preferencesFor(eventType string) map[string]map[string]models.NotificationPreference {
+	var rows []models.NotificationPreference
+	if err := h.db.Where("event_type IN ?", []string{eventType, models.NotificationEventTypeDefault}).Find(&rows).Error; err != nil {
+		log.Printf("⚠️ Failed to load notification preferences, delivering in-app only: %v", err)
+		return nil
+	}
+	prefs := make(map[string]map[string]models.NotificationPreference)
+	for _, row := range rows {
+		if prefs[row.AdminUserID] == nil {
+			prefs[row.AdminUserID] = make(map[string]models.NotificationPreference)
+		}
+		prefs[row.AdminUserID][row.EventType] = row
+	}
+	return prefs
+}
+
+// resolvePreference picks an admin's preference for a type, falling back to
+// their "*" preference and then the default
+func resolvePreference(prefs map[string]map[string]models.NotificationPreference, adminUserID, eventType string) models.NotificationPreference {
+	if pref, ok := prefs[adminUserID][eventType]; ok {
+		return pref
+	}
+	if pref, ok := prefs[adminUserID][models.NotificationEventTypeDefault]; ok {
+		return pref
+	}
+	return models.DefaultNotificationPreference(adminUserID, eventType)
+}
+
+// queue holds a notification for an admin and channel until deliverAfter
+func (h *AdminNotificationHub) queue(notification *models.AdminNotification, adminUserID, channel string, deliverAfter time.Time) {
+	queued := models.QueuedNotification{
+		NotificationID: notification.ID,
+		AdminUserID:    adminUserID,
+		Channel:        channel,
+		DeliverAfter:   deliverAfter,
+		Status:         models.QueuedNotificationPending,
+	}
+	if err := h.db.Create(&queued).Error; err != nil {
+		log.Printf("❌ Failed to queue %s notification %d for %s: %v", channel, notification.ID, adminUserID, err)
+	}
+}
+
+// deliverExternal emails and texts a notification to the admins whose
+// preferences ask for it, queuing it for admins in quiet hours
+func (h *AdminNotificationHub) deliverExternal(notification *models.AdminNotification, prefs map[string]map[string]models.NotificationPreference, now time.Time) {
+	urgent := models.IsUrgentNotification(notification.Priority)
+	for adminUserID := range prefs {
+		pref := resolvePreference(prefs, adminUserID, notification.Type)
+		quietUntil := pref.QuietUntil(now)
+		for channel, enabled := range map[string]bool{
+			models.NotificationChannelEmail: pref.Email,
+			models.NotificationChannelSMS:   pref.SMS,
+		} {
+			if !enabled {
+				continue
+			}
+			if !quietUntil.IsZero() && !urgent {
+				h.queue(notification, adminUserID, channel, quietUntil)
+				continue
+			}
+			if err := h.send(notification, pref, channel); err != nil {
+				log.Printf("⚠️ Failed to %s notification %d to %s: %v", channel, notification.ID, adminUserID, err)
+			}
+		}
+	}
+}
+
+// send delivers a notification to an admin by email or SMS
+func (h *AdminNotificationHub) send(notification *models.AdminNotification, pref models.NotificationPreference, channel string) error {
+	metadata := map[string]interface{}{"notification_id": notification.ID, "notification_type": notification.Type}
+	switch channel {
+	case models.NotificationChannelEmail:
+		if h.email == nil {
+			return fmt.Errorf("email is not configured")
+		}
+		var admin models.AdminUser
+		if err := h.db.Select("id", "email").Where("id = ?", pref.AdminUserID).First(&admin).Error; err != nil {
+			return fmt.Errorf("failed to look up admin email: %w", err)
+		}
+		body := fmt.Sprintf("<p><strong>%s</strong></p><p>%s</p>", html.EscapeString(notification.Title), html.EscapeString(notification.Message))
+		return h.email.SendEmail(admin.Email, notification.Title, body, metadata)
+	case models.NotificationChannelSMS:
+		if h.sms == nil {
+			return fmt.Errorf("SMS is not configured")
+		}
+		if pref.SMSPhone == "" {
+			return fmt.Errorf("no SMS phone in %s preference", pref.EventType)
+		}
+		return h.sms.SendSMS(pref.SMSPhone, notification.Title+": "+notification.Message, metadata)
+	}
+	return fmt.Errorf("unknown channel %q", channel)
+}
+
+// StartQueuedDelivery runs DeliverQueued every interval until ctx is cancelled
+func (h *AdminNotificationHub) StartQueuedDelivery(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 Queued notification delivery stopped")
+				return
+			case <-ticker.C:
+				if sent, err := h.DeliverQueued(); err != nil {
+					log.Printf("⚠️ Failed to deliver queued notifications: %v", err)
+				} else if sent > 0 {
+					log.Printf("🔔 Delivered %d queued notifications", sent)
+				}
+			}
+		}
+	}()
+
+	log.Printf("🕐 Queued notification delivery started (every %v)", interval)
+}
+
+// DeliverQueued delivers queued notifications whose quiet hours have ended,
+// returning how many were sent. In-app notifications go to the admin's open
+// connections; admins who are offline see them in the notification list.
+func (h *AdminNotificationHub) DeliverQueued() (int, error) {
+	var due []models.QueuedNotification
+	err := h.db.Where("status = ? AND deliver_after <= ?", models.QueuedNotificationPending, h.now()).
+		Order("deliver_after").Limit(queuedNotificationBatch).Find(&due).Error
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, queued := range due {
+		var notification models.AdminNotification
+		err := h.db.First(&notification, queued.NotificationID).Error
+		if err == nil {
+			if queued.Channel == models.NotificationChannelInApp {
+				h.direct <- adminMessage{adminUserID: queued.AdminUserID, message: notification.ToDict()}
+			} else {
+				pref := resolvePreference(h.preferencesFor(notification.Type), queued.AdminUserID, notification.Type)
+				err = h.send(&notification, pref, queued.Channel)
+			}
+		}
+
+		updates := map[string]interface{}{"status": models.QueuedNotificationSent, "sent_at": h.now(), "error": ""}
+		if err != nil {
+			updates = map[string]interface{}{"status": models.QueuedNotificationFailed, "error": err.Error()}
+		} else {
+			sent++
+		}
+		if err := h.db.Model(&models.QueuedNotification{}).Where("id = ?", queued.ID).Updates(updates).Error; err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
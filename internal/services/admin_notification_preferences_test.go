@@ -0,0 +1,74 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+)
+
+func TestAdminNotificationHub_QueuesNonUrgentEmailDuringQuietHours(t *testing.T) {
+	db := newAdminUsersTestDB(t)
+	if err := db.AutoMigrate(&models.AdminNotification{}, &models.NotificationPreference{}, &models.QueuedNotification{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	db.Exec(`INSERT INTO admin_users (id, username, email) VALUES ('admin-1', 'agent', 'agent@example.com')`)
+
+	hub := NewAdminNotificationHub(db)
+	email := &recordingAlertEmailSender{sent: make(chan string, 4)}
+	hub.email = email
+	// 23:00 UTC, inside 22:00-07:00 quiet hours
+	now := time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC)
+	hub.now = func() time.Time { return now }
+
+	_, err := hub.SavePreferences("admin-1", []models.NotificationPreference{
+		{EventType: models.NotificationEventTypeDefault, InApp: true, Email: true, QuietHoursStart: 22, QuietHoursEnd: 7, Timezone: "UTC"},
+		{EventType: "property_saved", InApp: false},
+	})
+	if err != nil {
+		t.Fatalf("SavePreferences failed: %v", err)
+	}
+
+	// Types without their own preference use the admin's default
+	prefs := hub.preferencesFor("booking_created")
+	if pref := resolvePreference(prefs, "admin-1", "booking_created"); !pref.Email {
+		t.Fatalf("Expected the default preference, got %+v", pref)
+	}
+	if pref := resolvePreference(hub.preferencesFor("property_saved"), "admin-1", "property_saved"); pref.InApp || pref.Email {
+		t.Errorf("Expected the property_saved preference to turn off delivery, got %+v", pref)
+	}
+	if pref := resolvePreference(prefs, "admin-2", "booking_created"); !pref.InApp || pref.Email {
+		t.Errorf("Expected admins without preferences to get in-app only, got %+v", pref)
+	}
+
+	normal := &models.AdminNotification{Type: "booking_created", Title: "New Booking", Priority: "normal"}
+	urgent := &models.AdminNotification{Type: "booking_created", Title: "Hot Lead", Priority: "high"}
+	db.Create(normal)
+	db.Create(urgent)
+
+	hub.deliverExternal(normal, prefs, now)
+	hub.deliverExternal(urgent, prefs, now)
+	if len(email.sent) != 1 {
+		t.Fatalf("Expected only the urgent notification to be emailed during quiet hours, got %d", len(email.sent))
+	}
+	<-email.sent
+
+	var queued models.QueuedNotification
+	if err := db.Where("notification_id = ?", normal.ID).First(&queued).Error; err != nil {
+		t.Fatalf("Expected the normal notification to be queued: %v", err)
+	}
+	if want := time.Date(2026, 10, 17, 7, 0, 0, 0, time.UTC); !queued.DeliverAfter.Equal(want) {
+		t.Errorf("Expected delivery at %v, got %v", want, queued.DeliverAfter)
+	}
+
+	if sent, err := hub.DeliverQueued(); err != nil || sent != 0 {
+		t.Fatalf("Expected nothing due before quiet hours end, got %d (%v)", sent, err)
+	}
+	now = now.Add(8 * time.Hour)
+	if sent, err := hub.DeliverQueued(); err != nil || sent != 1 {
+		t.Fatalf("Expected the queued email after quiet hours, got %d (%v)", sent, err)
+	}
+	if to := <-email.sent; to != "agent@example.com" {
+		t.Errorf("Expected the admin's email, got %q", to)
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// unreadBy scopes AdminNotification queries to notifications an admin has not read
+func unreadBy(adminUserID string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		read := db.Session(&gorm.Session{NewDB: true}).Model(&models.AdminNotificationRead{}).
+			Select("notification_id").Where("admin_user_id = ?", adminUserID)
+		return db.Where("id NOT IN (?)", read)
+	}
+}
+
+// ListNotificationsFor returns recent notifications, newest first, with read
+// and read_at set for one admin
+func (h *AdminNotificationHub) ListNotificationsFor(adminUserID string, unreadOnly bool, limit int) ([]map[string]interface{}, error) {
+	query := h.db.Order("created_at DESC").Limit(limit)
+	if unreadOnly {
+		query = query.Scopes(unreadBy(adminUserID))
+	}
+	var notifications []models.AdminNotification
+	if err := query.Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(notifications))
+	for i, notification := range notifications {
+		ids[i] = notification.ID
+	}
+	var reads []models.AdminNotificationRead
+	if len(ids) > 0 && !unreadOnly {
+		if err := h.db.Where("admin_user_id = ? AND notification_id IN ?", adminUserID, ids).Find(&reads).Error; err != nil {
+			return nil, err
+		}
+	}
+	readAt := make(map[uint]time.Time, len(reads))
+	for _, read := range reads {
+		readAt[read.NotificationID] = read.ReadAt
+	}
+
+	results := make([]map[string]interface{}, len(notifications))
+	for i, notification := range notifications {
+		result := notification.ToDict()
+		at, read := readAt[notification.ID]
+		result["read"] = read
+		result["read_at"] = nil
+		if read {
+			result["read_at"] = at
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// UnreadCountFor returns how many notifications one admin has not read
+func (h *AdminNotificationHub) UnreadCountFor(adminUserID string) (int64, error) {
+	var count int64
+	err := h.db.Model(&models.AdminNotification{}).Scopes(unreadBy(adminUserID)).Count(&count).Error
+	return count, err
+}
+
+// MarkReadFor marks notifications read for one admin; no IDs marks every
+// unread notification. The admin's open connections are sent the new
+// unread count so other tabs update their badge.
+func (h *AdminNotificationHub) MarkReadFor(adminUserID string, ids []uint) error {
+	if len(ids) == 0 {
+		if err := h.db.Model(&models.AdminNotification{}).Scopes(unreadBy(adminUserID)).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+	}
+	if len(ids) > 0 {
+		now := h.now()
+		reads := make([]models.AdminNotificationRead, len(ids))
+		for i, id := range ids {
+			reads[i] = models.AdminNotificationRead{NotificationID: id, AdminUserID: adminUserID, ReadAt: now}
+		}
+		err := h.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(reads, 500).Error
+		if err != nil {
+			return err
+		}
+	}
+	h.PushUnreadCount(adminUserID)
+	return nil
+}
+
+// PushUnreadCount sends an admin's open connections their unread count as
+// {"type": "unread_count", "unread_count": n}
+func (h *AdminNotificationHub) PushUnreadCount(adminUserID string) {
+	count, err := h.UnreadCountFor(adminUserID)
+	if err != nil {
+		return
+	}
+	select {
+	case h.direct <- adminMessage{
+		adminUserID: adminUserID,
+		message:     map[string]interface{}{"type": "unread_count", "unread_count": count},
+	}:
+	default:
+		// The hub is backed up; clients resync the count when they next load
+	}
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Refresh token revocation reasons
+const (
+	RefreshRevokedLogout = "logout"
+	RefreshRevokedReuse  = "reuse_detected"
+)
+
+var (
+	// ErrRefreshTokenInvalid is returned for unknown, expired, or revoked refresh tokens
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	// ErrRefreshTokenReused is returned when an already-rotated refresh token is presented again
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+)
+
+// AdminRefreshTokenService issues and rotates admin refresh tokens. Each refresh
+// consumes the presented token and issues a replacement in the same family;
+// presenting a consumed token again revokes the whole family, since it means
+// the token was copied.
+type AdminRefreshTokenService struct {
+	db  *gorm.DB
+	ttl time.Duration
+}
+
+// NewAdminRefreshTokenService creates a refresh token service
+func NewAdminRefreshTokenService(db *gorm.DB, ttl time.Duration) *AdminRefreshTokenService {
+	if ttl <= 0 {
+		ttl = 30 * 24 * time.Hour
+	}
+	return &AdminRefreshTokenService{db: db, ttl: ttl}
+}
+
+// TTL returns how long issued refresh tokens are valid
+func (s *AdminRefreshTokenService) TTL() time.Duration {
+	return s.ttl
+}
+
+// Issue creates a refresh token for a new login, starting a new token family
+func (s *AdminRefreshTokenService) Issue(adminUserID, ipAddress, userAgent string) (string, error) {
+	token, _, err := s.issue(s.db, adminUserID, uuid.New().String(), ipAddress, userAgent)
+	return token, err
+}
+
+// Rotate consumes a refresh token and issues its replacement, returning the
+// new token and the stored record it was issued from
+func (s *AdminRefreshTokenService) Rotate(token, ipAddress, userAgent string) (string, *models.AdminRefreshToken, error) {
+	var current models.AdminRefreshToken
+	if err := s.db.Where("token_hash = ?", hashRefreshToken(token)).First(&current).Error; err != nil {
+		return "", nil, ErrRefreshTokenInvalid
+	}
+
+	if current.RotatedAt != nil {
+		s.revokeReusedFamily(&current, ipAddress)
+		return "", nil, ErrRefreshTokenReused
+	}
+	if current.RevokedAt != nil || time.Now().After(current.ExpiresAt) {
+		return "", nil, ErrRefreshTokenInvalid
+	}
+
+	var newToken string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var replacement *models.AdminRefreshToken
+		var err error
+		newToken, replacement, err = s.issue(tx, current.AdminUserID, current.FamilyID, ipAddress, userAgent)
+		if err != nil {
+			return err
+		}
+
+		// Only one concurrent refresh may consume the token
+		now := time.Now()
+		result := tx.Model(&models.AdminRefreshToken{}).
+			Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", current.ID).
+			Updates(map[string]interface{}{"rotated_at": now, "replaced_by_id": replacement.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+		return nil
+	})
+	if errors.Is(err, ErrRefreshTokenReused) {
+		s.revokeReusedFamily(&current, ipAddress)
+		return "", nil, err
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	return newToken, &current, nil
+}
+
+// Revoke revokes the family of the given refresh token, e.g. on logout
+func (s *AdminRefreshTokenService) Revoke(token string) error {
+	var current models.AdminRefreshToken
+	if err := s.db.Where("token_hash = ?", hashRefreshToken(token)).First(&current).Error; err != nil {
+		return ErrRefreshTokenInvalid
+	}
+	return s.RevokeFamily(current.FamilyID, RefreshRevokedLogout)
+}
+
+// RevokeFamily revokes every unrevoked token in a family
+func (s *AdminRefreshTokenService) RevokeFamily(familyID, reason string) error {
+	return s.db.Model(&models.AdminRefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason}).Error
+}
+
+// CleanupExpired deletes refresh tokens that expired before the cutoff
+func (s *AdminRefreshTokenService) CleanupExpired(before time.Time) (int64, error) {
+	result := s.db.Where("expires_at < ?", before).Delete(&models.AdminRefreshToken{})
+	return result.RowsAffected, result.Error
+}
+
+func (s *AdminRefreshTokenService) issue(db *gorm.DB, adminUserID, familyID, ipAddress, userAgent string) (string, *models.AdminRefreshToken, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	record := &models.AdminRefreshToken{
+		AdminUserID: adminUserID,
+		FamilyID:    familyID,
+		TokenHash:   hashRefreshToken(token),
+		ExpiresAt:   time.Now().Add(s.ttl),
+		IPAddress:   ipAddress,
+		UserAgent:   userAgent,
+	}
+	if err := db.Create(record).Error; err != nil {
+		return "", nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return token, record, nil
+}
+
+// revokeReusedFamily treats reuse of a consumed token as a compromise and
+// revokes every token descended from the same login
+func (s *AdminRefreshTokenService) revokeReusedFamily(token *models.AdminRefreshToken, ipAddress string) {
+	log.Printf("🚨 Refresh token reuse detected for admin %s (family %s, from %s) - revoking session",
+		token.AdminUserID, token.FamilyID, ipAddress)
+	if err := s.RevokeFamily(token.FamilyID, RefreshRevokedReuse); err != nil {
+		log.Printf("⚠️ Failed to revoke refresh token family %s: %v", token.FamilyID, err)
+	}
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAdminRefreshToken_RotationRevokesFamilyOnReuse(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AdminRefreshToken{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	service := NewAdminRefreshTokenService(db, time.Hour)
+	first, err := service.Issue("admin-1", "127.0.0.1", "test")
+	if err != nil {
+		t.Fatalf("Failed to issue refresh token: %v", err)
+	}
+
+	var count int64
+	db.Model(&models.AdminRefreshToken{}).Where("token_hash = ?", first).Count(&count)
+	if count != 0 {
+		t.Fatal("Refresh token should not be stored in plaintext")
+	}
+
+	second, previous, err := service.Rotate(first, "127.0.0.1", "test")
+	if err != nil || second == "" || second == first {
+		t.Fatalf("Expected a new refresh token, got %q (%v)", second, err)
+	}
+	if previous.AdminUserID != "admin-1" {
+		t.Errorf("Expected rotated token to belong to admin-1, got %s", previous.AdminUserID)
+	}
+
+	// Replaying the consumed token is a compromise signal
+	if _, _, err := service.Rotate(first, "10.0.0.9", "attacker"); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("Expected reuse to be detected, got %v", err)
+	}
+
+	// The legitimate successor is revoked along with the rest of the family
+	if _, _, err := service.Rotate(second, "127.0.0.1", "test"); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("Expected successor token to be revoked, got %v", err)
+	}
+
+	third, err := service.Issue("admin-1", "127.0.0.1", "test")
+	if err != nil {
+		t.Fatalf("Failed to issue refresh token: %v", err)
+	}
+	if err := service.Revoke(third); err != nil {
+		t.Fatalf("Failed to revoke on logout: %v", err)
+	}
+	if _, _, err := service.Rotate(third, "127.0.0.1", "test"); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("Expected logged-out token to be rejected, got %v", err)
+	}
+}
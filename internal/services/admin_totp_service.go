@@ -0,0 +1,171 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"gorm.io/gorm"
+)
+
+// adminTOTPIssuer names the account in authenticator apps
+const adminTOTPIssuer = "PropertyHub Admin"
+
+// adminTOTPReplayWindow covers the codes totp.Validate accepts (the current
+// 30 second step and one either side)
+const adminTOTPReplayWindow = 90 * time.Second
+
+// Admin TOTP errors
+var (
+	ErrMFAAlreadyEnabled = errors.New("TOTP is already enabled for this admin")
+	ErrMFANotEnrolled    = errors.New("no pending TOTP enrollment for this admin")
+)
+
+// AdminTOTPService enrolls admin users in TOTP and verifies their codes. The
+// secret is stored encrypted on the AdminUser record and is only usable once
+// the admin has confirmed a code from their authenticator app.
+type AdminTOTPService struct {
+	db                *gorm.DB
+	encryptionManager *security.EncryptionManager
+	now               func() time.Time
+
+	mutex    sync.Mutex
+	lastUsed map[string]adminTOTPUse // admin ID -> last accepted code
+}
+
+// adminTOTPUse records an accepted code so it cannot be replayed
+type adminTOTPUse struct {
+	code string
+	at   time.Time
+}
+
+// NewAdminTOTPService creates a new admin TOTP service
+func NewAdminTOTPService(db *gorm.DB, encryptionManager *security.EncryptionManager) *AdminTOTPService {
+	return &AdminTOTPService{
+		db:                db,
+		encryptionManager: encryptionManager,
+		now:               time.Now,
+		lastUsed:          make(map[string]adminTOTPUse),
+	}
+}
+
+// Enroll generates a new TOTP secret for an admin who has not enabled TOTP.
+// The secret stays pending until Confirm succeeds.
+func (s *AdminTOTPService) Enroll(admin *models.AdminUser) (*otp.Key, error) {
+	current, err := s.load(admin.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current.MFATOTPEnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: adminTOTPIssuer, AccountName: admin.Email})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+	encrypted, err := s.encryptionManager.Encrypt(key.Secret())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt TOTP secret: %w", err)
+	}
+
+	result := s.db.Model(&models.AdminUser{}).
+		Where("id = ? AND mfa_totp_enabled_at IS NULL", admin.ID).
+		Update("mfa_totp_secret", encrypted)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to store TOTP secret: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	return key, nil
+}
+
+// Confirm enables a pending TOTP enrollment once the admin enters a valid code
+func (s *AdminTOTPService) Confirm(adminID, code string) error {
+	admin, err := s.load(adminID)
+	if err != nil {
+		return err
+	}
+	if admin.MFATOTPEnabledAt != nil {
+		return ErrMFAAlreadyEnabled
+	}
+	if admin.MFATOTPSecret == "" {
+		return ErrMFANotEnrolled
+	}
+	valid, err := s.validate(admin, code)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrMFACodeInvalid
+	}
+
+	result := s.db.Model(&models.AdminUser{}).
+		Where("id = ? AND mfa_totp_secret = ? AND mfa_totp_enabled_at IS NULL", adminID, admin.MFATOTPSecret).
+		Update("mfa_totp_enabled_at", s.now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to enable TOTP: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrMFANotEnrolled
+	}
+	return nil
+}
+
+// Enabled reports whether the admin has confirmed a TOTP enrollment
+func (s *AdminTOTPService) Enabled(adminID string) (bool, error) {
+	admin, err := s.load(adminID)
+	if err != nil {
+		return false, err
+	}
+	return admin.MFATOTPEnabledAt != nil, nil
+}
+
+// Verify checks a code against the admin's enabled TOTP secret. A code is
+// accepted once; replaying it returns false.
+func (s *AdminTOTPService) Verify(adminID, code string) (bool, error) {
+	admin, err := s.load(adminID)
+	if err != nil {
+		return false, err
+	}
+	if admin.MFATOTPEnabledAt == nil {
+		return false, ErrMFANotConfigured
+	}
+	return s.validate(admin, code)
+}
+
+// validate checks the code against the stored secret and records its use
+func (s *AdminTOTPService) validate(admin *models.AdminUser, code string) (bool, error) {
+	secret, err := s.encryptionManager.Decrypt(admin.MFATOTPSecret)
+	if err != nil {
+		return false, fmt.Errorf("failed to decrypt TOTP secret: %w", err)
+	}
+	if !totp.Validate(code, secret) {
+		return false, nil
+	}
+
+	now := s.now()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if last, ok := s.lastUsed[admin.ID]; ok && last.code == code && now.Sub(last.at) < adminTOTPReplayWindow {
+		return false, nil
+	}
+	s.lastUsed[admin.ID] = adminTOTPUse{code: code, at: now}
+	return true, nil
+}
+
+// load reads the admin's TOTP columns
+func (s *AdminTOTPService) load(adminID string) (*models.AdminUser, error) {
+	var admin models.AdminUser
+	if err := s.db.Select("id", "email", "mfa_totp_secret", "mfa_totp_enabled_at").
+		First(&admin, "id = ?", adminID).Error; err != nil {
+		return nil, err
+	}
+	return &admin, nil
+}
@@ -471,6 +471,81 @@ func (acs *AnalyticsCacheService) cacheTrends(ctx context.Context, key string, t
 	return acs.redis.SetEx(ctx, key, data, ttl).Err()
 }
 
+// Dashboard Stats Caching
+
+// dashboardStatsKeyPrefix namespaces per-stat-type dashboard cache entries
+const dashboardStatsKeyPrefix = "analytics:dashboard:stats:"
+
+// GetDashboardStats returns cached dashboard stats for a stat type (live, hot, warm, daily)
+func (acs *AnalyticsCacheService) GetDashboardStats(ctx context.Context, statType string) (map[string]interface{}, bool) {
+	if cached := acs.getCachedDashboardMetrics(ctx, dashboardStatsKeyPrefix+statType); cached != nil {
+		acs.recordCacheHit()
+		return cached, true
+	}
+	acs.recordCacheMiss()
+	return nil, false
+}
+
+// SetDashboardStats caches dashboard stats for a stat type
+func (acs *AnalyticsCacheService) SetDashboardStats(ctx context.Context, statType string, stats map[string]interface{}, ttl time.Duration) error {
+	if err := acs.cacheDashboardMetrics(ctx, dashboardStatsKeyPrefix+statType, stats, ttl); err != nil {
+		acs.recordCacheError()
+		return err
+	}
+	return nil
+}
+
+// InvalidateDashboardStats removes all cached dashboard stats
+func (acs *AnalyticsCacheService) InvalidateDashboardStats(ctx context.Context) error {
+	return acs.deleteFromCache(ctx, dashboardStatsKeyPrefix+"*")
+}
+
+// Conversion Funnel Caching
+
+// conversionFunnelKeyPrefix namespaces cached conversion funnel reports by date range
+const conversionFunnelKeyPrefix = "analytics:funnel:conversion:"
+
+// GetConversionFunnel returns a cached conversion funnel report
+func (acs *AnalyticsCacheService) GetConversionFunnel(ctx context.Context, rangeKey string) (*ConversionFunnelReport, bool) {
+	if acs.redis == nil {
+		return nil, false
+	}
+
+	val, err := acs.redis.Get(ctx, conversionFunnelKeyPrefix+rangeKey).Result()
+	if err != nil {
+		acs.recordCacheMiss()
+		return nil, false
+	}
+
+	var report ConversionFunnelReport
+	if err := json.Unmarshal([]byte(val), &report); err != nil {
+		log.Printf("⚠️ Failed to unmarshal conversion funnel from cache: %v", err)
+		acs.recordCacheError()
+		return nil, false
+	}
+
+	acs.recordCacheHit()
+	return &report, true
+}
+
+// SetConversionFunnel caches a conversion funnel report
+func (acs *AnalyticsCacheService) SetConversionFunnel(ctx context.Context, rangeKey string, report *ConversionFunnelReport, ttl time.Duration) error {
+	if acs.redis == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal conversion funnel: %v", err)
+	}
+
+	if err := acs.redis.SetEx(ctx, conversionFunnelKeyPrefix+rangeKey, data, ttl).Err(); err != nil {
+		acs.recordCacheError()
+		return err
+	}
+	return nil
+}
+
 func (acs *AnalyticsCacheService) getCachedDashboardMetrics(ctx context.Context, key string) map[string]interface{} {
 	if acs.redis == nil {
 		return nil
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+// applicationRecoveryBatch caps how many stalled applicants one run handles
+const applicationRecoveryBatch = 200
+
+// applicationRecoveryRecent is how many recoveries the report lists
+const applicationRecoveryRecent = 20
+
+// ApplicationRecoverySettings controls the reminders sent to applicants who
+// start an application and don't submit it
+type ApplicationRecoverySettings struct {
+	Delay        time.Duration // Wait after the application was started before the first reminder
+	Interval     time.Duration // Wait between reminders, and after the last one before giving up
+	MaxReminders int
+}
+
+// DefaultApplicationRecoverySettings reminds after a day, then every two days, three times
+func DefaultApplicationRecoverySettings() ApplicationRecoverySettings {
+	return ApplicationRecoverySettings{Delay: 24 * time.Hour, Interval: 48 * time.Hour, MaxReminders: 3}
+}
+
+// ApplicationRecoveryReport summarizes how application recoveries turned out
+type ApplicationRecoveryReport struct {
+	Since          time.Time                    `json:"since"`
+	Outcomes       map[string]int64             `json:"outcomes"`
+	RemindersSent  int64                        `json:"reminders_sent"`
+	CompletionRate float64                      `json:"completion_rate"` // Completed share of finished recoveries, 0-1
+	Recent         []models.ApplicationRecovery `json:"recent"`
+}
+
+// EnableApplicationRecovery turns on reminders for applications started and
+// not submitted. Unset settings take the defaults.
+func (s *AbandonmentRecoveryService) EnableApplicationRecovery(db *gorm.DB, settings ApplicationRecoverySettings) {
+	defaults := DefaultApplicationRecoverySettings()
+	if settings.Delay <= 0 {
+		settings.Delay = defaults.Delay
+	}
+	if settings.Interval <= 0 {
+		settings.Interval = defaults.Interval
+	}
+	if settings.MaxReminders <= 0 {
+		settings.MaxReminders = defaults.MaxReminders
+	}
+	s.db = db
+	s.appSettings = settings
+	if s.now == nil {
+		s.now = time.Now
+	}
+	// Avoid storing typed nils so the channel checks in remindApplicant work
+	if s.emailService != nil {
+		s.appEmail = s.emailService
+	}
+	if s.smsService != nil {
+		s.appSMS = s.smsService
+	}
+}
+
+// SetLeadSafetyFilter skips applicants whose lead the safety filter blocks
+func (s *AbandonmentRecoveryService) SetLeadSafetyFilter(safety *LeadSafetyFilter) {
+	s.safety = safety
+}
+
+// StartApplicationRecovery runs RunApplicationRecovery every interval until ctx is cancelled
+func (s *AbandonmentRecoveryService) StartApplicationRecovery(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if reminded, err := s.RunApplicationRecovery(); err != nil {
+				log.Printf("⚠️ Application recovery run failed: %v", err)
+			} else if reminded > 0 {
+				log.Printf("📝 Reminded %d applicants to finish their applications", reminded)
+			}
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 Application recovery stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	log.Printf("🕐 Application recovery started (every %v, first reminder after %v, up to %d reminders)",
+		interval, s.appSettings.Delay, s.appSettings.MaxReminders)
+}
+
+// RunApplicationRecovery records the outcome of finished recoveries, then
+// reminds applicants whose next reminder is due. It returns how many
+// applicants were reminded.
+func (s *AbandonmentRecoveryService) RunApplicationRecovery() (int, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("application recovery is not enabled")
+	}
+	now := s.now()
+	if err := s.resolveApplicationRecoveries(now); err != nil {
+		return 0, err
+	}
+
+	// Applicants whose recovery has ended, or whose last reminder is too recent, are not due
+	notDue := s.db.Model(&models.ApplicationRecovery{}).Select("applicant_id").
+		Where("outcome <> ? OR last_reminder_at > ?", models.RecoveryPending, now.Add(-s.appSettings.Interval))
+	var applicants []models.ApplicationApplicant
+	if err := s.db.Where("completion_status = ? AND created_at <= ?", models.ApplicantStarted, now.Add(-s.appSettings.Delay)).
+		Where("id NOT IN (?)", notDue).
+		Order("created_at ASC").
+		Limit(applicationRecoveryBatch).
+		Find(&applicants).Error; err != nil {
+		return 0, fmt.Errorf("failed to load unfinished applications: %w", err)
+	}
+	if len(applicants) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uint, len(applicants))
+	for i, applicant := range applicants {
+		ids[i] = applicant.ID
+	}
+	var existing []models.ApplicationRecovery
+	if err := s.db.Where("applicant_id IN ?", ids).Find(&existing).Error; err != nil {
+		return 0, fmt.Errorf("failed to load application recoveries: %w", err)
+	}
+	recoveries := make(map[uint]*models.ApplicationRecovery, len(existing))
+	for i := range existing {
+		recoveries[existing[i].ApplicantID] = &existing[i]
+	}
+
+	reminded := 0
+	for i := range applicants {
+		applicant := &applicants[i]
+		recovery, ok := recoveries[applicant.ID]
+		if !ok {
+			address, _ := applicant.ApplicationData["property_address"].(string)
+			recovery = &models.ApplicationRecovery{
+				ApplicantID:     applicant.ID,
+				ApplicantEmail:  applicant.ApplicantEmail,
+				PropertyAddress: address,
+				Outcome:         models.RecoveryPending,
+			}
+		}
+		if recovery.RemindersSent >= s.appSettings.MaxReminders {
+			continue
+		}
+
+		sent, err := s.remindApplicant(applicant, recovery, now)
+		if err != nil {
+			log.Printf("⚠️ Failed to remind applicant %d: %v", applicant.ID, err)
+			continue
+		}
+		if sent {
+			reminded++
+		}
+	}
+	return reminded, nil
+}
+
+// resolveApplicationRecoveries marks recoveries completed once the applicant
+// submits, and ignored once the last reminder has gone unanswered
+func (s *AbandonmentRecoveryService) resolveApplicationRecoveries(now time.Time) error {
+	submitted := s.db.Model(&models.ApplicationApplicant{}).Select("id").
+		Where("completion_status = ?", models.ApplicantSubmitted)
+	if err := s.db.Model(&models.ApplicationRecovery{}).
+		Where("outcome = ? AND applicant_id IN (?)", models.RecoveryPending, submitted).
+		Updates(map[string]interface{}{"outcome": models.RecoveryCompleted, "outcome_at": now}).Error; err != nil {
+		return fmt.Errorf("failed to record completed recoveries: %w", err)
+	}
+
+	if err := s.db.Model(&models.ApplicationRecovery{}).
+		Where("outcome = ? AND reminders_sent >= ? AND last_reminder_at <= ?",
+			models.RecoveryPending, s.appSettings.MaxReminders, now.Add(-s.appSettings.Interval)).
+		Updates(map[string]interface{}{
+			"outcome":        models.RecoveryIgnored,
+			"outcome_reason": fmt.Sprintf("no application after %d reminders", s.appSettings.MaxReminders),
+			"outcome_at":     now,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to record ignored recoveries: %w", err)
+	}
+	return nil
+}
+
+// remindApplicant sends the applicant's next reminder by email, and by SMS if
+// they agreed to texts, unless they may not be contacted. It returns whether
+// a reminder went out.
+func (s *AbandonmentRecoveryService) remindApplicant(applicant *models.ApplicationApplicant, recovery *models.ApplicationRecovery, now time.Time) (bool, error) {
+	reason, err := s.applicantContactBlock(applicant)
+	if err != nil {
+		return false, err
+	}
+	if reason != "" {
+		return false, s.suppressRecovery(recovery, reason, now)
+	}
+
+	step := recovery.RemindersSent + 1
+	address := recovery.PropertyAddress
+	if address == "" {
+		address = "your new home"
+	}
+	resumeURL, _ := applicant.ApplicationData["resume_url"].(string)
+	metadata := map[string]interface{}{
+		"applicant_id":  applicant.ID,
+		"reminder":      step,
+		"campaign_type": "application_recovery",
+	}
+
+	var channels, failures []string
+	dncBlocked := false
+	attempt := func(channel string, err error) {
+		switch {
+		case err == nil:
+			channels = append(channels, channel)
+		case errors.Is(err, ErrRecipientOnDNC):
+			dncBlocked = true
+		default:
+			failures = append(failures, fmt.Sprintf("%s: %v", channel, err))
+		}
+	}
+
+	if s.appEmail != nil && applicant.ApplicantEmail != "" {
+		subject, body := applicationReminderEmail(applicant.ApplicantName, address, resumeURL, step == s.appSettings.MaxReminders)
+		attempt("email", s.appEmail.SendEmail(applicant.ApplicantEmail, subject, body, metadata))
+	}
+	if consent, _ := applicant.ApplicationData["sms_consent"].(bool); consent && s.appSMS != nil && applicant.ApplicantPhone != "" {
+		attempt("sms", s.appSMS.SendSMS(applicant.ApplicantPhone, applicationReminderSMS(applicant.ApplicantName, address, resumeURL), metadata))
+	}
+
+	if len(channels) == 0 {
+		if dncBlocked && len(failures) == 0 {
+			return false, s.suppressRecovery(recovery, ErrRecipientOnDNC.Error(), now)
+		}
+		if len(failures) == 0 {
+			return false, fmt.Errorf("no email or SMS channel available")
+		}
+		return false, fmt.Errorf("reminder %d not sent: %s", step, strings.Join(failures, "; "))
+	}
+
+	recovery.RemindersSent = step
+	recovery.LastChannels = strings.Join(channels, ",")
+	recovery.LastReminderAt = &now
+	return true, s.db.Save(recovery).Error
+}
+
+// applicantContactBlock returns why the applicant may not be reminded, if they
+// unsubscribed or the lead safety filter blocks their lead
+func (s *AbandonmentRecoveryService) applicantContactBlock(applicant *models.ApplicationApplicant) (string, error) {
+	if applicant.ApplicantEmail != "" && s.db.Migrator().HasTable("unsubscribe_records") {
+		var count int64
+		s.db.Table("unsubscribe_records").
+			Where("LOWER(email) = ? AND unsubscribe_type IN ? AND is_active = ?",
+				strings.ToLower(applicant.ApplicantEmail), []string{"all", "marketing"}, true).
+			Count(&count)
+		if count > 0 {
+			return "applicant unsubscribed", nil
+		}
+	}
+
+	if s.safety == nil {
+		return "", nil
+	}
+	if applicant.FUBLeadID != "" {
+		blocks, err := s.safety.ContactBlocks([]string{applicant.FUBLeadID})
+		if err != nil {
+			return "", err
+		}
+		return blocks[applicant.FUBLeadID], nil
+	}
+	_, reason, err := s.safety.ContactBlockForEmail(applicant.ApplicantEmail)
+	return reason, err
+}
+
+func (s *AbandonmentRecoveryService) suppressRecovery(recovery *models.ApplicationRecovery, reason string, now time.Time) error {
+	recovery.Outcome = models.RecoverySuppressed
+	recovery.OutcomeReason = reason
+	recovery.OutcomeAt = &now
+	log.Printf("🚫 Application recovery for applicant %d suppressed: %s", recovery.ApplicantID, reason)
+	return s.db.Save(recovery).Error
+}
+
+// ApplicationRecoveryReport summarizes recoveries started since the given time
+func (s *AbandonmentRecoveryService) ApplicationRecoveryReport(since time.Time) (*ApplicationRecoveryReport, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("application recovery is not enabled")
+	}
+	report := &ApplicationRecoveryReport{Since: since, Outcomes: map[string]int64{
+		models.RecoveryPending:    0,
+		models.RecoveryCompleted:  0,
+		models.RecoveryIgnored:    0,
+		models.RecoverySuppressed: 0,
+	}}
+
+	var rows []struct {
+		Outcome   string
+		Count     int64
+		Reminders int64
+	}
+	if err := s.db.Model(&models.ApplicationRecovery{}).
+		Select("outcome, COUNT(*) AS count, COALESCE(SUM(reminders_sent), 0) AS reminders").
+		Where("created_at >= ?", since).
+		Group("outcome").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to summarize application recoveries: %w", err)
+	}
+	for _, row := range rows {
+		report.Outcomes[row.Outcome] = row.Count
+		report.RemindersSent += row.Reminders
+	}
+	if finished := report.Outcomes[models.RecoveryCompleted] + report.Outcomes[models.RecoveryIgnored]; finished > 0 {
+		report.CompletionRate = float64(report.Outcomes[models.RecoveryCompleted]) / float64(finished)
+	}
+
+	if err := s.db.Where("created_at >= ?", since).
+		Order("updated_at DESC").
+		Limit(applicationRecoveryRecent).
+		Find(&report.Recent).Error; err != nil {
+		return nil, fmt.Errorf("failed to load application recoveries: %w", err)
+	}
+	return report, nil
+}
+
+func applicationReminderEmail(name, address, resumeURL string, last bool) (string, string) {
+	subject := fmt.Sprintf("Finish your application for %s", address)
+	if last {
+		subject = fmt.Sprintf("Last reminder: your application for %s", address)
+	}
+	body := fmt.Sprintf(`<p>Hi %s,</p>
+<p>You started an application for <strong>%s</strong> but haven't submitted it yet. It only takes a few minutes to finish.</p>`,
+		html.EscapeString(applicantFirstName(name)), html.EscapeString(address))
+	if resumeURL != "" {
+		body += fmt.Sprintf(`
+<p><a href="%s">Finish your application</a></p>`, html.EscapeString(resumeURL))
+	}
+	if last {
+		body += `
+<p>This is the last reminder we'll send about this application.</p>`
+	}
+	return subject, body
+}
+
+func applicationReminderSMS(name, address, resumeURL string) string {
+	message := fmt.Sprintf("Hi %s, your application for %s isn't finished yet.", applicantFirstName(name), address)
+	if resumeURL != "" {
+		message += " Pick up where you left off: " + resumeURL
+	}
+	return message + " Reply STOP to opt out."
+}
+
+func applicantFirstName(name string) string {
+	if fields := strings.Fields(name); len(fields) > 0 {
+		return fields[0]
+	}
+	return "there"
+}
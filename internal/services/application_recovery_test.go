@@ -0,0 +1,98 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type recordingRecoverySender struct {
+	sent []string
+}
+
+func (r *recordingRecoverySender) SendEmail(to, subject, content string, metadata map[string]interface{}) error {
+	r.sent = append(r.sent, to)
+	return nil
+}
+
+func (r *recordingRecoverySender) SendSMS(to, content string, metadata map[string]interface{}) error {
+	r.sent = append(r.sent, to)
+	return nil
+}
+
+func TestApplicationRecovery_RemindsUntilCompletedOrIgnored(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Lead{}, &models.ApplicationRecovery{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	db.Exec(`CREATE TABLE unsubscribe_records (id INTEGER PRIMARY KEY, email TEXT, unsubscribe_type TEXT, is_active BOOLEAN)`)
+	db.Exec(`INSERT INTO unsubscribe_records (email, unsubscribe_type, is_active) VALUES ('bob@example.com', 'all', true)`)
+
+	workflow := NewApplicationWorkflowService(db)
+	for _, start := range []ApplicationStart{
+		{Name: "Ann Lee", Email: "Ann@example.com", Phone: "+17135550100", PropertyAddress: "12 Oak St", SMSConsent: true},
+		{Name: "Bob Ray", Email: "bob@example.com", Phone: "+17135550101", PropertyAddress: "12 Oak St"},
+		{Name: "Eve Fox", Email: "eve@example.com", Phone: "+17135550102", PropertyAddress: "9 Elm St"},
+	} {
+		if _, err := workflow.RecordApplicationStarted(start); err != nil {
+			t.Fatalf("Failed to record application start: %v", err)
+		}
+	}
+	// Starting again doesn't create a second record
+	workflow.RecordApplicationStarted(ApplicationStart{Name: "Ann Lee", Email: "ann@example.com", PropertyAddress: "12 Oak St", SMSConsent: true, Phone: "+17135550100"})
+	workflow.ProcessBuildiumEmail("Dan Poe", "dan@example.com", "4 Pine St")
+
+	sender := &recordingRecoverySender{}
+	recovery := &AbandonmentRecoveryService{}
+	recovery.EnableApplicationRecovery(db, ApplicationRecoverySettings{Delay: time.Hour, Interval: 24 * time.Hour, MaxReminders: 2})
+	recovery.appEmail = sender
+	recovery.appSMS = sender
+	now := time.Now().Add(2 * time.Hour)
+	recovery.now = func() time.Time { return now }
+
+	// Cara started too recently to be reminded
+	db.Create(&models.ApplicationApplicant{ApplicantName: "Cara", ApplicantEmail: "cara@example.com",
+		CompletionStatus: models.ApplicantStarted, CreatedAt: now.Add(-30 * time.Minute)})
+
+	reminded, err := recovery.RunApplicationRecovery()
+	if err != nil || reminded != 2 {
+		t.Fatalf("Expected Ann and Eve reminded, got %d (%v)", reminded, err)
+	}
+	if len(sender.sent) != 3 || sender.sent[0] != "ann@example.com" || sender.sent[1] != "+17135550100" || sender.sent[2] != "eve@example.com" {
+		t.Errorf("Expected Ann by email and SMS and Eve by email only, got %v", sender.sent)
+	}
+	if reminded, _ := recovery.RunApplicationRecovery(); reminded != 0 {
+		t.Errorf("Expected no reminders before the interval, got %d", reminded)
+	}
+
+	workflow.ProcessBuildiumEmail("Ann Lee", "ann@example.com", "12 Oak St")
+	for i := 0; i < 2; i++ {
+		now = now.Add(24 * time.Hour)
+		recovery.RunApplicationRecovery()
+	}
+
+	outcomes := map[string]string{}
+	var recoveries []models.ApplicationRecovery
+	db.Find(&recoveries)
+	for _, r := range recoveries {
+		outcomes[r.ApplicantEmail] = r.Outcome
+	}
+	if len(recoveries) != 4 || outcomes["cara@example.com"] != models.RecoveryPending || outcomes["ann@example.com"] != models.RecoveryCompleted ||
+		outcomes["bob@example.com"] != models.RecoverySuppressed || outcomes["eve@example.com"] != models.RecoveryIgnored {
+		t.Fatalf("Expected Ann completed, Bob suppressed, Eve ignored and Cara pending, got %v", outcomes)
+	}
+
+	report, err := recovery.ApplicationRecoveryReport(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to build report: %v", err)
+	}
+	if report.RemindersSent != 5 || report.CompletionRate != 0.5 || report.Outcomes[models.RecoverySuppressed] != 1 {
+		t.Errorf("Expected 5 reminders and half of finished recoveries completed, got %+v", report)
+	}
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrApplicationNotFound is returned when the application number does not exist
+	ErrApplicationNotFound = errors.New("application not found")
+	// ErrApplicantNotFound is returned when the applicant does not exist
+	ErrApplicantNotFound = errors.New("applicant not found")
+	// ErrApplicationRequirementsNotMet is returned when an allowed transition
+	// is refused because the application is missing what the new status needs
+	ErrApplicationRequirementsNotMet = errors.New("application does not meet the requirements for this status")
+	// ErrApplicationDecided is returned when changing the applicants of an
+	// application that has already been approved, denied or cancelled
+	ErrApplicationDecided = errors.New("application has already been decided")
+	// ErrApplicationStatusChanged is returned when another request changed the
+	// application's status between reading and updating it
+	ErrApplicationStatusChanged = errors.New("application status changed concurrently")
+)
+
+// ApplicationTransition is a completed application status change
+type ApplicationTransition struct {
+	Application *models.ApplicationNumber
+	Log         models.ApplicationStatusLog
+}
+
+// TransitionApplication moves an application number to a new status after
+// checking the move is allowed and the application meets the new status's
+// requirements, and records the change in the status log
+func (aws *ApplicationWorkflowService) TransitionApplication(applicationID uint, newStatus, changedBy, reason, notes string) (*ApplicationTransition, error) {
+	var transition ApplicationTransition
+	err := aws.db.Transaction(func(tx *gorm.DB) error {
+		var application models.ApplicationNumber
+		if err := tx.First(&application, applicationID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrApplicationNotFound
+			}
+			return err
+		}
+		from := application.Status
+		if err := models.ValidateApplicationTransition(from, newStatus); err != nil {
+			return err
+		}
+		if err := checkApplicationRequirements(tx, &application, newStatus, reason); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{
+			"status":            newStatus,
+			"status_updated_at": &now,
+			"status_updated_by": changedBy,
+		}
+		if notes != "" {
+			application.InternalNotes = appendApplicationNote(application.InternalNotes,
+				fmt.Sprintf("Notes added by %s: %s", changedBy, notes))
+			updates["internal_notes"] = application.InternalNotes
+		}
+		// Guard on the current status so concurrent updates cannot both apply
+		result := tx.Model(&models.ApplicationNumber{}).
+			Where("id = ? AND status = ?", application.ID, from).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrApplicationStatusChanged
+		}
+
+		transition.Log = models.ApplicationStatusLog{
+			ApplicationNumberID: application.ID,
+			PreviousStatus:      from,
+			NewStatus:           newStatus,
+			ChangedBy:           changedBy,
+			ChangeReason:        reason,
+			Notes:               notes,
+		}
+		if err := tx.Create(&transition.Log).Error; err != nil {
+			return err
+		}
+
+		application.Status = newStatus
+		application.StatusUpdatedAt = &now
+		application.StatusUpdatedBy = changedBy
+		transition.Application = &application
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transition, nil
+}
+
+// checkApplicationRequirements enforces what each status needs: screening
+// starts with at least one applicant, approval needs every applicant
+// screened, and a denial needs a reason
+func checkApplicationRequirements(tx *gorm.DB, application *models.ApplicationNumber, status, reason string) error {
+	switch status {
+	case models.AppStatusDenied:
+		if strings.TrimSpace(reason) == "" {
+			return fmt.Errorf("%w: a reason is required to deny an application", ErrApplicationRequirementsNotMet)
+		}
+		return nil
+	case models.AppStatusReview, models.AppStatusBackup, models.AppStatusApproved:
+	default:
+		return nil
+	}
+
+	var applicants []models.ApplicationApplicant
+	if err := tx.Where("application_number_id = ?", application.ID).Find(&applicants).Error; err != nil {
+		return err
+	}
+	if len(applicants) == 0 {
+		return fmt.Errorf("%w: %s has no applicants", ErrApplicationRequirementsNotMet, application.ApplicationName)
+	}
+	if status != models.AppStatusApproved {
+		return nil
+	}
+
+	var unscreened []string
+	for _, applicant := range applicants {
+		if applicant.ScreenedAt == nil {
+			unscreened = append(unscreened, applicant.ApplicantName)
+		}
+	}
+	if len(unscreened) > 0 {
+		return fmt.Errorf("%w: applicants not yet screened: %s", ErrApplicationRequirementsNotMet, strings.Join(unscreened, ", "))
+	}
+	return nil
+}
+
+// ApplicantScreening is the result of screening one applicant. Nil fields
+// leave the applicant's existing values unchanged.
+type ApplicantScreening struct {
+	CreditScore      *int     `json:"credit_score"`
+	Income           *float64 `json:"income"`
+	EmploymentStatus *string  `json:"employment_status"`
+	RentalHistory    *string  `json:"rental_history"`
+}
+
+// ScreenApplicant records an applicant's screening results and marks them screened
+func (aws *ApplicationWorkflowService) ScreenApplicant(applicantID uint, screening ApplicantScreening, screenedBy string) (*models.ApplicationApplicant, error) {
+	var applicant models.ApplicationApplicant
+	if err := aws.db.First(&applicant, applicantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApplicantNotFound
+		}
+		return nil, err
+	}
+	if applicant.ApplicationNumberID != 0 {
+		var application models.ApplicationNumber
+		if err := aws.db.Select("id", "status").First(&application, applicant.ApplicationNumberID).Error; err == nil &&
+			models.IsApplicationDecided(application.Status) {
+			return nil, ErrApplicationDecided
+		}
+	}
+
+	now := time.Now()
+	applicant.ScreenedAt = &now
+	applicant.ScreenedBy = screenedBy
+	if screening.CreditScore != nil {
+		applicant.CreditScore = *screening.CreditScore
+	}
+	if screening.Income != nil {
+		applicant.Income = *screening.Income
+	}
+	if screening.EmploymentStatus != nil {
+		applicant.EmploymentStatus = *screening.EmploymentStatus
+	}
+	if screening.RentalHistory != nil {
+		applicant.RentalHistory = *screening.RentalHistory
+	}
+	if err := aws.db.Save(&applicant).Error; err != nil {
+		return nil, err
+	}
+	return &applicant, nil
+}
+
+// GetStatusHistory returns an application's status changes, oldest first
+func (aws *ApplicationWorkflowService) GetStatusHistory(applicationID uint) ([]models.ApplicationStatusLog, error) {
+	if err := aws.db.Select("id").First(&models.ApplicationNumber{}, applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApplicationNotFound
+		}
+		return nil, err
+	}
+	history := []models.ApplicationStatusLog{}
+	err := aws.db.Where("application_number_id = ?", applicationID).Order("created_at ASC, id ASC").Find(&history).Error
+	return history, err
+}
+
+// appendApplicationNote appends a note to existing notes
+func appendApplicationNote(existingNotes, newNote string) string {
+	if existingNotes == "" {
+		return newNote
+	}
+	return existingNotes + "\n\n" + newNote
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestApplicationWorkflowService_TransitionGuards(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	service := NewApplicationWorkflowService(db)
+
+	group := models.PropertyApplicationGroup{PropertyID: 1, PropertyAddress: "123 Main St"}
+	db.Create(&group)
+	application, err := group.CreateNextApplicationNumber(db)
+	if err != nil {
+		t.Fatalf("Failed to create application: %v", err)
+	}
+
+	// Screening cannot start without applicants
+	if _, err := service.TransitionApplication(application.ID, models.AppStatusReview, "agent", "", ""); !errors.Is(err, ErrApplicationRequirementsNotMet) {
+		t.Errorf("Expected review without applicants to be rejected, got %v", err)
+	}
+
+	first := models.ApplicationApplicant{ApplicantName: "Ann", ApplicantEmail: "ann@example.com"}
+	second := models.ApplicationApplicant{ApplicantName: "Bob", ApplicantEmail: "bob@example.com"}
+	application.AddApplicant(db, &first)
+	application.AddApplicant(db, &second)
+
+	// Decisions require screening first
+	if _, err := service.TransitionApplication(application.ID, models.AppStatusApproved, "agent", "", ""); !errors.Is(err, models.ErrInvalidApplicationTransition) {
+		t.Errorf("Expected submitted -> approved to be rejected, got %v", err)
+	}
+	if _, err := service.TransitionApplication(application.ID, models.AppStatusReview, "agent", "", ""); err != nil {
+		t.Fatalf("Expected submitted -> review to succeed, got %v", err)
+	}
+
+	// An incomplete group cannot be approved
+	if _, err := service.ScreenApplicant(first.ID, ApplicantScreening{}, "agent"); err != nil {
+		t.Fatalf("ScreenApplicant failed: %v", err)
+	}
+	if _, err := service.TransitionApplication(application.ID, models.AppStatusApproved, "agent", "", ""); !errors.Is(err, ErrApplicationRequirementsNotMet) {
+		t.Errorf("Expected approving with an unscreened applicant to be rejected, got %v", err)
+	}
+	if _, err := service.TransitionApplication(application.ID, models.AppStatusDenied, "agent", "", ""); !errors.Is(err, ErrApplicationRequirementsNotMet) {
+		t.Errorf("Expected a denial without a reason to be rejected, got %v", err)
+	}
+
+	score := 720
+	if _, err := service.ScreenApplicant(second.ID, ApplicantScreening{CreditScore: &score}, "agent"); err != nil {
+		t.Fatalf("ScreenApplicant failed: %v", err)
+	}
+	transition, err := service.TransitionApplication(application.ID, models.AppStatusApproved, "agent", "All screened", "")
+	if err != nil {
+		t.Fatalf("Expected approval to succeed once every applicant is screened, got %v", err)
+	}
+	if transition.Application.Status != models.AppStatusApproved {
+		t.Errorf("Expected approved status, got %s", transition.Application.Status)
+	}
+
+	// Approved applications are final apart from cancellation
+	if _, err := service.TransitionApplication(application.ID, models.AppStatusReview, "agent", "", ""); !errors.Is(err, models.ErrInvalidApplicationTransition) {
+		t.Errorf("Expected approved -> review to be rejected, got %v", err)
+	}
+	late := models.ApplicationApplicant{ApplicantName: "Cy", ApplicantEmail: "cy@example.com"}
+	db.Create(&late)
+	if err := service.MoveApplicantToApplication(late.ID, application.ID, "agent", ""); !errors.Is(err, ErrApplicationDecided) {
+		t.Errorf("Expected adding an applicant to an approved application to be rejected, got %v", err)
+	}
+
+	history, err := service.GetStatusHistory(application.ID)
+	if err != nil {
+		t.Fatalf("GetStatusHistory failed: %v", err)
+	}
+	if len(history) != 2 || history[0].NewStatus != models.AppStatusReview || history[1].PreviousStatus != models.AppStatusReview || history[1].NewStatus != models.AppStatusApproved {
+		t.Errorf("Unexpected status history %+v", history)
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"strings"
 	"time"
 	"gorm.io/gorm"
 	"chrisgross-ctrl-project/internal/models"
@@ -25,8 +26,75 @@ func NewApplicationWorkflowService(db *gorm.DB) *ApplicationWorkflowService {
 	}
 }
 
+// ApplicationStart is an applicant beginning an application on the site
+type ApplicationStart struct {
+	Name            string `json:"name" binding:"required"`
+	Email           string `json:"email" binding:"required,email"`
+	Phone           string `json:"phone"`
+	PropertyAddress string `json:"property_address" binding:"required"`
+	ResumeURL       string `json:"resume_url"`
+	SMSConsent      bool   `json:"sms_consent"` // Agreed to text reminders
+}
+
+// RecordApplicationStarted tracks an application the applicant has begun but
+// not submitted, so it can be recovered if they stall. Starting again for the
+// same property updates the existing record.
+func (aws *ApplicationWorkflowService) RecordApplicationStarted(start ApplicationStart) (*models.ApplicationApplicant, error) {
+	email := strings.ToLower(strings.TrimSpace(start.Email))
+	var started []models.ApplicationApplicant
+	if err := aws.db.Where("LOWER(applicant_email) = ? AND completion_status = ?", email, models.ApplicantStarted).
+		Find(&started).Error; err != nil {
+		return nil, err
+	}
+	var applicant models.ApplicationApplicant
+	for _, existing := range started {
+		if address, _ := existing.ApplicationData["property_address"].(string); address == start.PropertyAddress {
+			applicant = existing
+			break
+		}
+	}
+
+	applicant.ApplicantName = strings.TrimSpace(start.Name)
+	applicant.ApplicantEmail = email
+	applicant.ApplicantPhone = start.Phone
+	applicant.ApplicationDate = time.Now()
+	applicant.SourceEmail = "website"
+	applicant.CompletionStatus = models.ApplicantStarted
+	applicant.ApplicationData = models.JSONB{
+		"property_address": start.PropertyAddress,
+		"resume_url":       start.ResumeURL,
+		"sms_consent":      start.SMSConsent,
+		"source":           "website",
+	}
+	if fubLeadID, matchFound := aws.findFUBMatch(email); matchFound {
+		applicant.FUBLeadID = fubLeadID
+		applicant.FUBMatch = true
+		applicant.MatchScore = 0.9
+	}
+
+	if err := aws.db.Save(&applicant).Error; err != nil {
+		return nil, err
+	}
+	return &applicant, nil
+}
+
 // ProcessBuildiumEmail creates unassigned applicant from Buildium email notification
 func (aws *ApplicationWorkflowService) ProcessBuildiumEmail(applicantName, applicantEmail, propertyAddress string) error {
+	// An applicant who started on the site has now submitted
+	result := aws.db.Model(&models.ApplicationApplicant{}).
+		Where("LOWER(applicant_email) = ? AND completion_status = ?", strings.ToLower(strings.TrimSpace(applicantEmail)), models.ApplicantStarted).
+		Updates(map[string]interface{}{
+			"completion_status": models.ApplicantSubmitted,
+			"application_date":  time.Now(),
+			"source_email":      "buildium_notification",
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
 	// Create unassigned applicant record - Christopher's business model
 	applicant := &models.ApplicationApplicant{
 		ApplicantName:   applicantName,
@@ -137,6 +205,18 @@ func (aws *ApplicationWorkflowService) MoveApplicantToApplication(applicantID, t
 		return err
 	}
 	
+	// Decided applications keep the applicants they were decided with
+	if models.IsApplicationDecided(targetApplication.Status) {
+		return ErrApplicationDecided
+	}
+	if applicant.ApplicationNumberID != 0 {
+		var sourceApplication models.ApplicationNumber
+		if err := aws.db.First(&sourceApplication, applicant.ApplicationNumberID).Error; err == nil &&
+			models.IsApplicationDecided(sourceApplication.Status) {
+			return ErrApplicationDecided
+		}
+	}
+	
 	// Update applicant assignment
 	oldAppID := applicant.ApplicationNumberID
 	applicant.ApplicationNumberID = targetApplicationID
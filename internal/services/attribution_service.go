@@ -0,0 +1,319 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/gorm"
+)
+
+// Attribution models
+const (
+	AttributionFirstTouch = "first_touch"
+	AttributionLastTouch  = "last_touch"
+	AttributionLinear     = "linear"
+)
+
+// Touch channels on a lead's timeline
+const (
+	TouchChannelSource   = "source"
+	TouchChannelCampaign = "campaign"
+)
+
+// ErrUnknownAttributionModel is returned for a model other than first_touch, last_touch or linear
+var ErrUnknownAttributionModel = errors.New("unknown attribution model")
+
+// AttributionTouch is one marketing touch on a lead's timeline
+type AttributionTouch struct {
+	Channel string    `json:"channel"`
+	Name    string    `json:"name"`
+	At      time.Time `json:"at"`
+}
+
+// AttributionCredit is the conversion credit one source or campaign earned
+type AttributionCredit struct {
+	Channel        string  `json:"channel"`
+	Name           string  `json:"name"`
+	Conversions    float64 `json:"conversions"` // credited conversions, fractional under linear
+	LeadsTouched   int     `json:"leads_touched"`
+	Touches        int     `json:"touches"`
+	ConversionRate float64 `json:"conversion_rate"` // credited conversions per lead touched, %
+}
+
+// AttributionReport credits conversions in a date range to the sources and
+// campaigns on each converted lead's timeline
+type AttributionReport struct {
+	Model                 string              `json:"model"`
+	Since                 time.Time           `json:"since"`
+	Until                 time.Time           `json:"until"`
+	ConvertedLeads        int                 `json:"converted_leads"`
+	UnconvertedLeads      int                 `json:"unconverted_leads"`
+	MultiTouchConversions int                 `json:"multi_touch_conversions"`
+	AvgTouchesToConvert   float64             `json:"avg_touches_to_convert"`
+	Credits               []AttributionCredit `json:"credits"`
+	GeneratedAt           time.Time           `json:"generated_at"`
+}
+
+// AttributionService computes which lead sources and campaigns drive conversions.
+// A lead converts when an approval linked to it is approved or a closing for
+// its email is completed.
+type AttributionService struct {
+	db                *gorm.DB
+	encryptionManager *security.EncryptionManager
+}
+
+// NewAttributionService creates an attribution service; encryptionManager
+// decrypts closing tenant emails and may be nil
+func NewAttributionService(db *gorm.DB, encryptionManager *security.EncryptionManager) *AttributionService {
+	return &AttributionService{db: db, encryptionManager: encryptionManager}
+}
+
+// BuildReport attributes conversions between since and until using the given
+// model. Leads created in the range that have not converted count toward each
+// touch's reach but earn no credit.
+func (s *AttributionService) BuildReport(model string, since, until time.Time) (*AttributionReport, error) {
+	if model != AttributionFirstTouch && model != AttributionLastTouch && model != AttributionLinear {
+		return nil, ErrUnknownAttributionModel
+	}
+
+	var leads []models.Lead
+	if err := s.db.Select("id", "email", "source", "fub_lead_id", "created_at").
+		Where("created_at < ?", until).
+		Find(&leads).Error; err != nil {
+		return nil, fmt.Errorf("failed to load leads: %w", err)
+	}
+
+	conversions, err := s.conversions(leads)
+	if err != nil {
+		return nil, err
+	}
+
+	// Converted in range, or created in range and not converted
+	var population []models.Lead
+	for _, lead := range leads {
+		if at, ok := conversions[lead.ID]; ok {
+			if !at.Before(since) && at.Before(until) {
+				population = append(population, lead)
+			}
+		} else if !lead.CreatedAt.Before(since) {
+			population = append(population, lead)
+		}
+	}
+
+	timelines, err := s.timelines(population)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AttributionReport{Model: model, Since: since, Until: until, GeneratedAt: time.Now()}
+	credits := make(map[[2]string]*AttributionCredit)
+	credit := func(touch AttributionTouch) *AttributionCredit {
+		key := [2]string{touch.Channel, touch.Name}
+		if credits[key] == nil {
+			credits[key] = &AttributionCredit{Channel: touch.Channel, Name: touch.Name}
+		}
+		return credits[key]
+	}
+
+	totalTouches := 0
+	for _, lead := range population {
+		touches := timelines[lead.ID]
+		convertedAt, converted := conversions[lead.ID]
+		if converted {
+			touches = touchesBefore(touches, convertedAt)
+		}
+
+		touched := make(map[[2]string]bool)
+		for _, touch := range touches {
+			c := credit(touch)
+			c.Touches++
+			if key := [2]string{touch.Channel, touch.Name}; !touched[key] {
+				touched[key] = true
+				c.LeadsTouched++
+			}
+		}
+
+		if !converted {
+			report.UnconvertedLeads++
+			continue
+		}
+		report.ConvertedLeads++
+		totalTouches += len(touches)
+		if len(touches) > 1 {
+			report.MultiTouchConversions++
+		}
+		if len(touches) == 0 {
+			continue
+		}
+
+		switch model {
+		case AttributionFirstTouch:
+			credit(touches[0]).Conversions++
+		case AttributionLastTouch:
+			credit(touches[len(touches)-1]).Conversions++
+		case AttributionLinear:
+			share := 1 / float64(len(touches))
+			for _, touch := range touches {
+				credit(touch).Conversions += share
+			}
+		}
+	}
+
+	if report.ConvertedLeads > 0 {
+		report.AvgTouchesToConvert = float64(totalTouches) / float64(report.ConvertedLeads)
+	}
+	for _, c := range credits {
+		if c.LeadsTouched > 0 {
+			c.ConversionRate = c.Conversions / float64(c.LeadsTouched) * 100
+		}
+		report.Credits = append(report.Credits, *c)
+	}
+	sort.Slice(report.Credits, func(i, j int) bool {
+		if report.Credits[i].Conversions != report.Credits[j].Conversions {
+			return report.Credits[i].Conversions > report.Credits[j].Conversions
+		}
+		return report.Credits[i].LeadsTouched > report.Credits[j].LeadsTouched
+	})
+	return report, nil
+}
+
+// conversions returns each converted lead's first conversion time
+func (s *AttributionService) conversions(leads []models.Lead) (map[uint]time.Time, error) {
+	byFUBID := make(map[string]uint)
+	byEmail := make(map[string]uint)
+	for _, lead := range leads {
+		if lead.FUBLeadID != "" {
+			byFUBID[lead.FUBLeadID] = lead.ID
+		}
+		if email := strings.ToLower(strings.TrimSpace(lead.Email)); email != "" {
+			byEmail[email] = lead.ID
+		}
+	}
+
+	converted := make(map[uint]time.Time)
+	record := func(leadID uint, at time.Time) {
+		if current, ok := converted[leadID]; !ok || at.Before(current) {
+			converted[leadID] = at
+		}
+	}
+
+	var approvals []models.Approval
+	if err := s.db.Select("id", "fub_lead_id", "updated_at").
+		Where("status = ? AND fub_lead_id <> ''", "approved").
+		Find(&approvals).Error; err != nil {
+		return nil, fmt.Errorf("failed to load approvals: %w", err)
+	}
+	for _, approval := range approvals {
+		if leadID, ok := byFUBID[approval.FUBLeadID]; ok {
+			record(leadID, approval.UpdatedAt)
+		}
+	}
+
+	var closings []models.ClosingPipeline
+	if err := s.db.Select("id", "tenant_email", "lease_signed_date", "updated_at").
+		Where("status = ?", "completed").
+		Find(&closings).Error; err != nil {
+		return nil, fmt.Errorf("failed to load closings: %w", err)
+	}
+	for _, closing := range closings {
+		email := string(closing.TenantEmail)
+		if s.encryptionManager != nil {
+			if decrypted, err := s.encryptionManager.Decrypt(closing.TenantEmail); err == nil {
+				email = decrypted
+			}
+		}
+		leadID, ok := byEmail[strings.ToLower(strings.TrimSpace(email))]
+		if !ok {
+			continue
+		}
+		at := closing.UpdatedAt
+		if closing.LeaseSignedDate != nil {
+			at = *closing.LeaseSignedDate
+		}
+		record(leadID, at)
+	}
+	return converted, nil
+}
+
+// timelines returns each lead's touches in time order: its source when it was
+// created, then re-engagement campaigns sent and campaign emails it engaged with
+func (s *AttributionService) timelines(leads []models.Lead) (map[uint][]AttributionTouch, error) {
+	timelines := make(map[uint][]AttributionTouch, len(leads))
+	if len(leads) == 0 {
+		return timelines, nil
+	}
+
+	leadIDs := make([]uint, 0, len(leads))
+	byFUBID := make(map[string]uint)
+	var fubIDs []string
+	for _, lead := range leads {
+		source := lead.Source
+		if source == "" {
+			source = "unknown"
+		}
+		timelines[lead.ID] = []AttributionTouch{{Channel: TouchChannelSource, Name: source, At: lead.CreatedAt}}
+		leadIDs = append(leadIDs, lead.ID)
+		if lead.FUBLeadID != "" {
+			byFUBID[lead.FUBLeadID] = lead.ID
+			fubIDs = append(fubIDs, lead.FUBLeadID)
+		}
+	}
+
+	if len(fubIDs) > 0 {
+		var sends []struct {
+			FUBContactID string
+			CampaignName string
+			TemplateName string
+			ExecutedAt   time.Time
+		}
+		if err := s.db.Table("campaign_executions AS ce").
+			Select("lr.fub_contact_id, ce.campaign_name, ct.name AS template_name, ce.executed_at").
+			Joins("JOIN lead_reengagements lr ON lr.id = ce.lead_reengagement_id").
+			Joins("LEFT JOIN campaign_templates ct ON ct.id = ce.campaign_template_id").
+			Where("ce.status = ? AND ce.executed_at IS NOT NULL AND ce.deleted_at IS NULL", "sent").
+			Where("lr.fub_contact_id IN ?", fubIDs).
+			Scan(&sends).Error; err != nil {
+			return nil, fmt.Errorf("failed to load campaign sends: %w", err)
+		}
+		for _, send := range sends {
+			name := send.CampaignName
+			if name == "" {
+				name = send.TemplateName
+			}
+			leadID := byFUBID[send.FUBContactID]
+			timelines[leadID] = append(timelines[leadID], AttributionTouch{Channel: TouchChannelCampaign, Name: name, At: send.ExecutedAt})
+		}
+	}
+
+	var events []models.BehavioralEvent
+	if err := s.db.Select("lead_id", "event_data", "created_at").
+		Where("lead_id IN ?", leadIDs).
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to load behavioral events: %w", err)
+	}
+	for _, event := range events {
+		campaignID, _ := event.EventData["campaign_id"].(string)
+		if campaignID == "" {
+			continue
+		}
+		leadID := uint(event.LeadID)
+		timelines[leadID] = append(timelines[leadID], AttributionTouch{Channel: TouchChannelCampaign, Name: campaignID, At: event.CreatedAt})
+	}
+
+	for leadID := range timelines {
+		touches := timelines[leadID]
+		sort.SliceStable(touches, func(i, j int) bool { return touches[i].At.Before(touches[j].At) })
+	}
+	return timelines, nil
+}
+
+// touchesBefore returns the touches at or before the conversion
+func touchesBefore(touches []AttributionTouch, at time.Time) []AttributionTouch {
+	n := sort.Search(len(touches), func(i int) bool { return touches[i].At.After(at) })
+	return touches[:n]
+}
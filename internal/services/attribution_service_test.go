@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAttributionService_CreditsTouchesBeforeConversion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Lead{}, &models.Approval{}, &models.ClosingPipeline{}, &models.LeadReengagement{},
+		&models.CampaignTemplate{}, &models.CampaignExecution{}, &models.BehavioralEvent{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	now := time.Now()
+	daysAgo := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+	newLead := func(id uint, source, email string, created time.Time) {
+		db.Create(&models.Lead{ID: id, FirstName: "Lead", Email: email, FUBLeadID: email, Source: source, CreatedAt: created})
+		db.Create(&models.LeadReengagement{FUBContactID: email})
+	}
+	newLead(1, "Zillow", "ann@example.com", daysAgo(20))
+	newLead(2, "Website", "ben@example.com", daysAgo(12))
+	newLead(3, "Website", "cy@example.com", daysAgo(8))
+	newLead(4, "Zillow", "old@example.com", daysAgo(60))
+
+	template := models.CampaignTemplate{Name: "Spring", EmailNumber: 1, Subject: "Spring"}
+	db.Create(&template)
+	send := func(reengagementID uint, at time.Time) {
+		db.Create(&models.CampaignExecution{LeadReengagementID: reengagementID, CampaignTemplateID: template.ID,
+			CampaignName: "spring_promo", Status: "sent", ExecutedAt: &at})
+	}
+	send(1, daysAgo(15))
+	send(2, daysAgo(1)) // after Ben converted
+	send(3, daysAgo(3))
+	db.Create(&models.BehavioralEvent{LeadID: 1, EventType: "email_clicked", EventData: models.JSONB{"campaign_id": "newsletter"}, CreatedAt: daysAgo(10)})
+
+	approval := models.Approval{ApprovalType: "rental_application", Status: "approved", FUBLeadID: "ann@example.com"}
+	db.Create(&approval)
+	db.Model(&approval).UpdateColumn("updated_at", daysAgo(5))
+	signed := daysAgo(2)
+	db.Create(&models.ClosingPipeline{PropertyAddress: "1 Main St", TenantEmail: security.EncryptedString("Ben@Example.com"), Status: "completed", LeaseSignedDate: &signed})
+
+	service := NewAttributionService(db, nil)
+	if _, err := service.BuildReport("u_shaped", daysAgo(30), now); err != ErrUnknownAttributionModel {
+		t.Errorf("Expected an unknown model error, got %v", err)
+	}
+
+	credited := func(report *AttributionReport) map[string]float64 {
+		credits := map[string]float64{}
+		for _, c := range report.Credits {
+			credits[c.Name] = c.Conversions
+		}
+		return credits
+	}
+
+	linear, err := service.BuildReport(AttributionLinear, daysAgo(30), now)
+	if err != nil {
+		t.Fatalf("BuildReport failed: %v", err)
+	}
+	if linear.ConvertedLeads != 2 || linear.UnconvertedLeads != 1 || linear.MultiTouchConversions != 1 || linear.AvgTouchesToConvert != 2 {
+		t.Errorf("Expected 2 converted (one multi-touch), 1 unconverted, got %+v", linear)
+	}
+	credits := credited(linear)
+	if credits["Website"] != 1 || credits["spring_promo"] < 0.33 || credits["spring_promo"] > 0.34 {
+		t.Errorf("Expected Ben's conversion to Website and a third of Ann's to spring_promo, got %v", credits)
+	}
+	if linear.Credits[0].Name != "Website" || linear.Credits[0].LeadsTouched != 2 {
+		t.Errorf("Expected Website first, touching Ben and Cy, got %+v", linear.Credits[0])
+	}
+
+	first, _ := service.BuildReport(AttributionFirstTouch, daysAgo(30), now)
+	if credits := credited(first); credits["Zillow"] != 1 || credits["newsletter"] != 0 {
+		t.Errorf("Expected first touch to credit Zillow, got %v", credits)
+	}
+	last, _ := service.BuildReport(AttributionLastTouch, daysAgo(30), now)
+	if credits := credited(last); credits["newsletter"] != 1 || credits["Zillow"] != 0 {
+		t.Errorf("Expected last touch to credit the newsletter, got %v", credits)
+	}
+}
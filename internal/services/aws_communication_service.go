@@ -1,10 +1,17 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -108,6 +115,87 @@ func (svc *AWSCommunicationService) SendEmail(to, subject, bodyHTML, bodyText st
 	return nil
 }
 
+// SendEmailWithHeaders sends an email with extra message headers (e.g.
+// List-Unsubscribe) via SES SendRawEmail, since SendEmail cannot set headers
+func (svc *AWSCommunicationService) SendEmailWithHeaders(to, subject, bodyHTML, bodyText string, headers map[string]string) error {
+	if !svc.enabled {
+		return fmt.Errorf("AWS email service not configured - check AWS_REGION, AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY environment variables")
+	}
+	if bodyText == "" {
+		bodyText = stripHTMLBasic(bodyHTML)
+	}
+
+	raw, err := buildRawEmail(svc.fromEmail, to, subject, bodyHTML, bodyText, headers)
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	result, err := svc.sesClient.SendRawEmail(context.Background(), &ses.SendRawEmailInput{
+		Destinations: []string{to},
+		Source:       aws.String(svc.fromEmail),
+		RawMessage:   &sestypes.RawMessage{Data: raw},
+	})
+	if err != nil {
+		log.Printf("❌ Failed to send email via SES to %s: %v", to, err)
+		return fmt.Errorf("SES send failed: %w", err)
+	}
+
+	log.Printf("✅ Email sent via SES to %s (MessageID: %s)", to, *result.MessageId)
+	return nil
+}
+
+// buildRawEmail renders a multipart/alternative MIME message with the given extra headers
+func buildRawEmail(from, to, subject, bodyHTML, bodyText string, headers map[string]string) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", bodyText},
+		{"text/html; charset=UTF-8", bodyHTML},
+	} {
+		if part.content == "" {
+			continue
+		}
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// Header values must not be able to inject further headers
+		value := strings.NewReplacer("\r", "", "\n", "").Replace(headers[name])
+		fmt.Fprintf(&msg, "%s: %s\r\n", textproto.CanonicalMIMEHeaderKey(name), value)
+	}
+
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
 // SendBulkEmail sends bulk emails via AWS SES (up to 50 recipients per call)
 func (svc *AWSCommunicationService) SendBulkEmail(recipients []string, subject, bodyHTML, bodyText string) (int, error) {
 	if !svc.enabled {
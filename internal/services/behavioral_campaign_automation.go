@@ -0,0 +1,219 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+// Campaign automations that tracked behavioral events feed
+const (
+	CampaignAutomationTriggers     = "campaign_triggers"
+	CampaignAutomationOrchestrator = "event_orchestrator"
+)
+
+const (
+	// campaignAutomationFiringLimit caps the recent firings kept for the status endpoint
+	campaignAutomationFiringLimit = 100
+	// campaignHotLeadScore is the composite score at which a lead counts as hot
+	campaignHotLeadScore = 70
+	// eventTriggerCooldown is the minimum time between firings of one trigger per lead
+	eventTriggerCooldown = 24 * time.Hour
+)
+
+// ErrUnknownCampaignAutomation is returned for an automation that isn't running
+var ErrUnknownCampaignAutomation = errors.New("unknown campaign automation")
+
+// CampaignAutomationFiring records a campaign that a tracked event set off
+type CampaignAutomationFiring struct {
+	Automation string    `json:"automation"`
+	TriggerID  string    `json:"trigger_id"`
+	LeadID     int64     `json:"lead_id"`
+	PropertyID *int64    `json:"property_id,omitempty"`
+	EventID    int64     `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	Status     string    `json:"status"` // "fired", "failed"
+	Error      string    `json:"error,omitempty"`
+	FiredAt    time.Time `json:"fired_at"`
+}
+
+// CampaignAutomationStatus describes one automation and the triggers it runs
+type CampaignAutomationStatus struct {
+	Name     string            `json:"name"`
+	Enabled  bool              `json:"enabled"`
+	Triggers []CampaignTrigger `json:"triggers"`
+}
+
+// BehavioralCampaignAutomation feeds tracked behavioral events into the
+// campaign trigger automation and the event campaign orchestrator
+type BehavioralCampaignAutomation struct {
+	db           *gorm.DB
+	triggers     *CampaignTriggerAutomation
+	orchestrator *EventCampaignOrchestrator
+	now          func() time.Time
+
+	mu        sync.Mutex
+	enabled   map[string]bool
+	lastFired map[string]time.Time
+	firings   []CampaignAutomationFiring
+}
+
+// NewBehavioralCampaignAutomation creates the event feed. Either automation may
+// be nil, in which case it is left out; the others start enabled.
+func NewBehavioralCampaignAutomation(db *gorm.DB, triggers *CampaignTriggerAutomation, orchestrator *EventCampaignOrchestrator) *BehavioralCampaignAutomation {
+	enabled := make(map[string]bool)
+	if triggers != nil {
+		enabled[CampaignAutomationTriggers] = true
+	}
+	if orchestrator != nil {
+		enabled[CampaignAutomationOrchestrator] = true
+	}
+	return &BehavioralCampaignAutomation{
+		db:           db,
+		triggers:     triggers,
+		orchestrator: orchestrator,
+		now:          time.Now,
+		enabled:      enabled,
+		lastFired:    make(map[string]time.Time),
+	}
+}
+
+// SetEnabled turns an automation on or off
+func (a *BehavioralCampaignAutomation) SetEnabled(name string, enabled bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.enabled[name]; !ok {
+		return ErrUnknownCampaignAutomation
+	}
+	a.enabled[name] = enabled
+	log.Printf("🎯 Campaign automation %s enabled=%t", name, enabled)
+	return nil
+}
+
+// Status lists the running automations and their triggers
+func (a *BehavioralCampaignAutomation) Status() []CampaignAutomationStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var statuses []CampaignAutomationStatus
+	if a.triggers != nil {
+		statuses = append(statuses, CampaignAutomationStatus{
+			Name:     CampaignAutomationTriggers,
+			Enabled:  a.enabled[CampaignAutomationTriggers],
+			Triggers: a.triggers.EventTriggers(),
+		})
+	}
+	if a.orchestrator != nil {
+		statuses = append(statuses, CampaignAutomationStatus{
+			Name:     CampaignAutomationOrchestrator,
+			Enabled:  a.enabled[CampaignAutomationOrchestrator],
+			Triggers: a.orchestrator.Triggers(),
+		})
+	}
+	return statuses
+}
+
+// RecentFirings returns up to limit of the latest firings, newest first
+func (a *BehavioralCampaignAutomation) RecentFirings(limit int) []CampaignAutomationFiring {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if limit <= 0 || limit > len(a.firings) {
+		limit = len(a.firings)
+	}
+	firings := make([]CampaignAutomationFiring, 0, limit)
+	for i := len(a.firings) - 1; i >= len(a.firings)-limit; i-- {
+		firings = append(firings, a.firings[i])
+	}
+	return firings
+}
+
+// HandleEvent runs a just-tracked behavioral event through the enabled
+// automations. Each trigger fires at most once per lead within its cooldown.
+func (a *BehavioralCampaignAutomation) HandleEvent(event *models.BehavioralEvent) {
+	if a.isEnabled(CampaignAutomationTriggers) {
+		matched, err := a.triggers.MatchEventTriggers(event)
+		if err != nil {
+			log.Printf("⚠️ Failed to match campaign triggers for event %d: %v", event.ID, err)
+		}
+		for _, trigger := range matched {
+			if !a.reserve(trigger.ID, event.LeadID, eventTriggerCooldown) {
+				continue
+			}
+			_, err := a.triggers.ExecuteEventTrigger(trigger, event)
+			a.record(CampaignAutomationTriggers, trigger.ID, event, err)
+		}
+	}
+
+	if a.isEnabled(CampaignAutomationOrchestrator) {
+		score, hot := a.hotLeadScore(event.LeadID)
+		if hot && a.reserve("lead_scored_hot", event.LeadID, a.orchestrator.CooldownFor("lead_scored_hot")) {
+			err := a.orchestrator.ProcessEvent("lead_scored_hot", map[string]interface{}{
+				"lead_id":       float64(event.LeadID),
+				"overall_score": float64(score),
+			})
+			a.record(CampaignAutomationOrchestrator, "lead_scored_hot", event, err)
+		}
+	}
+}
+
+func (a *BehavioralCampaignAutomation) isEnabled(name string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.enabled[name]
+}
+
+// reserve claims a trigger's firing for a lead, returning false while it is in cooldown
+func (a *BehavioralCampaignAutomation) reserve(triggerID string, leadID int64, cooldown time.Duration) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	key := fmt.Sprintf("%s:%d", triggerID, leadID)
+	now := a.now()
+	if last, ok := a.lastFired[key]; ok && now.Sub(last) < cooldown {
+		return false
+	}
+	a.lastFired[key] = now
+	return true
+}
+
+// hotLeadScore returns the lead's latest composite score and whether it is hot
+func (a *BehavioralCampaignAutomation) hotLeadScore(leadID int64) (int, bool) {
+	var scores []int
+	a.db.Model(&models.BehavioralScore{}).Where("lead_id = ?", leadID).
+		Order("updated_at DESC").Limit(1).Pluck("composite_score", &scores)
+	if len(scores) == 0 {
+		return 0, false
+	}
+	return scores[0], scores[0] >= campaignHotLeadScore
+}
+
+func (a *BehavioralCampaignAutomation) record(automation, triggerID string, event *models.BehavioralEvent, err error) {
+	firing := CampaignAutomationFiring{
+		Automation: automation,
+		TriggerID:  triggerID,
+		LeadID:     event.LeadID,
+		PropertyID: event.PropertyID,
+		EventID:    event.ID,
+		EventType:  event.EventType,
+		Status:     "fired",
+		FiredAt:    a.now(),
+	}
+	if err != nil {
+		firing.Status = "failed"
+		firing.Error = err.Error()
+		log.Printf("❌ Campaign trigger %s failed for lead %d: %v", triggerID, event.LeadID, err)
+	} else {
+		log.Printf("🎯 Campaign trigger %s fired for lead %d", triggerID, event.LeadID)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.firings = append(a.firings, firing)
+	if len(a.firings) > campaignAutomationFiringLimit {
+		a.firings = a.firings[len(a.firings)-campaignAutomationFiringLimit:]
+	}
+}
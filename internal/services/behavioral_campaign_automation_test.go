@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestBehavioralCampaignAutomation_FiresRepeatViewTriggerOncePerLead(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Lead{}, &models.BehavioralEvent{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	db.Create(&models.Lead{ID: 7, FirstName: "Dana", LastName: "Lee", Email: "dana@example.com", FUBLeadID: "fub-7", Status: "new"})
+
+	automation := NewBehavioralCampaignAutomation(db, NewCampaignTriggerAutomation(db, nil, nil, nil, nil), nil)
+	if err := automation.SetEnabled(CampaignAutomationOrchestrator, true); err != ErrUnknownCampaignAutomation {
+		t.Errorf("Expected the missing orchestrator to be unknown, got %v", err)
+	}
+
+	propertyID := int64(42)
+	view := func() {
+		event := &models.BehavioralEvent{LeadID: 7, EventType: "viewed", PropertyID: &propertyID}
+		db.Create(event)
+		automation.HandleEvent(event)
+	}
+
+	view()
+	view()
+	if firings := automation.RecentFirings(10); len(firings) != 0 {
+		t.Fatalf("Expected no firing before the third view, got %+v", firings)
+	}
+	view()
+	view()
+	firings := automation.RecentFirings(10)
+	if len(firings) != 1 || firings[0].TriggerID != "high_view" || firings[0].LeadID != 7 {
+		t.Fatalf("Expected one repeat-view firing within the cooldown, got %+v", firings)
+	}
+	// No email batch service is running, so the send is recorded as failed
+	if firings[0].Status != "failed" || firings[0].Error == "" {
+		t.Errorf("Expected the failed send to be recorded, got %+v", firings[0])
+	}
+
+	automation.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+	automation.SetEnabled(CampaignAutomationTriggers, false)
+	view()
+	if firings := automation.RecentFirings(10); len(firings) != 1 {
+		t.Errorf("Expected no firings while disabled, got %+v", firings)
+	}
+
+	status := automation.Status()
+	if len(status) != 1 || status[0].Name != CampaignAutomationTriggers || status[0].Enabled || len(status[0].Triggers) == 0 {
+		t.Errorf("Expected the disabled trigger automation in the status, got %+v", status)
+	}
+}
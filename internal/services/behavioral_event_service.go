@@ -13,6 +13,9 @@ import (
 type BehavioralEventService struct {
 	db            *gorm.DB
 	scoringEngine *BehavioralScoringEngine
+	cache         *IntelligenceCacheService
+
+	onSessionChange func()
 }
 
 
@@ -24,13 +27,35 @@ func NewBehavioralEventService(db *gorm.DB) *BehavioralEventService {
 	}
 }
 
+// SetSessionChangeListener registers a callback fired when a session starts or ends
+func (s *BehavioralEventService) SetSessionChangeListener(listener func()) {
+	s.onSessionChange = listener
+}
+
+// SetIntelligenceCache evicts a lead's cached intelligence when a new event
+// is stored for it or its score is recalculated
+func (s *BehavioralEventService) SetIntelligenceCache(cache *IntelligenceCacheService) {
+	s.cache = cache
+	s.scoringEngine.SetIntelligenceCache(cache)
+}
+
+func (s *BehavioralEventService) notifySessionChange() {
+	if s.onSessionChange != nil {
+		s.onSessionChange()
+	}
+}
+
 // ============================================================================
 // EVENT TRACKING (WITH AUTOMATIC SCORING)
 // ============================================================================
 
+// duplicateEventWindow is how close together two events with the same session,
+// lead, type, and property must be for the later one to count as a duplicate
+const duplicateEventWindow = 2 * time.Second
+
 // TrackEvent logs a behavioral event and triggers score recalculation
 func (s *BehavioralEventService) TrackEvent(leadID int64, eventType string, eventData map[string]interface{}, propertyID *int64, sessionID string, ipAddress string, userAgent string) error {
-	event := models.BehavioralEvent{
+	_, _, err := s.RecordEvent(&models.BehavioralEvent{
 		LeadID:     leadID,
 		EventType:  eventType,
 		EventData:  eventData,
@@ -38,45 +63,144 @@ func (s *BehavioralEventService) TrackEvent(leadID int64, eventType string, even
 		SessionID:  sessionID,
 		IPAddress:  ipAddress,
 		UserAgent:  userAgent,
+	})
+	return err
+}
+
+// RecordEvent stores a behavioral event unless it repeats one already stored,
+// either by client event ID or as a rapid-fire duplicate in the same session.
+// It returns the stored event and whether it was a duplicate; scores are only
+// recalculated for new events.
+func (s *BehavioralEventService) RecordEvent(event *models.BehavioralEvent) (*models.BehavioralEvent, bool, error) {
+	if event.ClientEventID != nil && *event.ClientEventID == "" {
+		event.ClientEventID = nil
 	}
 
-	if err := s.db.Create(&event).Error; err != nil {
-		log.Printf("❌ Failed to track event %s for lead %d: %v", eventType, leadID, err)
-		return err
+	if existing, err := s.findDuplicateEvent(event); err != nil {
+		return nil, false, err
+	} else if existing != nil {
+		log.Printf("🔁 Skipped duplicate event: %s for lead %d (event %d)", event.EventType, event.LeadID, existing.ID)
+		return existing, true, nil
+	}
+
+	if err := s.db.Create(event).Error; err != nil {
+		// A concurrent retry may have stored the same client event ID first
+		if event.ClientEventID != nil {
+			var existing models.BehavioralEvent
+			if lookupErr := s.db.Where("client_event_id = ?", *event.ClientEventID).First(&existing).Error; lookupErr == nil {
+				return &existing, true, nil
+			}
+		}
+		log.Printf("❌ Failed to track event %s for lead %d: %v", event.EventType, event.LeadID, err)
+		return nil, false, err
 	}
 
-	log.Printf("✅ Tracked event: %s for lead %d", eventType, leadID)
+	log.Printf("✅ Tracked event: %s for lead %d", event.EventType, event.LeadID)
+	s.touchSession(event.SessionID, event.CreatedAt)
+
+	if s.cache != nil {
+		if err := s.cache.InvalidateLead(event.LeadID); err != nil {
+			log.Printf("⚠️  %v", err)
+		}
+	}
 
 	// Trigger score recalculation asynchronously
+	leadID := event.LeadID
 	go func() {
 		if _, err := s.scoringEngine.CalculateScore(leadID); err != nil {
 			log.Printf("⚠️  Failed to recalculate score for lead %d: %v", leadID, err)
 		}
 	}()
 
-	return nil
+	return event, false, nil
 }
 
-// TrackPropertyView logs a property view event
-func (s *BehavioralEventService) TrackPropertyView(leadID int64, propertyID int64, sessionID string, ipAddress string, userAgent string) error {
+// touchSession records an event time on an open session so it is not closed as idle
+func (s *BehavioralEventService) touchSession(sessionID string, at time.Time) {
+	if sessionID == "" {
+		return
+	}
+	if err := s.db.Model(&models.BehavioralSession{}).
+		Where("id = ? AND end_time IS NULL", sessionID).
+		Update("last_event_at", at).Error; err != nil {
+		log.Printf("⚠️  Failed to update last event time for session %s: %v", sessionID, err)
+	}
+}
+
+// findDuplicateEvent returns a stored event that the given event repeats, or nil
+func (s *BehavioralEventService) findDuplicateEvent(event *models.BehavioralEvent) (*models.BehavioralEvent, error) {
+	var existing models.BehavioralEvent
+	if event.ClientEventID != nil {
+		err := s.db.Where("client_event_id = ?", *event.ClientEventID).First(&existing).Error
+		if err == nil {
+			return &existing, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+
+	if event.SessionID == "" {
+		return nil, nil
+	}
+	query := s.db.Where("session_id = ? AND lead_id = ? AND event_type = ? AND created_at >= ?",
+		event.SessionID, event.LeadID, event.EventType, time.Now().Add(-duplicateEventWindow))
+	if event.PropertyID != nil {
+		query = query.Where("property_id = ?", *event.PropertyID)
+	} else {
+		query = query.Where("property_id IS NULL")
+	}
+	err := query.Order("created_at DESC").First(&existing).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// TrackPropertyView logs a property view event. It returns the stored event and
+// whether it repeated one already stored.
+func (s *BehavioralEventService) TrackPropertyView(leadID int64, propertyID int64, sessionID string, clientEventID string, ipAddress string, userAgent string) (*models.BehavioralEvent, bool, error) {
 	eventData := map[string]interface{}{
 		"property_id": propertyID,
 		"action":      "view",
 	}
-	return s.TrackEvent(leadID, "viewed", eventData, &propertyID, sessionID, ipAddress, userAgent)
+	return s.RecordEvent(&models.BehavioralEvent{
+		LeadID:        leadID,
+		EventType:     "viewed",
+		EventData:     eventData,
+		PropertyID:    &propertyID,
+		SessionID:     sessionID,
+		ClientEventID: &clientEventID,
+		IPAddress:     ipAddress,
+		UserAgent:     userAgent,
+	})
 }
 
-// TrackPropertySave logs a property save event
-func (s *BehavioralEventService) TrackPropertySave(leadID int64, propertyID int64, sessionID string, ipAddress string, userAgent string) error {
+// TrackPropertySave logs a property save event. It returns the stored event and
+// whether it repeated one already stored.
+func (s *BehavioralEventService) TrackPropertySave(leadID int64, propertyID int64, sessionID string, clientEventID string, ipAddress string, userAgent string) (*models.BehavioralEvent, bool, error) {
 	eventData := map[string]interface{}{
 		"property_id": propertyID,
 		"action":      "save",
 	}
-	return s.TrackEvent(leadID, "saved", eventData, &propertyID, sessionID, ipAddress, userAgent)
+	return s.RecordEvent(&models.BehavioralEvent{
+		LeadID:        leadID,
+		EventType:     "saved",
+		EventData:     eventData,
+		PropertyID:    &propertyID,
+		SessionID:     sessionID,
+		ClientEventID: &clientEventID,
+		IPAddress:     ipAddress,
+		UserAgent:     userAgent,
+	})
 }
 
-// TrackInquiry logs an inquiry/contact form submission
-func (s *BehavioralEventService) TrackInquiry(leadID int64, propertyID *int64, inquiryType string, sessionID string, ipAddress string, userAgent string) error {
+// TrackInquiry logs an inquiry/contact form submission. It returns the stored
+// event and whether it repeated one already stored.
+func (s *BehavioralEventService) TrackInquiry(leadID int64, propertyID *int64, inquiryType string, sessionID string, clientEventID string, ipAddress string, userAgent string) (*models.BehavioralEvent, bool, error) {
 	eventData := map[string]interface{}{
 		"inquiry_type": inquiryType,
 		"action":       "inquiry",
@@ -84,7 +208,16 @@ func (s *BehavioralEventService) TrackInquiry(leadID int64, propertyID *int64, i
 	if propertyID != nil {
 		eventData["property_id"] = *propertyID
 	}
-	return s.TrackEvent(leadID, "inquired", eventData, propertyID, sessionID, ipAddress, userAgent)
+	return s.RecordEvent(&models.BehavioralEvent{
+		LeadID:        leadID,
+		EventType:     "inquired",
+		EventData:     eventData,
+		PropertyID:    propertyID,
+		SessionID:     sessionID,
+		ClientEventID: &clientEventID,
+		IPAddress:     ipAddress,
+		UserAgent:     userAgent,
+	})
 }
 
 // TrackApplication logs an application submission
@@ -124,19 +257,22 @@ func (s *BehavioralEventService) TrackEmailEngagement(leadID int64, eventType st
 // StartSession creates a new behavioral session
 func (s *BehavioralEventService) StartSession(leadID int64, ipAddress string, userAgent string, referrer string) (string, error) {
 	sessionID := uuid.New().String()
+	startTime := time.Now()
 	
 	session := models.BehavioralSession{
-		ID:        sessionID,
-		LeadID:    leadID,
-		StartTime: time.Now(),
-		IPAddress: ipAddress,
-		UserAgent: userAgent,
-		Referrer:  referrer,
+		ID:          sessionID,
+		LeadID:      leadID,
+		StartTime:   startTime,
+		LastEventAt: &startTime,
+		IPAddress:   ipAddress,
+		UserAgent:   userAgent,
+		Referrer:    referrer,
 	}
 
 	if err := s.db.Create(&session).Error; err != nil {
 		return "", err
 	}
+	s.notifySessionChange()
 
 	// Track session start event
 	eventData := map[string]interface{}{
@@ -163,7 +299,11 @@ func (s *BehavioralEventService) EndSession(sessionID string) error {
 		"duration_seconds": duration,
 	}
 
-	return s.db.Model(&session).Updates(updates).Error
+	if err := s.db.Model(&session).Updates(updates).Error; err != nil {
+		return err
+	}
+	s.notifySessionChange()
+	return nil
 }
 
 // UpdateSession updates session metrics
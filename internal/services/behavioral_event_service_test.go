@@ -0,0 +1,142 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupBehavioralEventTestService(t *testing.T) *BehavioralEventService {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.BehavioralEvent{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	// Score recalculation runs in the background; one connection keeps it on the same in-memory database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get database handle: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	return NewBehavioralEventService(db)
+}
+
+func countBehavioralEvents(t *testing.T, service *BehavioralEventService) int64 {
+	t.Helper()
+	var count int64
+	if err := service.db.Model(&models.BehavioralEvent{}).Count(&count).Error; err != nil {
+		t.Fatalf("Failed to count events: %v", err)
+	}
+	return count
+}
+
+func TestRecordEvent_RetryWithClientEventIDReturnsExistingEvent(t *testing.T) {
+	service := setupBehavioralEventTestService(t)
+
+	first, duplicate, err := service.TrackPropertyView(42, 7, "sess-1", "evt-abc", "127.0.0.1", "test")
+	if err != nil || duplicate {
+		t.Fatalf("Expected first view to be stored, got duplicate=%v err=%v", duplicate, err)
+	}
+
+	// Age the stored event past the rapid-fire window so only the client ID can match
+	service.db.Model(&models.BehavioralEvent{}).Where("id = ?", first.ID).
+		Update("created_at", time.Now().Add(-time.Hour))
+
+	retry, duplicate, err := service.TrackPropertyView(42, 7, "sess-1", "evt-abc", "127.0.0.1", "test")
+	if err != nil {
+		t.Fatalf("Retry failed: %v", err)
+	}
+	if !duplicate || retry.ID != first.ID {
+		t.Errorf("Expected retry to return event %d as a duplicate, got event %d duplicate=%v", first.ID, retry.ID, duplicate)
+	}
+	if count := countBehavioralEvents(t, service); count != 1 {
+		t.Errorf("Expected 1 stored event after retry, got %d", count)
+	}
+
+	if _, duplicate, err := service.TrackPropertyView(42, 7, "sess-1", "evt-def", "127.0.0.1", "test"); err != nil || duplicate {
+		t.Errorf("Expected a new client event ID to be stored, got duplicate=%v err=%v", duplicate, err)
+	}
+}
+
+func TestRecordEvent_DedupesRapidFireEventsInSameSession(t *testing.T) {
+	service := setupBehavioralEventTestService(t)
+
+	first, _, err := service.TrackPropertySave(42, 7, "sess-1", "", "127.0.0.1", "test")
+	if err != nil {
+		t.Fatalf("Failed to store save: %v", err)
+	}
+
+	repeat, duplicate, err := service.TrackPropertySave(42, 7, "sess-1", "", "127.0.0.1", "test")
+	if err != nil || !duplicate || repeat.ID != first.ID {
+		t.Errorf("Expected rapid repeat to return event %d, got %+v duplicate=%v err=%v", first.ID, repeat, duplicate, err)
+	}
+
+	if _, duplicate, _ := service.TrackPropertySave(42, 8, "sess-1", "", "127.0.0.1", "test"); duplicate {
+		t.Error("Expected a save of a different property to be stored")
+	}
+	if _, duplicate, _ := service.TrackPropertySave(42, 7, "sess-2", "", "127.0.0.1", "test"); duplicate {
+		t.Error("Expected a save in a different session to be stored")
+	}
+
+	service.db.Model(&models.BehavioralEvent{}).Where("id = ?", first.ID).
+		Update("created_at", time.Now().Add(-duplicateEventWindow-time.Second))
+	service.db.Where("id <> ?", first.ID).Delete(&models.BehavioralEvent{})
+	if _, duplicate, _ := service.TrackPropertySave(42, 7, "sess-1", "", "127.0.0.1", "test"); duplicate {
+		t.Error("Expected a repeat outside the window to be stored")
+	}
+
+	if count := countBehavioralEvents(t, service); count != 2 {
+		t.Errorf("Expected 2 stored events, got %d", count)
+	}
+}
+
+func TestCloseIdleSessions_ClosesOnlySessionsWithoutRecentEvents(t *testing.T) {
+	service := setupBehavioralEventTestService(t)
+	if err := service.db.AutoMigrate(&models.BehavioralSession{}); err != nil {
+		t.Fatalf("Failed to migrate sessions: %v", err)
+	}
+
+	now := time.Now()
+	lastEvent := now.Add(-45 * time.Minute)
+	sessions := []models.BehavioralSession{
+		{ID: "idle", LeadID: 1, StartTime: now.Add(-time.Hour), LastEventAt: &lastEvent},
+		{ID: "never-touched", LeadID: 2, StartTime: now.Add(-2 * time.Hour)},
+		{ID: "active", LeadID: 3, StartTime: now.Add(-time.Hour), LastEventAt: &lastEvent},
+	}
+	for i := range sessions {
+		if err := service.db.Create(&sessions[i]).Error; err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+	}
+
+	// A new event keeps the active session open
+	if _, _, err := service.TrackPropertyView(3, 7, "active", "", "127.0.0.1", "test"); err != nil {
+		t.Fatalf("Failed to track event: %v", err)
+	}
+
+	closed, err := service.CloseIdleSessions(30 * time.Minute)
+	if err != nil {
+		t.Fatalf("CloseIdleSessions failed: %v", err)
+	}
+	if closed != 2 {
+		t.Errorf("Expected 2 sessions closed, got %d", closed)
+	}
+
+	var idle models.BehavioralSession
+	service.db.First(&idle, "id = ?", "idle")
+	if idle.EndTime == nil || !idle.EndTime.Equal(lastEvent) || idle.DurationSeconds != 15*60 {
+		t.Errorf("Expected idle session to end at its last event after 15 minutes, got end=%v duration=%d", idle.EndTime, idle.DurationSeconds)
+	}
+
+	var active models.BehavioralSession
+	service.db.First(&active, "id = ?", "active")
+	if active.EndTime != nil {
+		t.Errorf("Expected active session to stay open, got end=%v", active.EndTime)
+	}
+}
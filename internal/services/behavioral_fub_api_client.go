@@ -2,11 +2,14 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -19,6 +22,7 @@ type BehavioralFUBAPIClient struct {
 	apiKey       string
 	baseURL      string
 	errorHandler *FUBErrorHandler
+	batch        *FUBBatchService // Rate limits and batches writes when set
 }
 
 // NewBehavioralFUBAPIClient creates a new behavioral intelligence-driven FUB API client
@@ -32,6 +36,18 @@ func NewBehavioralFUBAPIClient(db *gorm.DB, apiKey string) *BehavioralFUBAPIClie
 	}
 }
 
+// SetBatchService routes writes through the FUB batch service's rate limiter;
+// without one, writes call the FUB API directly
+func (client *BehavioralFUBAPIClient) SetBatchService(batch *FUBBatchService) {
+	client.batch = batch
+}
+
+// SetErrorHandler shares an error handler so failures are classified and
+// counted alongside other FUB callers
+func (client *BehavioralFUBAPIClient) SetErrorHandler(errorHandler *FUBErrorHandler) {
+	client.errorHandler = errorHandler
+}
+
 // FUBContact represents a FUB contact/lead structure
 type FUBContact struct {
 	ID           string                 `json:"id,omitempty"`
@@ -141,33 +157,56 @@ func (client *BehavioralFUBAPIClient) makeRequest(method, endpoint string, body
 
 	// Use error handler with retry logic
 	return client.errorHandler.ExecuteWithRetry(operation, func() (*http.Response, error) {
-		var reqBody *bytes.Buffer
-		if body != nil {
-			jsonBody, err := json.Marshal(body)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal request body: %v", err)
-			}
-			reqBody = bytes.NewBuffer(jsonBody)
-		} else {
-			reqBody = bytes.NewBuffer(nil)
+		if client.batch != nil && method != "GET" {
+			return client.batch.Do(context.Background(), fubWritePriority(endpoint), func() (*http.Response, error) {
+				return client.send(method, endpoint, body)
+			})
 		}
+		return client.send(method, endpoint, body)
+	})
+}
 
-		url := fmt.Sprintf("%s%s", client.baseURL, endpoint)
-		req, err := http.NewRequest(method, url, reqBody)
+// fubWritePriority orders a write within a batch the way the batch service's
+// own queues do: contacts first, then everything else, then notes
+func fubWritePriority(endpoint string) int {
+	switch {
+	case strings.HasPrefix(endpoint, "/people"):
+		return 1
+	case strings.HasPrefix(endpoint, "/notes"):
+		return 3
+	default:
+		return 2
+	}
+}
+
+// send makes one authenticated HTTP request to the FUB API
+func (client *BehavioralFUBAPIClient) send(method, endpoint string, body interface{}) (*http.Response, error) {
+	var reqBody *bytes.Buffer
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %v", err)
+			return nil, fmt.Errorf("failed to marshal request body: %v", err)
 		}
+		reqBody = bytes.NewBuffer(jsonBody)
+	} else {
+		reqBody = bytes.NewBuffer(nil)
+	}
+
+	url := fmt.Sprintf("%s%s", client.baseURL, endpoint)
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
 
-		// Set headers
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Accept", "application/json")
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
 
-		// FUB API uses Basic Auth with API key as username and empty password
-		auth := base64.StdEncoding.EncodeToString([]byte(client.apiKey + ":"))
-		req.Header.Set("Authorization", "Basic "+auth)
+	// FUB API uses Basic Auth with API key as username and empty password
+	auth := base64.StdEncoding.EncodeToString([]byte(client.apiKey + ":"))
+	req.Header.Set("Authorization", "Basic "+auth)
 
-		return client.client.Do(req)
-	})
+	return client.client.Do(req)
 }
 
 // CreateContact creates a new contact in FUB with behavioral intelligence data
@@ -213,6 +252,31 @@ func (client *BehavioralFUBAPIClient) UpdateContact(contactID string, updates *F
 	return &result, nil
 }
 
+// FindContactByEmail looks up a FUB contact by email, returning nil if there is none
+func (client *BehavioralFUBAPIClient) FindContactByEmail(email string) (*FUBContact, error) {
+	endpoint := "/people?limit=1&email=" + url.QueryEscape(email)
+	resp, err := client.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to find contact: HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		People []FUBContact `json:"people"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	if len(result.People) == 0 {
+		return nil, nil
+	}
+	return &result.People[0], nil
+}
+
 // CreateDeal creates a new deal in FUB with behavioral intelligence data
 func (client *BehavioralFUBAPIClient) CreateDeal(deal *FUBDeal) (*FUBDeal, error) {
 	resp, err := client.makeRequest("POST", "/deals", deal)
@@ -386,6 +450,28 @@ func (client *BehavioralFUBAPIClient) CreateTask(contactID, title, description s
 	return nil
 }
 
+// AddNote adds a note to a contact's FUB timeline
+func (client *BehavioralFUBAPIClient) AddNote(contactID, subject, body string) error {
+	payload := map[string]interface{}{
+		"contactId": contactID,
+		"subject":   subject,
+		"body":      body,
+	}
+
+	resp, err := client.makeRequest("POST", "/notes", payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 201 {
+		return fmt.Errorf("failed to add note: HTTP %d", resp.StatusCode)
+	}
+
+	log.Printf("✅ Added FUB note for contact %s", contactID)
+	return nil
+}
+
 // GetAgents retrieves available agents from FUB
 func (client *BehavioralFUBAPIClient) GetAgents() ([]map[string]interface{}, error) {
 	resp, err := client.makeRequest("GET", "/users", nil)
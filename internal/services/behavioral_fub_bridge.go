@@ -1,12 +1,14 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
+	"time"
 
+	"chrisgross-ctrl-project/internal/logging"
 	"gorm.io/gorm"
 )
 
@@ -14,16 +16,48 @@ import (
 type BehavioralFUBBridge struct {
 	db                 *gorm.DB
 	integrationService *BehavioralFUBIntegrationService
+	pusher             fubContactPusher
+	safety             *LeadSafetyFilter
+	now                func() time.Time
 }
 
 // NewBehavioralFUBBridge creates a new bridge service
 func NewBehavioralFUBBridge(db *gorm.DB, apiKey string) *BehavioralFUBBridge {
+	integrationService := NewBehavioralFUBIntegrationService(db, apiKey)
 	return &BehavioralFUBBridge{
 		db:                 db,
-		integrationService: NewBehavioralFUBIntegrationService(db, apiKey),
+		integrationService: integrationService,
+		pusher:             integrationService,
+		now:                time.Now,
 	}
 }
 
+// SetErrorHandler shares an error handler with the bridge's FUB API client
+func (bridge *BehavioralFUBBridge) SetErrorHandler(errorHandler *FUBErrorHandler) {
+	bridge.integrationService.SetErrorHandler(errorHandler)
+}
+
+// SetBatchService routes the bridge's FUB writes through the batch service's
+// rate limiter
+func (bridge *BehavioralFUBBridge) SetBatchService(batch *FUBBatchService) {
+	bridge.integrationService.SetBatchService(batch)
+}
+
+// SetSafetyFilter stops the bridge pushing contacts that fail the contact
+// safety check to FUB
+func (bridge *BehavioralFUBBridge) SetSafetyFilter(safety *LeadSafetyFilter) {
+	bridge.safety = safety
+}
+
+// contactBlock returns the blocked lead and why the request's contact must not
+// be pushed to FUB, or "" if it may be
+func (bridge *BehavioralFUBBridge) contactBlock(request *BehavioralTriggerRequest) (string, string, error) {
+	if bridge.safety == nil {
+		return "", "", nil
+	}
+	return bridge.safety.ContactBlockForEmail(request.Email)
+}
+
 // BehavioralTriggerRequest represents incoming behavioral trigger from handlers
 type BehavioralTriggerRequest struct {
 	SessionID             string                 `json:"session_id"`
@@ -43,9 +77,18 @@ type BehavioralTriggerRequest struct {
 	TimelineContext       map[string]interface{} `json:"timeline_context"`
 }
 
-// ProcessBehavioralTriggerForFUB processes behavioral intelligence triggers and executes FUB operations
-func (bridge *BehavioralFUBBridge) ProcessBehavioralTriggerForFUB(request *BehavioralTriggerRequest) (*BehavioralTriggerResult, error) {
-	log.Printf("🌉 Processing behavioral trigger for FUB integration: %s", request.TriggerType)
+// ProcessBehavioralTriggerForFUB processes behavioral intelligence triggers and executes FUB operations.
+// Log lines go through the request-scoped logger carried by ctx.
+func (bridge *BehavioralFUBBridge) ProcessBehavioralTriggerForFUB(ctx context.Context, request *BehavioralTriggerRequest) (*BehavioralTriggerResult, error) {
+	logger := logging.FromContext(ctx).With("trigger_type", request.TriggerType, "session_id", request.SessionID)
+	logger.Info("processing behavioral trigger for FUB integration")
+
+	if _, reason, err := bridge.contactBlock(request); err != nil {
+		return nil, err
+	} else if reason != "" {
+		logger.Warn("behavioral trigger blocked by lead safety filter", "reason", reason)
+		return nil, fmt.Errorf("%w: %s", ErrLeadContactBlocked, reason)
+	}
 
 	// Convert behavioral trigger request to property category trigger data
 	triggerData := bridge.convertToPropertyCategoryData(request)
@@ -58,7 +101,13 @@ func (bridge *BehavioralFUBBridge) ProcessBehavioralTriggerForFUB(request *Behav
 	}
 
 	// Process through the behavioral integration service
-	return bridge.integrationService.ProcessBehavioralTrigger(triggerData, contactInfo)
+	result, err := bridge.integrationService.ProcessBehavioralTrigger(triggerData, contactInfo)
+	if err != nil {
+		logger.Error("behavioral trigger failed", "error", err)
+		return nil, err
+	}
+	logger.Info("behavioral trigger processed", "property_category", triggerData.PropertyCategory)
+	return result, nil
 }
 
 // convertToPropertyCategoryData converts behavioral trigger request to property category trigger data
@@ -473,12 +522,12 @@ func (bridge *BehavioralFUBBridge) getCurrentSeasonalFactor() float64 {
 }
 
 // IntegrateBehavioralTriggerWithFUB is the main entry point for behavioral intelligence → FUB integration
-func (bridge *BehavioralFUBBridge) IntegrateBehavioralTriggerWithFUB(triggerJSON []byte) (*BehavioralTriggerResult, error) {
+func (bridge *BehavioralFUBBridge) IntegrateBehavioralTriggerWithFUB(ctx context.Context, triggerJSON []byte) (*BehavioralTriggerResult, error) {
 	var request BehavioralTriggerRequest
 
 	if err := json.Unmarshal(triggerJSON, &request); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal trigger request: %v", err)
 	}
 
-	return bridge.ProcessBehavioralTriggerForFUB(&request)
+	return bridge.ProcessBehavioralTriggerForFUB(ctx, &request)
 }
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"chrisgross-ctrl-project/internal/logging"
+	"chrisgross-ctrl-project/internal/models"
+)
+
+// fubPushRetryBatch caps how many pending pushes one retry run re-sends
+const fubPushRetryBatch = 50
+
+// FUBContactPush is what a context trigger decided to send to FUB along with
+// the contact: extra tags, a timeline note and the follow-up task
+type FUBContactPush struct {
+	Tags              []string  `json:"tags"`
+	Note              string    `json:"note"`
+	TaskTitle         string    `json:"task_title"`
+	FollowUpAt        time.Time `json:"follow_up_at"`
+	Priority          string    `json:"priority"`
+	WorkflowType      string    `json:"workflow_type"`
+	RecommendedAction string    `json:"recommended_action"`
+}
+
+// fubContactPusher is the part of BehavioralFUBIntegrationService a push uses
+type fubContactPusher interface {
+	PushContact(contactInfo map[string]string, triggerData *PropertyCategoryTriggerData, push FUBContactPush) (*BehavioralTriggerResult, error)
+}
+
+// contextFUBPushPayload is stored with a push so a retry can re-send it
+type contextFUBPushPayload struct {
+	Request *BehavioralTriggerRequest `json:"request"`
+	Push    FUBContactPush            `json:"push"`
+}
+
+// PushHighIntentTrigger upserts the trigger's FUB contact with the push's tags
+// and note and schedules its follow-up, recording the outcome. If the FUB API
+// fails the push is recorded pending retry and the error returned; a contact
+// failing the lead safety check is recorded blocked with ErrLeadContactBlocked.
+func (bridge *BehavioralFUBBridge) PushHighIntentTrigger(ctx context.Context, triggerID string, request *BehavioralTriggerRequest, push FUBContactPush) (*BehavioralTriggerResult, error) {
+	payload, err := json.Marshal(contextFUBPushPayload{Request: request, Push: push})
+	if err != nil {
+		return nil, err
+	}
+	record := &models.ContextFUBPush{
+		TriggerID:    triggerID,
+		SessionID:    request.SessionID,
+		Email:        request.Email,
+		Priority:     push.Priority,
+		WorkflowType: push.WorkflowType,
+		Payload:      string(payload),
+	}
+
+	// A failed safety check is retried like a failed push
+	var result *BehavioralTriggerResult
+	leadID, reason, pushErr := bridge.contactBlock(request)
+	if pushErr == nil && reason != "" {
+		bridge.recordBlocked(record, leadID, reason)
+		if err := bridge.db.Create(record).Error; err != nil {
+			logging.FromContext(ctx).Error("failed to record FUB push", "trigger_id", triggerID, "error", err)
+		}
+		logging.FromContext(ctx).Warn("FUB push blocked by lead safety filter", "trigger_id", triggerID, "reason", reason)
+		return nil, fmt.Errorf("%w: %s", ErrLeadContactBlocked, reason)
+	}
+	if pushErr == nil {
+		result, pushErr = bridge.pushContact(request, push)
+	}
+	bridge.recordAttempt(record, result, pushErr)
+	if err := bridge.db.Create(record).Error; err != nil {
+		logging.FromContext(ctx).Error("failed to record FUB push", "trigger_id", triggerID, "error", err)
+	}
+	if pushErr != nil {
+		logging.FromContext(ctx).Warn("FUB push failed, queued for retry", "trigger_id", triggerID, "error", pushErr)
+		return nil, pushErr
+	}
+	return result, nil
+}
+
+// RetryPendingPushes re-sends pushes whose retry is due, returning how many
+// succeeded. A push still failing after MaxFUBPushRetries is marked failed,
+// and one whose contact has since failed the safety check is marked blocked.
+func (bridge *BehavioralFUBBridge) RetryPendingPushes() (int, error) {
+	var pending []models.ContextFUBPush
+	err := bridge.db.Where("status = ? AND next_retry <= ?", models.FUBPushStatusPendingRetry, bridge.now()).
+		Order("next_retry ASC").Limit(fubPushRetryBatch).Find(&pending).Error
+	if err != nil {
+		return 0, err
+	}
+
+	pushed := 0
+	for i := range pending {
+		record := &pending[i]
+		var payload contextFUBPushPayload
+		if err := json.Unmarshal([]byte(record.Payload), &payload); err != nil || payload.Request == nil {
+			record.Status = models.FUBPushStatusFailed
+			record.LastError = fmt.Sprintf("unreadable push payload: %v", err)
+			record.NextRetry = nil
+		} else if leadID, reason, err := bridge.contactBlock(payload.Request); err == nil && reason != "" {
+			bridge.recordBlocked(record, leadID, reason)
+		} else {
+			record.RetryCount++
+			var result *BehavioralTriggerResult
+			pushErr := err
+			if pushErr == nil {
+				result, pushErr = bridge.pushContact(payload.Request, payload.Push)
+			}
+			bridge.recordAttempt(record, result, pushErr)
+			if pushErr == nil {
+				pushed++
+			}
+		}
+		if err := bridge.db.Save(record).Error; err != nil {
+			return pushed, err
+		}
+	}
+	return pushed, nil
+}
+
+// StartPushRetries retries pending FUB pushes on an interval until ctx is cancelled
+func (bridge *BehavioralFUBBridge) StartPushRetries(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 FUB push retries stopped")
+				return
+			case <-ticker.C:
+				if pushed, err := bridge.RetryPendingPushes(); err != nil {
+					log.Printf("⚠️ Failed to retry FUB pushes: %v", err)
+				} else if pushed > 0 {
+					log.Printf("🔁 Pushed %d queued triggers to FUB", pushed)
+				}
+			}
+		}
+	}()
+
+	log.Printf("🕐 FUB push retries started (every %v)", interval)
+}
+
+// pushContact sends one push through the integration service
+func (bridge *BehavioralFUBBridge) pushContact(request *BehavioralTriggerRequest, push FUBContactPush) (*BehavioralTriggerResult, error) {
+	contactInfo := map[string]string{
+		"name":  request.Name,
+		"email": request.Email,
+		"phone": request.Phone,
+	}
+	return bridge.pusher.PushContact(contactInfo, bridge.convertToPropertyCategoryData(request), push)
+}
+
+// recordBlocked marks a push the lead safety filter refused, which is never retried
+func (bridge *BehavioralFUBBridge) recordBlocked(record *models.ContextFUBPush, leadID, reason string) {
+	record.Status = models.FUBPushStatusBlocked
+	record.LastError = reason
+	record.NextRetry = nil
+	bridge.safety.RecordExclusion(leadID, "fub_push", record.TriggerID, reason)
+}
+
+// recordAttempt applies a push outcome to its record, backing off retries
+// exponentially from one minute
+func (bridge *BehavioralFUBBridge) recordAttempt(record *models.ContextFUBPush, result *BehavioralTriggerResult, pushErr error) {
+	now := bridge.now()
+	if pushErr == nil {
+		record.Status = models.FUBPushStatusPushed
+		record.ContactID = result.ContactID
+		record.AutomationID = result.AutomationID
+		record.LastError = ""
+		record.NextRetry = nil
+		record.PushedAt = &now
+		return
+	}
+
+	record.LastError = pushErr.Error()
+	if record.RetryCount >= models.MaxFUBPushRetries {
+		record.Status = models.FUBPushStatusFailed
+		record.NextRetry = nil
+		return
+	}
+	record.Status = models.FUBPushStatusPendingRetry
+	nextRetry := now.Add(time.Minute << uint(record.RetryCount))
+	record.NextRetry = &nextRetry
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type flakyContactPusher struct {
+	failures int
+	pushes   []FUBContactPush
+}
+
+func (f *flakyContactPusher) PushContact(contactInfo map[string]string, triggerData *PropertyCategoryTriggerData, push FUBContactPush) (*BehavioralTriggerResult, error) {
+	f.pushes = append(f.pushes, push)
+	if f.failures > 0 {
+		f.failures--
+		return nil, errors.New("failed to upsert contact: HTTP 503")
+	}
+	return &BehavioralTriggerResult{Success: true, ContactID: "fub-42", AutomationID: "rental_placement"}, nil
+}
+
+func TestPushHighIntentTrigger_QueuesFailuresForRetry(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.ContextFUBPush{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	pusher := &flakyContactPusher{failures: 1}
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	bridge := &BehavioralFUBBridge{db: db, pusher: pusher, now: func() time.Time { return now }}
+	request := &BehavioralTriggerRequest{SessionID: "sess-1", Email: "dana@example.com", Name: "Dana Lee", PropertyType: "rental"}
+	push := FUBContactPush{Tags: []string{"context_high_intent"}, TaskTitle: "HIGH priority follow-up", Priority: "HIGH"}
+
+	if _, err := bridge.PushHighIntentTrigger(context.Background(), "trig_1", request, push); err == nil {
+		t.Fatal("Expected the FUB failure to be returned")
+	}
+	var record models.ContextFUBPush
+	db.First(&record, "trigger_id = ?", "trig_1")
+	if record.Status != models.FUBPushStatusPendingRetry || record.NextRetry == nil {
+		t.Fatalf("Expected the push pending retry, got %q", record.Status)
+	}
+
+	if pushed, _ := bridge.RetryPendingPushes(); pushed != 0 {
+		t.Errorf("Expected no retry before next_retry, got %d", pushed)
+	}
+	now = now.Add(2 * time.Minute)
+	if pushed, err := bridge.RetryPendingPushes(); err != nil || pushed != 1 {
+		t.Fatalf("Expected the retry to push, got %d (%v)", pushed, err)
+	}
+	db.First(&record, "trigger_id = ?", "trig_1")
+	if record.Status != models.FUBPushStatusPushed || record.ContactID != "fub-42" || record.RetryCount != 1 {
+		t.Errorf("Expected the push recorded with its contact after one retry, got %+v", record)
+	}
+	if got := pusher.pushes[1].Tags; len(got) != 1 || got[0] != "context_high_intent" {
+		t.Errorf("Expected the retry to re-send the stored tags, got %v", got)
+	}
+
+	// A push that keeps failing is eventually given up on
+	pusher.failures = models.MaxFUBPushRetries + 1
+	bridge.PushHighIntentTrigger(context.Background(), "trig_2", request, push)
+	for i := 0; i < models.MaxFUBPushRetries; i++ {
+		now = now.Add(time.Hour)
+		bridge.RetryPendingPushes()
+	}
+	record = models.ContextFUBPush{}
+	db.First(&record, "trigger_id = ?", "trig_2")
+	if record.Status != models.FUBPushStatusFailed {
+		t.Errorf("Expected the push marked failed after %d retries, got %q", models.MaxFUBPushRetries, record.Status)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"chrisgross-ctrl-project/internal/utils"
 	"gorm.io/gorm"
 )
 
@@ -23,6 +24,16 @@ func NewBehavioralFUBIntegrationService(db *gorm.DB, apiKey string) *BehavioralF
 	}
 }
 
+// SetBatchService routes FUB writes through the batch service's rate limiter
+func (service *BehavioralFUBIntegrationService) SetBatchService(batch *FUBBatchService) {
+	service.apiClient.SetBatchService(batch)
+}
+
+// SetErrorHandler shares an error handler with the FUB API client
+func (service *BehavioralFUBIntegrationService) SetErrorHandler(errorHandler *FUBErrorHandler) {
+	service.apiClient.SetErrorHandler(errorHandler)
+}
+
 // PropertyCategoryTriggerData represents property-specific trigger data
 type PropertyCategoryTriggerData struct {
 	PropertyCategory string                 `json:"property_category"`
@@ -108,12 +119,31 @@ func (service *BehavioralFUBIntegrationService) ProcessBehavioralTrigger(trigger
 
 // createOrUpdateContact creates or updates a FUB contact with behavioral intelligence data
 func (service *BehavioralFUBIntegrationService) createOrUpdateContact(contactInfo map[string]string, triggerData *PropertyCategoryTriggerData) (*FUBContact, error) {
-	// Extract name components
-	fullName := contactInfo["name"]
-	nameParts := strings.Fields(fullName)
-	firstName := nameParts[0]
+	contact := service.buildBehavioralContact(contactInfo, triggerData)
+
+	// Try to create the contact
+	result, err := service.apiClient.CreateContact(contact)
+	if err != nil {
+		// If creation fails, it might already exist - try to find and update
+		log.Printf("🔄 Contact creation failed, attempting update: %v", err)
+		// For now, return the creation error - in production you'd implement contact lookup
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// buildBehavioralContact builds a FUB contact tagged and annotated with behavioral intelligence data
+func (service *BehavioralFUBIntegrationService) buildBehavioralContact(contactInfo map[string]string, triggerData *PropertyCategoryTriggerData) *FUBContact {
+	// Extract name components, falling back to the email for anonymous visitors
+	fullName := strings.TrimSpace(contactInfo["name"])
+	if fullName == "" {
+		fullName = contactInfo["email"]
+	}
+	firstName := ""
 	lastName := ""
-	if len(nameParts) > 1 {
+	if nameParts := strings.Fields(fullName); len(nameParts) > 0 {
+		firstName = nameParts[0]
 		lastName = strings.Join(nameParts[1:], " ")
 	}
 
@@ -133,7 +163,7 @@ func (service *BehavioralFUBIntegrationService) createOrUpdateContact(contactInf
 		"houston_location":      triggerData.Location,
 	}
 
-	contact := &FUBContact{
+	return &FUBContact{
 		Name:         fullName,
 		FirstName:    firstName,
 		LastName:     lastName,
@@ -143,19 +173,79 @@ func (service *BehavioralFUBIntegrationService) createOrUpdateContact(contactInf
 		Tags:         tags,
 		CustomFields: customFields,
 	}
+}
 
-	// Try to create the contact
-	result, err := service.apiClient.CreateContact(contact)
+// PushContact upserts a FUB contact with behavioral data plus the push's tags
+// and note, and schedules its follow-up task. The contact, note and task must
+// all succeed; the action plan is best effort and, as FUB's automation for the
+// contact, is returned as the AutomationID.
+func (service *BehavioralFUBIntegrationService) PushContact(contactInfo map[string]string, triggerData *PropertyCategoryTriggerData, push FUBContactPush) (*BehavioralTriggerResult, error) {
+	if service.apiClient.apiKey == "" {
+		return nil, fmt.Errorf("FUB API key is not configured")
+	}
+	if contactInfo["email"] == "" && contactInfo["phone"] == "" {
+		return nil, fmt.Errorf("an email or phone is required to push a FUB contact")
+	}
+
+	contact := service.buildBehavioralContact(contactInfo, triggerData)
+	for _, tag := range push.Tags {
+		if tag != "" && !utils.Contains(contact.Tags, tag) {
+			contact.Tags = append(contact.Tags, tag)
+		}
+	}
+
+	upserted, err := service.upsertContact(contact)
 	if err != nil {
-		// If creation fails, it might already exist - try to find and update
-		log.Printf("🔄 Contact creation failed, attempting update: %v", err)
-		// For now, return the creation error - in production you'd implement contact lookup
-		return nil, err
+		return nil, fmt.Errorf("failed to upsert contact: %w", err)
+	}
+
+	if push.Note != "" {
+		if err := service.apiClient.AddNote(upserted.ID, push.TaskTitle, push.Note); err != nil {
+			return nil, fmt.Errorf("failed to add note: %w", err)
+		}
+	}
+	if err := service.apiClient.CreateTask(upserted.ID, push.TaskTitle, push.Note, push.FollowUpAt, push.Priority); err != nil {
+		return nil, fmt.Errorf("failed to schedule follow-up: %w", err)
 	}
 
+	result := &BehavioralTriggerResult{
+		Success:           true,
+		ContactID:         upserted.ID,
+		WorkflowType:      push.WorkflowType,
+		RecommendedAction: push.RecommendedAction,
+		Priority:          push.Priority,
+		ScheduledAt:       push.FollowUpAt,
+		ProcessedAt:       time.Now(),
+	}
+	actionPlanID, err := service.assignBehavioralActionPlan(upserted.ID, triggerData)
+	if err != nil {
+		log.Printf("⚠️ Failed to assign action plan: %v", err) // Non-fatal
+	} else {
+		result.ActionPlanID = actionPlanID
+		result.AutomationID = actionPlanID
+	}
 	return result, nil
 }
 
+// upsertContact updates the FUB contact with the same email, or creates one
+func (service *BehavioralFUBIntegrationService) upsertContact(contact *FUBContact) (*FUBContact, error) {
+	if contact.Email != "" {
+		existing, err := service.apiClient.FindContactByEmail(contact.Email)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			for _, tag := range existing.Tags {
+				if !utils.Contains(contact.Tags, tag) {
+					contact.Tags = append(contact.Tags, tag)
+				}
+			}
+			return service.apiClient.UpdateContact(existing.ID, contact)
+		}
+	}
+	return service.apiClient.CreateContact(contact)
+}
+
 // determineWorkflowAndPriority determines the appropriate workflow and priority based on behavioral intelligence
 func (service *BehavioralFUBIntegrationService) determineWorkflowAndPriority(triggerData *PropertyCategoryTriggerData) (string, string) {
 	category := triggerData.PropertyCategory
@@ -8,11 +8,19 @@ import (
 	"chrisgross-ctrl-project/internal/models"
 )
 
+// Component weights in the composite score
+const (
+	urgencyScoreWeight    = 0.40
+	engagementScoreWeight = 0.40
+	financialScoreWeight  = 0.20
+)
+
 // BehavioralScoringEngine calculates and manages behavioral scores for leads
 type BehavioralScoringEngine struct {
 	db           *gorm.DB
 	scoringRules *ScoringRules
 	notificationHub *AdminNotificationHub
+	cache           *IntelligenceCacheService
 }
 
 // NewBehavioralScoringEngine creates a new scoring engine
@@ -40,9 +48,9 @@ func (e *BehavioralScoringEngine) CalculateScore(leadID int64) (*models.Behavior
 	
 	// Calculate composite score (weighted average, 0-100)
 	compositeScore := int(
-		(float64(urgencyScore) * 0.40) +
-		(float64(engagementScore) * 0.40) +
-		(float64(financialScore) * 0.20),
+		(float64(urgencyScore) * urgencyScoreWeight) +
+		(float64(engagementScore) * engagementScoreWeight) +
+		(float64(financialScore) * financialScoreWeight),
 	)
 
 	// Build score factors JSON
@@ -211,15 +219,31 @@ func (e *BehavioralScoringEngine) SaveScore(score *models.BehavioralScore) error
 			e.notificationHub.SendHotLeadAlert(leadName, score.CompositeScore, int64(*score.LeadID))
 		}
 	}
+
+	// The lead's cached score and insights no longer reflect this score
+	if e.cache != nil && score.LeadID != nil {
+		if err := e.cache.InvalidateLead(int64(*score.LeadID)); err != nil {
+			log.Printf("⚠️ %v", err)
+		}
+	}
 	
 	return nil
 }
 
 // GetScore retrieves the current score for a lead
 func (e *BehavioralScoringEngine) GetScore(leadID int64) (*models.BehavioralScore, error) {
+	if e.cache != nil {
+		if cached, err := e.cache.GetLeadScore(leadID); err == nil {
+			return cached, nil
+		}
+	}
+
 	var score models.BehavioralScore
 	leadIDInt := int(leadID)
 	err := e.db.Where("lead_id = ?", &leadIDInt).First(&score).Error
+	if err == nil && e.cache != nil {
+		e.cache.SetLeadScore(leadID, &score)
+	}
 	return &score, err
 }
 
@@ -242,6 +266,12 @@ func (e *BehavioralScoringEngine) RecalculateAllScores() error {
 	return nil
 }
 
+// SetIntelligenceCache serves scores from the intelligence cache and evicts a
+// lead's cached intelligence whenever its score is saved
+func (e *BehavioralScoringEngine) SetIntelligenceCache(cache *IntelligenceCacheService) {
+	e.cache = cache
+}
+
 func (e *BehavioralScoringEngine) SetNotificationHub(hub *AdminNotificationHub) {
 	e.notificationHub = hub
 	log.Println("🔔 Notification hub connected to scoring engine")
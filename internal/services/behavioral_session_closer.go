@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+)
+
+// idleSessionCloseBatch caps how many sessions one pass loads at a time
+const idleSessionCloseBatch = 500
+
+// StartIdleSessionCloser runs CloseIdleSessions every interval until ctx is
+// cancelled, so sessions whose visitors left without an end event stop
+// counting as active
+func (s *BehavioralEventService) StartIdleSessionCloser(ctx context.Context, idleTimeout, interval time.Duration) {
+	if idleTimeout <= 0 {
+		idleTimeout = 30 * time.Minute
+	}
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if closed, err := s.CloseIdleSessions(idleTimeout); err != nil {
+				log.Printf("⚠️ Failed to close idle behavioral sessions: %v", err)
+			} else if closed > 0 {
+				log.Printf("🧹 Closed %d idle behavioral sessions", closed)
+			}
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 Idle behavioral session closer stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	log.Printf("🕐 Idle behavioral session closer started (idle after %v, every %v)", idleTimeout, interval)
+}
+
+// CloseIdleSessions sets end_time on open sessions with no events for at least
+// idleTimeout. A session ends at its last event, or its start if it had none,
+// so its duration reflects actual activity. It returns how many were closed.
+func (s *BehavioralEventService) CloseIdleSessions(idleTimeout time.Duration) (int, error) {
+	cutoff := time.Now().Add(-idleTimeout)
+	closed := 0
+
+	for {
+		var sessions []models.BehavioralSession
+		err := s.db.Where("end_time IS NULL AND COALESCE(last_event_at, start_time) < ?", cutoff).
+			Order("start_time ASC").
+			Limit(idleSessionCloseBatch).
+			Find(&sessions).Error
+		if err != nil {
+			return closed, err
+		}
+
+		for _, session := range sessions {
+			endTime := session.StartTime
+			if session.LastEventAt != nil && session.LastEventAt.After(endTime) {
+				endTime = *session.LastEventAt
+			}
+			result := s.db.Model(&models.BehavioralSession{}).
+				Where("id = ? AND end_time IS NULL", session.ID).
+				Updates(map[string]interface{}{
+					"end_time":         endTime,
+					"duration_seconds": int(endTime.Sub(session.StartTime).Seconds()),
+				})
+			if result.Error != nil {
+				return closed, result.Error
+			}
+			closed += int(result.RowsAffected)
+		}
+
+		if len(sessions) < idleSessionCloseBatch {
+			break
+		}
+	}
+
+	if closed > 0 {
+		s.notifySessionChange()
+	}
+	return closed, nil
+}
@@ -0,0 +1,646 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/gorm"
+)
+
+// CampaignDispatcher sends scheduled re-engagement campaign executions.
+// It picks up due CampaignExecution rows, renders the template for each lead,
+// and enqueues the email on the EmailBatchService.
+type CampaignDispatcher struct {
+	db                *gorm.DB
+	emailBatch        campaignEmailQueue
+	encryptionManager *security.EncryptionManager
+	volumeController  *VolumeController
+	tracker           *CampaignTracker
+	rateShaper        *DomainRateShaper
+	dnc               *DNCService
+	safety            *LeadSafetyFilter
+
+	interval  time.Duration
+	batchSize int
+
+	maxAttempts  int           // Send attempts before an execution is dead-lettered
+	retryBackoff time.Duration // Delay before the first retry, doubling after each failure
+
+	mutex    sync.Mutex
+	wg       sync.WaitGroup
+	stopChan chan bool
+	running  bool
+}
+
+// campaignEmailQueue is the part of EmailBatchService the dispatcher depends on
+type campaignEmailQueue interface {
+	QueueEmail(email EmailJob) error
+}
+
+// DispatchResult summarizes a single dispatch cycle
+type DispatchResult struct {
+	Processed int `json:"processed"`
+	Sent      int `json:"sent"`
+	Skipped   int `json:"skipped"`
+	Failed    int `json:"failed"`
+	Retrying  int `json:"retrying"`
+	Deferred  int `json:"deferred"`
+}
+
+var (
+	ErrCampaignExecutionNotFound  = errors.New("campaign execution not found")
+	ErrCampaignExecutionNotFailed = errors.New("campaign execution has not failed")
+)
+
+// NewCampaignDispatcher creates a new campaign dispatcher
+func NewCampaignDispatcher(db *gorm.DB, emailBatch *EmailBatchService, encryptionManager *security.EncryptionManager) *CampaignDispatcher {
+	dispatcher := &CampaignDispatcher{
+		db:                db,
+		encryptionManager: encryptionManager,
+		volumeController:  NewVolumeController(db),
+		interval:          1 * time.Minute,
+		batchSize:         100,
+		maxAttempts:       3,
+		retryBackoff:      5 * time.Minute,
+		stopChan:          make(chan bool),
+	}
+	// Avoid storing a typed nil so the availability check in DispatchDue works
+	if emailBatch != nil {
+		dispatcher.emailBatch = emailBatch
+	}
+	return dispatcher
+}
+
+// SetTracker enables open/click tracking on dispatched emails
+func (d *CampaignDispatcher) SetTracker(tracker *CampaignTracker) {
+	d.tracker = tracker
+}
+
+// SetRateShaper caps sends per minute overall and per recipient domain;
+// executions over a cap are rescheduled for the next window
+func (d *CampaignDispatcher) SetRateShaper(shaper *DomainRateShaper) {
+	d.rateShaper = shaper
+}
+
+// SetDNCService refuses sends to recipients on the do-not-contact list,
+// whatever the campaign's own segment filters
+func (d *CampaignDispatcher) SetDNCService(dnc *DNCService) {
+	d.dnc = dnc
+}
+
+// SetSafetyFilter skips sends to leads that fail the contact safety check,
+// recording why
+func (d *CampaignDispatcher) SetSafetyFilter(safety *LeadSafetyFilter) {
+	d.safety = safety
+}
+
+// SetRetryPolicy sets how many times a failing send is attempted and the
+// backoff before the first retry, which doubles after each further failure
+func (d *CampaignDispatcher) SetRetryPolicy(maxAttempts int, backoff time.Duration) {
+	if maxAttempts > 0 {
+		d.maxAttempts = maxAttempts
+	}
+	if backoff > 0 {
+		d.retryBackoff = backoff
+	}
+}
+
+// SetWarmupSchedule limits daily sends to the lower of the warmup cap and the configured limit
+func (d *CampaignDispatcher) SetWarmupSchedule(warmup *WarmupSchedule) {
+	d.volumeController.SetWarmupSchedule(warmup)
+}
+
+// RateShaper returns the dispatcher's rate shaper, or nil if sends are not shaped
+func (d *CampaignDispatcher) RateShaper() *DomainRateShaper {
+	return d.rateShaper
+}
+
+// Start begins dispatching due campaign executions in the background
+func (d *CampaignDispatcher) Start() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.running {
+		log.Println("⚠️  Campaign dispatcher already running")
+		return
+	}
+
+	d.running = true
+	d.wg.Add(1)
+	go d.run()
+	log.Printf("📨 Campaign dispatcher started (interval: %v)", d.interval)
+}
+
+// Stop halts the background dispatch loop and waits for any in-progress cycle to finish
+func (d *CampaignDispatcher) Stop() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if !d.running {
+		return
+	}
+
+	log.Println("🛑 Stopping campaign dispatcher...")
+	d.running = false
+	close(d.stopChan)
+	d.wg.Wait()
+}
+
+func (d *CampaignDispatcher) run() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stopChan:
+			return
+		case <-ticker.C:
+			result, err := d.DispatchDue()
+			if err != nil {
+				log.Printf("⚠️  Campaign dispatch failed: %v", err)
+				continue
+			}
+			if result.Processed > 0 {
+				log.Printf("📨 Campaign dispatch: %d sent, %d skipped, %d failed, %d retrying, %d deferred",
+					result.Sent, result.Skipped, result.Failed, result.Retrying, result.Deferred)
+			}
+		}
+	}
+}
+
+// DispatchDue sends all scheduled executions whose ScheduledFor has passed,
+// respecting the global volume limit and each campaign's daily limit
+func (d *CampaignDispatcher) DispatchDue() (DispatchResult, error) {
+	var result DispatchResult
+
+	if d.emailBatch == nil {
+		return result, fmt.Errorf("email batch service not available")
+	}
+
+	if IsCampaignEmergencyStopActive() {
+		return result, nil
+	}
+
+	volume, err := d.volumeController.CheckVolumeCompliance()
+	if err != nil {
+		return result, fmt.Errorf("failed to check volume compliance: %w", err)
+	}
+	globalRemaining := volume.DailyLimit - volume.DailyVolume
+	if globalRemaining <= 0 {
+		return result, nil
+	}
+
+	var executions []models.CampaignExecution
+	if err := d.db.Preload("LeadReengagement").Preload("CampaignTemplate").
+		Where("status = ? AND scheduled_for <= ?", "scheduled", time.Now()).
+		Order("scheduled_for ASC").
+		Limit(d.batchSize).
+		Find(&executions).Error; err != nil {
+		return result, fmt.Errorf("failed to load scheduled executions: %w", err)
+	}
+
+	campaignRemaining := make(map[string]int)
+
+	for i := range executions {
+		execution := &executions[i]
+		result.Processed++
+
+		// Re-check before every send so an emergency stop halts mid-batch
+		if globalRemaining <= 0 || IsCampaignEmergencyStopActive() {
+			result.Deferred++
+			continue
+		}
+
+		if execution.DailyLimit > 0 {
+			remaining, ok := campaignRemaining[execution.CampaignName]
+			if !ok {
+				remaining = execution.DailyLimit - d.sentTodayForCampaign(execution.CampaignName)
+			}
+			if remaining <= 0 {
+				campaignRemaining[execution.CampaignName] = 0
+				result.Deferred++
+				continue
+			}
+			campaignRemaining[execution.CampaignName] = remaining - 1
+		}
+
+		switch d.dispatchExecution(execution) {
+		case "sent":
+			result.Sent++
+			globalRemaining--
+		case "skipped":
+			result.Skipped++
+		case "retrying":
+			result.Retrying++
+		case "deferred":
+			result.Deferred++
+			if execution.DailyLimit > 0 {
+				campaignRemaining[execution.CampaignName]++
+			}
+		default:
+			result.Failed++
+		}
+	}
+
+	return result, nil
+}
+
+// dispatchExecution sends a single execution and records the outcome
+func (d *CampaignDispatcher) dispatchExecution(execution *models.CampaignExecution) string {
+	lead := &execution.LeadReengagement
+
+	if reason := campaignSuppressionReason(lead); reason != "" {
+		if lead.OnDNCList && d.dnc != nil {
+			email, _ := d.decrypt(lead.Email)
+			d.dnc.LogSuppression("email", "campaign_dispatcher", email, &lead.ID, reason)
+		}
+		d.markExecution(execution, "skipped", reason)
+		return "skipped"
+	}
+
+	// Leads can be flagged after they were scheduled
+	if d.safety != nil {
+		blocks, err := d.safety.ContactBlocks([]string{lead.FUBContactID})
+		if err != nil {
+			log.Printf("⚠️  Deferring campaign execution %d: %v", execution.ID, err)
+			return "deferred"
+		}
+		if reason, blocked := blocks[lead.FUBContactID]; blocked {
+			d.safety.RecordExclusion(lead.FUBContactID, "campaign", execution.CampaignName, reason)
+			d.markExecution(execution, "skipped", reason)
+			return "skipped"
+		}
+	}
+
+	email, err := d.decrypt(lead.Email)
+	if err != nil || email == "" {
+		return d.failExecution(execution, models.CampaignFailureRecipientUnavailable, "recipient email unavailable", false)
+	}
+
+	if d.isEmailUnsubscribed(email) {
+		d.markExecution(execution, "skipped", "recipient unsubscribed after scheduling")
+		return "skipped"
+	}
+
+	// The list may have been imported after the lead was last flagged
+	if d.dnc != nil {
+		if err := d.dnc.CheckEmail(email, "campaign_dispatcher", &lead.ID); errors.Is(err, ErrRecipientOnDNC) {
+			d.db.Model(&models.LeadReengagement{}).Where("id = ?", lead.ID).Update("on_dnc_list", true)
+			d.markExecution(execution, "skipped", "recipient is on the do-not-contact list")
+			return "skipped"
+		} else if err != nil {
+			log.Printf("⚠️  Deferring campaign execution %d: %v", execution.ID, err)
+			return "deferred"
+		}
+	}
+
+	if d.rateShaper != nil && !d.rateShaper.Allow(email) {
+		d.db.Model(&models.CampaignExecution{}).Where("id = ?", execution.ID).
+			Update("scheduled_for", d.rateShaper.NextWindow())
+		return "deferred"
+	}
+
+	if execution.CampaignTemplate.ID == 0 {
+		return d.failExecution(execution, models.CampaignFailureRenderFailure, "campaign template no longer exists", false)
+	}
+
+	variables := d.templateVariables(lead, email, &execution.CampaignTemplate)
+	var headers map[string]string
+	if d.tracker != nil {
+		variables["unsubscribe_url"] = d.tracker.UnsubscribeURL(lead.ID, execution.ID)
+		headers = d.tracker.ListUnsubscribeHeaders(lead.ID, execution.ID)
+	}
+	subject := RenderCampaignTemplate(execution.CampaignTemplate.Subject, variables)
+	body := RenderCampaignTemplate(execution.CampaignTemplate.Body, variables)
+	htmlBody := RenderCampaignHTML(execution.CampaignTemplate.Body, variables)
+	if strings.TrimSpace(subject) == "" || strings.TrimSpace(body) == "" {
+		return d.failExecution(execution, models.CampaignFailureRenderFailure, "template rendered an empty subject or body", false)
+	}
+	if unsubscribeURL, ok := variables["unsubscribe_url"]; ok {
+		if !strings.Contains(body, unsubscribeURL) {
+			body += unsubscribeFooter(unsubscribeURL)
+		}
+		if !strings.Contains(htmlBody, html.EscapeString(unsubscribeURL)) {
+			htmlBody += unsubscribeFooter(unsubscribeURL)
+		}
+	}
+	if d.tracker != nil {
+		htmlBody = d.tracker.InstrumentHTML(htmlBody, execution.ID)
+	}
+
+	job := EmailJob{
+		To:       []string{email},
+		Subject:  subject,
+		Body:     body,
+		HTMLBody: htmlBody,
+		Headers:  headers,
+		Priority: 3,
+		Metadata: map[string]interface{}{
+			"campaign_execution_id": execution.ID,
+			"campaign_name":         execution.CampaignName,
+		},
+	}
+
+	if err := d.emailBatch.QueueEmail(job); errors.Is(err, ErrRecipientOnDNC) {
+		d.markExecution(execution, "skipped", "recipient is on the do-not-contact list")
+		return "skipped"
+	} else if err != nil {
+		return d.failExecution(execution, models.CampaignFailureQueueError, err.Error(), true)
+	}
+
+	d.markExecution(execution, "sent", "")
+
+	now := time.Now()
+	d.db.Model(&models.LeadReengagement{}).Where("id = ?", lead.ID).Updates(map[string]interface{}{
+		"emails_sent":     gorm.Expr("emails_sent + 1"),
+		"last_email_sent": now,
+	})
+	d.db.Model(&models.CampaignTemplate{}).Where("id = ?", execution.CampaignTemplateID).
+		Update("times_sent", gorm.Expr("times_sent + 1"))
+
+	return "sent"
+}
+
+// campaignSuppressionReason returns why a lead must not be contacted, or "" if it may be
+func campaignSuppressionReason(lead *models.LeadReengagement) string {
+	switch {
+	case lead.ID == 0:
+		return "lead no longer exists"
+	case lead.PreviousUnsubscribe:
+		return "recipient unsubscribed after scheduling"
+	case lead.OnDNCList:
+		return "recipient is on the do-not-contact list"
+	case lead.HardBounce:
+		return "recipient email hard bounced"
+	case lead.ConsentStatus == models.ConsentRevoked:
+		return "recipient revoked consent"
+	case lead.CampaignStatus == models.CampaignSuppressed:
+		return "lead suppressed from campaign"
+	}
+	return ""
+}
+
+// isEmailUnsubscribed checks the CAN-SPAM unsubscribe list for the recipient
+func (d *CampaignDispatcher) isEmailUnsubscribed(email string) bool {
+	if !d.db.Migrator().HasTable("unsubscribe_records") {
+		return false
+	}
+
+	var count int64
+	d.db.Table("unsubscribe_records").
+		Where("LOWER(email) = ? AND unsubscribe_type IN ? AND is_active = ?", strings.ToLower(email), []string{"all", "marketing"}, true).
+		Count(&count)
+	return count > 0
+}
+
+func (d *CampaignDispatcher) sentTodayForCampaign(campaignName string) int {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var count int64
+	d.db.Model(&models.CampaignExecution{}).
+		Where("campaign_name = ? AND status = ? AND executed_at >= ?", campaignName, "sent", startOfDay).
+		Count(&count)
+	return int(count)
+}
+
+func (d *CampaignDispatcher) markExecution(execution *models.CampaignExecution, status, errorMessage string) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":        status,
+		"error_message": errorMessage,
+		"retry_count":   execution.RetryCount,
+	}
+	if status == "sent" {
+		updates["executed_at"] = now
+	}
+
+	if err := d.db.Model(&models.CampaignExecution{}).Where("id = ?", execution.ID).Updates(updates).Error; err != nil {
+		log.Printf("⚠️  Failed to update campaign execution %d: %v", execution.ID, err)
+	}
+}
+
+// failExecution records a failed send attempt. Retryable failures are
+// rescheduled with backoff until the attempts run out; anything else lands in
+// the failed (dead-letter) state with its reason.
+func (d *CampaignDispatcher) failExecution(execution *models.CampaignExecution, reason, message string, retryable bool) string {
+	execution.RetryCount++
+	now := time.Now()
+
+	if retryable && execution.RetryCount < d.maxAttempts {
+		nextRetry := now.Add(d.retryBackoff << (execution.RetryCount - 1))
+		if err := d.db.Model(&models.CampaignExecution{}).Where("id = ?", execution.ID).Updates(map[string]interface{}{
+			"scheduled_for":  nextRetry,
+			"next_retry":     nextRetry,
+			"error_message":  message,
+			"failure_reason": reason,
+			"retry_count":    execution.RetryCount,
+		}).Error; err != nil {
+			log.Printf("⚠️  Failed to reschedule campaign execution %d: %v", execution.ID, err)
+		}
+		return "retrying"
+	}
+
+	if err := d.db.Model(&models.CampaignExecution{}).Where("id = ?", execution.ID).Updates(map[string]interface{}{
+		"status":         "failed",
+		"error_message":  message,
+		"failure_reason": reason,
+		"retry_count":    execution.RetryCount,
+		"next_retry":     nil,
+		"failed_at":      now,
+	}).Error; err != nil {
+		log.Printf("⚠️  Failed to update campaign execution %d: %v", execution.ID, err)
+	}
+	log.Printf("❌ Campaign execution %d failed after %d attempt(s): %s: %s", execution.ID, execution.RetryCount, reason, message)
+	return "failed"
+}
+
+// HandleEmailFailure dead-letters the campaign execution of an email the
+// batch service gave up on after its own SMTP retries
+func (d *CampaignDispatcher) HandleEmailFailure(email EmailJob, sendErr error) {
+	executionID, ok := campaignExecutionID(email.Metadata)
+	if !ok {
+		return
+	}
+	message := "email delivery failed"
+	if sendErr != nil {
+		message = sendErr.Error()
+	}
+
+	if err := d.db.Model(&models.CampaignExecution{}).Where("id = ? AND status = ?", executionID, "sent").Updates(map[string]interface{}{
+		"status":         "failed",
+		"error_message":  message,
+		"failure_reason": models.CampaignFailureSMTPError,
+		"retry_count":    gorm.Expr("retry_count + 1"),
+		"failed_at":      time.Now(),
+	}).Error; err != nil {
+		log.Printf("⚠️  Failed to dead-letter campaign execution %d: %v", executionID, err)
+	}
+}
+
+// campaignExecutionID reads the execution ID the dispatcher stores in email
+// metadata, which is a float64 once the job has round-tripped through Redis
+func campaignExecutionID(metadata map[string]interface{}) (uint, bool) {
+	switch id := metadata["campaign_execution_id"].(type) {
+	case uint:
+		return id, true
+	case int:
+		return uint(id), id > 0
+	case float64:
+		return uint(id), id > 0
+	}
+	return 0, false
+}
+
+// RequeueFailedExecution puts a dead-lettered execution back on the schedule
+// for an immediate send with a fresh attempt count
+func RequeueFailedExecution(db *gorm.DB, id uint) (*models.CampaignExecution, error) {
+	var execution models.CampaignExecution
+	if err := db.First(&execution, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCampaignExecutionNotFound
+		}
+		return nil, err
+	}
+	if execution.Status != "failed" {
+		return nil, ErrCampaignExecutionNotFailed
+	}
+
+	result := db.Model(&models.CampaignExecution{}).Where("id = ? AND status = ?", id, "failed").Updates(map[string]interface{}{
+		"status":         "scheduled",
+		"scheduled_for":  time.Now(),
+		"retry_count":    0,
+		"next_retry":     nil,
+		"failed_at":      nil,
+		"error_message":  "",
+		"failure_reason": "",
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrCampaignExecutionNotFailed
+	}
+
+	if err := db.First(&execution, id).Error; err != nil {
+		return nil, err
+	}
+	return &execution, nil
+}
+
+func (d *CampaignDispatcher) decrypt(value security.EncryptedString) (string, error) {
+	if d.encryptionManager == nil {
+		return string(value), nil
+	}
+	return d.encryptionManager.Decrypt(value)
+}
+
+// templateVariables returns the lead's placeholder values plus the template's
+// custom variables; lead values win if a stored custom key shadows one
+func (d *CampaignDispatcher) templateVariables(lead *models.LeadReengagement, email string, template *models.CampaignTemplate) map[string]string {
+	firstName, _ := d.decrypt(lead.FirstName)
+	lastName, _ := d.decrypt(lead.LastName)
+
+	variables := template.CustomValues()
+	for key, value := range CampaignTemplateVariables(firstName, lastName, email) {
+		variables[key] = value
+	}
+	return variables
+}
+
+// unsubscribeFooter is appended to campaign emails whose template does not place {{unsubscribe_url}} itself
+func unsubscribeFooter(unsubscribeURL string) string {
+	return fmt.Sprintf(`<p style="font-size:12px;color:#666">You are receiving this because you previously contacted us. <a href="%s">Unsubscribe</a></p>`, html.EscapeString(unsubscribeURL))
+}
+
+// CampaignTemplateVariables builds the placeholder values rendered for a lead's decrypted fields
+func CampaignTemplateVariables(firstName, lastName, email string) map[string]string {
+	return map[string]string{
+		"first_name": firstName,
+		"last_name":  lastName,
+		"name":       strings.TrimSpace(firstName + " " + lastName),
+		"email":      email,
+	}
+}
+
+// CampaignLeadVariables are the placeholders the dispatcher fills for each lead
+var CampaignLeadVariables = []string{"first_name", "last_name", "name", "email", "unsubscribe_url"}
+
+// campaignVariablePattern matches {{variable}} placeholders, tolerating inner whitespace
+var campaignVariablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.]+)\s*\}\}`)
+
+// campaignVariableName matches names a {{variable}} placeholder can use
+var campaignVariableName = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+// IsCampaignVariableName reports whether name can be used as a {{variable}} placeholder
+func IsCampaignVariableName(name string) bool {
+	return campaignVariableName.MatchString(name)
+}
+
+// RenderCampaignTemplate replaces {{variable}} placeholders in a campaign template.
+// Placeholders without a value are left as-is.
+func RenderCampaignTemplate(template string, variables map[string]string) string {
+	return campaignVariablePattern.ReplaceAllStringFunc(template, func(match string) string {
+		name := campaignVariablePattern.FindStringSubmatch(match)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// RenderCampaignHTML renders a campaign template as HTML, escaping the
+// variable values so lead data can't inject markup
+func RenderCampaignHTML(template string, variables map[string]string) string {
+	escaped := make(map[string]string, len(variables))
+	for name, value := range variables {
+		escaped[name] = html.EscapeString(value)
+	}
+	return RenderCampaignTemplate(template, escaped)
+}
+
+// ExtractTemplateVariables returns the sorted, de-duplicated placeholder names used across the given texts
+func ExtractTemplateVariables(texts ...string) []string {
+	seen := make(map[string]bool)
+	variables := []string{}
+	for _, text := range texts {
+		for _, match := range campaignVariablePattern.FindAllStringSubmatch(text, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				variables = append(variables, match[1])
+			}
+		}
+	}
+	sort.Strings(variables)
+	return variables
+}
+
+// UnknownTemplateVariables returns the variables that are neither lead fields nor declared custom keys
+func UnknownTemplateVariables(variables []string, customKeys []string) []string {
+	allowed := make(map[string]bool)
+	for _, name := range CampaignLeadVariables {
+		allowed[name] = true
+	}
+	for _, name := range customKeys {
+		allowed[name] = true
+	}
+
+	unknown := []string{}
+	for _, name := range variables {
+		if !allowed[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
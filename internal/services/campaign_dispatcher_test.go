@@ -0,0 +1,298 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// stopAfterQueue records queued emails and activates the emergency stop after the first send,
+// simulating an operator hitting stop while the dispatcher is mid-batch
+type stopAfterQueue struct {
+	controls *EmergencyControls
+	queued   []EmailJob
+}
+
+func (q *stopAfterQueue) QueueEmail(email EmailJob) error {
+	q.queued = append(q.queued, email)
+	if len(q.queued) == 1 {
+		q.controls.ActivateEmergencyStop("test stop", "test")
+	}
+	return nil
+}
+
+func setupDispatcherTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	err = db.AutoMigrate(&models.LeadReengagement{}, &models.CampaignTemplate{}, &models.CampaignExecution{})
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+func TestCampaignDispatcher_HaltsOnEmergencyStop(t *testing.T) {
+	db := setupDispatcherTestDB(t)
+	controls := NewEmergencyControls(nil)
+	t.Cleanup(controls.DeactivateEmergencyStop)
+
+	template := models.CampaignTemplate{Name: "reengage", EmailNumber: 1, Subject: "Hello {{first_name}}", Body: "<p>Hi</p>"}
+	db.Create(&template)
+
+	for i := 0; i < 3; i++ {
+		lead := models.LeadReengagement{
+			FUBContactID: fmt.Sprintf("fub-%d", i),
+			Email:        security.EncryptedString(fmt.Sprintf("lead%d@example.com", i)),
+			FirstName:    security.EncryptedString("Lead"),
+		}
+		db.Create(&lead)
+		db.Create(&models.CampaignExecution{
+			LeadReengagementID: lead.ID,
+			CampaignTemplateID: template.ID,
+			CampaignName:       "reengage",
+			ScheduledFor:       time.Now().Add(-time.Minute),
+			Status:             "scheduled",
+		})
+	}
+
+	queue := &stopAfterQueue{controls: controls}
+	dispatcher := NewCampaignDispatcher(db, nil, nil)
+	dispatcher.emailBatch = queue
+
+	result, err := dispatcher.DispatchDue()
+	if err != nil {
+		t.Fatalf("DispatchDue failed: %v", err)
+	}
+
+	if len(queue.queued) != 1 {
+		t.Errorf("Expected dispatcher to stop after 1 send, queued %d", len(queue.queued))
+	}
+	if result.Sent != 1 || result.Deferred != 2 {
+		t.Errorf("Expected 1 sent and 2 deferred, got %d sent and %d deferred", result.Sent, result.Deferred)
+	}
+
+	// A cycle started while the stop is active must not send anything
+	result, err = dispatcher.DispatchDue()
+	if err != nil {
+		t.Fatalf("DispatchDue failed: %v", err)
+	}
+	if result.Processed != 0 || len(queue.queued) != 1 {
+		t.Errorf("Expected no sends while emergency stop is active, processed %d", result.Processed)
+	}
+
+	controls.DeactivateEmergencyStop()
+	if IsCampaignEmergencyStopActive() {
+		t.Error("Expected deactivation to clear the emergency stop flag")
+	}
+}
+
+// failingQueue rejects every email, like an unreachable queue backend
+type failingQueue struct{}
+
+func (failingQueue) QueueEmail(email EmailJob) error {
+	return fmt.Errorf("redis unavailable")
+}
+
+func TestCampaignDispatcher_RetriesThenDeadLettersFailedSends(t *testing.T) {
+	db := setupDispatcherTestDB(t)
+	template := models.CampaignTemplate{Name: "reengage", EmailNumber: 1, Subject: "Hello", Body: "<p>Hi</p>"}
+	db.Create(&template)
+	lead := models.LeadReengagement{FUBContactID: "fub-1", Email: security.EncryptedString("lead@example.com")}
+	db.Create(&lead)
+	execution := models.CampaignExecution{LeadReengagementID: lead.ID, CampaignTemplateID: template.ID,
+		ScheduledFor: time.Now().Add(-time.Minute), Status: "scheduled"}
+	db.Create(&execution)
+
+	dispatcher := NewCampaignDispatcher(db, nil, nil)
+	dispatcher.emailBatch = failingQueue{}
+	dispatcher.SetRetryPolicy(2, time.Minute)
+
+	result, _ := dispatcher.DispatchDue()
+	db.First(&execution, execution.ID)
+	if result.Retrying != 1 || execution.Status != "scheduled" || execution.RetryCount != 1 || !execution.ScheduledFor.After(time.Now()) {
+		t.Fatalf("Expected the first failure to be retried later, got %+v (status %s, attempts %d)", result, execution.Status, execution.RetryCount)
+	}
+
+	db.Model(&execution).Update("scheduled_for", time.Now().Add(-time.Second))
+	result, _ = dispatcher.DispatchDue()
+	db.First(&execution, execution.ID)
+	if result.Failed != 1 || execution.Status != "failed" || execution.FailureReason != models.CampaignFailureQueueError ||
+		execution.RetryCount != 2 || execution.FailedAt == nil {
+		t.Fatalf("Expected the last attempt to dead-letter the execution, got status %s reason %q attempts %d",
+			execution.Status, execution.FailureReason, execution.RetryCount)
+	}
+
+	requeued, err := RequeueFailedExecution(db, execution.ID)
+	if err != nil || requeued.Status != "scheduled" || requeued.RetryCount != 0 || requeued.FailureReason != "" {
+		t.Fatalf("Expected requeue to reset the execution, got %+v err=%v", requeued, err)
+	}
+	if _, err := RequeueFailedExecution(db, execution.ID); err != ErrCampaignExecutionNotFailed {
+		t.Errorf("Expected requeueing a scheduled execution to fail, got %v", err)
+	}
+
+	// SMTP failures reported after queueing dead-letter the sent execution
+	db.Model(&execution).Update("status", "sent")
+	dispatcher.HandleEmailFailure(EmailJob{Metadata: map[string]interface{}{"campaign_execution_id": float64(execution.ID)}}, fmt.Errorf("554 rejected"))
+	db.First(&execution, execution.ID)
+	if execution.Status != "failed" || execution.FailureReason != models.CampaignFailureSMTPError || execution.ErrorMessage != "554 rejected" {
+		t.Errorf("Expected an SMTP failure to dead-letter the execution, got status %s reason %q", execution.Status, execution.FailureReason)
+	}
+}
+
+func TestTemplateVariables_ExtractAndValidate(t *testing.T) {
+	variables := ExtractTemplateVariables("Hi {{ first_name }}", "<p>{{first_name}}, see {{listing_url}} or {{ agent_phone }}</p>")
+
+	expected := []string{"agent_phone", "first_name", "listing_url"}
+	if len(variables) != len(expected) {
+		t.Fatalf("Expected variables %v, got %v", expected, variables)
+	}
+	for i, name := range expected {
+		if variables[i] != name {
+			t.Errorf("Expected variables %v, got %v", expected, variables)
+			break
+		}
+	}
+
+	unknown := UnknownTemplateVariables(variables, []string{"agent_phone"})
+	if len(unknown) != 1 || unknown[0] != "listing_url" {
+		t.Errorf("Expected only listing_url to be unknown, got %v", unknown)
+	}
+
+	rendered := RenderCampaignTemplate("Hi {{ first_name }} {{unknown}}", map[string]string{"first_name": "Jane"})
+	if rendered != "Hi Jane {{unknown}}" {
+		t.Errorf("Unexpected rendered template: %q", rendered)
+	}
+}
+
+func TestCampaignDispatcher_RendersCustomVariables(t *testing.T) {
+	db := setupDispatcherTestDB(t)
+	template := models.CampaignTemplate{Name: "reengage", EmailNumber: 1, Subject: "Hi {{first_name}}",
+		Body:            "<p>Call {{agent_phone}}</p>",
+		CustomVariables: models.JSONB{"agent_phone": "555-0100", "first_name": "Shadowed"}}
+	db.Create(&template)
+	lead := models.LeadReengagement{FUBContactID: "fub-1", Email: security.EncryptedString("lead@example.com"), FirstName: "Jane"}
+	db.Create(&lead)
+	db.Create(&models.CampaignExecution{LeadReengagementID: lead.ID, CampaignTemplateID: template.ID,
+		ScheduledFor: time.Now().Add(-time.Minute), Status: "scheduled"})
+
+	queue := &recordingQueue{}
+	dispatcher := NewCampaignDispatcher(db, nil, nil)
+	dispatcher.emailBatch = queue
+	if _, err := dispatcher.DispatchDue(); err != nil {
+		t.Fatalf("DispatchDue failed: %v", err)
+	}
+
+	if len(queue.queued) != 1 {
+		t.Fatalf("Expected 1 email queued, got %d", len(queue.queued))
+	}
+	if email := queue.queued[0]; email.Subject != "Hi Jane" || email.HTMLBody != "<p>Call 555-0100</p>" {
+		t.Errorf("Expected custom values rendered and lead values to win, got %q / %q", email.Subject, email.HTMLBody)
+	}
+}
+
+func TestCampaignDispatcher_QueuesMarksSentAndHonorsDailyLimit(t *testing.T) {
+	db := setupDispatcherTestDB(t)
+	template := models.CampaignTemplate{Name: "reengage", EmailNumber: 1, Subject: "Hi {{first_name}}", Body: "<p>Hi {{first_name}}</p>"}
+	db.Create(&template)
+
+	// One send already went out today, leaving room for one more
+	now := time.Now()
+	db.Create(&models.CampaignExecution{LeadReengagementID: 999, CampaignTemplateID: template.ID, CampaignName: "spring",
+		DailyLimit: 2, ScheduledFor: now.Add(-time.Hour), ExecutedAt: &now, Status: "sent"})
+
+	var executions []models.CampaignExecution
+	for i, firstName := range []string{"<b>Jane</b>", "Sam"} {
+		lead := models.LeadReengagement{
+			FUBContactID: fmt.Sprintf("fub-%d", i),
+			Email:        security.EncryptedString(fmt.Sprintf("lead%d@example.com", i)),
+			FirstName:    security.EncryptedString(firstName),
+		}
+		db.Create(&lead)
+		execution := models.CampaignExecution{LeadReengagementID: lead.ID, CampaignTemplateID: template.ID, CampaignName: "spring",
+			DailyLimit: 2, ScheduledFor: now.Add(time.Duration(i-2) * time.Minute), Status: "scheduled"}
+		db.Create(&execution)
+		executions = append(executions, execution)
+	}
+
+	queue := &recordingQueue{}
+	dispatcher := NewCampaignDispatcher(db, nil, nil)
+	dispatcher.emailBatch = queue
+	result, err := dispatcher.DispatchDue()
+	if err != nil {
+		t.Fatalf("DispatchDue failed: %v", err)
+	}
+	if result.Sent != 1 || result.Deferred != 1 || len(queue.queued) != 1 {
+		t.Fatalf("Expected one send and one deferral under the daily limit, got %+v with %d queued", result, len(queue.queued))
+	}
+
+	email := queue.queued[0]
+	if len(email.To) != 1 || email.To[0] != "lead0@example.com" {
+		t.Errorf("Expected the earliest execution sent first, got %v", email.To)
+	}
+	if email.Subject != "Hi <b>Jane</b>" || email.HTMLBody != "<p>Hi &lt;b&gt;Jane&lt;/b&gt;</p>" {
+		t.Errorf("Expected variables escaped in the HTML body only, got %q / %q", email.Subject, email.HTMLBody)
+	}
+
+	var sent, deferred models.CampaignExecution
+	db.First(&sent, executions[0].ID)
+	db.First(&deferred, executions[1].ID)
+	if sent.Status != "sent" || sent.ExecutedAt == nil {
+		t.Errorf("Expected the queued execution marked sent, got status %s", sent.Status)
+	}
+	if deferred.Status != "scheduled" || deferred.ExecutedAt != nil {
+		t.Errorf("Expected the execution over the limit left scheduled, got status %s", deferred.Status)
+	}
+
+	var lead models.LeadReengagement
+	db.First(&lead, sent.LeadReengagementID)
+	db.First(&template, template.ID)
+	if lead.EmailsSent != 1 || lead.LastEmailSent == nil || template.TimesSent != 1 {
+		t.Errorf("Expected the send counted on the lead and template, got %d / %d", lead.EmailsSent, template.TimesSent)
+	}
+
+	// The limit still holds on the next run the same day
+	if result, _ := dispatcher.DispatchDue(); result.Sent != 0 || len(queue.queued) != 1 {
+		t.Errorf("Expected no more sends today, got %+v", result)
+	}
+}
+
+func TestCampaignDispatcher_EscapesVariablesInTrackedHTML(t *testing.T) {
+	db := setupDispatcherTestDB(t)
+	template := models.CampaignTemplate{Name: "reengage", EmailNumber: 1, Subject: "Hi {{first_name}}",
+		Body: `<p>Hi {{first_name}}, <a href="https://example.com/homes?a=1&amp;b=2">see homes</a></p>`}
+	db.Create(&template)
+	lead := models.LeadReengagement{FUBContactID: "fub-1", Email: security.EncryptedString("lead@example.com"),
+		FirstName: security.EncryptedString(`<script>alert(1)</script>`)}
+	db.Create(&lead)
+	db.Create(&models.CampaignExecution{LeadReengagementID: lead.ID, CampaignTemplateID: template.ID,
+		ScheduledFor: time.Now().Add(-time.Minute), Status: "scheduled"})
+
+	queue := &recordingQueue{}
+	dispatcher := NewCampaignDispatcher(db, nil, nil)
+	dispatcher.emailBatch = queue
+	dispatcher.SetTracker(NewCampaignTracker("https://track.example.com", "secret"))
+	if _, err := dispatcher.DispatchDue(); err != nil {
+		t.Fatalf("DispatchDue failed: %v", err)
+	}
+	if len(queue.queued) != 1 {
+		t.Fatalf("Expected 1 email queued, got %d", len(queue.queued))
+	}
+
+	htmlBody := queue.queued[0].HTMLBody
+	if strings.Contains(htmlBody, "<script>") || !strings.Contains(htmlBody, "Hi &lt;script&gt;alert(1)&lt;/script&gt;") {
+		t.Errorf("Expected the first name escaped in the tracked HTML body, got %s", htmlBody)
+	}
+	if !strings.Contains(htmlBody, "https://track.example.com/t/click/") || !strings.Contains(htmlBody, "/t/open/") {
+		t.Errorf("Expected click and open tracking in the HTML body, got %s", htmlBody)
+	}
+}
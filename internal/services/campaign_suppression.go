@@ -0,0 +1,142 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/gorm"
+)
+
+// openApprovalStatuses are the approval statuses still awaiting a decision
+var openApprovalStatuses = []string{"pending", "under_review"}
+
+// CampaignSuppressionOptions selects the automatic exclusions applied on top
+// of a campaign's saved suppressions
+type CampaignSuppressionOptions struct {
+	ExcludeOpenApprovals   bool // Leads with a pending or under-review approval
+	ExcludeActivePipelines bool // Leads whose email is the tenant of a closing not yet completed
+}
+
+// CampaignSuppressionService excludes campaign-specific suppression lists and
+// leads already in the approval or closing pipeline from campaign audiences
+type CampaignSuppressionService struct {
+	db                *gorm.DB
+	encryptionManager *security.EncryptionManager
+}
+
+// NewCampaignSuppressionService creates a campaign suppression service
+func NewCampaignSuppressionService(db *gorm.DB, encryptionManager *security.EncryptionManager) *CampaignSuppressionService {
+	return &CampaignSuppressionService{db: db, encryptionManager: encryptionManager}
+}
+
+// Apply narrows the eligible lead query to the leads not suppressed from the
+// named campaign and returns how many eligible leads were suppressed. The
+// returned query is safe to reuse.
+func (s *CampaignSuppressionService) Apply(campaignName string, eligible *gorm.DB, opts CampaignSuppressionOptions) (*gorm.DB, int64, error) {
+	base := eligible.Session(&gorm.Session{})
+	filtered := base
+	narrowed := false
+
+	if campaignName != "" {
+		var suppressions []models.CampaignSuppression
+		if err := s.db.Where("campaign_name = ? AND active = ?", campaignName, true).Find(&suppressions).Error; err != nil {
+			return nil, 0, fmt.Errorf("failed to load campaign suppressions: %w", err)
+		}
+		for _, suppression := range suppressions {
+			if len(suppression.LeadIDs) > 0 {
+				filtered = filtered.Where("id NOT IN ?", []uint(suppression.LeadIDs))
+				narrowed = true
+			}
+			if !suppression.Query.IsEmpty() {
+				condition, args := suppressionQueryCondition(suppression.Query)
+				filtered = filtered.Where("NOT ("+condition+")", args...)
+				narrowed = true
+			}
+		}
+	}
+
+	if opts.ExcludeOpenApprovals {
+		openApprovals := s.db.Model(&models.Approval{}).
+			Select("fub_lead_id").
+			Where("status IN ? AND fub_lead_id IS NOT NULL AND fub_lead_id <> ''", openApprovalStatuses)
+		filtered = filtered.Where("fub_contact_id NOT IN (?)", openApprovals)
+		narrowed = true
+	}
+
+	if opts.ExcludeActivePipelines {
+		hashes, err := s.activePipelineEmailHashes()
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(hashes) > 0 {
+			filtered = filtered.Where("(email_hash IS NULL OR email_hash NOT IN ?)", hashes)
+			narrowed = true
+		}
+	}
+
+	if !narrowed {
+		return base, 0, nil
+	}
+
+	filtered = filtered.Session(&gorm.Session{})
+	var total, remaining int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count eligible leads: %w", err)
+	}
+	if err := filtered.Count(&remaining).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count unsuppressed leads: %w", err)
+	}
+	return filtered, total - remaining, nil
+}
+
+// activePipelineEmailHashes returns the email blind indexes of tenants on
+// closings that are not yet completed
+func (s *CampaignSuppressionService) activePipelineEmailHashes() ([]string, error) {
+	if s.encryptionManager == nil {
+		return nil, nil
+	}
+
+	var pipelines []models.ClosingPipeline
+	if err := s.db.Select("id", "tenant_email").
+		Where("status <> ?", "completed").
+		Find(&pipelines).Error; err != nil {
+		return nil, fmt.Errorf("failed to load active closing pipelines: %w", err)
+	}
+
+	seen := map[string]bool{}
+	var hashes []string
+	for _, pipeline := range pipelines {
+		email := string(pipeline.TenantEmail)
+		if decrypted, err := s.encryptionManager.Decrypt(pipeline.TenantEmail); err == nil {
+			email = decrypted
+		}
+		hash := s.encryptionManager.EmailBlindIndex(email)
+		if hash == "" || seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
+
+// suppressionQueryCondition builds the SQL condition a lead must meet to match
+// every non-empty filter of a saved suppression query
+func suppressionQueryCondition(q models.CampaignSuppressionQuery) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+	add := func(column string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		parts = append(parts, "COALESCE("+column+", '') IN ?")
+		args = append(args, values)
+	}
+	add("segment", q.Segments)
+	add("risk_level", q.RiskLevels)
+	add("campaign_status", q.CampaignStatuses)
+	add("original_source", q.Sources)
+	return strings.Join(parts, " AND "), args
+}
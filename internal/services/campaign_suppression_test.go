@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestCampaignSuppressionService_Apply(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&security.EncryptionKey{}, &models.LeadReengagement{}, &models.CampaignSuppression{},
+		&models.Approval{}, &models.ClosingPipeline{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	t.Setenv("ENCRYPTION_KEY", newTestEncryptionKey(t))
+	em, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create encryption manager: %v", err)
+	}
+
+	newLead := func(fubID, email string, segment models.LeadSegment) models.LeadReengagement {
+		lead := models.LeadReengagement{FUBContactID: fubID, Segment: segment, RiskLevel: models.RiskLow, ConsentStatus: models.ConsentExpress}
+		lead.SetBlindIndexes(em, "", "", email, "")
+		if err := db.Create(&lead).Error; err != nil {
+			t.Fatalf("Failed to store lead: %v", err)
+		}
+		return lead
+	}
+	listed := newLead("fub-1", "one@example.com", models.SegmentDormant)
+	newLead("fub-2", "two@example.com", models.SegmentActive)    // matches the saved query
+	newLead("fub-3", "three@example.com", models.SegmentDormant) // open approval
+	newLead("fub-4", "Four@Example.com", models.SegmentDormant)  // active closing
+	approved := newLead("fub-5", "five@example.com", models.SegmentDormant)
+	closed := newLead("fub-6", "six@example.com", models.SegmentDormant)
+
+	db.Create(&models.CampaignSuppression{CampaignName: "Spring", LeadIDs: models.LeadIDList{listed.ID}, Active: true})
+	db.Create(&models.CampaignSuppression{CampaignName: "Spring", Query: models.CampaignSuppressionQuery{Segments: []string{"active"}}, Active: true})
+	deleted := models.CampaignSuppression{CampaignName: "Spring", LeadIDs: models.LeadIDList{approved.ID}, Active: true}
+	db.Create(&deleted)
+	db.Model(&deleted).Update("active", false)
+	db.Create(&models.CampaignSuppression{CampaignName: "Fall", LeadIDs: models.LeadIDList{closed.ID}, Active: true})
+	db.Create(&models.Approval{ApprovalType: "rental_application", Status: "under_review", FUBLeadID: "fub-3"})
+	db.Create(&models.Approval{ApprovalType: "rental_application", Status: "approved", FUBLeadID: "fub-5"})
+	db.Create(&models.Approval{ApprovalType: "showing_request", Status: "pending"})
+	activeEmail, _ := em.Encrypt("four@example.com")
+	closedEmail, _ := em.Encrypt("six@example.com")
+	db.Create(&models.ClosingPipeline{PropertyAddress: "1 Main St", TenantEmail: activeEmail, Status: "in_progress"})
+	db.Create(&models.ClosingPipeline{PropertyAddress: "2 Main St", TenantEmail: closedEmail, Status: "completed"})
+
+	service := NewCampaignSuppressionService(db, em)
+	eligible := func() *gorm.DB {
+		return db.Model(&models.LeadReengagement{}).Where("campaign_status = ?", models.CampaignPending)
+	}
+
+	query, suppressed, err := service.Apply("Spring", eligible(), CampaignSuppressionOptions{ExcludeOpenApprovals: true, ExcludeActivePipelines: true})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if suppressed != 4 {
+		t.Errorf("Expected 4 suppressed leads, got %d", suppressed)
+	}
+	var remaining []models.LeadReengagement
+	if err := query.Order("id").Find(&remaining).Error; err != nil {
+		t.Fatalf("Failed to load unsuppressed leads: %v", err)
+	}
+	if len(remaining) != 2 || remaining[0].ID != approved.ID || remaining[1].ID != closed.ID {
+		t.Errorf("Expected only the approved and closed leads to remain, got %d leads", len(remaining))
+	}
+
+	// Without the automatic exclusions only the campaign's own lists apply
+	_, suppressed, err = service.Apply("Spring", eligible(), CampaignSuppressionOptions{})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if suppressed != 2 {
+		t.Errorf("Expected 2 suppressed leads without automatic exclusions, got %d", suppressed)
+	}
+	if _, suppressed, _ := service.Apply("Winter", eligible(), CampaignSuppressionOptions{}); suppressed != 0 {
+		t.Errorf("Expected a campaign without suppressions to suppress nothing, got %d", suppressed)
+	}
+}
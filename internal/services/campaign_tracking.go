@@ -0,0 +1,78 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// TrackingPixelGIF is a transparent 1x1 GIF served by the open-tracking endpoint
+var TrackingPixelGIF, _ = base64.StdEncoding.DecodeString("R0lGODlhAQABAIAAAAAAAP///yH5BAEAAAAALAAAAAABAAEAAAIBRAA7")
+
+// hrefPattern matches absolute http(s) links in anchor href attributes
+var hrefPattern = regexp.MustCompile(`(?i)href\s*=\s*"(https?://[^"]+)"`)
+
+// CampaignTracker builds open/click tracking URLs for campaign emails.
+// Click URLs are signed so the redirect endpoint cannot be used as an open redirect.
+type CampaignTracker struct {
+	baseURL string
+	secret  []byte
+}
+
+// NewCampaignTracker creates a tracker that builds URLs under baseURL
+func NewCampaignTracker(baseURL, secret string) *CampaignTracker {
+	return &CampaignTracker{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  []byte(secret),
+	}
+}
+
+// OpenPixelURL returns the tracking pixel URL for an execution
+func (t *CampaignTracker) OpenPixelURL(executionID uint) string {
+	return fmt.Sprintf("%s/t/open/%d.gif", t.baseURL, executionID)
+}
+
+// ClickURL returns a signed redirect URL that records a click before forwarding to target
+func (t *CampaignTracker) ClickURL(executionID uint, target string) string {
+	return fmt.Sprintf("%s/t/click/%d?u=%s&s=%s",
+		t.baseURL, executionID, url.QueryEscape(target), t.sign(executionID, target))
+}
+
+// VerifyClick checks that a click URL signature matches the execution and target
+func (t *CampaignTracker) VerifyClick(executionID uint, target, signature string) bool {
+	expected := t.sign(executionID, target)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// InstrumentHTML rewrites absolute links through the click tracker and injects the open pixel
+func (t *CampaignTracker) InstrumentHTML(body string, executionID uint) string {
+	rewritten := hrefPattern.ReplaceAllStringFunc(body, func(match string) string {
+		// The attribute is HTML-encoded (&amp; between query params); sign
+		// the URL the browser will actually follow
+		target := html.UnescapeString(hrefPattern.FindStringSubmatch(match)[1])
+		// Leave unsubscribe links untouched so opt-outs never depend on tracking
+		if strings.Contains(strings.ToLower(target), "unsubscribe") {
+			return match
+		}
+		return fmt.Sprintf(`href="%s"`, html.EscapeString(t.ClickURL(executionID, target)))
+	})
+
+	pixel := fmt.Sprintf(`<img src="%s" width="1" height="1" alt="" style="display:none" />`, t.OpenPixelURL(executionID))
+
+	if idx := strings.LastIndex(strings.ToLower(rewritten), "</body>"); idx != -1 {
+		return rewritten[:idx] + pixel + rewritten[idx:]
+	}
+	return rewritten + pixel
+}
+
+func (t *CampaignTracker) sign(executionID uint, target string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(fmt.Sprintf("%d|%s", executionID, target)))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"testing"
+)
+
+func TestCampaignTracker_SignsDecodedHrefs(t *testing.T) {
+	tracker := NewCampaignTracker("https://example.com", "secret")
+	body := `<p><a href="https://listings.example.com/search?city=Houston&amp;beds=3">Homes</a>` +
+		`<a href="https://example.com/unsubscribe?t=abc">Unsubscribe</a></p>`
+
+	instrumented := tracker.InstrumentHTML(body, 42)
+	hrefs := regexp.MustCompile(`href="([^"]+)"`).FindAllStringSubmatch(instrumented, -1)
+	if len(hrefs) != 2 {
+		t.Fatalf("Expected two links, got %d in %s", len(hrefs), instrumented)
+	}
+	if hrefs[1][1] != "https://example.com/unsubscribe?t=abc" {
+		t.Errorf("Expected the unsubscribe link left untouched, got %s", hrefs[1][1])
+	}
+
+	// A browser decodes the attribute before following it
+	clickURL, err := url.Parse(html.UnescapeString(hrefs[0][1]))
+	if err != nil {
+		t.Fatalf("Failed to parse click URL: %v", err)
+	}
+	target := clickURL.Query().Get("u")
+	if target != "https://listings.example.com/search?city=Houston&beds=3" {
+		t.Errorf("Expected the decoded link as the click target, got %s", target)
+	}
+	if !tracker.VerifyClick(42, target, clickURL.Query().Get("s")) {
+		t.Error("Expected the click signature to verify against the decoded target")
+	}
+}
@@ -529,3 +529,117 @@ func (cta *CampaignTriggerAutomation) getTemplateSubject(templateName string, da
 	
 	return subject
 }
+
+// eventTriggers are checked as each behavioral event is tracked, rather than
+// by the periodic trigger runs above
+var eventTriggers = []CampaignTrigger{
+	{
+		ID:          "high_view",
+		Name:        "Repeat property views",
+		Type:        "behavioral",
+		Condition:   "Lead viewed the same property 3+ times in 24 hours",
+		Template:    "property_reengagement",
+		Enabled:     true,
+		AutoExecute: true,
+		Priority:    1,
+		Metadata:    map[string]interface{}{"event_type": "viewed", "threshold": 3, "window_hours": 24},
+	},
+	{
+		ID:          "repeat_saves",
+		Name:        "Several saved properties",
+		Type:        "behavioral",
+		Condition:   "Lead saved 3+ properties in 7 days",
+		Template:    "hot_lead_followup",
+		Enabled:     true,
+		AutoExecute: true,
+		Priority:    2,
+		Metadata:    map[string]interface{}{"event_type": "saved", "threshold": 3, "window_hours": 168},
+	},
+}
+
+// EventTriggers returns the triggers checked as each behavioral event is tracked
+func (cta *CampaignTriggerAutomation) EventTriggers() []CampaignTrigger {
+	return append([]CampaignTrigger(nil), eventTriggers...)
+}
+
+// MatchEventTriggers returns the event triggers a just-tracked behavioral event satisfies
+func (cta *CampaignTriggerAutomation) MatchEventTriggers(event *models.BehavioralEvent) ([]CampaignTrigger, error) {
+	var matched []CampaignTrigger
+	for _, trigger := range eventTriggers {
+		if trigger.Metadata["event_type"] != event.EventType {
+			continue
+		}
+
+		since := event.CreatedAt.Add(-time.Duration(trigger.Metadata["window_hours"].(int)) * time.Hour)
+		query := cta.db.Model(&models.BehavioralEvent{}).
+			Where("lead_id = ? AND event_type = ? AND created_at >= ?", event.LeadID, event.EventType, since)
+		if trigger.ID == "high_view" {
+			if event.PropertyID == nil {
+				continue
+			}
+			query = query.Where("property_id = ?", *event.PropertyID)
+		}
+
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count >= int64(trigger.Metadata["threshold"].(int)) {
+			matched = append(matched, trigger)
+		}
+	}
+	return matched, nil
+}
+
+// ExecuteEventTrigger queues the trigger's campaign email to the event's lead
+func (cta *CampaignTriggerAutomation) ExecuteEventTrigger(trigger CampaignTrigger, event *models.BehavioralEvent) (*CampaignExecution, error) {
+	var lead models.Lead
+	if err := cta.db.First(&lead, event.LeadID).Error; err != nil {
+		return nil, fmt.Errorf("lead %d not found: %v", event.LeadID, err)
+	}
+	if lead.Email == "" {
+		return nil, fmt.Errorf("lead %d has no email address", event.LeadID)
+	}
+
+	templateData := map[string]interface{}{
+		"lead_name":        strings.TrimSpace(lead.FirstName + " " + lead.LastName),
+		"lead_email":       lead.Email,
+		"property_address": "the property",
+		"view_count":       trigger.Metadata["threshold"],
+	}
+	if event.PropertyID != nil {
+		templateData["property_id"] = *event.PropertyID
+		var property models.Property
+		if err := cta.db.Select("id", "address").First(&property, *event.PropertyID).Error; err == nil && property.Address != "" {
+			templateData["property_address"] = string(property.Address)
+		}
+	}
+
+	execution := &CampaignExecution{
+		ID:           fmt.Sprintf("exec_%s_%d_%d", trigger.ID, event.LeadID, time.Now().Unix()),
+		TriggerID:    trigger.ID,
+		TriggerType:  trigger.Type,
+		LeadID:       event.LeadID,
+		Template:     trigger.Template,
+		TemplateData: templateData,
+		ExecutedAt:   time.Now(),
+		Status:       "failed",
+	}
+	if cta.emailBatch == nil {
+		return execution, fmt.Errorf("email batch service not available")
+	}
+
+	err := cta.emailBatch.QueueEmail(EmailJob{
+		To:       []string{lead.Email},
+		Subject:  cta.getTemplateSubject(trigger.Template, templateData),
+		Body:     cta.getTemplateContent(trigger.Template, templateData),
+		Priority: trigger.Priority,
+		Metadata: map[string]interface{}{"trigger_id": trigger.ID, "lead_id": event.LeadID},
+	})
+	if err != nil {
+		return execution, err
+	}
+	execution.Status = "sent"
+	log.Printf("📧 Sent %s trigger campaign to lead %d (template: %s)", trigger.ID, event.LeadID, trigger.Template)
+	return execution, nil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -10,6 +11,12 @@ import (
 	"chrisgross-ctrl-project/internal/security"
 )
 
+// ErrPropertyDeleted is returned when syncing state for a property that is in the trash
+var ErrPropertyDeleted = errors.New("property is deleted")
+
+// deletedPropertyMLSIDs selects the MLS IDs of soft-deleted properties
+const deletedPropertyMLSIDs = "SELECT mls_id FROM properties WHERE deleted_at IS NOT NULL AND mls_id <> ''"
+
 // CentralPropertyStateManager manages the single source of truth for all property data
 type CentralPropertyStateManager struct {
 	db                *gorm.DB
@@ -29,6 +36,13 @@ func NewCentralPropertyStateManager(db *gorm.DB, encryptionManager *security.Enc
 func (cpsm *CentralPropertyStateManager) CreateOrUpdateProperty(request models.PropertyUpdateRequest) (*models.PropertyState, error) {
 	log.Printf("🔄 Creating/updating property: %s", request.Address)
 
+	if deleted, err := cpsm.isPropertyDeleted(request.MLSId); err != nil {
+		return nil, err
+	} else if deleted {
+		log.Printf("⏭️ Skipping sync for deleted property: %s", request.MLSId)
+		return nil, ErrPropertyDeleted
+	}
+
 	// Convert PropertyUpdateRequest to PropertyState
 	propertyState := &models.PropertyState{
 		MLSId:        request.MLSId,
@@ -143,7 +157,7 @@ func (cpsm *CentralPropertyStateManager) GetPropertyState(mlsID string) (*models
 // GetAllProperties retrieves all properties in the central state
 func (cpsm *CentralPropertyStateManager) GetAllProperties() ([]models.PropertyState, error) {
 	var properties []models.PropertyState
-	if err := cpsm.db.Find(&properties).Error; err != nil {
+	if err := cpsm.db.Where("mls_id NOT IN (" + deletedPropertyMLSIDs + ")").Find(&properties).Error; err != nil {
 		return nil, fmt.Errorf("failed to retrieve properties: %v", err)
 	}
 	return properties, nil
@@ -158,6 +172,7 @@ func (cpsm *CentralPropertyStateManager) GetPublicProperties() ([]models.Propert
 	err := cpsm.db.Raw(`
 		SELECT * FROM property_states 
 		WHERE is_available_for_showing = true
+		AND mls_id NOT IN (`+deletedPropertyMLSIDs+`)
 	`).Scan(&propertyStates).Error
 	
 	if err != nil {
@@ -170,6 +185,12 @@ func (cpsm *CentralPropertyStateManager) GetPublicProperties() ([]models.Propert
 
 // UpdatePropertyStatus updates the status of a property by MLS ID
 func (cpsm *CentralPropertyStateManager) UpdatePropertyStatus(mlsID string, newStatus string, source string) error {
+	if deleted, err := cpsm.isPropertyDeleted(mlsID); err != nil {
+		return err
+	} else if deleted {
+		return ErrPropertyDeleted
+	}
+
 	// Find property by MLS ID
 	var property models.PropertyState
 	if err := cpsm.db.Where("mls_id = ?", mlsID).First(&property).Error; err != nil {
@@ -204,3 +225,18 @@ func (cpsm *CentralPropertyStateManager) UpdatePropertyStatus(mlsID string, newS
 
 	return nil
 }
+
+// isPropertyDeleted reports whether the property with this MLS ID is in the trash
+func (cpsm *CentralPropertyStateManager) isPropertyDeleted(mlsID string) (bool, error) {
+	if mlsID == "" {
+		return false, nil
+	}
+	var count int64
+	err := cpsm.db.Unscoped().Model(&models.Property{}).
+		Where("mls_id = ? AND deleted_at IS NOT NULL", mlsID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check property deletion state: %w", err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+// Cohort granularities and retention metrics
+const (
+	CohortWeekly  = "week"
+	CohortMonthly = "month"
+
+	CohortMetricEngagement = "engagement" // % of the cohort with a session in the period
+	CohortMetricConversion = "conversion" // % of the cohort converted by the end of the period
+
+	maxCohorts = 52
+)
+
+// CohortRetentionRow is one acquisition cohort's row in the retention matrix
+type CohortRetentionRow struct {
+	Cohort string     `json:"cohort"` // start of the acquisition period, YYYY-MM-DD
+	Size   int        `json:"size"`
+	Values []*float64 `json:"values"` // % per period since acquisition; null for periods not yet reached
+}
+
+// CohortRetention groups leads by when they were acquired and tracks the
+// share still engaged, or converted, in each following period
+type CohortRetention struct {
+	Granularity string               `json:"granularity"`
+	Metric      string               `json:"metric"`
+	Periods     int                  `json:"periods"`
+	Cohorts     []CohortRetentionRow `json:"cohorts"`
+	GeneratedAt time.Time            `json:"generated_at"`
+}
+
+// GetCohortRetention builds the retention matrix for the last `cohorts` weeks
+// or months, using behavioral sessions as the engagement signal
+func (bis *BusinessIntelligenceService) GetCohortRetention(granularity, metric string, cohorts int) (*CohortRetention, error) {
+	return bis.cohortRetention(granularity, metric, cohorts, time.Now())
+}
+
+func (bis *BusinessIntelligenceService) cohortRetention(granularity, metric string, cohorts int, now time.Time) (*CohortRetention, error) {
+	if granularity != CohortWeekly && granularity != CohortMonthly {
+		return nil, fmt.Errorf("unknown cohort granularity %q", granularity)
+	}
+	if metric != CohortMetricEngagement && metric != CohortMetricConversion {
+		return nil, fmt.Errorf("unknown cohort metric %q", metric)
+	}
+	if cohorts < 1 || cohorts > maxCohorts {
+		return nil, fmt.Errorf("cohorts must be between 1 and %d", maxCohorts)
+	}
+
+	current := cohortPeriodStart(now, granularity)
+	first := addCohortPeriods(current, granularity, -(cohorts - 1))
+
+	var leads []struct {
+		ID        int64
+		CreatedAt time.Time
+	}
+	if err := bis.db.Table("leads").
+		Select("id, created_at").
+		Where("created_at >= ? AND created_at <= ?", first, now).
+		Scan(&leads).Error; err != nil {
+		return nil, fmt.Errorf("failed to load leads: %w", err)
+	}
+
+	cohortOf := make(map[int64]int, len(leads))
+	sizes := make([]int, cohorts)
+	leadIDs := make([]int64, 0, len(leads))
+	for _, lead := range leads {
+		index := cohortPeriodsBetween(first, lead.CreatedAt, granularity)
+		if index < 0 || index >= cohorts {
+			continue
+		}
+		cohortOf[lead.ID] = index
+		sizes[index]++
+		leadIDs = append(leadIDs, lead.ID)
+	}
+
+	// counts[cohort][period] is how many of the cohort's leads hit the metric
+	counts := make([][]int, cohorts)
+	for i := range counts {
+		counts[i] = make([]int, cohorts-i)
+	}
+
+	if len(leadIDs) > 0 {
+		var activity []struct {
+			LeadID int64
+			At     time.Time
+		}
+		var err error
+		if metric == CohortMetricEngagement {
+			err = bis.db.Table("behavioral_sessions").
+				Select("lead_id, start_time AS at").
+				Where("lead_id IN ? AND start_time >= ?", leadIDs, first).
+				Scan(&activity).Error
+		} else {
+			err = bis.db.Table("behavioral_events").
+				Select("lead_id, created_at AS at").
+				Where("lead_id IN ? AND event_type IN ?", leadIDs, conversionStageEvents["closing"]).
+				Order("created_at").
+				Scan(&activity).Error
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s activity: %w", metric, err)
+		}
+
+		seen := make(map[[2]int64]bool)
+		converted := make(map[int64]bool)
+		for _, a := range activity {
+			cohort := cohortOf[a.LeadID]
+			cohortStart := addCohortPeriods(first, granularity, cohort)
+			period := cohortPeriodsBetween(cohortStart, a.At, granularity)
+			if period < 0 || period >= len(counts[cohort]) {
+				continue
+			}
+			if metric == CohortMetricConversion {
+				// Converted leads stay converted in every later period, so
+				// only the first conversion counts
+				if converted[a.LeadID] {
+					continue
+				}
+				converted[a.LeadID] = true
+				for p := period; p < len(counts[cohort]); p++ {
+					counts[cohort][p]++
+				}
+				continue
+			}
+			key := [2]int64{a.LeadID, int64(period)}
+			if !seen[key] {
+				seen[key] = true
+				counts[cohort][period]++
+			}
+		}
+	}
+
+	retention := &CohortRetention{
+		Granularity: granularity,
+		Metric:      metric,
+		Periods:     cohorts,
+		GeneratedAt: now,
+	}
+	for i := 0; i < cohorts; i++ {
+		row := CohortRetentionRow{
+			Cohort: addCohortPeriods(first, granularity, i).Format("2006-01-02"),
+			Size:   sizes[i],
+			Values: make([]*float64, cohorts),
+		}
+		for p, count := range counts[i] {
+			value := 0.0
+			if sizes[i] > 0 {
+				value = float64(count) / float64(sizes[i]) * 100
+			}
+			row.Values[p] = &value
+		}
+		retention.Cohorts = append(retention.Cohorts, row)
+	}
+	return retention, nil
+}
+
+// cohortPeriodStart returns the start of the week (Monday) or month containing t
+func cohortPeriodStart(t time.Time, granularity string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if granularity == CohortMonthly {
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func addCohortPeriods(start time.Time, granularity string, n int) time.Time {
+	if granularity == CohortMonthly {
+		return start.AddDate(0, n, 0)
+	}
+	return start.AddDate(0, 0, 7*n)
+}
+
+// cohortPeriodsBetween counts whole periods from start to t
+func cohortPeriodsBetween(start, t time.Time, granularity string) int {
+	periodStart := cohortPeriodStart(t.In(start.Location()), granularity)
+	if granularity == CohortMonthly {
+		return (periodStart.Year()-start.Year())*12 + int(periodStart.Month()-start.Month())
+	}
+	return int(periodStart.Sub(start).Hours()+12) / (7 * 24)
+}
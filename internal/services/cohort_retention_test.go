@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestCohortRetention_WeeklyEngagementAndConversion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Lead{}, &models.BehavioralSession{}, &models.BehavioralEvent{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	// Wednesday; the four weekly cohorts start Mondays Sep 21 through Oct 12
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	week := func(n int, day int) time.Time { return time.Date(2026, 9, 21+7*n+day, 10, 0, 0, 0, time.UTC) }
+
+	db.Create(&models.Lead{ID: 1, FirstName: "Ann", FUBLeadID: "fub-1", CreatedAt: week(0, 1)})
+	db.Create(&models.Lead{ID: 2, FirstName: "Ben", FUBLeadID: "fub-2", CreatedAt: week(0, 4)})
+	db.Create(&models.Lead{ID: 3, FirstName: "Cy", FUBLeadID: "fub-3", CreatedAt: week(3, 0)})
+	db.Create(&models.Lead{ID: 4, FirstName: "Old", FUBLeadID: "fub-4", CreatedAt: week(-2, 0)})
+
+	db.Create(&models.BehavioralSession{ID: "s1", LeadID: 1, StartTime: week(0, 1)})
+	db.Create(&models.BehavioralSession{ID: "s2", LeadID: 1, StartTime: week(2, 3)})
+	db.Create(&models.BehavioralSession{ID: "s3", LeadID: 1, StartTime: week(2, 4)})
+	db.Create(&models.BehavioralSession{ID: "s4", LeadID: 2, StartTime: week(0, 5)})
+	db.Create(&models.BehavioralEvent{LeadID: 1, EventType: "converted", CreatedAt: week(1, 2)})
+
+	service := NewBusinessIntelligenceService(db)
+	engagement, err := service.cohortRetention(CohortWeekly, CohortMetricEngagement, 4, now)
+	if err != nil {
+		t.Fatalf("cohortRetention failed: %v", err)
+	}
+	if len(engagement.Cohorts) != 4 || engagement.Cohorts[0].Cohort != "2026-09-21" || engagement.Cohorts[0].Size != 2 || engagement.Cohorts[3].Size != 1 {
+		t.Fatalf("Expected four weekly cohorts from Sep 21 sized 2,0,0,1, got %+v", engagement.Cohorts)
+	}
+	want := []float64{100, 0, 50, 0}
+	for i, value := range engagement.Cohorts[0].Values {
+		if value == nil || *value != want[i] {
+			t.Errorf("Expected first cohort engagement %v, got period %d = %v", want, i, value)
+		}
+	}
+	if latest := engagement.Cohorts[3].Values; latest[0] == nil || latest[1] != nil {
+		t.Errorf("Expected only period 0 for the current cohort, got %v", latest)
+	}
+
+	conversion, err := service.cohortRetention(CohortWeekly, CohortMetricConversion, 4, now)
+	if err != nil {
+		t.Fatalf("cohortRetention failed: %v", err)
+	}
+	want = []float64{0, 50, 50, 50}
+	for i, value := range conversion.Cohorts[0].Values {
+		if value == nil || *value != want[i] {
+			t.Errorf("Expected first cohort conversion %v, got period %d = %v", want, i, value)
+		}
+	}
+}
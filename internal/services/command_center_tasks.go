@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+// Command center task generation defaults
+const (
+	defaultTaskMinConfidence = 0.8            // Lowest insight confidence (0-1) turned into a task
+	taskRegenerateCooldown   = 24 * time.Hour // How long a completed task holds off a new one for the same source
+	taskHotLeadLimit         = 25             // Hot leads considered per run
+)
+
+// ErrTaskNotFound is returned for an unknown task ID
+var ErrTaskNotFound = errors.New("task not found")
+
+// ErrTaskNotOpen is returned when completing or snoozing a completed task
+var ErrTaskNotOpen = errors.New("task is not open")
+
+// TaskFilter narrows ListTasks
+type TaskFilter struct {
+	AssignedTo     string // Admin user ID; empty for every task
+	Status         string // open or completed; empty for both
+	IncludeSnoozed bool
+	Limit          int
+}
+
+// CommandCenterTaskService turns high-confidence command center insights into
+// assignable tasks and tracks them through completion
+type CommandCenterTaskService struct {
+	db            *gorm.DB
+	insights      digestInsightSource
+	hub           *AdminNotificationHub
+	minConfidence float64
+	now           func() time.Time
+}
+
+// NewCommandCenterTaskService creates a task service. insights may be nil, in
+// which case only hot lead tasks are generated.
+func NewCommandCenterTaskService(db *gorm.DB, insights *InsightGeneratorService) *CommandCenterTaskService {
+	s := &CommandCenterTaskService{
+		db:            db,
+		minConfidence: defaultTaskMinConfidence,
+		now:           time.Now,
+	}
+	if insights != nil {
+		s.insights = insights
+	}
+	return s
+}
+
+// SetNotificationHub notifies assigned agents of new tasks through the hub
+func (s *CommandCenterTaskService) SetNotificationHub(hub *AdminNotificationHub) {
+	s.hub = hub
+}
+
+// SetMinConfidence sets the lowest confidence (0-1) turned into a task
+func (s *CommandCenterTaskService) SetMinConfidence(confidence float64) {
+	if confidence > 0 && confidence <= 1 {
+		s.minConfidence = confidence
+	}
+}
+
+// Start generates tasks on an interval until ctx is cancelled
+func (s *CommandCenterTaskService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if created, err := s.GenerateTasks(); err != nil {
+				log.Printf("⚠️ Failed to generate command center tasks: %v", err)
+			} else if created > 0 {
+				log.Printf("✅ Created %d command center tasks", created)
+			}
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 Command center task generation stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	log.Printf("🕐 Command center task generation started (every %v, min confidence %.2f)", interval, s.minConfidence)
+}
+
+// GenerateTasks converts hot leads and high-priority insights at or above the
+// minimum confidence into tasks, skipping any that already have an open task
+// or one completed within the last day. It returns how many were created.
+func (s *CommandCenterTaskService) GenerateTasks() (int, error) {
+	now := s.now()
+
+	candidates, err := s.hotLeadTasks(now)
+	if err != nil {
+		return 0, err
+	}
+	candidates = append(candidates, s.insightTasks(now)...)
+
+	created := 0
+	for i := range candidates {
+		task := &candidates[i]
+		exists, err := s.hasActiveTask(task.SourceKey, now)
+		if err != nil {
+			return created, err
+		}
+		if exists {
+			continue
+		}
+		if err := s.db.Create(task).Error; err != nil {
+			return created, err
+		}
+		s.notify(task)
+		created++
+	}
+	return created, nil
+}
+
+// hotLeadTasks builds call tasks for hot leads, assigned to the lead's agent
+// and due at the optimal next action time for its urgency and engagement
+func (s *CommandCenterTaskService) hotLeadTasks(now time.Time) ([]models.Task, error) {
+	var leads []struct {
+		LeadID          int64
+		FirstName       string
+		LastName        string
+		AssignedAgentID string
+		CompositeScore  int
+		EngagementScore int
+	}
+	err := s.db.Table("leads").
+		Select("leads.id AS lead_id, leads.first_name, leads.last_name, leads.assigned_agent_id, "+
+			"MAX(behavioral_scores.composite_score) AS composite_score, MAX(behavioral_scores.engagement_score) AS engagement_score").
+		Joins("JOIN behavioral_scores ON behavioral_scores.lead_id = leads.id").
+		Where("leads.status IN ?", []string{"new", "active", "warm"}).
+		Where("behavioral_scores.composite_score >= ?", int(s.minConfidence*100)).
+		Group("leads.id, leads.first_name, leads.last_name, leads.assigned_agent_id").
+		Order("composite_score DESC").
+		Limit(taskHotLeadLimit).
+		Scan(&leads).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]models.Task, 0, len(leads))
+	for _, lead := range leads {
+		leadID := lead.LeadID
+		confidence := float64(lead.CompositeScore) / 100
+		dueAt := OptimalNextActionTime(now, confidence, float64(lead.EngagementScore)/100, "", nil)
+		name := strings.TrimSpace(lead.FirstName + " " + lead.LastName)
+
+		tasks = append(tasks, models.Task{
+			Title:       fmt.Sprintf("Call %s %s", name, dueWindow(now, dueAt)),
+			Description: fmt.Sprintf("Behavioral score %d — reach out while they're engaged", lead.CompositeScore),
+			Type:        "call_lead",
+			Source:      models.TaskSourceHotLead,
+			SourceKey:   fmt.Sprintf("hot_lead:%d", lead.LeadID),
+			LeadID:      &leadID,
+			AssignedTo:  lead.AssignedAgentID,
+			Priority:    10,
+			Confidence:  confidence,
+			Status:      models.TaskStatusOpen,
+			DueAt:       dueAt,
+		})
+	}
+	return tasks, nil
+}
+
+// insightTasks builds team tasks for dashboard insights whose priority (1-5)
+// puts them at or above the minimum confidence
+func (s *CommandCenterTaskService) insightTasks(now time.Time) []models.Task {
+	if s.insights == nil {
+		return nil
+	}
+	dashboard, err := s.insights.GenerateDashboardInsights()
+	if err != nil || dashboard == nil {
+		return nil
+	}
+
+	tasks := []models.Task{}
+	for _, insight := range dashboard.Insights {
+		confidence := float64(insight.Priority) / 5
+		if confidence < s.minConfidence {
+			continue
+		}
+		title := insight.Message
+		if len(insight.Actions) > 0 {
+			title = insight.Actions[0].Label
+		}
+		tasks = append(tasks, models.Task{
+			Title:       title,
+			Description: insight.Message,
+			Type:        "review_insight",
+			Source:      models.TaskSourceInsight,
+			SourceKey:   "insight:" + insight.ID,
+			Priority:    insight.Priority * 2,
+			Confidence:  confidence,
+			Status:      models.TaskStatusOpen,
+			DueAt:       OptimalNextActionTime(now, confidence, 0, "", nil),
+		})
+	}
+	return tasks
+}
+
+// hasActiveTask reports whether a source already has an open task or one
+// completed within the regenerate cooldown
+func (s *CommandCenterTaskService) hasActiveTask(sourceKey string, now time.Time) (bool, error) {
+	var count int64
+	err := s.db.Model(&models.Task{}).
+		Where("source_key = ?", sourceKey).
+		Where("status = ? OR (status = ? AND completed_at > ?)", models.TaskStatusOpen, models.TaskStatusCompleted, now.Add(-taskRegenerateCooldown)).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// dueWindow describes a due time relative to now, e.g. "within 15 min" or
+// "by Mon 9:00 AM"
+func dueWindow(now, dueAt time.Time) string {
+	if wait := dueAt.Sub(now); wait <= time.Hour {
+		return fmt.Sprintf("within %d min", int(wait.Round(time.Minute).Minutes()))
+	}
+	return "by " + dueAt.Format("Mon 3:04 PM")
+}
+
+// notify tells the assigned agent, or the team, about a new task
+func (s *CommandCenterTaskService) notify(task *models.Task) {
+	if s.hub != nil {
+		s.hub.SendTaskAssignedAlert(task)
+	}
+}
+
+// ListTasks returns tasks, open ones soonest due first
+func (s *CommandCenterTaskService) ListTasks(filter TaskFilter) ([]models.Task, error) {
+	query := s.db.Model(&models.Task{}).Order("status DESC, due_at ASC")
+	if filter.AssignedTo != "" {
+		query = query.Where("assigned_to = ?", filter.AssignedTo)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if !filter.IncludeSnoozed {
+		query = query.Where("snoozed_until IS NULL OR snoozed_until <= ?", s.now())
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var tasks []models.Task
+	err := query.Find(&tasks).Error
+	return tasks, err
+}
+
+// CreateTask saves a task created by hand and notifies its assignee
+func (s *CommandCenterTaskService) CreateTask(task *models.Task) error {
+	if strings.TrimSpace(task.Title) == "" {
+		return errors.New("title is required")
+	}
+	task.Source = models.TaskSourceManual
+	task.SourceKey = ""
+	task.Status = models.TaskStatusOpen
+	if task.DueAt.IsZero() {
+		task.DueAt = OptimalNextActionTime(s.now(), 0, 0, "", nil)
+	}
+	if err := s.db.Create(task).Error; err != nil {
+		return err
+	}
+	s.notify(task)
+	return nil
+}
+
+// CompleteTask marks an open task completed by an admin
+func (s *CommandCenterTaskService) CompleteTask(id uint, adminUserID string) (*models.Task, error) {
+	task, err := s.openTask(id)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	task.Status = models.TaskStatusCompleted
+	task.CompletedAt = &now
+	task.CompletedBy = adminUserID
+	task.SnoozedUntil = nil
+	if err := s.db.Save(task).Error; err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// SnoozeTask hides an open task until a later time
+func (s *CommandCenterTaskService) SnoozeTask(id uint, until time.Time) (*models.Task, error) {
+	if !until.After(s.now()) {
+		return nil, errors.New("snooze time must be in the future")
+	}
+	task, err := s.openTask(id)
+	if err != nil {
+		return nil, err
+	}
+	task.SnoozedUntil = &until
+	if err := s.db.Save(task).Error; err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// AssignTask reassigns an open task and notifies the new assignee
+func (s *CommandCenterTaskService) AssignTask(id uint, adminUserID string) (*models.Task, error) {
+	task, err := s.openTask(id)
+	if err != nil {
+		return nil, err
+	}
+	task.AssignedTo = adminUserID
+	if err := s.db.Save(task).Error; err != nil {
+		return nil, err
+	}
+	s.notify(task)
+	return task, nil
+}
+
+// openTask loads a task that can still be acted on
+func (s *CommandCenterTaskService) openTask(id uint) (*models.Task, error) {
+	var task models.Task
+	if err := s.db.First(&task, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, err
+	}
+	if task.Status != models.TaskStatusOpen {
+		return nil, ErrTaskNotOpen
+	}
+	return &task, nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestCommandCenterTasks_GeneratesDedupedHotLeadTasks(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Lead{}, &models.Task{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	// behavioral_scores uses postgres defaults, so create it by hand for sqlite
+	if err := db.Exec(`CREATE TABLE behavioral_scores (id TEXT PRIMARY KEY, lead_id INTEGER, composite_score INTEGER, engagement_score INTEGER)`).Error; err != nil {
+		t.Fatalf("Failed to create behavioral_scores: %v", err)
+	}
+	db.Create(&models.Lead{ID: 1, FirstName: "Dana", LastName: "Hot", Email: "dana@example.com", FUBLeadID: "fub-1", Status: "active", AssignedAgentID: "admin-1"})
+	db.Create(&models.Lead{ID: 2, FirstName: "Riley", LastName: "Warm", Email: "riley@example.com", FUBLeadID: "fub-2", Status: "active"})
+	db.Exec(`INSERT INTO behavioral_scores VALUES ('s1', 1, 85, 50), ('s2', 2, 60, 90)`)
+
+	central, _ := time.LoadLocation("America/Chicago")
+	now := time.Date(2026, 10, 14, 15, 0, 0, 0, central) // A Wednesday afternoon
+	tasks := NewCommandCenterTaskService(db, nil)
+	tasks.now = func() time.Time { return now }
+
+	if created, err := tasks.GenerateTasks(); err != nil || created != 1 {
+		t.Fatalf("Expected one task for the hot lead, got %d (%v)", created, err)
+	}
+	open, _ := tasks.ListTasks(TaskFilter{Status: models.TaskStatusOpen})
+	if len(open) != 1 {
+		t.Fatalf("Expected 1 open task, got %d", len(open))
+	}
+	task := open[0]
+	if task.Title != "Call Dana Hot within 15 min" || task.AssignedTo != "admin-1" {
+		t.Errorf("Expected a 15 minute call task for admin-1, got %q for %q", task.Title, task.AssignedTo)
+	}
+	if !task.DueAt.Equal(now.Add(15 * time.Minute)) {
+		t.Errorf("Expected the task due at %v, got %v", now.Add(15*time.Minute), task.DueAt)
+	}
+
+	if created, _ := tasks.GenerateTasks(); created != 0 {
+		t.Errorf("Expected the open task to dedupe, got %d new", created)
+	}
+
+	// Snoozed tasks drop out of the list until the snooze ends
+	if _, err := tasks.SnoozeTask(task.ID, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Failed to snooze: %v", err)
+	}
+	if open, _ := tasks.ListTasks(TaskFilter{Status: models.TaskStatusOpen}); len(open) != 0 {
+		t.Errorf("Expected the snoozed task to be hidden, got %d", len(open))
+	}
+	if open, _ := tasks.ListTasks(TaskFilter{Status: models.TaskStatusOpen, IncludeSnoozed: true}); len(open) != 1 {
+		t.Errorf("Expected include_snoozed to list it, got %d", len(open))
+	}
+
+	// A completed task holds off a new one for the same lead for a day
+	if _, err := tasks.CompleteTask(task.ID, "admin-1"); err != nil {
+		t.Fatalf("Failed to complete: %v", err)
+	}
+	if _, err := tasks.CompleteTask(task.ID, "admin-1"); err != ErrTaskNotOpen {
+		t.Errorf("Expected completing twice to fail, got %v", err)
+	}
+	if created, _ := tasks.GenerateTasks(); created != 0 {
+		t.Errorf("Expected no new task right after completing, got %d", created)
+	}
+	now = now.Add(25 * time.Hour)
+	if created, _ := tasks.GenerateTasks(); created != 1 {
+		t.Errorf("Expected a new task a day later, got %d", created)
+	}
+}
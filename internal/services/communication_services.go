@@ -16,6 +16,7 @@ type EmailService struct {
 	fromEmail    string
 	fromName     string
 	isConfigured bool
+	dnc          *DNCService
 }
 
 type SMSService struct {
@@ -23,6 +24,7 @@ type SMSService struct {
 	awsService   *AWSCommunicationService
 	from         string
 	isConfigured bool
+	dnc          *DNCService
 }
 
 type NotificationService struct {
@@ -95,6 +97,16 @@ func NewBehavioralLeadScoringService() *BehavioralLeadScoringService {
 	return &BehavioralLeadScoringService{}
 }
 
+// SetDNCService refuses email to addresses on the do-not-contact list
+func (es *EmailService) SetDNCService(dnc *DNCService) {
+	es.dnc = dnc
+}
+
+// SetDNCService refuses SMS to numbers on the do-not-contact list
+func (ss *SMSService) SetDNCService(dnc *DNCService) {
+	ss.dnc = dnc
+}
+
 func (es *EmailService) SendEmail(to, subject, content string, metadata map[string]interface{}) error {
 	controls := safety.GetSafetyControls()
 	if !controls.IsEmailSendingAllowed() {
@@ -102,6 +114,12 @@ func (es *EmailService) SendEmail(to, subject, content string, metadata map[stri
 		return fmt.Errorf("email sending is disabled by safety controls")
 	}
 
+	if es.dnc != nil {
+		if err := es.dnc.CheckEmail(to, "email_service", nil); err != nil {
+			return err
+		}
+	}
+
 	if !es.isConfigured || es.awsService == nil {
 		log.Printf("⚠️  Email not configured - would have sent: To=%s, Subject=%s", to, subject)
 		return fmt.Errorf("email service not configured")
@@ -134,6 +152,12 @@ func (ss *SMSService) SendSMS(to, content string, metadata map[string]interface{
 		return fmt.Errorf("SMS sending is disabled by safety controls")
 	}
 
+	if ss.dnc != nil {
+		if err := ss.dnc.CheckSMS(to, "sms_service", nil); err != nil {
+			return err
+		}
+	}
+
 	if !ss.isConfigured || ss.awsService == nil {
 		log.Printf("⚠️  SMS not configured - would have sent: To=%s, Content=%s", to, content)
 		return fmt.Errorf("SMS service not configured")
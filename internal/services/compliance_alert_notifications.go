@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/config"
+	"chrisgross-ctrl-project/internal/models"
+)
+
+// Compliance alert notification channels
+const (
+	AlertChannelEmail   = "email"
+	AlertChannelSMS     = "sms"
+	AlertChannelWebhook = "webhook"
+)
+
+// alertEmailSender is the part of EmailService the alert manager depends on
+type alertEmailSender interface {
+	SendEmail(to, subject, content string, metadata map[string]interface{}) error
+}
+
+// alertSMSSender is the part of SMSService the alert manager depends on
+type alertSMSSender interface {
+	SendSMS(to, content string, metadata map[string]interface{}) error
+}
+
+// AlertNotificationRoute lists the channels and recipients for one alert severity
+type AlertNotificationRoute struct {
+	Channels []string
+	Emails   []string
+	Phones   []string
+}
+
+// AlertNotificationRoutesFromConfig builds per-severity notification routes from settings
+func AlertNotificationRoutesFromConfig(cfg *config.Config) map[string]AlertNotificationRoute {
+	routes := make(map[string]AlertNotificationRoute)
+	for severity, channels := range cfg.ComplianceAlertChannels {
+		routes[severity] = AlertNotificationRoute{
+			Channels: channels,
+			Emails:   cfg.ComplianceAlertEmails[severity],
+			Phones:   cfg.ComplianceAlertPhones[severity],
+		}
+	}
+	return routes
+}
+
+// SetNotifiers sets the services alerts are delivered through. Any may be nil.
+func (am *AlertManager) SetNotifiers(email *EmailService, sms *SMSService, webhooks *OutboundWebhookService) {
+	// Avoid storing typed nils so the channel checks in sendNotification work
+	if email != nil {
+		am.email = email
+	}
+	if sms != nil {
+		am.sms = sms
+	}
+	am.webhooks = webhooks
+}
+
+// SetNotificationRoutes sets the channels and recipients used for each severity
+func (am *AlertManager) SetNotificationRoutes(routes map[string]AlertNotificationRoute) {
+	am.routes = routes
+}
+
+// SetNotificationCooldown sets the minimum time between notifications for one alert type
+func (am *AlertManager) SetNotificationCooldown(cooldown time.Duration) {
+	am.cooldown = cooldown
+}
+
+// reserveNotification reports whether an alert type may notify now, and if so
+// starts its cooldown. This keeps an alert that is repeatedly resolved and
+// re-raised from flooding recipients.
+func (am *AlertManager) reserveNotification(alertType string) bool {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	if last, ok := am.lastNotified[alertType]; ok && time.Since(last) < am.cooldown {
+		log.Printf("🔕 Compliance alert %s notification suppressed (cooldown until %s)",
+			alertType, last.Add(am.cooldown).Format(time.RFC3339))
+		return false
+	}
+	am.lastNotified[alertType] = time.Now()
+	return true
+}
+
+// sendNotification delivers an alert over the channels configured for its severity
+func (am *AlertManager) sendNotification(alert models.ComplianceAlert) {
+	route, ok := am.routes[alert.Severity]
+	if !ok || len(route.Channels) == 0 {
+		log.Printf("[ALERT] Logging to monitoring system: %s", alert.Title)
+		return
+	}
+
+	subject := fmt.Sprintf("%s compliance alert: %s", strings.ToUpper(alert.Severity), alert.Title)
+	for _, channel := range route.Channels {
+		switch channel {
+		case AlertChannelEmail:
+			if am.email == nil {
+				continue
+			}
+			body := fmt.Sprintf("<p><strong>%s</strong></p><p>%s</p><p>Recommended action: %s</p><p>Alert ID: %s</p>",
+				html.EscapeString(alert.Title), html.EscapeString(alert.Message), html.EscapeString(alert.Action), alert.ID)
+			for _, to := range route.Emails {
+				if err := am.email.SendEmail(to, subject, body, map[string]interface{}{"alert_id": alert.ID}); err != nil {
+					log.Printf("⚠️ Failed to email compliance alert %s to %s: %v", alert.ID, to, err)
+				}
+			}
+		case AlertChannelSMS:
+			if am.sms == nil {
+				continue
+			}
+			message := fmt.Sprintf("%s: %s", subject, alert.Message)
+			for _, to := range route.Phones {
+				if err := am.sms.SendSMS(to, message, map[string]interface{}{"alert_id": alert.ID}); err != nil {
+					log.Printf("⚠️ Failed to text compliance alert %s to %s: %v", alert.ID, to, err)
+				}
+			}
+		case AlertChannelWebhook:
+			if err := am.webhooks.Send("compliance.alert", alert); err != nil {
+				log.Printf("⚠️ Failed to deliver compliance alert %s webhook: %v", alert.ID, err)
+			}
+		default:
+			log.Printf("⚠️ Unknown compliance alert channel %q for %s alerts", channel, alert.Severity)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAlertManager_PersistsAndDedupesUnresolvedAlerts(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.ComplianceAlert{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	manager := NewAlertManager(db)
+	manager.TriggerAlert(models.ComplianceAlert{ID: "volume_1", Type: "volume_warning", Severity: "medium", Title: "Volume"})
+	manager.TriggerAlert(models.ComplianceAlert{ID: "volume_2", Type: "volume_warning", Severity: "medium", Title: "Volume"})
+
+	// A fresh manager sees the same state, as another instance or a restart would
+	restarted := NewAlertManager(db)
+	active := restarted.GetActiveAlerts()
+	if len(active) != 1 || active[0].ID != "volume_1" {
+		t.Fatalf("Expected only the first volume alert to be active, got %+v", active)
+	}
+
+	if err := restarted.AcknowledgeAlert("volume_1", "agent"); err != nil {
+		t.Fatalf("Failed to acknowledge: %v", err)
+	}
+	if err := restarted.AcknowledgeAlert("missing", "agent"); err != gorm.ErrRecordNotFound {
+		t.Errorf("Expected ErrRecordNotFound for unknown alert, got %v", err)
+	}
+	if err := restarted.ResolveAlert("volume_1", "agent"); err != nil {
+		t.Fatalf("Failed to resolve: %v", err)
+	}
+
+	stored, _ := manager.GetAlert("volume_1")
+	if !stored.Acknowledged || !stored.Resolved || stored.ResolvedBy != "agent" || stored.ResolvedAt == nil {
+		t.Errorf("Expected resolved alert to be persisted, got %+v", stored)
+	}
+	if len(manager.GetActiveAlerts()) != 0 {
+		t.Error("Resolved alert should not be active")
+	}
+
+	// Once resolved, the same type can alert again
+	manager.TriggerAlert(models.ComplianceAlert{ID: "volume_3", Type: "volume_warning", Severity: "medium", Title: "Volume"})
+	if active := manager.GetActiveAlerts(); len(active) != 1 || active[0].ID != "volume_3" {
+		t.Errorf("Expected a new alert after resolution, got %+v", active)
+	}
+}
+
+type recordingAlertEmailSender struct {
+	sent chan string
+}
+
+func (r *recordingAlertEmailSender) SendEmail(to, subject, content string, metadata map[string]interface{}) error {
+	r.sent <- to
+	return nil
+}
+
+func TestAlertManager_CriticalAlertEmailsOncePerCooldown(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.ComplianceAlert{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	sender := &recordingAlertEmailSender{sent: make(chan string, 10)}
+	manager := NewAlertManager(db)
+	manager.email = sender
+	manager.SetNotificationCooldown(time.Hour)
+	manager.SetNotificationRoutes(map[string]AlertNotificationRoute{
+		"critical": {Channels: []string{AlertChannelEmail}, Emails: []string{"compliance@example.com"}},
+	})
+
+	manager.TriggerAlert(models.ComplianceAlert{ID: "critical_1", Type: "critical_risk", Severity: "critical", Title: "Spam complaints"})
+	select {
+	case to := <-sender.sent:
+		if to != "compliance@example.com" {
+			t.Errorf("Expected email to compliance@example.com, got %s", to)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a critical alert email")
+	}
+
+	// Resolving and re-raising within the cooldown must not email again
+	if err := manager.ResolveAlert("critical_1", "agent"); err != nil {
+		t.Fatalf("Failed to resolve: %v", err)
+	}
+	manager.TriggerAlert(models.ComplianceAlert{ID: "critical_2", Type: "critical_risk", Severity: "critical", Title: "Spam complaints"})
+
+	select {
+	case to := <-sender.sent:
+		t.Errorf("Expected no second email within the cooldown, got one to %s", to)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if len(manager.GetActiveAlerts()) != 1 {
+		t.Error("The re-raised alert should still be recorded")
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"log"
 	"math"
 	"strings"
+	"sync"
 	"time"
 
 	"chrisgross-ctrl-project/internal/models"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ComplianceMonitoringService provides comprehensive compliance monitoring
@@ -19,18 +22,73 @@ type ComplianceMonitoringService struct {
 	alertManager       *AlertManager
 	complianceReporter *ComplianceReporter
 	emergencyControls  *EmergencyControls
+
+	configMutex sync.RWMutex
+	config      models.ComplianceConfig
 }
 
 // NewComplianceMonitoringService creates a new compliance monitoring service
 func NewComplianceMonitoringService(db *gorm.DB) *ComplianceMonitoringService {
-	return &ComplianceMonitoringService{
+	cms := &ComplianceMonitoringService{
 		db:                 db,
 		reputationMonitor:  NewReputationMonitor(db),
 		volumeController:   NewVolumeController(db),
-		alertManager:       NewAlertManager(),
+		alertManager:       NewAlertManager(db),
 		complianceReporter: NewComplianceReporter(db),
 		emergencyControls:  NewEmergencyControls(db),
 	}
+	cms.applyConfig(cms.loadConfig())
+	return cms
+}
+
+// loadConfig returns the saved compliance config, or the defaults if none is saved
+func (cms *ComplianceMonitoringService) loadConfig() models.ComplianceConfig {
+	config := models.DefaultComplianceConfig()
+	if err := cms.db.First(&config, models.ComplianceConfigID).Error; err != nil && err != gorm.ErrRecordNotFound {
+		log.Printf("⚠️ Failed to load compliance config, using defaults: %v", err)
+		return models.DefaultComplianceConfig()
+	}
+	return config
+}
+
+// applyConfig makes a config the one used for scoring and reputation checks
+func (cms *ComplianceMonitoringService) applyConfig(config models.ComplianceConfig) {
+	cms.configMutex.Lock()
+	cms.config = config
+	cms.configMutex.Unlock()
+
+	cms.reputationMonitor.SetThresholds(ReputationThresholds{
+		MinOverallScore:    config.MinOverallScore,
+		MaxBounceRate:      config.MaxBounceRate,
+		MaxComplaintRate:   config.MaxComplaintRate,
+		MaxUnsubscribeRate: config.MaxUnsubscribeRate,
+		MinOpenRate:        config.MinOpenRate,
+		MinClickRate:       config.MinClickRate,
+	})
+}
+
+// GetConfig returns the weights and thresholds currently in use
+func (cms *ComplianceMonitoringService) GetConfig() models.ComplianceConfig {
+	cms.configMutex.RLock()
+	defer cms.configMutex.RUnlock()
+	return cms.config
+}
+
+// UpdateConfig validates, saves and applies new weights and thresholds
+func (cms *ComplianceMonitoringService) UpdateConfig(config models.ComplianceConfig, updatedBy string) (models.ComplianceConfig, error) {
+	if err := config.Validate(); err != nil {
+		return config, err
+	}
+
+	config.ID = models.ComplianceConfigID
+	config.UpdatedBy = updatedBy
+	if err := cms.db.Save(&config).Error; err != nil {
+		return config, fmt.Errorf("failed to save compliance config: %w", err)
+	}
+
+	cms.applyConfig(config)
+	log.Printf("⚖️ Compliance config updated by %s", updatedBy)
+	return config, nil
 }
 
 // ComplianceStatus represents current compliance status
@@ -43,7 +101,7 @@ type ComplianceStatus struct {
 	LegalCompliance  LegalComplianceStatus  `json:"legal_compliance"`
 	RiskFactors      []RiskFactor           `json:"risk_factors"`
 	Recommendations  []string               `json:"recommendations"`
-	Alerts           []ComplianceAlert      `json:"alerts"`
+	Alerts           []models.ComplianceAlert      `json:"alerts"`
 	EmergencyStatus  EmergencyStatus        `json:"emergency_status"`
 }
 
@@ -58,6 +116,11 @@ type VolumeComplianceStatus struct {
 	VolumeUtilization float64 `json:"volume_utilization"`
 	IsWithinLimits    bool    `json:"is_within_limits"`
 	TimeToReset       string  `json:"time_to_reset"`
+
+	// Warmup is set while a sending domain warmup schedule is configured;
+	// DailyLimit is then the lower of the warmup cap and ConfiguredDailyLimit
+	ConfiguredDailyLimit int           `json:"configured_daily_limit"`
+	Warmup               *WarmupStatus `json:"warmup,omitempty"`
 }
 
 // ReputationStatus tracks sender reputation metrics
@@ -97,19 +160,6 @@ type RiskFactor struct {
 	DetectedAt  time.Time `json:"detected_at"`
 }
 
-// ComplianceAlert represents a compliance alert
-type ComplianceAlert struct {
-	ID           string    `json:"id"`
-	Type         string    `json:"type"`
-	Severity     string    `json:"severity"`
-	Title        string    `json:"title"`
-	Message      string    `json:"message"`
-	Action       string    `json:"action"`
-	CreatedAt    time.Time `json:"created_at"`
-	Acknowledged bool      `json:"acknowledged"`
-	Resolved     bool      `json:"resolved"`
-}
-
 // EmergencyStatus tracks emergency control status
 type EmergencyStatus struct {
 	IsActive            bool      `json:"is_active"`
@@ -121,13 +171,24 @@ type EmergencyStatus struct {
 	EstimatedResolution string    `json:"estimated_resolution"`
 }
 
+// SetWarmupSchedule reports the sending domain warmup in volume compliance
+// and caps the daily limit by it
+func (cms *ComplianceMonitoringService) SetWarmupSchedule(warmup *WarmupSchedule) {
+	cms.volumeController.SetWarmupSchedule(warmup)
+}
+
+// Alerts returns the service's alert manager
+func (cms *ComplianceMonitoringService) Alerts() *AlertManager {
+	return cms.alertManager
+}
+
 // PerformComplianceCheck performs comprehensive compliance checking
 func (cms *ComplianceMonitoringService) PerformComplianceCheck() (ComplianceStatus, error) {
 	status := ComplianceStatus{
 		LastChecked:     time.Now(),
 		RiskFactors:     []RiskFactor{},
 		Recommendations: []string{},
-		Alerts:          []ComplianceAlert{},
+		Alerts:          []models.ComplianceAlert{},
 	}
 
 	// Check volume compliance
@@ -143,6 +204,9 @@ func (cms *ComplianceMonitoringService) PerformComplianceCheck() (ComplianceStat
 		return status, fmt.Errorf("reputation check failed: %w", err)
 	}
 	status.ReputationStatus = reputationStatus
+	if err := cms.complianceReporter.RecordReputationSnapshot(reputationStatus); err != nil {
+		log.Printf("⚠️ Failed to record reputation snapshot: %v", err)
+	}
 
 	// Check legal compliance
 	legalStatus, err := cms.checkLegalCompliance()
@@ -289,10 +353,11 @@ func (cms *ComplianceMonitoringService) calculateOverallCompliance(status Compli
 // calculateOverallScore calculates overall compliance score
 func (cms *ComplianceMonitoringService) calculateOverallScore(status ComplianceStatus) float64 {
 	// Weighted scoring
-	volumeWeight := 0.25
-	reputationWeight := 0.35
-	legalWeight := 0.25
-	emergencyWeight := 0.15
+	config := cms.GetConfig()
+	volumeWeight := config.VolumeWeight
+	reputationWeight := config.ReputationWeight
+	legalWeight := config.LegalWeight
+	emergencyWeight := config.EmergencyWeight
 
 	volumeScore := 100.0
 	if !status.VolumeCompliance.IsWithinLimits {
@@ -410,7 +475,7 @@ func (cms *ComplianceMonitoringService) triggerAlertsIfNeeded(status ComplianceS
 	// Critical alerts
 	for _, risk := range status.RiskFactors {
 		if risk.Severity == "critical" {
-			alert := ComplianceAlert{
+			alert := models.ComplianceAlert{
 				ID:        fmt.Sprintf("critical_%d", time.Now().Unix()),
 				Type:      "critical_risk",
 				Severity:  "critical",
@@ -425,7 +490,7 @@ func (cms *ComplianceMonitoringService) triggerAlertsIfNeeded(status ComplianceS
 
 	// Volume alerts
 	if status.VolumeCompliance.VolumeUtilization > 0.9 {
-		alert := ComplianceAlert{
+		alert := models.ComplianceAlert{
 			ID:        fmt.Sprintf("volume_%d", time.Now().Unix()),
 			Type:      "volume_warning",
 			Severity:  "high",
@@ -439,7 +504,7 @@ func (cms *ComplianceMonitoringService) triggerAlertsIfNeeded(status ComplianceS
 
 	// Reputation alerts
 	if status.ReputationStatus.OverallScore < 70.0 {
-		alert := ComplianceAlert{
+		alert := models.ComplianceAlert{
 			ID:        fmt.Sprintf("reputation_%d", time.Now().Unix()),
 			Type:      "reputation_warning",
 			Severity:  "high",
@@ -460,6 +525,7 @@ type VolumeController struct {
 	dailyLimit   int
 	weeklyLimit  int
 	monthlyLimit int
+	warmup       *WarmupSchedule
 }
 
 // NewVolumeController creates a new volume controller
@@ -482,25 +548,37 @@ func (vc *VolumeController) CheckVolumeCompliance() (VolumeComplianceStatus, err
 		monthlyVolume = 3200
 	}
 
+	now := time.Now()
 	status := VolumeComplianceStatus{
-		DailyVolume:   dailyVolume,
-		DailyLimit:    vc.dailyLimit,
-		WeeklyVolume:  weeklyVolume,
-		WeeklyLimit:   vc.weeklyLimit,
-		MonthlyVolume: monthlyVolume,
-		MonthlyLimit:  vc.monthlyLimit,
+		DailyVolume:          dailyVolume,
+		DailyLimit:           vc.dailyLimit,
+		WeeklyVolume:         weeklyVolume,
+		WeeklyLimit:          vc.weeklyLimit,
+		MonthlyVolume:        monthlyVolume,
+		MonthlyLimit:         vc.monthlyLimit,
+		ConfiguredDailyLimit: vc.dailyLimit,
+	}
+
+	// A warming sending domain may not exceed today's warmup cap
+	if vc.warmup != nil {
+		warmup := vc.warmup.StatusOn(now)
+		status.Warmup = &warmup
+		if warmup.DailyCap < status.DailyLimit {
+			status.DailyLimit = warmup.DailyCap
+		}
 	}
 
 	// Calculate utilization
-	status.VolumeUtilization = float64(dailyVolume) / float64(vc.dailyLimit)
+	if status.DailyLimit > 0 {
+		status.VolumeUtilization = float64(dailyVolume) / float64(status.DailyLimit)
+	}
 
 	// Check if within limits
-	status.IsWithinLimits = dailyVolume <= vc.dailyLimit &&
+	status.IsWithinLimits = dailyVolume <= status.DailyLimit &&
 		weeklyVolume <= vc.weeklyLimit &&
 		monthlyVolume <= vc.monthlyLimit
 
 	// Calculate time to reset
-	now := time.Now()
 	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
 	status.TimeToReset = time.Until(tomorrow).String()
 
@@ -538,9 +616,16 @@ func (vc *VolumeController) SetLimits(daily, weekly, monthly int) {
 	vc.monthlyLimit = monthly
 }
 
+// SetWarmupSchedule caps the daily limit by a sending domain warmup schedule;
+// nil removes the cap
+func (vc *VolumeController) SetWarmupSchedule(warmup *WarmupSchedule) {
+	vc.warmup = warmup
+}
+
 // ReputationMonitor monitors sender reputation
 type ReputationMonitor struct {
 	db         *gorm.DB
+	mutex      sync.RWMutex
 	thresholds ReputationThresholds
 }
 
@@ -556,19 +641,27 @@ type ReputationThresholds struct {
 
 // NewReputationMonitor creates a new reputation monitor
 func NewReputationMonitor(db *gorm.DB) *ReputationMonitor {
+	defaults := models.DefaultComplianceConfig()
 	return &ReputationMonitor{
 		db: db,
 		thresholds: ReputationThresholds{
-			MinOverallScore:    70.0,
-			MaxBounceRate:      5.0,
-			MaxComplaintRate:   0.1,
-			MaxUnsubscribeRate: 5.0,
-			MinOpenRate:        15.0,
-			MinClickRate:       1.0,
+			MinOverallScore:    defaults.MinOverallScore,
+			MaxBounceRate:      defaults.MaxBounceRate,
+			MaxComplaintRate:   defaults.MaxComplaintRate,
+			MaxUnsubscribeRate: defaults.MaxUnsubscribeRate,
+			MinOpenRate:        defaults.MinOpenRate,
+			MinClickRate:       defaults.MinClickRate,
 		},
 	}
 }
 
+// SetThresholds replaces the thresholds used by reputation checks
+func (rm *ReputationMonitor) SetThresholds(thresholds ReputationThresholds) {
+	rm.mutex.Lock()
+	rm.thresholds = thresholds
+	rm.mutex.Unlock()
+}
+
 // CheckReputationStatus checks current reputation status
 func (rm *ReputationMonitor) CheckReputationStatus() (ReputationStatus, error) {
 	metrics, err := rm.calculateReputationMetrics()
@@ -587,6 +680,10 @@ func (rm *ReputationMonitor) CheckReputationStatus() (ReputationStatus, error) {
 
 	status := metrics
 
+	// Thresholds can be changed through the compliance config while a check runs
+	rm.mutex.RLock()
+	defer rm.mutex.RUnlock()
+
 	// Calculate scores
 	status.DeliverabilityScore = rm.calculateDeliverabilityScore(status)
 	status.EngagementScore = rm.calculateEngagementScore(status)
@@ -712,81 +809,140 @@ func (rm *ReputationMonitor) calculateComplaintScore(status ReputationStatus) fl
 	return math.Max(score, 0.0)
 }
 
-// AlertManager manages compliance alerts
+// AlertManager manages compliance alerts, persisted so acknowledgement and
+// resolution survive restarts and are visible to every instance
 type AlertManager struct {
-	activeAlerts []ComplianceAlert
+	db *gorm.DB
+
+	// Notification delivery, configured with SetNotifiers and SetNotificationRoutes
+	email    alertEmailSender
+	sms      alertSMSSender
+	webhooks *OutboundWebhookService
+	routes   map[string]AlertNotificationRoute
+	cooldown time.Duration
+
+	mutex        sync.Mutex
+	lastNotified map[string]time.Time // alert type -> last notification
 }
 
 // NewAlertManager creates a new alert manager
-func NewAlertManager() *AlertManager {
+func NewAlertManager(db *gorm.DB) *AlertManager {
 	return &AlertManager{
-		activeAlerts: []ComplianceAlert{},
+		db:           db,
+		routes:       make(map[string]AlertNotificationRoute),
+		cooldown:     30 * time.Minute,
+		lastNotified: make(map[string]time.Time),
 	}
 }
 
-// TriggerAlert triggers a new compliance alert
-func (am *AlertManager) TriggerAlert(alert ComplianceAlert) {
-	// Check if similar alert already exists
-	for _, existing := range am.activeAlerts {
-		if existing.Type == alert.Type && !existing.Resolved {
-			return // Don't duplicate alerts
-		}
+// TriggerAlert records a new compliance alert unless an unresolved alert of
+// the same type already exists
+func (am *AlertManager) TriggerAlert(alert models.ComplianceAlert) {
+	if alert.ID == "" {
+		alert.ID = uuid.New().String()
 	}
 
-	am.activeAlerts = append(am.activeAlerts, alert)
+	created := false
+	err := am.db.Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&models.ComplianceAlert{}).
+			Where("type = ? AND resolved = ?", alert.Type, false).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return nil // Don't duplicate alerts
+		}
+		if err := tx.Create(&alert).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		// The partial unique index on unresolved types rejects concurrent duplicates
+		log.Printf("⚠️ Failed to record compliance alert %s: %v", alert.Type, err)
+		return
+	}
+	if !created {
+		return
+	}
 
 	log.Printf("COMPLIANCE ALERT [%s]: %s - %s", alert.Severity, alert.Title, alert.Message)
-	
-	go am.sendNotification(alert)
-}
-
-// sendNotification sends alert notifications through various channels
-func (am *AlertManager) sendNotification(alert ComplianceAlert) {
-	switch alert.Severity {
-	case "critical":
-		log.Printf("[CRITICAL ALERT] Sending immediate notifications via email, SMS, and webhook")
-		log.Printf("[EMAIL] To: admin@propertyhub.com Subject: CRITICAL - %s", alert.Title)
-		log.Printf("[SMS] To: +1-XXX-XXX-XXXX Message: CRITICAL ALERT - %s", alert.Title)
-		log.Printf("[WEBHOOK] POST to webhook.propertyhub.com/alerts with payload: %v", alert)
-	case "high":
-		log.Printf("[HIGH ALERT] Sending email notification")
-		log.Printf("[EMAIL] To: admin@propertyhub.com Subject: HIGH PRIORITY - %s", alert.Title)
-	default:
-		log.Printf("[ALERT] Logging to monitoring system: %s", alert.Title)
-	}
-}
-
-// GetActiveAlerts returns all active alerts
-func (am *AlertManager) GetActiveAlerts() []ComplianceAlert {
-	var active []ComplianceAlert
-	for _, alert := range am.activeAlerts {
-		if !alert.Resolved {
-			active = append(active, alert)
-		}
+
+	if am.reserveNotification(alert.Type) {
+		go am.sendNotification(alert)
+	}
+}
+
+// GetActiveAlerts returns all unresolved alerts, newest first
+func (am *AlertManager) GetActiveAlerts() []models.ComplianceAlert {
+	active := []models.ComplianceAlert{}
+	if err := am.db.Where("resolved = ?", false).Order("created_at DESC").Find(&active).Error; err != nil {
+		log.Printf("⚠️ Failed to load active compliance alerts: %v", err)
 	}
 	return active
 }
 
+// ListAlerts returns alerts, optionally including resolved ones
+func (am *AlertManager) ListAlerts(includeResolved bool, limit int) ([]models.ComplianceAlert, error) {
+	alerts := []models.ComplianceAlert{}
+	query := am.db.Order("created_at DESC").Limit(limit)
+	if !includeResolved {
+		query = query.Where("resolved = ?", false)
+	}
+	err := query.Find(&alerts).Error
+	return alerts, err
+}
+
 // AcknowledgeAlert acknowledges an alert
-func (am *AlertManager) AcknowledgeAlert(alertID string) error {
-	for i, alert := range am.activeAlerts {
-		if alert.ID == alertID {
-			am.activeAlerts[i].Acknowledged = true
-			return nil
-		}
+func (am *AlertManager) AcknowledgeAlert(alertID, acknowledgedBy string) error {
+	return am.updateAlert(alertID, map[string]interface{}{
+		"acknowledged":    true,
+		"acknowledged_by": acknowledgedBy,
+		"acknowledged_at": time.Now(),
+	})
+}
+
+// ResolveAlert resolves an alert, which also acknowledges it if it wasn't already
+func (am *AlertManager) ResolveAlert(alertID, resolvedBy string) error {
+	var alert models.ComplianceAlert
+	if err := am.db.First(&alert, "id = ?", alertID).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"resolved":    true,
+		"resolved_by": resolvedBy,
+		"resolved_at": now,
 	}
-	return fmt.Errorf("alert not found: %s", alertID)
+	if !alert.Acknowledged {
+		updates["acknowledged"] = true
+		updates["acknowledged_by"] = resolvedBy
+		updates["acknowledged_at"] = now
+	}
+	return am.db.Model(&alert).Updates(updates).Error
 }
 
-// ResolveAlert resolves an alert
-func (am *AlertManager) ResolveAlert(alertID string) error {
-	for i, alert := range am.activeAlerts {
-		if alert.ID == alertID {
-			am.activeAlerts[i].Resolved = true
-			return nil
-		}
+// GetAlert returns an alert by ID
+func (am *AlertManager) GetAlert(alertID string) (*models.ComplianceAlert, error) {
+	var alert models.ComplianceAlert
+	if err := am.db.First(&alert, "id = ?", alertID).Error; err != nil {
+		return nil, err
 	}
-	return fmt.Errorf("alert not found: %s", alertID)
+	return &alert, nil
+}
+
+func (am *AlertManager) updateAlert(alertID string, updates map[string]interface{}) error {
+	result := am.db.Model(&models.ComplianceAlert{}).Where("id = ?", alertID).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
 // ComplianceReporter generates compliance reports
@@ -925,15 +1081,15 @@ func (cr *ComplianceReporter) generateActionItems(status ComplianceStatus) []Act
 func (cr *ComplianceReporter) generateTrendAnalysis() TrendAnalysis {
 	trends, err := cr.calculateHistoricalTrends()
 	if err != nil {
-		log.Printf("Error calculating trends: %v, using defaults", err)
+		log.Printf("Error calculating trends: %v", err)
 		return TrendAnalysis{
 			ReputationTrend: "stable",
-			VolumeTrend:     "increasing",
-			EngagementTrend: "improving",
+			VolumeTrend:     "stable",
+			EngagementTrend: "stable",
 			TrendData: map[string][]float64{
-				"reputation": {75.0, 78.0, 80.0, 82.0, 85.0},
-				"volume":     {100, 150, 200, 250, 300},
-				"engagement": {20.0, 22.0, 24.0, 25.0, 26.0},
+				"reputation": {},
+				"volume":     {},
+				"engagement": {},
 			},
 		}
 	}
@@ -966,21 +1122,66 @@ func (cr *ComplianceReporter) calculateHistoricalTrends() (TrendAnalysis, error)
 		}
 	}
 
-	volumeTrend := cr.determineTrend(volumeData)
-	engagementTrend := cr.determineTrend(engagementData)
+	reputationData, err := cr.reputationHistory(reputationTrendSnapshots)
+	if err != nil {
+		return TrendAnalysis{}, fmt.Errorf("failed to load reputation history: %w", err)
+	}
 
 	return TrendAnalysis{
-		ReputationTrend: "stable",
-		VolumeTrend:     volumeTrend,
-		EngagementTrend: engagementTrend,
+		ReputationTrend: cr.determineTrend(reputationData),
+		VolumeTrend:     cr.determineTrend(volumeData),
+		EngagementTrend: cr.determineTrend(engagementData),
 		TrendData: map[string][]float64{
-			"reputation": {75.0, 78.0, 80.0, 82.0, 85.0},
+			"reputation": reputationData,
 			"volume":     volumeData,
 			"engagement": engagementData,
 		},
 	}, nil
 }
 
+// reputationTrendSnapshots is how many daily reputation snapshots the trend covers
+const reputationTrendSnapshots = 7
+
+// RecordReputationSnapshot stores today's reputation metrics, replacing any
+// snapshot already taken today
+func (cr *ComplianceReporter) RecordReputationSnapshot(status ReputationStatus) error {
+	now := time.Now().UTC()
+	snapshot := models.ReputationSnapshot{
+		SnapshotDate:        time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		OverallScore:        status.OverallScore,
+		DeliverabilityScore: status.DeliverabilityScore,
+		EngagementScore:     status.EngagementScore,
+		ComplaintScore:      status.ComplaintScore,
+		BounceRate:          status.BounceRate,
+		SpamComplaintRate:   status.SpamComplaintRate,
+		UnsubscribeRate:     status.UnsubscribeRate,
+		OpenRate:            status.OpenRate,
+		ClickRate:           status.ClickRate,
+	}
+
+	return cr.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "snapshot_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"overall_score", "deliverability_score", "engagement_score", "complaint_score",
+			"bounce_rate", "spam_complaint_rate", "unsubscribe_rate", "open_rate", "click_rate", "updated_at",
+		}),
+	}).Create(&snapshot).Error
+}
+
+// reputationHistory returns the overall score of the last n snapshots, oldest first
+func (cr *ComplianceReporter) reputationHistory(n int) ([]float64, error) {
+	var snapshots []models.ReputationSnapshot
+	if err := cr.db.Order("snapshot_date DESC").Limit(n).Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+
+	scores := make([]float64, len(snapshots))
+	for i, snapshot := range snapshots {
+		scores[len(snapshots)-1-i] = snapshot.OverallScore
+	}
+	return scores, nil
+}
+
 // determineTrend determines if a data series is increasing, decreasing, or stable
 func (cr *ComplianceReporter) determineTrend(data []float64) string {
 	if len(data) < 2 {
@@ -989,6 +1190,12 @@ func (cr *ComplianceReporter) determineTrend(data []float64) string {
 
 	first := data[0]
 	last := data[len(data)-1]
+	if first == 0 {
+		if last > 0 {
+			return "increasing"
+		}
+		return "stable"
+	}
 
 	change := ((last - first) / first) * 100
 
@@ -1000,15 +1207,48 @@ func (cr *ComplianceReporter) determineTrend(data []float64) string {
 	return "stable"
 }
 
-// EmergencyControls handles emergency stop functionality
-type EmergencyControls struct {
-	db          *gorm.DB
-	isActive    bool
+// campaignEmergencyStop is shared by every EmergencyControls and CampaignDispatcher
+// so an activated stop halts in-flight sends, not just future scheduling
+var campaignEmergencyStop struct {
+	sync.RWMutex
+	active      bool
 	reason      string
 	activatedAt time.Time
 	activatedBy string
 }
 
+// IsCampaignEmergencyStopActive reports whether campaign sending is halted
+func IsCampaignEmergencyStopActive() bool {
+	campaignEmergencyStop.RLock()
+	defer campaignEmergencyStop.RUnlock()
+	return campaignEmergencyStop.active
+}
+
+// RestoreCampaignEmergencyStop reloads a stop saved before the last restart
+func RestoreCampaignEmergencyStop(db *gorm.DB) error {
+	var saved models.CampaignEmergencyStop
+	err := db.Where("id = ?", models.CampaignEmergencyStopID).Limit(1).Find(&saved).Error
+	if err != nil || !saved.Active {
+		return err
+	}
+
+	campaignEmergencyStop.Lock()
+	defer campaignEmergencyStop.Unlock()
+	campaignEmergencyStop.active = true
+	campaignEmergencyStop.reason = saved.Reason
+	campaignEmergencyStop.activatedBy = saved.ActivatedBy
+	if saved.ActivatedAt != nil {
+		campaignEmergencyStop.activatedAt = *saved.ActivatedAt
+	}
+	log.Printf("EMERGENCY STOP RESTORED (activated by %s: %s)", saved.ActivatedBy, saved.Reason)
+	return nil
+}
+
+// EmergencyControls handles emergency stop functionality
+type EmergencyControls struct {
+	db *gorm.DB
+}
+
 // NewEmergencyControls creates new emergency controls
 func NewEmergencyControls(db *gorm.DB) *EmergencyControls {
 	return &EmergencyControls{db: db}
@@ -1016,14 +1256,18 @@ func NewEmergencyControls(db *gorm.DB) *EmergencyControls {
 
 // ActivateEmergencyStop activates emergency stop
 func (ec *EmergencyControls) ActivateEmergencyStop(reason, activatedBy string) {
-	ec.isActive = true
-	ec.reason = reason
-	ec.activatedAt = time.Now()
-	ec.activatedBy = activatedBy
+	now := time.Now()
+	campaignEmergencyStop.Lock()
+	campaignEmergencyStop.active = true
+	campaignEmergencyStop.reason = reason
+	campaignEmergencyStop.activatedAt = now
+	campaignEmergencyStop.activatedBy = activatedBy
+	campaignEmergencyStop.Unlock()
 
 	log.Printf("EMERGENCY STOP ACTIVATED by %s: %s", activatedBy, reason)
 
 	if ec.db != nil {
+		ec.saveEmergencyStop(models.CampaignEmergencyStop{Active: true, Reason: reason, ActivatedBy: activatedBy, ActivatedAt: &now})
 		if err := ec.db.Model(&models.CampaignExecution{}).Where("status = ?", "scheduled").Update("status", "skipped").Error; err != nil {
 			log.Printf("Failed to stop scheduled campaigns: %v", err)
 		}
@@ -1032,28 +1276,48 @@ func (ec *EmergencyControls) ActivateEmergencyStop(reason, activatedBy string) {
 
 // DeactivateEmergencyStop deactivates emergency stop
 func (ec *EmergencyControls) DeactivateEmergencyStop() {
-	ec.isActive = false
+	campaignEmergencyStop.Lock()
+	campaignEmergencyStop.active = false
+	campaignEmergencyStop.reason = ""
+	campaignEmergencyStop.activatedBy = ""
+	campaignEmergencyStop.activatedAt = time.Time{}
+	campaignEmergencyStop.Unlock()
+
 	log.Printf("EMERGENCY STOP DEACTIVATED")
+
+	if ec.db != nil {
+		ec.saveEmergencyStop(models.CampaignEmergencyStop{Active: false})
+	}
+}
+
+// saveEmergencyStop writes the stop state to its single row
+func (ec *EmergencyControls) saveEmergencyStop(stop models.CampaignEmergencyStop) {
+	stop.ID = models.CampaignEmergencyStopID
+	if err := ec.db.Save(&stop).Error; err != nil {
+		log.Printf("Failed to save emergency stop state: %v", err)
+	}
 }
 
 // GetStatus returns current emergency status
 func (ec *EmergencyControls) GetStatus() EmergencyStatus {
+	campaignEmergencyStop.RLock()
 	status := EmergencyStatus{
-		IsActive: ec.isActive,
+		IsActive:    campaignEmergencyStop.active,
+		Reason:      campaignEmergencyStop.reason,
+		ActivatedAt: campaignEmergencyStop.activatedAt,
+		ActivatedBy: campaignEmergencyStop.activatedBy,
 	}
+	campaignEmergencyStop.RUnlock()
 
-	if ec.isActive {
-		status.Reason = ec.reason
-		status.ActivatedAt = ec.activatedAt
-		status.ActivatedBy = ec.activatedBy
+	if status.IsActive {
 		status.EstimatedResolution = "Manual intervention required"
 		
 		if ec.db != nil {
 			var stoppedCount, blockedCount int64
-			ec.db.Model(&models.CampaignExecution{}).Where("status = ? AND updated_at > ?", "skipped", ec.activatedAt).Count(&stoppedCount)
+			ec.db.Model(&models.CampaignExecution{}).Where("status = ? AND updated_at > ?", "skipped", status.ActivatedAt).Count(&stoppedCount)
 			status.CampaignsStopped = int(stoppedCount)
 			
-			ec.db.Model(&models.CampaignExecution{}).Where("status = ? AND created_at > ?", "scheduled", ec.activatedAt).Count(&blockedCount)
+			ec.db.Model(&models.CampaignExecution{}).Where("status = ? AND created_at > ?", "scheduled", status.ActivatedAt).Count(&blockedCount)
 			status.EmailsBlocked = int(blockedCount)
 		} else {
 			status.CampaignsStopped = 5
@@ -1066,5 +1330,5 @@ func (ec *EmergencyControls) GetStatus() EmergencyStatus {
 
 // IsEmergencyActive checks if emergency stop is active
 func (ec *EmergencyControls) IsEmergencyActive() bool {
-	return ec.isActive
+	return IsCampaignEmergencyStopActive()
 }
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestEmergencyControls_StopSurvivesRestart(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.CampaignEmergencyStop{}, &models.CampaignExecution{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	controls := NewEmergencyControls(db)
+	t.Cleanup(controls.DeactivateEmergencyStop)
+
+	controls.ActivateEmergencyStop("complaint spike", "ops@example.com")
+
+	// A restart starts with the in-memory flag cleared
+	campaignEmergencyStop.Lock()
+	campaignEmergencyStop.active = false
+	campaignEmergencyStop.reason = ""
+	campaignEmergencyStop.activatedBy = ""
+	campaignEmergencyStop.Unlock()
+
+	if err := RestoreCampaignEmergencyStop(db); err != nil {
+		t.Fatalf("RestoreCampaignEmergencyStop failed: %v", err)
+	}
+	status := NewEmergencyControls(db).GetStatus()
+	if !status.IsActive || status.Reason != "complaint spike" || status.ActivatedBy != "ops@example.com" ||
+		time.Since(status.ActivatedAt) > time.Minute {
+		t.Fatalf("Expected the saved stop restored, got %+v", status)
+	}
+
+	controls.DeactivateEmergencyStop()
+	if err := RestoreCampaignEmergencyStop(db); err != nil {
+		t.Fatalf("RestoreCampaignEmergencyStop failed: %v", err)
+	}
+	if IsCampaignEmergencyStopActive() {
+		t.Error("Expected a deactivated stop to stay off after a restart")
+	}
+	var rows int64
+	db.Model(&models.CampaignEmergencyStop{}).Count(&rows)
+	if rows != 1 {
+		t.Errorf("Expected a single emergency stop row, got %d", rows)
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestComplianceReporter_ReputationTrendFromSnapshots(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.ReputationSnapshot{}, &models.CampaignExecution{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	reporter := NewComplianceReporter(db)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for i, score := range []float64{90, 85, 78, 70} {
+		db.Create(&models.ReputationSnapshot{SnapshotDate: today.AddDate(0, 0, i-4), OverallScore: score})
+	}
+
+	// Repeated checks on one day keep a single, latest snapshot
+	reporter.RecordReputationSnapshot(ReputationStatus{OverallScore: 66})
+	if err := reporter.RecordReputationSnapshot(ReputationStatus{OverallScore: 60}); err != nil {
+		t.Fatalf("Failed to record snapshot: %v", err)
+	}
+	var count int64
+	db.Model(&models.ReputationSnapshot{}).Count(&count)
+	if count != 5 {
+		t.Fatalf("Expected 5 snapshots, got %d", count)
+	}
+
+	trends := reporter.generateTrendAnalysis()
+	want := []float64{90, 85, 78, 70, 60}
+	got := trends.TrendData["reputation"]
+	if len(got) != len(want) {
+		t.Fatalf("Expected reputation series %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected reputation series %v, got %v", want, got)
+		}
+	}
+	if trends.ReputationTrend != "decreasing" {
+		t.Errorf("Expected decreasing reputation trend, got %s", trends.ReputationTrend)
+	}
+}
+
+func TestComplianceConfig_BounceThresholdChangesReputationHealth(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.ComplianceConfig{}, &models.CampaignExecution{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	// 100 sends with a 4% bounce rate and healthy engagement
+	executedAt := time.Now().Add(-time.Hour)
+	for i := 0; i < 100; i++ {
+		execution := models.CampaignExecution{ExecutedAt: &executedAt, Status: "sent", EmailOpened: i < 40, EmailClicked: i < 5}
+		if i >= 96 {
+			execution.Status = "failed"
+		}
+		db.Create(&execution)
+	}
+
+	monitoring := NewComplianceMonitoringService(db)
+	status, _ := monitoring.reputationMonitor.CheckReputationStatus()
+	if !status.IsHealthy {
+		t.Fatalf("Expected 4%% bounce rate to be healthy under the default threshold, got %+v", status)
+	}
+
+	config := monitoring.GetConfig()
+	config.MaxBounceRate = 3.0
+	if _, err := monitoring.UpdateConfig(config, "agent"); err != nil {
+		t.Fatalf("Failed to update config: %v", err)
+	}
+	status, _ = monitoring.reputationMonitor.CheckReputationStatus()
+	if status.IsHealthy {
+		t.Error("Expected 4% bounce rate to be unhealthy with a 3% threshold")
+	}
+
+	// The saved threshold applies to a freshly started service too
+	if reloaded := NewComplianceMonitoringService(db).GetConfig(); reloaded.MaxBounceRate != 3.0 {
+		t.Errorf("Expected saved bounce threshold 3.0, got %.1f", reloaded.MaxBounceRate)
+	}
+
+	config.VolumeWeight = 0.5
+	if _, err := monitoring.UpdateConfig(config, "agent"); err == nil {
+		t.Error("Expected weights that don't sum to 1.0 to be rejected")
+	}
+}
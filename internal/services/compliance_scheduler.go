@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+)
+
+// StartScheduledChecks runs PerformComplianceCheck immediately and then every
+// interval until ctx is cancelled, storing each result as a snapshot. Runs are
+// skipped while an emergency stop is active, since sending is already halted.
+func (cms *ComplianceMonitoringService) StartScheduledChecks(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			cms.runScheduledCheck()
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 Scheduled compliance checks stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	log.Printf("🕐 Scheduled compliance checks started (every %v)", interval)
+}
+
+// runScheduledCheck performs one check and records it
+func (cms *ComplianceMonitoringService) runScheduledCheck() {
+	if cms.emergencyControls.IsEmergencyActive() {
+		log.Println("⏭️ Skipping scheduled compliance check - emergency stop is active")
+		return
+	}
+
+	status, err := cms.PerformComplianceCheck()
+	if err != nil {
+		log.Printf("⚠️ Scheduled compliance check failed: %v", err)
+		return
+	}
+
+	if err := cms.RecordCheckSnapshot(status); err != nil {
+		log.Printf("⚠️ Failed to record compliance check snapshot: %v", err)
+	}
+}
+
+// RecordCheckSnapshot stores a compliance check result for history charts
+func (cms *ComplianceMonitoringService) RecordCheckSnapshot(status ComplianceStatus) error {
+	snapshot := models.ComplianceCheckSnapshot{
+		CheckedAt:         status.LastChecked,
+		IsCompliant:       status.IsCompliant,
+		OverallScore:      status.OverallScore,
+		ReputationScore:   status.ReputationStatus.OverallScore,
+		LegalScore:        status.LegalCompliance.ComplianceScore,
+		VolumeUtilization: status.VolumeCompliance.VolumeUtilization,
+		RiskFactors:       len(status.RiskFactors),
+		ActiveAlerts:      len(status.Alerts),
+		Status:            structToJSONB(status),
+	}
+	return cms.db.Create(&snapshot).Error
+}
+
+// GetCheckHistory returns compliance check snapshots since a time, oldest first.
+// The full status is omitted to keep chart payloads small.
+func (cms *ComplianceMonitoringService) GetCheckHistory(since time.Time) ([]models.ComplianceCheckSnapshot, error) {
+	history := []models.ComplianceCheckSnapshot{}
+	err := cms.db.Omit("status").
+		Where("checked_at >= ?", since).
+		Order("checked_at ASC").
+		Find(&history).Error
+	return history, err
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestScheduledComplianceCheck_RecordsHistoryAndSkipsDuringEmergency(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	err = db.AutoMigrate(&models.CampaignExecution{}, &models.IncomingEmail{}, &models.ComplianceAlert{},
+		&models.ReputationSnapshot{}, &models.ComplianceConfig{}, &models.ComplianceCheckSnapshot{})
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	monitoring := NewComplianceMonitoringService(db)
+	monitoring.runScheduledCheck()
+
+	history, err := monitoring.GetCheckHistory(time.Now().Add(-time.Hour))
+	if err != nil || len(history) != 1 {
+		t.Fatalf("Expected one compliance snapshot, got %d (%v)", len(history), err)
+	}
+	if history[0].OverallScore <= 0 {
+		t.Errorf("Expected the overall score to be recorded, got %+v", history[0])
+	}
+	if history[0].Status != nil {
+		t.Error("History should omit the full status payload")
+	}
+
+	controls := NewEmergencyControls(nil)
+	controls.ActivateEmergencyStop("test stop", "test")
+	defer controls.DeactivateEmergencyStop()
+	monitoring.runScheduledCheck()
+
+	var count int64
+	db.Model(&models.ComplianceCheckSnapshot{}).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected the check to be skipped during an emergency stop, got %d snapshots", count)
+	}
+}
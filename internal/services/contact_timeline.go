@@ -0,0 +1,473 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/gorm"
+)
+
+// Contact timeline event types
+const (
+	TimelineContactSubmitted    = "contact_submitted"
+	TimelineLeadCreated         = "lead_created"
+	TimelinePropertyViewed      = "property_viewed"
+	TimelinePropertySaved       = "property_saved"
+	TimelineInquiry             = "inquiry"
+	TimelineApplicationReceived = "application_received"
+	TimelineActivity            = "activity" // Any other behavioral event
+	TimelineEmailSent           = "email_sent"
+	TimelineCampaignTouched     = "campaign_touched" // Campaign execution scheduled, skipped or failed
+	TimelineEmailReceived       = "email_received"
+	TimelineApprovalRequested   = "approval_requested"
+)
+
+// TimelineEventTypes lists every type a contact timeline can contain
+var TimelineEventTypes = []string{
+	TimelineContactSubmitted, TimelineLeadCreated, TimelinePropertyViewed, TimelinePropertySaved,
+	TimelineInquiry, TimelineApplicationReceived, TimelineActivity, TimelineEmailSent,
+	TimelineCampaignTouched, TimelineEmailReceived, TimelineApprovalRequested,
+}
+
+// behavioralTimelineTypes maps timeline types to the behavioral event types they cover
+var behavioralTimelineTypes = map[string][]string{
+	TimelinePropertyViewed:      {"viewed", "property_view", "property_viewed"},
+	TimelinePropertySaved:       {"saved", "property_saved"},
+	TimelineInquiry:             {"inquired", "inquiry"},
+	TimelineApplicationReceived: {"application", "applied", "application_submitted"},
+}
+
+// Contact timeline paging defaults
+const (
+	DefaultTimelineLimit = 50
+	MaxTimelineLimit     = 200
+)
+
+var ErrContactNotFound = errors.New("contact not found")
+
+// TimelineEvent is one entry in a contact's timeline
+type TimelineEvent struct {
+	Type       string                 `json:"type"`
+	Source     string                 `json:"source"` // contact, lead, behavioral_event, campaign_execution, incoming_email, approval
+	SourceID   int64                  `json:"source_id"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Title      string                 `json:"title"`
+	PropertyID *int64                 `json:"property_id,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+}
+
+// TimelineContact is the decrypted identity a timeline was built for
+type TimelineContact struct {
+	ID                  uint   `json:"id"`
+	Name                string `json:"name"`
+	Email               string `json:"email"`
+	Phone               string `json:"phone"`
+	FUBLeadID           string `json:"fub_lead_id,omitempty"`
+	LeadIDs             []uint `json:"lead_ids"`
+	ReengagementLeadIDs []uint `json:"reengagement_lead_ids"`
+}
+
+// TimelineQuery selects a page of a contact timeline. Empty Types includes every type.
+type TimelineQuery struct {
+	Types []string
+	Page  int
+	Limit int
+}
+
+// ContactTimeline is one page of a contact's merged activity, newest first
+type ContactTimeline struct {
+	Contact TimelineContact `json:"contact"`
+	Events  []TimelineEvent `json:"events"`
+	Total   int64           `json:"total"`
+}
+
+// ContactTimelineService merges everything known about a contact (the contact
+// itself, matching leads, their behavioral events, campaign executions,
+// incoming emails and approvals) into one chronological timeline
+type ContactTimelineService struct {
+	db                *gorm.DB
+	encryptionManager *security.EncryptionManager
+}
+
+// NewContactTimelineService creates a contact timeline service
+func NewContactTimelineService(db *gorm.DB, encryptionManager *security.EncryptionManager) *ContactTimelineService {
+	return &ContactTimelineService{db: db, encryptionManager: encryptionManager}
+}
+
+// timelineSource loads up to fetch of a source's newest events and counts all of them
+type timelineSource func(contact *TimelineContact, types map[string]bool, fetch int) ([]TimelineEvent, int64, error)
+
+// Timeline returns a page of the contact's timeline. Campaign executions are
+// limited to re-engagement leads visible to viewer.
+func (s *ContactTimelineService) Timeline(contactID uint, viewer *models.AdminUser, query TimelineQuery) (*ContactTimeline, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 || query.Limit > MaxTimelineLimit {
+		query.Limit = DefaultTimelineLimit
+	}
+	var types map[string]bool
+	if len(query.Types) > 0 {
+		types = make(map[string]bool, len(query.Types))
+		for _, t := range query.Types {
+			types[t] = true
+		}
+	}
+
+	var record models.Contact
+	if err := s.db.First(&record, contactID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContactNotFound
+		}
+		return nil, err
+	}
+	contact, err := s.resolveContact(record, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every source's newest page*limit events are enough to fill the page
+	fetch := query.Page * query.Limit
+	sources := []timelineSource{
+		func(c *TimelineContact, types map[string]bool, fetch int) ([]TimelineEvent, int64, error) {
+			if !wantsTimelineType(types, TimelineContactSubmitted) {
+				return nil, 0, nil
+			}
+			return []TimelineEvent{contactTimelineEvent(record)}, 1, nil
+		},
+		s.leadEvents,
+		s.behavioralEvents,
+		s.campaignEvents,
+		s.incomingEmailEvents,
+		s.approvalEvents,
+	}
+
+	timeline := &ContactTimeline{Contact: *contact, Events: []TimelineEvent{}}
+	for _, source := range sources {
+		events, count, err := source(contact, types, fetch)
+		if err != nil {
+			return nil, err
+		}
+		timeline.Events = append(timeline.Events, events...)
+		timeline.Total += count
+	}
+
+	sort.SliceStable(timeline.Events, func(i, j int) bool {
+		return timeline.Events[i].Timestamp.After(timeline.Events[j].Timestamp)
+	})
+	offset := (query.Page - 1) * query.Limit
+	if offset >= len(timeline.Events) {
+		timeline.Events = []TimelineEvent{}
+	} else {
+		end := offset + query.Limit
+		if end > len(timeline.Events) {
+			end = len(timeline.Events)
+		}
+		timeline.Events = timeline.Events[offset:end]
+	}
+	return timeline, nil
+}
+
+// resolveContact decrypts the contact's PII and finds the leads and
+// re-engagement leads that share its FUB ID or email
+func (s *ContactTimelineService) resolveContact(record models.Contact, viewer *models.AdminUser) (*TimelineContact, error) {
+	contact := &TimelineContact{
+		ID:                  record.ID,
+		Name:                s.decrypt(record.Name),
+		Email:               strings.ToLower(strings.TrimSpace(s.decrypt(record.Email))),
+		Phone:               s.decrypt(record.Phone),
+		FUBLeadID:           record.FUBLeadID,
+		LeadIDs:             []uint{},
+		ReengagementLeadIDs: []uint{},
+	}
+
+	if contact.FUBLeadID != "" || contact.Email != "" {
+		query := s.db.Model(&models.Lead{})
+		switch {
+		case contact.FUBLeadID != "" && contact.Email != "":
+			query = query.Where("fub_lead_id = ? OR LOWER(email) = ?", contact.FUBLeadID, contact.Email)
+		case contact.FUBLeadID != "":
+			query = query.Where("fub_lead_id = ?", contact.FUBLeadID)
+		default:
+			query = query.Where("LOWER(email) = ?", contact.Email)
+		}
+		if err := query.Pluck("id", &contact.LeadIDs).Error; err != nil {
+			return nil, fmt.Errorf("failed to find leads: %w", err)
+		}
+	}
+
+	var emailHash string
+	if contact.Email != "" && s.encryptionManager != nil {
+		emailHash = s.encryptionManager.EmailBlindIndex(contact.Email)
+	}
+	if viewer != nil && (contact.FUBLeadID != "" || emailHash != "") {
+		query := s.db.Model(&models.LeadReengagement{}).Scopes(models.LeadsVisibleTo(viewer))
+		switch {
+		case contact.FUBLeadID != "" && emailHash != "":
+			query = query.Where("fub_contact_id = ? OR email_hash = ?", contact.FUBLeadID, emailHash)
+		case contact.FUBLeadID != "":
+			query = query.Where("fub_contact_id = ?", contact.FUBLeadID)
+		default:
+			query = query.Where("email_hash = ?", emailHash)
+		}
+		if err := query.Pluck("id", &contact.ReengagementLeadIDs).Error; err != nil {
+			return nil, fmt.Errorf("failed to find re-engagement leads: %w", err)
+		}
+	}
+	return contact, nil
+}
+
+func (s *ContactTimelineService) leadEvents(contact *TimelineContact, types map[string]bool, fetch int) ([]TimelineEvent, int64, error) {
+	if len(contact.LeadIDs) == 0 || !wantsTimelineType(types, TimelineLeadCreated) {
+		return nil, 0, nil
+	}
+	var leads []models.Lead
+	if err := s.db.Where("id IN ?", contact.LeadIDs).Order("created_at DESC").Limit(fetch).Find(&leads).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to load leads: %w", err)
+	}
+	events := make([]TimelineEvent, 0, len(leads))
+	for _, lead := range leads {
+		events = append(events, TimelineEvent{
+			Type:      TimelineLeadCreated,
+			Source:    "lead",
+			SourceID:  int64(lead.ID),
+			Timestamp: lead.CreatedAt,
+			Title:     fmt.Sprintf("Lead created from %s", lead.Source),
+			Data:      map[string]interface{}{"status": lead.Status, "fub_lead_id": lead.FUBLeadID},
+		})
+	}
+	return events, int64(len(contact.LeadIDs)), nil
+}
+
+func (s *ContactTimelineService) behavioralEvents(contact *TimelineContact, types map[string]bool, fetch int) ([]TimelineEvent, int64, error) {
+	if len(contact.LeadIDs) == 0 {
+		return nil, 0, nil
+	}
+	query := s.db.Model(&models.BehavioralEvent{}).Where("lead_id IN ?", contact.LeadIDs)
+	if types != nil {
+		var eventTypes, mapped []string
+		for timelineType, values := range behavioralTimelineTypes {
+			mapped = append(mapped, values...)
+			if types[timelineType] {
+				eventTypes = append(eventTypes, values...)
+			}
+		}
+		switch {
+		case types[TimelineActivity] && len(eventTypes) > 0:
+			query = query.Where("event_type IN ? OR event_type NOT IN ?", eventTypes, mapped)
+		case types[TimelineActivity]:
+			query = query.Where("event_type NOT IN ?", mapped)
+		case len(eventTypes) > 0:
+			query = query.Where("event_type IN ?", eventTypes)
+		default:
+			return nil, 0, nil
+		}
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count behavioral events: %w", err)
+	}
+	var records []models.BehavioralEvent
+	if err := query.Order("created_at DESC").Limit(fetch).Find(&records).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to load behavioral events: %w", err)
+	}
+
+	events := make([]TimelineEvent, 0, len(records))
+	for _, record := range records {
+		timelineType := behavioralTimelineType(record.EventType)
+		title := strings.ReplaceAll(record.EventType, "_", " ")
+		if timelineType != TimelineActivity || title == "" {
+			title = strings.ReplaceAll(timelineType, "_", " ")
+		}
+		events = append(events, TimelineEvent{
+			Type:       timelineType,
+			Source:     "behavioral_event",
+			SourceID:   record.ID,
+			Timestamp:  record.CreatedAt,
+			Title:      strings.ToUpper(title[:1]) + title[1:],
+			PropertyID: record.PropertyID,
+			Data:       map[string]interface{}{"event_type": record.EventType, "session_id": record.SessionID},
+		})
+	}
+	return events, total, nil
+}
+
+func (s *ContactTimelineService) campaignEvents(contact *TimelineContact, types map[string]bool, fetch int) ([]TimelineEvent, int64, error) {
+	if len(contact.ReengagementLeadIDs) == 0 {
+		return nil, 0, nil
+	}
+	query := s.db.Model(&models.CampaignExecution{}).Where("lead_reengagement_id IN ?", contact.ReengagementLeadIDs)
+	switch {
+	case wantsTimelineType(types, TimelineEmailSent) && wantsTimelineType(types, TimelineCampaignTouched):
+	case wantsTimelineType(types, TimelineEmailSent):
+		query = query.Where("status = ?", "sent")
+	case wantsTimelineType(types, TimelineCampaignTouched):
+		query = query.Where("status <> ?", "sent")
+	default:
+		return nil, 0, nil
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count campaign executions: %w", err)
+	}
+	// Sent executions are placed at their send time, the rest at their last update
+	var records []models.CampaignExecution
+	if err := query.Preload("CampaignTemplate").
+		Order("COALESCE(executed_at, updated_at) DESC").
+		Limit(fetch).Find(&records).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to load campaign executions: %w", err)
+	}
+
+	events := make([]TimelineEvent, 0, len(records))
+	for _, record := range records {
+		event := TimelineEvent{
+			Type:      TimelineCampaignTouched,
+			Source:    "campaign_execution",
+			SourceID:  int64(record.ID),
+			Timestamp: record.UpdatedAt,
+			Title:     fmt.Sprintf("Campaign %q %s", record.CampaignName, record.Status),
+			Data: map[string]interface{}{
+				"campaign_name": record.CampaignName,
+				"status":        record.Status,
+				"subject":       record.CampaignTemplate.Subject,
+			},
+		}
+		if record.Status == "sent" {
+			event.Type = TimelineEmailSent
+			event.Title = fmt.Sprintf("Campaign email sent: %s", record.CampaignTemplate.Subject)
+			event.Data["opened"] = record.EmailOpened
+			event.Data["clicked"] = record.EmailClicked
+			if record.ExecutedAt != nil {
+				event.Timestamp = *record.ExecutedAt
+			}
+		} else if record.FailureReason != "" {
+			event.Data["failure_reason"] = record.FailureReason
+		}
+		events = append(events, event)
+	}
+	return events, total, nil
+}
+
+func (s *ContactTimelineService) incomingEmailEvents(contact *TimelineContact, types map[string]bool, fetch int) ([]TimelineEvent, int64, error) {
+	if contact.Email == "" || !wantsTimelineType(types, TimelineEmailReceived) {
+		return nil, 0, nil
+	}
+	// From addresses are stored bare or as "Name <address>"
+	query := s.db.Model(&models.IncomingEmail{}).
+		Where("LOWER(from_email) = ? OR LOWER(from_email) LIKE ?", contact.Email, "%<"+contact.Email+">")
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count incoming emails: %w", err)
+	}
+	var records []models.IncomingEmail
+	if err := query.Order("received_at DESC").Limit(fetch).Find(&records).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to load incoming emails: %w", err)
+	}
+
+	events := make([]TimelineEvent, 0, len(records))
+	for _, record := range records {
+		timestamp := record.ReceivedAt
+		if timestamp.IsZero() {
+			timestamp = record.CreatedAt
+		}
+		events = append(events, TimelineEvent{
+			Type:      TimelineEmailReceived,
+			Source:    "incoming_email",
+			SourceID:  int64(record.ID),
+			Timestamp: timestamp,
+			Title:     fmt.Sprintf("Email received: %s", record.Subject),
+			Data:      map[string]interface{}{"email_type": record.EmailType, "processing_status": record.ProcessingStatus},
+		})
+	}
+	return events, total, nil
+}
+
+func (s *ContactTimelineService) approvalEvents(contact *TimelineContact, types map[string]bool, fetch int) ([]TimelineEvent, int64, error) {
+	if contact.FUBLeadID == "" {
+		return nil, 0, nil
+	}
+	query := s.db.Model(&models.Approval{}).Where("fub_lead_id = ?", contact.FUBLeadID)
+	switch {
+	case wantsTimelineType(types, TimelineApplicationReceived) && wantsTimelineType(types, TimelineApprovalRequested):
+	case wantsTimelineType(types, TimelineApplicationReceived):
+		query = query.Where("approval_type = ?", "rental_application")
+	case wantsTimelineType(types, TimelineApprovalRequested):
+		query = query.Where("approval_type <> ?", "rental_application")
+	default:
+		return nil, 0, nil
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count approvals: %w", err)
+	}
+	var records []models.Approval
+	if err := query.Order("created_at DESC").Limit(fetch).Find(&records).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to load approvals: %w", err)
+	}
+
+	events := make([]TimelineEvent, 0, len(records))
+	for _, record := range records {
+		event := TimelineEvent{
+			Type:      TimelineApprovalRequested,
+			Source:    "approval",
+			SourceID:  int64(record.ID),
+			Timestamp: record.CreatedAt,
+			Title:     fmt.Sprintf("%s requested", strings.ReplaceAll(record.ApprovalType, "_", " ")),
+			Data:      map[string]interface{}{"status": record.Status, "property_address": record.PropertyAddress},
+		}
+		if record.ApprovalType == "rental_application" {
+			event.Type = TimelineApplicationReceived
+			event.Title = fmt.Sprintf("Application received for %s", record.PropertyAddress)
+		}
+		events = append(events, event)
+	}
+	return events, total, nil
+}
+
+func contactTimelineEvent(contact models.Contact) TimelineEvent {
+	return TimelineEvent{
+		Type:      TimelineContactSubmitted,
+		Source:    "contact",
+		SourceID:  int64(contact.ID),
+		Timestamp: contact.CreatedAt,
+		Title:     fmt.Sprintf("Contact submitted via %s", contact.Source),
+		Data:      map[string]interface{}{"message": contact.Message, "property_id": contact.PropertyID, "urgent": contact.Urgent},
+	}
+}
+
+// behavioralTimelineType maps a behavioral event type to its timeline type
+func behavioralTimelineType(eventType string) string {
+	for timelineType, values := range behavioralTimelineTypes {
+		for _, value := range values {
+			if value == eventType {
+				return timelineType
+			}
+		}
+	}
+	return TimelineActivity
+}
+
+// wantsTimelineType reports whether the type filter includes timelineType
+func wantsTimelineType(types map[string]bool, timelineType string) bool {
+	return types == nil || types[timelineType]
+}
+
+func (s *ContactTimelineService) decrypt(value security.EncryptedString) string {
+	if s.encryptionManager == nil {
+		return string(value)
+	}
+	plaintext, err := s.encryptionManager.Decrypt(value)
+	if err != nil {
+		return ""
+	}
+	return plaintext
+}
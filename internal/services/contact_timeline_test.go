@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestContactTimeline_MergesSourcesNewestFirst(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Contact{}, &models.Lead{}, &models.BehavioralEvent{}, &models.LeadReengagement{},
+		&models.CampaignTemplate{}, &models.CampaignExecution{}, &models.PreListingItem{}, &models.IncomingEmail{}, &models.Approval{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	start := time.Now().Add(-10 * 24 * time.Hour)
+	at := func(days int) time.Time { return start.Add(time.Duration(days) * 24 * time.Hour) }
+
+	contact := models.Contact{Name: security.EncryptedString("Dana Reyes"), Phone: security.EncryptedString("7135550100"),
+		Email: security.EncryptedString("Dana@Example.com"), FUBLeadID: "fub-9", Source: "contact_form", CreatedAt: at(0)}
+	db.Create(&contact)
+	lead := models.Lead{FirstName: "Dana", LastName: "Reyes", Email: "dana@example.com", FUBLeadID: "fub-9", CreatedAt: at(1)}
+	db.Create(&lead)
+	property := int64(12)
+	db.Create(&models.BehavioralEvent{LeadID: int64(lead.ID), EventType: "viewed", PropertyID: &property, CreatedAt: at(2)})
+	db.Create(&models.BehavioralEvent{LeadID: int64(lead.ID), EventType: "session_start", CreatedAt: at(3)})
+	db.Create(&models.BehavioralEvent{LeadID: 999, EventType: "viewed", CreatedAt: at(3)})
+
+	reengagement := models.LeadReengagement{FUBContactID: "fub-9", OwnerID: "agent-1"}
+	db.Create(&reengagement)
+	template := models.CampaignTemplate{Name: "welcome", EmailNumber: 1, Subject: "Welcome back", Body: "Hi"}
+	db.Create(&template)
+	sentAt := at(4)
+	db.Create(&models.CampaignExecution{LeadReengagementID: reengagement.ID, CampaignTemplateID: template.ID,
+		CampaignName: "welcome", Status: "sent", ExecutedAt: &sentAt})
+
+	db.Create(&models.IncomingEmail{FromEmail: "Dana Reyes <dana@example.com>", ToEmail: "team@example.com", Subject: "Question", ReceivedAt: at(5)})
+	db.Create(&models.IncomingEmail{FromEmail: "someone@example.com", ToEmail: "team@example.com", Subject: "Other", ReceivedAt: at(5)})
+	approval := models.Approval{ApprovalType: "rental_application", FUBLeadID: "fub-9", PropertyAddress: "1 Oak St"}
+	db.Create(&approval)
+	db.Model(&approval).Update("created_at", at(6))
+
+	service := NewContactTimelineService(db, nil)
+	owner := &models.AdminUser{ID: "agent-1", Role: "agent"}
+
+	timeline, err := service.Timeline(contact.ID, owner, TimelineQuery{})
+	if err != nil {
+		t.Fatalf("Timeline failed: %v", err)
+	}
+	expected := []string{TimelineApplicationReceived, TimelineEmailReceived, TimelineEmailSent, TimelineActivity,
+		TimelinePropertyViewed, TimelineLeadCreated, TimelineContactSubmitted}
+	if timeline.Total != int64(len(expected)) || len(timeline.Events) != len(expected) {
+		t.Fatalf("Expected %d events, got total %d and %+v", len(expected), timeline.Total, timeline.Events)
+	}
+	for i, eventType := range expected {
+		if timeline.Events[i].Type != eventType {
+			t.Errorf("Expected event %d to be %s, got %s", i, eventType, timeline.Events[i].Type)
+		}
+	}
+	if timeline.Contact.Name != "Dana Reyes" || timeline.Contact.Email != "dana@example.com" {
+		t.Errorf("Expected decrypted contact identity, got %+v", timeline.Contact)
+	}
+
+	page, _ := service.Timeline(contact.ID, owner, TimelineQuery{Types: []string{TimelinePropertyViewed, TimelineEmailSent}, Page: 2, Limit: 1})
+	if page.Total != 2 || len(page.Events) != 1 || page.Events[0].Type != TimelinePropertyViewed {
+		t.Errorf("Expected the property view alone on page 2 of the filtered timeline, got %+v", page)
+	}
+
+	// Campaign executions for another agent's lead stay hidden
+	other, _ := service.Timeline(contact.ID, &models.AdminUser{ID: "agent-2", Role: "agent"}, TimelineQuery{Types: []string{TimelineEmailSent}})
+	if other.Total != 0 {
+		t.Errorf("Expected no campaign events for another agent, got %+v", other.Events)
+	}
+
+	if _, err := service.Timeline(contact.ID+100, owner, TimelineQuery{}); err != ErrContactNotFound {
+		t.Errorf("Expected ErrContactNotFound, got %v", err)
+	}
+}
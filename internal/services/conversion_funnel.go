@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ConversionFunnelStages are the stages of the classic conversion funnel, in order
+var ConversionFunnelStages = []string{"visit", "inquiry", "application", "closing"}
+
+// conversionStageEvents maps each funnel stage to the behavioral events that reach it
+var conversionStageEvents = map[string][]string{
+	"visit":       {"viewed", "property_viewed", "property_view", "saved", "property_saved"},
+	"inquiry":     {"inquired", "inquiry", "inquiry_submitted"},
+	"application": {"applied", "application", "application_started", "application_submitted"},
+	"closing":     {"converted", "lease_signed"},
+}
+
+// DefaultFunnelCacheTTL is how long a conversion funnel is served from the analytics cache
+const DefaultFunnelCacheTTL = 5 * time.Minute
+
+// ConversionFunnelStage counts the leads that reached a funnel stage
+type ConversionFunnelStage struct {
+	Name           string  `json:"name"`
+	LeadCount      int     `json:"lead_count"`
+	ConversionRate float64 `json:"conversion_rate"` // % that move to next stage
+	ReachedRate    float64 `json:"reached_rate"`    // % of visiting leads that got this far
+}
+
+// ConversionFunnel is the visit → inquiry → application → closing funnel for a set of leads
+type ConversionFunnel struct {
+	Stages            []ConversionFunnelStage `json:"stages"`
+	TotalLeads        int                     `json:"total_leads"`
+	OverallConversion float64                 `json:"overall_conversion_rate"`
+}
+
+// ConversionFunnelReport is the conversion funnel for a date range, overall and
+// broken down by lead source and by the type of property the lead engaged with
+type ConversionFunnelReport struct {
+	Start          time.Time                   `json:"start"`
+	End            time.Time                   `json:"end"`
+	Funnel         ConversionFunnel            `json:"funnel"`
+	BySource       map[string]ConversionFunnel `json:"by_source"`
+	ByPropertyType map[string]ConversionFunnel `json:"by_property_type"`
+	GeneratedAt    time.Time                   `json:"generated_at"`
+}
+
+// ConversionFunnelStageChange compares a stage across two funnel reports
+type ConversionFunnelStageChange struct {
+	Name                 string  `json:"name"`
+	LeadCountChange      int     `json:"lead_count_change"`
+	LeadCountChangeRate  float64 `json:"lead_count_change_rate"` // % change from the previous range
+	ConversionRateChange float64 `json:"conversion_rate_change"` // percentage points
+}
+
+// ConversionFunnelComparison compares the funnel over two date ranges
+type ConversionFunnelComparison struct {
+	Current                 *ConversionFunnelReport       `json:"current"`
+	Previous                *ConversionFunnelReport       `json:"previous"`
+	Stages                  []ConversionFunnelStageChange `json:"stages"`
+	OverallConversionChange float64                       `json:"overall_conversion_change"` // percentage points
+}
+
+// conversionFunnelRow is one lead's event of one type in the range
+type conversionFunnelRow struct {
+	LeadID       int64
+	EventType    string
+	Source       string
+	PropertyType string
+}
+
+// SetAnalyticsCache serves conversion funnels from the analytics cache
+func (fas *FunnelAnalyticsService) SetAnalyticsCache(cache *AnalyticsCacheService, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultFunnelCacheTTL
+	}
+	fas.analyticsCache = cache
+	fas.analyticsCacheTTL = ttl
+}
+
+// BuildConversionFunnel counts the leads reaching each stage between start and
+// end. A lead reaches a stage when it has an event for that stage or a later one.
+func (fas *FunnelAnalyticsService) BuildConversionFunnel(start, end time.Time) (*ConversionFunnelReport, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("funnel range must end after it starts")
+	}
+
+	var cacheKey string
+	if fas.analyticsCache != nil {
+		// Rolling ranges land in the same bucket for the length of the TTL
+		cacheKey = fmt.Sprintf("%d:%d", start.Truncate(fas.analyticsCacheTTL).Unix(), end.Truncate(fas.analyticsCacheTTL).Unix())
+		if cached, ok := fas.analyticsCache.GetConversionFunnel(context.Background(), cacheKey); ok {
+			return cached, nil
+		}
+	}
+
+	stageOf := make(map[string]int)
+	var eventTypes []string
+	for i, stage := range ConversionFunnelStages {
+		for _, eventType := range conversionStageEvents[stage] {
+			stageOf[eventType] = i
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	var rows []conversionFunnelRow
+	if err := fas.db.Table("behavioral_events AS be").
+		Select("DISTINCT be.lead_id, be.event_type, COALESCE(l.source, '') AS source, COALESCE(p.property_type, '') AS property_type").
+		Joins("LEFT JOIN leads l ON l.id = be.lead_id").
+		Joins("LEFT JOIN properties p ON p.id = be.property_id").
+		Where("be.created_at >= ? AND be.created_at < ?", start, end).
+		Where("be.event_type IN ?", eventTypes).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load funnel events: %w", err)
+	}
+
+	// Furthest stage per lead, overall and per group
+	furthest := make(map[int64]int)
+	bySource := make(map[string]map[int64]int)
+	byPropertyType := make(map[string]map[int64]int)
+	reach := func(groups map[string]map[int64]int, group string, leadID int64, stage int) {
+		if group == "" {
+			group = "unknown"
+		}
+		if groups[group] == nil {
+			groups[group] = make(map[int64]int)
+		}
+		if current, ok := groups[group][leadID]; !ok || stage > current {
+			groups[group][leadID] = stage
+		}
+	}
+	for _, row := range rows {
+		stage := stageOf[row.EventType]
+		if current, ok := furthest[row.LeadID]; !ok || stage > current {
+			furthest[row.LeadID] = stage
+		}
+		reach(bySource, row.Source, row.LeadID, stage)
+		reach(byPropertyType, row.PropertyType, row.LeadID, stage)
+	}
+
+	report := &ConversionFunnelReport{
+		Start:          start,
+		End:            end,
+		Funnel:         buildConversionFunnel(furthest),
+		BySource:       make(map[string]ConversionFunnel, len(bySource)),
+		ByPropertyType: make(map[string]ConversionFunnel, len(byPropertyType)),
+		GeneratedAt:    time.Now(),
+	}
+	for source, leads := range bySource {
+		report.BySource[source] = buildConversionFunnel(leads)
+	}
+	for propertyType, leads := range byPropertyType {
+		report.ByPropertyType[propertyType] = buildConversionFunnel(leads)
+	}
+
+	if fas.analyticsCache != nil {
+		if err := fas.analyticsCache.SetConversionFunnel(context.Background(), cacheKey, report, fas.analyticsCacheTTL); err != nil {
+			log.Printf("⚠️ Failed to cache conversion funnel: %v", err)
+		}
+	}
+	return report, nil
+}
+
+// CompareConversionFunnels builds the funnel for both ranges and the change between them
+func (fas *FunnelAnalyticsService) CompareConversionFunnels(currentStart, currentEnd, previousStart, previousEnd time.Time) (*ConversionFunnelComparison, error) {
+	current, err := fas.BuildConversionFunnel(currentStart, currentEnd)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := fas.BuildConversionFunnel(previousStart, previousEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &ConversionFunnelComparison{
+		Current:                 current,
+		Previous:                previous,
+		OverallConversionChange: current.Funnel.OverallConversion - previous.Funnel.OverallConversion,
+	}
+	for i, stage := range current.Funnel.Stages {
+		before := previous.Funnel.Stages[i]
+		change := ConversionFunnelStageChange{
+			Name:                 stage.Name,
+			LeadCountChange:      stage.LeadCount - before.LeadCount,
+			ConversionRateChange: stage.ConversionRate - before.ConversionRate,
+		}
+		if before.LeadCount > 0 {
+			change.LeadCountChangeRate = float64(change.LeadCountChange) / float64(before.LeadCount) * 100
+		}
+		comparison.Stages = append(comparison.Stages, change)
+	}
+	return comparison, nil
+}
+
+// buildConversionFunnel turns each lead's furthest stage into stage counts and rates
+func buildConversionFunnel(furthest map[int64]int) ConversionFunnel {
+	counts := make([]int, len(ConversionFunnelStages))
+	for _, stage := range furthest {
+		for i := 0; i <= stage; i++ {
+			counts[i]++
+		}
+	}
+
+	funnel := ConversionFunnel{TotalLeads: len(furthest)}
+	for i, name := range ConversionFunnelStages {
+		stage := ConversionFunnelStage{Name: name, LeadCount: counts[i]}
+		if i+1 < len(counts) && counts[i] > 0 {
+			stage.ConversionRate = float64(counts[i+1]) / float64(counts[i]) * 100
+		}
+		if counts[0] > 0 {
+			stage.ReachedRate = float64(counts[i]) / float64(counts[0]) * 100
+		}
+		funnel.Stages = append(funnel.Stages, stage)
+	}
+	funnel.OverallConversion = funnel.Stages[len(funnel.Stages)-1].ReachedRate
+	return funnel
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestConversionFunnel_CountsFurthestStagePerLeadAndCompares(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Lead{}, &models.Property{}, &models.BehavioralEvent{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	house := models.Property{MLSId: "house", Address: "1 Oak St", PropertyType: "house"}
+	condo := models.Property{MLSId: "condo", Address: "2 Elm St", PropertyType: "condo"}
+	db.Create(&house)
+	db.Create(&condo)
+	houseID, condoID := int64(house.ID), int64(condo.ID)
+
+	for i, source := range []string{"Zillow", "Website", "Website", "Website", "Website"} {
+		db.Create(&models.Lead{ID: uint(i + 1), FirstName: "Lead", Email: "lead@example.com", FUBLeadID: fmt.Sprintf("fub-%d", i+1), Source: source})
+	}
+
+	now := time.Now()
+	event := func(leadID int64, eventType string, propertyID *int64, daysAgo int) {
+		db.Create(&models.BehavioralEvent{LeadID: leadID, EventType: eventType, PropertyID: propertyID, CreatedAt: now.AddDate(0, 0, -daysAgo)})
+	}
+	event(1, "viewed", &houseID, 3)
+	event(1, "inquired", &houseID, 2)
+	event(1, "applied", &houseID, 2)
+	event(1, "converted", &houseID, 1)
+	event(2, "viewed", &condoID, 3)
+	event(2, "inquired", &condoID, 3)
+	event(3, "applied", nil, 2)
+	event(4, "viewed", &condoID, 1)
+	event(5, "viewed", &condoID, 10)
+
+	service := NewFunnelAnalyticsService(db)
+	comparison, err := service.CompareConversionFunnels(now.AddDate(0, 0, -7), now, now.AddDate(0, 0, -14), now.AddDate(0, 0, -7))
+	if err != nil {
+		t.Fatalf("CompareConversionFunnels failed: %v", err)
+	}
+
+	funnel := comparison.Current.Funnel
+	counts := []int{}
+	for _, stage := range funnel.Stages {
+		counts = append(counts, stage.LeadCount)
+	}
+	if len(counts) != 4 || counts[0] != 4 || counts[1] != 3 || counts[2] != 2 || counts[3] != 1 {
+		t.Fatalf("Expected visit/inquiry/application/closing of 4/3/2/1, got %v", counts)
+	}
+	if funnel.OverallConversion != 25 || funnel.Stages[0].ConversionRate != 75 {
+		t.Errorf("Expected 25%% overall and 75%% visit to inquiry, got %+v", funnel)
+	}
+
+	if website := comparison.Current.BySource["Website"]; website.TotalLeads != 3 || website.Stages[3].LeadCount != 0 {
+		t.Errorf("Expected three unconverted website leads, got %+v", website)
+	}
+	if houses := comparison.Current.ByPropertyType["house"]; houses.Stages[3].LeadCount != 1 {
+		t.Errorf("Expected the house lead to close, got %+v", houses)
+	}
+	if unknown := comparison.Current.ByPropertyType["unknown"]; unknown.TotalLeads != 1 || unknown.Stages[2].LeadCount != 1 {
+		t.Errorf("Expected the application without a property under unknown, got %+v", unknown)
+	}
+
+	if comparison.Previous.Funnel.TotalLeads != 1 || comparison.Stages[0].LeadCountChange != 3 || comparison.Stages[0].LeadCountChangeRate != 300 {
+		t.Errorf("Expected visits up 3 (300%%) on the previous week, got %+v", comparison.Stages[0])
+	}
+}
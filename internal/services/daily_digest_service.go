@@ -0,0 +1,457 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Daily digest limits
+const (
+	digestHotLeadScore  = 70 // Composite behavioral score above which a lead is hot
+	digestListLimit     = 5  // Hot leads, overdue pre-listings and actions listed in full
+	digestInsightMinPri = 4  // Lowest insight priority (1-5) included in top actions
+)
+
+// digestOpportunitySource is the part of RelationshipIntelligenceEngine the digest uses
+type digestOpportunitySource interface {
+	AnalyzeOpportunities() ([]Opportunity, error)
+}
+
+// digestInsightSource is the part of InsightGeneratorService the digest uses
+type digestInsightSource interface {
+	GenerateDashboardInsights() (*DashboardInsights, error)
+}
+
+// DailyDigest is one admin's morning summary
+type DailyDigest struct {
+	AdminUserID        string              `json:"admin_user_id"`
+	AdminName          string              `json:"admin_name"`
+	Date               string              `json:"date"`
+	NewLeads           int64               `json:"new_leads"`
+	HotLeadCount       int64               `json:"hot_lead_count"`
+	HotLeads           []DigestLead        `json:"hot_leads"`
+	OverdueCount       int64               `json:"overdue_pre_listing_count"`
+	OverduePreListings []DigestPreListing  `json:"overdue_pre_listings"`
+	Campaigns          DigestCampaignStats `json:"campaigns"`
+	TopActions         []DigestAction      `json:"top_actions"`
+}
+
+// DigestLead is a hot lead listed in the digest
+type DigestLead struct {
+	LeadID uint   `json:"lead_id"`
+	Name   string `json:"name"`
+	Score  int    `json:"score"`
+}
+
+// DigestPreListing is an overdue pre-listing item listed in the digest
+type DigestPreListing struct {
+	Address string `json:"address"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason"`
+}
+
+// DigestCampaignStats summarises campaign sends since the last digest
+type DigestCampaignStats struct {
+	Sent    int64 `json:"sent"`
+	Opened  int64 `json:"opened"`
+	Clicked int64 `json:"clicked"`
+	Failed  int64 `json:"failed"`
+}
+
+// DigestAction is a recommended action for today
+type DigestAction struct {
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+// DailyDigestService emails opted-in admins a summary of their leads, overdue
+// pre-listings, campaign results and today's top actions once a day
+type DailyDigestService struct {
+	db            *gorm.DB
+	email         alertEmailSender
+	opportunities digestOpportunitySource
+	insights      digestInsightSource
+	secret        []byte
+
+	sendHour int // Local hour from which the day's digest is sent
+	location *time.Location
+	baseURL  string
+	now      func() time.Time
+}
+
+// NewDailyDigestService creates a daily digest service. secret signs the
+// digest unsubscribe links.
+func NewDailyDigestService(db *gorm.DB, email *EmailService, secret string) *DailyDigestService {
+	s := &DailyDigestService{
+		db:       db,
+		secret:   []byte(secret),
+		sendHour: 7,
+		location: time.Local,
+		now:      time.Now,
+	}
+	// Avoid storing a typed nil so the check in RunOnce works
+	if email != nil {
+		s.email = email
+	}
+	return s
+}
+
+// SetIntelligence sets the engines the "top actions today" section is built from. Either may be nil.
+func (s *DailyDigestService) SetIntelligence(relationships *RelationshipIntelligenceEngine, insights *InsightGeneratorService) {
+	if relationships != nil {
+		s.opportunities = relationships
+	}
+	if insights != nil {
+		s.insights = insights
+	}
+}
+
+// SetSchedule sets the hour, in loc, from which each day's digest is sent
+func (s *DailyDigestService) SetSchedule(hour int, loc *time.Location) {
+	s.sendHour = hour
+	if loc != nil {
+		s.location = loc
+	}
+}
+
+// SetBaseURL sets the site URL used for links in the digest
+func (s *DailyDigestService) SetBaseURL(baseURL string) {
+	s.baseURL = strings.TrimRight(baseURL, "/")
+}
+
+// Start runs RunOnce every interval until ctx is cancelled
+func (s *DailyDigestService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if sent, err := s.RunOnce(); err != nil {
+				log.Printf("⚠️ Failed to send daily digests: %v", err)
+			} else if sent > 0 {
+				log.Printf("📰 Sent %d daily digests", sent)
+			}
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 Daily digest stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	log.Printf("🕐 Daily digest started (from %02d:00 %s, checked every %v)", s.sendHour, s.location, interval)
+}
+
+// RunOnce sends today's digest to every opted-in admin who has not had it
+// yet, once the send hour has passed. It returns how many were sent.
+func (s *DailyDigestService) RunOnce() (int, error) {
+	now := s.now().In(s.location)
+	if now.Hour() < s.sendHour || s.email == nil {
+		return 0, nil
+	}
+	date := now.Format("2006-01-02")
+
+	var admins []models.AdminUser
+	err := s.db.Model(&models.AdminUser{}).
+		Select("admin_users.id", "admin_users.username", "admin_users.email", "admin_users.role").
+		Joins("JOIN notification_preferences ON notification_preferences.admin_user_id = admin_users.id").
+		Where("notification_preferences.event_type = ? AND notification_preferences.email = ?", models.NotificationEventDailyDigest, true).
+		Where("admin_users.active = ?", true).
+		Where("admin_users.id NOT IN (?)", s.db.Model(&models.DailyDigestDelivery{}).
+			Select("admin_user_id").Where("digest_date = ? AND status = ?", date, models.DailyDigestSent)).
+		Find(&admins).Error
+	if err != nil || len(admins) == 0 {
+		return 0, err
+	}
+
+	// The intelligence engines are team-wide, so run them once per batch
+	opportunities, insights := s.intelligence()
+
+	sent := 0
+	since := now.Add(-24 * time.Hour)
+	for _, admin := range admins {
+		digest, err := s.BuildDigest(admin, since, opportunities, insights)
+		if err == nil {
+			digest.Date = date
+			err = s.email.SendEmail(admin.Email, "Your PropertyHub morning digest — "+now.Format("Mon Jan 2"),
+				s.renderDigest(digest), map[string]interface{}{"type": models.NotificationEventDailyDigest, "admin_user_id": admin.ID})
+		}
+		if s.recordDelivery(admin.ID, date, err) == nil && err == nil {
+			sent++
+		}
+		if err != nil {
+			log.Printf("⚠️ Failed to send daily digest to %s: %v", admin.Username, err)
+		}
+	}
+	return sent, nil
+}
+
+// Preview builds an admin's digest for the last 24 hours without sending it
+func (s *DailyDigestService) Preview(admin models.AdminUser) (*DailyDigest, error) {
+	now := s.now().In(s.location)
+	opportunities, insights := s.intelligence()
+	digest, err := s.BuildDigest(admin, now.Add(-24*time.Hour), opportunities, insights)
+	if err != nil {
+		return nil, err
+	}
+	digest.Date = now.Format("2006-01-02")
+	return digest, nil
+}
+
+// intelligence runs the relationship and insight engines, skipping any that fail
+func (s *DailyDigestService) intelligence() ([]Opportunity, []Insight) {
+	var opportunities []Opportunity
+	if s.opportunities != nil {
+		var err error
+		if opportunities, err = s.opportunities.AnalyzeOpportunities(); err != nil {
+			log.Printf("⚠️ Daily digest: failed to analyze opportunities: %v", err)
+		}
+	}
+	var insights []Insight
+	if s.insights != nil {
+		if dashboard, err := s.insights.GenerateDashboardInsights(); err == nil && dashboard != nil {
+			insights = dashboard.Insights
+		}
+	}
+	return opportunities, insights
+}
+
+// recordDelivery stores the outcome of one admin's digest for the day
+func (s *DailyDigestService) recordDelivery(adminUserID, date string, sendErr error) error {
+	delivery := models.DailyDigestDelivery{AdminUserID: adminUserID, DigestDate: date, Status: models.DailyDigestSent}
+	if sendErr != nil {
+		delivery.Status = models.DailyDigestFailed
+		delivery.Error = sendErr.Error()
+	} else {
+		sentAt := s.now()
+		delivery.SentAt = &sentAt
+	}
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "admin_user_id"}, {Name: "digest_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "error", "sent_at"}),
+	}).Create(&delivery).Error
+}
+
+// BuildDigest assembles an admin's digest for activity since since. Team
+// admins see every lead; other admins see the leads assigned to them.
+func (s *DailyDigestService) BuildDigest(admin models.AdminUser, since time.Time, opportunities []Opportunity, insights []Insight) (*DailyDigest, error) {
+	digest := &DailyDigest{AdminUserID: admin.ID, AdminName: admin.Username}
+	teamWide := models.IsTeamAdminRole(admin.Role)
+	assigned := func(db *gorm.DB) *gorm.DB {
+		if teamWide {
+			return db
+		}
+		return db.Where("leads.assigned_agent_id = ?", admin.ID)
+	}
+
+	if err := s.db.Model(&models.Lead{}).Scopes(assigned).Where("leads.created_at >= ?", since).Count(&digest.NewLeads).Error; err != nil {
+		return nil, fmt.Errorf("failed to count new leads: %w", err)
+	}
+
+	hot := s.db.Table("behavioral_scores").
+		Joins("JOIN leads ON leads.id = behavioral_scores.lead_id").
+		Where("behavioral_scores.composite_score > ?", digestHotLeadScore).Scopes(assigned)
+	if err := hot.Session(&gorm.Session{}).Count(&digest.HotLeadCount).Error; err != nil {
+		return nil, fmt.Errorf("failed to count hot leads: %w", err)
+	}
+	var hotRows []struct {
+		ID        uint
+		FirstName string
+		LastName  string
+		Score     int
+	}
+	err := hot.Session(&gorm.Session{}).
+		Select("leads.id, leads.first_name, leads.last_name, behavioral_scores.composite_score AS score").
+		Order("behavioral_scores.composite_score DESC").Limit(digestListLimit).Scan(&hotRows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hot leads: %w", err)
+	}
+	for _, row := range hotRows {
+		digest.HotLeads = append(digest.HotLeads, DigestLead{LeadID: row.ID, Name: strings.TrimSpace(row.FirstName + " " + row.LastName), Score: row.Score})
+	}
+
+	// Pre-listings are not assigned to agents, so every admin sees them all
+	overdue := s.db.Model(&models.PreListingItem{}).Where("is_overdue = ?", true)
+	if err := overdue.Session(&gorm.Session{}).Count(&digest.OverdueCount).Error; err != nil {
+		return nil, fmt.Errorf("failed to count overdue pre-listings: %w", err)
+	}
+	var items []models.PreListingItem
+	if err := overdue.Session(&gorm.Session{}).Order("created_at").Limit(digestListLimit).Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to list overdue pre-listings: %w", err)
+	}
+	for _, item := range items {
+		digest.OverduePreListings = append(digest.OverduePreListings, DigestPreListing{Address: item.Address, Status: item.Status, Reason: item.OverdueReason})
+	}
+
+	if err := s.campaignStats(admin.ID, teamWide, since, &digest.Campaigns); err != nil {
+		return nil, fmt.Errorf("failed to summarise campaigns: %w", err)
+	}
+
+	digest.TopActions, err = s.topActions(admin.ID, teamWide, opportunities, insights)
+	if err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// campaignStats counts campaign sends, opens, clicks and failures since since
+func (s *DailyDigestService) campaignStats(adminUserID string, teamWide bool, since time.Time, stats *DigestCampaignStats) error {
+	executions := func() *gorm.DB {
+		query := s.db.Model(&models.CampaignExecution{}).Where("campaign_executions.executed_at >= ?", since)
+		if !teamWide {
+			query = query.Joins("JOIN lead_reengagements ON lead_reengagements.id = campaign_executions.lead_reengagement_id").
+				Where("lead_reengagements.owner_id = ?", adminUserID)
+		}
+		return query
+	}
+	for _, count := range []struct {
+		into  *int64
+		where string
+		args  []interface{}
+	}{
+		{&stats.Sent, "campaign_executions.status = ?", []interface{}{"sent"}},
+		{&stats.Opened, "campaign_executions.email_opened = ?", []interface{}{true}},
+		{&stats.Clicked, "campaign_executions.email_clicked = ?", []interface{}{true}},
+		{&stats.Failed, "campaign_executions.status = ?", []interface{}{"failed"}},
+	} {
+		if err := executions().Where(count.where, count.args...).Count(count.into).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// topActions picks today's actions from the relationship engine's
+// opportunities on the admin's leads, then high-priority team insights
+func (s *DailyDigestService) topActions(adminUserID string, teamWide bool, opportunities []Opportunity, insights []Insight) ([]DigestAction, error) {
+	var ownLeads map[int64]bool
+	if !teamWide && len(opportunities) > 0 {
+		var ids []int64
+		if err := s.db.Model(&models.Lead{}).Where("assigned_agent_id = ?", adminUserID).Pluck("id", &ids).Error; err != nil {
+			return nil, fmt.Errorf("failed to load assigned leads: %w", err)
+		}
+		ownLeads = make(map[int64]bool, len(ids))
+		for _, id := range ids {
+			ownLeads[id] = true
+		}
+	}
+
+	var actions []DigestAction
+	for _, opp := range opportunities {
+		if len(actions) == digestListLimit {
+			return actions, nil
+		}
+		if !teamWide && !ownLeads[opp.LeadID] {
+			continue
+		}
+		title := opp.LeadName
+		if len(opp.ActionSequence) > 0 {
+			title = opp.ActionSequence[0].Description + " — " + opp.LeadName
+		}
+		actions = append(actions, DigestAction{Title: title, Detail: opp.Context})
+	}
+	for _, insight := range insights {
+		if len(actions) == digestListLimit {
+			break
+		}
+		if insight.Priority >= digestInsightMinPri {
+			actions = append(actions, DigestAction{Title: insight.Message, Detail: "From " + insight.Source})
+		}
+	}
+	return actions, nil
+}
+
+// renderDigest formats a digest as an HTML email
+func (s *DailyDigestService) renderDigest(d *DailyDigest) string {
+	var b strings.Builder
+	esc := html.EscapeString
+	fmt.Fprintf(&b, "<h2>Good morning, %s</h2>", esc(d.AdminName))
+	fmt.Fprintf(&b, "<p><strong>%d</strong> new leads · <strong>%d</strong> hot leads · <strong>%d</strong> overdue pre-listings</p>",
+		d.NewLeads, d.HotLeadCount, d.OverdueCount)
+
+	if len(d.TopActions) > 0 {
+		b.WriteString("<h3>Top actions today</h3><ol>")
+		for _, action := range d.TopActions {
+			fmt.Fprintf(&b, "<li><strong>%s</strong><br>%s</li>", esc(action.Title), esc(action.Detail))
+		}
+		b.WriteString("</ol>")
+	}
+	if len(d.HotLeads) > 0 {
+		b.WriteString("<h3>Hot leads</h3><ul>")
+		for _, lead := range d.HotLeads {
+			fmt.Fprintf(&b, "<li>%s (score %d)</li>", esc(lead.Name), lead.Score)
+		}
+		b.WriteString("</ul>")
+	}
+	if len(d.OverduePreListings) > 0 {
+		b.WriteString("<h3>Overdue pre-listings</h3><ul>")
+		for _, item := range d.OverduePreListings {
+			fmt.Fprintf(&b, "<li>%s — %s", esc(item.Address), esc(item.Status))
+			if item.Reason != "" {
+				fmt.Fprintf(&b, " (%s)", esc(item.Reason))
+			}
+			b.WriteString("</li>")
+		}
+		b.WriteString("</ul>")
+	}
+	fmt.Fprintf(&b, "<h3>Campaigns (last 24 hours)</h3><p>%d sent · %d opened · %d clicked · %d failed</p>",
+		d.Campaigns.Sent, d.Campaigns.Opened, d.Campaigns.Clicked, d.Campaigns.Failed)
+
+	fmt.Fprintf(&b, `<p style="font-size:12px;color:#666"><a href="%s/admin/dashboard">Open the dashboard</a> · <a href="%s">Unsubscribe from the daily digest</a></p>`,
+		esc(s.baseURL), esc(s.UnsubscribeURL(d.AdminUserID)))
+	return b.String()
+}
+
+// UnsubscribeURL returns the signed link that turns off an admin's digest
+func (s *DailyDigestService) UnsubscribeURL(adminUserID string) string {
+	payload := "v1." + adminUserID
+	token := base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + s.signUnsubscribe(payload)
+	return fmt.Sprintf("%s/digest/unsubscribe?token=%s", s.baseURL, url.QueryEscape(token))
+}
+
+// Unsubscribe verifies a digest unsubscribe token and turns off that admin's
+// digest, returning the admin's ID
+func (s *DailyDigestService) Unsubscribe(token string) (string, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return "", ErrInvalidUnsubscribeToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || !hmac.Equal([]byte(s.signUnsubscribe(string(payload))), []byte(signature)) {
+		return "", ErrInvalidUnsubscribeToken
+	}
+	adminUserID, found := strings.CutPrefix(string(payload), "v1.")
+	if !found || adminUserID == "" {
+		return "", ErrInvalidUnsubscribeToken
+	}
+
+	err = s.db.Model(&models.NotificationPreference{}).
+		Where("admin_user_id = ? AND event_type = ?", adminUserID, models.NotificationEventDailyDigest).
+		Updates(map[string]interface{}{"email": false, "updated_at": s.now()}).Error
+	return adminUserID, err
+}
+
+// signUnsubscribe uses its own HMAC domain so campaign and digest tokens
+// cannot be swapped
+func (s *DailyDigestService) signUnsubscribe(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("digest-unsubscribe|" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
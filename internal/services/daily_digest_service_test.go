@@ -0,0 +1,102 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+)
+
+type recordingDigestSender struct {
+	to      []string
+	content []string
+}
+
+func (r *recordingDigestSender) SendEmail(to, subject, content string, metadata map[string]interface{}) error {
+	r.to = append(r.to, to)
+	r.content = append(r.content, content)
+	return nil
+}
+
+type staticOpportunities []Opportunity
+
+func (s staticOpportunities) AnalyzeOpportunities() ([]Opportunity, error) {
+	return s, nil
+}
+
+func TestDailyDigest_SendsOncePerDayToOptedInAdmins(t *testing.T) {
+	db := newAdminUsersTestDB(t)
+	if err := db.AutoMigrate(&models.NotificationPreference{}, &models.DailyDigestDelivery{}, &models.Lead{}, &models.PreListingItem{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	// behavioral_scores uses postgres defaults, so create it by hand for sqlite
+	for _, ddl := range []string{
+		`CREATE TABLE behavioral_scores (id TEXT PRIMARY KEY, lead_id INTEGER, composite_score INTEGER)`,
+		`CREATE TABLE campaign_executions (id INTEGER PRIMARY KEY, lead_reengagement_id INTEGER, status TEXT,
+			email_opened BOOLEAN, email_clicked BOOLEAN, executed_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE lead_reengagements (id INTEGER PRIMARY KEY, owner_id TEXT)`,
+	} {
+		if err := db.Exec(ddl).Error; err != nil {
+			t.Fatalf("Failed to create table: %v", err)
+		}
+	}
+	db.Exec(`INSERT INTO admin_users (id, username, email, role, active) VALUES ('admin-1', 'agent', 'agent@example.com', 'admin', true), ('admin-2', 'other', 'other@example.com', 'admin', true)`)
+	db.Create(&models.NotificationPreference{AdminUserID: "admin-1", EventType: models.NotificationEventDailyDigest, Email: true})
+	// The "*" default does not opt an admin in to the digest
+	db.Create(&models.NotificationPreference{AdminUserID: "admin-2", EventType: models.NotificationEventTypeDefault, Email: true})
+	db.Create(&models.Lead{ID: 1, FirstName: "Dana", LastName: "Mine", Email: "dana@example.com", FUBLeadID: "fub-1", AssignedAgentID: "admin-1"})
+	db.Create(&models.Lead{ID: 2, FirstName: "Riley", LastName: "Theirs", Email: "riley@example.com", FUBLeadID: "fub-2", AssignedAgentID: "admin-2"})
+	db.Exec(`INSERT INTO behavioral_scores VALUES ('s1', 1, 85), ('s2', 2, 90)`)
+	db.Create(&models.PreListingItem{Address: "12 Oak St", Status: "lockbox_pending", IsOverdue: true, OverdueReason: "No lockbox after 14 days"})
+
+	email := &recordingDigestSender{}
+	digest := NewDailyDigestService(db, nil, "secret")
+	digest.email = email
+	digest.opportunities = staticOpportunities{
+		{LeadID: 2, LeadName: "Riley Theirs", Context: "Viewed 8 listings"},
+		{LeadID: 1, LeadName: "Dana Mine", Context: "Saved 3 listings", ActionSequence: []OpportunityAction{{Description: "Call now"}}},
+	}
+	digest.SetSchedule(7, time.UTC)
+	digest.SetBaseURL("https://example.com")
+	now := time.Date(2026, 10, 16, 6, 30, 0, 0, time.UTC)
+	digest.now = func() time.Time { return now }
+
+	if sent, err := digest.RunOnce(); err != nil || sent != 0 {
+		t.Fatalf("Expected nothing before the send hour, got %d (%v)", sent, err)
+	}
+	now = now.Add(time.Hour)
+	if sent, err := digest.RunOnce(); err != nil || sent != 1 {
+		t.Fatalf("Expected one digest, got %d (%v)", sent, err)
+	}
+	if email.to[0] != "agent@example.com" {
+		t.Errorf("Expected the opted-in admin's digest, got %v", email.to)
+	}
+	content := email.content[0]
+	for _, want := range []string{"Dana Mine", "Call now — Dana Mine", "12 Oak St", "/digest/unsubscribe?token="} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected the digest to contain %q", want)
+		}
+	}
+	if strings.Contains(content, "Riley") {
+		t.Error("Expected another agent's leads to be left out")
+	}
+
+	if sent, _ := digest.RunOnce(); sent != 0 {
+		t.Errorf("Expected the digest only once a day, got %d more", sent)
+	}
+
+	// The unsubscribe link turns the digest off
+	if _, err := digest.Unsubscribe("forged.token"); err != ErrInvalidUnsubscribeToken {
+		t.Errorf("Expected a forged token to be rejected, got %v", err)
+	}
+	link, _ := url.Parse(digest.UnsubscribeURL("admin-1"))
+	if adminUserID, err := digest.Unsubscribe(link.Query().Get("token")); err != nil || adminUserID != "admin-1" {
+		t.Fatalf("Expected the link to unsubscribe admin-1, got %q (%v)", adminUserID, err)
+	}
+	now = now.Add(24 * time.Hour)
+	if sent, _ := digest.RunOnce(); sent != 0 {
+		t.Errorf("Expected no digest after unsubscribing, got %d", sent)
+	}
+}
@@ -1,13 +1,26 @@
 package services
 
 import (
-	"gorm.io/gorm"
+	"fmt"
+	"sort"
+	"sync"
 	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
 )
 
+// Follow-ups are booked into free business-hours slots of this length
+const dailyPlanFollowUpSlot = 15 * time.Minute
+
 // DailyScheduleService handles daily schedule management
 type DailyScheduleService struct {
-	db *gorm.DB
+	db  *gorm.DB
+	now func() time.Time
+
+	// Generated plans by agent and date, until regenerated
+	plansMux sync.Mutex
+	plans    map[string]*DailyPlan
 }
 
 // ScheduleItem represents a schedule item
@@ -47,7 +60,9 @@ type DailyScheduleStats struct {
 // NewDailyScheduleService creates a new daily schedule service
 func NewDailyScheduleService(db *gorm.DB) *DailyScheduleService {
 	return &DailyScheduleService{
-		db: db,
+		db:    db,
+		now:   time.Now,
+		plans: make(map[string]*DailyPlan),
 	}
 }
 
@@ -166,3 +181,270 @@ func (dss *DailyScheduleService) DeleteTodoItem(id int) error {
 	// Mock deletion
 	return nil
 }
+
+// DailyPlan is an agent's generated plan for one day in business hours:
+// the day's showings, follow-up tasks booked around them, and overdue work
+type DailyPlan struct {
+	AgentID      string          `json:"agent_id"` // Empty for the whole team
+	Date         string          `json:"date"`     // YYYY-MM-DD in Timezone
+	Timezone     string          `json:"timezone"`
+	DayStart     time.Time       `json:"day_start"` // Business hours for the day
+	DayEnd       time.Time       `json:"day_end"`
+	BusinessDay  bool            `json:"business_day"`
+	Appointments []DailyPlanItem `json:"appointments"`
+	FollowUps    []DailyPlanItem `json:"follow_ups"`
+	Overdue      []DailyPlanItem `json:"overdue"`
+	Unscheduled  []DailyPlanItem `json:"unscheduled"` // Follow-ups with no free slot left in the day
+	GeneratedAt  time.Time       `json:"generated_at"`
+}
+
+// DailyPlanItem is a showing, task or pre-listing item in a daily plan
+type DailyPlanItem struct {
+	Kind        string     `json:"kind"` // showing, task, pre_listing
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Start       *time.Time `json:"start,omitempty"`
+	End         *time.Time `json:"end,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	Priority    int        `json:"priority,omitempty"`
+	LeadID      *int64     `json:"lead_id,omitempty"`
+	Status      string     `json:"status,omitempty"`
+}
+
+// GetDailyPlan returns the agent's plan for the date, generating it if it has
+// not been generated yet
+func (dss *DailyScheduleService) GetDailyPlan(agentID string, date time.Time) (*DailyPlan, error) {
+	dss.plansMux.Lock()
+	plan, ok := dss.plans[dailyPlanKey(agentID, date)]
+	dss.plansMux.Unlock()
+	if ok {
+		return plan, nil
+	}
+	return dss.RegenerateDailyPlan(agentID, date)
+}
+
+// RegenerateDailyPlan builds the agent's plan for the date from current
+// bookings and tasks, replacing any generated earlier
+func (dss *DailyScheduleService) RegenerateDailyPlan(agentID string, date time.Time) (*DailyPlan, error) {
+	plan, err := dss.buildDailyPlan(agentID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	today := dss.now().In(BusinessLocation()).Format("2006-01-02")
+	dss.plansMux.Lock()
+	defer dss.plansMux.Unlock()
+	for key, cached := range dss.plans {
+		if cached.Date < today {
+			delete(dss.plans, key)
+		}
+	}
+	dss.plans[dailyPlanKey(agentID, date)] = plan
+	return plan, nil
+}
+
+// buildDailyPlan lists the day's showings and overdue work, then books open
+// follow-up tasks into free slots from their optimal contact time onwards
+func (dss *DailyScheduleService) buildDailyPlan(agentID string, date time.Time) (*DailyPlan, error) {
+	loc := BusinessLocation()
+	now := dss.now().In(loc)
+	date = date.In(loc)
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	nextMidnight := midnight.AddDate(0, 0, 1)
+
+	plan := &DailyPlan{
+		AgentID:      agentID,
+		Date:         midnight.Format("2006-01-02"),
+		Timezone:     loc.String(),
+		DayStart:     time.Date(midnight.Year(), midnight.Month(), midnight.Day(), BusinessHoursStart, 0, 0, 0, loc),
+		DayEnd:       time.Date(midnight.Year(), midnight.Month(), midnight.Day(), BusinessHoursEnd, 0, 0, 0, loc),
+		BusinessDay:  midnight.Weekday() != time.Saturday && midnight.Weekday() != time.Sunday,
+		Appointments: []DailyPlanItem{},
+		FollowUps:    []DailyPlanItem{},
+		Overdue:      []DailyPlanItem{},
+		Unscheduled:  []DailyPlanItem{},
+		GeneratedAt:  now,
+	}
+
+	// Bookings carry no agent, so every agent sees the team's showings
+	var bookings []models.Booking
+	if err := dss.db.Where("showing_date >= ? AND showing_date < ?", midnight, nextMidnight).
+		Where("status NOT IN ?", []string{"cancelled", "canceled"}).
+		Order("showing_date").Find(&bookings).Error; err != nil {
+		return nil, fmt.Errorf("failed to load showings: %w", err)
+	}
+	busy := make([][2]time.Time, 0, len(bookings))
+	for _, booking := range bookings {
+		start := booking.ShowingDate.In(loc)
+		duration := time.Duration(booking.DurationMinutes) * time.Minute
+		if duration <= 0 {
+			duration = 30 * time.Minute
+		}
+		end := start.Add(duration)
+		busy = append(busy, [2]time.Time{start, end})
+
+		title := "Showing"
+		if booking.PropertyAddress != "" {
+			title = "Showing at " + booking.PropertyAddress
+		}
+		plan.Appointments = append(plan.Appointments, DailyPlanItem{
+			Kind:        "showing",
+			ID:          booking.ID,
+			Title:       title,
+			Description: booking.Notes,
+			Start:       &start,
+			End:         &end,
+			Status:      booking.Status,
+		})
+	}
+
+	// Work due before the plan starts is overdue: before now for today,
+	// before midnight for a later day
+	overdueBefore := midnight
+	if now.After(midnight) && now.Before(nextMidnight) {
+		overdueBefore = now
+	}
+
+	query := dss.db.Where("status = ? AND due_at < ?", models.TaskStatusOpen, nextMidnight).
+		Where("snoozed_until IS NULL OR snoozed_until < ?", nextMidnight)
+	if agentID != "" {
+		query = query.Where("assigned_to = ? OR assigned_to = ''", agentID)
+	}
+	var tasks []models.Task
+	if err := query.Order("priority DESC, due_at").Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+	engagement, err := dss.leadEngagement(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	var followUps []models.Task
+	for _, task := range tasks {
+		if task.DueAt.Before(overdueBefore) {
+			dueAt := task.DueAt.In(loc)
+			plan.Overdue = append(plan.Overdue, taskPlanItem(task, &dueAt))
+			continue
+		}
+		followUps = append(followUps, task)
+	}
+
+	var items []models.PreListingItem
+	if err := dss.db.Where("is_overdue = ?", true).Order("created_at").Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to load overdue pre-listings: %w", err)
+	}
+	for _, item := range items {
+		plan.Overdue = append(plan.Overdue, DailyPlanItem{
+			Kind:        "pre_listing",
+			ID:          item.ID,
+			Title:       item.Address,
+			Description: item.OverdueReason,
+			Status:      item.Status,
+		})
+	}
+
+	// Book follow-ups highest priority first, each no earlier than its lead's
+	// optimal contact time, the start of business hours, or now
+	earliest := plan.DayStart
+	if now.After(earliest) {
+		earliest = now.Truncate(dailyPlanFollowUpSlot).Add(dailyPlanFollowUpSlot)
+	}
+	for _, task := range followUps {
+		target := task.DueAt
+		if task.LeadID != nil {
+			if contactAt := OptimalNextActionTime(now, task.Confidence, engagement[*task.LeadID], "", nil); contactAt.Before(target) {
+				target = contactAt
+			}
+		}
+		target = AdjustToBusinessHours(target)
+		if target.Before(earliest) {
+			target = earliest
+		}
+
+		dueAt := task.DueAt.In(loc)
+		item := taskPlanItem(task, &dueAt)
+		start, ok := nextFreeSlot(target, plan.DayEnd, busy)
+		if !plan.BusinessDay || !ok {
+			plan.Unscheduled = append(plan.Unscheduled, item)
+			continue
+		}
+		end := start.Add(dailyPlanFollowUpSlot)
+		busy = append(busy, [2]time.Time{start, end})
+		item.Start, item.End = &start, &end
+		plan.FollowUps = append(plan.FollowUps, item)
+	}
+	sort.SliceStable(plan.FollowUps, func(i, j int) bool {
+		return plan.FollowUps[i].Start.Before(*plan.FollowUps[j].Start)
+	})
+	return plan, nil
+}
+
+// leadEngagement returns the engagement score (0-1) of each lead the tasks are for
+func (dss *DailyScheduleService) leadEngagement(tasks []models.Task) (map[int64]float64, error) {
+	var leadIDs []int64
+	for _, task := range tasks {
+		if task.LeadID != nil {
+			leadIDs = append(leadIDs, *task.LeadID)
+		}
+	}
+	engagement := make(map[int64]float64, len(leadIDs))
+	if len(leadIDs) == 0 {
+		return engagement, nil
+	}
+
+	var scores []struct {
+		LeadID          int64
+		EngagementScore int
+	}
+	if err := dss.db.Table("behavioral_scores").
+		Select("lead_id, MAX(engagement_score) AS engagement_score").
+		Where("lead_id IN ?", leadIDs).Group("lead_id").
+		Scan(&scores).Error; err != nil {
+		return nil, fmt.Errorf("failed to load lead engagement: %w", err)
+	}
+	for _, score := range scores {
+		engagement[score.LeadID] = float64(score.EngagementScore) / 100
+	}
+	return engagement, nil
+}
+
+// nextFreeSlot returns the first follow-up slot starting at or after from
+// that ends by dayEnd without overlapping a busy period
+func nextFreeSlot(from, dayEnd time.Time, busy [][2]time.Time) (time.Time, bool) {
+	start := from
+	for !start.Add(dailyPlanFollowUpSlot).After(dayEnd) {
+		end := start.Add(dailyPlanFollowUpSlot)
+		clash := false
+		for _, period := range busy {
+			if start.Before(period[1]) && period[0].Before(end) {
+				start = period[1]
+				clash = true
+				break
+			}
+		}
+		if !clash {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// taskPlanItem describes a command center task in a daily plan
+func taskPlanItem(task models.Task, dueAt *time.Time) DailyPlanItem {
+	return DailyPlanItem{
+		Kind:        "task",
+		ID:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		DueAt:       dueAt,
+		Priority:    task.Priority,
+		LeadID:      task.LeadID,
+		Status:      task.Status,
+	}
+}
+
+// dailyPlanKey identifies an agent's plan for a business day
+func dailyPlanKey(agentID string, date time.Time) string {
+	return agentID + "|" + date.In(BusinessLocation()).Format("2006-01-02")
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestDailyPlan_BooksFollowUpsAroundShowingsInBusinessHours(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Task{}, &models.PreListingItem{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	// bookings and behavioral_scores use postgres types, so create them by hand for sqlite
+	for _, ddl := range []string{
+		`CREATE TABLE bookings (id INTEGER PRIMARY KEY, property_address TEXT, showing_date DATETIME,
+			duration_minutes INTEGER, status TEXT, notes TEXT)`,
+		`CREATE TABLE behavioral_scores (id TEXT PRIMARY KEY, lead_id INTEGER, engagement_score INTEGER)`,
+	} {
+		if err := db.Exec(ddl).Error; err != nil {
+			t.Fatalf("Failed to create table: %v", err)
+		}
+	}
+
+	central := BusinessLocation()
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, central)
+	}
+	db.Exec(`INSERT INTO bookings VALUES (1, '12 Oak St', ?, 45, 'scheduled', ''), (2, '9 Elm St', ?, 30, 'cancelled', ''),
+		(3, '4 Pine St', ?, 30, 'scheduled', '')`, at(15, 10, 15).UTC(), at(15, 14, 0).UTC(), at(16, 10, 0).UTC())
+	db.Exec(`INSERT INTO behavioral_scores VALUES ('s1', 7, 90)`)
+
+	leadID := int64(7)
+	db.Create(&models.Task{Title: "Overdue call", AssignedTo: "agent-a", Priority: 9, Status: models.TaskStatusOpen, DueAt: at(14, 15, 0)})
+	db.Create(&models.Task{Title: "Call hot lead", AssignedTo: "agent-a", LeadID: &leadID, Priority: 8, Confidence: 0.9, Status: models.TaskStatusOpen, DueAt: at(15, 17, 0)})
+	db.Create(&models.Task{Title: "Send comps", AssignedTo: "agent-a", Priority: 5, Status: models.TaskStatusOpen, DueAt: at(15, 11, 0)})
+	db.Create(&models.Task{Title: "Someone else's", AssignedTo: "agent-b", Priority: 10, Status: models.TaskStatusOpen, DueAt: at(15, 12, 0)})
+	db.Create(&models.Task{Title: "End of day", Priority: 3, Status: models.TaskStatusOpen, DueAt: at(15, 17, 50)})
+	db.Create(&models.PreListingItem{Address: "3 Birch St", Status: "lockbox_pending", IsOverdue: true, OverdueReason: "No lockbox after 14 days"})
+
+	schedule := NewDailyScheduleService(db)
+	schedule.now = func() time.Time { return at(15, 10, 5).UTC() }
+
+	plan, err := schedule.GetDailyPlan("agent-a", at(15, 0, 0))
+	if err != nil {
+		t.Fatalf("Failed to build plan: %v", err)
+	}
+	if plan.Date != "2026-10-15" || !plan.DayStart.Equal(at(15, 9, 0)) || !plan.DayEnd.Equal(at(15, 18, 0)) {
+		t.Errorf("Expected Central business hours on 2026-10-15, got %s %v-%v", plan.Date, plan.DayStart, plan.DayEnd)
+	}
+	if len(plan.Appointments) != 1 || plan.Appointments[0].ID != 1 {
+		t.Errorf("Expected only the day's scheduled showing, got %+v", plan.Appointments)
+	}
+	if len(plan.Overdue) != 2 || plan.Overdue[0].Title != "Overdue call" || plan.Overdue[1].Kind != "pre_listing" {
+		t.Errorf("Expected the overdue task and pre-listing, got %+v", plan.Overdue)
+	}
+
+	// The engaged lead is due now, but the showing runs until 11:00
+	if len(plan.FollowUps) != 2 {
+		t.Fatalf("Expected two booked follow-ups, got %+v", plan.FollowUps)
+	}
+	if plan.FollowUps[0].Title != "Call hot lead" || !plan.FollowUps[0].Start.Equal(at(15, 11, 0)) {
+		t.Errorf("Expected the hot lead call right after the showing, got %+v", plan.FollowUps[0])
+	}
+	if plan.FollowUps[1].Title != "Send comps" || !plan.FollowUps[1].Start.Equal(at(15, 11, 15)) {
+		t.Errorf("Expected comps in the next free slot, got %+v", plan.FollowUps[1])
+	}
+	if len(plan.Unscheduled) != 1 || plan.Unscheduled[0].Title != "End of day" {
+		t.Errorf("Expected the task past business hours to be unscheduled, got %+v", plan.Unscheduled)
+	}
+
+	db.Create(&models.Task{Title: "New task", AssignedTo: "agent-a", Priority: 4, Status: models.TaskStatusOpen, DueAt: at(15, 13, 0)})
+	if cached, _ := schedule.GetDailyPlan("agent-a", at(15, 12, 0)); cached != plan {
+		t.Errorf("Expected the generated plan until it is regenerated")
+	}
+	plan, err = schedule.RegenerateDailyPlan("agent-a", at(15, 0, 0))
+	if err != nil || len(plan.FollowUps) != 3 {
+		t.Errorf("Expected the regenerated plan to include the new task, got %+v (%v)", plan, err)
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"log"
 	"strings"
 	"time"
@@ -12,8 +13,21 @@ type DashboardStatsService struct {
 	db           *gorm.DB
 	spiderwebAI  *SpiderwebAIOrchestrator
 	cache        *IntelligenceCacheService
+
+	analyticsCache    dashboardStatsCache
+	analyticsCacheTTL time.Duration
+}
+
+// dashboardStatsCache is the part of AnalyticsCacheService the dashboard stats depend on
+type dashboardStatsCache interface {
+	GetDashboardStats(ctx context.Context, statType string) (map[string]interface{}, bool)
+	SetDashboardStats(ctx context.Context, statType string, stats map[string]interface{}, ttl time.Duration) error
+	InvalidateDashboardStats(ctx context.Context) error
 }
 
+// DefaultDashboardCacheTTL is how long dashboard stats are served from the analytics cache
+const DefaultDashboardCacheTTL = 60 * time.Second
+
 func NewDashboardStatsService(
 	db *gorm.DB,
 	spiderwebAI *SpiderwebAIOrchestrator,
@@ -26,7 +40,60 @@ func NewDashboardStatsService(
 	}
 }
 
+// SetAnalyticsCache enables the short-lived analytics cache in front of stat computation
+func (dss *DashboardStatsService) SetAnalyticsCache(cache *AnalyticsCacheService, ttl time.Duration) {
+	if cache == nil {
+		dss.analyticsCache = nil
+		return
+	}
+	if ttl <= 0 {
+		ttl = DefaultDashboardCacheTTL
+	}
+	dss.analyticsCache = cache
+	dss.analyticsCacheTTL = ttl
+}
+
+// InvalidateCache forces the next request for each stat type to recompute.
+// Only dashboard entries are evicted; other cached intelligence is kept.
+func (dss *DashboardStatsService) InvalidateCache() error {
+	if dss.analyticsCache != nil {
+		if err := dss.analyticsCache.InvalidateDashboardStats(context.Background()); err != nil {
+			return err
+		}
+	}
+	if dss.cache != nil && dss.cache.IsAvailable() {
+		return dss.cache.InvalidateDashboard()
+	}
+	return nil
+}
+
+// withAnalyticsCache serves statType from the analytics cache, computing and writing back on miss
+func (dss *DashboardStatsService) withAnalyticsCache(statType string, compute func() (map[string]interface{}, error)) (map[string]interface{}, error) {
+	if dss.analyticsCache == nil {
+		return compute()
+	}
+
+	ctx := context.Background()
+	if cached, ok := dss.analyticsCache.GetDashboardStats(ctx, statType); ok {
+		return cached, nil
+	}
+
+	stats, err := compute()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dss.analyticsCache.SetDashboardStats(ctx, statType, stats, dss.analyticsCacheTTL); err != nil {
+		log.Printf("⚠️ Failed to cache dashboard %s stats: %v", statType, err)
+	}
+	return stats, nil
+}
+
 func (dss *DashboardStatsService) GetLiveStats() (map[string]interface{}, error) {
+	return dss.withAnalyticsCache("live", dss.computeLiveStats)
+}
+
+func (dss *DashboardStatsService) computeLiveStats() (map[string]interface{}, error) {
 	var activeUsers int64
 	var unreadMessages int64
 	var currentShowings int64
@@ -124,6 +191,10 @@ func (dss *DashboardStatsService) GetLiveStats() (map[string]interface{}, error)
 }
 
 func (dss *DashboardStatsService) GetHotStats() (map[string]interface{}, error) {
+	return dss.withAnalyticsCache("hot", dss.getHotStats)
+}
+
+func (dss *DashboardStatsService) getHotStats() (map[string]interface{}, error) {
 	if dss.cache != nil && dss.cache.IsAvailable() {
 		cached, err := dss.cache.GetDashboardHot()
 		if err == nil {
@@ -196,6 +267,10 @@ func (dss *DashboardStatsService) computeHotStats() (map[string]interface{}, err
 }
 
 func (dss *DashboardStatsService) GetWarmStats() (map[string]interface{}, error) {
+	return dss.withAnalyticsCache("warm", dss.getWarmStats)
+}
+
+func (dss *DashboardStatsService) getWarmStats() (map[string]interface{}, error) {
 	if dss.cache != nil && dss.cache.IsAvailable() {
 		cached, err := dss.cache.GetDashboardWarm()
 		if err == nil {
@@ -269,6 +344,10 @@ func (dss *DashboardStatsService) computeWarmStats() (map[string]interface{}, er
 }
 
 func (dss *DashboardStatsService) GetDailyStats() (map[string]interface{}, error) {
+	return dss.withAnalyticsCache("daily", dss.getDailyStats)
+}
+
+func (dss *DashboardStatsService) getDailyStats() (map[string]interface{}, error) {
 	if dss.cache != nil && dss.cache.IsAvailable() {
 		cached, err := dss.cache.GetDashboardDaily()
 		if err == nil {
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// memoryDashboardCache is an in-process stand-in for the Redis analytics cache
+type memoryDashboardCache struct {
+	stats map[string]map[string]interface{}
+	ttls  map[string]time.Duration
+}
+
+func (m *memoryDashboardCache) GetDashboardStats(ctx context.Context, statType string) (map[string]interface{}, bool) {
+	stats, ok := m.stats[statType]
+	return stats, ok
+}
+
+func (m *memoryDashboardCache) SetDashboardStats(ctx context.Context, statType string, stats map[string]interface{}, ttl time.Duration) error {
+	m.stats[statType] = stats
+	m.ttls[statType] = ttl
+	return nil
+}
+
+func (m *memoryDashboardCache) InvalidateDashboardStats(ctx context.Context) error {
+	m.stats = make(map[string]map[string]interface{})
+	return nil
+}
+
+func TestDashboardStats_CachesUntilInvalidated(t *testing.T) {
+	cache := &memoryDashboardCache{stats: make(map[string]map[string]interface{}), ttls: make(map[string]time.Duration)}
+	service := NewDashboardStatsService(nil, nil, nil)
+	service.analyticsCache = cache
+	service.analyticsCacheTTL = 30 * time.Second
+
+	computed := 0
+	compute := func() (map[string]interface{}, error) {
+		computed++
+		return map[string]interface{}{"run": computed}, nil
+	}
+
+	stats, err := service.withAnalyticsCache("hot", compute)
+	if err != nil || computed != 1 || stats["run"] != 1 {
+		t.Fatalf("Expected a miss to compute, got %v (computed %d, err %v)", stats, computed, err)
+	}
+	if cached, ok := cache.stats["hot"]; !ok || cached["run"] != 1 || cache.ttls["hot"] != 30*time.Second {
+		t.Fatalf("Expected the computed stats written back with the TTL, got %v / %v", cached, cache.ttls["hot"])
+	}
+
+	if stats, _ := service.withAnalyticsCache("hot", compute); computed != 1 || stats["run"] != 1 {
+		t.Errorf("Expected a hit to be served from the cache, computed %d times", computed)
+	}
+	if _, err := service.withAnalyticsCache("warm", compute); err != nil || computed != 2 {
+		t.Errorf("Expected each stat type cached separately, computed %d times", computed)
+	}
+
+	if err := service.InvalidateCache(); err != nil {
+		t.Fatalf("InvalidateCache failed: %v", err)
+	}
+	if stats, _ := service.withAnalyticsCache("hot", compute); computed != 3 || stats["run"] != 3 {
+		t.Errorf("Expected invalidation to force a recompute, got %v (computed %d)", stats, computed)
+	}
+}
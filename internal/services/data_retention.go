@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// dataRetentionBatch caps how many events or leads one statement touches
+const dataRetentionBatch = 500
+
+// dataRetentionSample is how many affected lead IDs a report lists
+const dataRetentionSample = 50
+
+// DataRetentionSettings is the retention policy the data retention job enforces
+type DataRetentionSettings struct {
+	EventRetentionDays   int  // Behavioral events older than this are deleted; 0 keeps them
+	LeadInactivityMonths int  // Leads with no activity for this long are anonymized; 0 keeps them
+	DryRun               bool // Report what would be affected without changing anything
+}
+
+// DataRetentionReport is what one retention run purged and anonymized, or
+// would have in a dry run
+type DataRetentionReport struct {
+	RunID            string     `json:"run_id"`
+	DryRun           bool       `json:"dry_run"`
+	EventCutoff      *time.Time `json:"event_cutoff,omitempty"`
+	InactivityCutoff *time.Time `json:"inactivity_cutoff,omitempty"`
+	EventsPurged     int64      `json:"events_purged"`
+	LeadsAnonymized  int64      `json:"leads_anonymized"`
+	SampleLeadIDs    []uint     `json:"sample_lead_ids"`
+	StartedAt        time.Time  `json:"started_at"`
+	CompletedAt      time.Time  `json:"completed_at"`
+}
+
+// DataRetentionService purges old behavioral events and anonymizes inactive
+// leads. Leads are anonymized rather than deleted because approvals, closings
+// and analytics still reference them.
+type DataRetentionService struct {
+	db       *gorm.DB
+	settings DataRetentionSettings
+	cache    *IntelligenceCacheService
+	now      func() time.Time
+}
+
+// NewDataRetentionService creates a data retention service enforcing settings
+func NewDataRetentionService(db *gorm.DB, settings DataRetentionSettings) *DataRetentionService {
+	return &DataRetentionService{db: db, settings: settings, now: time.Now}
+}
+
+// SetIntelligenceCache evicts anonymized leads' cached intelligence
+func (s *DataRetentionService) SetIntelligenceCache(cache *IntelligenceCacheService) {
+	s.cache = cache
+}
+
+// Settings returns the retention policy
+func (s *DataRetentionService) Settings() DataRetentionSettings {
+	return s.settings
+}
+
+// Start runs the retention policy every interval until ctx is cancelled
+func (s *DataRetentionService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if report, err := s.Run(); err != nil {
+				log.Printf("⚠️ Data retention run failed: %v", err)
+			} else if report.DryRun {
+				log.Printf("🧪 Data retention dry run: would purge %d events and anonymize %d leads", report.EventsPurged, report.LeadsAnonymized)
+			} else if report.EventsPurged > 0 || report.LeadsAnonymized > 0 {
+				log.Printf("🧹 Data retention purged %d events and anonymized %d leads", report.EventsPurged, report.LeadsAnonymized)
+			}
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 Data retention stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	log.Printf("🕐 Data retention started (every %v, events kept %d days, leads anonymized after %d months inactive, dry run: %v)",
+		interval, s.settings.EventRetentionDays, s.settings.LeadInactivityMonths, s.settings.DryRun)
+}
+
+// Run applies the retention policy, or only reports on it when the policy is a dry run
+func (s *DataRetentionService) Run() (*DataRetentionReport, error) {
+	return s.run(s.settings.DryRun)
+}
+
+// Preview reports what the retention policy would purge and anonymize now,
+// without changing anything
+func (s *DataRetentionService) Preview() (*DataRetentionReport, error) {
+	return s.run(true)
+}
+
+// RecentLogs returns the latest retention actions, newest first
+func (s *DataRetentionService) RecentLogs(limit int) ([]models.DataRetentionLog, error) {
+	var logs []models.DataRetentionLog
+	err := s.db.Order("created_at DESC, id DESC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+func (s *DataRetentionService) run(dryRun bool) (*DataRetentionReport, error) {
+	now := s.now()
+	report := &DataRetentionReport{RunID: uuid.New().String(), DryRun: dryRun, StartedAt: now, SampleLeadIDs: []uint{}}
+
+	if s.settings.EventRetentionDays > 0 {
+		cutoff := now.AddDate(0, 0, -s.settings.EventRetentionDays)
+		report.EventCutoff = &cutoff
+		purged, err := s.purgeEvents(report.RunID, cutoff, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		report.EventsPurged = purged
+	}
+
+	if s.settings.LeadInactivityMonths > 0 {
+		cutoff := now.AddDate(0, -s.settings.LeadInactivityMonths, 0)
+		report.InactivityCutoff = &cutoff
+		if err := s.anonymizeLeads(report, cutoff, dryRun); err != nil {
+			return nil, err
+		}
+	}
+
+	report.CompletedAt = s.now()
+	return report, nil
+}
+
+// purgeEvents deletes behavioral events created before cutoff, in batches
+func (s *DataRetentionService) purgeEvents(runID string, cutoff time.Time, dryRun bool) (int64, error) {
+	if dryRun {
+		var count int64
+		if err := s.db.Model(&models.BehavioralEvent{}).Where("created_at < ?", cutoff).Count(&count).Error; err != nil {
+			return 0, fmt.Errorf("failed to count expired events: %w", err)
+		}
+		return count, nil
+	}
+
+	var purged int64
+	for {
+		expired := s.db.Model(&models.BehavioralEvent{}).Select("id").Where("created_at < ?", cutoff).Limit(dataRetentionBatch)
+		result := s.db.Where("id IN (?)", expired).Delete(&models.BehavioralEvent{})
+		if result.Error != nil {
+			return purged, fmt.Errorf("failed to purge expired events: %w", result.Error)
+		}
+		purged += result.RowsAffected
+		if result.RowsAffected < dataRetentionBatch {
+			break
+		}
+	}
+
+	if purged > 0 {
+		if err := s.db.Create(&models.DataRetentionLog{
+			RunID:       runID,
+			Action:      models.RetentionActionPurgeEvents,
+			RecordCount: purged,
+			Cutoff:      cutoff,
+			Details:     fmt.Sprintf("deleted behavioral events older than %d days", s.settings.EventRetentionDays),
+		}).Error; err != nil {
+			log.Printf("⚠️ Failed to record event purge of %d events: %v", purged, err)
+		}
+	}
+	return purged, nil
+}
+
+// inactiveLeads selects leads not yet anonymized with no update, event or
+// session since cutoff
+func (s *DataRetentionService) inactiveLeads(cutoff time.Time) *gorm.DB {
+	return s.db.Model(&models.Lead{}).
+		Where("status <> ? AND updated_at < ?", models.LeadStatusAnonymized, cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM behavioral_events be WHERE be.lead_id = leads.id AND be.created_at >= ?)", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM behavioral_sessions bs WHERE bs.lead_id = leads.id AND bs.start_time >= ?)", cutoff)
+}
+
+// anonymizeLeads strips personal details from inactive leads and their
+// browsing history, keeping the rows and their IDs
+func (s *DataRetentionService) anonymizeLeads(report *DataRetentionReport, cutoff time.Time, dryRun bool) error {
+	if dryRun {
+		if err := s.inactiveLeads(cutoff).Count(&report.LeadsAnonymized).Error; err != nil {
+			return fmt.Errorf("failed to count inactive leads: %w", err)
+		}
+		if err := s.inactiveLeads(cutoff).Order("id").Limit(dataRetentionSample).Pluck("id", &report.SampleLeadIDs).Error; err != nil {
+			return fmt.Errorf("failed to list inactive leads: %w", err)
+		}
+		return nil
+	}
+
+	var afterID uint
+	for {
+		var leadIDs []uint
+		if err := s.inactiveLeads(cutoff).Where("id > ?", afterID).Order("id").Limit(dataRetentionBatch).Pluck("id", &leadIDs).Error; err != nil {
+			return fmt.Errorf("failed to list inactive leads: %w", err)
+		}
+		if len(leadIDs) == 0 {
+			return nil
+		}
+		afterID = leadIDs[len(leadIDs)-1]
+
+		for _, leadID := range leadIDs {
+			details := fmt.Sprintf("no activity for %d months", s.settings.LeadInactivityMonths)
+			if err := s.anonymizeLead(report.RunID, leadID, cutoff, details); err != nil {
+				log.Printf("⚠️ Failed to anonymize lead %d: %v", leadID, err)
+				continue
+			}
+			report.LeadsAnonymized++
+			if len(report.SampleLeadIDs) < dataRetentionSample {
+				report.SampleLeadIDs = append(report.SampleLeadIDs, leadID)
+			}
+		}
+
+		if s.cache != nil {
+			ids := make([]int64, len(leadIDs))
+			for i, id := range leadIDs {
+				ids[i] = int64(id)
+			}
+			if err := s.cache.InvalidateLeads(ids); err != nil {
+				log.Printf("⚠️ Failed to evict anonymized leads' intelligence: %v", err)
+			}
+		}
+		if len(leadIDs) < dataRetentionBatch {
+			return nil
+		}
+	}
+}
+
+// AnonymizeLead strips one lead's personal details outside the scheduled run,
+// such as for an erasure request, logging it under runID with the reason given
+func (s *DataRetentionService) AnonymizeLead(runID string, leadID uint, reason string) error {
+	if err := s.anonymizeLead(runID, leadID, s.now(), reason); err != nil {
+		return err
+	}
+	if s.cache != nil {
+		if err := s.cache.InvalidateLead(int64(leadID)); err != nil {
+			log.Printf("⚠️ Failed to evict anonymized lead %d's intelligence: %v", leadID, err)
+		}
+	}
+	return nil
+}
+
+func (s *DataRetentionService) anonymizeLead(runID string, leadID uint, cutoff time.Time, details string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Lead{}).Where("id = ?", leadID).Updates(map[string]interface{}{
+			"first_name":    "Anonymized",
+			"last_name":     "Lead",
+			"email":         fmt.Sprintf("anonymized-%d@invalid", leadID),
+			"phone":         "",
+			"city":          "",
+			"state":         "",
+			"tags":          models.StringArray{},
+			"custom_fields": models.JSONB{},
+			"status":        models.LeadStatusAnonymized,
+			"version":       gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+
+		events := tx.Model(&models.BehavioralEvent{}).Where("lead_id = ?", leadID).
+			Updates(map[string]interface{}{"ip_address": "", "user_agent": ""})
+		if events.Error != nil {
+			return events.Error
+		}
+		sessions := tx.Model(&models.BehavioralSession{}).Where("lead_id = ?", leadID).
+			Updates(map[string]interface{}{"ip_address": "", "user_agent": "", "referrer": ""})
+		if sessions.Error != nil {
+			return sessions.Error
+		}
+
+		return tx.Create(&models.DataRetentionLog{
+			RunID:       runID,
+			Action:      models.RetentionActionAnonymizeLead,
+			LeadID:      &leadID,
+			RecordCount: 1 + events.RowsAffected + sessions.RowsAffected,
+			Cutoff:      cutoff,
+			Details:     fmt.Sprintf("%s; scrubbed %d events and %d sessions", details, events.RowsAffected, sessions.RowsAffected),
+		}).Error
+	})
+}
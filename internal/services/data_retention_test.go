@@ -0,0 +1,80 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestDataRetention_DryRunThenEnforce(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Lead{}, &models.BehavioralEvent{}, &models.BehavioralSession{}, &models.DataRetentionLog{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	now := time.Now()
+	old := now.AddDate(-3, 0, 0)
+	stale := models.Lead{FirstName: "Ann", LastName: "Lee", Email: "ann@example.com", Phone: "+17135550100", FUBLeadID: "fub-1"}
+	active := models.Lead{FirstName: "Bob", LastName: "Ray", Email: "bob@example.com", FUBLeadID: "fub-2"}
+	db.Create(&stale)
+	db.Create(&active)
+	db.Model(&models.Lead{}).Where("id IN ?", []uint{stale.ID, active.ID}).UpdateColumn("updated_at", old)
+
+	db.Create(&models.BehavioralEvent{LeadID: int64(stale.ID), EventType: "viewed", IPAddress: "10.0.0.1", CreatedAt: old})
+	db.Create(&models.BehavioralEvent{LeadID: int64(stale.ID), EventType: "saved", IPAddress: "10.0.0.1", CreatedAt: now.AddDate(-1, -6, 0)})
+	db.Create(&models.BehavioralEvent{LeadID: int64(active.ID), EventType: "viewed", CreatedAt: now.AddDate(0, -1, 0)})
+	db.Create(&models.BehavioralSession{ID: "s1", LeadID: int64(stale.ID), StartTime: old, IPAddress: "10.0.0.1"})
+
+	retention := NewDataRetentionService(db, DataRetentionSettings{EventRetentionDays: 730, LeadInactivityMonths: 12, DryRun: true})
+
+	report, err := retention.Run()
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if report.EventsPurged != 1 || report.LeadsAnonymized != 1 || len(report.SampleLeadIDs) != 1 || report.SampleLeadIDs[0] != stale.ID {
+		t.Fatalf("Expected one event and Ann reported, got %+v", report)
+	}
+	var events, logs int64
+	db.Model(&models.BehavioralEvent{}).Count(&events)
+	db.Model(&models.DataRetentionLog{}).Count(&logs)
+	if events != 3 || logs != 0 {
+		t.Fatalf("Expected the dry run to change nothing, got %d events and %d logs", events, logs)
+	}
+
+	retention.settings.DryRun = false
+	if report, err = retention.Run(); err != nil || report.EventsPurged != 1 || report.LeadsAnonymized != 1 {
+		t.Fatalf("Expected one event purged and Ann anonymized, got %+v (%v)", report, err)
+	}
+
+	var lead models.Lead
+	db.First(&lead, stale.ID)
+	if lead.Email == "ann@example.com" || lead.Phone != "" || lead.FirstName == "Ann" || lead.Status != models.LeadStatusAnonymized || lead.FUBLeadID != "fub-1" {
+		t.Errorf("Expected Ann's details removed and the row kept, got %+v", lead)
+	}
+	var session models.BehavioralSession
+	db.First(&session, "id = ?", "s1")
+	if session.IPAddress != "" {
+		t.Errorf("Expected Ann's session IP scrubbed, got %q", session.IPAddress)
+	}
+	var bob models.Lead
+	db.First(&bob, active.ID)
+	if bob.Email != "bob@example.com" {
+		t.Errorf("Expected Bob left alone, got %+v", bob)
+	}
+
+	recent, err := retention.RecentLogs(10)
+	if err != nil || len(recent) != 2 {
+		t.Fatalf("Expected a purge and an anonymization logged, got %d (%v)", len(recent), err)
+	}
+
+	// Already anonymized leads are not anonymized again
+	if report, _ = retention.Run(); report.LeadsAnonymized != 0 || report.EventsPurged != 0 {
+		t.Errorf("Expected nothing left to do, got %+v", report)
+	}
+}
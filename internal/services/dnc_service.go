@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRecipientOnDNC is returned when a send is refused because the recipient
+// is on the do-not-contact list
+var ErrRecipientOnDNC = errors.New("recipient is on the do-not-contact list")
+
+// DNCImportResult summarizes a do-not-contact list import
+type DNCImportResult struct {
+	Phones       int   `json:"phones"`
+	Emails       int   `json:"emails"`
+	Added        int   `json:"added"`
+	Duplicates   int   `json:"duplicates"`
+	Invalid      int   `json:"invalid"`
+	LeadsFlagged int64 `json:"leads_flagged"`
+}
+
+// DNCService imports the do-not-contact list, flags matching leads and gates
+// outbound email and SMS against it. Entries are stored as blind index hashes.
+type DNCService struct {
+	db                *gorm.DB
+	encryptionManager *security.EncryptionManager
+}
+
+// NewDNCService creates a do-not-contact list service
+func NewDNCService(db *gorm.DB, encryptionManager *security.EncryptionManager) *DNCService {
+	return &DNCService{db: db, encryptionManager: encryptionManager}
+}
+
+// ImportCSV adds every phone number and email address in the CSV to the list,
+// in any column and with or without a header row, then flags matching leads
+func (s *DNCService) ImportCSV(r io.Reader, source, importedBy string) (DNCImportResult, error) {
+	var result DNCImportResult
+	if s.encryptionManager == nil {
+		return result, fmt.Errorf("encryption manager required to hash DNC entries")
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	seen := map[string]bool{}
+	var entries []models.DNCEntry
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("row %d: %w", row, err)
+		}
+
+		for _, cell := range record {
+			value := strings.TrimSpace(strings.TrimPrefix(cell, "\ufeff"))
+			if value == "" {
+				continue
+			}
+			kind, hash := s.hashContact(value)
+			if hash == "" {
+				// Header cells like "phone" or "email" land here too
+				result.Invalid++
+				continue
+			}
+			if kind == models.DNCKindEmail {
+				result.Emails++
+			} else {
+				result.Phones++
+			}
+			if seen[kind+hash] {
+				result.Duplicates++
+				continue
+			}
+			seen[kind+hash] = true
+			entries = append(entries, models.DNCEntry{
+				Kind:       kind,
+				ValueHash:  hash,
+				IndexKeyID: s.encryptionManager.IndexKeyID(),
+				Source:     source,
+				ImportedBy: importedBy,
+			})
+		}
+	}
+
+	for start := 0; start < len(entries); start += 500 {
+		end := start + 500
+		if end > len(entries) {
+			end = len(entries)
+		}
+		insert := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(entries[start:end])
+		if insert.Error != nil {
+			return result, fmt.Errorf("failed to store DNC entries: %w", insert.Error)
+		}
+		result.Added += int(insert.RowsAffected)
+	}
+	result.Duplicates += len(entries) - result.Added
+
+	flagged, err := s.FlagListedLeads()
+	if err != nil {
+		return result, err
+	}
+	result.LeadsFlagged = flagged
+	return result, nil
+}
+
+// FlagListedLeads sets on_dnc_list on every lead whose email or phone is on
+// the list and returns how many leads were newly flagged
+func (s *DNCService) FlagListedLeads() (int64, error) {
+	emails := s.db.Model(&models.DNCEntry{}).Select("value_hash").Where("kind = ?", models.DNCKindEmail)
+	phones := s.db.Model(&models.DNCEntry{}).Select("value_hash").Where("kind = ?", models.DNCKindPhone)
+
+	result := s.db.Model(&models.LeadReengagement{}).
+		Where("on_dnc_list = ?", false).
+		Where("(email_hash <> '' AND email_hash IN (?)) OR (phone_hash <> '' AND phone_hash IN (?))", emails, phones).
+		Update("on_dnc_list", true)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to flag DNC leads: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// StartRecheck flags leads against the list every interval, catching leads
+// imported or edited after the list was loaded, until ctx is cancelled
+func (s *DNCService) StartRecheck(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if flagged, err := s.FlagListedLeads(); err != nil {
+				log.Printf("⚠️ DNC recheck failed: %v", err)
+			} else if flagged > 0 {
+				log.Printf("🚫 DNC recheck flagged %d leads", flagged)
+			}
+
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 DNC recheck stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	log.Printf("🕐 DNC recheck started (every %v)", interval)
+}
+
+// IsListed reports whether the email or phone number is on the list. Empty
+// values are ignored.
+func (s *DNCService) IsListed(email, phone string) (bool, error) {
+	if s.encryptionManager == nil {
+		return false, nil
+	}
+	emailHash := s.encryptionManager.EmailBlindIndex(email)
+	phoneHash := s.encryptionManager.PhoneBlindIndex(phone)
+	if emailHash == "" && phoneHash == "" {
+		return false, nil
+	}
+
+	var count int64
+	err := s.db.Model(&models.DNCEntry{}).
+		Where("(kind = ? AND value_hash = ?) OR (kind = ? AND value_hash = ?)",
+			models.DNCKindEmail, emailHash, models.DNCKindPhone, phoneHash).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CheckSMS refuses an SMS to a listed number, logging the suppressed attempt.
+// A failed lookup also refuses the send, since DNC must never be bypassed.
+func (s *DNCService) CheckSMS(phone, source string, leadID *uint) error {
+	listed, err := s.IsListed("", phone)
+	if err != nil {
+		return fmt.Errorf("failed to check DNC list: %w", err)
+	}
+	if !listed {
+		return nil
+	}
+	s.LogSuppression("sms", source, phone, leadID, ErrRecipientOnDNC.Error())
+	return ErrRecipientOnDNC
+}
+
+// CheckEmail refuses an email to a listed address, logging the suppressed attempt
+func (s *DNCService) CheckEmail(email, source string, leadID *uint) error {
+	listed, err := s.IsListed(email, "")
+	if err != nil {
+		return fmt.Errorf("failed to check DNC list: %w", err)
+	}
+	if !listed {
+		return nil
+	}
+	s.LogSuppression("email", source, email, leadID, ErrRecipientOnDNC.Error())
+	return ErrRecipientOnDNC
+}
+
+// LogSuppression records a refused contact attempt for audit. The recipient is
+// stored as its blind index hash.
+func (s *DNCService) LogSuppression(channel, source, recipient string, leadID *uint, reason string) {
+	entry := models.DNCSuppressionLog{
+		Channel:            channel,
+		Source:             source,
+		LeadReengagementID: leadID,
+		Reason:             reason,
+	}
+	if s.encryptionManager != nil {
+		_, entry.RecipientHash = s.hashContact(recipient)
+	}
+	if err := s.db.Create(&entry).Error; err != nil {
+		log.Printf("⚠️ Failed to log DNC suppression: %v", err)
+		return
+	}
+	log.Printf("🚫 %s to DNC-listed recipient suppressed (%s)", channel, source)
+}
+
+// hashContact classifies a value as an email or phone number and returns its
+// blind index, or an empty hash if it is neither
+func (s *DNCService) hashContact(value string) (string, string) {
+	if strings.Contains(value, "@") {
+		address, err := mail.ParseAddress(value)
+		if err != nil {
+			return models.DNCKindEmail, ""
+		}
+		return models.DNCKindEmail, s.encryptionManager.EmailBlindIndex(address.Address)
+	}
+	if normalizePhoneDigits(value) == "" {
+		return models.DNCKindPhone, ""
+	}
+	return models.DNCKindPhone, s.encryptionManager.PhoneBlindIndex(value)
+}
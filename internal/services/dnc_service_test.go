@@ -0,0 +1,92 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestDNCService_ImportFlagsLeadsAndGatesCampaignSends(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&security.EncryptionKey{}, &models.LeadReengagement{}, &models.CampaignTemplate{},
+		&models.CampaignExecution{}, &models.DNCEntry{}, &models.DNCSuppressionLog{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	t.Setenv("ENCRYPTION_KEY", newTestEncryptionKey(t))
+	em, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create encryption manager: %v", err)
+	}
+
+	newLead := func(fubID, email, phone string) models.LeadReengagement {
+		lead := models.LeadReengagement{FUBContactID: fubID, Email: security.EncryptedString(email), ConsentStatus: models.ConsentExpress}
+		lead.SetBlindIndexes(em, "", "", email, phone)
+		if err := db.Create(&lead).Error; err != nil {
+			t.Fatalf("Failed to store lead: %v", err)
+		}
+		return lead
+	}
+	byPhone := newLead("fub-1", "one@example.com", "(713) 555-0100")
+	byEmail := newLead("fub-2", "Two@Example.com", "713-555-0200")
+	newLead("fub-3", "three@example.com", "713-555-0300")
+
+	service := NewDNCService(db, em)
+	csv := "phone,email\n+1 713 555 0100,\n,two@example.com\n7135550100,not-an-email@\n"
+	result, err := service.ImportCSV(strings.NewReader(csv), "test", "admin")
+	if err != nil {
+		t.Fatalf("ImportCSV failed: %v", err)
+	}
+	if result.Added != 2 || result.Duplicates != 1 || result.Invalid != 3 || result.LeadsFlagged != 2 {
+		t.Errorf("Unexpected import result %+v", result)
+	}
+	var flagged []models.LeadReengagement
+	db.Where("on_dnc_list = ?", true).Order("id").Find(&flagged)
+	if len(flagged) != 2 || flagged[0].ID != byPhone.ID || flagged[1].ID != byEmail.ID {
+		t.Errorf("Expected the phone and email matches to be flagged, got %d leads", len(flagged))
+	}
+
+	// A lead imported after the list was loaded is caught at send time
+	late := newLead("fub-4", "two@example.com", "")
+	template := models.CampaignTemplate{Name: "reengage", EmailNumber: 1, Subject: "Hello", Body: "<p>Hi</p>"}
+	db.Create(&template)
+	db.Create(&models.CampaignExecution{LeadReengagementID: late.ID, CampaignTemplateID: template.ID,
+		ScheduledFor: time.Now().Add(-time.Minute), Status: "scheduled"})
+
+	queue := &recordingQueue{}
+	dispatcher := NewCampaignDispatcher(db, nil, nil)
+	dispatcher.emailBatch = queue
+	dispatcher.SetDNCService(service)
+	dispatchResult, err := dispatcher.DispatchDue()
+	if err != nil {
+		t.Fatalf("DispatchDue failed: %v", err)
+	}
+	if dispatchResult.Skipped != 1 || len(queue.queued) != 0 {
+		t.Errorf("Expected the DNC-listed send to be skipped, got %+v with %d queued", dispatchResult, len(queue.queued))
+	}
+	db.First(&late, late.ID)
+	if !late.OnDNCList {
+		t.Error("Expected the late lead to be flagged at send time")
+	}
+
+	if err := service.CheckSMS("713.555.0100", "sms_service", nil); err != ErrRecipientOnDNC {
+		t.Errorf("Expected SMS to a listed number to be refused, got %v", err)
+	}
+	if err := service.CheckSMS("713-555-0300", "sms_service", nil); err != nil {
+		t.Errorf("Expected SMS to an unlisted number to pass, got %v", err)
+	}
+
+	var suppressions []models.DNCSuppressionLog
+	db.Order("id").Find(&suppressions)
+	if len(suppressions) != 2 || suppressions[0].Channel != "email" || suppressions[0].LeadReengagementID == nil ||
+		suppressions[1].Channel != "sms" || suppressions[1].RecipientHash != em.PhoneBlindIndex("7135550100") {
+		t.Errorf("Expected one email and one SMS suppression logged, got %+v", suppressions)
+	}
+}
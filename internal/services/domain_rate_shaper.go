@@ -0,0 +1,155 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DomainRateShaper spreads campaign sends over time by capping how many emails
+// go out per minute in total (per sender) and to each recipient domain.
+// Sends over a cap are deferred to the next one-minute window.
+type DomainRateShaper struct {
+	senderLimit   int            // Max sends per minute overall, 0 = unlimited
+	defaultLimit  int            // Max sends per minute per domain, 0 = unlimited
+	domainLimits  map[string]int // Per-domain overrides
+	window        time.Duration
+	now           func() time.Time
+	mutex         sync.Mutex
+	windowStart   time.Time
+	senderCount   int
+	windowCounts  map[string]int
+	totalSent     map[string]int
+	totalDeferred map[string]int
+}
+
+// DomainSendStats reports rate shaping counters for one recipient domain
+type DomainSendStats struct {
+	Domain         string `json:"domain"`
+	LimitPerMinute int    `json:"limit_per_minute"` // 0 = unlimited
+	SentThisWindow int    `json:"sent_this_window"`
+	TotalSent      int    `json:"total_sent"`
+	TotalDeferred  int    `json:"total_deferred"`
+}
+
+// RateShapingStats is a snapshot of the shaper's counters
+type RateShapingStats struct {
+	WindowStart          time.Time         `json:"window_start"`
+	WindowSeconds        int               `json:"window_seconds"`
+	SenderLimitPerMinute int               `json:"sender_limit_per_minute"`
+	SenderSentThisWindow int               `json:"sender_sent_this_window"`
+	Domains              []DomainSendStats `json:"domains"`
+}
+
+// NewDomainRateShaper creates a shaper with an overall and a default per-domain
+// limit per minute. domainLimits overrides the default for specific domains.
+func NewDomainRateShaper(senderLimit, defaultDomainLimit int, domainLimits map[string]int) *DomainRateShaper {
+	limits := make(map[string]int, len(domainLimits))
+	for domain, limit := range domainLimits {
+		limits[strings.ToLower(domain)] = limit
+	}
+	return &DomainRateShaper{
+		senderLimit:   senderLimit,
+		defaultLimit:  defaultDomainLimit,
+		domainLimits:  limits,
+		window:        time.Minute,
+		now:           time.Now,
+		windowCounts:  make(map[string]int),
+		totalSent:     make(map[string]int),
+		totalDeferred: make(map[string]int),
+	}
+}
+
+// Allow reports whether an email to the address may be sent in the current
+// window, counting it if so and recording a deferral if not
+func (s *DomainRateShaper) Allow(email string) bool {
+	domain := recipientDomain(email)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.rollWindow()
+
+	limit := s.limitFor(domain)
+	if (s.senderLimit > 0 && s.senderCount >= s.senderLimit) || (limit > 0 && s.windowCounts[domain] >= limit) {
+		s.totalDeferred[domain]++
+		return false
+	}
+
+	s.senderCount++
+	s.windowCounts[domain]++
+	s.totalSent[domain]++
+	return true
+}
+
+// NextWindow returns when the next window opens, i.e. when deferred sends may retry
+func (s *DomainRateShaper) NextWindow() time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.rollWindow()
+	return s.windowStart.Add(s.window)
+}
+
+// Stats returns per-domain counters, busiest domains first
+func (s *DomainRateShaper) Stats() RateShapingStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.rollWindow()
+
+	stats := RateShapingStats{
+		WindowStart:          s.windowStart,
+		WindowSeconds:        int(s.window / time.Second),
+		SenderLimitPerMinute: s.senderLimit,
+		SenderSentThisWindow: s.senderCount,
+		Domains:              []DomainSendStats{},
+	}
+
+	domains := map[string]bool{}
+	for domain := range s.totalSent {
+		domains[domain] = true
+	}
+	for domain := range s.totalDeferred {
+		domains[domain] = true
+	}
+	for domain := range domains {
+		stats.Domains = append(stats.Domains, DomainSendStats{
+			Domain:         domain,
+			LimitPerMinute: s.limitFor(domain),
+			SentThisWindow: s.windowCounts[domain],
+			TotalSent:      s.totalSent[domain],
+			TotalDeferred:  s.totalDeferred[domain],
+		})
+	}
+	sort.Slice(stats.Domains, func(i, j int) bool {
+		if stats.Domains[i].TotalSent != stats.Domains[j].TotalSent {
+			return stats.Domains[i].TotalSent > stats.Domains[j].TotalSent
+		}
+		return stats.Domains[i].Domain < stats.Domains[j].Domain
+	})
+	return stats
+}
+
+// rollWindow starts a new window once the current one has elapsed. Callers hold the mutex.
+func (s *DomainRateShaper) rollWindow() {
+	now := s.now()
+	if s.windowStart.IsZero() || !now.Before(s.windowStart.Add(s.window)) {
+		s.windowStart = now.Truncate(s.window)
+		s.senderCount = 0
+		s.windowCounts = make(map[string]int)
+	}
+}
+
+func (s *DomainRateShaper) limitFor(domain string) int {
+	if limit, ok := s.domainLimits[domain]; ok {
+		return limit
+	}
+	return s.defaultLimit
+}
+
+// recipientDomain returns the lower-cased domain of an email address
+func recipientDomain(email string) string {
+	if at := strings.LastIndex(email, "@"); at != -1 {
+		return strings.ToLower(strings.TrimSpace(email[at+1:]))
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+)
+
+type recordingQueue struct {
+	queued []EmailJob
+}
+
+func (q *recordingQueue) QueueEmail(email EmailJob) error {
+	q.queued = append(q.queued, email)
+	return nil
+}
+
+func TestDomainRateShaper_DefersOverflowToNextWindow(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 30, 15, 0, time.UTC)
+	shaper := NewDomainRateShaper(0, 2, map[string]int{"Yahoo.com": 1})
+	shaper.now = func() time.Time { return now }
+
+	if !shaper.Allow("a@gmail.com") || !shaper.Allow("b@GMAIL.com") {
+		t.Fatal("Expected the first two gmail sends to be allowed")
+	}
+	if shaper.Allow("c@gmail.com") {
+		t.Error("Expected the third gmail send in the window to be deferred")
+	}
+	if !shaper.Allow("d@yahoo.com") || shaper.Allow("e@yahoo.com") {
+		t.Error("Expected the yahoo override of 1 per minute to apply")
+	}
+	if next := shaper.NextWindow(); !next.Equal(time.Date(2026, 10, 16, 9, 31, 0, 0, time.UTC)) {
+		t.Errorf("Expected next window at 09:31, got %v", next)
+	}
+
+	now = now.Add(time.Minute)
+	if !shaper.Allow("c@gmail.com") {
+		t.Error("Expected a deferred send to go out in the next window")
+	}
+
+	stats := shaper.Stats()
+	if len(stats.Domains) != 2 || stats.Domains[0].Domain != "gmail.com" ||
+		stats.Domains[0].TotalSent != 3 || stats.Domains[0].TotalDeferred != 1 || stats.Domains[0].SentThisWindow != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	// The sender cap applies across domains
+	sender := NewDomainRateShaper(1, 0, nil)
+	if !sender.Allow("a@one.com") || sender.Allow("b@two.com") {
+		t.Error("Expected the sender cap to defer the second send")
+	}
+}
+
+func TestCampaignDispatcher_ReschedulesRateShapedSends(t *testing.T) {
+	db := setupDispatcherTestDB(t)
+
+	template := models.CampaignTemplate{Name: "reengage", EmailNumber: 1, Subject: "Hello", Body: "<p>Hi</p>"}
+	db.Create(&template)
+	for i := 0; i < 3; i++ {
+		lead := models.LeadReengagement{FUBContactID: fmt.Sprintf("fub-%d", i), Email: security.EncryptedString(fmt.Sprintf("lead%d@gmail.com", i))}
+		db.Create(&lead)
+		db.Create(&models.CampaignExecution{LeadReengagementID: lead.ID, CampaignTemplateID: template.ID, CampaignName: "reengage",
+			ScheduledFor: time.Now().Add(-time.Minute), Status: "scheduled"})
+	}
+
+	queue := &recordingQueue{}
+	dispatcher := NewCampaignDispatcher(db, nil, nil)
+	dispatcher.emailBatch = queue
+	dispatcher.SetRateShaper(NewDomainRateShaper(0, 2, nil))
+
+	result, err := dispatcher.DispatchDue()
+	if err != nil {
+		t.Fatalf("DispatchDue failed: %v", err)
+	}
+	if result.Sent != 2 || result.Deferred != 1 || len(queue.queued) != 2 {
+		t.Fatalf("Expected 2 sent and 1 deferred, got %+v", result)
+	}
+
+	var deferred models.CampaignExecution
+	db.Where("status = ?", "scheduled").First(&deferred)
+	if !deferred.ScheduledFor.After(time.Now()) {
+		t.Errorf("Expected the deferred send rescheduled to the next window, got %v", deferred.ScheduledFor)
+	}
+}
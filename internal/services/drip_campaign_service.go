@@ -0,0 +1,565 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDripCampaignNotFound = errors.New("drip campaign not found")
+	ErrDripCampaignStopped  = errors.New("drip campaign is stopped")
+	ErrInvalidDripCampaign  = errors.New("invalid drip campaign")
+)
+
+// DripStepInput describes one step when creating a drip campaign. A nil
+// DaysDelay uses the template's own DaysDelay.
+type DripStepInput struct {
+	TemplateID uint `json:"template_id" binding:"required"`
+	DaysDelay  *int `json:"days_delay"`
+}
+
+// DripEnrollResult summarizes an enrollment request
+type DripEnrollResult struct {
+	Enrolled int `json:"enrolled"`
+	Skipped  int `json:"skipped"` // ineligible or already enrolled
+}
+
+// DripAdvanceResult summarizes a single scheduler cycle
+type DripAdvanceResult struct {
+	Scheduled int `json:"scheduled"`
+	Advanced  int `json:"advanced"`
+	Completed int `json:"completed"`
+	Stopped   int `json:"stopped"`
+	Deferred  int `json:"deferred"`
+}
+
+// DripCampaignService manages drip campaigns and, when started, advances each
+// enrolled lead through its steps. Each due step becomes a scheduled
+// CampaignExecution that the CampaignDispatcher sends, so unsubscribes,
+// suppression, and per-campaign daily limits are enforced at send time.
+type DripCampaignService struct {
+	db               *gorm.DB
+	volumeController *VolumeController
+
+	interval  time.Duration
+	batchSize int
+
+	mutex    sync.Mutex
+	wg       sync.WaitGroup
+	stopChan chan bool
+	running  bool
+}
+
+// NewDripCampaignService creates a new drip campaign service
+func NewDripCampaignService(db *gorm.DB) *DripCampaignService {
+	return &DripCampaignService{
+		db:               db,
+		volumeController: NewVolumeController(db),
+		interval:         1 * time.Minute,
+		batchSize:        200,
+		stopChan:         make(chan bool),
+	}
+}
+
+// SetWarmupSchedule limits how many steps are queued per day to the sending domain's warmup cap
+func (s *DripCampaignService) SetWarmupSchedule(warmup *WarmupSchedule) {
+	s.volumeController.SetWarmupSchedule(warmup)
+}
+
+// CreateCampaign creates a drip campaign from an ordered list of steps
+func (s *DripCampaignService) CreateCampaign(name, description string, dailyLimit int, steps []DripStepInput, createdBy string) (*models.DripCampaign, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidDripCampaign)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("%w: at least one step is required", ErrInvalidDripCampaign)
+	}
+
+	campaign := &models.DripCampaign{
+		Name:        name,
+		Description: description,
+		Status:      models.DripCampaignActive,
+		DailyLimit:  dailyLimit,
+		CreatedBy:   createdBy,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for i, step := range steps {
+			var template models.CampaignTemplate
+			if err := tx.First(&template, "id = ?", step.TemplateID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("%w: template %d not found", ErrInvalidDripCampaign, step.TemplateID)
+				}
+				return err
+			}
+			delay := template.DaysDelay
+			if step.DaysDelay != nil {
+				delay = *step.DaysDelay
+			}
+			if delay < 0 {
+				return fmt.Errorf("%w: step %d has a negative delay", ErrInvalidDripCampaign, i+1)
+			}
+			campaign.Steps = append(campaign.Steps, models.DripCampaignStep{
+				Position:           i,
+				CampaignTemplateID: template.ID,
+				CampaignTemplate:   template,
+				DaysDelay:          delay,
+			})
+		}
+		return tx.Omit("Steps.CampaignTemplate").Create(campaign).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("💧 Drip campaign %q created with %d steps", campaign.Name, len(campaign.Steps))
+	return campaign, nil
+}
+
+// GetCampaign loads a drip campaign with its steps in order
+func (s *DripCampaignService) GetCampaign(id uint) (*models.DripCampaign, error) {
+	var campaign models.DripCampaign
+	err := s.db.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Preload("Steps.CampaignTemplate").First(&campaign, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDripCampaignNotFound
+		}
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// Enroll adds leads to an active or paused drip campaign. Only leads that are
+// eligible for re-engagement and not already enrolled are added; the first
+// step becomes due DaysDelay days from now.
+func (s *DripCampaignService) Enroll(campaignID uint, leadQuery *gorm.DB) (DripEnrollResult, error) {
+	var result DripEnrollResult
+
+	campaign, err := s.GetCampaign(campaignID)
+	if err != nil {
+		return result, err
+	}
+	if campaign.Status == models.DripCampaignStopped {
+		return result, ErrDripCampaignStopped
+	}
+	if len(campaign.Steps) == 0 {
+		return result, fmt.Errorf("%w: campaign has no steps", ErrInvalidDripCampaign)
+	}
+
+	var leads []models.LeadReengagement
+	if err := leadQuery.Find(&leads).Error; err != nil {
+		return result, fmt.Errorf("failed to load leads: %w", err)
+	}
+
+	now := time.Now()
+	firstDue := now.AddDate(0, 0, campaign.Steps[0].DaysDelay)
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for _, lead := range leads {
+			if !lead.IsEligibleForCampaign() || campaignSuppressionReason(&lead) != "" {
+				result.Skipped++
+				continue
+			}
+
+			var existing int64
+			if err := tx.Model(&models.DripEnrollment{}).
+				Where("drip_campaign_id = ? AND lead_reengagement_id = ?", campaign.ID, lead.ID).
+				Count(&existing).Error; err != nil {
+				return err
+			}
+			if existing > 0 {
+				result.Skipped++
+				continue
+			}
+
+			enrollment := models.DripEnrollment{
+				DripCampaignID:     campaign.ID,
+				LeadReengagementID: lead.ID,
+				Status:             models.DripEnrollmentActive,
+				NextStepAt:         &firstDue,
+			}
+			if err := tx.Create(&enrollment).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.LeadReengagement{}).Where("id = ?", lead.ID).Updates(map[string]interface{}{
+				"campaign_status":  models.CampaignActive,
+				"campaign_started": now,
+			}).Error; err != nil {
+				return err
+			}
+			result.Enrolled++
+		}
+		return nil
+	})
+	if err != nil {
+		return DripEnrollResult{}, err
+	}
+
+	log.Printf("💧 Enrolled %d leads in drip campaign %q (%d skipped)", result.Enrolled, campaign.Name, result.Skipped)
+	return result, nil
+}
+
+// PauseCampaign holds every enrollment at its current step. Executions queued
+// but not yet sent are withdrawn and re-queued on resume.
+func (s *DripCampaignService) PauseCampaign(campaignID uint) error {
+	return s.setStatus(campaignID, models.DripCampaignPaused)
+}
+
+// ResumeCampaign continues a paused drip campaign
+func (s *DripCampaignService) ResumeCampaign(campaignID uint) error {
+	return s.setStatus(campaignID, models.DripCampaignActive)
+}
+
+// StopCampaign ends a drip campaign for every enrolled lead. Stopped campaigns
+// cannot be resumed.
+func (s *DripCampaignService) StopCampaign(campaignID uint) error {
+	return s.setStatus(campaignID, models.DripCampaignStopped)
+}
+
+func (s *DripCampaignService) setStatus(campaignID uint, status string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var campaign models.DripCampaign
+		if err := tx.First(&campaign, "id = ?", campaignID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrDripCampaignNotFound
+			}
+			return err
+		}
+		if campaign.Status == models.DripCampaignStopped {
+			return ErrDripCampaignStopped
+		}
+		if err := tx.Model(&campaign).Update("status", status).Error; err != nil {
+			return err
+		}
+		if status == models.DripCampaignActive {
+			log.Printf("▶️ Drip campaign %q resumed", campaign.Name)
+			return nil
+		}
+
+		active := func() *gorm.DB {
+			return tx.Model(&models.DripEnrollment{}).
+				Where("drip_campaign_id = ? AND status = ?", campaign.ID, models.DripEnrollmentActive)
+		}
+		if err := withdrawPendingExecutions(tx, active()); err != nil {
+			return err
+		}
+		if status == models.DripCampaignPaused {
+			log.Printf("⏸️ Drip campaign %q paused", campaign.Name)
+			return nil
+		}
+
+		leadIDs := active().Select("lead_reengagement_id")
+		if err := tx.Model(&models.LeadReengagement{}).
+			Where("id IN (?) AND campaign_status = ?", leadIDs, models.CampaignActive).
+			Update("campaign_status", models.CampaignPending).Error; err != nil {
+			return err
+		}
+		if err := active().Updates(map[string]interface{}{
+			"status":         models.DripEnrollmentStopped,
+			"stopped_reason": "campaign stopped",
+			"next_step_at":   nil,
+		}).Error; err != nil {
+			return err
+		}
+		log.Printf("⏹️ Drip campaign %q stopped", campaign.Name)
+		return nil
+	})
+}
+
+// withdrawPendingExecutions deletes queued, unsent executions for the
+// enrollments and clears their pending marker so the step is re-queued later.
+// Executions already sent keep the marker; the scheduler advances those.
+func withdrawPendingExecutions(tx *gorm.DB, enrollments *gorm.DB) error {
+	var pendingIDs []uint
+	if err := enrollments.Where("pending_execution_id IS NOT NULL").Pluck("pending_execution_id", &pendingIDs).Error; err != nil {
+		return err
+	}
+	if len(pendingIDs) == 0 {
+		return nil
+	}
+
+	var unsentIDs []uint
+	if err := tx.Model(&models.CampaignExecution{}).
+		Where("id IN ? AND status = ?", pendingIDs, "scheduled").
+		Pluck("id", &unsentIDs).Error; err != nil {
+		return err
+	}
+	if len(unsentIDs) == 0 {
+		return nil
+	}
+
+	if err := tx.Where("id IN ?", unsentIDs).Delete(&models.CampaignExecution{}).Error; err != nil {
+		return fmt.Errorf("failed to withdraw queued executions: %w", err)
+	}
+	return tx.Model(&models.DripEnrollment{}).
+		Where("pending_execution_id IN ?", unsentIDs).
+		Update("pending_execution_id", nil).Error
+}
+
+// ListEnrollments returns the enrollments of a drip campaign, limited to leads
+// matched by leadScope
+func (s *DripCampaignService) ListEnrollments(campaignID uint, leadScope func(*gorm.DB) *gorm.DB, status string, limit, offset int) ([]models.DripEnrollment, int64, error) {
+	visibleLeads := s.db.Model(&models.LeadReengagement{}).Scopes(leadScope).Select("id")
+	query := s.db.Model(&models.DripEnrollment{}).
+		Where("drip_campaign_id = ? AND lead_reengagement_id IN (?)", campaignID, visibleLeads)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var enrollments []models.DripEnrollment
+	if err := query.Order("id ASC").Limit(limit).Offset(offset).Find(&enrollments).Error; err != nil {
+		return nil, 0, err
+	}
+	return enrollments, total, nil
+}
+
+// Start begins advancing drip enrollments in the background
+func (s *DripCampaignService) Start() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.running {
+		log.Println("⚠️  Drip campaign scheduler already running")
+		return
+	}
+
+	s.running = true
+	s.wg.Add(1)
+	go s.run()
+	log.Printf("💧 Drip campaign scheduler started (interval: %v)", s.interval)
+}
+
+// Stop halts the background scheduler and waits for any in-progress cycle to finish
+func (s *DripCampaignService) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.running {
+		return
+	}
+
+	log.Println("🛑 Stopping drip campaign scheduler...")
+	s.running = false
+	close(s.stopChan)
+	s.wg.Wait()
+}
+
+func (s *DripCampaignService) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			result, err := s.AdvanceDue()
+			if err != nil {
+				log.Printf("⚠️  Drip campaign cycle failed: %v", err)
+				continue
+			}
+			if result.Scheduled+result.Advanced+result.Stopped > 0 {
+				log.Printf("💧 Drip campaigns: %d scheduled, %d advanced, %d completed, %d stopped, %d deferred",
+					result.Scheduled, result.Advanced, result.Completed, result.Stopped, result.Deferred)
+			}
+		}
+	}
+}
+
+// AdvanceDue moves enrollments forward: enrollments whose queued step was
+// sent advance to the next step (or complete), those whose step was skipped
+// or failed are stopped, and due steps of active campaigns are queued as
+// CampaignExecutions, no more than the volume controller allows today.
+func (s *DripCampaignService) AdvanceDue() (DripAdvanceResult, error) {
+	var result DripAdvanceResult
+
+	if err := s.advanceSent(&result); err != nil {
+		return result, err
+	}
+
+	if IsCampaignEmergencyStopActive() {
+		return result, nil
+	}
+
+	volume, err := s.volumeController.CheckVolumeCompliance()
+	if err != nil {
+		return result, fmt.Errorf("failed to check volume compliance: %w", err)
+	}
+	var queued int64
+	s.db.Model(&models.CampaignExecution{}).Where("status = ?", "scheduled").Count(&queued)
+	remaining := volume.DailyLimit - volume.DailyVolume - int(queued)
+
+	var due []models.DripEnrollment
+	activeCampaigns := s.db.Model(&models.DripCampaign{}).Where("status = ?", models.DripCampaignActive).Select("id")
+	if err := s.db.Preload("LeadReengagement").
+		Where("status = ? AND pending_execution_id IS NULL AND next_step_at <= ? AND drip_campaign_id IN (?)",
+			models.DripEnrollmentActive, time.Now(), activeCampaigns).
+		Order("next_step_at ASC").
+		Limit(s.batchSize).
+		Find(&due).Error; err != nil {
+		return result, fmt.Errorf("failed to load due enrollments: %w", err)
+	}
+
+	campaigns := map[uint]*models.DripCampaign{}
+	for i := range due {
+		enrollment := &due[i]
+
+		if reason := campaignSuppressionReason(&enrollment.LeadReengagement); reason != "" {
+			s.stopEnrollment(enrollment, reason)
+			result.Stopped++
+			continue
+		}
+		if remaining <= 0 {
+			result.Deferred++
+			continue
+		}
+
+		campaign, ok := campaigns[enrollment.DripCampaignID]
+		if !ok {
+			campaign, err = s.GetCampaign(enrollment.DripCampaignID)
+			if err != nil {
+				return result, err
+			}
+			campaigns[enrollment.DripCampaignID] = campaign
+		}
+		if enrollment.CurrentStep >= len(campaign.Steps) {
+			s.completeEnrollment(enrollment)
+			result.Completed++
+			continue
+		}
+		step := campaign.Steps[enrollment.CurrentStep]
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			execution := models.CampaignExecution{
+				LeadReengagementID: enrollment.LeadReengagementID,
+				CampaignTemplateID: step.CampaignTemplateID,
+				CampaignName:       campaign.Name,
+				DailyLimit:         campaign.DailyLimit,
+				ScheduledFor:       time.Now(),
+				Status:             "scheduled",
+				DripEnrollmentID:   &enrollment.ID,
+				DripStep:           step.Position,
+			}
+			if err := tx.Create(&execution).Error; err != nil {
+				return err
+			}
+			return tx.Model(&models.DripEnrollment{}).Where("id = ?", enrollment.ID).
+				Update("pending_execution_id", execution.ID).Error
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to queue drip step for enrollment %d: %w", enrollment.ID, err)
+		}
+		result.Scheduled++
+		remaining--
+	}
+
+	return result, nil
+}
+
+// advanceSent resolves enrollments whose queued execution has been dispatched
+func (s *DripCampaignService) advanceSent(result *DripAdvanceResult) error {
+	var enrollments []models.DripEnrollment
+	if err := s.db.Where("status = ? AND pending_execution_id IS NOT NULL", models.DripEnrollmentActive).
+		Find(&enrollments).Error; err != nil {
+		return fmt.Errorf("failed to load pending enrollments: %w", err)
+	}
+
+	campaigns := map[uint]*models.DripCampaign{}
+	for i := range enrollments {
+		enrollment := &enrollments[i]
+
+		var execution models.CampaignExecution
+		err := s.db.First(&execution, "id = ?", *enrollment.PendingExecutionID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Withdrawn (or merged away) before it was sent; queue the step again
+			s.db.Model(&models.DripEnrollment{}).Where("id = ?", enrollment.ID).Update("pending_execution_id", nil)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		switch execution.Status {
+		case "scheduled":
+			continue
+		case "sent":
+		default:
+			reason := execution.ErrorMessage
+			if reason == "" {
+				reason = "step " + execution.Status
+			}
+			s.stopEnrollment(enrollment, reason)
+			result.Stopped++
+			continue
+		}
+
+		campaign, ok := campaigns[enrollment.DripCampaignID]
+		if !ok {
+			campaign, err = s.GetCampaign(enrollment.DripCampaignID)
+			if err != nil {
+				return err
+			}
+			campaigns[enrollment.DripCampaignID] = campaign
+		}
+
+		nextStep := enrollment.CurrentStep + 1
+		if nextStep >= len(campaign.Steps) {
+			enrollment.CurrentStep = nextStep
+			s.completeEnrollment(enrollment)
+			result.Completed++
+			continue
+		}
+
+		sentAt := time.Now()
+		if execution.ExecutedAt != nil {
+			sentAt = *execution.ExecutedAt
+		}
+		nextAt := sentAt.AddDate(0, 0, campaign.Steps[nextStep].DaysDelay)
+		s.db.Model(&models.DripEnrollment{}).Where("id = ?", enrollment.ID).Updates(map[string]interface{}{
+			"current_step":         nextStep,
+			"next_step_at":         nextAt,
+			"pending_execution_id": nil,
+		})
+		result.Advanced++
+	}
+	return nil
+}
+
+func (s *DripCampaignService) completeEnrollment(enrollment *models.DripEnrollment) {
+	now := time.Now()
+	s.db.Model(&models.DripEnrollment{}).Where("id = ?", enrollment.ID).Updates(map[string]interface{}{
+		"status":               models.DripEnrollmentCompleted,
+		"current_step":         enrollment.CurrentStep,
+		"completed_at":         now,
+		"next_step_at":         nil,
+		"pending_execution_id": nil,
+	})
+	s.db.Model(&models.LeadReengagement{}).Where("id = ? AND campaign_status = ?", enrollment.LeadReengagementID, models.CampaignActive).
+		Updates(map[string]interface{}{"campaign_status": models.CampaignCompleted, "campaign_completed": now})
+}
+
+func (s *DripCampaignService) stopEnrollment(enrollment *models.DripEnrollment, reason string) {
+	s.db.Model(&models.DripEnrollment{}).Where("id = ?", enrollment.ID).Updates(map[string]interface{}{
+		"status":               models.DripEnrollmentStopped,
+		"stopped_reason":       reason,
+		"next_step_at":         nil,
+		"pending_execution_id": nil,
+	})
+	s.db.Model(&models.LeadReengagement{}).Where("id = ? AND campaign_status = ?", enrollment.LeadReengagementID, models.CampaignActive).
+		Update("campaign_status", models.CampaignPending)
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestDripCampaignService_AdvancesLeadsThroughSteps(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.LeadReengagement{}, &models.CampaignTemplate{}, &models.CampaignExecution{},
+		&models.DripCampaign{}, &models.DripCampaignStep{}, &models.DripEnrollment{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	first := models.CampaignTemplate{Name: "Hello", EmailNumber: 1, Subject: "Hi", Body: "Hi {{first_name}}"}
+	second := models.CampaignTemplate{Name: "Follow up", EmailNumber: 2, Subject: "Still looking?", Body: "Checking in", DaysDelay: 3}
+	db.Create(&first)
+	db.Create(&second)
+
+	var leads []models.LeadReengagement
+	for _, fubID := range []string{"fub-1", "fub-2"} {
+		lead := models.LeadReengagement{FUBContactID: fubID, HasEmail: true, EmailValid: true, Segment: models.SegmentActive,
+			RiskLevel: models.RiskLow, ConsentStatus: models.ConsentImplied, CampaignStatus: models.CampaignPending}
+		db.Create(&lead)
+		leads = append(leads, lead)
+	}
+
+	service := NewDripCampaignService(db)
+	campaign, err := service.CreateCampaign("Nurture", "", 0, []DripStepInput{{TemplateID: first.ID}, {TemplateID: second.ID}}, "admin-1")
+	if err != nil {
+		t.Fatalf("CreateCampaign failed: %v", err)
+	}
+	if campaign.Steps[1].DaysDelay != 3 {
+		t.Errorf("Expected step delay to default to the template's, got %d", campaign.Steps[1].DaysDelay)
+	}
+
+	enrolled, err := service.Enroll(campaign.ID, db.Model(&models.LeadReengagement{}))
+	if err != nil || enrolled.Enrolled != 2 {
+		t.Fatalf("Expected 2 enrolled, got %+v (%v)", enrolled, err)
+	}
+	if again, _ := service.Enroll(campaign.ID, db.Model(&models.LeadReengagement{})); again.Enrolled != 0 {
+		t.Errorf("Expected re-enrollment to be skipped, got %+v", again)
+	}
+
+	// The second lead unsubscribes before the first send
+	db.Model(&models.LeadReengagement{}).Where("id = ?", leads[1].ID).Update("previous_unsubscribe", true)
+
+	result, err := service.AdvanceDue()
+	if err != nil {
+		t.Fatalf("AdvanceDue failed: %v", err)
+	}
+	if result.Scheduled != 1 || result.Stopped != 1 {
+		t.Fatalf("Expected 1 scheduled and 1 stopped, got %+v", result)
+	}
+
+	var enrollment models.DripEnrollment
+	db.Where("lead_reengagement_id = ?", leads[0].ID).First(&enrollment)
+	if enrollment.PendingExecutionID == nil {
+		t.Fatal("Expected the first step to be queued")
+	}
+
+	// Pausing withdraws the unsent execution; resuming queues it again
+	if err := service.PauseCampaign(campaign.ID); err != nil {
+		t.Fatalf("PauseCampaign failed: %v", err)
+	}
+	var queued int64
+	db.Model(&models.CampaignExecution{}).Where("status = ?", "scheduled").Count(&queued)
+	if queued != 0 {
+		t.Errorf("Expected pause to withdraw queued executions, %d left", queued)
+	}
+	if result, _ := service.AdvanceDue(); result.Scheduled != 0 {
+		t.Errorf("Expected nothing scheduled while paused, got %+v", result)
+	}
+	service.ResumeCampaign(campaign.ID)
+	if result, _ := service.AdvanceDue(); result.Scheduled != 1 {
+		t.Fatalf("Expected the step re-queued after resume, got %+v", result)
+	}
+
+	// Simulate the dispatcher sending step 1
+	db.Where("lead_reengagement_id = ?", leads[0].ID).First(&enrollment)
+	sentAt := time.Now().Add(-time.Hour)
+	db.Model(&models.CampaignExecution{}).Where("id = ?", *enrollment.PendingExecutionID).
+		Updates(map[string]interface{}{"status": "sent", "executed_at": sentAt})
+
+	if result, _ := service.AdvanceDue(); result.Advanced != 1 || result.Scheduled != 0 {
+		t.Fatalf("Expected advance to step 2 without sending yet, got %+v", result)
+	}
+	db.First(&enrollment, enrollment.ID)
+	if enrollment.CurrentStep != 1 || enrollment.NextStepAt == nil || enrollment.NextStepAt.Sub(sentAt) < 72*time.Hour-time.Minute {
+		t.Fatalf("Expected step 2 due 3 days after the first send, got %+v", enrollment)
+	}
+
+	// Step 2 comes due and is sent
+	db.Model(&enrollment).Update("next_step_at", time.Now().Add(-time.Minute))
+	if result, _ := service.AdvanceDue(); result.Scheduled != 1 {
+		t.Fatalf("Expected step 2 queued, got %+v", result)
+	}
+	db.First(&enrollment, enrollment.ID)
+	var execution models.CampaignExecution
+	db.First(&execution, *enrollment.PendingExecutionID)
+	if execution.CampaignTemplateID != second.ID || execution.DripStep != 1 {
+		t.Errorf("Expected step 2 to use the follow-up template, got %+v", execution)
+	}
+	db.Model(&execution).Updates(map[string]interface{}{"status": "sent", "executed_at": time.Now()})
+
+	if result, _ := service.AdvanceDue(); result.Completed != 1 {
+		t.Fatalf("Expected enrollment completed, got %+v", result)
+	}
+	var lead models.LeadReengagement
+	db.First(&lead, leads[0].ID)
+	if lead.CampaignStatus != models.CampaignCompleted {
+		t.Errorf("Expected lead campaign completed, got %s", lead.CampaignStatus)
+	}
+
+	if err := service.StopCampaign(campaign.ID); err != nil {
+		t.Fatalf("StopCampaign failed: %v", err)
+	}
+	if err := service.ResumeCampaign(campaign.ID); err != ErrDripCampaignStopped {
+		t.Errorf("Expected stopped campaign to stay stopped, got %v", err)
+	}
+}
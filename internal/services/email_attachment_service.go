@@ -0,0 +1,186 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrAttachmentRejected is returned when an attachment fails validation
+var ErrAttachmentRejected = errors.New("attachment rejected")
+
+// Attachment limits for incoming emails
+const (
+	MaxEmailAttachmentBytes      = 10 * 1024 * 1024
+	MaxEmailAttachmentTotalBytes = 25 * 1024 * 1024
+	MaxEmailAttachments          = 10
+)
+
+// allowedAttachmentTypes are the content types accepted on incoming emails
+var allowedAttachmentTypes = map[string]bool{
+	"application/pdf":    true,
+	"image/jpeg":         true,
+	"image/png":          true,
+	"image/gif":          true,
+	"text/plain":         true,
+	"text/csv":           true,
+	"application/msword": true,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
+	"application/vnd.ms-excel": true,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": true,
+}
+
+// dangerousAttachmentExtensions are executable, script, or macro-enabled file
+// types that are rejected regardless of the declared content type
+var dangerousAttachmentExtensions = map[string]bool{
+	".exe": true, ".dll": true, ".com": true, ".bat": true, ".cmd": true, ".msi": true,
+	".scr": true, ".pif": true, ".cpl": true, ".hta": true, ".lnk": true, ".jar": true,
+	".js": true, ".jse": true, ".vbs": true, ".vbe": true, ".wsf": true, ".ps1": true,
+	".sh": true, ".app": true, ".iso": true, ".html": true, ".htm": true, ".svg": true,
+	".docm": true, ".xlsm": true, ".pptm": true, ".dotm": true, ".xlam": true,
+}
+
+// executableSignatures are leading bytes of executable formats
+var executableSignatures = [][]byte{
+	[]byte("MZ"),             // Windows PE
+	[]byte("\x7fELF"),        // Linux ELF
+	[]byte("#!"),             // Script with interpreter line
+	{0xCF, 0xFA, 0xED, 0xFE}, // Mach-O
+}
+
+// EmailAttachmentInput is an attachment as posted with an incoming email
+type EmailAttachmentInput struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Data        string `json:"data"` // Base64-encoded file content
+}
+
+// EmailAttachmentService validates and stores incoming email attachments
+type EmailAttachmentService struct {
+	db *gorm.DB
+}
+
+// NewEmailAttachmentService creates a new email attachment service
+func NewEmailAttachmentService(db *gorm.DB) *EmailAttachmentService {
+	return &EmailAttachmentService{db: db}
+}
+
+// ValidateAttachments decodes and checks attachments before anything is stored.
+// An error wrapping ErrAttachmentRejected names the offending file.
+func (s *EmailAttachmentService) ValidateAttachments(inputs []EmailAttachmentInput) ([]models.IncomingEmailAttachment, error) {
+	if len(inputs) > MaxEmailAttachments {
+		return nil, fmt.Errorf("%w: at most %d attachments are allowed", ErrAttachmentRejected, MaxEmailAttachments)
+	}
+
+	attachments := make([]models.IncomingEmailAttachment, 0, len(inputs))
+	total := 0
+	for _, input := range inputs {
+		attachment, err := validateAttachment(input)
+		if err != nil {
+			return nil, err
+		}
+		total += attachment.SizeBytes
+		if total > MaxEmailAttachmentTotalBytes {
+			return nil, fmt.Errorf("%w: attachments exceed %d MB in total", ErrAttachmentRejected, MaxEmailAttachmentTotalBytes/(1024*1024))
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+func validateAttachment(input EmailAttachmentInput) (models.IncomingEmailAttachment, error) {
+	filename := filepath.Base(strings.ReplaceAll(strings.TrimSpace(input.Filename), "\\", "/"))
+	if filename == "" || filename == "." || filename == "/" {
+		return models.IncomingEmailAttachment{}, fmt.Errorf("%w: filename is required", ErrAttachmentRejected)
+	}
+	reject := func(reason string) (models.IncomingEmailAttachment, error) {
+		return models.IncomingEmailAttachment{}, fmt.Errorf("%w: %s: %s", ErrAttachmentRejected, filename, reason)
+	}
+
+	// Check every extension so "lease.pdf.exe" and "lease.exe.pdf" are both caught
+	for _, part := range strings.Split(strings.ToLower(filename), ".")[1:] {
+		if dangerousAttachmentExtensions["."+part] {
+			return reject("file type is not allowed")
+		}
+	}
+
+	contentType, _, err := mime.ParseMediaType(input.ContentType)
+	if err != nil || !allowedAttachmentTypes[contentType] {
+		return reject(fmt.Sprintf("content type %q is not allowed", input.ContentType))
+	}
+
+	if base64.StdEncoding.DecodedLen(len(input.Data)) > MaxEmailAttachmentBytes+3 {
+		return reject(fmt.Sprintf("exceeds %d MB", MaxEmailAttachmentBytes/(1024*1024)))
+	}
+	data, err := base64.StdEncoding.DecodeString(input.Data)
+	if err != nil {
+		return reject("data is not valid base64")
+	}
+	if len(data) == 0 {
+		return reject("file is empty")
+	}
+	if len(data) > MaxEmailAttachmentBytes {
+		return reject(fmt.Sprintf("exceeds %d MB", MaxEmailAttachmentBytes/(1024*1024)))
+	}
+
+	for _, signature := range executableSignatures {
+		if bytes.HasPrefix(data, signature) {
+			return reject("file content is executable")
+		}
+	}
+	if !contentMatchesType(data, contentType) {
+		return reject(fmt.Sprintf("file content does not match %s", contentType))
+	}
+
+	sum := sha256.Sum256(data)
+	return models.IncomingEmailAttachment{
+		Filename:    filename,
+		ContentType: contentType,
+		SizeBytes:   len(data),
+		SHA256:      hex.EncodeToString(sum[:]),
+		Data:        data,
+	}, nil
+}
+
+// contentMatchesType checks PDFs and images against their file signatures;
+// other allowed types are not sniffed
+func contentMatchesType(data []byte, contentType string) bool {
+	switch {
+	case contentType == "application/pdf":
+		return bytes.HasPrefix(data, []byte("%PDF-"))
+	case strings.HasPrefix(contentType, "image/"):
+		return http.DetectContentType(data) == contentType
+	default:
+		return true
+	}
+}
+
+// SaveAttachments stores validated attachments for an incoming email
+func (s *EmailAttachmentService) SaveAttachments(incomingEmailID uint, attachments []models.IncomingEmailAttachment) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+	for i := range attachments {
+		attachments[i].IncomingEmailID = incomingEmailID
+	}
+	return s.db.Create(&attachments).Error
+}
+
+// GetAttachment returns an attachment including its file content
+func (s *EmailAttachmentService) GetAttachment(id uint) (*models.IncomingEmailAttachment, error) {
+	var attachment models.IncomingEmailAttachment
+	if err := s.db.First(&attachment, id).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestEmailAttachmentService_ValidatesAndStoresAttachments(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.IncomingEmailAttachment{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	service := NewEmailAttachmentService(db)
+	encode := func(data string) string { return base64.StdEncoding.EncodeToString([]byte(data)) }
+
+	rejected := []EmailAttachmentInput{
+		{Filename: "invoice.pdf.exe", ContentType: "application/pdf", Data: encode("%PDF-1.7")},
+		{Filename: "lease.pdf", ContentType: "application/x-msdownload", Data: encode("%PDF-1.7")},
+		{Filename: "lease.pdf", ContentType: "application/pdf", Data: encode("MZ\x90\x00")},
+		{Filename: "lease.pdf", ContentType: "application/pdf", Data: encode("<html>not a pdf</html>")},
+		{Filename: "notes.txt", ContentType: "text/plain", Data: "not base64!"},
+		{Filename: "", ContentType: "text/plain", Data: encode("hello")},
+	}
+	for _, input := range rejected {
+		if _, err := service.ValidateAttachments([]EmailAttachmentInput{input}); !errors.Is(err, ErrAttachmentRejected) {
+			t.Errorf("Expected %q (%s) to be rejected, got %v", input.Filename, input.ContentType, err)
+		}
+	}
+
+	attachments, err := service.ValidateAttachments([]EmailAttachmentInput{
+		{Filename: `C:\scans\signed-lease.pdf`, ContentType: "application/pdf", Data: encode("%PDF-1.7 signed")},
+		{Filename: "notes.txt", ContentType: "text/plain; charset=utf-8", Data: encode("Keys under the mat")},
+	})
+	if err != nil {
+		t.Fatalf("Expected valid attachments to pass, got %v", err)
+	}
+	if attachments[0].Filename != "signed-lease.pdf" || attachments[1].ContentType != "text/plain" {
+		t.Errorf("Expected path-free filename and bare content type, got %+v", attachments)
+	}
+
+	if err := service.SaveAttachments(42, attachments); err != nil {
+		t.Fatalf("Failed to save attachments: %v", err)
+	}
+	byEmail, err := models.AttachmentsForEmails(db, []uint{42})
+	if err != nil || len(byEmail[42]) != 2 {
+		t.Fatalf("Expected two attachments for the email, got %v (%v)", byEmail, err)
+	}
+	if listed := byEmail[42][0]; listed.Data != nil || listed.SizeBytes != len("%PDF-1.7 signed") {
+		t.Errorf("Expected listing to omit file content, got %+v", listed)
+	}
+
+	stored, err := service.GetAttachment(byEmail[42][0].ID)
+	if err != nil || string(stored.Data) != "%PDF-1.7 signed" {
+		t.Errorf("Expected stored file content, got %v (%v)", stored, err)
+	}
+}
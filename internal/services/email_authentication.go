@@ -0,0 +1,211 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"chrisgross-ctrl-project/internal/models"
+)
+
+// EmailAuthResults holds the SPF, DKIM, and DMARC verdicts recorded by the
+// receiving mail server in an email's Authentication-Results and Received-SPF headers
+type EmailAuthResults struct {
+	SPF             string   `json:"spf,omitempty"`
+	SPFDomain       string   `json:"spf_domain,omitempty"`
+	DKIM            string   `json:"dkim,omitempty"`
+	DKIMPassDomains []string `json:"dkim_pass_domains,omitempty"`
+	DMARC           string   `json:"dmarc,omitempty"`
+	DMARCDomain     string   `json:"dmarc_domain,omitempty"`
+}
+
+// SenderAuthCheck is the outcome of checking an email against its sender's auth policy
+type SenderAuthCheck struct {
+	Passed  bool             `json:"passed"`
+	Policy  string           `json:"policy"`
+	Reason  string           `json:"reason"`
+	Results EmailAuthResults `json:"results"`
+}
+
+// ParseEmailAuthResults extracts authentication verdicts from an email's headers.
+// Header names are matched case-insensitively; a value may hold several header
+// instances separated by newlines.
+func ParseEmailAuthResults(headers map[string]string) EmailAuthResults {
+	var results EmailAuthResults
+	for name, value := range headers {
+		if strings.EqualFold(name, "Authentication-Results") {
+			parseAuthenticationResults(stripHeaderComments(value), &results)
+		}
+	}
+
+	// Received-SPF is only consulted when Authentication-Results has no SPF verdict
+	if results.SPF == "" {
+		for name, value := range headers {
+			if strings.EqualFold(name, "Received-SPF") {
+				parseReceivedSPF(stripHeaderComments(value), &results)
+			}
+		}
+	}
+	return results
+}
+
+// parseAuthenticationResults reads RFC 8601 method results such as
+// "dkim=pass header.d=example.com; spf=pass smtp.mailfrom=a@example.com"
+func parseAuthenticationResults(value string, results *EmailAuthResults) {
+	for _, clause := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == '\n' }) {
+		fields := strings.Fields(clause)
+		if len(fields) == 0 {
+			continue
+		}
+		method, result, found := strings.Cut(strings.ToLower(fields[0]), "=")
+		if !found {
+			continue // authserv-id
+		}
+		props := headerProperties(fields[1:])
+
+		switch method {
+		case "spf":
+			if results.SPF != "pass" {
+				results.SPF = result
+				results.SPFDomain = addressDomain(firstNonEmpty(props["smtp.mailfrom"], props["smtp.helo"]))
+			}
+		case "dkim":
+			if results.DKIM != "pass" {
+				results.DKIM = result
+			}
+			if result == "pass" {
+				if domain := addressDomain(firstNonEmpty(props["header.d"], props["header.i"])); domain != "" {
+					results.DKIMPassDomains = append(results.DKIMPassDomains, domain)
+				}
+			}
+		case "dmarc":
+			if results.DMARC != "pass" {
+				results.DMARC = result
+				results.DMARCDomain = addressDomain(props["header.from"])
+			}
+		}
+	}
+}
+
+// parseReceivedSPF reads an RFC 7208 Received-SPF header such as
+// "pass client-ip=192.0.2.1; envelope-from=a@example.com"
+func parseReceivedSPF(value string, results *EmailAuthResults) {
+	fields := strings.Fields(strings.ReplaceAll(value, ";", " "))
+	if len(fields) == 0 {
+		return
+	}
+	results.SPF = strings.ToLower(fields[0])
+	results.SPFDomain = addressDomain(headerProperties(fields[1:])["envelope-from"])
+}
+
+// headerProperties maps lowercased key=value tokens, unquoting values
+func headerProperties(fields []string) map[string]string {
+	props := make(map[string]string)
+	for _, field := range fields {
+		if key, value, found := strings.Cut(field, "="); found {
+			props[strings.ToLower(key)] = strings.Trim(value, `"<>`)
+		}
+	}
+	return props
+}
+
+// stripHeaderComments removes parenthesized comments, which may contain
+// misleading text such as "domain of x designates y as permitted sender"
+func stripHeaderComments(value string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range value {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// addressDomain returns the lowercased domain of an address or bare domain
+func addressDomain(value string) string {
+	if i := strings.LastIndex(value, "@"); i >= 0 {
+		value = value[i+1:]
+	}
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// domainsAligned reports relaxed DMARC alignment: the domains are equal or one
+// is a subdomain of the other
+func domainsAligned(authDomain, fromDomain string) bool {
+	if authDomain == "" || fromDomain == "" {
+		return false
+	}
+	return authDomain == fromDomain ||
+		strings.HasSuffix(authDomain, "."+fromDomain) ||
+		strings.HasSuffix(fromDomain, "."+authDomain)
+}
+
+// VerifySenderAuthentication checks an email's authentication results against
+// the trusted sender's auth policy. Emails that fail should not be processed
+// automatically, since their From address may be spoofed.
+func (s *EmailSenderValidationService) VerifySenderAuthentication(sender *models.TrustedEmailSender, fromEmail string, headers map[string]string) SenderAuthCheck {
+	check := SenderAuthCheck{
+		Policy:  sender.AuthPolicy,
+		Results: ParseEmailAuthResults(headers),
+	}
+	if check.Policy == "" {
+		check.Policy = models.SenderAuthPolicyRelaxed
+	}
+	if check.Policy == models.SenderAuthPolicyNone {
+		check.Passed = true
+		check.Reason = "authentication checks disabled for sender"
+		return check
+	}
+
+	fromDomain := addressDomain(fromEmail)
+	results := check.Results
+	spfAligned := results.SPF == "pass" && domainsAligned(results.SPFDomain, fromDomain)
+	dkimAligned := false
+	for _, domain := range results.DKIMPassDomains {
+		if domainsAligned(domain, fromDomain) {
+			dkimAligned = true
+			break
+		}
+	}
+	dmarcAligned := results.DMARC == "pass" && (results.DMARCDomain == "" || results.DMARCDomain == fromDomain)
+
+	switch {
+	case results.SPF == "" && results.DKIM == "" && results.DMARC == "":
+		check.Reason = "no authentication results in headers"
+	case check.Policy == models.SenderAuthPolicyStrict:
+		check.Passed = spfAligned && dkimAligned
+		if !check.Passed {
+			check.Reason = fmt.Sprintf("strict policy requires SPF and DKIM to pass for %s (spf=%s, dkim=%s)", fromDomain, orNone(results.SPF), orNone(results.DKIM))
+		}
+	default:
+		check.Passed = dmarcAligned || spfAligned || dkimAligned
+		if !check.Passed {
+			check.Reason = fmt.Sprintf("no SPF, DKIM, or DMARC pass for %s (spf=%s, dkim=%s, dmarc=%s)", fromDomain, orNone(results.SPF), orNone(results.DKIM), orNone(results.DMARC))
+		}
+	}
+	if check.Passed {
+		check.Reason = "sender authenticated"
+	}
+	return check
+}
+
+func orNone(result string) string {
+	if result == "" {
+		return "none"
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+)
+
+func TestVerifySenderAuthentication_PassingAndSpoofedHeaders(t *testing.T) {
+	service := NewEmailSenderValidationService(nil)
+	relaxed := &models.TrustedEmailSender{SenderEmail: "alerts@vendor.com"}
+	strict := &models.TrustedEmailSender{SenderEmail: "alerts@vendor.com", AuthPolicy: models.SenderAuthPolicyStrict}
+
+	passing := map[string]string{
+		"authentication-results": "mx.example.net; dkim=pass header.d=mail.vendor.com header.s=s1; " +
+			"spf=pass (mx.example.net: domain of bounces@vendor.com designates 192.0.2.1 as permitted sender) smtp.mailfrom=bounces@vendor.com; " +
+			"dmarc=pass (p=REJECT) header.from=vendor.com",
+	}
+	// The From header claims the vendor, but SPF and DKIM only pass for the attacker's domain
+	spoofed := map[string]string{
+		"Authentication-Results": "mx.example.net; dkim=pass header.d=attacker.example; " +
+			"spf=pass (domain of x@vendor.com designates 203.0.113.9) smtp.mailfrom=x@attacker.example; " +
+			"dmarc=fail (p=NONE) header.from=vendor.com",
+	}
+	spfOnly := map[string]string{
+		"Received-SPF": `pass (mx.example.net: domain of bounces@vendor.com designates 192.0.2.1) client-ip=192.0.2.1; envelope-from="bounces@vendor.com";`,
+	}
+
+	cases := []struct {
+		name    string
+		sender  *models.TrustedEmailSender
+		headers map[string]string
+		passed  bool
+	}{
+		{"relaxed passing", relaxed, passing, true},
+		{"strict passing", strict, passing, true},
+		{"relaxed spoofed", relaxed, spoofed, false},
+		{"strict spoofed", strict, spoofed, false},
+		{"relaxed without headers", relaxed, nil, false},
+		{"relaxed Received-SPF only", relaxed, spfOnly, true},
+		{"strict Received-SPF only", strict, spfOnly, false},
+		{"policy none", &models.TrustedEmailSender{AuthPolicy: models.SenderAuthPolicyNone}, spoofed, true},
+	}
+	for _, tc := range cases {
+		check := service.VerifySenderAuthentication(tc.sender, "Alerts@Vendor.com", tc.headers)
+		if check.Passed != tc.passed {
+			t.Errorf("%s: expected passed=%v, got %+v", tc.name, tc.passed, check)
+		}
+	}
+
+	results := ParseEmailAuthResults(spoofed)
+	if results.SPF != "pass" || results.SPFDomain != "attacker.example" || results.DMARC != "fail" {
+		t.Errorf("Expected comments to be ignored when parsing results, got %+v", results)
+	}
+}
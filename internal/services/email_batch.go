@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -45,6 +46,12 @@ type EmailBatchService struct {
 	// Email provider settings
 	smtpConfig SMTPConfig
 	awsService *AWSCommunicationService
+	smtpPool   *SMTPPool // When set, mail goes over pooled SMTP instead of SES
+
+	// Called once an email has failed its last retry
+	failureHandler func(email EmailJob, err error)
+
+	dnc *DNCService
 }
 
 // EmailJob represents a single email to be sent
@@ -57,11 +64,13 @@ type EmailJob struct {
 	Body        string                 `json:"body"`
 	HTMLBody    string                 `json:"html_body,omitempty"`
 	Attachments []EmailAttachment      `json:"attachments,omitempty"`
+	Headers     map[string]string      `json:"headers,omitempty"`
 	Priority    int                    `json:"priority"` // 1=highest, 5=lowest
 	ScheduledAt *time.Time             `json:"scheduled_at,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 	CreatedAt   time.Time              `json:"created_at"`
 	Retries     int                    `json:"retries"`
+	Delivered   []string               `json:"delivered,omitempty"` // Recipients already sent to, skipped on retry
 }
 
 // EmailAttachment represents an email attachment
@@ -131,6 +140,28 @@ func NewEmailBatchService(redis *redis.Client, smtpConfig SMTPConfig) *EmailBatc
 	}
 }
 
+// SetFailureHandler registers a callback for emails that fail their last retry
+func (e *EmailBatchService) SetFailureHandler(handler func(email EmailJob, err error)) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.failureHandler = handler
+}
+
+// SetSMTPPool sends batches over pooled SMTP connections instead of SES. Call
+// it before Start; workers are raised to the pool's connection count so every
+// connection can be kept busy. The caller closes the pool after Stop.
+func (e *EmailBatchService) SetSMTPPool(pool *SMTPPool) {
+	e.smtpPool = pool
+	if pool.config.MaxConnections > e.workerCount {
+		e.workerCount = pool.config.MaxConnections
+	}
+}
+
+// SetDNCService drops recipients on the do-not-contact list from queued emails
+func (e *EmailBatchService) SetDNCService(dnc *DNCService) {
+	e.dnc = dnc
+}
+
 // Start initializes and starts the email batch service
 func (e *EmailBatchService) Start() error {
 	log.Println("📧 Starting email batch service...")
@@ -179,6 +210,22 @@ func (e *EmailBatchService) Stop() error {
 
 // QueueEmail adds an email to the processing queue
 func (e *EmailBatchService) QueueEmail(email EmailJob) error {
+	if e.dnc != nil {
+		var err error
+		if email.To, err = e.allowedRecipients(email.To); err != nil {
+			return err
+		}
+		if email.CC, err = e.allowedRecipients(email.CC); err != nil {
+			return err
+		}
+		if email.BCC, err = e.allowedRecipients(email.BCC); err != nil {
+			return err
+		}
+		if len(email.To) == 0 {
+			return ErrRecipientOnDNC
+		}
+	}
+
 	email.ID = fmt.Sprintf("email_%d_%s", time.Now().UnixNano(), email.Subject[:utils.Min(10, len(email.Subject))])
 	email.CreatedAt = time.Now()
 
@@ -207,6 +254,22 @@ func (e *EmailBatchService) QueueEmail(email EmailJob) error {
 	}
 }
 
+// allowedRecipients removes addresses on the do-not-contact list
+func (e *EmailBatchService) allowedRecipients(recipients []string) ([]string, error) {
+	allowed := recipients[:0:0]
+	for _, recipient := range recipients {
+		err := e.dnc.CheckEmail(recipient, "email_batch", nil)
+		if errors.Is(err, ErrRecipientOnDNC) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		allowed = append(allowed, recipient)
+	}
+	return allowed, nil
+}
+
 // batchCreator groups emails into batches
 func (e *EmailBatchService) batchCreator() {
 	currentBatch := EmailBatch{
@@ -294,7 +357,7 @@ func (e *EmailBatchService) emailWorker(id int) {
 			<-rateLimiter.C
 
 			startTime := time.Now()
-			success, err := e.sendEmail(email)
+			success, err := e.sendEmail(&email)
 			deliveryTime := time.Since(startTime)
 
 			result := EmailResult{
@@ -330,6 +393,13 @@ func (e *EmailBatchService) emailWorker(id int) {
 						log.Printf("⚠️  Failed to requeue email %s for retry", retryEmail.ID)
 					}
 				}(email)
+			} else if !success {
+				e.mutex.RLock()
+				handler := e.failureHandler
+				e.mutex.RUnlock()
+				if handler != nil {
+					handler(email, err)
+				}
 			}
 		}
 
@@ -339,17 +409,25 @@ func (e *EmailBatchService) emailWorker(id int) {
 	}
 }
 
-// sendEmail sends a single email via AWS SES
-func (e *EmailBatchService) sendEmail(email EmailJob) (bool, error) {
+// sendEmail sends a single email via pooled SMTP or AWS SES
+func (e *EmailBatchService) sendEmail(email *EmailJob) (bool, error) {
 	log.Printf("📨 Sending email %s to %v: %s", email.ID, email.To, email.Subject)
 
+	if e.smtpPool != nil {
+		return e.sendEmailSMTP(email)
+	}
 	if e.awsService == nil {
 		return false, fmt.Errorf("AWS email service not initialized - check AWS credentials")
 	}
 
 	for _, recipient := range email.To {
+		if email.deliveredTo(recipient) {
+			continue
+		}
 		var err error
-		if email.HTMLBody != "" {
+		if len(email.Headers) > 0 {
+			err = e.awsService.SendEmailWithHeaders(recipient, email.Subject, email.HTMLBody, email.Body, email.Headers)
+		} else if email.HTMLBody != "" {
 			err = e.awsService.SendEmail(recipient, email.Subject, email.HTMLBody, email.Body)
 		} else {
 			err = e.awsService.SendEmail(recipient, email.Subject, email.Body, email.Body)
@@ -357,11 +435,50 @@ func (e *EmailBatchService) sendEmail(email EmailJob) (bool, error) {
 		if err != nil {
 			return false, fmt.Errorf("failed to send email to %s: %w", recipient, err)
 		}
+		email.Delivered = append(email.Delivered, recipient)
 	}
 
 	return true, nil
 }
 
+// sendEmailSMTP sends each recipient their own copy over a pooled connection.
+// Recipients are recorded on the job as they are sent so a retry after a
+// failure part way through only goes to the rest.
+func (e *EmailBatchService) sendEmailSMTP(email *EmailJob) (bool, error) {
+	bodyText := email.Body
+	if bodyText == "" {
+		bodyText = stripHTMLBasic(email.HTMLBody)
+	}
+	from := e.smtpPool.smtpConfig.From
+	for _, recipient := range email.To {
+		if email.deliveredTo(recipient) {
+			continue
+		}
+		msg, err := buildRawEmail(from, recipient, email.Subject, email.HTMLBody, bodyText, email.Headers)
+		if err != nil {
+			return false, fmt.Errorf("failed to build email: %w", err)
+		}
+		if err := e.smtpPool.Send(from, []string{recipient}, msg); errors.Is(err, ErrSMTPDeliveryUnconfirmed) {
+			// The server may already have the message; sending again could deliver it twice
+			log.Printf("⚠️  Delivery of email %s to %s unconfirmed, not resending: %v", email.ID, recipient, err)
+		} else if err != nil {
+			return false, fmt.Errorf("failed to send email to %s: %w", recipient, err)
+		}
+		email.Delivered = append(email.Delivered, recipient)
+	}
+	return true, nil
+}
+
+// deliveredTo reports whether an earlier attempt already sent the job to recipient
+func (job *EmailJob) deliveredTo(recipient string) bool {
+	for _, delivered := range job.Delivered {
+		if delivered == recipient {
+			return true
+		}
+	}
+	return false
+}
+
 // resultProcessor handles email sending results
 func (e *EmailBatchService) resultProcessor() {
 	for result := range e.resultQueue {
@@ -477,7 +594,7 @@ func (e *EmailBatchService) GetStats() map[string]interface{} {
 		deliveryRate = (float64(e.sentEmails) / float64(e.totalEmails)) * 100
 	}
 
-	return map[string]interface{}{
+	stats := map[string]interface{}{
 		"total_emails":          e.totalEmails,
 		"sent_emails":           e.sentEmails,
 		"failed_emails":         e.failedEmails,
@@ -495,6 +612,10 @@ func (e *EmailBatchService) GetStats() map[string]interface{} {
 			"rate_limit_per_second": e.rateLimitPerSecond,
 		},
 	}
+	if e.smtpPool != nil {
+		stats["smtp_pool"] = e.smtpPool.Stats()
+	}
+	return stats
 }
 
 
@@ -7,9 +7,13 @@ import (
 	"chrisgross-ctrl-project/internal/models"
 )
 
+// DefaultLowConfidenceThreshold is the confidence below which a parsed email is flagged for review
+const DefaultLowConfidenceThreshold = 0.6
+
 // EmailProcessor handles email processing for pre-listings
 type EmailProcessor struct {
-	db *gorm.DB
+	db                     *gorm.DB
+	lowConfidenceThreshold float64
 }
 
 // EmailProcessingRequest represents an email to be processed
@@ -38,17 +42,38 @@ type EmailProcessingStats struct {
 	FailedEmails    int64     `json:"failed_emails"`
 	LowConfidence   int64     `json:"low_confidence"`
 	LastProcessed   time.Time `json:"last_processed"`
+
+	LowConfidenceThreshold float64            `json:"low_confidence_threshold"`
+	ConfidenceBuckets      []ConfidenceBucket `json:"confidence_buckets"`
+}
+
+// ConfidenceBucket counts parsed emails whose confidence falls in [Min, Max)
+type ConfidenceBucket struct {
+	Label string  `json:"label"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int64   `json:"count"`
 }
 
 // NewEmailProcessor creates a new email processor
 func NewEmailProcessor(db *gorm.DB) *EmailProcessor {
 	return &EmailProcessor{
-		db: db,
+		db:                     db,
+		lowConfidenceThreshold: DefaultLowConfidenceThreshold,
+	}
+}
+
+// SetLowConfidenceThreshold overrides the confidence below which emails count as low confidence
+func (ep *EmailProcessor) SetLowConfidenceThreshold(threshold float64) {
+	if threshold > 0 && threshold <= 1 {
+		ep.lowConfidenceThreshold = threshold
 	}
 }
 
 // ProcessEmail processes an incoming email
 func (ep *EmailProcessor) ProcessEmail(req *EmailProcessingRequest) (*EmailProcessingResult, error) {
+	result := ep.parseEmail(req)
+
 	// Create incoming email record
 	incomingEmail := &models.IncomingEmail{
 		FromEmail:        req.From,
@@ -57,26 +82,29 @@ func (ep *EmailProcessor) ProcessEmail(req *EmailProcessingRequest) (*EmailProce
 		Content:          req.Content,
 		ReceivedAt:       req.ReceivedAt,
 		ProcessingStatus: "processed",
-		EmailType:        "pre_listing",
-		Confidence:       0.8,
+		EmailType:        result.EmailType,
+		Confidence:       float64(result.Confidence),
 	}
 
 	if err := ep.db.Create(incomingEmail).Error; err != nil {
 		return nil, err
 	}
 
+	result.IncomingEmail = incomingEmail
+	return result, nil
+}
+
+// parseEmail extracts listing data from an email without storing anything
+func (ep *EmailProcessor) parseEmail(req *EmailProcessingRequest) *EmailProcessingResult {
 	// Mock processing result
-	result := &EmailProcessingResult{
+	return &EmailProcessingResult{
 		Success:    true,
 		EmailType:  "pre_listing",
 		Confidence: 0.8,
 		ExtractedData: map[string]interface{}{
 			"address": "Sample Address",
 		},
-		IncomingEmail: incomingEmail,
 	}
-
-	return result, nil
 }
 
 // GetEmailProcessingStats returns processing statistics
@@ -86,7 +114,25 @@ func (ep *EmailProcessor) GetEmailProcessingStats() (*EmailProcessingStats, erro
 	ep.db.Model(&models.IncomingEmail{}).Count(&stats.TotalEmails)
 	ep.db.Model(&models.IncomingEmail{}).Where("processing_status = ?", "processed").Count(&stats.ProcessedEmails)
 	ep.db.Model(&models.IncomingEmail{}).Where("processing_status = ?", "failed").Count(&stats.FailedEmails)
-	ep.db.Model(&models.IncomingEmail{}).Where("confidence < ?", 0.5).Count(&stats.LowConfidence)
+	ep.db.Model(&models.IncomingEmail{}).Where("confidence < ?", ep.lowConfidenceThreshold).Count(&stats.LowConfidence)
+	stats.LowConfidenceThreshold = ep.lowConfidenceThreshold
+
+	stats.ConfidenceBuckets = []ConfidenceBucket{
+		{Label: "0.0-0.5", Min: 0, Max: 0.5},
+		{Label: "0.5-0.8", Min: 0.5, Max: 0.8},
+		{Label: "0.8-1.0", Min: 0.8, Max: 1.0},
+	}
+	for i := range stats.ConfidenceBuckets {
+		bucket := &stats.ConfidenceBuckets[i]
+		query := ep.db.Model(&models.IncomingEmail{}).Where("confidence >= ?", bucket.Min)
+		if bucket.Max < 1.0 {
+			query = query.Where("confidence < ?", bucket.Max)
+		} else {
+			// Top bucket is inclusive so perfect-confidence parses are counted
+			query = query.Where("confidence <= ?", bucket.Max)
+		}
+		query.Count(&bucket.Count)
+	}
 
 	var lastEmail models.IncomingEmail
 	if err := ep.db.Order("received_at DESC").First(&lastEmail).Error; err == nil {
@@ -95,3 +141,80 @@ func (ep *EmailProcessor) GetEmailProcessingStats() (*EmailProcessingStats, erro
 
 	return &stats, nil
 }
+
+// ReprocessFilter narrows which failed emails are reprocessed
+type ReprocessFilter struct {
+	SenderEmail string
+	Since       *time.Time
+}
+
+// ReprocessSummary reports the outcome of a batch reprocess run
+type ReprocessSummary struct {
+	Reprocessed int `json:"reprocessed"`
+	Succeeded   int `json:"succeeded"`
+	StillFailed int `json:"still_failed"`
+}
+
+// FindReprocessableEmails returns failed or review-pending emails matching the filter
+func (ep *EmailProcessor) FindReprocessableEmails(filter ReprocessFilter) ([]models.IncomingEmail, error) {
+	query := ep.db.Where("processing_status IN ?", []string{models.ProcessingStatusFailed, models.ProcessingStatusRequiresReview})
+	if filter.SenderEmail != "" {
+		query = query.Where("LOWER(from_email) = LOWER(?)", filter.SenderEmail)
+	}
+	if filter.Since != nil {
+		query = query.Where("received_at >= ?", *filter.Since)
+	}
+
+	var emails []models.IncomingEmail
+	if err := query.Order("received_at ASC").Find(&emails).Error; err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+// ReprocessEmails re-parses each stored email in place, updating its status and
+// confidence rather than storing it again. Emails that still parse below the
+// low-confidence threshold stay in review.
+// progress, if non-nil, is called after each email with the running summary.
+func (ep *EmailProcessor) ReprocessEmails(emails []models.IncomingEmail, progress func(ReprocessSummary)) ReprocessSummary {
+	var summary ReprocessSummary
+
+	for _, email := range emails {
+		summary.Reprocessed++
+
+		result := ep.parseEmail(&EmailProcessingRequest{
+			From:       email.FromEmail,
+			To:         email.ToEmail,
+			Subject:    email.Subject,
+			Content:    email.Content,
+			ReceivedAt: email.ReceivedAt,
+		})
+
+		updates := map[string]interface{}{
+			"confidence": result.Confidence,
+			"email_type": result.EmailType,
+		}
+		parsed := result.Success && float64(result.Confidence) >= ep.lowConfidenceThreshold
+		switch {
+		case parsed:
+			updates["processing_status"] = models.ProcessingStatusProcessed
+		case result.Success:
+			updates["processing_status"] = models.ProcessingStatusRequiresReview
+		default:
+			updates["processing_status"] = models.ProcessingStatusFailed
+		}
+
+		err := ep.db.Model(&models.IncomingEmail{}).Where("id = ?", email.ID).Updates(updates).Error
+		if err == nil && parsed {
+			summary.Succeeded++
+		} else {
+			summary.StillFailed++
+		}
+
+		if progress != nil {
+			progress(summary)
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestReprocessEmails_UpdatesOriginalsInPlace(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.PreListingItem{}, &models.IncomingEmail{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	for _, status := range []string{models.ProcessingStatusFailed, models.ProcessingStatusRequiresReview} {
+		db.Create(&models.IncomingEmail{FromEmail: "terry@example.com", ToEmail: "team@example.com", Subject: "New listing",
+			ReceivedAt: time.Now(), ProcessingStatus: status, Confidence: 0.2})
+	}
+
+	processor := NewEmailProcessor(db)
+	processor.SetLowConfidenceThreshold(0.9)
+	emails, _ := processor.FindReprocessableEmails(ReprocessFilter{})
+	if summary := processor.ReprocessEmails(emails, nil); summary.Reprocessed != 2 || summary.StillFailed != 2 {
+		t.Fatalf("Expected both emails still below the threshold, got %+v", summary)
+	}
+
+	processor.SetLowConfidenceThreshold(0.6)
+	emails, _ = processor.FindReprocessableEmails(ReprocessFilter{})
+	if len(emails) != 2 {
+		t.Fatalf("Expected low-confidence emails left in review, got %d", len(emails))
+	}
+	if summary := processor.ReprocessEmails(emails, nil); summary.Succeeded != 2 {
+		t.Fatalf("Expected both emails to parse, got %+v", summary)
+	}
+
+	var stored []models.IncomingEmail
+	db.Find(&stored)
+	if len(stored) != 2 {
+		t.Fatalf("Expected reprocessing not to store the emails again, got %d rows", len(stored))
+	}
+	for _, email := range stored {
+		if email.ProcessingStatus != models.ProcessingStatusProcessed || email.Confidence < 0.6 {
+			t.Errorf("Expected email %d processed with its new confidence, got %s at %.2f", email.ID, email.ProcessingStatus, email.Confidence)
+		}
+	}
+}
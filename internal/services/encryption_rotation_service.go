@@ -0,0 +1,299 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// encryptionRotationBatchSize is the number of rows re-encrypted per batch
+const encryptionRotationBatchSize = 200
+
+// EncryptedColumns lists the encrypted PII columns of each table that key rotation covers
+var EncryptedColumns = map[string][]string{
+	"lead_reengagements": {"email", "phone", "first_name", "last_name"},
+	"bookings":           {"email", "name", "phone"},
+	"contacts":           {"name", "phone", "email"},
+	"closing_pipelines":  {"tenant_name", "tenant_email", "tenant_phone"},
+	"sms_send_logs":      {"to_phone"},
+}
+
+// EncryptionRotationService re-encrypts stored PII with the current encryption key
+type EncryptionRotationService struct {
+	db                *gorm.DB
+	encryptionManager *security.EncryptionManager
+
+	mutex   sync.Mutex
+	running map[string]string // table -> running job ID
+}
+
+// NewEncryptionRotationService creates a new encryption rotation service
+func NewEncryptionRotationService(db *gorm.DB, encryptionManager *security.EncryptionManager) *EncryptionRotationService {
+	return &EncryptionRotationService{
+		db:                db,
+		encryptionManager: encryptionManager,
+		running:           make(map[string]string),
+	}
+}
+
+// RotatableTables returns the tables that can be re-encrypted, sorted by name
+func RotatableTables() []string {
+	tables := make([]string, 0, len(EncryptedColumns))
+	for table := range EncryptedColumns {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// StartRotation starts re-encrypting a table's PII to the current key in the background
+func (s *EncryptionRotationService) StartRotation(table, requestedBy string) (*models.EncryptionRotationJob, error) {
+	if _, ok := EncryptedColumns[table]; !ok {
+		return nil, fmt.Errorf("table %q has no registered encrypted columns", table)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if jobID, busy := s.running[table]; busy {
+		return nil, fmt.Errorf("rotation job %s is already running for %s", jobID, table)
+	}
+
+	job := &models.EncryptionRotationJob{
+		ID:          uuid.New().String(),
+		TargetTable: table,
+		TargetKeyID: s.encryptionManager.CurrentKeyID(),
+		Status:      models.RotationStatusRunning,
+		RequestedBy: requestedBy,
+		StartedAt:   time.Now(),
+	}
+	if err := s.db.Table(table).Count(&job.Total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count %s rows: %w", table, err)
+	}
+	if err := s.db.Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create rotation job: %w", err)
+	}
+
+	s.running[table] = job.ID
+	started := *job
+	go s.runRotation(job)
+
+	log.Printf("🔑 Key rotation job %s started for %s (%d rows)", job.ID, table, job.Total)
+	return &started, nil
+}
+
+// EncryptionTableStatus reports how much of a table's PII is under an old key
+type EncryptionTableStatus struct {
+	Table      string   `json:"table"`
+	Columns    []string `json:"columns"`
+	Rows       int64    `json:"rows"`
+	StaleRows  int64    `json:"stale_rows"` // Rows with a value under a previous key
+	RunningJob string   `json:"running_job,omitempty"`
+}
+
+// EncryptionStatus summarizes the current key and how far stored PII has been
+// re-encrypted to it
+type EncryptionStatus struct {
+	CurrentKeyID    string                        `json:"current_key_id"`
+	ActiveKeys      int64                         `json:"active_keys"`
+	EncryptedFields int                           `json:"encrypted_fields"`
+	StaleRows       int64                         `json:"stale_rows"`
+	NeedsRotation   bool                          `json:"needs_rotation"`
+	LastKeyRotation *time.Time                    `json:"last_key_rotation"` // When a key was last retired
+	LastJob         *models.EncryptionRotationJob `json:"last_job"`
+	Tables          []EncryptionTableStatus       `json:"tables"`
+}
+
+// Status reports the current key version, the registered encrypted fields and
+// the rows still holding values under a previous key
+func (s *EncryptionRotationService) Status() (*EncryptionStatus, error) {
+	currentKeyID := s.encryptionManager.CurrentKeyID()
+	status := &EncryptionStatus{CurrentKeyID: currentKeyID, Tables: []EncryptionTableStatus{}}
+
+	if err := s.db.Model(&security.EncryptionKey{}).Where("is_active = ?", true).Count(&status.ActiveKeys).Error; err != nil {
+		return nil, fmt.Errorf("failed to count encryption keys: %w", err)
+	}
+	var retired security.EncryptionKey
+	if err := s.db.Where("rotated_at IS NOT NULL").Order("rotated_at DESC").Limit(1).Find(&retired).Error; err != nil {
+		return nil, fmt.Errorf("failed to load encryption keys: %w", err)
+	}
+	status.LastKeyRotation = retired.RotatedAt
+
+	var jobs []models.EncryptionRotationJob
+	if err := s.db.Order("started_at DESC").Limit(1).Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load rotation jobs: %w", err)
+	}
+	if len(jobs) > 0 {
+		status.LastJob = &jobs[0]
+	}
+
+	s.mutex.Lock()
+	running := make(map[string]string, len(s.running))
+	for table, jobID := range s.running {
+		running[table] = jobID
+	}
+	s.mutex.Unlock()
+
+	// Encrypted values are JSON that starts with their key ID
+	stale := fmt.Sprintf(`{"key_id":"%s"%%`, currentKeyID)
+	for _, table := range RotatableTables() {
+		columns := EncryptedColumns[table]
+		tableStatus := EncryptionTableStatus{Table: table, Columns: columns, RunningJob: running[table]}
+		status.EncryptedFields += len(columns)
+		if !s.db.Migrator().HasTable(table) {
+			status.Tables = append(status.Tables, tableStatus)
+			continue
+		}
+		if err := s.db.Table(table).Count(&tableStatus.Rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to count %s rows: %w", table, err)
+		}
+
+		query := s.db.Table(table)
+		conditions := s.db
+		for i, column := range columns {
+			condition := fmt.Sprintf(`%s LIKE '{"key_id":%%' AND %s NOT LIKE ?`, column, column)
+			if i == 0 {
+				conditions = conditions.Where(condition, stale)
+			} else {
+				conditions = conditions.Or(condition, stale)
+			}
+		}
+		if err := query.Where(conditions).Count(&tableStatus.StaleRows).Error; err != nil {
+			return nil, fmt.Errorf("failed to count stale %s rows: %w", table, err)
+		}
+		status.StaleRows += tableStatus.StaleRows
+		status.Tables = append(status.Tables, tableStatus)
+	}
+	status.NeedsRotation = status.StaleRows > 0
+	return status, nil
+}
+
+// GetRotationJob returns a rotation job by ID
+func (s *EncryptionRotationService) GetRotationJob(jobID string) (*models.EncryptionRotationJob, error) {
+	var job models.EncryptionRotationJob
+	if err := s.db.First(&job, "id = ?", jobID).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// runRotation walks the table in ID order, re-encrypting any value not under the
+// current key and saving progress after each batch
+func (s *EncryptionRotationService) runRotation(job *models.EncryptionRotationJob) {
+	defer func() {
+		s.mutex.Lock()
+		delete(s.running, job.TargetTable)
+		s.mutex.Unlock()
+	}()
+
+	columns := EncryptedColumns[job.TargetTable]
+	for {
+		var rows []map[string]interface{}
+		err := s.db.Table(job.TargetTable).
+			Select(append([]string{"id"}, columns...)).
+			Where("id > ?", job.LastID).
+			Order("id ASC").
+			Limit(encryptionRotationBatchSize).
+			Find(&rows).Error
+		if err != nil {
+			s.finishRotation(job, models.RotationStatusFailed, err.Error())
+			return
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			id, _ := strconv.ParseUint(fmt.Sprint(row["id"]), 10, 64)
+			changed, skipped, err := s.reencryptRow(job.TargetTable, uint(id), columns, row)
+			if err != nil {
+				job.Failed++
+				job.LastError = fmt.Sprintf("row %d: %v", id, err)
+			} else if changed {
+				job.Reencrypted++
+			} else if skipped {
+				job.Skipped++
+			}
+			job.Processed++
+			job.LastID = uint(id)
+		}
+
+		s.db.Model(job).Updates(map[string]interface{}{
+			"processed":   job.Processed,
+			"reencrypted": job.Reencrypted,
+			"failed":      job.Failed,
+			"skipped":     job.Skipped,
+			"last_id":     job.LastID,
+			"last_error":  job.LastError,
+		})
+	}
+
+	s.finishRotation(job, models.RotationStatusCompleted, job.LastError)
+}
+
+// reencryptRow re-encrypts the row's columns that are under an old key,
+// reporting whether anything was rewritten. Each write only matches while the
+// column still holds the ciphertext that was read, so a value edited since the
+// batch was loaded is left alone; skipped reports a row where that happened to
+// every stale column.
+func (s *EncryptionRotationService) reencryptRow(table string, id uint, columns []string, row map[string]interface{}) (changed, skipped bool, err error) {
+	for _, column := range columns {
+		var value security.EncryptedString
+		switch v := row[column].(type) {
+		case string:
+			value = security.EncryptedString(v)
+		case []byte:
+			value = security.EncryptedString(v)
+		}
+
+		if !s.encryptionManager.NeedsReencryption(value) {
+			continue
+		}
+		reencrypted, err := s.encryptionManager.Reencrypt(value)
+		if err != nil {
+			return changed, false, fmt.Errorf("%s: %w", column, err)
+		}
+
+		// Column names come from EncryptedColumns, never from the request
+		result := s.db.Table(table).Where("id = ? AND "+column+" = ?", id, value).Update(column, reencrypted)
+		if result.Error != nil {
+			return changed, false, fmt.Errorf("%s: %w", column, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			skipped = true
+			continue
+		}
+		changed = true
+	}
+	return changed, skipped && !changed, nil
+}
+
+// finishRotation records the final job state
+func (s *EncryptionRotationService) finishRotation(job *models.EncryptionRotationJob, status, lastError string) {
+	completedAt := time.Now()
+	job.Status = status
+	job.LastError = lastError
+	job.CompletedAt = &completedAt
+
+	s.db.Model(job).Updates(map[string]interface{}{
+		"status":       status,
+		"processed":    job.Processed,
+		"reencrypted":  job.Reencrypted,
+		"failed":       job.Failed,
+		"skipped":      job.Skipped,
+		"last_id":      job.LastID,
+		"last_error":   lastError,
+		"completed_at": completedAt,
+	})
+
+	log.Printf("🔑 Key rotation job %s for %s %s: %d processed, %d failed, %d skipped",
+		job.ID, job.TargetTable, status, job.Processed, job.Failed, job.Skipped)
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestEncryptionKey(t *testing.T) string {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("Failed to generate test key: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestEncryptionRotation_ReencryptsOldKeyValuesToCurrentKey(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	// The rotation runs in a goroutine; keep it on the same in-memory database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&security.EncryptionKey{}, &models.LeadReengagement{}, &models.EncryptionRotationJob{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	oldKey, newKey := newTestEncryptionKey(t), newTestEncryptionKey(t)
+
+	t.Setenv("ENCRYPTION_KEY", oldKey)
+	oldManager, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create old encryption manager: %v", err)
+	}
+	email, _ := oldManager.EncryptEmail("rotate@example.com")
+	lead := models.LeadReengagement{FUBContactID: "fub-rotate", Email: email, Segment: models.SegmentActive, RiskLevel: models.RiskLow, ConsentStatus: models.ConsentUnknown}
+	if err := db.Create(&lead).Error; err != nil {
+		t.Fatalf("Failed to store lead: %v", err)
+	}
+
+	t.Setenv("ENCRYPTION_KEY", newKey)
+	t.Setenv("ENCRYPTION_PREVIOUS_KEYS", oldKey)
+	manager, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create rotated encryption manager: %v", err)
+	}
+	if !manager.NeedsReencryption(email) {
+		t.Fatal("Expected value under the previous key to need re-encryption")
+	}
+
+	service := NewEncryptionRotationService(db, manager)
+	status, err := service.Status()
+	if err != nil {
+		t.Fatalf("Failed to load encryption status: %v", err)
+	}
+	if status.CurrentKeyID != manager.CurrentKeyID() || status.StaleRows != 1 || !status.NeedsRotation || status.LastKeyRotation == nil {
+		t.Errorf("Expected one stale row under the retired key, got %+v", status)
+	}
+
+	job, err := service.StartRotation("lead_reengagements", "tester")
+	if err != nil {
+		t.Fatalf("Failed to start rotation: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status == models.RotationStatusRunning && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		if job, err = service.GetRotationJob(job.ID); err != nil {
+			t.Fatalf("Failed to load rotation job: %v", err)
+		}
+	}
+	if job.Status != models.RotationStatusCompleted || job.Reencrypted != 1 || job.Failed != 0 {
+		t.Fatalf("Expected completed job with 1 re-encrypted row, got %+v", job)
+	}
+
+	if status, err := service.Status(); err != nil || status.StaleRows != 0 || status.LastJob == nil || status.LastJob.ID != job.ID {
+		t.Errorf("Expected no stale rows and the finished job after rotation, got %+v (%v)", status, err)
+	}
+
+	var stored models.LeadReengagement
+	db.First(&stored, lead.ID)
+	if manager.KeyID(stored.Email) != manager.CurrentKeyID() {
+		t.Errorf("Expected email to be encrypted with the current key")
+	}
+	if plaintext, err := manager.Decrypt(stored.Email); err != nil || plaintext != "rotate@example.com" {
+		t.Errorf("Expected re-encrypted email to decrypt, got %q (%v)", plaintext, err)
+	}
+}
+
+func TestEncryptionRotation_SkipsValuesEditedMidRotation(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&security.EncryptionKey{}, &models.LeadReengagement{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	oldKey := newTestEncryptionKey(t)
+	t.Setenv("ENCRYPTION_KEY", oldKey)
+	oldManager, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create old encryption manager: %v", err)
+	}
+	oldEmail, _ := oldManager.EncryptEmail("old@example.com")
+	oldPhone, _ := oldManager.Encrypt("5551230000")
+	lead := models.LeadReengagement{FUBContactID: "fub-edit", Email: oldEmail, Phone: oldPhone,
+		Segment: models.SegmentActive, RiskLevel: models.RiskLow, ConsentStatus: models.ConsentUnknown}
+	db.Create(&lead)
+
+	t.Setenv("ENCRYPTION_KEY", newTestEncryptionKey(t))
+	t.Setenv("ENCRYPTION_PREVIOUS_KEYS", oldKey)
+	manager, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create rotated encryption manager: %v", err)
+	}
+	service := NewEncryptionRotationService(db, manager)
+
+	// The batch was read before the user saved a new email
+	snapshot := map[string]interface{}{"id": lead.ID, "email": string(oldEmail), "phone": string(oldPhone)}
+	editedEmail, _ := manager.EncryptEmail("new@example.com")
+	db.Model(&lead).Update("email", editedEmail)
+
+	changed, skipped, err := service.reencryptRow("lead_reengagements", lead.ID, []string{"email", "phone"}, snapshot)
+	if err != nil || !changed || skipped {
+		t.Fatalf("Expected the unedited phone re-encrypted, got changed=%v skipped=%v err=%v", changed, skipped, err)
+	}
+	var stored models.LeadReengagement
+	db.First(&stored, lead.ID)
+	if stored.Email != editedEmail {
+		t.Error("Expected the user's edit to survive the rotation")
+	}
+	if manager.KeyID(stored.Phone) != manager.CurrentKeyID() {
+		t.Error("Expected the phone re-encrypted with the current key")
+	}
+
+	// Only the edited column was stale in this snapshot, so the row is skipped
+	changed, skipped, err = service.reencryptRow("lead_reengagements", lead.ID, []string{"email"}, snapshot)
+	if err != nil || changed || !skipped {
+		t.Errorf("Expected the row skipped, got changed=%v skipped=%v err=%v", changed, skipped, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
@@ -140,6 +141,35 @@ func (eco *EventCampaignOrchestrator) initializeEventMappings() {
 	}
 }
 
+// Triggers describes the event→campaign mappings, highest priority first
+func (eco *EventCampaignOrchestrator) Triggers() []CampaignTrigger {
+	triggers := make([]CampaignTrigger, 0, len(eco.eventMappings))
+	for eventType, mapping := range eco.eventMappings {
+		triggers = append(triggers, CampaignTrigger{
+			ID:          eventType,
+			Name:        mapping.CampaignName,
+			Type:        "event",
+			Condition:   fmt.Sprintf("%s event", eventType),
+			Enabled:     true,
+			AutoExecute: true,
+			Priority:    mapping.Priority,
+			Metadata:    map[string]interface{}{"cooldown_hours": mapping.CooldownHours},
+		})
+	}
+	sort.Slice(triggers, func(i, j int) bool {
+		if triggers[i].Priority != triggers[j].Priority {
+			return triggers[i].Priority < triggers[j].Priority
+		}
+		return triggers[i].ID < triggers[j].ID
+	})
+	return triggers
+}
+
+// CooldownFor returns how long to wait between campaigns for an event type per lead
+func (eco *EventCampaignOrchestrator) CooldownFor(eventType string) time.Duration {
+	return time.Duration(eco.eventMappings[eventType].CooldownHours) * time.Hour
+}
+
 // ProcessEvent is the main orchestration entry point
 func (eco *EventCampaignOrchestrator) ProcessEvent(eventType string, eventData map[string]interface{}) error {
 	log.Printf("🎵 Orchestrating campaign for event: %s", eventType)
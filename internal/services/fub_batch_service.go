@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -28,8 +29,21 @@ type FUBOperation struct {
 	BookingID   uint                   `json:"booking_id,omitempty"`
 	CreatedAt   time.Time              `json:"created_at"`
 	ScheduledAt time.Time              `json:"scheduled_at"`
+
+	// Set for FUBAPIRequest operations queued through Do
+	request func() (*http.Response, error)
+	done    chan fubRequestResult
+}
+
+// fubRequestResult is handed back to the caller of Do
+type fubRequestResult struct {
+	resp *http.Response
+	err  error
 }
 
+// ErrFUBBatchQueueFull is returned when no more FUB operations can be queued
+var ErrFUBBatchQueueFull = errors.New("FUB operation queue is full")
+
 // FUBOperationType defines the type of FUB operation
 type FUBOperationType string
 
@@ -41,6 +55,7 @@ const (
 	FUBUpdateContact    FUBOperationType = "update_contact"
 	FUBBulkSync         FUBOperationType = "bulk_sync"
 	FUBWebhookProcessor FUBOperationType = "webhook_processor"
+	FUBAPIRequest       FUBOperationType = "api_request" // A caller's own request, run through Do
 )
 
 // FUBBatchResult represents the result of a batch operation
@@ -62,6 +77,9 @@ type FUBBatchStats struct {
 	AverageProcessTime int64     `json:"average_process_time_ms"`
 	RateLimitHits      int64     `json:"rate_limit_hits"`
 	LastBatchProcessed time.Time `json:"last_batch_processed"`
+	LastFlushSize      int       `json:"last_flush_size"`
+	LastFlushDuration  int64     `json:"last_flush_duration_ms"`
+	QueueDepth         int       `json:"queue_depth"`
 }
 
 // FUBBatchService handles batched FUB API operations for improved performance
@@ -70,15 +88,17 @@ type FUBBatchService struct {
 	redis        *redis.Client
 	apiKey       string
 	apiURL       string
-	batchSize    int
-	rateLimitPer time.Duration
-	maxRetries   int
+	batchSize     int
+	flushInterval time.Duration
+	limiter       *fubTokenBucket
+	maxRetries    int
 
 	// Batch processing
 	operationQueue chan *FUBOperation
 	batchTicker    *time.Ticker
 	processingMux  sync.RWMutex
 	isProcessing   bool
+	statsMux       sync.Mutex
 	stats          *FUBBatchStats
 
 	// HTTP client with timeouts
@@ -98,7 +118,8 @@ func NewFUBBatchService(db *gorm.DB, redis *redis.Client) *FUBBatchService {
 		apiKey:         apiKey,
 		apiURL:         "https://api.followupboss.com/v1",
 		batchSize:      10,          // Process in batches of 10 operations
-		rateLimitPer:   time.Second, // Rate limit: 1 request per second
+		flushInterval:  time.Second, // Flush partial batches every second
+		limiter:        newFUBTokenBucket(defaultFUBRatePerMinute, defaultFUBRateBurst),
 		maxRetries:     3,
 		operationQueue: make(chan *FUBOperation, 1000), // Buffer up to 1000 operations
 		stats:          &FUBBatchStats{},
@@ -107,15 +128,54 @@ func NewFUBBatchService(db *gorm.DB, redis *redis.Client) *FUBBatchService {
 		},
 	}
 
-	// Start batch processing
-	if apiKey != "" {
-		service.startBatchProcessor()
-		log.Println("🔄 FUB batch processing service started")
-	}
+	// Requests queued through Do carry their own credentials, so the
+	// processor runs even without FUB_API_TOKEN
+	service.startBatchProcessor()
+	log.Println("🔄 FUB batch processing service started")
 
 	return service
 }
 
+// SetRateLimit sets how many FUB API calls per minute the batch processor
+// makes, allowing bursts of up to burst calls
+func (fbs *FUBBatchService) SetRateLimit(perMinute, burst int) {
+	fbs.limiter.configure(perMinute, burst)
+}
+
+// Do queues a FUB API request and waits for the batch processor to run it
+// under the rate limit. Lower priority numbers run first within a batch.
+// The caller owns the returned response body.
+func (fbs *FUBBatchService) Do(ctx context.Context, priority int, request func() (*http.Response, error)) (*http.Response, error) {
+	operation := &FUBOperation{
+		ID:          fmt.Sprintf("api_request_%d", time.Now().UnixNano()),
+		Type:        FUBAPIRequest,
+		Priority:    priority,
+		CreatedAt:   time.Now(),
+		ScheduledAt: time.Now(),
+		request:     request,
+		done:        make(chan fubRequestResult, 1),
+	}
+
+	select {
+	case fbs.operationQueue <- operation:
+	default:
+		return nil, ErrFUBBatchQueueFull
+	}
+
+	select {
+	case result := <-operation.done:
+		return result.resp, result.err
+	case <-ctx.Done():
+		// The request may still run; close its body since nobody will read it
+		go func() {
+			if result := <-operation.done; result.resp != nil {
+				result.resp.Body.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
 // QueueCreateLead queues a lead creation operation
 func (fbs *FUBBatchService) QueueCreateLead(contactID uint, leadData map[string]interface{}) error {
 	if fbs.apiKey == "" {
@@ -139,7 +199,7 @@ func (fbs *FUBBatchService) QueueCreateLead(contactID uint, leadData map[string]
 		log.Printf("🔄 Queued FUB lead creation for contact %d", contactID)
 		return nil
 	default:
-		return fmt.Errorf("FUB operation queue is full")
+		return ErrFUBBatchQueueFull
 	}
 }
 
@@ -164,7 +224,7 @@ func (fbs *FUBBatchService) QueueUpdateLead(fubLeadID string, updateData map[str
 		log.Printf("🔄 Queued FUB lead update for %s", fubLeadID)
 		return nil
 	default:
-		return fmt.Errorf("FUB operation queue is full")
+		return ErrFUBBatchQueueFull
 	}
 }
 
@@ -189,7 +249,7 @@ func (fbs *FUBBatchService) QueueCreateNote(fubLeadID string, noteData map[strin
 		log.Printf("🔄 Queued FUB note creation for %s", fubLeadID)
 		return nil
 	default:
-		return fmt.Errorf("FUB operation queue is full")
+		return ErrFUBBatchQueueFull
 	}
 }
 
@@ -222,7 +282,7 @@ func (fbs *FUBBatchService) QueueBulkSync(contactIDs []uint) error {
 		case fbs.operationQueue <- operation:
 			log.Printf("🔄 Queued FUB bulk sync for %d contacts (chunk %d)", len(chunk), i/chunkSize+1)
 		default:
-			return fmt.Errorf("FUB operation queue is full")
+			return ErrFUBBatchQueueFull
 		}
 	}
 
@@ -233,7 +293,7 @@ func (fbs *FUBBatchService) QueueBulkSync(contactIDs []uint) error {
 func (fbs *FUBBatchService) startBatchProcessor() {
 	// Process operations immediately as they come in, with rate limiting
 	go func() {
-		ticker := time.NewTicker(fbs.rateLimitPer)
+		ticker := time.NewTicker(fbs.flushInterval)
 		defer ticker.Stop()
 
 		batch := make([]*FUBOperation, 0, fbs.batchSize)
@@ -272,6 +332,10 @@ func (fbs *FUBBatchService) startBatchProcessor() {
 		}
 	}()
 
+	if fbs.apiKey == "" {
+		return
+	}
+
 	// Periodic bulk sync processor
 	go func() {
 		bulkTicker := time.NewTicker(30 * time.Minute) // Run bulk sync every 30 minutes
@@ -314,15 +378,23 @@ func (fbs *FUBBatchService) processBatch(batch []*FUBOperation) {
 	log.Printf("🔄 Processing FUB batch of %d operations", len(batch))
 
 	for _, operation := range batch {
+		// Rate limiting
+		fbs.limiter.Wait(context.Background())
+
 		result := fbs.processOperation(operation)
 		results = append(results, result)
 
 		// Update stats
+		fbs.statsMux.Lock()
 		fbs.stats.TotalOperations++
 		if result.Success {
 			fbs.stats.SuccessfulOps++
 		} else {
 			fbs.stats.FailedOps++
+		}
+		fbs.statsMux.Unlock()
+
+		if !result.Success {
 
 			// Retry failed operations
 			if operation.RetryCount < operation.MaxRetries {
@@ -331,7 +403,7 @@ func (fbs *FUBBatchService) processBatch(batch []*FUBOperation) {
 
 				select {
 				case fbs.operationQueue <- operation:
-					fbs.stats.RetryOps++
+					fbs.recordRetry()
 					log.Printf("🔄 Retrying FUB operation %s (attempt %d/%d)",
 						operation.ID, operation.RetryCount, operation.MaxRetries)
 				default:
@@ -339,17 +411,18 @@ func (fbs *FUBBatchService) processBatch(batch []*FUBOperation) {
 				}
 			}
 		}
-
-		// Rate limiting
-		time.Sleep(fbs.rateLimitPer)
 	}
 
 	processingTime := time.Since(startTime)
+	fbs.statsMux.Lock()
 	fbs.stats.AverageProcessTime = processingTime.Milliseconds() / int64(len(batch))
 	fbs.stats.LastBatchProcessed = time.Now()
+	fbs.stats.LastFlushSize = len(batch)
+	fbs.stats.LastFlushDuration = processingTime.Milliseconds()
+	fbs.statsMux.Unlock()
 
 	log.Printf("✅ FUB batch processed: %d operations in %v (avg: %dms per op)",
-		len(batch), processingTime, fbs.stats.AverageProcessTime)
+		len(batch), processingTime, processingTime.Milliseconds()/int64(len(batch)))
 
 	// Cache results for debugging
 	fbs.cacheResults(results)
@@ -366,6 +439,8 @@ func (fbs *FUBBatchService) processOperation(operation *FUBOperation) *FUBBatchR
 		return fbs.processCreateNote(operation)
 	case FUBBulkSync:
 		return fbs.processBulkSync(operation)
+	case FUBAPIRequest:
+		return fbs.processAPIRequest(operation)
 	default:
 		return &FUBBatchResult{
 			OperationID: operation.ID,
@@ -375,6 +450,26 @@ func (fbs *FUBBatchService) processOperation(operation *FUBOperation) *FUBBatchR
 	}
 }
 
+// processAPIRequest runs a request queued through Do and hands its response
+// back to the caller. Retrying is left to the caller.
+func (fbs *FUBBatchService) processAPIRequest(operation *FUBOperation) *FUBBatchResult {
+	resp, err := operation.request()
+	operation.done <- fubRequestResult{resp: resp, err: err}
+
+	result := &FUBBatchResult{OperationID: operation.ID}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.HTTPStatus = resp.StatusCode
+	result.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if resp.StatusCode == 429 {
+		fbs.recordRateLimitHit()
+		result.Error = "Rate limited by FUB API"
+	}
+	return result
+}
+
 // processCreateLead handles lead creation via FUB API
 func (fbs *FUBBatchService) processCreateLead(operation *FUBOperation) *FUBBatchResult {
 	jsonPayload, err := json.Marshal(operation.Payload)
@@ -416,7 +511,7 @@ func (fbs *FUBBatchService) processCreateLead(operation *FUBOperation) *FUBBatch
 
 	// Handle rate limiting
 	if resp.StatusCode == 429 {
-		fbs.stats.RateLimitHits++
+		fbs.recordRateLimitHit()
 		result.Error = "Rate limited by FUB API"
 		return result
 	}
@@ -508,7 +603,7 @@ func (fbs *FUBBatchService) processUpdateLead(operation *FUBOperation) *FUBBatch
 	}
 
 	if resp.StatusCode == 429 {
-		fbs.stats.RateLimitHits++
+		fbs.recordRateLimitHit()
 		result.Error = "Rate limited by FUB API"
 	} else if !result.Success {
 		bodyBytes, _ := json.Marshal(resp.Body)
@@ -575,7 +670,7 @@ func (fbs *FUBBatchService) processCreateNote(operation *FUBOperation) *FUBBatch
 	}
 
 	if resp.StatusCode == 429 {
-		fbs.stats.RateLimitHits++
+		fbs.recordRateLimitHit()
 		result.Error = "Rate limited by FUB API"
 	} else if !result.Success {
 		bodyBytes, _ := json.Marshal(resp.Body)
@@ -637,6 +732,9 @@ func (fbs *FUBBatchService) processBulkSync(operation *FUBOperation) *FUBBatchRe
 			}
 		}
 
+		// Rate limiting within bulk operations
+		fbs.limiter.Wait(context.Background())
+
 		// Create individual operation for this contact
 		contactOp := &FUBOperation{
 			ID:          fmt.Sprintf("bulk_contact_%d_%d", contact.ID, time.Now().Unix()),
@@ -656,9 +754,6 @@ func (fbs *FUBBatchService) processBulkSync(operation *FUBOperation) *FUBBatchRe
 			errorCount++
 			log.Printf("⚠️ Bulk sync failed for contact %d: %s", contact.ID, result.Error)
 		}
-
-		// Rate limiting within bulk operations
-		time.Sleep(fbs.rateLimitPer)
 	}
 
 	return &FUBBatchResult{
@@ -719,8 +814,25 @@ func (fbs *FUBBatchService) cacheResults(results []*FUBBatchResult) {
 	fbs.redis.SetEx(ctx, cacheKey, resultsJSON, time.Hour).Err()
 }
 
+// recordRateLimitHit counts a 429 from the FUB API
+func (fbs *FUBBatchService) recordRateLimitHit() {
+	fbs.statsMux.Lock()
+	fbs.stats.RateLimitHits++
+	fbs.statsMux.Unlock()
+}
+
+// recordRetry counts a re-queued operation
+func (fbs *FUBBatchService) recordRetry() {
+	fbs.statsMux.Lock()
+	fbs.stats.RetryOps++
+	fbs.statsMux.Unlock()
+}
+
 // GetBatchStats returns current batch processing statistics
 func (fbs *FUBBatchService) GetBatchStats() *FUBBatchStats {
+	fbs.statsMux.Lock()
+	defer fbs.statsMux.Unlock()
+
 	return &FUBBatchStats{
 		TotalOperations:    fbs.stats.TotalOperations,
 		SuccessfulOps:      fbs.stats.SuccessfulOps,
@@ -729,6 +841,9 @@ func (fbs *FUBBatchService) GetBatchStats() *FUBBatchStats {
 		AverageProcessTime: fbs.stats.AverageProcessTime,
 		RateLimitHits:      fbs.stats.RateLimitHits,
 		LastBatchProcessed: fbs.stats.LastBatchProcessed,
+		LastFlushSize:      fbs.stats.LastFlushSize,
+		LastFlushDuration:  fbs.stats.LastFlushDuration,
+		QueueDepth:         len(fbs.operationQueue),
 	}
 }
 
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFUBTokenBucket_AllowsBurstThenPaces(t *testing.T) {
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	bucket := newFUBTokenBucket(60, 2)
+	bucket.now = func() time.Time { return now }
+	bucket.last = now
+
+	if bucket.reserve() != 0 || bucket.reserve() != 0 {
+		t.Fatal("Expected the burst to go through without waiting")
+	}
+	if wait := bucket.reserve(); wait != time.Second {
+		t.Errorf("Expected the third call to wait 1s at 60/min, got %v", wait)
+	}
+
+	now = now.Add(time.Minute)
+	if wait := bucket.reserve(); wait != 0 {
+		t.Errorf("Expected the bucket refilled after a minute, got %v", wait)
+	}
+}
+
+func TestBehavioralFUBAPIClient_RoutesWritesThroughBatch(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	batch := NewFUBBatchService(nil, nil)
+	batch.SetRateLimit(6000, 5)
+	client := NewBehavioralFUBAPIClient(nil, "test-key")
+	client.baseURL = server.URL
+	client.SetBatchService(batch)
+
+	if err := client.AddNote("fub-42", "Follow up", "Viewed 3 listings"); err != nil {
+		t.Fatalf("Expected the note to be added, got %v", err)
+	}
+	if len(methods) != 1 || methods[0] != "POST" {
+		t.Fatalf("Expected one POST to FUB, got %v", methods)
+	}
+
+	stats := batch.GetBatchStats()
+	if stats.TotalOperations != 1 || stats.SuccessfulOps != 1 || stats.LastFlushSize != 1 {
+		t.Errorf("Expected the note flushed as a batch of one, got %+v", stats)
+	}
+	if stats.QueueDepth != 0 {
+		t.Errorf("Expected an empty queue after the flush, got %d", stats.QueueDepth)
+	}
+}
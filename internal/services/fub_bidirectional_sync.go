@@ -2,6 +2,7 @@ package services
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -19,6 +20,8 @@ type FUBBidirectionalSync struct {
 	fubBaseURL         string
 	behavioralService  *BehavioralEventService
 	scoringEngine      *BehavioralScoringEngine
+	errorHandler       *FUBErrorHandler
+	httpClient         *http.Client
 }
 
 // NewFUBBidirectionalSync creates a new bi-directional sync service
@@ -29,9 +32,17 @@ func NewFUBBidirectionalSync(db *gorm.DB, fubAPIKey string) *FUBBidirectionalSyn
 		fubBaseURL:        "https://api.followupboss.com/v1",
 		behavioralService: NewBehavioralEventService(db),
 		scoringEngine:     NewBehavioralScoringEngine(db),
+		errorHandler:      NewFUBErrorHandler(),
+		httpClient:        &http.Client{Timeout: 10 * time.Second},
 	}
 }
 
+// SetErrorHandler shares an error handler so sync failures are classified and
+// counted alongside other FUB callers
+func (s *FUBBidirectionalSync) SetErrorHandler(errorHandler *FUBErrorHandler) {
+	s.errorHandler = errorHandler
+}
+
 // ============================================================================
 // PROPERTYHUB → FUB (Action Logging)
 // ============================================================================
@@ -377,7 +388,8 @@ func (s *FUBBidirectionalSync) getLeadIDFromFUBPerson(fubPersonID string) (int64
 	return int64(lead.ID), nil
 }
 
-// sendToFUB sends an API request to Follow Up Boss
+// sendToFUB sends an API request to Follow Up Boss, retrying rate-limited and
+// transient failures through the error handler
 func (s *FUBBidirectionalSync) sendToFUB(method string, endpoint string, payload map[string]interface{}) error {
 	url := s.fubBaseURL + endpoint
 
@@ -386,24 +398,22 @@ func (s *FUBBidirectionalSync) sendToFUB(method string, endpoint string, payload
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+	resp, err := s.errorHandler.ExecuteWithRetry(method+" "+endpoint, func() (*http.Response, error) {
+		req, err := http.NewRequest(method, url, bytes.NewBuffer(jsonData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Basic "+s.fubAPIKey)
+		// FUB API uses Basic Auth with API key as username and empty password
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(s.fubAPIKey+":")))
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+		return s.httpClient.Do(req)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("FUB API error: status %d", resp.StatusCode)
+		return err
 	}
+	resp.Body.Close()
 
 	return nil
 }
@@ -2,16 +2,39 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 	"chrisgross-ctrl-project/internal/utils"
 )
 
+// FUBErrorClass groups FUB API failures by how they should be handled
+type FUBErrorClass string
+
+const (
+	FUBErrorRateLimited FUBErrorClass = "rate_limited" // Retried after Retry-After
+	FUBErrorAuth        FUBErrorClass = "auth"         // API key rejected, a configuration problem
+	FUBErrorValidation  FUBErrorClass = "validation"   // Request rejected, not retried
+	FUBErrorNotFound    FUBErrorClass = "not_found"
+	FUBErrorServer      FUBErrorClass = "server"  // Retried with backoff
+	FUBErrorNetwork     FUBErrorClass = "network" // Retried with backoff
+)
+
+// ErrFUBAuthConfig is wrapped by FUB API errors caused by a rejected API key
+var ErrFUBAuthConfig = errors.New("FUB API key rejected - check the FUB_API_KEY setting")
+
+// recentFUBErrorLimit caps how many recent failures FUBErrorHandler keeps
+const recentFUBErrorLimit = 20
+
 // FUBAPIError represents a structured error from FUB API operations
 type FUBAPIError struct {
 	Operation  string                 `json:"operation"`
+	Class      FUBErrorClass          `json:"class"`
 	ErrorCode  string                 `json:"error_code"`
 	Message    string                 `json:"message"`
 	HTTPStatus int                    `json:"http_status"`
@@ -32,12 +55,33 @@ func (e *FUBAPIError) IsRetryable() bool {
 	return e.Retryable
 }
 
+// Unwrap lets errors.Is match ErrFUBAuthConfig for authentication failures
+func (e *FUBAPIError) Unwrap() error {
+	if e.Class == FUBErrorAuth {
+		return ErrFUBAuthConfig
+	}
+	return nil
+}
+
+// FUBErrorStats summarizes FUB API failures seen by a FUBErrorHandler
+type FUBErrorStats struct {
+	Counts          map[FUBErrorClass]int64 `json:"counts"`
+	Recent          []FUBAPIError           `json:"recent"`
+	LastAuthFailure *time.Time              `json:"last_auth_failure,omitempty"`
+}
+
 // FUBErrorHandler provides centralized error handling for FUB API operations
 type FUBErrorHandler struct {
 	maxRetries    int
 	baseDelay     time.Duration
 	maxDelay      time.Duration
 	backoffFactor float64
+	sleep         func(time.Duration)
+
+	failuresMux     sync.Mutex
+	counts          map[FUBErrorClass]int64
+	recent          []FUBAPIError
+	lastAuthFailure *time.Time
 }
 
 // NewFUBErrorHandler creates a new error handler with default settings
@@ -47,6 +91,8 @@ func NewFUBErrorHandler() *FUBErrorHandler {
 		baseDelay:     1 * time.Second,
 		maxDelay:      30 * time.Second,
 		backoffFactor: 2.0,
+		sleep:         time.Sleep,
+		counts:        make(map[FUBErrorClass]int64),
 	}
 }
 
@@ -74,40 +120,43 @@ func (eh *FUBErrorHandler) HandleHTTPResponse(resp *http.Response, operation str
 	// Map HTTP status codes to error details
 	switch resp.StatusCode {
 	case 400:
+		fubError.Class = FUBErrorValidation
 		fubError.ErrorCode = "BAD_REQUEST"
 		fubError.Message = "Invalid request data or parameters"
 		fubError.Retryable = false
 	case 401:
+		fubError.Class = FUBErrorAuth
 		fubError.ErrorCode = "UNAUTHORIZED"
 		fubError.Message = "API authentication failed - check API key"
 		fubError.Retryable = false
 	case 403:
+		fubError.Class = FUBErrorAuth
 		fubError.ErrorCode = "FORBIDDEN"
 		fubError.Message = "API access denied - insufficient permissions"
 		fubError.Retryable = false
 	case 404:
+		fubError.Class = FUBErrorNotFound
 		fubError.ErrorCode = "NOT_FOUND"
 		fubError.Message = "Requested resource not found"
 		fubError.Retryable = false
 	case 422:
+		fubError.Class = FUBErrorValidation
 		fubError.ErrorCode = "UNPROCESSABLE_ENTITY"
 		fubError.Message = "Request data validation failed"
 		fubError.Retryable = false
 	case 429:
+		fubError.Class = FUBErrorRateLimited
 		fubError.ErrorCode = "RATE_LIMITED"
 		fubError.Message = "API rate limit exceeded"
 		fubError.Retryable = true
-		// Extract retry-after header if present
-		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
-			if duration, err := time.ParseDuration(retryAfter + "s"); err == nil {
-				fubError.RetryAfter = &duration
-			}
-		}
+		fubError.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
 	case 500:
+		fubError.Class = FUBErrorServer
 		fubError.ErrorCode = "INTERNAL_SERVER_ERROR"
 		fubError.Message = "FUB API internal server error"
 		fubError.Retryable = true
 	case 502, 503, 504:
+		fubError.Class = FUBErrorServer
 		fubError.ErrorCode = "SERVICE_UNAVAILABLE"
 		fubError.Message = "FUB API service temporarily unavailable"
 		fubError.Retryable = true
@@ -115,6 +164,10 @@ func (eh *FUBErrorHandler) HandleHTTPResponse(resp *http.Response, operation str
 		fubError.ErrorCode = "UNKNOWN_ERROR"
 		fubError.Message = fmt.Sprintf("Unexpected HTTP status: %d", resp.StatusCode)
 		fubError.Retryable = resp.StatusCode >= 500
+		fubError.Class = FUBErrorValidation
+		if fubError.Retryable {
+			fubError.Class = FUBErrorServer
+		}
 	}
 
 	// Extract detailed error message from response if available
@@ -134,7 +187,9 @@ func (eh *FUBErrorHandler) HandleHTTPResponse(resp *http.Response, operation str
 	return fubError
 }
 
-// ExecuteWithRetry executes a function with retry logic for retryable errors
+// ExecuteWithRetry executes a function with retry logic for retryable errors.
+// Rate-limited calls wait for Retry-After; network and server errors back off
+// exponentially; authentication and validation failures are returned at once.
 func (eh *FUBErrorHandler) ExecuteWithRetry(operation string, fn func() (*http.Response, error)) (*http.Response, error) {
 	var lastError error
 
@@ -144,10 +199,18 @@ func (eh *FUBErrorHandler) ExecuteWithRetry(operation string, fn func() (*http.R
 		resp, err := fn()
 		if err != nil {
 			lastError = err
+			eh.recordFailure(&FUBAPIError{
+				Operation: operation,
+				Class:     FUBErrorNetwork,
+				ErrorCode: "NETWORK_ERROR",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+				Retryable: true,
+			})
 			if attempt < eh.maxRetries {
 				delay := eh.calculateBackoffDelay(attempt)
 				log.Printf("⏰ Retrying %s in %v due to error: %v", operation, delay, err)
-				time.Sleep(delay)
+				eh.sleep(delay)
 			}
 			continue
 		}
@@ -155,20 +218,28 @@ func (eh *FUBErrorHandler) ExecuteWithRetry(operation string, fn func() (*http.R
 		// Check if response indicates an error
 		if fubError := eh.HandleHTTPResponse(resp, operation); fubError != nil {
 			lastError = fubError
-			if fubError.IsRetryable() && attempt < eh.maxRetries {
-				resp.Body.Close() // Clean up response body
+			eh.recordFailure(fubError)
+			resp.Body.Close() // The body was consumed building the error
 
+			if fubError.IsRetryable() && attempt < eh.maxRetries {
 				// Use custom retry delay if provided
 				delay := eh.calculateBackoffDelay(attempt)
 				if fubError.RetryAfter != nil && *fubError.RetryAfter > 0 {
 					delay = *fubError.RetryAfter
+					if delay > eh.maxDelay {
+						delay = eh.maxDelay
+					}
 				}
 
 				log.Printf("⏰ Retrying %s in %v due to retryable error: %v", operation, delay, fubError.Message)
-				time.Sleep(delay)
+				eh.sleep(delay)
 				continue
 			}
-			return resp, fubError
+
+			if fubError.Class == FUBErrorAuth {
+				log.Printf("🔑 %s failed: %v", operation, ErrFUBAuthConfig)
+			}
+			return nil, fubError
 		}
 
 		// Success
@@ -182,13 +253,61 @@ func (eh *FUBErrorHandler) ExecuteWithRetry(operation string, fn func() (*http.R
 
 // calculateBackoffDelay calculates the delay for exponential backoff
 func (eh *FUBErrorHandler) calculateBackoffDelay(attempt int) time.Duration {
-	delay := float64(eh.baseDelay) * (eh.backoffFactor * float64(attempt))
+	delay := float64(eh.baseDelay) * math.Pow(eh.backoffFactor, float64(attempt))
 	if time.Duration(delay) > eh.maxDelay {
 		delay = float64(eh.maxDelay)
 	}
 	return time.Duration(delay)
 }
 
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) *time.Duration {
+	if value == "" {
+		return nil
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay := time.Duration(seconds) * time.Second
+		return &delay
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		delay := time.Until(at)
+		return &delay
+	}
+	return nil
+}
+
+// recordFailure counts a failure by class and keeps it among the recent ones
+func (eh *FUBErrorHandler) recordFailure(fubError *FUBAPIError) {
+	eh.failuresMux.Lock()
+	defer eh.failuresMux.Unlock()
+
+	eh.counts[fubError.Class]++
+	eh.recent = append(eh.recent, *fubError)
+	if len(eh.recent) > recentFUBErrorLimit {
+		eh.recent = eh.recent[len(eh.recent)-recentFUBErrorLimit:]
+	}
+	if fubError.Class == FUBErrorAuth {
+		at := fubError.Timestamp
+		eh.lastAuthFailure = &at
+	}
+}
+
+// Stats returns failure counts by class and the most recent failures
+func (eh *FUBErrorHandler) Stats() FUBErrorStats {
+	eh.failuresMux.Lock()
+	defer eh.failuresMux.Unlock()
+
+	stats := FUBErrorStats{
+		Counts:          make(map[FUBErrorClass]int64, len(eh.counts)),
+		Recent:          append([]FUBAPIError(nil), eh.recent...),
+		LastAuthFailure: eh.lastAuthFailure,
+	}
+	for class, count := range eh.counts {
+		stats.Counts[class] = count
+	}
+	return stats
+}
+
 // BehavioralTriggerValidationError represents validation errors for behavioral triggers
 type BehavioralTriggerValidationError struct {
 	Field   string      `json:"field"`
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fubResponses returns a request func replaying the given statuses in order
+func fubResponses(calls *int, statuses []int, header http.Header) func() (*http.Response, error) {
+	return func() (*http.Response, error) {
+		status := statuses[*calls]
+		*calls++
+		rec := httptest.NewRecorder()
+		for key, values := range header {
+			rec.Header()[key] = values
+		}
+		rec.WriteHeader(status)
+		rec.WriteString(`{"message":"rejected"}`)
+		return rec.Result(), nil
+	}
+}
+
+func newTestFUBErrorHandler(delays *[]time.Duration) *FUBErrorHandler {
+	handler := NewFUBErrorHandler()
+	handler.sleep = func(d time.Duration) { *delays = append(*delays, d) }
+	return handler
+}
+
+func TestFUBErrorHandler_RateLimitedWaitsForRetryAfter(t *testing.T) {
+	var delays []time.Duration
+	handler := newTestFUBErrorHandler(&delays)
+	calls := 0
+
+	resp, err := handler.ExecuteWithRetry("POST /people", fubResponses(&calls, []int{429, 201}, http.Header{"Retry-After": {"7"}}))
+	if err != nil || resp.StatusCode != 201 {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if len(delays) != 1 || delays[0] != 7*time.Second {
+		t.Errorf("Expected one 7s wait from Retry-After, got %v", delays)
+	}
+	if got := handler.Stats().Counts[FUBErrorRateLimited]; got != 1 {
+		t.Errorf("Expected 1 rate-limited failure recorded, got %d", got)
+	}
+}
+
+func TestFUBErrorHandler_AuthFailureSurfacesConfigError(t *testing.T) {
+	var delays []time.Duration
+	handler := newTestFUBErrorHandler(&delays)
+	calls := 0
+
+	resp, err := handler.ExecuteWithRetry("POST /notes", fubResponses(&calls, []int{401}, nil))
+	if resp != nil || !errors.Is(err, ErrFUBAuthConfig) {
+		t.Fatalf("Expected ErrFUBAuthConfig, got %v", err)
+	}
+	if calls != 1 || len(delays) != 0 {
+		t.Errorf("Expected no retry for an auth failure, got %d calls", calls)
+	}
+	if handler.Stats().LastAuthFailure == nil {
+		t.Error("Expected the auth failure to be recorded")
+	}
+}
+
+func TestFUBErrorHandler_ValidationFailureIsNotRetried(t *testing.T) {
+	var delays []time.Duration
+	handler := newTestFUBErrorHandler(&delays)
+	calls := 0
+
+	_, err := handler.ExecuteWithRetry("POST /people", fubResponses(&calls, []int{422}, nil))
+	var fubError *FUBAPIError
+	if !errors.As(err, &fubError) || fubError.Class != FUBErrorValidation {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+	if fubError.Message != "rejected" || errors.Is(err, ErrFUBAuthConfig) {
+		t.Errorf("Expected FUB's message and no config error, got %q", fubError.Message)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single call, got %d", calls)
+	}
+}
+
+func TestFUBErrorHandler_ServerAndNetworkErrorsBackOff(t *testing.T) {
+	var delays []time.Duration
+	handler := newTestFUBErrorHandler(&delays)
+	calls := 0
+
+	_, err := handler.ExecuteWithRetry("PUT /people/1", fubResponses(&calls, []int{503, 503, 503, 503}, nil))
+	var fubError *FUBAPIError
+	if !errors.As(err, &fubError) || fubError.Class != FUBErrorServer {
+		t.Fatalf("Expected a server error after retries, got %v", err)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	if calls != 4 || len(delays) != len(want) {
+		t.Fatalf("Expected 4 calls with 3 waits, got %d calls and %v", calls, delays)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("Expected backoff %v, got %v", want, delays)
+			break
+		}
+	}
+
+	attempts := 0
+	_, err = handler.ExecuteWithRetry("GET /people", func() (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("connection reset by peer")
+		}
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(http.StatusOK)
+		return rec.Result(), nil
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("Expected the network error to be retried, got %v after %d attempts", err, attempts)
+	}
+	stats := handler.Stats()
+	if stats.Counts[FUBErrorNetwork] != 1 || stats.Counts[FUBErrorServer] != 4 {
+		t.Errorf("Expected 1 network and 4 server failures, got %v", stats.Counts)
+	}
+	if last := stats.Recent[len(stats.Recent)-1]; !strings.Contains(last.Message, "connection reset") {
+		t.Errorf("Expected the network failure most recent, got %q", last.Message)
+	}
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+// FUB lead match decisions
+const (
+	FUBLeadMatchAutoLink = "auto_link" // confident, unambiguous match
+	FUBLeadMatchReview   = "review"    // plausible match that needs a person to confirm
+	FUBLeadMatchNone     = "none"      // nothing close enough to suggest
+)
+
+const (
+	// DefaultFUBLeadAutoLinkThreshold is the confidence required to link without review
+	DefaultFUBLeadAutoLinkThreshold = 0.85
+
+	// DefaultFUBLeadReviewThreshold is the confidence below which candidates are not suggested
+	DefaultFUBLeadReviewThreshold = 0.5
+
+	// fubLeadAmbiguityMargin is how close the runner-up may score before the best
+	// match is considered ambiguous
+	fubLeadAmbiguityMargin = 0.05
+
+	// fubLeadCandidateLimit caps how many leads are scored per lookup
+	fubLeadCandidateLimit = 200
+)
+
+// nameNicknames maps common nicknames to the formal first name they abbreviate
+var nameNicknames = func() map[string]string {
+	formalNames := map[string][]string{
+		"robert":      {"bob", "bobby", "rob", "robbie"},
+		"william":     {"bill", "billy", "will", "liam"},
+		"michael":     {"mike", "mikey"},
+		"james":       {"jim", "jimmy", "jamie"},
+		"joseph":      {"joe", "joey"},
+		"thomas":      {"tom", "tommy"},
+		"david":       {"dave"},
+		"daniel":      {"dan", "danny"},
+		"christopher": {"chris"},
+		"matthew":     {"matt"},
+		"nicholas":    {"nick"},
+		"anthony":     {"tony"},
+		"steven":      {"steve"},
+		"richard":     {"rick", "rich", "dick"},
+		"jonathan":    {"jon"},
+		"alexander":   {"alex"},
+		"andrew":      {"andy", "drew"},
+		"benjamin":    {"ben"},
+		"samuel":      {"sam"},
+		"edward":      {"ed", "eddie", "ted"},
+		"gregory":     {"greg"},
+		"joshua":      {"josh"},
+		"jennifer":    {"jen", "jenny"},
+		"elizabeth":   {"liz", "beth", "betty"},
+		"katherine":   {"kate", "katie", "kathy"},
+		"susan":       {"sue", "susie"},
+		"patricia":    {"patty", "pat", "trish"},
+		"margaret":    {"maggie", "peggy"},
+		"rebecca":     {"becky"},
+		"victoria":    {"vicky"},
+		"deborah":     {"debbie", "deb"},
+		"amanda":      {"mandy"},
+	}
+	nicknames := map[string]string{}
+	for formal, names := range formalNames {
+		for _, name := range names {
+			nicknames[name] = formal
+		}
+	}
+	return nicknames
+}()
+
+// FUBLeadCandidate is a scored lead considered by the matcher
+type FUBLeadCandidate struct {
+	LeadID       uint    `json:"lead_id"`
+	FUBLeadID    string  `json:"fub_lead_id"`
+	Name         string  `json:"name"`
+	Email        string  `json:"email"`
+	NameScore    float64 `json:"name_score"`
+	EmailMatched bool    `json:"email_matched"`
+	Confidence   float64 `json:"confidence"`
+}
+
+// FUBLeadMatch is the matcher's verdict for an applicant
+type FUBLeadMatch struct {
+	Decision   string             `json:"decision"`
+	Lead       *models.Lead       `json:"lead,omitempty"`
+	Confidence float64            `json:"confidence"`
+	Ambiguous  bool               `json:"ambiguous"`
+	Candidates []FUBLeadCandidate `json:"candidates"`
+}
+
+// FUBLeadMatcher finds the FUB lead an applicant most likely corresponds to,
+// scoring candidates by name similarity and email
+type FUBLeadMatcher struct {
+	db                *gorm.DB
+	autoLinkThreshold float64
+	reviewThreshold   float64
+}
+
+// NewFUBLeadMatcher creates a matcher with the default thresholds
+func NewFUBLeadMatcher(db *gorm.DB) *FUBLeadMatcher {
+	return &FUBLeadMatcher{
+		db:                db,
+		autoLinkThreshold: DefaultFUBLeadAutoLinkThreshold,
+		reviewThreshold:   DefaultFUBLeadReviewThreshold,
+	}
+}
+
+// SetThresholds changes the auto-link and review confidence thresholds
+func (m *FUBLeadMatcher) SetThresholds(autoLink, review float64) {
+	m.autoLinkThreshold = autoLink
+	m.reviewThreshold = review
+}
+
+// Match scores leads sharing the applicant's email or a name token and returns
+// the best one. A match is only auto-linked when it clears the auto-link
+// threshold and no other candidate scores within the ambiguity margin.
+func (m *FUBLeadMatcher) Match(name, email string) (*FUBLeadMatch, error) {
+	tokens := nameTokens(name)
+	email = strings.ToLower(strings.TrimSpace(email))
+	result := &FUBLeadMatch{Decision: FUBLeadMatchNone, Candidates: []FUBLeadCandidate{}}
+	if len(tokens) == 0 && email == "" {
+		return result, nil
+	}
+
+	// Search on the formal and raw forms of every token so "Bob" finds "Robert"
+	searchTokens := map[string]bool{}
+	for _, token := range tokens {
+		searchTokens[token] = true
+		searchTokens[formalFirstName(token)] = true
+	}
+	names := make([]string, 0, len(searchTokens))
+	for token := range searchTokens {
+		names = append(names, token)
+	}
+
+	query := m.db.Model(&models.Lead{})
+	switch {
+	case email != "" && len(names) > 0:
+		query = query.Where("LOWER(email) = ? OR LOWER(first_name) IN ? OR LOWER(last_name) IN ?", email, names, names)
+	case email != "":
+		query = query.Where("LOWER(email) = ?", email)
+	default:
+		query = query.Where("LOWER(first_name) IN ? OR LOWER(last_name) IN ?", names, names)
+	}
+
+	var leads []models.Lead
+	if err := query.Limit(fubLeadCandidateLimit).Find(&leads).Error; err != nil {
+		return nil, fmt.Errorf("failed to load lead candidates: %w", err)
+	}
+
+	byID := map[uint]models.Lead{}
+	for _, lead := range leads {
+		candidate := scoreFUBLeadCandidate(tokens, email, lead)
+		if candidate.Confidence < m.reviewThreshold {
+			continue
+		}
+		byID[lead.ID] = lead
+		result.Candidates = append(result.Candidates, candidate)
+	}
+	if len(result.Candidates) == 0 {
+		return result, nil
+	}
+
+	sort.SliceStable(result.Candidates, func(i, j int) bool {
+		return result.Candidates[i].Confidence > result.Candidates[j].Confidence
+	})
+
+	best := result.Candidates[0]
+	lead := byID[best.LeadID]
+	result.Lead = &lead
+	result.Confidence = best.Confidence
+	result.Ambiguous = len(result.Candidates) > 1 &&
+		best.Confidence-result.Candidates[1].Confidence < fubLeadAmbiguityMargin
+
+	if best.Confidence >= m.autoLinkThreshold && !result.Ambiguous {
+		result.Decision = FUBLeadMatchAutoLink
+	} else {
+		result.Decision = FUBLeadMatchReview
+	}
+	return result, nil
+}
+
+// scoreFUBLeadCandidate combines name similarity with an email match. An email
+// match alone is strong evidence; a name alone can never reach full confidence.
+func scoreFUBLeadCandidate(tokens []string, email string, lead models.Lead) FUBLeadCandidate {
+	candidate := FUBLeadCandidate{
+		LeadID:       lead.ID,
+		FUBLeadID:    lead.FUBLeadID,
+		Name:         strings.TrimSpace(lead.FirstName + " " + lead.LastName),
+		Email:        lead.Email,
+		NameScore:    nameSimilarity(tokens, nameTokens(lead.FirstName), nameTokens(lead.LastName)),
+		EmailMatched: email != "" && strings.EqualFold(strings.TrimSpace(lead.Email), email),
+	}
+
+	if candidate.EmailMatched {
+		candidate.Confidence = 0.7 + 0.3*candidate.NameScore
+	} else {
+		candidate.Confidence = 0.9 * candidate.NameScore
+	}
+	return candidate
+}
+
+// nameSimilarity scores an applicant's name tokens against a lead's first and
+// last names. The applicant's first token is compared with the lead's first
+// name (allowing nicknames) and the last token with the lead's last name, so
+// middle names and initials are ignored.
+func nameSimilarity(applicant, first, last []string) float64 {
+	if len(applicant) == 0 || len(first) == 0 || len(last) == 0 {
+		return 0
+	}
+
+	firstScore := tokenSimilarity(formalFirstName(applicant[0]), formalFirstName(first[0]))
+	lastScore := tokenSimilarity(applicant[len(applicant)-1], last[len(last)-1])
+	if len(applicant) == 1 {
+		// A single token could be either name
+		return 0.5 * max(firstScore, tokenSimilarity(applicant[0], last[len(last)-1]))
+	}
+	return 0.4*firstScore + 0.6*lastScore
+}
+
+// nameTokens lowercases a name and splits it into words, dropping punctuation.
+// "Last, First Middle" is reordered to "first middle last".
+func nameTokens(name string) []string {
+	if parts := strings.SplitN(name, ",", 2); len(parts) == 2 {
+		name = parts[1] + " " + parts[0]
+	}
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+}
+
+// formalFirstName returns the formal name a nickname abbreviates
+func formalFirstName(name string) string {
+	if formal, ok := nameNicknames[name]; ok {
+		return formal
+	}
+	return name
+}
+
+// tokenSimilarity is 1 minus the Levenshtein distance normalized by the longer token
+func tokenSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	longest := len([]rune(a))
+	if n := len([]rune(b)); n > longest {
+		longest = n
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(min(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
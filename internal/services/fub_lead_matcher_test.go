@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestFUBLeadMatcher_ScoresNamesAndEmail(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Lead{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	for _, lead := range []models.Lead{
+		{FirstName: "Robert", LastName: "Nguyen", Email: "rnguyen@example.com", FUBLeadID: "fub-1"},
+		{FirstName: "Maria", LastName: "Gonzalez", Email: "maria.g@example.com", FUBLeadID: "fub-2"},
+		{FirstName: "Mario", LastName: "Gonzales", Email: "mgonzales@example.com", FUBLeadID: "fub-3"},
+		{FirstName: "Dana", LastName: "Whitfield", Email: "dana@example.com", FUBLeadID: "fub-4"},
+		{FirstName: "Kim", LastName: "Tran", Email: "kim.tran@example.com", FUBLeadID: "fub-5"},
+		{FirstName: "Kim", LastName: "Tran", Email: "ktran@example.com", FUBLeadID: "fub-6"},
+	} {
+		if err := db.Create(&lead).Error; err != nil {
+			t.Fatalf("Failed to create lead: %v", err)
+		}
+	}
+
+	matcher := NewFUBLeadMatcher(db)
+	tests := []struct {
+		name      string
+		applicant string
+		email     string
+		decision  string
+		fubLeadID string
+	}{
+		{"nickname and middle name", "Bob T. Nguyen", "", FUBLeadMatchAutoLink, "fub-1"},
+		{"last, first with different case", "NGUYEN, ROBERT", "", FUBLeadMatchAutoLink, "fub-1"},
+		{"email with mismatched name", "Jordan Smith", "DANA@example.com", FUBLeadMatchReview, "fub-4"},
+		{"email and name", "Dana Whitfield", "dana@example.com", FUBLeadMatchAutoLink, "fub-4"},
+		{"misspelled first name", "Mari Gonzalez", "", FUBLeadMatchReview, "fub-2"},
+		{"exact name beats similar name", "Maria Gonzalez", "", FUBLeadMatchAutoLink, "fub-2"},
+		{"duplicate names are ambiguous", "Kim Tran", "", FUBLeadMatchReview, "fub-5"},
+		{"email breaks a name tie", "Kim Tran", "ktran@example.com", FUBLeadMatchAutoLink, "fub-6"},
+		{"unknown applicant", "Priya Raman", "priya@example.com", FUBLeadMatchNone, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := matcher.Match(tt.applicant, tt.email)
+			if err != nil {
+				t.Fatalf("Match failed: %v", err)
+			}
+			if match.Decision != tt.decision {
+				t.Fatalf("Expected decision %s, got %s (confidence %.2f, candidates %+v)",
+					tt.decision, match.Decision, match.Confidence, match.Candidates)
+			}
+			if tt.fubLeadID == "" {
+				if match.Lead != nil {
+					t.Fatalf("Expected no lead, got %s", match.Lead.FUBLeadID)
+				}
+				return
+			}
+			if match.Lead == nil || match.Lead.FUBLeadID != tt.fubLeadID {
+				t.Fatalf("Expected lead %s, got %+v", tt.fubLeadID, match.Lead)
+			}
+		})
+	}
+}
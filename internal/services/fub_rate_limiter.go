@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Default FUB API rate limit
+const (
+	defaultFUBRatePerMinute = 120
+	defaultFUBRateBurst     = 10
+)
+
+// fubTokenBucket limits FUB API calls to a steady rate while allowing short
+// bursts up to its capacity
+type fubTokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	rate     float64 // Tokens added per second
+	last     time.Time
+	now      func() time.Time
+}
+
+// newFUBTokenBucket creates a full bucket allowing perMinute calls with bursts of burst
+func newFUBTokenBucket(perMinute, burst int) *fubTokenBucket {
+	bucket := &fubTokenBucket{now: time.Now}
+	bucket.configure(perMinute, burst)
+	bucket.tokens = bucket.capacity
+	bucket.last = bucket.now()
+	return bucket
+}
+
+// configure changes the rate and burst; non-positive values keep the defaults
+func (b *fubTokenBucket) configure(perMinute, burst int) {
+	if perMinute <= 0 {
+		perMinute = defaultFUBRatePerMinute
+	}
+	if burst <= 0 {
+		burst = defaultFUBRateBurst
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rate = float64(perMinute) / 60
+	b.capacity = float64(burst)
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
+
+// reserve takes a token, returning how long to wait before it may be used
+func (b *fubTokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Wait blocks until a token is available or ctx is done
+func (b *fubTokenBucket) Wait(ctx context.Context) error {
+	wait := b.reserve()
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -12,6 +12,9 @@ import (
 // FunnelAnalyticsService analyzes conversion funnel performance
 type FunnelAnalyticsService struct {
 	db *gorm.DB
+
+	analyticsCache    *AnalyticsCacheService
+	analyticsCacheTTL time.Duration
 }
 
 // NewFunnelAnalyticsService creates a new funnel analytics service
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"chrisgross-ctrl-project/internal/security"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Health statuses, for components and the overall report
+const (
+	HealthStatusHealthy   = "healthy"
+	HealthStatusDegraded  = "degraded"  // only non-critical components are failing
+	HealthStatusUnhealthy = "unhealthy" // a critical component is failing
+	HealthStatusDisabled  = "disabled"  // component is not configured
+)
+
+// defaultHealthCheckTimeout bounds each dependency probe
+const defaultHealthCheckTimeout = 3 * time.Second
+
+// ComponentHealth is the result of probing one dependency
+type ComponentHealth struct {
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// HealthReport is the combined result of a health check
+type HealthReport struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components"`
+	Timestamp  time.Time                  `json:"timestamp"`
+}
+
+// Healthy reports whether every critical component is healthy
+func (r HealthReport) Healthy() bool {
+	return r.Status != HealthStatusUnhealthy
+}
+
+// healthProbe checks one dependency, returning an error when it is unavailable
+type healthProbe struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) error // nil when the component is not configured
+}
+
+// HealthChecker probes the database, Redis, encryption, and integration
+// configuration so health endpoints report real dependency status
+type HealthChecker struct {
+	db                *gorm.DB
+	redisClient       *redis.Client
+	redisConfigured   bool
+	encryptionManager *security.EncryptionManager
+	integrations      map[string]bool // integration name -> configured
+	timeout           time.Duration
+}
+
+// NewHealthChecker creates a health checker. redisConfigured reports whether a
+// Redis URL was set, so a client that failed to connect at startup is reported
+// as unhealthy rather than disabled.
+func NewHealthChecker(db *gorm.DB, redisClient *redis.Client, redisConfigured bool, encryptionManager *security.EncryptionManager) *HealthChecker {
+	return &HealthChecker{
+		db:                db,
+		redisClient:       redisClient,
+		redisConfigured:   redisConfigured,
+		encryptionManager: encryptionManager,
+		integrations:      map[string]bool{},
+		timeout:           defaultHealthCheckTimeout,
+	}
+}
+
+// SetIntegration records whether an external integration (e.g. FUB, scraper) is configured
+func (hc *HealthChecker) SetIntegration(name string, configured bool) {
+	hc.integrations[name] = configured
+}
+
+// Check probes every component concurrently
+func (hc *HealthChecker) Check(ctx context.Context) HealthReport {
+	probes := append(hc.criticalProbes(), healthProbe{name: "redis", check: hc.redisCheck()})
+	for name, configured := range hc.integrations {
+		probes = append(probes, healthProbe{name: name, check: configuredCheck(configured)})
+	}
+	return hc.run(ctx, probes)
+}
+
+// Ready probes only the components the server cannot serve requests without
+func (hc *HealthChecker) Ready(ctx context.Context) HealthReport {
+	return hc.run(ctx, hc.criticalProbes())
+}
+
+func (hc *HealthChecker) criticalProbes() []healthProbe {
+	return []healthProbe{
+		{name: "database", critical: true, check: hc.checkDatabase},
+		{name: "encryption", critical: true, check: hc.checkEncryption},
+	}
+}
+
+// run executes probes in parallel and derives the overall status
+func (hc *HealthChecker) run(ctx context.Context, probes []healthProbe) HealthReport {
+	report := HealthReport{
+		Status:     HealthStatusHealthy,
+		Components: make(map[string]ComponentHealth, len(probes)),
+		Timestamp:  time.Now(),
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, probe := range probes {
+		wg.Add(1)
+		go func(probe healthProbe) {
+			defer wg.Done()
+			result := hc.runProbe(ctx, probe)
+			mutex.Lock()
+			report.Components[probe.name] = result
+			mutex.Unlock()
+		}(probe)
+	}
+	wg.Wait()
+
+	for _, component := range report.Components {
+		if component.Status != HealthStatusUnhealthy {
+			continue
+		}
+		if component.Critical {
+			report.Status = HealthStatusUnhealthy
+			break
+		}
+		report.Status = HealthStatusDegraded
+	}
+	return report
+}
+
+func (hc *HealthChecker) runProbe(ctx context.Context, probe healthProbe) ComponentHealth {
+	result := ComponentHealth{Status: HealthStatusHealthy, Critical: probe.critical}
+	if probe.check == nil {
+		result.Status = HealthStatusDisabled
+		return result
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, hc.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := probe.check(probeCtx)
+	result.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		result.Status = HealthStatusUnhealthy
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// checkDatabase pings the underlying connection pool
+func (hc *HealthChecker) checkDatabase(ctx context.Context) error {
+	if hc.db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	sqlDB, err := hc.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// checkEncryption round-trips a probe value through the encryption manager
+func (hc *HealthChecker) checkEncryption(ctx context.Context) error {
+	if hc.encryptionManager == nil {
+		return fmt.Errorf("encryption manager not initialized")
+	}
+	probe := fmt.Sprintf("health-probe-%d", time.Now().UnixNano())
+	encrypted, err := hc.encryptionManager.Encrypt(probe)
+	if err != nil {
+		return fmt.Errorf("encrypt failed: %w", err)
+	}
+	decrypted, err := hc.encryptionManager.Decrypt(encrypted)
+	if err != nil {
+		return fmt.Errorf("decrypt failed: %w", err)
+	}
+	if decrypted != probe {
+		return fmt.Errorf("decrypted probe does not match")
+	}
+	return nil
+}
+
+// redisCheck pings Redis, or returns nil when Redis is not configured
+func (hc *HealthChecker) redisCheck() func(ctx context.Context) error {
+	if !hc.redisConfigured {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		if hc.redisClient == nil {
+			return fmt.Errorf("redis configured but not connected")
+		}
+		return hc.redisClient.Ping(ctx).Err()
+	}
+}
+
+// configuredCheck reports an integration as healthy when configured and disabled otherwise
+func configuredCheck(configured bool) func(ctx context.Context) error {
+	if !configured {
+		return nil
+	}
+	return func(ctx context.Context) error { return nil }
+}
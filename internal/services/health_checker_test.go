@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestHealthChecker_ReportsComponentStatus(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&security.EncryptionKey{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	t.Setenv("ENCRYPTION_KEY", newTestEncryptionKey(t))
+	manager, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create encryption manager: %v", err)
+	}
+
+	// Redis was configured but failed to connect at startup
+	checker := NewHealthChecker(db, nil, true, manager)
+	checker.SetIntegration("fub", true)
+	checker.SetIntegration("scraper", false)
+
+	report := checker.Check(context.Background())
+	if report.Status != HealthStatusDegraded || !report.Healthy() {
+		t.Fatalf("Expected degraded but healthy report, got %+v", report)
+	}
+	for name, want := range map[string]string{
+		"database":   HealthStatusHealthy,
+		"encryption": HealthStatusHealthy,
+		"redis":      HealthStatusUnhealthy,
+		"fub":        HealthStatusHealthy,
+		"scraper":    HealthStatusDisabled,
+	} {
+		if got := report.Components[name].Status; got != want {
+			t.Errorf("Expected %s to be %s, got %s", name, want, got)
+		}
+	}
+
+	// Losing the database makes the server unhealthy and not ready
+	sqlDB, _ := db.DB()
+	sqlDB.Close()
+
+	ready := checker.Ready(context.Background())
+	if ready.Healthy() || ready.Components["database"].Error == "" {
+		t.Fatalf("Expected unready report with a database error, got %+v", ready)
+	}
+	if _, ok := ready.Components["redis"]; ok {
+		t.Fatalf("Readiness should only probe critical components, got %+v", ready.Components)
+	}
+}
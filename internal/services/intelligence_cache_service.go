@@ -5,20 +5,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
+	"chrisgross-ctrl-project/internal/models"
 	"github.com/redis/go-redis/v9"
 )
 
 type IntelligenceCacheService struct {
 	redis *redis.Client
 	ctx   context.Context
+	ttls  IntelligenceCacheTTLs
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
 }
 
 func NewIntelligenceCacheService(redisClient *redis.Client) *IntelligenceCacheService {
 	return &IntelligenceCacheService{
 		redis: redisClient,
 		ctx:   context.Background(),
+		ttls:  DefaultIntelligenceCacheTTLs(),
 	}
 }
 
@@ -34,17 +42,96 @@ const (
 	KeyWorkflowHot         = "intelligence:workflow:hot:v1"
 	KeyTeamHot             = "intelligence:team:hot:v1"
 	KeySystemHot           = "intelligence:system:hot:v1"
+	KeyIntelligenceCycle   = "intelligence:cycle:last:v1"
 	TTLHot   = 5 * time.Minute
 	TTLWarm  = 1 * time.Hour
 	TTLDaily = 24 * time.Hour
+	TTLLead  = 15 * time.Minute
+
+	leadCacheKeyPattern = "intelligence:lead:*"
 )
 
+// LeadInsightsKey is the cache key for a lead's opportunity analysis
+func LeadInsightsKey(leadID int64) string {
+	return fmt.Sprintf("intelligence:lead:%d:insights:v1", leadID)
+}
+
+// LeadScoreKey is the cache key for a lead's behavioral score
+func LeadScoreKey(leadID int64) string {
+	return fmt.Sprintf("intelligence:lead:%d:score:v1", leadID)
+}
+
+// IntelligenceCacheTTLs sets how long each tier of intelligence stays cached
+type IntelligenceCacheTTLs struct {
+	Hot   time.Duration `json:"hot"`
+	Warm  time.Duration `json:"warm"`
+	Daily time.Duration `json:"daily"`
+	Lead  time.Duration `json:"lead"`
+}
+
+// DefaultIntelligenceCacheTTLs returns the built-in tier TTLs
+func DefaultIntelligenceCacheTTLs() IntelligenceCacheTTLs {
+	return IntelligenceCacheTTLs{Hot: TTLHot, Warm: TTLWarm, Daily: TTLDaily, Lead: TTLLead}
+}
+
+// IntelligenceCacheMetrics reports intelligence cache effectiveness
+type IntelligenceCacheMetrics struct {
+	Available     bool             `json:"available"`
+	Hits          int64            `json:"hits"`
+	Misses        int64            `json:"misses"`
+	HitRate       float64          `json:"hit_rate"`
+	Invalidations int64            `json:"invalidations"`
+	TTLSeconds    map[string]int64 `json:"ttl_seconds"`
+}
+
+// SetTTLs overrides the tier TTLs; zero values keep the current setting
+func (ics *IntelligenceCacheService) SetTTLs(ttls IntelligenceCacheTTLs) {
+	if ttls.Hot > 0 {
+		ics.ttls.Hot = ttls.Hot
+	}
+	if ttls.Warm > 0 {
+		ics.ttls.Warm = ttls.Warm
+	}
+	if ttls.Daily > 0 {
+		ics.ttls.Daily = ttls.Daily
+	}
+	if ttls.Lead > 0 {
+		ics.ttls.Lead = ttls.Lead
+	}
+}
+
+// TTLs returns the tier TTLs in effect
+func (ics *IntelligenceCacheService) TTLs() IntelligenceCacheTTLs {
+	return ics.ttls
+}
+
+// Metrics returns hit/miss counters since startup
+func (ics *IntelligenceCacheService) Metrics() IntelligenceCacheMetrics {
+	hits, misses := ics.hits.Load(), ics.misses.Load()
+	metrics := IntelligenceCacheMetrics{
+		Available:     ics.IsAvailable(),
+		Hits:          hits,
+		Misses:        misses,
+		Invalidations: ics.invalidations.Load(),
+		TTLSeconds: map[string]int64{
+			"hot":   int64(ics.ttls.Hot.Seconds()),
+			"warm":  int64(ics.ttls.Warm.Seconds()),
+			"daily": int64(ics.ttls.Daily.Seconds()),
+			"lead":  int64(ics.ttls.Lead.Seconds()),
+		},
+	}
+	if hits+misses > 0 {
+		metrics.HitRate = float64(hits) / float64(hits+misses)
+	}
+	return metrics
+}
+
 func (ics *IntelligenceCacheService) GetDashboardHot() (map[string]interface{}, error) {
 	return ics.get(KeyDashboardHot)
 }
 
 func (ics *IntelligenceCacheService) SetDashboardHot(data map[string]interface{}) error {
-	return ics.set(KeyDashboardHot, data, TTLHot)
+	return ics.set(KeyDashboardHot, data, ics.ttls.Hot)
 }
 
 func (ics *IntelligenceCacheService) GetDashboardWarm() (map[string]interface{}, error) {
@@ -52,7 +139,7 @@ func (ics *IntelligenceCacheService) GetDashboardWarm() (map[string]interface{},
 }
 
 func (ics *IntelligenceCacheService) SetDashboardWarm(data map[string]interface{}) error {
-	return ics.set(KeyDashboardWarm, data, TTLWarm)
+	return ics.set(KeyDashboardWarm, data, ics.ttls.Warm)
 }
 
 func (ics *IntelligenceCacheService) GetDashboardDaily() (map[string]interface{}, error) {
@@ -60,7 +147,7 @@ func (ics *IntelligenceCacheService) GetDashboardDaily() (map[string]interface{}
 }
 
 func (ics *IntelligenceCacheService) SetDashboardDaily(data map[string]interface{}) error {
-	return ics.set(KeyDashboardDaily, data, TTLDaily)
+	return ics.set(KeyDashboardDaily, data, ics.ttls.Daily)
 }
 
 func (ics *IntelligenceCacheService) GetLeadsHot() (map[string]interface{}, error) {
@@ -68,7 +155,7 @@ func (ics *IntelligenceCacheService) GetLeadsHot() (map[string]interface{}, erro
 }
 
 func (ics *IntelligenceCacheService) SetLeadsHot(data map[string]interface{}) error {
-	return ics.set(KeyLeadsHot, data, TTLHot)
+	return ics.set(KeyLeadsHot, data, ics.ttls.Hot)
 }
 
 func (ics *IntelligenceCacheService) GetLeadsWarm() (map[string]interface{}, error) {
@@ -76,7 +163,7 @@ func (ics *IntelligenceCacheService) GetLeadsWarm() (map[string]interface{}, err
 }
 
 func (ics *IntelligenceCacheService) SetLeadsWarm(data map[string]interface{}) error {
-	return ics.set(KeyLeadsWarm, data, TTLWarm)
+	return ics.set(KeyLeadsWarm, data, ics.ttls.Warm)
 }
 
 func (ics *IntelligenceCacheService) GetPropertiesHot() (map[string]interface{}, error) {
@@ -84,7 +171,7 @@ func (ics *IntelligenceCacheService) GetPropertiesHot() (map[string]interface{},
 }
 
 func (ics *IntelligenceCacheService) SetPropertiesHot(data map[string]interface{}) error {
-	return ics.set(KeyPropertiesHot, data, TTLHot)
+	return ics.set(KeyPropertiesHot, data, ics.ttls.Hot)
 }
 
 func (ics *IntelligenceCacheService) GetPropertiesWarm() (map[string]interface{}, error) {
@@ -92,14 +179,77 @@ func (ics *IntelligenceCacheService) GetPropertiesWarm() (map[string]interface{}
 }
 
 func (ics *IntelligenceCacheService) SetPropertiesWarm(data map[string]interface{}) error {
-	return ics.set(KeyPropertiesWarm, data, TTLWarm)
+	return ics.set(KeyPropertiesWarm, data, ics.ttls.Warm)
+}
+
+// GetLeadInsights returns a lead's cached opportunity analysis
+func (ics *IntelligenceCacheService) GetLeadInsights(leadID int64) (map[string]interface{}, error) {
+	return ics.get(LeadInsightsKey(leadID))
+}
+
+func (ics *IntelligenceCacheService) SetLeadInsights(leadID int64, data map[string]interface{}) error {
+	return ics.set(LeadInsightsKey(leadID), data, ics.ttls.Lead)
+}
+
+// GetLeadScore returns a lead's cached behavioral score
+func (ics *IntelligenceCacheService) GetLeadScore(leadID int64) (*models.BehavioralScore, error) {
+	var score models.BehavioralScore
+	if err := ics.getJSON(LeadScoreKey(leadID), &score); err != nil {
+		return nil, err
+	}
+	return &score, nil
+}
+
+func (ics *IntelligenceCacheService) SetLeadScore(leadID int64, score *models.BehavioralScore) error {
+	return ics.setJSON(LeadScoreKey(leadID), score, ics.ttls.Lead)
+}
+
+// InvalidateLead evicts a lead's cached insights and score, along with the
+// lead lists they appear in, after its behavioral data changes
+func (ics *IntelligenceCacheService) InvalidateLead(leadID int64) error {
+	return ics.InvalidateLeads([]int64{leadID})
+}
+
+// InvalidateLeads evicts cached insights and scores for several leads at once
+func (ics *IntelligenceCacheService) InvalidateLeads(leadIDs []int64) error {
+	if ics.redis == nil || len(leadIDs) == 0 {
+		return nil
+	}
+
+	keys := []string{KeyLeadsHot, KeyLeadsWarm}
+	for _, leadID := range leadIDs {
+		keys = append(keys, LeadInsightsKey(leadID), LeadScoreKey(leadID))
+	}
+	if err := ics.redis.Del(ics.ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate lead intelligence: %w", err)
+	}
+	ics.invalidations.Add(int64(len(leadIDs)))
+	return nil
+}
+
+// InvalidateDashboard evicts the cached dashboard tiers, leaving lead and
+// property intelligence in place
+func (ics *IntelligenceCacheService) InvalidateDashboard() error {
+	if ics.redis == nil {
+		return nil
+	}
+	if err := ics.redis.Del(ics.ctx, KeyDashboardHot, KeyDashboardWarm, KeyDashboardDaily).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate dashboard intelligence: %w", err)
+	}
+	ics.invalidations.Add(1)
+	return nil
 }
 
 func (ics *IntelligenceCacheService) InvalidateAll() error {
+	if ics.redis == nil {
+		return nil
+	}
+
 	keys := []string{
 		KeyDashboardHot, KeyDashboardWarm, KeyDashboardDaily,
 		KeyLeadsHot, KeyLeadsWarm, KeyPropertiesHot, KeyPropertiesWarm,
 		KeyCommunicationsHot, KeyWorkflowHot, KeyTeamHot, KeySystemHot,
+		KeyIntelligenceCycle,
 	}
 	
 	for _, key := range keys {
@@ -107,33 +257,68 @@ func (ics *IntelligenceCacheService) InvalidateAll() error {
 			log.Printf("⚠️ Failed to delete cache key %s: %v", key, err)
 		}
 	}
+
+	iter := ics.redis.Scan(ics.ctx, 0, leadCacheKeyPattern, 100).Iterator()
+	for iter.Next(ics.ctx) {
+		if err := ics.redis.Del(ics.ctx, iter.Val()).Err(); err != nil {
+			log.Printf("⚠️ Failed to delete cache key %s: %v", iter.Val(), err)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		log.Printf("⚠️ Failed to scan lead intelligence keys: %v", err)
+	}
+	ics.invalidations.Add(1)
 	
 	log.Println("🗑️ Intelligence cache invalidated")
 	return nil
 }
 
+// Has reports whether key is cached, without counting a hit or miss
+func (ics *IntelligenceCacheService) Has(key string) bool {
+	if ics.redis == nil {
+		return false
+	}
+	n, err := ics.redis.Exists(ics.ctx, key).Result()
+	return err == nil && n > 0
+}
+
 func (ics *IntelligenceCacheService) get(key string) (map[string]interface{}, error) {
+	var data map[string]interface{}
+	if err := ics.getJSON(key, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (ics *IntelligenceCacheService) getJSON(key string, v interface{}) error {
 	if ics.redis == nil {
-		return nil, fmt.Errorf("redis not available")
+		return fmt.Errorf("redis not available")
 	}
 	
 	val, err := ics.redis.Get(ics.ctx, key).Result()
 	if err == redis.Nil {
-		return nil, fmt.Errorf("cache miss")
+		ics.misses.Add(1)
+		return fmt.Errorf("cache miss")
 	}
 	if err != nil {
-		return nil, err
+		ics.misses.Add(1)
+		return err
 	}
 	
-	var data map[string]interface{}
-	if err := json.Unmarshal([]byte(val), &data); err != nil {
-		return nil, err
+	if err := json.Unmarshal([]byte(val), v); err != nil {
+		ics.misses.Add(1)
+		return err
 	}
 	
-	return data, nil
+	ics.hits.Add(1)
+	return nil
 }
 
 func (ics *IntelligenceCacheService) set(key string, data map[string]interface{}, ttl time.Duration) error {
+	return ics.setJSON(key, data, ttl)
+}
+
+func (ics *IntelligenceCacheService) setJSON(key string, data interface{}, ttl time.Duration) error {
 	if ics.redis == nil {
 		return fmt.Errorf("redis not available")
 	}
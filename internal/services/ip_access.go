@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+// IP access rule errors
+var (
+	ErrInvalidIPRule  = errors.New("invalid IP access rule")
+	ErrIPRuleExists   = errors.New("network is already on that list")
+	ErrIPRuleNotFound = errors.New("IP access rule not found")
+)
+
+// IPAccessSettings configures the IP allowlist and denylist
+type IPAccessSettings struct {
+	Allowlist      []string      // Networks always allowed on admin routes, from config
+	EventThreshold int           // Unresolved high/critical security events that deny an IP; 0 disables
+	EventWindow    time.Duration // How far back security events are counted
+	DenyDuration   time.Duration // How long event-driven denials last; 0 keeps them until removed
+}
+
+// ipNetwork is a compiled allow or deny rule
+type ipNetwork struct {
+	network   *net.IPNet
+	expiresAt *time.Time
+}
+
+// IPAccessService keeps the allowlist for admin routes and the denylist for
+// every route in memory, backed by ip_access_rules
+type IPAccessService struct {
+	db       *gorm.DB
+	settings IPAccessSettings
+	static   []ipNetwork
+	now      func() time.Time
+
+	mutex sync.RWMutex
+	allow []ipNetwork
+	deny  []ipNetwork
+}
+
+// NewIPAccessService creates an IP access service. It fails if a configured
+// allowlist entry is not an IP or CIDR.
+func NewIPAccessService(db *gorm.DB, settings IPAccessSettings) (*IPAccessService, error) {
+	s := &IPAccessService{db: db, settings: settings, now: time.Now}
+	for _, entry := range settings.Allowlist {
+		network, err := ParseIPNetwork(entry)
+		if err != nil {
+			return nil, err
+		}
+		s.static = append(s.static, ipNetwork{network: network})
+	}
+	s.allow = s.static
+	return s, nil
+}
+
+// ParseIPNetwork parses a CIDR, or a bare IP as a single-address network
+func ParseIPNetwork(value string) (*net.IPNet, error) {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "/") {
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a valid CIDR", ErrInvalidIPRule, value)
+		}
+		return network, nil
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("%w: %q is not a valid IP address", ErrInvalidIPRule, value)
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return &net.IPNet{IP: ipv4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// Start denies IPs with repeated security events and reloads the rules every
+// interval until ctx is cancelled
+func (s *IPAccessService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if denied, err := s.DenyFromSecurityEvents(); err != nil {
+				log.Printf("⚠️ IP denylist update from security events failed: %v", err)
+			} else if denied > 0 {
+				log.Printf("🚫 Denied %d IPs with repeated security events", denied)
+			}
+			if err := s.Reload(); err != nil {
+				log.Printf("⚠️ Failed to reload IP access rules: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Reload reads the unexpired rules into memory
+func (s *IPAccessService) Reload() error {
+	var rules []models.IPAccessRule
+	if err := s.db.Where("expires_at IS NULL OR expires_at > ?", s.now()).Find(&rules).Error; err != nil {
+		return fmt.Errorf("failed to load IP access rules: %w", err)
+	}
+
+	allow := append([]ipNetwork{}, s.static...)
+	var deny []ipNetwork
+	for _, rule := range rules {
+		network, err := ParseIPNetwork(rule.CIDR)
+		if err != nil {
+			log.Printf("⚠️ Skipping IP access rule %d: %v", rule.ID, err)
+			continue
+		}
+		compiled := ipNetwork{network: network, expiresAt: rule.ExpiresAt}
+		if rule.List == models.IPAccessAllow {
+			allow = append(allow, compiled)
+		} else {
+			deny = append(deny, compiled)
+		}
+	}
+
+	s.mutex.Lock()
+	s.allow, s.deny = allow, deny
+	s.mutex.Unlock()
+	return nil
+}
+
+// HasAllowlist reports whether admin routes are restricted to an allowlist
+func (s *IPAccessService) HasAllowlist() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.activeNetworks(s.allow)) > 0
+}
+
+// AdminAllowed reports whether ip may reach admin routes: any IP while no
+// allowlist exists, otherwise only allowlisted ones
+func (s *IPAccessService) AdminAllowed(ip string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	allow := s.activeNetworks(s.allow)
+	return len(allow) == 0 || containsIP(allow, ip)
+}
+
+// Denied reports whether ip is on the denylist
+func (s *IPAccessService) Denied(ip string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return containsIP(s.activeNetworks(s.deny), ip)
+}
+
+// ConfiguredAllowlist returns the allowlist entries from config, which are
+// not stored as rules and cannot be removed through the API
+func (s *IPAccessService) ConfiguredAllowlist() []string {
+	entries := make([]string, len(s.static))
+	for i, entry := range s.static {
+		entries[i] = entry.network.String()
+	}
+	return entries
+}
+
+// Rules lists the stored rules on list ("" for both), newest first
+func (s *IPAccessService) Rules(list string) ([]models.IPAccessRule, error) {
+	query := s.db.Order("created_at DESC, id DESC")
+	if list != "" {
+		query = query.Where("list = ?", list)
+	}
+	rules := []models.IPAccessRule{}
+	if err := query.Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list IP access rules: %w", err)
+	}
+	return rules, nil
+}
+
+// AddRule stores a rule for the network and applies it immediately
+func (s *IPAccessService) AddRule(rule models.IPAccessRule) (*models.IPAccessRule, error) {
+	if rule.List != models.IPAccessAllow && rule.List != models.IPAccessDeny {
+		return nil, fmt.Errorf("%w: list must be allow or deny", ErrInvalidIPRule)
+	}
+	network, err := ParseIPNetwork(rule.CIDR)
+	if err != nil {
+		return nil, err
+	}
+	rule.CIDR = network.String()
+	if rule.Source == "" {
+		rule.Source = models.IPAccessSourceManual
+	}
+
+	var existing int64
+	if err := s.db.Model(&models.IPAccessRule{}).
+		Where("list = ? AND cidr = ? AND (expires_at IS NULL OR expires_at > ?)", rule.List, rule.CIDR, s.now()).
+		Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check IP access rules: %w", err)
+	}
+	if existing > 0 {
+		return nil, ErrIPRuleExists
+	}
+
+	if err := s.db.Create(&rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to save IP access rule: %w", err)
+	}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// RemoveRule deletes a stored rule and lifts it immediately
+func (s *IPAccessService) RemoveRule(id uint) error {
+	result := s.db.Delete(&models.IPAccessRule{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete IP access rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrIPRuleNotFound
+	}
+	return s.Reload()
+}
+
+// DenySecurityEvent denies the IP a security event came from
+func (s *IPAccessService) DenySecurityEvent(eventID uint, rule models.IPAccessRule) (*models.IPAccessRule, error) {
+	var event models.SecurityEvent
+	if err := s.db.First(&event, eventID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: security event %d not found", ErrInvalidIPRule, eventID)
+		}
+		return nil, fmt.Errorf("failed to load security event: %w", err)
+	}
+	if event.IPAddress == "" {
+		return nil, fmt.Errorf("%w: security event %d has no IP address", ErrInvalidIPRule, eventID)
+	}
+
+	rule.List = models.IPAccessDeny
+	rule.CIDR = event.IPAddress
+	rule.Source = models.IPAccessSourceSecurityEvent
+	rule.SecurityEventID = &event.ID
+	if rule.Reason == "" {
+		rule.Reason = fmt.Sprintf("%s security event from %s", event.Type, event.Endpoint)
+	}
+	return s.AddRule(rule)
+}
+
+// DenyFromSecurityEvents denies IPs with at least EventThreshold unresolved
+// high or critical security events within EventWindow, returning how many
+// were added. Allowlisted and loopback IPs are never denied automatically.
+func (s *IPAccessService) DenyFromSecurityEvents() (int, error) {
+	if s.settings.EventThreshold <= 0 {
+		return 0, nil
+	}
+	window := s.settings.EventWindow
+	if window <= 0 {
+		window = time.Hour
+	}
+	now := s.now()
+
+	var offenders []struct {
+		IPAddress string
+		Events    int64
+		LatestID  uint
+	}
+	if err := s.db.Model(&models.SecurityEvent{}).
+		Select("ip_address, COUNT(*) AS events, MAX(id) AS latest_id").
+		Where("created_at >= ? AND resolved = ? AND ip_address <> '' AND severity IN ?",
+			now.Add(-window), false, []string{models.SecuritySeverityHigh, models.SecuritySeverityCritical}).
+		Group("ip_address").
+		Having("COUNT(*) >= ?", s.settings.EventThreshold).
+		Scan(&offenders).Error; err != nil {
+		return 0, fmt.Errorf("failed to count security events: %w", err)
+	}
+
+	denied := 0
+	for _, offender := range offenders {
+		ip := net.ParseIP(offender.IPAddress)
+		if ip == nil || ip.IsLoopback() || s.Denied(offender.IPAddress) || (s.HasAllowlist() && s.AdminAllowed(offender.IPAddress)) {
+			continue
+		}
+
+		rule := models.IPAccessRule{
+			List:            models.IPAccessDeny,
+			CIDR:            offender.IPAddress,
+			Reason:          fmt.Sprintf("%d high severity security events within %s", offender.Events, window),
+			Source:          models.IPAccessSourceSecurityEvent,
+			SecurityEventID: &offender.LatestID,
+		}
+		if s.settings.DenyDuration > 0 {
+			expiresAt := now.Add(s.settings.DenyDuration)
+			rule.ExpiresAt = &expiresAt
+		}
+		if _, err := s.AddRule(rule); errors.Is(err, ErrIPRuleExists) {
+			continue
+		} else if err != nil {
+			return denied, err
+		}
+		denied++
+	}
+	return denied, nil
+}
+
+// activeNetworks drops rules that expired since the last reload
+func (s *IPAccessService) activeNetworks(networks []ipNetwork) []ipNetwork {
+	now := s.now()
+	active := networks[:0:0]
+	for _, network := range networks {
+		if network.expiresAt == nil || network.expiresAt.After(now) {
+			active = append(active, network)
+		}
+	}
+	return active
+}
+
+func containsIP(networks []ipNetwork, value string) bool {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestParseIPNetwork(t *testing.T) {
+	cases := map[string]string{
+		"10.1.2.3":        "10.1.2.3/32",
+		" 10.1.2.3/16 ":   "10.1.0.0/16",
+		"2001:db8::1":     "2001:db8::1/128",
+		"2001:db8::/32":   "2001:db8::/32",
+		"::ffff:10.1.2.3": "10.1.2.3/32",
+	}
+	for value, want := range cases {
+		network, err := ParseIPNetwork(value)
+		if err != nil || network.String() != want {
+			t.Errorf("ParseIPNetwork(%q) = %v, %v; want %s", value, network, err, want)
+		}
+	}
+	for _, value := range []string{"", "10.1.2", "10.1.2.3/33", "example.com"} {
+		if _, err := ParseIPNetwork(value); !errors.Is(err, ErrInvalidIPRule) {
+			t.Errorf("Expected ParseIPNetwork(%q) to be rejected, got %v", value, err)
+		}
+	}
+}
+
+func TestIPAccessService_DeniesRepeatedSecurityEvents(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.IPAccessRule{}, &models.SecurityEvent{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	access, err := NewIPAccessService(db, IPAccessSettings{
+		Allowlist:      []string{"203.0.113.0/24"},
+		EventThreshold: 3,
+		EventWindow:    time.Hour,
+		DenyDuration:   24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create IP access service: %v", err)
+	}
+
+	now := time.Now()
+	record := func(ip, severity string, age time.Duration, resolved bool) {
+		db.Create(&models.SecurityEvent{Type: models.SecurityEventSQLInjection, Severity: severity, IPAddress: ip,
+			Endpoint: "/api/v1/leads", Timestamp: now.Add(-age), Resolved: resolved})
+	}
+	for i := 0; i < 3; i++ {
+		record("192.0.2.1", models.SecuritySeverityHigh, time.Minute, false)   // denied
+		record("192.0.2.2", models.SecuritySeverityLow, time.Minute, false)    // low severity
+		record("192.0.2.3", models.SecuritySeverityHigh, 2*time.Hour, false)   // outside the window
+		record("192.0.2.4", models.SecuritySeverityHigh, time.Minute, true)    // resolved
+		record("203.0.113.9", models.SecuritySeverityHigh, time.Minute, false) // allowlisted
+	}
+
+	denied, err := access.DenyFromSecurityEvents()
+	if err != nil {
+		t.Fatalf("DenyFromSecurityEvents failed: %v", err)
+	}
+	if denied != 1 || !access.Denied("192.0.2.1") {
+		t.Fatalf("Expected only 192.0.2.1 denied, got %d", denied)
+	}
+	for _, ip := range []string{"192.0.2.2", "192.0.2.3", "192.0.2.4", "203.0.113.9"} {
+		if access.Denied(ip) {
+			t.Errorf("Expected %s not to be denied", ip)
+		}
+	}
+	if denied, _ := access.DenyFromSecurityEvents(); denied != 0 {
+		t.Errorf("Expected an already denied IP to be skipped, got %d", denied)
+	}
+
+	// Event-driven denials expire
+	access.now = func() time.Time { return now.Add(25 * time.Hour) }
+	if access.Denied("192.0.2.1") {
+		t.Error("Expected the denial to expire")
+	}
+}
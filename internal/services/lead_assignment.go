@@ -0,0 +1,257 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+// Lead assignment errors
+var (
+	ErrRoutingLeadNotFound = errors.New("lead not found")
+	ErrRoutingAgentInvalid = errors.New("agent not found or inactive")
+)
+
+// LeadAssignmentService assigns leads to agents, choosing the agent with
+// LeadRoutingService under the saved routing config. Each assignment is
+// recorded on the lead and in its history, and the agent is notified.
+type LeadAssignmentService struct {
+	db      *gorm.DB
+	routing *LeadRoutingService
+	hub     *AdminNotificationHub
+	now     func() time.Time
+
+	// Routing loads workloads then assigns, so leads are routed one at a time
+	mu sync.Mutex
+}
+
+// NewLeadAssignmentService creates a lead assignment service routing with routing
+func NewLeadAssignmentService(db *gorm.DB, routing *LeadRoutingService) *LeadAssignmentService {
+	return &LeadAssignmentService{
+		db:      db,
+		routing: routing,
+		now:     time.Now,
+	}
+}
+
+// SetNotificationHub notifies agents of the leads assigned to them
+func (s *LeadAssignmentService) SetNotificationHub(hub *AdminNotificationHub) {
+	s.hub = hub
+}
+
+// Config returns the routing config in use
+func (s *LeadAssignmentService) Config() (models.LeadRoutingConfig, error) {
+	return models.LoadLeadRoutingConfig(s.db)
+}
+
+// UpdateConfig validates and saves the routing config
+func (s *LeadAssignmentService) UpdateConfig(config models.LeadRoutingConfig, updatedBy string) (models.LeadRoutingConfig, error) {
+	if err := config.Validate(); err != nil {
+		return config, err
+	}
+	config.ID = models.LeadRoutingConfigID
+	config.UpdatedBy = updatedBy
+	if err := s.db.Save(&config).Error; err != nil {
+		return config, err
+	}
+	log.Printf("🔀 Lead routing set to %s (auto-route: %v) by %s", config.Strategy, config.AutoRoute, updatedBy)
+	return config, nil
+}
+
+// RouteLead assigns a lead to the agent the routing strategy picks.
+// requestedBy is the admin who asked for routing, empty when automatic.
+func (s *LeadAssignmentService) RouteLead(leadID uint, requestedBy string) (*models.LeadAssignment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var lead models.Lead
+	if err := s.db.First(&lead, leadID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoutingLeadNotFound
+		}
+		return nil, err
+	}
+
+	config, err := s.Config()
+	if err != nil {
+		return nil, err
+	}
+	agents, workloads, err := s.loadAgents()
+	if err != nil {
+		return nil, err
+	}
+	s.routing.SetAgents(agents, workloads)
+	s.routing.SetStrategy(config.Strategy)
+
+	result, err := s.routing.RouteLeadToAgent(routingRequestForLead(&lead, s.now()))
+	if err != nil {
+		return nil, err
+	}
+	return s.assign(&lead, result.AssignedAgentID, config.Strategy, result.RoutingReason, requestedBy)
+}
+
+// AutoRoute routes a newly created lead when auto-routing is on and the lead
+// has no agent yet. Failures are logged; the lead stays unassigned.
+func (s *LeadAssignmentService) AutoRoute(lead *models.Lead) {
+	if lead.AssignedAgentID != "" {
+		return
+	}
+	config, err := s.Config()
+	if err != nil {
+		log.Printf("⚠️ Failed to load lead routing config: %v", err)
+		return
+	}
+	if !config.AutoRoute {
+		return
+	}
+
+	assignment, err := s.RouteLead(lead.ID, "")
+	if err != nil {
+		log.Printf("⚠️ Failed to auto-route lead %d: %v", lead.ID, err)
+		return
+	}
+	lead.AssignedAgentID = assignment.AgentID
+}
+
+// ReassignLead assigns a lead to an agent chosen by an admin
+func (s *LeadAssignmentService) ReassignLead(leadID uint, agentID, assignedBy, reason string) (*models.LeadAssignment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var agent models.AdminUser
+	if err := s.db.Where("id = ? AND active = ?", agentID, true).First(&agent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoutingAgentInvalid
+		}
+		return nil, err
+	}
+
+	var lead models.Lead
+	if err := s.db.First(&lead, leadID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoutingLeadNotFound
+		}
+		return nil, err
+	}
+
+	if reason == "" {
+		reason = "Reassigned by admin"
+	}
+	return s.assign(&lead, agent.ID, models.LeadRoutingManual, reason, assignedBy)
+}
+
+// ListAssignments returns a lead's assignment history, newest first
+func (s *LeadAssignmentService) ListAssignments(leadID uint) ([]models.LeadAssignment, error) {
+	var assignments []models.LeadAssignment
+	err := s.db.Where("lead_id = ?", leadID).Order("created_at DESC, id DESC").Find(&assignments).Error
+	return assignments, err
+}
+
+// assign records the agent on the lead and in its history, then notifies the agent
+func (s *LeadAssignmentService) assign(lead *models.Lead, agentID, strategy, reason, assignedBy string) (*models.LeadAssignment, error) {
+	assignment := &models.LeadAssignment{
+		LeadID:          lead.ID,
+		AgentID:         agentID,
+		PreviousAgentID: lead.AssignedAgentID,
+		Strategy:        strategy,
+		Reason:          reason,
+		AssignedBy:      assignedBy,
+		CreatedAt:       s.now(),
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Lead{}).Where("id = ?", lead.ID).Updates(map[string]interface{}{
+			"assigned_agent_id": agentID,
+			"version":           gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Create(assignment).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign lead %d: %w", lead.ID, err)
+	}
+	lead.AssignedAgentID = agentID
+
+	if s.hub != nil {
+		s.hub.SendLeadAssignedAlert(lead, assignment)
+	}
+	log.Printf("🔀 Lead %d assigned to %s (%s: %s)", lead.ID, agentID, strategy, reason)
+	return assignment, nil
+}
+
+// loadAgents returns the active admin users accepting leads, with their
+// routing profiles applied and their open and today's lead counts
+func (s *LeadAssignmentService) loadAgents() ([]*Agent, map[string]*AgentWorkload, error) {
+	var admins []models.AdminUser
+	if err := s.db.Where("active = ?", true).Order("id").Find(&admins).Error; err != nil {
+		return nil, nil, err
+	}
+	var profiles []models.AgentRoutingProfile
+	if err := s.db.Find(&profiles).Error; err != nil {
+		return nil, nil, err
+	}
+	profileByAgent := make(map[string]models.AgentRoutingProfile, len(profiles))
+	for _, profile := range profiles {
+		profileByAgent[profile.AgentID] = profile
+	}
+
+	now := s.now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	agents := make([]*Agent, 0, len(admins))
+	workloads := make(map[string]*AgentWorkload, len(admins))
+	for _, admin := range admins {
+		profile, ok := profileByAgent[admin.ID]
+		if !ok {
+			profile = models.AgentRoutingProfile{AgentID: admin.ID, Accepting: true, Weight: 1}
+		}
+		if !profile.Accepting {
+			continue
+		}
+
+		var openLeads, leadsToday int64
+		s.db.Model(&models.Lead{}).Where("assigned_agent_id = ? AND status != ?", admin.ID, "closed").Count(&openLeads)
+		s.db.Model(&models.LeadAssignment{}).Where("agent_id = ? AND created_at >= ?", admin.ID, startOfDay).Count(&leadsToday)
+		var last models.LeadAssignment
+		s.db.Where("agent_id = ?", admin.ID).Order("created_at DESC, id DESC").Limit(1).Find(&last)
+
+		agents = append(agents, &Agent{
+			ID:              admin.ID,
+			Name:            admin.Username,
+			Email:           admin.Email,
+			Active:          true,
+			GeographicAreas: profile.TerritoryList(),
+			MaxDailyLeads:   profile.MaxDailyLeads,
+			CurrentWorkload: int(openLeads),
+			Weight:          profile.Weight,
+			LastAssignedAt:  last.CreatedAt,
+		})
+		workloads[admin.ID] = &AgentWorkload{
+			AgentID:     admin.ID,
+			ActiveLeads: int(openLeads),
+			LeadsToday:  int(leadsToday),
+		}
+	}
+	return agents, workloads, nil
+}
+
+// routingRequestForLead describes a lead for LeadRoutingService
+func routingRequestForLead(lead *models.Lead, now time.Time) LeadRoutingRequest {
+	request := LeadRoutingRequest{
+		LeadID:        strconv.FormatUint(uint64(lead.ID), 10),
+		Location:      lead.City,
+		LeadSource:    lead.Source,
+		RequestedTime: now,
+	}
+	if zip, ok := lead.CustomFields["zip_code"].(string); ok {
+		request.ZipCode = zip
+	}
+	return request
+}
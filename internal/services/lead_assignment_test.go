@@ -0,0 +1,91 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+func setupLeadAssignmentTest(t *testing.T) (*gorm.DB, *LeadAssignmentService) {
+	db := newAdminUsersTestDB(t)
+	if err := db.AutoMigrate(&models.Lead{}, &models.LeadRoutingConfig{}, &models.AgentRoutingProfile{}, &models.LeadAssignment{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	db.Exec(`INSERT INTO admin_users (id, username, email, role, active) VALUES ('agent-a', 'alex', 'alex@example.com', 'agent', true),
+		('agent-b', 'blair', 'blair@example.com', 'agent', true), ('agent-c', 'casey', 'casey@example.com', 'agent', false)`)
+
+	service := NewLeadAssignmentService(db, NewLeadRoutingService())
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	service.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	return db, service
+}
+
+func TestLeadAssignment_RoundRobinAlternatesAgents(t *testing.T) {
+	db, service := setupLeadAssignmentTest(t)
+
+	var agents []string
+	for i := uint(1); i <= 4; i++ {
+		lead := models.Lead{ID: i, FirstName: "Lead", LastName: "Test", Email: fmt.Sprintf("lead%d@example.com", i), FUBLeadID: fmt.Sprintf("fub-%d", i), Status: "new"}
+		db.Create(&lead)
+		service.AutoRoute(&lead)
+		if lead.AssignedAgentID == "" {
+			t.Fatalf("Expected lead %d to be auto-routed", i)
+		}
+		agents = append(agents, lead.AssignedAgentID)
+	}
+
+	if agents[0] == agents[1] || agents[0] != agents[2] || agents[1] != agents[3] {
+		t.Errorf("Expected leads to alternate between the active agents, got %v", agents)
+	}
+	for _, agent := range agents {
+		if agent == "agent-c" {
+			t.Errorf("Expected the inactive agent to get no leads, got %v", agents)
+		}
+	}
+}
+
+func TestLeadAssignment_TerritoryAndManualReassign(t *testing.T) {
+	db, service := setupLeadAssignmentTest(t)
+	db.Create(&models.AgentRoutingProfile{AgentID: "agent-b", Accepting: true, Weight: 1, Territories: "Katy, 77002"})
+	if _, err := service.UpdateConfig(models.LeadRoutingConfig{Strategy: models.LeadRoutingTerritory, AutoRoute: false}, "admin"); err != nil {
+		t.Fatalf("Failed to update config: %v", err)
+	}
+
+	lead := models.Lead{ID: 1, FirstName: "Dana", LastName: "Katy", Email: "dana@example.com", FUBLeadID: "fub-1", City: "katy", Status: "new"}
+	db.Create(&lead)
+	service.AutoRoute(&lead)
+	if lead.AssignedAgentID != "" {
+		t.Fatalf("Expected no auto-routing when it is off, got %s", lead.AssignedAgentID)
+	}
+
+	assignment, err := service.RouteLead(1, "agent-a")
+	if err != nil {
+		t.Fatalf("Failed to route lead: %v", err)
+	}
+	if assignment.AgentID != "agent-b" || assignment.Strategy != models.LeadRoutingTerritory {
+		t.Errorf("Expected the territory agent, got %+v", assignment)
+	}
+
+	if _, err := service.ReassignLead(1, "agent-c", "agent-a", ""); err != ErrRoutingAgentInvalid {
+		t.Errorf("Expected an inactive agent to be rejected, got %v", err)
+	}
+	if _, err := service.ReassignLead(1, "agent-a", "agent-a", "Speaks Spanish"); err != nil {
+		t.Fatalf("Failed to reassign lead: %v", err)
+	}
+
+	var stored models.Lead
+	db.First(&stored, 1)
+	if stored.AssignedAgentID != "agent-a" {
+		t.Errorf("Expected the lead to be reassigned, got %s", stored.AssignedAgentID)
+	}
+	history, _ := service.ListAssignments(1)
+	if len(history) != 2 || history[0].Strategy != models.LeadRoutingManual || history[0].PreviousAgentID != "agent-b" {
+		t.Errorf("Expected manual reassignment on top of the history, got %+v", history)
+	}
+}
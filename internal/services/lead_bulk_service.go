@@ -0,0 +1,159 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+// MaxBulkLeadBatch caps how many leads a single bulk operation may touch
+const MaxBulkLeadBatch = 1000
+
+// ValidLeadStatuses are the statuses a lead may be moved to
+var ValidLeadStatuses = []string{"new", "contacted", "qualified", "negotiating", "closed", "lost", "archived"}
+
+var (
+	ErrBulkBatchTooLarge  = fmt.Errorf("batch exceeds %d leads", MaxBulkLeadBatch)
+	ErrInvalidLeadStatus  = errors.New("invalid lead status")
+	ErrMixedLeadOwnership = errors.New("batch contains leads assigned to different agents")
+)
+
+// BulkLeadStatusItem is the outcome for one requested lead ID
+type BulkLeadStatusItem struct {
+	ID             int64  `json:"id"`
+	Success        bool   `json:"success"`
+	PreviousStatus string `json:"previous_status,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// BulkLeadStatusResult summarizes a bulk status update
+type BulkLeadStatusResult struct {
+	Status    string               `json:"status"`
+	DryRun    bool                 `json:"dry_run"`
+	Updated   int                  `json:"updated"`
+	Failed    int                  `json:"failed"`
+	Results   []BulkLeadStatusItem `json:"results"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// LeadBulkService applies bulk changes to leads
+type LeadBulkService struct {
+	db           *gorm.DB
+	agentScoping bool
+}
+
+// NewLeadBulkService creates a new lead bulk service
+func NewLeadBulkService(db *gorm.DB) *LeadBulkService {
+	return &LeadBulkService{db: db}
+}
+
+// SetAgentScoping restricts agents to leads assigned to them and rejects
+// batches that span more than one assigned agent
+func (s *LeadBulkService) SetAgentScoping(enabled bool) {
+	s.agentScoping = enabled
+}
+
+// UpdateStatus moves the given leads to status in a single transaction,
+// reporting success or failure per ID. IDs that are missing or not assigned to
+// the actor fail individually; the rest are updated together. With dryRun the
+// outcome is computed without writing anything.
+func (s *LeadBulkService) UpdateStatus(actor *models.AdminUser, ids []int64, status string, dryRun bool) (*BulkLeadStatusResult, error) {
+	if len(ids) > MaxBulkLeadBatch {
+		return nil, ErrBulkBatchTooLarge
+	}
+	if !isValidLeadStatus(status) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLeadStatus, status)
+	}
+
+	result := &BulkLeadStatusResult{
+		Status:    status,
+		DryRun:    dryRun,
+		Results:   make([]BulkLeadStatusItem, 0, len(ids)),
+		UpdatedAt: time.Now(),
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var leads []models.Lead
+		if err := tx.Where("id IN ?", ids).Find(&leads).Error; err != nil {
+			return fmt.Errorf("failed to load leads: %w", err)
+		}
+		byID := make(map[int64]models.Lead, len(leads))
+		owners := make(map[string]bool)
+		for _, lead := range leads {
+			byID[int64(lead.ID)] = lead
+			owners[lead.AssignedAgentID] = true
+		}
+		if s.agentScoping && len(owners) > 1 {
+			return ErrMixedLeadOwnership
+		}
+
+		seen := make(map[int64]bool, len(ids))
+		var updatable []int64
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			item := BulkLeadStatusItem{ID: id}
+			lead, found := byID[id]
+			switch {
+			case !found:
+				item.Error = "lead not found"
+			case !s.mayUpdate(actor, lead):
+				item.Error = "lead is not assigned to you"
+			default:
+				item.Success = true
+				item.PreviousStatus = lead.Status
+				updatable = append(updatable, id)
+			}
+			result.Results = append(result.Results, item)
+		}
+
+		if dryRun || len(updatable) == 0 {
+			return nil
+		}
+		return tx.Model(&models.Lead{}).
+			Where("id IN ?", updatable).
+			Updates(map[string]interface{}{
+				"status":     status,
+				"updated_at": result.UpdatedAt,
+				"version":    gorm.Expr("version + 1"),
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range result.Results {
+		if item.Success {
+			result.Updated++
+		} else {
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// mayUpdate applies agent scoping; team admins may update any lead
+func (s *LeadBulkService) mayUpdate(actor *models.AdminUser, lead models.Lead) bool {
+	if !s.agentScoping {
+		return true
+	}
+	if actor == nil {
+		return false
+	}
+	return models.IsTeamAdminRole(actor.Role) || lead.AssignedAgentID == actor.ID
+}
+
+func isValidLeadStatus(status string) bool {
+	for _, valid := range ValidLeadStatuses {
+		if status == valid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestLeadBulkService_UpdateStatus(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Lead{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	leads := []models.Lead{
+		{FirstName: "A", LastName: "One", Email: "a@example.com", FUBLeadID: "fub-a", AssignedAgentID: "agent-1"},
+		{FirstName: "B", LastName: "Two", Email: "b@example.com", FUBLeadID: "fub-b", AssignedAgentID: "agent-1"},
+		{FirstName: "C", LastName: "Three", Email: "c@example.com", FUBLeadID: "fub-c", AssignedAgentID: "agent-2"},
+	}
+	if err := db.Create(&leads).Error; err != nil {
+		t.Fatalf("Failed to create leads: %v", err)
+	}
+	a, b, c := int64(leads[0].ID), int64(leads[1].ID), int64(leads[2].ID)
+
+	service := NewLeadBulkService(db)
+	agent := &models.AdminUser{ID: "agent-1", Role: models.RoleAdmin}
+
+	if _, err := service.UpdateStatus(agent, []int64{a}, "bogus", false); !errors.Is(err, ErrInvalidLeadStatus) {
+		t.Fatalf("Expected ErrInvalidLeadStatus, got %v", err)
+	}
+	if _, err := service.UpdateStatus(agent, make([]int64, MaxBulkLeadBatch+1), "contacted", false); !errors.Is(err, ErrBulkBatchTooLarge) {
+		t.Fatalf("Expected ErrBulkBatchTooLarge, got %v", err)
+	}
+
+	// Dry run reports per-ID outcomes without writing
+	result, err := service.UpdateStatus(agent, []int64{a, b, 9999}, "contacted", true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if result.Updated != 2 || result.Failed != 1 || result.Results[2].Error == "" {
+		t.Fatalf("Unexpected dry run result %+v", result)
+	}
+	var stored models.Lead
+	db.First(&stored, a)
+	if stored.Status == "contacted" {
+		t.Fatalf("Dry run must not update leads")
+	}
+
+	result, err = service.UpdateStatus(agent, []int64{a, b}, "contacted", false)
+	if err != nil || result.Updated != 2 {
+		t.Fatalf("Expected 2 updated leads, got %+v (err %v)", result, err)
+	}
+	db.First(&stored, a)
+	if stored.Status != "contacted" || stored.Version != 2 {
+		t.Fatalf("Expected lead contacted at version 2, got %+v", stored)
+	}
+
+	// With agent scoping, mixed batches are rejected and other agents' leads fail
+	service.SetAgentScoping(true)
+	if _, err := service.UpdateStatus(agent, []int64{a, c}, "qualified", false); !errors.Is(err, ErrMixedLeadOwnership) {
+		t.Fatalf("Expected ErrMixedLeadOwnership, got %v", err)
+	}
+	result, err = service.UpdateStatus(agent, []int64{c}, "qualified", false)
+	if err != nil || result.Updated != 0 || result.Results[0].Success {
+		t.Fatalf("Expected agent to be refused another agent's lead, got %+v (err %v)", result, err)
+	}
+	teamAdmin := &models.AdminUser{ID: "boss", Role: models.RoleMainAdmin}
+	result, err = service.UpdateStatus(teamAdmin, []int64{c}, "qualified", false)
+	if err != nil || result.Updated != 1 {
+		t.Fatalf("Expected team admin to update any lead, got %+v (err %v)", result, err)
+	}
+}
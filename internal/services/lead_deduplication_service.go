@@ -0,0 +1,300 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidMerge      = errors.New("invalid merge request")
+	ErrMergeLeadNotFound = errors.New("lead not found")
+)
+
+// DuplicateCluster is a group of leads sharing a blind-indexed email or phone,
+// directly or through another lead in the group
+type DuplicateCluster struct {
+	MatchedOn          []string                  `json:"matched_on"` // "email", "phone"
+	SuggestedPrimaryID uint                      `json:"suggested_primary_id"`
+	Leads              []models.LeadReengagement `json:"-"`
+}
+
+// LeadDeduplicationService finds and merges re-engagement leads imported more
+// than once (FUB, CSV, parsed email) with the same email or phone
+type LeadDeduplicationService struct {
+	db *gorm.DB
+}
+
+// NewLeadDeduplicationService creates a new lead deduplication service
+func NewLeadDeduplicationService(db *gorm.DB) *LeadDeduplicationService {
+	return &LeadDeduplicationService{db: db}
+}
+
+// FindDuplicates groups the leads visible to admin by blind-indexed email and
+// phone. Leads linked through either field end up in the same cluster.
+func (s *LeadDeduplicationService) FindDuplicates(admin *models.AdminUser) ([]DuplicateCluster, error) {
+	scopes := []func(*gorm.DB) *gorm.DB{models.LeadsVisibleTo(admin)}
+	emailHashes, err := s.duplicatedHashes("email_hash", scopes)
+	if err != nil {
+		return nil, err
+	}
+	phoneHashes, err := s.duplicatedHashes("phone_hash", scopes)
+	if err != nil {
+		return nil, err
+	}
+	if len(emailHashes) == 0 && len(phoneHashes) == 0 {
+		return []DuplicateCluster{}, nil
+	}
+
+	var leads []models.LeadReengagement
+	query := s.db.Model(&models.LeadReengagement{}).Scopes(scopes...)
+	switch {
+	case len(emailHashes) > 0 && len(phoneHashes) > 0:
+		query = query.Where("email_hash IN ? OR phone_hash IN ?", emailHashes, phoneHashes)
+	case len(emailHashes) > 0:
+		query = query.Where("email_hash IN ?", emailHashes)
+	default:
+		query = query.Where("phone_hash IN ?", phoneHashes)
+	}
+	if err := query.Order("id").Find(&leads).Error; err != nil {
+		return nil, fmt.Errorf("failed to load duplicate leads: %w", err)
+	}
+
+	// Union leads that share an email or phone hash
+	parent := make(map[uint]uint, len(leads))
+	var find func(id uint) uint
+	find = func(id uint) uint {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+	union := func(a, b uint) {
+		rootA, rootB := find(a), find(b)
+		if rootA < rootB {
+			parent[rootB] = rootA
+		} else if rootB < rootA {
+			parent[rootA] = rootB
+		}
+	}
+	firstByHash := map[string]uint{}
+	matchedOn := map[uint]map[string]bool{}
+	for _, lead := range leads {
+		parent[lead.ID] = lead.ID
+	}
+	for _, lead := range leads {
+		for field, hash := range map[string]string{"email": lead.EmailHash, "phone": lead.PhoneHash} {
+			if hash == "" {
+				continue
+			}
+			key := field + ":" + hash
+			if first, ok := firstByHash[key]; ok {
+				union(first, lead.ID)
+				if matchedOn[first] == nil {
+					matchedOn[first] = map[string]bool{}
+				}
+				matchedOn[first][field] = true
+			} else {
+				firstByHash[key] = lead.ID
+			}
+		}
+	}
+
+	byRoot := map[uint]*DuplicateCluster{}
+	fields := map[uint]map[string]bool{}
+	var roots []uint
+	for _, lead := range leads {
+		root := find(lead.ID)
+		cluster, ok := byRoot[root]
+		if !ok {
+			cluster = &DuplicateCluster{}
+			byRoot[root] = cluster
+			fields[root] = map[string]bool{}
+			roots = append(roots, root)
+		}
+		cluster.Leads = append(cluster.Leads, lead)
+		for field := range matchedOn[lead.ID] {
+			fields[root][field] = true
+		}
+	}
+
+	clusters := make([]DuplicateCluster, 0, len(roots))
+	for _, root := range roots {
+		cluster := byRoot[root]
+		if len(cluster.Leads) < 2 {
+			continue
+		}
+		for field := range fields[root] {
+			cluster.MatchedOn = append(cluster.MatchedOn, field)
+		}
+		sort.Strings(cluster.MatchedOn)
+		cluster.SuggestedPrimaryID = suggestPrimaryLead(cluster.Leads)
+		clusters = append(clusters, *cluster)
+	}
+	return clusters, nil
+}
+
+// duplicatedHashes returns the non-empty values of column shared by more than one lead
+func (s *LeadDeduplicationService) duplicatedHashes(column string, scopes []func(*gorm.DB) *gorm.DB) ([]string, error) {
+	var hashes []string
+	err := s.db.Model(&models.LeadReengagement{}).
+		Scopes(scopes...).
+		Where(column+" IS NOT NULL AND "+column+" <> ''").
+		Group(column).
+		Having("COUNT(*) > 1").
+		Pluck(column, &hashes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to group leads by %s: %w", column, err)
+	}
+	return hashes, nil
+}
+
+// suggestPrimaryLead prefers the lead with the most engagement, then the oldest
+func suggestPrimaryLead(leads []models.LeadReengagement) uint {
+	best := leads[0]
+	for _, lead := range leads[1:] {
+		leadScore := lead.EmailsSent + lead.EmailsOpened + lead.EmailsClicked
+		bestScore := best.EmailsSent + best.EmailsOpened + best.EmailsClicked
+		if leadScore > bestScore || (leadScore == bestScore && lead.CreatedAt.Before(best.CreatedAt)) {
+			best = lead
+		}
+	}
+	return best.ID
+}
+
+// Merge folds duplicate leads into the primary lead in one transaction:
+// campaign executions, behavioral trigger events, and application records are
+// reassigned to the primary, the duplicates are soft-deleted, and a
+// LeadMergeAudit is recorded. Every lead must be visible to the actor.
+func (s *LeadDeduplicationService) Merge(actor *models.AdminUser, primaryID uint, duplicateIDs []uint) (*models.LeadMergeAudit, error) {
+	if len(duplicateIDs) == 0 {
+		return nil, fmt.Errorf("%w: no duplicate leads given", ErrInvalidMerge)
+	}
+	seen := map[uint]bool{primaryID: true}
+	for _, id := range duplicateIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("%w: lead %d is listed twice or is the primary", ErrInvalidMerge, id)
+		}
+		seen[id] = true
+	}
+
+	audit := &models.LeadMergeAudit{
+		PrimaryLeadID: primaryID,
+		Reassigned:    models.JSONB{},
+		CarriedFlags:  models.JSONB{},
+		ActorID:       actor.ID,
+		ActorName:     actor.Username,
+	}
+	visible := models.LeadsVisibleTo(actor)
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var primary models.LeadReengagement
+		if err := tx.Scopes(visible).First(&primary, "id = ?", primaryID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: primary lead %d", ErrMergeLeadNotFound, primaryID)
+			}
+			return err
+		}
+
+		var duplicates []models.LeadReengagement
+		if err := tx.Scopes(visible).Where("id IN ?", duplicateIDs).Find(&duplicates).Error; err != nil {
+			return err
+		}
+		if len(duplicates) != len(duplicateIDs) {
+			return fmt.Errorf("%w: %d of %d duplicate leads", ErrMergeLeadNotFound, len(duplicateIDs)-len(duplicates), len(duplicateIDs))
+		}
+
+		var fubIDs []string
+		for _, duplicate := range duplicates {
+			audit.MergedLeadIDs = append(audit.MergedLeadIDs, strconv.FormatUint(uint64(duplicate.ID), 10))
+			if duplicate.FUBContactID != "" && duplicate.FUBContactID != primary.FUBContactID {
+				fubIDs = append(fubIDs, duplicate.FUBContactID)
+			}
+		}
+		audit.MergedFUBIDs = fubIDs
+
+		// An opt-out on any duplicate must survive the merge, or the primary
+		// could be emailed or texted by someone who asked not to be
+		carried := mergedOptOuts(primary, duplicates)
+		if len(carried) > 0 {
+			if err := tx.Model(&models.LeadReengagement{}).Where("id = ?", primaryID).Updates(carried).Error; err != nil {
+				return fmt.Errorf("failed to carry opt-outs to the primary lead: %w", err)
+			}
+			for field, value := range carried {
+				audit.CarriedFlags[field] = value
+			}
+		}
+
+		// Campaigns reference the lead row directly
+		result := tx.Model(&models.CampaignExecution{}).
+			Where("lead_reengagement_id IN ?", duplicateIDs).
+			Update("lead_reengagement_id", primaryID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to reassign campaign executions: %w", result.Error)
+		}
+		audit.Reassigned["campaign_executions"] = result.RowsAffected
+
+		// Events and applications reference the FUB contact
+		if len(fubIDs) > 0 && primary.FUBContactID != "" {
+			for _, target := range []struct{ table, column string }{
+				{"behavioral_triggers_log", "fub_contact_id"},
+				{"application_applicants", "fub_lead_id"},
+				{"approvals", "fub_lead_id"},
+			} {
+				result := tx.Table(target.table).
+					Where(target.column+" IN ?", fubIDs).
+					Update(target.column, primary.FUBContactID)
+				if result.Error != nil {
+					return fmt.Errorf("failed to reassign %s: %w", target.table, result.Error)
+				}
+				audit.Reassigned[target.table] = result.RowsAffected
+			}
+		}
+
+		if err := tx.Where("id IN ?", duplicateIDs).Delete(&models.LeadReengagement{}).Error; err != nil {
+			return fmt.Errorf("failed to delete merged leads: %w", err)
+		}
+		if err := tx.Create(audit).Error; err != nil {
+			return fmt.Errorf("failed to record merge audit: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("🔗 Merged %d duplicate leads into lead %d (by %s)", len(duplicateIDs), primaryID, actor.Username)
+	return audit, nil
+}
+
+// mergedOptOuts returns the most restrictive consent and do-not-contact fields
+// of the duplicates that the primary lead does not already have
+func mergedOptOuts(primary models.LeadReengagement, duplicates []models.LeadReengagement) map[string]interface{} {
+	carried := map[string]interface{}{}
+	for _, duplicate := range duplicates {
+		if duplicate.ConsentStatus == models.ConsentRevoked && primary.ConsentStatus != models.ConsentRevoked {
+			carried["consent_status"] = models.ConsentRevoked
+		}
+		if duplicate.PreviousUnsubscribe && !primary.PreviousUnsubscribe {
+			carried["previous_unsubscribe"] = true
+		}
+		if duplicate.OnDNCList && !primary.OnDNCList {
+			carried["on_dnc_list"] = true
+		}
+	}
+	return carried
+}
+
+// MergeHistory returns recent merges, newest first
+func (s *LeadDeduplicationService) MergeHistory(limit int) ([]models.LeadMergeAudit, error) {
+	var audits []models.LeadMergeAudit
+	if err := s.db.Order("created_at DESC, id DESC").Limit(limit).Find(&audits).Error; err != nil {
+		return nil, fmt.Errorf("failed to load lead merge history: %w", err)
+	}
+	return audits, nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestLeadDeduplicationService_FindAndMerge(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&security.EncryptionKey{}, &models.LeadReengagement{}, &models.CampaignExecution{},
+		&models.ApplicationApplicant{}, &models.Approval{}, &models.LeadMergeAudit{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE behavioral_triggers_log (id INTEGER PRIMARY KEY, trigger_type TEXT, fub_contact_id TEXT)`).Error; err != nil {
+		t.Fatalf("Failed to create trigger log table: %v", err)
+	}
+
+	t.Setenv("ENCRYPTION_KEY", newTestEncryptionKey(t))
+	em, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create encryption manager: %v", err)
+	}
+
+	// 1 and 2 share an email, 2 and 3 share a phone; 4 is unrelated
+	contacts := []struct{ fubID, email, phone string }{
+		{"fub-1", "pat@example.com", "555-0100"},
+		{"fub-2", "PAT@example.com", "555-0199"},
+		{"fub-3", "other@example.com", "(555) 0199"},
+		{"fub-4", "solo@example.com", "555-0400"},
+	}
+	var leads []models.LeadReengagement
+	for i, contact := range contacts {
+		lead := models.LeadReengagement{FUBContactID: contact.fubID, Segment: models.SegmentActive, RiskLevel: models.RiskLow,
+			ConsentStatus: models.ConsentUnknown, OwnerID: "agent-1", EmailsSent: i}
+		lead.SetBlindIndexes(em, "", "", contact.email, contact.phone)
+		if err := db.Create(&lead).Error; err != nil {
+			t.Fatalf("Failed to store lead: %v", err)
+		}
+		leads = append(leads, lead)
+	}
+
+	admin := &models.AdminUser{ID: "admin-1", Username: "admin", Role: models.RoleSuperAdmin}
+	service := NewLeadDeduplicationService(db)
+
+	clusters, err := service.FindDuplicates(admin)
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	if len(clusters) != 1 || len(clusters[0].Leads) != 3 {
+		t.Fatalf("Expected one cluster of 3 leads, got %+v", clusters)
+	}
+	if len(clusters[0].MatchedOn) != 2 || clusters[0].SuggestedPrimaryID != leads[2].ID {
+		t.Errorf("Expected email+phone match with lead %d suggested, got %v / %d", leads[2].ID, clusters[0].MatchedOn, clusters[0].SuggestedPrimaryID)
+	}
+
+	// Agents only see their own leads
+	if clusters, _ := service.FindDuplicates(&models.AdminUser{ID: "agent-2", Role: "agent"}); len(clusters) != 0 {
+		t.Errorf("Expected no clusters for another agent, got %d", len(clusters))
+	}
+
+	primary := leads[0]
+	db.Create(&models.CampaignExecution{LeadReengagementID: leads[1].ID, CampaignName: "c", Status: "sent", ScheduledFor: time.Now()})
+	db.Exec(`INSERT INTO behavioral_triggers_log (trigger_type, fub_contact_id) VALUES ('engagement_spike', 'fub-2'), ('engagement_spike', 'fub-4')`)
+	db.Create(&models.Approval{FUBLeadID: "fub-3"})
+
+	if _, err := service.Merge(admin, primary.ID, []uint{primary.ID}); err == nil {
+		t.Error("Expected merging a lead into itself to fail")
+	}
+
+	// The duplicates opted out in ways the primary did not
+	db.Model(&leads[1]).Update("previous_unsubscribe", true)
+	db.Model(&leads[2]).Updates(map[string]interface{}{"consent_status": models.ConsentRevoked, "on_dnc_list": true})
+
+	audit, err := service.Merge(admin, primary.ID, []uint{leads[1].ID, leads[2].ID})
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if len(audit.MergedLeadIDs) != 2 || len(audit.MergedFUBIDs) != 2 {
+		t.Errorf("Expected 2 merged leads in audit, got %+v", audit)
+	}
+	if len(audit.CarriedFlags) != 3 || audit.CarriedFlags["on_dnc_list"] != true {
+		t.Errorf("Expected the 3 opt-outs recorded in the audit, got %v", audit.CarriedFlags)
+	}
+	var merged models.LeadReengagement
+	db.First(&merged, primary.ID)
+	if merged.ConsentStatus != models.ConsentRevoked || !merged.PreviousUnsubscribe || !merged.OnDNCList {
+		t.Errorf("Expected the duplicates' opt-outs carried to the primary, got consent %s, unsubscribe %v, DNC %v",
+			merged.ConsentStatus, merged.PreviousUnsubscribe, merged.OnDNCList)
+	}
+
+	var count int64
+	db.Model(&models.CampaignExecution{}).Where("lead_reengagement_id = ?", primary.ID).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected campaign moved to primary, got %d", count)
+	}
+	db.Table("behavioral_triggers_log").Where("fub_contact_id = ?", "fub-1").Count(&count)
+	if count != 1 {
+		t.Errorf("Expected trigger event moved to primary, got %d", count)
+	}
+	db.Model(&models.Approval{}).Where("fub_lead_id = ?", "fub-1").Count(&count)
+	if count != 1 {
+		t.Errorf("Expected approval moved to primary, got %d", count)
+	}
+	db.Model(&models.LeadReengagement{}).Count(&count)
+	if count != 2 {
+		t.Errorf("Expected duplicates soft-deleted leaving 2 leads, got %d", count)
+	}
+
+	history, err := service.MergeHistory(10)
+	if err != nil || len(history) != 1 {
+		t.Errorf("Expected 1 merge audit, got %d (%v)", len(history), err)
+	}
+}
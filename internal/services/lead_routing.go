@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"log"
 	"sort"
+	"strings"
 	"time"
+
+	"chrisgross-ctrl-project/internal/models"
 )
 
 // LeadRoutingService provides intelligent lead routing (foundation for future scaling)
@@ -27,6 +30,8 @@ type Agent struct {
 	MaxDailyLeads    int                    `json:"max_daily_leads"`
 	CurrentWorkload  int                    `json:"current_workload"`
 	PerformanceScore float32                `json:"performance_score"` // 0.0 to 1.0
+	Weight           int                    `json:"weight"`            // Share of leads under weighted routing
+	LastAssignedAt   time.Time              `json:"last_assigned_at"`
 	Languages        []string               `json:"languages"`
 	Availability     AgentAvailability      `json:"availability"`
 	Preferences      map[string]interface{} `json:"preferences"`
@@ -150,7 +155,10 @@ func NewLeadRoutingService() *LeadRoutingService {
 func (lrs *LeadRoutingService) RouteLeadToAgent(request LeadRoutingRequest) (*LeadRoutingResult, error) {
 	// If routing is disabled (single agent), return the default agent
 	if !lrs.enabled {
-		return lrs.routeToDefaultAgent(request), nil
+		if result := lrs.routeToDefaultAgent(request); result != nil {
+			return result, nil
+		}
+		return nil, fmt.Errorf("no available agents found for lead %s", request.LeadID)
 	}
 
 	log.Printf("🔄 Routing lead %s with score %.2f", request.LeadID, request.LeadScore)
@@ -208,6 +216,53 @@ func (lrs *LeadRoutingService) AddAgent(agent *Agent) {
 	log.Printf("👤 Agent %s added to routing system", agent.Name)
 }
 
+// SetAgents replaces the agents leads are routed to along with their current
+// workloads, enabling routing when there is more than one
+func (lrs *LeadRoutingService) SetAgents(agents []*Agent, workloads map[string]*AgentWorkload) {
+	lrs.agents = make(map[string]*Agent, len(agents))
+	lrs.workloadTracker.AgentWorkloads = make(map[string]*AgentWorkload, len(agents))
+	for _, agent := range agents {
+		lrs.agents[agent.ID] = agent
+		workload := workloads[agent.ID]
+		if workload == nil {
+			workload = &AgentWorkload{AgentID: agent.ID}
+		}
+		lrs.workloadTracker.AgentWorkloads[agent.ID] = workload
+	}
+	lrs.workloadTracker.LastUpdated = time.Now()
+	lrs.enabled = len(lrs.agents) > 1
+}
+
+// SetStrategy replaces the routing rules with the given strategy. Round robin
+// needs no rule since it is the fallback when no rule picks an agent.
+func (lrs *LeadRoutingService) SetStrategy(strategy string) {
+	now := time.Now()
+	switch strategy {
+	case models.LeadRoutingWeighted:
+		lrs.routingRules = []RoutingRule{{
+			ID:        "weighted",
+			Name:      "Route by Agent Weight",
+			Priority:  100,
+			Action:    RoutingAction{Type: "weighted", FallbackType: "round_robin"},
+			Enabled:   true,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}}
+	case models.LeadRoutingTerritory:
+		lrs.routingRules = []RoutingRule{{
+			ID:        "territory",
+			Name:      "Route by Territory",
+			Priority:  100,
+			Action:    RoutingAction{Type: "geographic_match", FallbackType: "round_robin"},
+			Enabled:   true,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}}
+	default:
+		lrs.routingRules = []RoutingRule{}
+	}
+}
+
 // EnableRouting manually enables the routing system
 func (lrs *LeadRoutingService) EnableRouting() {
 	lrs.enabled = true
@@ -325,7 +380,17 @@ func (lrs *LeadRoutingService) initializeDefaultRoutingRules() {
 }
 
 func (lrs *LeadRoutingService) routeToDefaultAgent(request LeadRoutingRequest) *LeadRoutingResult {
-	defaultAgent := lrs.agents["agent_1"]
+	var defaultAgent *Agent
+	for _, agent := range lrs.sortedAgents() {
+		if agent.Active {
+			defaultAgent = agent
+			break
+		}
+	}
+	if defaultAgent == nil {
+		return nil
+	}
+	lrs.updateAgentWorkload(defaultAgent.ID, request)
 
 	return &LeadRoutingResult{
 		AssignedAgentID:   defaultAgent.ID,
@@ -424,6 +489,8 @@ func (lrs *LeadRoutingService) executeRoutingAction(action RoutingAction, reques
 		return lrs.skillBasedRouting(request, action.Parameters)
 	case "geographic_match":
 		return lrs.geographicRouting(request, action.Parameters)
+	case "weighted":
+		return lrs.weightedRouting(request)
 	case "round_robin":
 		return lrs.roundRobinRouting(request)
 	default:
@@ -478,29 +545,63 @@ func (lrs *LeadRoutingService) skillBasedRouting(request LeadRoutingRequest, par
 }
 
 func (lrs *LeadRoutingService) geographicRouting(request LeadRoutingRequest, params map[string]interface{}) (*Agent, float32, string) {
-	if request.ZipCode == "" {
+	if request.ZipCode == "" && request.Location == "" {
 		return nil, 0, "No location information for geographic routing"
 	}
 
-	for _, agent := range lrs.agents {
-		if !agent.Active || !lrs.isAgentAvailable(agent) {
+	for _, area := range []string{request.ZipCode, request.Location} {
+		if area == "" {
 			continue
 		}
+		for _, agent := range lrs.sortedAgents() {
+			if !agent.Active || !lrs.isAgentAvailable(agent) {
+				continue
+			}
 
-		if lrs.agentCoversArea(agent, request.ZipCode) {
-			return agent, 0.8, fmt.Sprintf("Geographic routing (area: %s)", request.ZipCode)
+			if lrs.agentCoversArea(agent, area) {
+				return agent, 0.8, fmt.Sprintf("Geographic routing (area: %s)", area)
+			}
 		}
 	}
 
 	return nil, 0, "No agents cover the requested geographic area"
 }
 
+// weightedRouting picks the agent with the fewest open leads for their weight,
+// so an agent weighted 2 carries twice the leads of one weighted 1
+func (lrs *LeadRoutingService) weightedRouting(request LeadRoutingRequest) (*Agent, float32, string) {
+	var bestAgent *Agent
+	var bestLoad float64
+
+	for _, agent := range lrs.sortedAgents() {
+		if !agent.Active || !lrs.isAgentAvailable(agent) {
+			continue
+		}
+
+		weight := agent.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		load := float64(agent.CurrentWorkload) / float64(weight)
+		if bestAgent == nil || load < bestLoad {
+			bestAgent = agent
+			bestLoad = load
+		}
+	}
+
+	if bestAgent != nil {
+		return bestAgent, 0.75, fmt.Sprintf("Weighted routing (weight: %d, workload: %d)", bestAgent.Weight, bestAgent.CurrentWorkload)
+	}
+
+	return nil, 0, "No available agents"
+}
+
 func (lrs *LeadRoutingService) roundRobinRouting(request LeadRoutingRequest) (*Agent, float32, string) {
-	// Find agent with lowest current workload
+	// Find agent with lowest current workload, longest since last assigned on ties
 	var bestAgent *Agent
 	lowestWorkload := 9999
 
-	for _, agent := range lrs.agents {
+	for _, agent := range lrs.sortedAgents() {
 		if !agent.Active || !lrs.isAgentAvailable(agent) {
 			continue
 		}
@@ -539,6 +640,7 @@ func (lrs *LeadRoutingService) updateAgentWorkload(agentID string, request LeadR
 
 	if agent, exists := lrs.agents[agentID]; exists {
 		agent.CurrentWorkload++
+		agent.LastAssignedAt = time.Now()
 		agent.UpdatedAt = time.Now()
 	}
 
@@ -547,9 +649,24 @@ func (lrs *LeadRoutingService) updateAgentWorkload(agentID string, request LeadR
 
 // Helper methods
 
+// sortedAgents returns agents longest since last assigned first, by ID on ties
+func (lrs *LeadRoutingService) sortedAgents() []*Agent {
+	agents := make([]*Agent, 0, len(lrs.agents))
+	for _, agent := range lrs.agents {
+		agents = append(agents, agent)
+	}
+	sort.Slice(agents, func(i, j int) bool {
+		if !agents[i].LastAssignedAt.Equal(agents[j].LastAssignedAt) {
+			return agents[i].LastAssignedAt.Before(agents[j].LastAssignedAt)
+		}
+		return agents[i].ID < agents[j].ID
+	})
+	return agents
+}
+
 func (lrs *LeadRoutingService) isAgentAvailable(agent *Agent) bool {
-	// Check if agent is under their daily lead limit
-	if workload, exists := lrs.workloadTracker.AgentWorkloads[agent.ID]; exists {
+	// Check if agent is under their daily lead limit (0 means no limit)
+	if workload, exists := lrs.workloadTracker.AgentWorkloads[agent.ID]; exists && agent.MaxDailyLeads > 0 {
 		if workload.LeadsToday >= agent.MaxDailyLeads {
 			return false
 		}
@@ -577,7 +694,7 @@ func (lrs *LeadRoutingService) agentHasSkills(agent *Agent, requiredSkills []str
 
 func (lrs *LeadRoutingService) agentCoversArea(agent *Agent, zipCode string) bool {
 	for _, area := range agent.GeographicAreas {
-		if area == zipCode {
+		if strings.EqualFold(area, zipCode) {
 			return true
 		}
 	}
@@ -1,8 +1,10 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,6 +12,17 @@ import (
 	"gorm.io/gorm"
 )
 
+// leadSafetyComplaintWindow is how long a complaint about a campaign email
+// blocks further contact
+const leadSafetyComplaintWindow = 90 * 24 * time.Hour
+
+// fubDoNotContactStatuses are FUB statuses and stages (lowercased) meaning the
+// contact must not be reached
+var fubDoNotContactStatuses = []string{"do not contact", "do_not_contact", "dnc"}
+
+// ErrLeadContactBlocked is returned when a lead fails the contact safety check
+var ErrLeadContactBlocked = errors.New("lead failed the contact safety check")
+
 // LeadSafetyFilter checks if automation actions are safe for a given lead
 // This prevents edge cases like spamming closing-stage leads, recommending rejected properties, etc.
 type LeadSafetyFilter struct {
@@ -25,6 +38,11 @@ func NewLeadSafetyFilter(db *gorm.DB, fubClient *BehavioralFUBAPIClient) *LeadSa
 	}
 }
 
+// SetFUBClient lets stage detection fall back to FUB when created before the FUB client
+func (lsf *LeadSafetyFilter) SetFUBClient(fubClient *BehavioralFUBAPIClient) {
+	lsf.fubClient = fubClient
+}
+
 // SafetyClassification represents the safety assessment of a lead for automation
 type SafetyClassification struct {
 	Safe          bool     `json:"safe"`           // Overall safety - true if action is safe
@@ -46,6 +64,7 @@ type LeadOverride struct {
 	ID              uint       `gorm:"primaryKey" json:"id"`
 	LeadID          string     `gorm:"uniqueIndex" json:"lead_id"`
 	DoNotContact    bool       `gorm:"default:false" json:"do_not_contact"`
+	Litigation      bool       `gorm:"default:false" json:"litigation"` // Lead is in a legal dispute; never contact
 	PauseUntil      *time.Time `json:"pause_until,omitempty"`
 	CustomCooldown  *int       `json:"custom_cooldown,omitempty"` // Custom cooldown in hours
 	Reason          string     `json:"reason"`
@@ -65,6 +84,17 @@ type LeadRejection struct {
 	RejectedBy string    `json:"rejected_by"` // Agent who recorded the rejection
 }
 
+// LeadSafetyExclusion records a lead left out of a campaign or FUB push
+// because it failed the contact safety check
+type LeadSafetyExclusion struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	LeadID    string    `gorm:"index" json:"lead_id"`
+	Channel   string    `json:"channel"` // "campaign", "fub_push"
+	Context   string    `json:"context"` // Campaign name or FUB trigger ID
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
 // LeadStageHistory tracks lead stage changes for detecting closing stage
 type LeadStageHistory struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
@@ -108,7 +138,21 @@ func (lsf *LeadSafetyFilter) ClassifyLead(leadData map[string]interface{}) Safet
 		}
 	}
 
-	// Check 3: Lead stage (detect closing stage)
+	// Check 3: Litigation flag, FUB Do Not Contact status and recent complaints
+	blocks, err := lsf.ContactBlocks([]string{leadID})
+	if err != nil {
+		classification.Safe = false
+		classification.Reasons = append(classification.Reasons, fmt.Sprintf("Safety check failed: %v", err))
+		return classification
+	}
+	if reason, blocked := blocks[leadID]; blocked {
+		classification.Safe = false
+		classification.DoNotContact = strings.Contains(reason, "Do Not Contact")
+		classification.Reasons = append(classification.Reasons, reason)
+		return classification
+	}
+
+	// Check 4: Lead stage (detect closing stage)
 	stage := lsf.detectLeadStage(leadID)
 	classification.Stage = stage
 	if stage == "closing" || stage == "signed" {
@@ -117,7 +161,7 @@ func (lsf *LeadSafetyFilter) ClassifyLead(leadData map[string]interface{}) Safet
 			"Lead is in closing stage - avoid property recommendations")
 	}
 
-	// Check 4: Application status (from PropertyHub or FUB)
+	// Check 5: Application status (from PropertyHub or FUB)
 	appStatus := lsf.checkApplicationStatus(leadID)
 	if appStatus == "approved" || appStatus == "lease_sent" {
 		classification.ClosingRisk = true
@@ -125,7 +169,7 @@ func (lsf *LeadSafetyFilter) ClassifyLead(leadData map[string]interface{}) Safet
 			fmt.Sprintf("Application status: %s - lead is closing", appStatus))
 	}
 
-	// Check 5: Recent agent contact (don't interfere with active conversations)
+	// Check 6: Recent agent contact (don't interfere with active conversations)
 	if recentContact := lsf.checkRecentAgentContact(leadID); recentContact {
 		classification.Warnings = append(classification.Warnings, 
 			"Agent contacted lead recently - consider delaying automation")
@@ -379,5 +423,169 @@ func (lsf *LeadSafetyFilter) AutoMigrate() error {
 		&LeadOverride{},
 		&LeadRejection{},
 		&LeadStageHistory{},
+		&LeadSafetyExclusion{},
 	)
 }
+
+// ContactBlocks returns why each of the leads (FUB lead or contact IDs) must
+// not be contacted: Do Not Contact or litigation flags, an active pause, a
+// Do Not Contact status in FUB, or a recent complaint. Safe leads are absent.
+func (lsf *LeadSafetyFilter) ContactBlocks(leadIDs []string) (map[string]string, error) {
+	blocks := make(map[string]string)
+	if len(leadIDs) == 0 {
+		return blocks, nil
+	}
+	block := func(leadID, reason string) {
+		if _, exists := blocks[leadID]; !exists {
+			blocks[leadID] = reason
+		}
+	}
+
+	var overrides []LeadOverride
+	if err := lsf.db.Where("lead_id IN ?", leadIDs).Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to load lead overrides: %w", err)
+	}
+	now := time.Now()
+	for _, override := range overrides {
+		switch {
+		case override.DoNotContact:
+			block(override.LeadID, "Lead marked Do Not Contact")
+		case override.Litigation:
+			block(override.LeadID, "Lead has a litigation flag")
+		case override.PauseUntil != nil && now.Before(*override.PauseUntil):
+			block(override.LeadID, fmt.Sprintf("Lead paused until %s", override.PauseUntil.Format("Jan 2, 3:04 PM")))
+		}
+	}
+
+	var fubLeads []struct {
+		FUBLeadID string
+		Status    string
+		Stage     string
+	}
+	if err := lsf.db.Table("fub_leads").Select("fub_lead_id, status, stage").
+		Where("fub_lead_id IN ?", leadIDs).
+		Where("LOWER(status) IN ? OR LOWER(stage) IN ?", fubDoNotContactStatuses, fubDoNotContactStatuses).
+		Scan(&fubLeads).Error; err != nil {
+		return nil, fmt.Errorf("failed to load FUB lead statuses: %w", err)
+	}
+	for _, fubLead := range fubLeads {
+		block(fubLead.FUBLeadID, "Lead is Do Not Contact in FUB")
+	}
+
+	var complaints []string
+	if err := lsf.db.Table("campaign_executions").
+		Joins("JOIN lead_reengagements ON lead_reengagements.id = campaign_executions.lead_reengagement_id").
+		Where("lead_reengagements.fub_contact_id IN ?", leadIDs).
+		Where("campaign_executions.response_type = ? AND campaign_executions.updated_at >= ?", "complaint", now.Add(-leadSafetyComplaintWindow)).
+		Distinct().Pluck("lead_reengagements.fub_contact_id", &complaints).Error; err != nil {
+		return nil, fmt.Errorf("failed to load lead complaints: %w", err)
+	}
+	for _, leadID := range complaints {
+		block(leadID, "Lead complained about a campaign email in the last 90 days")
+	}
+
+	return blocks, nil
+}
+
+// ContactBlockForEmail checks the FUB leads with the email, returning the
+// blocked lead and why the contact must not be reached, or "" if it may be
+func (lsf *LeadSafetyFilter) ContactBlockForEmail(email string) (string, string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return "", "", nil
+	}
+
+	var leadIDs []string
+	if err := lsf.db.Table("fub_leads").Where("LOWER(email) = ?", email).Pluck("fub_lead_id", &leadIDs).Error; err != nil {
+		return "", "", fmt.Errorf("failed to look up FUB leads: %w", err)
+	}
+	var localIDs []string
+	if err := lsf.db.Model(&models.Lead{}).Where("LOWER(email) = ? AND fub_lead_id <> ''", email).Pluck("fub_lead_id", &localIDs).Error; err != nil {
+		return "", "", fmt.Errorf("failed to look up leads: %w", err)
+	}
+	leadIDs = append(leadIDs, localIDs...)
+
+	blocks, err := lsf.ContactBlocks(leadIDs)
+	if err != nil {
+		return "", "", err
+	}
+	for _, leadID := range leadIDs {
+		if reason, blocked := blocks[leadID]; blocked {
+			return leadID, reason, nil
+		}
+	}
+	return "", "", nil
+}
+
+// SafetyKeyForLead returns the ID the safety filter knows a lead by: its FUB
+// lead ID, else the FUB lead with its email, else its own ID
+func (lsf *LeadSafetyFilter) SafetyKeyForLead(lead *models.Lead) string {
+	if lead.FUBLeadID != "" {
+		return lead.FUBLeadID
+	}
+	var leadIDs []string
+	lsf.db.Table("fub_leads").Where("LOWER(email) = ?", strings.ToLower(lead.Email)).Limit(1).Pluck("fub_lead_id", &leadIDs)
+	if len(leadIDs) > 0 && leadIDs[0] != "" {
+		return leadIDs[0]
+	}
+	return strconv.FormatUint(uint64(lead.ID), 10)
+}
+
+// ExcludeUnsafeCampaignLeads narrows an eligible LeadReengagement query to the
+// leads that pass the contact safety check, recording why each other lead was
+// excluded from the campaign. It returns how many leads were excluded.
+func (lsf *LeadSafetyFilter) ExcludeUnsafeCampaignLeads(campaignName string, eligible *gorm.DB) (*gorm.DB, int64, error) {
+	base := eligible.Session(&gorm.Session{})
+
+	var leads []struct {
+		ID           uint
+		FUBContactID string
+	}
+	if err := base.Session(&gorm.Session{}).Select("id", "fub_contact_id").Scan(&leads).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to load campaign leads: %w", err)
+	}
+	contactIDs := make([]string, 0, len(leads))
+	for _, lead := range leads {
+		contactIDs = append(contactIDs, lead.FUBContactID)
+	}
+	blocks, err := lsf.ContactBlocks(contactIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var excluded []uint
+	for _, lead := range leads {
+		if reason, blocked := blocks[lead.FUBContactID]; blocked {
+			excluded = append(excluded, lead.ID)
+			lsf.RecordExclusion(lead.FUBContactID, "campaign", campaignName, reason)
+		}
+	}
+	if len(excluded) == 0 {
+		return base, 0, nil
+	}
+	log.Printf("🛡️ Excluded %d leads failing safety checks from campaign %q", len(excluded), campaignName)
+	return base.Where("id NOT IN ?", excluded).Session(&gorm.Session{}), int64(len(excluded)), nil
+}
+
+// RecordExclusion records a lead left out of a campaign or FUB push and why
+func (lsf *LeadSafetyFilter) RecordExclusion(leadID, channel, context, reason string) {
+	exclusion := LeadSafetyExclusion{
+		LeadID:  leadID,
+		Channel: channel,
+		Context: context,
+		Reason:  reason,
+	}
+	if err := lsf.db.Create(&exclusion).Error; err != nil {
+		log.Printf("❌ Error recording safety exclusion for lead %s: %v", leadID, err)
+	}
+}
+
+// GetLeadExclusions returns the lead's most recent safety exclusions
+func (lsf *LeadSafetyFilter) GetLeadExclusions(leadID string, limit int) ([]LeadSafetyExclusion, error) {
+	var exclusions []LeadSafetyExclusion
+	err := lsf.db.Where("lead_id = ?", leadID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&exclusions).Error
+	return exclusions, err
+}
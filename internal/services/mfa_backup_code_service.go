@@ -0,0 +1,93 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/gorm"
+)
+
+// mfaBackupCodeConsumeAttempts bounds retries when concurrent requests race to consume codes
+const mfaBackupCodeConsumeAttempts = 3
+
+// MFABackupCodeService issues and verifies single-use MFA recovery codes for admin
+// users. Only code hashes are stored, on the AdminUser record.
+type MFABackupCodeService struct {
+	db *gorm.DB
+}
+
+// NewMFABackupCodeService creates a new MFA backup code service
+func NewMFABackupCodeService(db *gorm.DB) *MFABackupCodeService {
+	return &MFABackupCodeService{db: db}
+}
+
+// IssueBackupCodes replaces an admin's backup codes and returns the new plaintext
+// codes, which cannot be retrieved again
+func (s *MFABackupCodeService) IssueBackupCodes(adminID string) ([]string, error) {
+	codes, hashes, err := security.GenerateMFABackupCodes(security.MFABackupCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.db.Model(&models.AdminUser{}).Where("id = ?", adminID).Updates(map[string]interface{}{
+		"mfa_backup_codes":              strings.Join(hashes, " "),
+		"mfa_backup_codes_generated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to store backup codes: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return codes, nil
+}
+
+// ConsumeBackupCode reports whether the code is one of the admin's unused backup
+// codes, removing it so it cannot be used again
+func (s *MFABackupCodeService) ConsumeBackupCode(adminID, code string) (bool, error) {
+	for attempt := 0; attempt < mfaBackupCodeConsumeAttempts; attempt++ {
+		var admin models.AdminUser
+		if err := s.db.Select("id", "mfa_backup_codes").First(&admin, "id = ?", adminID).Error; err != nil {
+			return false, err
+		}
+
+		hashes := strings.Fields(admin.MFABackupCodes)
+		match := security.MatchMFABackupCode(hashes, code)
+		if match < 0 {
+			return false, nil
+		}
+		remaining := append(hashes[:match:match], hashes[match+1:]...)
+
+		// Only succeed if the stored codes are unchanged, so two requests
+		// cannot both consume the same code
+		result := s.db.Model(&models.AdminUser{}).
+			Where("id = ? AND mfa_backup_codes = ?", adminID, admin.MFABackupCodes).
+			Update("mfa_backup_codes", strings.Join(remaining, " "))
+		if result.Error != nil {
+			return false, result.Error
+		}
+		if result.RowsAffected == 1 {
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("backup codes changed concurrently, please retry")
+}
+
+// RemainingBackupCodes returns how many unused backup codes an admin has
+func (s *MFABackupCodeService) RemainingBackupCodes(adminID string) (int, error) {
+	var admin models.AdminUser
+	if err := s.db.Select("id", "mfa_backup_codes").First(&admin, "id = ?", adminID).Error; err != nil {
+		return 0, err
+	}
+	return len(strings.Fields(admin.MFABackupCodes)), nil
+}
+
+// ClearBackupCodes invalidates all of an admin's backup codes
+func (s *MFABackupCodeService) ClearBackupCodes(adminID string) error {
+	return s.db.Model(&models.AdminUser{}).Where("id = ?", adminID).
+		Update("mfa_backup_codes", "").Error
+}
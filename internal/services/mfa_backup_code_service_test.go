@@ -0,0 +1,72 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newAdminUsersTestDB opens an in-memory database with the admin_users table.
+// admin_users uses a postgres uuid default, so it is created by hand for sqlite.
+func newAdminUsersTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	err = db.Exec(`CREATE TABLE admin_users (
+		id TEXT PRIMARY KEY, username TEXT, email TEXT, password_hash TEXT, role TEXT, active BOOLEAN,
+		last_login DATETIME, login_count INTEGER, created_at DATETIME, updated_at DATETIME,
+		mfa_backup_codes TEXT, mfa_backup_codes_generated_at DATETIME, mfa_totp_secret TEXT, mfa_totp_enabled_at DATETIME)`).Error
+	if err != nil {
+		t.Fatalf("Failed to create admin_users table: %v", err)
+	}
+	return db
+}
+
+func TestMFABackupCodes_SingleUseAndStoredHashed(t *testing.T) {
+	db := newAdminUsersTestDB(t)
+
+	admin := models.AdminUser{ID: "admin-1", Username: "agent", Email: "agent@example.com", PasswordHash: "x"}
+	if err := db.Create(&admin).Error; err != nil {
+		t.Fatalf("Failed to create admin: %v", err)
+	}
+
+	service := NewMFABackupCodeService(db)
+	codes, err := service.IssueBackupCodes(admin.ID)
+	if err != nil || len(codes) != 10 {
+		t.Fatalf("Expected 10 backup codes, got %d (%v)", len(codes), err)
+	}
+
+	var stored models.AdminUser
+	db.First(&stored, "id = ?", admin.ID)
+	for _, code := range codes {
+		if strings.Contains(stored.MFABackupCodes, code) {
+			t.Fatalf("Backup code %s stored in plaintext", code)
+		}
+	}
+
+	// Codes are accepted regardless of case and dashes, but only once
+	if ok, err := service.ConsumeBackupCode(admin.ID, strings.ToLower(strings.ReplaceAll(codes[3], "-", ""))); err != nil || !ok {
+		t.Fatalf("Expected backup code to verify, got %v (%v)", ok, err)
+	}
+	if ok, _ := service.ConsumeBackupCode(admin.ID, codes[3]); ok {
+		t.Error("Expected a consumed backup code to be rejected")
+	}
+	if ok, _ := service.ConsumeBackupCode(admin.ID, "AAAAA-AAAAA"); ok {
+		t.Error("Expected an unknown backup code to be rejected")
+	}
+	if remaining, _ := service.RemainingBackupCodes(admin.ID); remaining != 9 {
+		t.Errorf("Expected 9 remaining codes, got %d", remaining)
+	}
+
+	// Regenerating invalidates the previous set
+	if _, err := service.IssueBackupCodes(admin.ID); err != nil {
+		t.Fatalf("Failed to regenerate backup codes: %v", err)
+	}
+	if ok, _ := service.ConsumeBackupCode(admin.ID, codes[0]); ok {
+		t.Error("Expected codes from a previous set to be rejected after regeneration")
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// MFA re-verification errors
+var (
+	ErrMFANotConfigured = errors.New("TOTP is not enabled for this admin")
+	ErrMFACodeInvalid   = errors.New("invalid MFA code")
+)
+
+// MFAReverificationService lets an admin confirm their second factor before a
+// sensitive operation. A confirmation is valid for a short window and is kept
+// in memory, so a restart asks for it again.
+//
+// Only a code from the admin's enrolled TOTP secret is accepted. Backup codes
+// are not: a session can ask for new ones, so they do not prove a second factor.
+type MFAReverificationService struct {
+	totp   *AdminTOTPService
+	window time.Duration
+	now    func() time.Time
+
+	mutex    sync.Mutex
+	verified map[string]time.Time // admin ID -> verified until
+}
+
+// NewMFAReverificationService creates an MFA re-verification service; window
+// defaults to five minutes
+func NewMFAReverificationService(adminTOTP *AdminTOTPService, window time.Duration) *MFAReverificationService {
+	if window <= 0 {
+		window = 5 * time.Minute
+	}
+	return &MFAReverificationService{
+		totp:     adminTOTP,
+		window:   window,
+		now:      time.Now,
+		verified: make(map[string]time.Time),
+	}
+}
+
+// Reverify checks a code from the admin's authenticator app and returns when
+// the re-verification expires
+func (s *MFAReverificationService) Reverify(adminID, code string) (time.Time, error) {
+	valid, err := s.totp.Verify(adminID, code)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !valid {
+		return time.Time{}, ErrMFACodeInvalid
+	}
+
+	until := s.now().Add(s.window)
+	s.mutex.Lock()
+	s.verified[adminID] = until
+	s.mutex.Unlock()
+	return until, nil
+}
+
+// Verified reports whether the admin re-verified within the window
+func (s *MFAReverificationService) Verified(adminID string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	until, ok := s.verified[adminID]
+	if ok && !s.now().Before(until) {
+		delete(s.verified, adminID)
+		return false
+	}
+	return ok
+}
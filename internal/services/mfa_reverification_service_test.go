@@ -0,0 +1,79 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"github.com/pquerna/otp/totp"
+)
+
+func TestMFAReverification_RequiresTOTPAndExpires(t *testing.T) {
+	db := newAdminUsersTestDB(t)
+	if err := db.AutoMigrate(&security.EncryptionKey{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	admin := &models.AdminUser{ID: "admin-1", Username: "agent", Email: "agent@example.com", PasswordHash: "x"}
+	db.Create(admin)
+	t.Setenv("ENCRYPTION_KEY", newTestEncryptionKey(t))
+	em, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create encryption manager: %v", err)
+	}
+
+	adminTOTP := NewAdminTOTPService(db, em)
+	reverification := NewMFAReverificationService(adminTOTP, 5*time.Minute)
+	if _, err := reverification.Reverify("admin-1", "123456"); !errors.Is(err, ErrMFANotConfigured) {
+		t.Fatalf("Expected re-verification to need TOTP enabled, got %v", err)
+	}
+
+	// Backup codes the session can issue are not a second factor
+	codes, _ := NewMFABackupCodeService(db).IssueBackupCodes("admin-1")
+	if _, err := reverification.Reverify("admin-1", codes[0]); !errors.Is(err, ErrMFANotConfigured) {
+		t.Fatalf("Expected a backup code to be rejected, got %v", err)
+	}
+
+	key, err := adminTOTP.Enroll(admin)
+	if err != nil {
+		t.Fatalf("Enroll failed: %v", err)
+	}
+	var stored models.AdminUser
+	db.First(&stored, "id = ?", "admin-1")
+	if stored.MFATOTPSecret == "" || string(stored.MFATOTPSecret) == key.Secret() {
+		t.Fatal("Expected the TOTP secret stored encrypted")
+	}
+	if _, err := reverification.Reverify("admin-1", "123456"); !errors.Is(err, ErrMFANotConfigured) {
+		t.Fatalf("Expected an unconfirmed enrollment not to count, got %v", err)
+	}
+
+	code, _ := totp.GenerateCode(key.Secret(), time.Now())
+	if err := adminTOTP.Confirm("admin-1", code); err != nil {
+		t.Fatalf("Confirm failed: %v", err)
+	}
+	if _, err := adminTOTP.Enroll(admin); !errors.Is(err, ErrMFAAlreadyEnabled) {
+		t.Fatalf("Expected a confirmed secret not to be replaced, got %v", err)
+	}
+
+	// The confirmation code cannot be replayed
+	if _, err := reverification.Reverify("admin-1", code); !errors.Is(err, ErrMFACodeInvalid) {
+		t.Fatalf("Expected a replayed code to be rejected, got %v", err)
+	}
+	if reverification.Verified("admin-1") {
+		t.Fatal("Expected no re-verification after a rejected code")
+	}
+
+	adminTOTP.lastUsed = make(map[string]adminTOTPUse)
+	if _, err := reverification.Reverify("admin-1", code); err != nil {
+		t.Fatalf("Reverify failed: %v", err)
+	}
+	if !reverification.Verified("admin-1") || reverification.Verified("admin-2") {
+		t.Error("Expected only admin-1 to be re-verified")
+	}
+
+	reverification.now = func() time.Time { return time.Now().Add(6 * time.Minute) }
+	if reverification.Verified("admin-1") {
+		t.Error("Expected the re-verification to expire")
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"strings"
+	"time"
+)
+
+// OptimalNextActionTime determines the best time for the next action on a
+// lead from its urgency and engagement (both 0-1), the property category it
+// is interested in and market conditions, moved into Central business hours
+func OptimalNextActionTime(now time.Time, urgencyScore, engagementScore float64, propertyCategory string, marketConditions map[string]interface{}) time.Time {
+	baseDelay := 24 * time.Hour
+
+	if urgencyScore >= 0.8 {
+		baseDelay = 15 * time.Minute
+	} else if urgencyScore >= 0.6 {
+		baseDelay = 2 * time.Hour
+	} else if urgencyScore >= 0.4 {
+		baseDelay = 8 * time.Hour
+	}
+
+	if engagementScore >= 0.8 {
+		baseDelay = time.Duration(float64(baseDelay) * 0.5)
+	} else if engagementScore >= 0.6 {
+		baseDelay = time.Duration(float64(baseDelay) * 0.7)
+	}
+
+	switch strings.ToLower(propertyCategory) {
+	case "rental":
+		baseDelay = time.Duration(float64(baseDelay) * 0.6)
+	case "investment":
+		baseDelay = time.Duration(float64(baseDelay) * 1.3)
+	case "commercial":
+		baseDelay = time.Duration(float64(baseDelay) * 2.0)
+	}
+
+	if marketConditions != nil {
+		if inventory, exists := marketConditions["inventory_level"]; exists {
+			if inventoryStr, ok := inventory.(string); ok {
+				if inventoryStr == "tight" {
+					baseDelay = time.Duration(float64(baseDelay) * 0.7)
+				} else if inventoryStr == "abundant" {
+					baseDelay = time.Duration(float64(baseDelay) * 1.2)
+				}
+			}
+		}
+	}
+
+	return AdjustToBusinessHours(now.Add(baseDelay))
+}
+
+// Business hours, in BusinessLocation, on weekdays
+const (
+	BusinessHoursStart = 9
+	BusinessHoursEnd   = 18
+)
+
+// BusinessLocation returns the time zone business hours are kept in (Central)
+func BusinessLocation() *time.Location {
+	central, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		return time.UTC
+	}
+	return central
+}
+
+// AdjustToBusinessHours moves a time outside 9am-6pm Central on a weekday to
+// 9am on the next business day
+func AdjustToBusinessHours(t time.Time) time.Time {
+	central := BusinessLocation()
+	adjustedTime := t.In(central)
+
+	hour := adjustedTime.Hour()
+	weekday := adjustedTime.Weekday()
+
+	if weekday == time.Saturday {
+		adjustedTime = adjustedTime.AddDate(0, 0, 2)
+		adjustedTime = time.Date(adjustedTime.Year(), adjustedTime.Month(), adjustedTime.Day(), BusinessHoursStart, 0, 0, 0, central)
+	} else if weekday == time.Sunday {
+		adjustedTime = adjustedTime.AddDate(0, 0, 1)
+		adjustedTime = time.Date(adjustedTime.Year(), adjustedTime.Month(), adjustedTime.Day(), BusinessHoursStart, 0, 0, 0, central)
+	} else {
+		if hour < BusinessHoursStart {
+			adjustedTime = time.Date(adjustedTime.Year(), adjustedTime.Month(), adjustedTime.Day(), BusinessHoursStart, 0, 0, 0, central)
+		} else if hour >= BusinessHoursEnd {
+			if weekday == time.Friday {
+				adjustedTime = adjustedTime.AddDate(0, 0, 3)
+			} else {
+				adjustedTime = adjustedTime.AddDate(0, 0, 1)
+			}
+			adjustedTime = time.Date(adjustedTime.Year(), adjustedTime.Month(), adjustedTime.Day(), BusinessHoursStart, 0, 0, 0, central)
+		}
+	}
+
+	return adjustedTime
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OutboundWebhookService delivers signed event payloads to configured external endpoints.
+// Each request carries an X-PropertyHub-Signature header (hex HMAC-SHA256 of the body)
+// so receivers can verify it came from us.
+type OutboundWebhookService struct {
+	endpoints   []string
+	secret      []byte
+	client      *http.Client
+	maxAttempts int
+}
+
+// OutboundWebhookPayload is the envelope posted to every endpoint
+type OutboundWebhookPayload struct {
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// NewOutboundWebhookService creates a webhook sender for the given endpoint URLs
+func NewOutboundWebhookService(endpoints []string, secret string) *OutboundWebhookService {
+	cleaned := []string{}
+	for _, endpoint := range endpoints {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			cleaned = append(cleaned, endpoint)
+		}
+	}
+
+	return &OutboundWebhookService{
+		endpoints:   cleaned,
+		secret:      []byte(secret),
+		client:      &http.Client{Timeout: 10 * time.Second},
+		maxAttempts: 3,
+	}
+}
+
+// IsConfigured reports whether any endpoints are configured
+func (ws *OutboundWebhookService) IsConfigured() bool {
+	return ws != nil && len(ws.endpoints) > 0
+}
+
+// Send delivers an event to every endpoint, returning the first delivery error
+func (ws *OutboundWebhookService) Send(event string, data interface{}) error {
+	if !ws.IsConfigured() {
+		return nil
+	}
+
+	body, err := json.Marshal(OutboundWebhookPayload{Event: event, Timestamp: time.Now(), Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	var firstErr error
+	for _, endpoint := range ws.endpoints {
+		if err := ws.deliver(endpoint, event, body); err != nil {
+			log.Printf("⚠️ Webhook %s delivery to %s failed: %v", event, endpoint, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// SendAsync delivers an event in the background
+func (ws *OutboundWebhookService) SendAsync(event string, data interface{}) {
+	if !ws.IsConfigured() {
+		return
+	}
+	go ws.Send(event, data)
+}
+
+func (ws *OutboundWebhookService) deliver(endpoint, event string, body []byte) error {
+	var lastErr error
+	for attempt := 0; attempt < ws.maxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-PropertyHub-Event", event)
+		req.Header.Set("X-PropertyHub-Signature", ws.sign(body))
+
+		resp, err := ws.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("HTTP %d", resp.StatusCode)
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+func (ws *OutboundWebhookService) sign(body []byte) string {
+	mac := hmac.New(sha256.New, ws.secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"runtime"
 	"runtime/debug"
+	"sort"
 	"sync"
 	"time"
 
@@ -53,8 +54,21 @@ type RequestMetrics struct {
 	ErrorCount      int64         `json:"error_count"`
 	LastAccessed    time.Time     `json:"last_accessed"`
 	StatusCodes     map[int]int64 `json:"status_codes"`
+
+	// Latency percentiles and throughput, computed on snapshot
+	FirstSeen         time.Time     `json:"first_seen"`
+	P50Duration       time.Duration `json:"p50_duration"`
+	P95Duration       time.Duration `json:"p95_duration"`
+	P99Duration       time.Duration `json:"p99_duration"`
+	RequestsPerMinute float64       `json:"requests_per_minute"`
+
+	samples     []time.Duration
+	sampleIndex int
 }
 
+// maxLatencySamples bounds the per-route ring of recent durations used for percentiles
+const maxLatencySamples = 500
+
 // SystemMetrics tracks system resource usage
 type SystemMetrics struct {
 	Timestamp           time.Time     `json:"timestamp"`
@@ -165,6 +179,7 @@ func (p *PerformanceMonitoringService) TrackRequest(method, path string, duratio
 			MinDuration: duration,
 			MaxDuration: duration,
 			StatusCodes: make(map[int]int64),
+			FirstSeen:   time.Now(),
 		}
 		p.requestMetrics[key] = metric
 	}
@@ -187,6 +202,60 @@ func (p *PerformanceMonitoringService) TrackRequest(method, path string, duratio
 	}
 
 	metric.StatusCodes[statusCode]++
+
+	if len(metric.samples) < maxLatencySamples {
+		metric.samples = append(metric.samples, duration)
+	} else {
+		metric.samples[metric.sampleIndex] = duration
+		metric.sampleIndex = (metric.sampleIndex + 1) % maxLatencySamples
+	}
+}
+
+// GetRequestMetrics returns a copy of per-route metrics with latency percentiles
+// and throughput calculated from recent samples
+func (p *PerformanceMonitoringService) GetRequestMetrics() map[string]RequestMetrics {
+	p.requestMutex.RLock()
+	defer p.requestMutex.RUnlock()
+
+	now := time.Now()
+	snapshot := make(map[string]RequestMetrics, len(p.requestMetrics))
+	for key, metric := range p.requestMetrics {
+		copied := *metric
+		copied.StatusCodes = make(map[int]int64, len(metric.StatusCodes))
+		for code, count := range metric.StatusCodes {
+			copied.StatusCodes[code] = count
+		}
+
+		sorted := append([]time.Duration(nil), metric.samples...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		copied.P50Duration = durationPercentile(sorted, 0.50)
+		copied.P95Duration = durationPercentile(sorted, 0.95)
+		copied.P99Duration = durationPercentile(sorted, 0.99)
+
+		if elapsed := now.Sub(metric.FirstSeen).Minutes(); elapsed > 0 {
+			copied.RequestsPerMinute = float64(metric.Count) / elapsed
+		}
+
+		copied.samples = nil
+		snapshot[key] = copied
+	}
+
+	return snapshot
+}
+
+// durationPercentile returns the nearest-rank percentile of an ascending slice
+func durationPercentile(sorted []time.Duration, percentile float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted))*percentile+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
 }
 
 // collectMetrics periodically collects metrics from all services
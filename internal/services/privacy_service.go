@@ -0,0 +1,416 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/gorm"
+)
+
+// privacyScanBatch is how many rows are decrypted at a time when searching
+// tables that have no email blind index
+const privacyScanBatch = 500
+
+// ErrInvalidPrivacyRequest is returned for a request without an email or
+// without the subject's identity verified
+var ErrInvalidPrivacyRequest = errors.New("invalid privacy request")
+
+// PrivacySubjectRequest identifies the person a privacy request is for
+type PrivacySubjectRequest struct {
+	Email              string
+	VerificationMethod string // How the person's identity was verified, e.g. id_document, email_confirmation
+	RequestedBy        *models.AdminUser
+}
+
+// PrivacyExport is every record held about a data subject, PII decrypted
+type PrivacyExport struct {
+	RequestID   uint                   `json:"request_id"`
+	Subject     string                 `json:"subject"`
+	GeneratedAt time.Time              `json:"generated_at"`
+	Counts      map[string]int         `json:"counts"`
+	Records     map[string]interface{} `json:"records"`
+}
+
+// PrivacyErasure reports what an erasure request anonymized and deleted
+type PrivacyErasure struct {
+	RequestID   uint           `json:"request_id"`
+	Anonymized  map[string]int `json:"anonymized"`
+	Deleted     map[string]int `json:"deleted"`
+	CompletedAt time.Time      `json:"completed_at"`
+}
+
+// PrivacyService fulfills data subject requests: exporting everything held
+// about a person, or erasing it. Erasure follows the retention rules: rows
+// other records depend on are anonymized, the rest are deleted. Unsubscribe and
+// do-not-contact entries are kept so the person is never contacted again.
+type PrivacyService struct {
+	db                *gorm.DB
+	encryptionManager *security.EncryptionManager
+	retention         *DataRetentionService
+}
+
+// NewPrivacyService creates a privacy service. Leads are anonymized through
+// the retention service so erasures land in the same audit trail.
+func NewPrivacyService(db *gorm.DB, encryptionManager *security.EncryptionManager, retention *DataRetentionService) *PrivacyService {
+	return &PrivacyService{db: db, encryptionManager: encryptionManager, retention: retention}
+}
+
+// privacySubject is the records found for one email that need decrypting or
+// link to further records
+type privacySubject struct {
+	email         string
+	leads         []models.Lead
+	reengagements []models.LeadReengagement
+	contacts      []models.Contact
+	bookings      []models.Booking
+	closings      []models.ClosingPipeline
+}
+
+func (p *privacySubject) leadIDs() []uint {
+	ids := make([]uint, len(p.leads))
+	for i, lead := range p.leads {
+		ids[i] = lead.ID
+	}
+	return ids
+}
+
+func (p *privacySubject) fubLeadIDs() []string {
+	var ids []string
+	for _, lead := range p.leads {
+		if lead.FUBLeadID != "" {
+			ids = append(ids, lead.FUBLeadID)
+		}
+	}
+	return ids
+}
+
+func (p *privacySubject) reengagementIDs() []uint {
+	ids := make([]uint, len(p.reengagements))
+	for i, lead := range p.reengagements {
+		ids[i] = lead.ID
+	}
+	return ids
+}
+
+func (p *privacySubject) contactIDs() []uint {
+	ids := make([]uint, len(p.contacts))
+	for i, contact := range p.contacts {
+		ids[i] = contact.ID
+	}
+	return ids
+}
+
+func (p *privacySubject) bookingIDs() []uint {
+	ids := make([]uint, len(p.bookings))
+	for i, booking := range p.bookings {
+		ids[i] = booking.ID
+	}
+	return ids
+}
+
+func (p *privacySubject) closingIDs() []uint {
+	ids := make([]uint, len(p.closings))
+	for i, closing := range p.closings {
+		ids[i] = closing.ID
+	}
+	return ids
+}
+
+// Export gathers every record held about the subject across leads, contacts,
+// bookings, behavioral history, emails, applications and campaigns
+func (s *PrivacyService) Export(req PrivacySubjectRequest) (*PrivacyExport, error) {
+	request, subject, err := s.open(models.PrivacyRequestExport, req)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &PrivacyExport{
+		RequestID:   request.ID,
+		Subject:     subject.email,
+		GeneratedAt: time.Now(),
+		Counts:      make(map[string]int),
+		Records:     make(map[string]interface{}),
+	}
+	add := func(section string, records interface{}) {
+		export.Records[section] = records
+		export.Counts[section] = reflect.ValueOf(records).Len()
+	}
+
+	add("leads", subject.leads)
+	add("lead_reengagements", models.DecryptLeadReengagementList(subject.reengagements, s.encryptionManager))
+	add("contacts", models.ToContactDataResponseList(subject.contacts, s.encryptionManager))
+	add("bookings", models.ToBookingDataResponseList(subject.bookings, s.encryptionManager))
+	add("closings", models.ToClosingPipelineDataResponseList(subject.closings, s.encryptionManager))
+
+	var (
+		events        []models.BehavioralEvent
+		sessions      []models.BehavioralSession
+		campaigns     []map[string]interface{}
+		applications  []models.ApplicationApplicant
+		recoveries    []models.ApplicationRecovery
+		approvals     []models.Approval
+		emails        []models.EmailEvent
+		savedProps    []map[string]interface{}
+		savedSearches []models.SavedSearch
+		fubPushes     []models.ContextFUBPush
+	)
+	queries := []struct {
+		section string
+		records interface{}
+		query   *gorm.DB
+	}{
+		{"behavioral_events", &events, s.db.Where("lead_id IN ?", subject.leadIDs()).Order("created_at")},
+		{"behavioral_sessions", &sessions, s.db.Where("lead_id IN ?", subject.leadIDs()).Order("start_time")},
+		{"campaign_executions", &campaigns, s.db.Table("campaign_executions").
+			Where("lead_reengagement_id IN ? AND deleted_at IS NULL", subject.reengagementIDs()).Order("scheduled_for")},
+		{"applications", &applications, s.db.Where("LOWER(applicant_email) = ?", subject.email)},
+		{"application_recoveries", &recoveries, s.db.Where("LOWER(applicant_email) = ?", subject.email)},
+		{"approvals", &approvals, s.db.Where("fub_lead_id IN ?", subject.fubLeadIDs())},
+		{"emails", &emails, s.db.Where("LOWER(to_email) = ?", subject.email).Order("sent_at")},
+		{"saved_properties", &savedProps, s.db.Table("saved_properties").Where("LOWER(email) = ?", subject.email)},
+		{"saved_searches", &savedSearches, s.db.Where("LOWER(owner_email) = ?", subject.email)},
+		{"fub_pushes", &fubPushes, s.db.Where("LOWER(email) = ?", subject.email)},
+	}
+	for _, q := range queries {
+		if err := q.query.Find(q.records).Error; err != nil {
+			err = fmt.Errorf("failed to load %s: %w", q.section, err)
+			s.finish(request, nil, err)
+			return nil, err
+		}
+		add(q.section, reflect.ValueOf(q.records).Elem().Interface())
+	}
+
+	counts := make(models.JSONB, len(export.Counts))
+	for section, count := range export.Counts {
+		counts[section] = count
+	}
+	s.finish(request, counts, nil)
+	return export, nil
+}
+
+// Erase anonymizes or deletes every record held about the subject
+func (s *PrivacyService) Erase(req PrivacySubjectRequest) (*PrivacyErasure, error) {
+	request, subject, err := s.open(models.PrivacyRequestErasure, req)
+	if err != nil {
+		return nil, err
+	}
+
+	erasure := &PrivacyErasure{
+		RequestID:  request.ID,
+		Anonymized: make(map[string]int),
+		Deleted:    make(map[string]int),
+	}
+
+	runID := fmt.Sprintf("privacy-request-%d", request.ID)
+	for _, lead := range subject.leads {
+		if err := s.retention.AnonymizeLead(runID, lead.ID, fmt.Sprintf("erasure request %d", request.ID)); err != nil {
+			err = fmt.Errorf("failed to anonymize lead %d: %w", lead.ID, err)
+			s.finish(request, nil, err)
+			return nil, err
+		}
+		erasure.Anonymized["leads"]++
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		steps := []struct {
+			section   string
+			anonymize bool
+			result    func() *gorm.DB
+		}{
+			// Campaign executions still reference these rows
+			{"lead_reengagements", true, func() *gorm.DB {
+				return tx.Model(&models.LeadReengagement{}).Where("id IN ?", subject.reengagementIDs()).Updates(map[string]interface{}{
+					"email": "", "phone": "", "first_name": "", "last_name": "", "notes": "",
+					"email_hash": "", "phone_hash": "", "search_index": "", "has_email": false,
+				})
+			}},
+			{"contacts", false, func() *gorm.DB {
+				return tx.Where("id IN ?", subject.contactIDs()).Delete(&models.Contact{})
+			}},
+			// Bookings keep their status history and FUB link
+			{"bookings", true, func() *gorm.DB {
+				return tx.Model(&models.Booking{}).Where("id IN ?", subject.bookingIDs()).
+					Updates(map[string]interface{}{"name": "", "email": "", "phone": "", "notes": "", "special_requests": ""})
+			}},
+			// Closings feed commission reporting
+			{"closings", true, func() *gorm.DB {
+				return tx.Model(&models.ClosingPipeline{}).Where("id IN ?", subject.closingIDs()).
+					Updates(map[string]interface{}{"tenant_name": "", "tenant_email": "", "tenant_phone": ""})
+			}},
+			// Applicants belong to an application number shared with co-applicants
+			{"applications", true, func() *gorm.DB {
+				return tx.Model(&models.ApplicationApplicant{}).Where("LOWER(applicant_email) = ?", subject.email).
+					Updates(map[string]interface{}{
+						"applicant_name": "Erased", "applicant_email": "erased@invalid", "applicant_phone": "",
+						"source_email": "", "application_data": models.JSONB{}, "applicant_notes": "",
+						"rental_history": "", "employment_status": "",
+					})
+			}},
+			{"application_recoveries", false, func() *gorm.DB {
+				return tx.Where("LOWER(applicant_email) = ?", subject.email).Delete(&models.ApplicationRecovery{})
+			}},
+			{"approvals", true, func() *gorm.DB {
+				return tx.Model(&models.Approval{}).Where("fub_lead_id IN ?", subject.fubLeadIDs()).
+					Updates(map[string]interface{}{"applicant_name": "Erased", "notes": "", "documents": models.StringArray{}})
+			}},
+			{"emails", false, func() *gorm.DB {
+				return tx.Unscoped().Where("LOWER(to_email) = ?", subject.email).Delete(&models.EmailEvent{})
+			}},
+			{"saved_properties", false, func() *gorm.DB {
+				return tx.Where("LOWER(email) = ?", subject.email).Delete(&models.SavedProperty{})
+			}},
+			{"saved_search_alerts", false, func() *gorm.DB {
+				return tx.Where("saved_search_id IN (?)", tx.Model(&models.SavedSearch{}).Select("id").
+					Where("LOWER(owner_email) = ?", subject.email)).Delete(&models.SavedSearchAlert{})
+			}},
+			{"saved_searches", false, func() *gorm.DB {
+				return tx.Where("LOWER(owner_email) = ?", subject.email).Delete(&models.SavedSearch{})
+			}},
+			{"fub_pushes", false, func() *gorm.DB {
+				return tx.Where("LOWER(email) = ?", subject.email).Delete(&models.ContextFUBPush{})
+			}},
+		}
+		for _, step := range steps {
+			result := step.result()
+			if result.Error != nil {
+				return fmt.Errorf("failed to erase %s: %w", step.section, result.Error)
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			if step.anonymize {
+				erasure.Anonymized[step.section] = int(result.RowsAffected)
+			} else {
+				erasure.Deleted[step.section] = int(result.RowsAffected)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.finish(request, nil, err)
+		return nil, err
+	}
+
+	erasure.CompletedAt = time.Now()
+	s.finish(request, models.JSONB{"anonymized": erasure.Anonymized, "deleted": erasure.Deleted}, nil)
+	log.Printf("🔏 Erasure request %d fulfilled: anonymized %v, deleted %v", request.ID, erasure.Anonymized, erasure.Deleted)
+	return erasure, nil
+}
+
+// RecentRequests returns the latest privacy requests, newest first
+func (s *PrivacyService) RecentRequests(limit int) ([]models.PrivacyRequest, error) {
+	var requests []models.PrivacyRequest
+	err := s.db.Order("created_at DESC, id DESC").Limit(limit).Find(&requests).Error
+	return requests, err
+}
+
+// open validates and logs a request, then finds the subject's records
+func (s *PrivacyService) open(kind string, req PrivacySubjectRequest) (*models.PrivacyRequest, *privacySubject, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email == "" || !strings.Contains(email, "@") {
+		return nil, nil, fmt.Errorf("%w: a valid email is required", ErrInvalidPrivacyRequest)
+	}
+	if strings.TrimSpace(req.VerificationMethod) == "" {
+		return nil, nil, fmt.Errorf("%w: the subject's identity must be verified", ErrInvalidPrivacyRequest)
+	}
+
+	request := &models.PrivacyRequest{
+		Kind:               kind,
+		SubjectHash:        s.encryptionManager.EmailBlindIndex(email),
+		IndexKeyID:         s.encryptionManager.IndexKeyID(),
+		VerificationMethod: req.VerificationMethod,
+		Status:             models.PrivacyRequestReceived,
+	}
+	if req.RequestedBy != nil {
+		request.RequestedByID = req.RequestedBy.ID
+		request.RequestedBy = req.RequestedBy.Username
+	}
+	if err := s.db.Create(request).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to log privacy request: %w", err)
+	}
+
+	subject, err := s.find(email)
+	if err != nil {
+		s.finish(request, nil, err)
+		return nil, nil, err
+	}
+	return request, subject, nil
+}
+
+// finish records a request's outcome
+func (s *PrivacyService) finish(request *models.PrivacyRequest, counts models.JSONB, failure error) {
+	now := time.Now()
+	updates := map[string]interface{}{"status": models.PrivacyRequestFulfilled, "record_counts": counts, "fulfilled_at": &now}
+	if failure != nil {
+		updates = map[string]interface{}{"status": models.PrivacyRequestFailed, "error": failure.Error()}
+	}
+	if err := s.db.Model(request).Updates(updates).Error; err != nil {
+		log.Printf("⚠️ Failed to record outcome of privacy request %d: %v", request.ID, err)
+	}
+}
+
+// find loads the subject's records that hold encrypted PII or link to others.
+// Contacts, bookings and closings have no email blind index, so their emails
+// are decrypted and compared.
+func (s *PrivacyService) find(email string) (*privacySubject, error) {
+	subject := &privacySubject{email: email}
+
+	if err := s.db.Where("LOWER(email) = ?", email).Find(&subject.leads).Error; err != nil {
+		return nil, fmt.Errorf("failed to load leads: %w", err)
+	}
+	if err := s.db.Scopes(models.LeadsWithEmail(s.encryptionManager, email)).Find(&subject.reengagements).Error; err != nil {
+		return nil, fmt.Errorf("failed to load re-engagement leads: %w", err)
+	}
+
+	var contacts []models.Contact
+	if err := s.db.FindInBatches(&contacts, privacyScanBatch, func(tx *gorm.DB, batch int) error {
+		for _, contact := range contacts {
+			if s.emailMatches(contact.Email, email) {
+				subject.contacts = append(subject.contacts, contact)
+			}
+		}
+		return nil
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to search contacts: %w", err)
+	}
+
+	var bookings []models.Booking
+	if err := s.db.FindInBatches(&bookings, privacyScanBatch, func(tx *gorm.DB, batch int) error {
+		for _, booking := range bookings {
+			if s.emailMatches(booking.Email, email) {
+				subject.bookings = append(subject.bookings, booking)
+			}
+		}
+		return nil
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to search bookings: %w", err)
+	}
+
+	var closings []models.ClosingPipeline
+	if err := s.db.FindInBatches(&closings, privacyScanBatch, func(tx *gorm.DB, batch int) error {
+		for _, closing := range closings {
+			if s.emailMatches(closing.TenantEmail, email) {
+				subject.closings = append(subject.closings, closing)
+			}
+		}
+		return nil
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to search closings: %w", err)
+	}
+
+	return subject, nil
+}
+
+func (s *PrivacyService) emailMatches(encrypted security.EncryptedString, email string) bool {
+	if encrypted == "" {
+		return false
+	}
+	decrypted, err := s.encryptionManager.Decrypt(encrypted)
+	return err == nil && strings.ToLower(strings.TrimSpace(decrypted)) == email
+}
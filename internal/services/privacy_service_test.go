@@ -0,0 +1,112 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestPrivacyService_ExportsThenErasesSubject(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&security.EncryptionKey{}, &models.Lead{}, &models.LeadReengagement{}, &models.CampaignTemplate{},
+		&models.CampaignExecution{}, &models.Contact{}, &models.BehavioralEvent{}, &models.BehavioralSession{},
+		&models.ApplicationRecovery{}, &models.EmailEvent{}, &models.SavedSearch{}, &models.SavedSearchAlert{},
+		&models.Booking{}, &models.ClosingPipeline{}, &models.ApplicationApplicant{}, &models.Approval{}, &models.SavedProperty{},
+		&models.ContextFUBPush{}, &models.DataRetentionLog{}, &models.PrivacyRequest{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	t.Setenv("ENCRYPTION_KEY", newTestEncryptionKey(t))
+	em, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create encryption manager: %v", err)
+	}
+	encrypt := func(value string) security.EncryptedString {
+		encrypted, err := em.Encrypt(value)
+		if err != nil {
+			t.Fatalf("Failed to encrypt: %v", err)
+		}
+		return encrypted
+	}
+
+	lead := models.Lead{FirstName: "Ann", LastName: "Lee", Email: "Ann@Example.com", Phone: "+17135550100", FUBLeadID: "fub-1"}
+	other := models.Lead{FirstName: "Bob", LastName: "Ray", Email: "bob@example.com", FUBLeadID: "fub-2"}
+	db.Create(&lead)
+	db.Create(&other)
+	reengagement := models.LeadReengagement{FUBContactID: "fub-1", Email: encrypt("ann@example.com"), FirstName: encrypt("Ann")}
+	reengagement.SetBlindIndexes(em, "Ann", "", "ann@example.com", "")
+	db.Create(&reengagement)
+	template := models.CampaignTemplate{Name: "reengage", EmailNumber: 1, Subject: "Hello", Body: "<p>Hi</p>"}
+	db.Create(&template)
+	db.Create(&models.CampaignExecution{LeadReengagementID: reengagement.ID, CampaignTemplateID: template.ID, Status: "sent"})
+	db.Create(&models.Contact{Name: encrypt("Ann Lee"), Phone: encrypt("+17135550100"), Email: encrypt("ann@example.com"), Message: "Is it available?"})
+	db.Create(&models.Contact{Name: encrypt("Bob Ray"), Phone: encrypt("+17135550101"), Email: encrypt("bob@example.com")})
+	db.Create(&models.BehavioralEvent{LeadID: int64(lead.ID), EventType: "viewed", IPAddress: "10.0.0.1"})
+	db.Create(&models.EmailEvent{EmailType: "campaign", Subject: "Hello", FromEmail: "team@example.com", ToEmail: "ann@example.com", SentAt: time.Now()})
+	db.Create(&models.ApplicationRecovery{ApplicantEmail: "ann@example.com"})
+	db.Create(&models.Booking{ReferenceNumber: "B-1", FUBLeadID: "fub-1", Email: encrypt("ann@example.com"), Name: encrypt("Ann Lee"), ShowingDate: time.Now()})
+	db.Create(&models.ApplicationApplicant{ApplicationNumberID: 1, ApplicantName: "Ann Lee", ApplicantEmail: "ann@example.com"})
+	db.Create(&models.Approval{ApprovalType: "rental_application", FUBLeadID: "fub-1", ApplicantName: "Ann Lee"})
+
+	privacy := NewPrivacyService(db, em, NewDataRetentionService(db, DataRetentionSettings{}))
+	if _, err := privacy.Export(PrivacySubjectRequest{Email: "ann@example.com"}); err == nil {
+		t.Fatal("Expected an unverified request to be refused")
+	}
+
+	export, err := privacy.Export(PrivacySubjectRequest{Email: " ANN@example.com", VerificationMethod: "id_document"})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	for section, want := range map[string]int{"leads": 1, "lead_reengagements": 1, "campaign_executions": 1, "contacts": 1,
+		"bookings": 1, "behavioral_events": 1, "emails": 1, "applications": 1, "application_recoveries": 1, "approvals": 1} {
+		if export.Counts[section] != want {
+			t.Errorf("Expected %d %s exported, got %d", want, section, export.Counts[section])
+		}
+	}
+	if contacts := export.Records["contacts"].([]models.ContactDataResponse); contacts[0].Name != "Ann Lee" {
+		t.Errorf("Expected the contact's name decrypted, got %q", contacts[0].Name)
+	}
+
+	erasure, err := privacy.Erase(PrivacySubjectRequest{Email: "ann@example.com", VerificationMethod: "id_document"})
+	if err != nil {
+		t.Fatalf("Erase failed: %v", err)
+	}
+	if erasure.Anonymized["leads"] != 1 || erasure.Anonymized["lead_reengagements"] != 1 || erasure.Anonymized["bookings"] != 1 ||
+		erasure.Deleted["contacts"] != 1 || erasure.Deleted["emails"] != 1 || erasure.Deleted["application_recoveries"] != 1 {
+		t.Errorf("Unexpected erasure %+v", erasure)
+	}
+
+	var stored models.Lead
+	db.First(&stored, lead.ID)
+	if stored.Email == "Ann@Example.com" || stored.Status != models.LeadStatusAnonymized {
+		t.Errorf("Expected the lead anonymized, got %+v", stored)
+	}
+	var contacts, executions int64
+	db.Model(&models.Contact{}).Count(&contacts)
+	db.Model(&models.CampaignExecution{}).Count(&executions)
+	if contacts != 1 || executions != 1 {
+		t.Errorf("Expected Bob's contact and the campaign history kept, got %d contacts and %d executions", contacts, executions)
+	}
+
+	// Nothing is left to find for the subject
+	export, err = privacy.Export(PrivacySubjectRequest{Email: "ann@example.com", VerificationMethod: "id_document"})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	for section, count := range export.Counts {
+		if count != 0 {
+			t.Errorf("Expected no %s left for the subject, got %d", section, count)
+		}
+	}
+
+	requests, _ := privacy.RecentRequests(10)
+	if len(requests) != 3 || requests[1].Kind != models.PrivacyRequestErasure || requests[1].Status != models.PrivacyRequestFulfilled {
+		t.Errorf("Expected three fulfilled requests logged, got %+v", requests)
+	}
+}
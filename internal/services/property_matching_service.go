@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -25,17 +26,46 @@ func NewPropertyMatchingService(db *gorm.DB) *PropertyMatchingService {
 
 // LeadCriteria represents a lead's property search criteria
 type LeadCriteria struct {
-	LeadID          int64
-	MinBedrooms     int
-	MaxBedrooms     int
-	MinBathrooms    float64
-	MaxBathrooms    float64
-	MinPrice        float64
-	MaxPrice        float64
-	PreferredZips   []string
-	PreferredCities []string
-	PropertyType    string // "apartment", "house", "condo", etc.
-	MustHaveFeatures []string // "parking", "pool", "pets_allowed", etc.
+	LeadID           int64    `json:"lead_id"`
+	MinBedrooms      int      `json:"min_bedrooms"`
+	MaxBedrooms      int      `json:"max_bedrooms"`
+	MinBathrooms     float64  `json:"min_bathrooms"`
+	MaxBathrooms     float64  `json:"max_bathrooms"`
+	MinPrice         float64  `json:"min_price"`
+	MaxPrice         float64  `json:"max_price"`
+	PreferredZips    []string `json:"preferred_zips,omitempty"`
+	PreferredCities  []string `json:"preferred_cities,omitempty"`
+	PropertyType     string   `json:"property_type,omitempty"`      // "apartment", "house", "condo", etc.
+	ListingType      string   `json:"listing_type,omitempty"`       // ListingTypeRental or ListingTypeSale
+	MustHaveFeatures []string `json:"must_have_features,omitempty"` // "parking", "pool", "pets_allowed", etc.
+}
+
+// Listing types a lead can be looking for
+const (
+	ListingTypeRental = "rental"
+	ListingTypeSale   = "sale"
+)
+
+// Match score thresholds and result limits for ranked lead matches
+const (
+	DefaultMatchScoreThreshold = 60.0
+	DefaultLeadMatchLimit      = 20
+	MaxLeadMatchLimit          = 100
+)
+
+// LeadMatchQuery overrides parts of a lead's inferred criteria when ranking
+// matches. Zero values keep the inferred criteria.
+type LeadMatchQuery struct {
+	MinPrice     float64
+	MaxPrice     float64
+	MinBedrooms  int
+	MaxBedrooms  int
+	Cities       []string
+	Zips         []string
+	PropertyType string
+	ListingType  string
+	MinScore     float64 // Defaults to DefaultMatchScoreThreshold
+	Limit        int     // Defaults to DefaultLeadMatchLimit, capped at MaxLeadMatchLimit
 }
 
 // PropertyMatch represents a property matched to a lead
@@ -119,22 +149,7 @@ func (pms *PropertyMatchingService) FindMatchesForLead(leadID int64) ([]Property
 	// Extract criteria
 	criteria := pms.extractLeadCriteria(lead)
 	
-	// Get active properties
-	var properties []models.Property
-	query := pms.db.Where("status = ?", "https://schema.org/InStock")
-	
-	// Apply basic filters
-	if criteria.MinPrice > 0 && criteria.MaxPrice > 0 {
-		query = query.Where("price BETWEEN ? AND ?", criteria.MinPrice, criteria.MaxPrice)
-	}
-	if criteria.MinBedrooms > 0 {
-		query = query.Where("bedrooms >= ?", criteria.MinBedrooms)
-	}
-	if criteria.MaxBedrooms > 0 {
-		query = query.Where("bedrooms <= ?", criteria.MaxBedrooms)
-	}
-	
-	err = query.Find(&properties).Error
+	properties, err := pms.candidateProperties(criteria)
 	if err != nil {
 		return nil, err
 	}
@@ -168,6 +183,126 @@ func (pms *PropertyMatchingService) FindMatchesForLead(leadID int64) ([]Property
 	return matches, nil
 }
 
+// RankMatchesForLead scores active properties against a lead's criteria, with
+// any query overrides applied, and returns the criteria used and the matches
+// at or above the minimum score, best first
+func (pms *PropertyMatchingService) RankMatchesForLead(leadID int64, query LeadMatchQuery) (LeadCriteria, []PropertyMatch, error) {
+	var lead models.Lead
+	if err := pms.db.First(&lead, leadID).Error; err != nil {
+		return LeadCriteria{}, nil, err
+	}
+
+	criteria := pms.extractLeadCriteria(lead)
+	applyLeadMatchQuery(&criteria, query)
+
+	minScore := query.MinScore
+	if minScore <= 0 {
+		minScore = DefaultMatchScoreThreshold
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultLeadMatchLimit
+	}
+	if limit > MaxLeadMatchLimit {
+		limit = MaxLeadMatchLimit
+	}
+
+	properties, err := pms.candidateProperties(criteria)
+	if err != nil {
+		return criteria, nil, err
+	}
+
+	matches := []PropertyMatch{}
+	for _, property := range properties {
+		// Rental vs sale is a filter: a lead looking to rent is never shown listings for sale
+		if listingType := NormalizeListingType(property.ListingType); criteria.ListingType != "" && listingType != "" && listingType != criteria.ListingType {
+			continue
+		}
+		matchScore, reasons := pms.calculateMatchScore(property, criteria)
+		if matchScore < minScore {
+			continue
+		}
+		matches = append(matches, PropertyMatch{
+			PropertyID:   int64(property.ID),
+			LeadID:       leadID,
+			MatchScore:   matchScore,
+			MatchReasons: reasons,
+			Property:     &property,
+			MatchedAt:    time.Now(),
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].MatchScore > matches[j].MatchScore
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return criteria, matches, nil
+}
+
+// applyLeadMatchQuery replaces inferred criteria with any overrides in the query
+func applyLeadMatchQuery(criteria *LeadCriteria, query LeadMatchQuery) {
+	if query.MinPrice > 0 {
+		criteria.MinPrice = query.MinPrice
+	}
+	if query.MaxPrice > 0 {
+		criteria.MaxPrice = query.MaxPrice
+	}
+	if query.MinBedrooms > 0 {
+		criteria.MinBedrooms = query.MinBedrooms
+	}
+	if query.MaxBedrooms > 0 {
+		criteria.MaxBedrooms = query.MaxBedrooms
+	}
+	if len(query.Cities) > 0 {
+		criteria.PreferredCities = query.Cities
+	}
+	if len(query.Zips) > 0 {
+		criteria.PreferredZips = query.Zips
+	}
+	if query.PropertyType != "" {
+		criteria.PropertyType = query.PropertyType
+	}
+	if query.ListingType != "" {
+		criteria.ListingType = NormalizeListingType(query.ListingType)
+	}
+}
+
+// candidateProperties loads active properties within the criteria's price and bedroom range
+func (pms *PropertyMatchingService) candidateProperties(criteria LeadCriteria) ([]models.Property, error) {
+	var properties []models.Property
+	query := pms.db.Where("status = ?", "https://schema.org/InStock")
+
+	// Apply basic filters
+	if criteria.MinPrice > 0 && criteria.MaxPrice > 0 {
+		query = query.Where("price BETWEEN ? AND ?", criteria.MinPrice, criteria.MaxPrice)
+	}
+	if criteria.MinBedrooms > 0 {
+		query = query.Where("bedrooms >= ?", criteria.MinBedrooms)
+	}
+	if criteria.MaxBedrooms > 0 {
+		query = query.Where("bedrooms <= ?", criteria.MaxBedrooms)
+	}
+
+	err := query.Find(&properties).Error
+	return properties, err
+}
+
+// NormalizeListingType maps listing and preference values such as "for_rent",
+// "For Sale", or "buy" to ListingTypeRental or ListingTypeSale, or "" if unknown
+func NormalizeListingType(value string) string {
+	value = strings.ToLower(value)
+	switch {
+	case strings.Contains(value, "rent"), strings.Contains(value, "lease"):
+		return ListingTypeRental
+	case strings.Contains(value, "sale"), strings.Contains(value, "buy"), strings.Contains(value, "purchase"):
+		return ListingTypeSale
+	}
+	return ""
+}
+
 // FindNewMatchesSince finds new property matches created since a given time
 func (pms *PropertyMatchingService) FindNewMatchesSince(since time.Time) ([]PropertyMatch, error) {
 	log.Printf("🔍 Property Matching: Finding new matches since %s", since.Format("2006-01-02"))
@@ -251,6 +386,9 @@ func (pms *PropertyMatchingService) extractLeadCriteria(lead models.Lead) LeadCr
 		if propType, ok := prefs["property_type"].(string); ok {
 			criteria.PropertyType = propType
 		}
+		if listingType, ok := prefs["listing_type"].(string); ok {
+			criteria.ListingType = NormalizeListingType(listingType)
+		}
 		
 		// Features
 		if features, ok := prefs["must_have_features"].([]interface{}); ok {
@@ -269,38 +407,59 @@ func (pms *PropertyMatchingService) extractLeadCriteria(lead models.Lead) LeadCr
 	if criteria.MinPrice == 0 && criteria.MaxPrice == 0 {
 		criteria.MinPrice, criteria.MaxPrice = pms.inferPriceRange(lead)
 	}
+	if criteria.ListingType == "" {
+		criteria.ListingType = pms.inferListingType(lead)
+	}
 	
 	return criteria
 }
 
-// inferBedroomPreference infers bedroom preference from viewed properties
-func (pms *PropertyMatchingService) inferBedroomPreference(lead models.Lead) (int, int) {
-	// Get properties the lead has viewed
+// viewedProperties returns up to 10 properties the lead has viewed
+func (pms *PropertyMatchingService) viewedProperties(lead models.Lead) []models.Property {
 	var events []models.BehavioralEvent
 	pms.db.Where("lead_id = ?", lead.ID).
-		Where("event_type = ?", "property_view").
+		Where("event_type IN ?", []string{"viewed", "property_view"}).
 		Where("property_id IS NOT NULL").
 		Limit(10).
 		Find(&events)
-	
-	if len(events) == 0 {
-		return 1, 4 // Default range
-	}
-	
-	// Get property IDs
+
 	propertyIDs := []int64{}
 	for _, event := range events {
 		if event.PropertyID != nil {
 			propertyIDs = append(propertyIDs, *event.PropertyID)
 		}
 	}
-	
-	// Get bedroom counts
+	if len(propertyIDs) == 0 {
+		return nil
+	}
+
 	var properties []models.Property
 	pms.db.Where("id IN ?", propertyIDs).Find(&properties)
-	
+	return properties
+}
+
+// inferListingType returns rental or sale when every viewed property shares one listing type
+func (pms *PropertyMatchingService) inferListingType(lead models.Lead) string {
+	inferred := ""
+	for _, property := range pms.viewedProperties(lead) {
+		listingType := NormalizeListingType(property.ListingType)
+		if listingType == "" {
+			continue
+		}
+		if inferred != "" && inferred != listingType {
+			return ""
+		}
+		inferred = listingType
+	}
+	return inferred
+}
+
+// inferBedroomPreference infers bedroom preference from viewed properties
+func (pms *PropertyMatchingService) inferBedroomPreference(lead models.Lead) (int, int) {
+	// Get properties the lead has viewed
+	properties := pms.viewedProperties(lead)
 	if len(properties) == 0 {
-		return 1, 4
+		return 1, 4 // Default range
 	}
 	
 	// Calculate average and range
@@ -334,29 +493,9 @@ func (pms *PropertyMatchingService) inferBedroomPreference(lead models.Lead) (in
 // inferPriceRange infers price range from viewed properties
 func (pms *PropertyMatchingService) inferPriceRange(lead models.Lead) (float64, float64) {
 	// Similar logic to bedroom inference
-	var events []models.BehavioralEvent
-	pms.db.Where("lead_id = ?", lead.ID).
-		Where("event_type = ?", "property_view").
-		Where("property_id IS NOT NULL").
-		Limit(10).
-		Find(&events)
-	
-	if len(events) == 0 {
-		return 800, 3000 // Default range
-	}
-	
-	propertyIDs := []int64{}
-	for _, event := range events {
-		if event.PropertyID != nil {
-			propertyIDs = append(propertyIDs, *event.PropertyID)
-		}
-	}
-	
-	var properties []models.Property
-	pms.db.Where("id IN ?", propertyIDs).Find(&properties)
-	
+	properties := pms.viewedProperties(lead)
 	if len(properties) == 0 {
-		return 800, 3000
+		return 800, 3000 // Default range
 	}
 	
 	// Calculate range
@@ -459,7 +598,7 @@ func (pms *PropertyMatchingService) calculateMatchScore(property models.Property
 	locationMatch := false
 	if len(criteria.PreferredZips) > 0 {
 		for _, zip := range criteria.PreferredZips {
-			if strings.Contains(string(property.Address), zip) {
+			if property.ZipCode == zip || strings.Contains(string(property.Address), zip) {
 				score += 20
 				reasons = append(reasons, "✓ In preferred ZIP code")
 				locationMatch = true
@@ -469,7 +608,7 @@ func (pms *PropertyMatchingService) calculateMatchScore(property models.Property
 	}
 	if !locationMatch && len(criteria.PreferredCities) > 0 {
 		for _, city := range criteria.PreferredCities {
-			if strings.Contains(strings.ToLower(string(property.Address)), strings.ToLower(city)) {
+			if strings.EqualFold(property.City, city) || strings.Contains(strings.ToLower(string(property.Address)), strings.ToLower(city)) {
 				score += 15
 				reasons = append(reasons, "✓ In preferred city")
 				locationMatch = true
@@ -492,6 +631,15 @@ func (pms *PropertyMatchingService) calculateMatchScore(property models.Property
 		score += 5
 	}
 	
+	// Listing type match (weight: 20, only when the lead's rental vs sale intent is known)
+	if criteria.ListingType != "" {
+		maxScore += 20
+		if NormalizeListingType(property.ListingType) == criteria.ListingType {
+			score += 20
+			reasons = append(reasons, fmt.Sprintf("✓ Listed for %s", criteria.ListingType))
+		}
+	}
+	
 	// Normalize to 0-100
 	finalScore := (score / maxScore) * 100
 	
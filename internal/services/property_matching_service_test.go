@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRankMatchesForLead_RanksByRelevanceWithOverrides(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Lead{}, &models.Property{}, &models.BehavioralEvent{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	lead := models.Lead{FirstName: "Dana", LastName: "Lee", Email: "dana@example.com", CustomFields: models.JSONB{
+		"min_price":        1500.0,
+		"max_price":        2500.0,
+		"min_bedrooms":     2.0,
+		"max_bedrooms":     3.0,
+		"preferred_cities": []interface{}{"Katy"},
+		"listing_type":     "for_rent",
+	}}
+	if err := db.Create(&lead).Error; err != nil {
+		t.Fatalf("Failed to create lead: %v", err)
+	}
+
+	two, three := 2, 3
+	properties := []models.Property{
+		{MLSId: "best", Address: "1 Oak St", City: "Katy", Bedrooms: &three, Price: 2000, ListingType: "for_rent"},
+		{MLSId: "other-city", Address: "2 Elm St", City: "Houston", Bedrooms: &two, Price: 1800, ListingType: "for_rent"},
+		{MLSId: "for-sale", Address: "3 Pine St", City: "Katy", Bedrooms: &three, Price: 2200, ListingType: "for_sale"},
+		{MLSId: "too-pricey", Address: "4 Ash St", City: "Katy", Bedrooms: &three, Price: 4000, ListingType: "for_rent"},
+	}
+	for i := range properties {
+		properties[i].Status = "https://schema.org/InStock"
+		if err := db.Create(&properties[i]).Error; err != nil {
+			t.Fatalf("Failed to create property: %v", err)
+		}
+	}
+
+	service := NewPropertyMatchingService(db)
+	criteria, matches, err := service.RankMatchesForLead(int64(lead.ID), LeadMatchQuery{})
+	if err != nil {
+		t.Fatalf("RankMatchesForLead failed: %v", err)
+	}
+	if criteria.ListingType != ListingTypeRental {
+		t.Errorf("Expected rental intent from custom fields, got %q", criteria.ListingType)
+	}
+	if len(matches) != 2 || matches[0].Property.MLSId != "best" || matches[1].Property.MLSId != "other-city" {
+		t.Fatalf("Expected best then other-city, got %+v", matches)
+	}
+	if matches[0].MatchScore <= matches[1].MatchScore || len(matches[0].MatchReasons) == 0 {
+		t.Errorf("Expected the Katy rental to score higher with reasons, got %+v", matches[0])
+	}
+
+	// Overrides replace the inferred criteria
+	_, matches, err = service.RankMatchesForLead(int64(lead.ID), LeadMatchQuery{ListingType: "sale", MaxPrice: 3000, Limit: 1})
+	if err != nil {
+		t.Fatalf("RankMatchesForLead with overrides failed: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Property.MLSId != "for-sale" {
+		t.Errorf("Expected the sale listing first when overriding to sale, got %+v", matches)
+	}
+
+	if _, _, err := service.RankMatchesForLead(9999, LeadMatchQuery{}); err != gorm.ErrRecordNotFound {
+		t.Errorf("Expected ErrRecordNotFound for a missing lead, got %v", err)
+	}
+}
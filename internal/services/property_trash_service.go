@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrPropertyNotDeleted is returned when restoring a property that is not in the trash
+var ErrPropertyNotDeleted = errors.New("property is not deleted")
+
+// PropertyAuditActor identifies who performed a delete or restore
+type PropertyAuditActor struct {
+	ID        string
+	Name      string
+	IPAddress string
+}
+
+// PropertyTrashService soft-deletes and restores properties, recording an
+// audit entry for each change
+type PropertyTrashService struct {
+	db *gorm.DB
+}
+
+// NewPropertyTrashService creates a new property trash service
+func NewPropertyTrashService(db *gorm.DB) *PropertyTrashService {
+	return &PropertyTrashService{db: db}
+}
+
+// DeleteProperty soft-deletes a property. Returns gorm.ErrRecordNotFound when
+// the property does not exist or is already deleted.
+func (s *PropertyTrashService) DeleteProperty(propertyID uint, actor PropertyAuditActor, reason string) (*models.Property, error) {
+	var property models.Property
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&property, propertyID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&property).Error; err != nil {
+			return fmt.Errorf("failed to delete property: %w", err)
+		}
+		return s.recordAudit(tx, &property, models.PropertyAuditActionDeleted, actor, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("🗑️ Property %d moved to trash by %s", property.ID, actor.Name)
+	return &property, nil
+}
+
+// RestoreProperty brings a soft-deleted property back. Returns
+// gorm.ErrRecordNotFound when the property does not exist and
+// ErrPropertyNotDeleted when it is not in the trash.
+func (s *PropertyTrashService) RestoreProperty(propertyID uint, actor PropertyAuditActor) (*models.Property, error) {
+	var property models.Property
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().First(&property, propertyID).Error; err != nil {
+			return err
+		}
+		if !property.DeletedAt.Valid {
+			return ErrPropertyNotDeleted
+		}
+		if err := tx.Unscoped().Model(&property).Update("deleted_at", nil).Error; err != nil {
+			return fmt.Errorf("failed to restore property: %w", err)
+		}
+		property.DeletedAt = gorm.DeletedAt{}
+		return s.recordAudit(tx, &property, models.PropertyAuditActionRestored, actor, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("♻️ Property %d restored by %s", property.ID, actor.Name)
+	return &property, nil
+}
+
+// ListTrash returns soft-deleted properties, most recently deleted first
+func (s *PropertyTrashService) ListTrash(limit int) ([]models.Property, error) {
+	var properties []models.Property
+	err := s.db.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Limit(limit).
+		Find(&properties).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted properties: %w", err)
+	}
+	return properties, nil
+}
+
+// AuditHistory returns the delete/restore history of a property, newest first
+func (s *PropertyTrashService) AuditHistory(propertyID uint) ([]models.PropertyAuditLog, error) {
+	var entries []models.PropertyAuditLog
+	err := s.db.Where("property_id = ?", propertyID).
+		Order("created_at DESC, id DESC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load property audit history: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *PropertyTrashService) recordAudit(tx *gorm.DB, property *models.Property, action string, actor PropertyAuditActor, reason string) error {
+	entry := models.PropertyAuditLog{
+		PropertyID: property.ID,
+		MLSId:      property.MLSId,
+		Action:     action,
+		ActorID:    actor.ID,
+		ActorName:  actor.Name,
+		IPAddress:  actor.IPAddress,
+		Reason:     reason,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to record property audit entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestPropertyTrashService_DeleteAndRestore(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Property{}, &models.PropertyAuditLog{}, &models.PropertyState{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	property := models.Property{MLSId: "HAR123", Address: "123 Main St", Status: "active"}
+	if err := db.Create(&property).Error; err != nil {
+		t.Fatalf("Failed to create property: %v", err)
+	}
+	if err := db.Create(&models.PropertyState{MLSId: "HAR123", Status: "active"}).Error; err != nil {
+		t.Fatalf("Failed to create property state: %v", err)
+	}
+
+	service := NewPropertyTrashService(db)
+	actor := PropertyAuditActor{ID: "admin-1", Name: "chris", IPAddress: "10.0.0.1"}
+
+	if _, err := service.DeleteProperty(property.ID, actor, "wrong listing"); err != nil {
+		t.Fatalf("DeleteProperty failed: %v", err)
+	}
+
+	var visible int64
+	db.Model(&models.Property{}).Count(&visible)
+	if visible != 0 {
+		t.Fatalf("Expected deleted property to be hidden from default queries, got %d", visible)
+	}
+	trash, err := service.ListTrash(10)
+	if err != nil || len(trash) != 1 || trash[0].ID != property.ID {
+		t.Fatalf("Expected property in trash, got %+v (err %v)", trash, err)
+	}
+
+	// Central sync must not resurrect or update a deleted property
+	stateManager := NewCentralPropertyStateManager(db, nil)
+	if err := stateManager.UpdatePropertyStatus("HAR123", "pending", "har"); !errors.Is(err, ErrPropertyDeleted) {
+		t.Fatalf("Expected ErrPropertyDeleted from status sync, got %v", err)
+	}
+	if states, _ := stateManager.GetAllProperties(); len(states) != 0 {
+		t.Fatalf("Expected deleted property excluded from central state, got %d", len(states))
+	}
+
+	if _, err := service.RestoreProperty(property.ID, actor); err != nil {
+		t.Fatalf("RestoreProperty failed: %v", err)
+	}
+	if _, err := service.RestoreProperty(property.ID, actor); !errors.Is(err, ErrPropertyNotDeleted) {
+		t.Fatalf("Expected ErrPropertyNotDeleted on second restore, got %v", err)
+	}
+	db.Model(&models.Property{}).Count(&visible)
+	if visible != 1 {
+		t.Fatalf("Expected restored property to be visible, got %d", visible)
+	}
+
+	history, err := service.AuditHistory(property.ID)
+	if err != nil || len(history) != 2 {
+		t.Fatalf("Expected 2 audit entries, got %+v (err %v)", history, err)
+	}
+	if history[0].Action != models.PropertyAuditActionRestored || history[1].Action != models.PropertyAuditActionDeleted {
+		t.Fatalf("Unexpected audit order %+v", history)
+	}
+	if history[1].ActorID != "admin-1" || history[1].Reason != "wrong listing" || history[1].MLSId != "HAR123" {
+		t.Fatalf("Unexpected delete audit entry %+v", history[1])
+	}
+}
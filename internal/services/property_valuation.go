@@ -12,6 +12,7 @@ import (
 	"chrisgross-ctrl-project/internal/config"
 	"chrisgross-ctrl-project/internal/models"
 	"chrisgross-ctrl-project/internal/scraper"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
@@ -23,6 +24,12 @@ type PropertyValuationService struct {
 	// harScraper removed - HAR blocked access
 	marketDataCache map[string]*MarketData
 	cacheTTL        time.Duration
+	valuationCache  *valuationCache
+
+	// Valuation change alerting
+	notificationHub        *AdminNotificationHub
+	webhooks               *OutboundWebhookService
+	changeThresholdPercent float64
 }
 
 // PropertyValuationRequest represents a valuation request
@@ -42,6 +49,7 @@ type PropertyValuationRequest struct {
 	UpdatedKitchen  bool    `json:"updated_kitchen,omitempty"`
 	UpdatedBathroom bool    `json:"updated_bathroom,omitempty"`
 	HardwoodFloors  bool    `json:"hardwood_floors,omitempty"`
+	ForceRefresh    bool    `json:"force_refresh,omitempty"` // Bypass the valuation cache
 }
 
 // PropertyValuation represents the valuation result
@@ -55,6 +63,7 @@ type PropertyValuation struct {
 	ValuationFactors []ValuationFactor       `json:"valuation_factors"`
 	Recommendations  []PricingRecommendation `json:"recommendations"`
 	LastUpdated      time.Time               `json:"last_updated"`
+	CacheHit         bool                    `json:"cache_hit"`
 }
 
 // ValueRange represents the estimated value range
@@ -123,11 +132,48 @@ func NewPropertyValuationService(config *config.Config, db *gorm.DB, scraperServ
 		scraperService:  scraperService,
 		marketDataCache: make(map[string]*MarketData),
 		cacheTTL:        24 * time.Hour,
+		valuationCache:  newValuationCache(DefaultValuationCacheTTL),
+
+		changeThresholdPercent: DefaultValuationChangeThresholdPercent,
 	}
 }
 
+// SetRedisCache stores cached valuations in Redis instead of the in-memory LRU
+func (pvs *PropertyValuationService) SetRedisCache(client *redis.Client) {
+	pvs.valuationCache.redis = client
+}
+
+// SetValuationCacheTTL sets how long a valuation is reused for the same address
+func (pvs *PropertyValuationService) SetValuationCacheTTL(ttl time.Duration) {
+	if ttl > 0 {
+		pvs.valuationCache.ttl = ttl
+	}
+}
+
+// ScraperHealth returns the scraper API health, or false if no scraper is configured
+func (pvs *PropertyValuationService) ScraperHealth() (scraper.ScraperHealth, bool) {
+	if pvs.scraperService == nil {
+		return scraper.ScraperHealth{}, false
+	}
+	return pvs.scraperService.Health(), true
+}
+
+// GetValuationCacheStats returns valuation cache hit/miss counters
+func (pvs *PropertyValuationService) GetValuationCacheStats() ValuationCacheStats {
+	return pvs.valuationCache.stats()
+}
+
 // ValuateProperty performs comprehensive property valuation
 func (pvs *PropertyValuationService) ValuateProperty(request PropertyValuationRequest) (*PropertyValuation, error) {
+	cacheKey := valuationCacheKey(request)
+	if !request.ForceRefresh {
+		if cached, ok := pvs.valuationCache.get(cacheKey); ok {
+			log.Printf("💾 Using cached valuation for %s", request.Address)
+			cached.CacheHit = true
+			return cached, nil
+		}
+	}
+
 	log.Printf("🏠 Starting property valuation for %s", request.Address)
 
 	// Get market data for the area
@@ -175,6 +221,9 @@ func (pvs *PropertyValuationService) ValuateProperty(request PropertyValuationRe
 		LastUpdated:      time.Now(),
 	}
 
+	pvs.valuationCache.set(cacheKey, valuation)
+	pvs.recordValuationChange(request, valuation)
+
 	log.Printf("🎯 Property valuation complete: $%d (confidence: %.2f)", adjustedValue, confidence)
 	return valuation, nil
 }
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// savedSearchListingLookback is how far back the first run after startup looks
+// for new listings; alert dedupe keeps re-checked listings from alerting twice
+const savedSearchListingLookback = time.Hour
+
+// savedSearchAlertBatch caps how many events or alerts one run handles
+const savedSearchAlertBatch = 200
+
+// SavedSearchAlertService matches new and re-priced listings against saved
+// searches and alerts their owners by email or SMS, outside quiet hours
+type SavedSearchAlertService struct {
+	db    *gorm.DB
+	email alertEmailSender
+	sms   alertSMSSender
+
+	quietStart int // Hour quiet hours start, 0-23
+	quietEnd   int // Hour quiet hours end; equal to quietStart disables quiet hours
+	location   *time.Location
+	baseURL    string
+
+	listingsCheckedAt time.Time
+	now               func() time.Time
+}
+
+// NewSavedSearchAlertService creates a saved search alert service
+func NewSavedSearchAlertService(db *gorm.DB, email *EmailService, sms *SMSService) *SavedSearchAlertService {
+	s := &SavedSearchAlertService{
+		db:         db,
+		quietStart: 21,
+		quietEnd:   8,
+		location:   time.Local,
+		now:        time.Now,
+	}
+	// Avoid storing typed nils so the channel checks in deliver work
+	if email != nil {
+		s.email = email
+	}
+	if sms != nil {
+		s.sms = sms
+	}
+	s.listingsCheckedAt = s.now().Add(-savedSearchListingLookback)
+	return s
+}
+
+// SetQuietHours sets the hours, in loc, during which alerts are held until
+// quiet hours end. Equal start and end hours disable quiet hours.
+func (s *SavedSearchAlertService) SetQuietHours(start, end int, loc *time.Location) {
+	s.quietStart = start
+	s.quietEnd = end
+	if loc != nil {
+		s.location = loc
+	}
+}
+
+// SetBaseURL sets the site URL used for listing links in alerts
+func (s *SavedSearchAlertService) SetBaseURL(baseURL string) {
+	s.baseURL = strings.TrimRight(baseURL, "/")
+}
+
+// Start runs RunOnce every interval until ctx is cancelled
+func (s *SavedSearchAlertService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.RunOnce()
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 Saved search alerts stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	log.Printf("🕐 Saved search alerts started (every %v, quiet %02d:00-%02d:00 %s)", interval, s.quietStart, s.quietEnd, s.location)
+}
+
+// RunOnce queues alerts for new listings and listing price changes, then
+// delivers pending alerts unless it is quiet hours
+func (s *SavedSearchAlertService) RunOnce() {
+	if err := s.queueNewListings(); err != nil {
+		log.Printf("⚠️ Failed to check new listings against saved searches: %v", err)
+	}
+	if err := s.queuePriceChanges(); err != nil {
+		log.Printf("⚠️ Failed to check price changes against saved searches: %v", err)
+	}
+	if s.InQuietHours(s.now()) {
+		return
+	}
+	if sent, err := s.DeliverPending(); err != nil {
+		log.Printf("⚠️ Failed to deliver saved search alerts: %v", err)
+	} else if sent > 0 {
+		log.Printf("🔔 Sent %d saved search alerts", sent)
+	}
+}
+
+// InQuietHours reports whether t falls in quiet hours
+func (s *SavedSearchAlertService) InQuietHours(t time.Time) bool {
+	if s.quietStart == s.quietEnd {
+		return false
+	}
+	hour := t.In(s.location).Hour()
+	if s.quietStart < s.quietEnd {
+		return hour >= s.quietStart && hour < s.quietEnd
+	}
+	return hour >= s.quietStart || hour < s.quietEnd
+}
+
+// queueNewListings matches listings created since the last check
+func (s *SavedSearchAlertService) queueNewListings() error {
+	checkedAt := s.now()
+	var properties []models.Property
+	if err := s.db.Where("created_at >= ?", s.listingsCheckedAt).
+		Order("created_at ASC").
+		Find(&properties).Error; err != nil {
+		return err
+	}
+	for _, property := range properties {
+		if _, err := s.QueueMatches(property, models.SavedSearchTriggerNewListing); err != nil {
+			return err
+		}
+	}
+	s.listingsCheckedAt = checkedAt
+	return nil
+}
+
+// queuePriceChanges matches listings whose listed price changed. Valuation
+// changes are estimates, not listing changes, so they do not alert.
+func (s *SavedSearchAlertService) queuePriceChanges() error {
+	var events []models.PriceChangeEvent
+	if err := s.db.Where("search_alerts_checked_at IS NULL AND source = ?", "listing").
+		Order("created_at ASC").
+		Limit(savedSearchAlertBatch).
+		Find(&events).Error; err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		var property models.Property
+		err := s.db.First(&property, event.PropertyID).Error
+		if err == nil {
+			if _, err := s.QueueMatches(property, models.SavedSearchTriggerPriceChange); err != nil {
+				return err
+			}
+		} else if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		now := s.now()
+		if err := s.db.Model(&models.PriceChangeEvent{}).
+			Where("id = ?", event.ID).
+			Update("search_alerts_checked_at", &now).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// QueueMatches records a pending alert for every active saved search the
+// property matches and that has not already been alerted for it. It returns
+// how many alerts were queued.
+func (s *SavedSearchAlertService) QueueMatches(property models.Property, trigger string) (int, error) {
+	var searches []models.SavedSearch
+	if err := s.db.Where("active = ?", true).Find(&searches).Error; err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for _, search := range searches {
+		if !SavedSearchMatches(search.Criteria, property) {
+			continue
+		}
+		alert := models.SavedSearchAlert{
+			SavedSearchID: search.ID,
+			PropertyID:    property.ID,
+			Trigger:       trigger,
+			Channel:       search.Channel,
+			Status:        models.SavedSearchAlertPending,
+		}
+		result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&alert)
+		if result.Error != nil {
+			return queued, result.Error
+		}
+		queued += int(result.RowsAffected)
+	}
+	return queued, nil
+}
+
+// SavedSearchMatches reports whether a property meets every filter in the criteria
+func SavedSearchMatches(criteria models.SavedSearchCriteria, property models.Property) bool {
+	if criteria.MinPrice > 0 && property.Price < criteria.MinPrice {
+		return false
+	}
+	if criteria.MaxPrice > 0 && property.Price > criteria.MaxPrice {
+		return false
+	}
+	if criteria.MinBedrooms > 0 && (property.Bedrooms == nil || *property.Bedrooms < criteria.MinBedrooms) {
+		return false
+	}
+	if criteria.MaxBedrooms > 0 && property.Bedrooms != nil && *property.Bedrooms > criteria.MaxBedrooms {
+		return false
+	}
+	if criteria.MinBathrooms > 0 && (property.Bathrooms == nil || float64(*property.Bathrooms) < criteria.MinBathrooms) {
+		return false
+	}
+	if len(criteria.Cities) > 0 && !containsFold(criteria.Cities, property.City) {
+		return false
+	}
+	if len(criteria.Zips) > 0 && !containsFold(criteria.Zips, property.ZipCode) {
+		return false
+	}
+	if len(criteria.PropertyTypes) > 0 && !containsFold(criteria.PropertyTypes, property.PropertyType) {
+		return false
+	}
+	if criteria.ListingType != "" && NormalizeListingType(property.ListingType) != NormalizeListingType(criteria.ListingType) {
+		return false
+	}
+	return true
+}
+
+// containsFold reports whether value case-insensitively equals a list entry
+func containsFold(list []string, value string) bool {
+	for _, entry := range list {
+		if strings.EqualFold(strings.TrimSpace(entry), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliverPending sends pending alerts and returns how many were sent. Alerts
+// whose saved search was deactivated since queueing are marked failed.
+func (s *SavedSearchAlertService) DeliverPending() (int, error) {
+	var alerts []models.SavedSearchAlert
+	if err := s.db.Where("status = ?", models.SavedSearchAlertPending).
+		Order("created_at ASC").
+		Limit(savedSearchAlertBatch).
+		Find(&alerts).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, alert := range alerts {
+		err := s.deliver(alert)
+		updates := map[string]interface{}{"status": models.SavedSearchAlertSent}
+		if err != nil {
+			log.Printf("❌ Failed to send saved search alert %d: %v", alert.ID, err)
+			updates = map[string]interface{}{"status": models.SavedSearchAlertFailed, "error": err.Error()}
+		} else {
+			now := s.now()
+			updates["sent_at"] = &now
+			sent++
+			s.db.Model(&models.SavedSearch{}).Where("id = ?", alert.SavedSearchID).Update("last_alerted_at", &now)
+		}
+		if err := s.db.Model(&models.SavedSearchAlert{}).Where("id = ?", alert.ID).Updates(updates).Error; err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// deliver sends one alert through its saved search's channel
+func (s *SavedSearchAlertService) deliver(alert models.SavedSearchAlert) error {
+	var search models.SavedSearch
+	if err := s.db.First(&search, alert.SavedSearchID).Error; err != nil {
+		return fmt.Errorf("saved search %d: %w", alert.SavedSearchID, err)
+	}
+	if !search.Active {
+		return fmt.Errorf("saved search %d is no longer active", search.ID)
+	}
+	var property models.Property
+	if err := s.db.First(&property, alert.PropertyID).Error; err != nil {
+		return fmt.Errorf("property %d: %w", alert.PropertyID, err)
+	}
+
+	metadata := map[string]interface{}{
+		"saved_search_id": search.ID,
+		"property_id":     property.ID,
+		"alert_id":        alert.ID,
+		"campaign_type":   "saved_search_alert",
+	}
+	headline := "New listing"
+	if alert.Trigger == models.SavedSearchTriggerPriceChange {
+		headline = "Price change"
+	}
+	link := fmt.Sprintf("%s/property/%d", s.baseURL, property.ID)
+	location := strings.TrimSpace(fmt.Sprintf("%s, %s %s", property.City, property.State, property.ZipCode))
+
+	switch search.Channel {
+	case models.SavedSearchChannelSMS:
+		if s.sms == nil {
+			return fmt.Errorf("sms service not configured")
+		}
+		content := fmt.Sprintf("%s for your saved search %q: %s, $%.0f. %s", headline, search.Name, location, property.Price, link)
+		return s.sms.SendSMS(search.OwnerPhone, content, metadata)
+	default:
+		if s.email == nil {
+			return fmt.Errorf("email service not configured")
+		}
+		subject := fmt.Sprintf("🏠 %s matching your saved search: %s", headline, property.Address)
+		body := fmt.Sprintf(`<h2>%s matching "%s"</h2>
+<p><strong>%s</strong><br>%s</p>
+<p style="font-size:24px;font-weight:700;">$%.0f</p>
+<p><a href="%s">View listing</a></p>`,
+			headline,
+			html.EscapeString(search.Name),
+			html.EscapeString(string(property.Address)),
+			html.EscapeString(location),
+			property.Price,
+			html.EscapeString(link),
+		)
+		return s.email.SendEmail(search.OwnerEmail, subject, body, metadata)
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type recordingAlertSMSSender struct {
+	sent chan string
+}
+
+func (r *recordingAlertSMSSender) SendSMS(to, content string, metadata map[string]interface{}) error {
+	r.sent <- to
+	return nil
+}
+
+func TestSavedSearchAlerts_AlertsOncePerListingOutsideQuietHours(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.Property{}, &models.PriceChangeEvent{}, &models.SavedSearch{}, &models.SavedSearchAlert{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	katy := models.SavedSearch{Name: "Katy rentals", OwnerEmail: "renter@example.com", Channel: models.SavedSearchChannelEmail, Active: true,
+		Criteria: models.SavedSearchCriteria{Cities: []string{"Katy"}, MaxPrice: 2500, ListingType: "rental"}}
+	houston := models.SavedSearch{Name: "Houston 3BR", OwnerPhone: "+17135550100", Channel: models.SavedSearchChannelSMS, Active: true,
+		Criteria: models.SavedSearchCriteria{Cities: []string{"Houston"}, MinBedrooms: 3}}
+	for _, search := range []*models.SavedSearch{&katy, &houston} {
+		if err := db.Create(search).Error; err != nil {
+			t.Fatalf("Failed to create saved search: %v", err)
+		}
+	}
+
+	email := &recordingAlertEmailSender{sent: make(chan string, 10)}
+	sms := &recordingAlertSMSSender{sent: make(chan string, 10)}
+	service := NewSavedSearchAlertService(db, nil, nil)
+	service.email = email
+	service.sms = sms
+	service.SetQuietHours(0, 0, time.Local)
+
+	three := 3
+	listing := models.Property{MLSId: "katy-1", Address: "1 Oak St", City: "Katy", Price: 2000, ListingType: "for_rent", Bedrooms: &three}
+	if err := db.Create(&listing).Error; err != nil {
+		t.Fatalf("Failed to create property: %v", err)
+	}
+	service.RunOnce()
+	if len(email.sent) != 1 || <-email.sent != "renter@example.com" {
+		t.Fatalf("Expected one email alert for the new Katy listing")
+	}
+
+	// A later price change on the same listing does not alert again
+	db.Create(&models.PriceChangeEvent{PropertyID: listing.ID, OldPrice: 2000, NewPrice: 1900, Source: "listing", ChangedAt: time.Now()})
+	service.RunOnce()
+	if len(email.sent) != 0 {
+		t.Errorf("Expected no second alert for the same listing, got %d", len(email.sent))
+	}
+	var checked int64
+	db.Model(&models.PriceChangeEvent{}).Where("search_alerts_checked_at IS NOT NULL").Count(&checked)
+	if checked != 1 {
+		t.Errorf("Expected the price change to be marked checked, got %d", checked)
+	}
+
+	// During quiet hours a match is queued but held until quiet hours end
+	hour := time.Now().Hour()
+	service.SetQuietHours(hour, (hour+1)%24, time.Local)
+	house := models.Property{MLSId: "hou-1", Address: "2 Elm St", City: "Houston", Price: 350000, ListingType: "for_sale", Bedrooms: &three}
+	if err := db.Create(&house).Error; err != nil {
+		t.Fatalf("Failed to create property: %v", err)
+	}
+	service.RunOnce()
+	if len(sms.sent) != 0 {
+		t.Fatalf("Expected no SMS during quiet hours, got %d", len(sms.sent))
+	}
+	var pending int64
+	db.Model(&models.SavedSearchAlert{}).Where("status = ?", models.SavedSearchAlertPending).Count(&pending)
+	if pending != 1 {
+		t.Errorf("Expected one held alert, got %d", pending)
+	}
+
+	service.SetQuietHours(0, 0, time.Local)
+	service.RunOnce()
+	if len(sms.sent) != 1 || <-sms.sent != "+17135550100" {
+		t.Errorf("Expected the held SMS alert after quiet hours")
+	}
+	if len(email.sent) != 0 {
+		t.Errorf("Expected the sale listing not to alert the rental search, got %d", len(email.sent))
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+)
+
+// ScoreFactor is one input to a score: its raw value, the weight applied to
+// it, and the points it contributed. Factors may break down into sub-factors.
+type ScoreFactor struct {
+	Name    string        `json:"name"`
+	Value   float64       `json:"value"`
+	Weight  float64       `json:"weight"`
+	Points  float64       `json:"points"`
+	Detail  string        `json:"detail,omitempty"`
+	Factors []ScoreFactor `json:"factors,omitempty"`
+}
+
+// ScoreExplanation describes how a score and its label were reached, so a
+// reviewer can see why a lead landed in a segment or priority
+type ScoreExplanation struct {
+	Score      float64            `json:"score"`
+	Label      string             `json:"label"`
+	Factors    []ScoreFactor      `json:"factors"`
+	Thresholds map[string]float64 `json:"thresholds,omitempty"`
+}
+
+// ExplainScore breaks a lead's current behavioral score into the factors that
+// produced it. It reads the lead's events but does not save a new score.
+func (e *BehavioralScoringEngine) ExplainScore(leadID int64) (*ScoreExplanation, error) {
+	var events []models.BehavioralEvent
+	if err := e.db.Where("lead_id = ?", leadID).
+		Order("created_at DESC").
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return e.explainEvents(events, time.Now()), nil
+}
+
+// explainEvents mirrors CalculateScore, recording each factor's contribution
+func (e *BehavioralScoringEngine) explainEvents(events []models.BehavioralEvent, now time.Time) *ScoreExplanation {
+	urgencyScore := e.calculateUrgencyScore(events)
+	engagementScore := e.calculateEngagementScore(events)
+	financialScore := e.calculateFinancialScore(events)
+
+	urgency := ScoreFactor{
+		Name:    "urgency",
+		Value:   float64(urgencyScore),
+		Weight:  urgencyScoreWeight,
+		Points:  float64(urgencyScore) * urgencyScoreWeight,
+		Detail:  "Event points decayed by age, capped at 100",
+		Factors: e.explainUrgencyEvents(events, now),
+	}
+
+	engagement := ScoreFactor{
+		Name:   "engagement",
+		Value:  float64(engagementScore),
+		Weight: engagementScoreWeight,
+		Points: float64(engagementScore) * engagementScoreWeight,
+		Detail: "Event frequency plus recency of last activity, capped at 100",
+	}
+	if len(events) > 0 {
+		frequency := float64(len(events)) * 2.0
+		if frequency > 50 {
+			frequency = 50
+		}
+		engagement.Factors = []ScoreFactor{
+			{Name: "frequency", Value: float64(len(events)), Weight: 2.0, Points: frequency, Detail: "2 points per event, capped at 50"},
+			{Name: "recency", Value: now.Sub(events[0].CreatedAt).Hours() / 24, Weight: 1, Points: float64(engagementScore) - frequency, Detail: "Days since last activity"},
+		}
+	}
+
+	financial := ScoreFactor{
+		Name:   "financial",
+		Value:  float64(financialScore),
+		Weight: financialScoreWeight,
+		Points: float64(financialScore) * financialScoreWeight,
+		Detail: "50 points per application and 20 per inquiry, capped at 100",
+	}
+	applications, inquiries := 0, 0
+	for _, event := range events {
+		switch event.EventType {
+		case "application":
+			applications++
+		case "inquiry":
+			inquiries++
+		}
+	}
+	if applications > 0 {
+		financial.Factors = append(financial.Factors, ScoreFactor{Name: "application", Value: float64(applications), Weight: 50, Points: float64(applications * 50)})
+	}
+	if inquiries > 0 {
+		financial.Factors = append(financial.Factors, ScoreFactor{Name: "inquiry", Value: float64(inquiries), Weight: 20, Points: float64(inquiries * 20)})
+	}
+
+	compositeScore := int(urgency.Points + engagement.Points + financial.Points)
+	return &ScoreExplanation{
+		Score:   float64(compositeScore),
+		Label:   e.determineSegment(compositeScore),
+		Factors: []ScoreFactor{urgency, engagement, financial},
+		Thresholds: map[string]float64{
+			"hot":  70,
+			"warm": 40,
+			"cold": 10,
+		},
+	}
+}
+
+// explainUrgencyEvents totals decayed points per event type, largest first
+func (e *BehavioralScoringEngine) explainUrgencyEvents(events []models.BehavioralEvent, now time.Time) []ScoreFactor {
+	byType := map[string]*ScoreFactor{}
+	for _, event := range events {
+		factor, exists := byType[event.EventType]
+		if !exists {
+			factor = &ScoreFactor{Name: event.EventType, Weight: float64(e.scoringRules.GetPoints(event.EventType))}
+			byType[event.EventType] = factor
+		}
+		daysSince := now.Sub(event.CreatedAt).Hours() / 24
+		factor.Value++
+		factor.Points += factor.Weight * e.calculateDecayFactor(daysSince)
+	}
+
+	factors := make([]ScoreFactor, 0, len(byType))
+	for _, factor := range byType {
+		factor.Detail = fmt.Sprintf("%.0f event(s) at %.0f points before decay", factor.Value, factor.Weight)
+		factors = append(factors, *factor)
+	}
+	sort.Slice(factors, func(i, j int) bool {
+		if factors[i].Points != factors[j].Points {
+			return factors[i].Points > factors[j].Points
+		}
+		return factors[i].Name < factors[j].Name
+	})
+	return factors
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+)
+
+func TestExplainEvents_FactorPointsAddUpToScore(t *testing.T) {
+	engine := NewBehavioralScoringEngine(nil)
+	now := time.Now()
+	events := []models.BehavioralEvent{
+		{EventType: "application", CreatedAt: now.Add(-1 * time.Hour)},
+		{EventType: "inquired", CreatedAt: now.Add(-2 * time.Hour)},
+		{EventType: "viewed", CreatedAt: now.Add(-3 * 24 * time.Hour)},
+		{EventType: "viewed", CreatedAt: now.Add(-10 * 24 * time.Hour)},
+	}
+
+	explanation := engine.explainEvents(events, now)
+
+	total := 0.0
+	for _, factor := range explanation.Factors {
+		total += factor.Points
+	}
+	if int(total) != int(explanation.Score) {
+		t.Errorf("Expected factor points %.2f to add up to score %.0f", total, explanation.Score)
+	}
+	if explanation.Label != engine.determineSegment(int(explanation.Score)) {
+		t.Errorf("Expected label to match segment, got %s", explanation.Label)
+	}
+
+	urgency := explanation.Factors[0]
+	if urgency.Name != "urgency" || len(urgency.Factors) != 3 {
+		t.Fatalf("Expected urgency broken down by 3 event types, got %+v", urgency)
+	}
+	if urgency.Factors[0].Name != "inquired" {
+		t.Errorf("Expected the largest urgency contributor first, got %s", urgency.Factors[0].Name)
+	}
+	viewed := urgency.Factors[1]
+	if viewed.Name != "viewed" || viewed.Value != 2 || math.Abs(viewed.Points-(5*0.8+5*0.5)) > 1e-9 {
+		t.Errorf("Expected two decayed views, got %+v", viewed)
+	}
+
+	financial := explanation.Factors[2]
+	if financial.Value != 50 || len(financial.Factors) != 1 || financial.Factors[0].Name != "application" {
+		t.Errorf("Expected financial score from one application, got %+v", financial)
+	}
+}
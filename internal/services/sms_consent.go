@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/gorm"
+)
+
+// SMSConsentDecision describes whether an SMS may be sent to a number and why
+type SMSConsentDecision struct {
+	Allowed       bool                 `json:"allowed"`
+	Reason        string               `json:"reason,omitempty"`
+	Basis         string               `json:"basis,omitempty"` // express_consent, opt_in
+	LeadID        *uint                `json:"lead_id,omitempty"`
+	ConsentStatus models.ConsentStatus `json:"consent_status,omitempty"`
+	ConsentMethod string               `json:"consent_method,omitempty"`
+	ConsentDate   *time.Time           `json:"consent_date,omitempty"`
+}
+
+// SMSConsentService gates outbound SMS on documented TCPA consent
+type SMSConsentService struct {
+	db                *gorm.DB
+	encryptionManager *security.EncryptionManager
+}
+
+// NewSMSConsentService creates a new SMS consent service
+func NewSMSConsentService(db *gorm.DB, encryptionManager *security.EncryptionManager) *SMSConsentService {
+	return &SMSConsentService{
+		db:                db,
+		encryptionManager: encryptionManager,
+	}
+}
+
+// CheckConsent looks up the lead for a phone number and decides whether SMS is permitted.
+// Only express written consent or a recorded opt-in qualifies; DNC and revoked consent always block.
+func (s *SMSConsentService) CheckConsent(phone string) (SMSConsentDecision, error) {
+	if normalizePhoneDigits(phone) == "" {
+		return SMSConsentDecision{Reason: "invalid phone number"}, nil
+	}
+
+	dnc := NewDNCService(s.db, s.encryptionManager)
+	leads, err := s.findLeadsByPhone(phone)
+	if err != nil {
+		return SMSConsentDecision{}, err
+	}
+
+	// The imported DNC list blocks numbers with or without a lead record
+	var leadID *uint
+	if len(leads) > 0 {
+		leadID = &leads[0].ID
+	}
+	if err := dnc.CheckSMS(phone, "sms_consent", leadID); err != nil {
+		if errors.Is(err, ErrRecipientOnDNC) {
+			return SMSConsentDecision{LeadID: leadID, Reason: "phone number is on the do-not-contact list"}, nil
+		}
+		return SMSConsentDecision{}, err
+	}
+	if len(leads) == 0 {
+		return SMSConsentDecision{Reason: "no consent record exists for this phone number"}, nil
+	}
+
+	// Several leads can share a number; an opt-out on any of them wins over
+	// consent recorded on another
+	for i := range leads {
+		lead := &leads[i]
+		switch {
+		case lead.OnDNCList:
+			decision := smsConsentDecisionFor(lead)
+			decision.Reason = "phone number is on the do-not-contact list"
+			dnc.LogSuppression("sms", "sms_consent", phone, decision.LeadID, decision.Reason)
+			return decision, nil
+		case lead.ConsentStatus == models.ConsentRevoked:
+			decision := smsConsentDecisionFor(lead)
+			decision.Reason = "recipient has revoked consent"
+			return decision, nil
+		}
+	}
+
+	for i := range leads {
+		if leads[i].ConsentStatus == models.ConsentExpress {
+			decision := smsConsentDecisionFor(&leads[i])
+			decision.Allowed = true
+			decision.Basis = "express_consent"
+			return decision, nil
+		}
+	}
+	for i := range leads {
+		if leads[i].OptedIn {
+			decision := smsConsentDecisionFor(&leads[i])
+			decision.Allowed = true
+			decision.Basis = "opt_in"
+			if decision.ConsentDate == nil {
+				decision.ConsentDate = leads[i].OptInDate
+			}
+			return decision, nil
+		}
+	}
+
+	decision := smsConsentDecisionFor(&leads[0])
+	decision.Reason = fmt.Sprintf("consent status is %q; SMS requires express written consent or an opt-in", leads[0].ConsentStatus)
+	return decision, nil
+}
+
+// smsConsentDecisionFor starts a decision from the lead's consent record
+func smsConsentDecisionFor(lead *models.LeadReengagement) SMSConsentDecision {
+	return SMSConsentDecision{
+		LeadID:        &lead.ID,
+		ConsentStatus: lead.ConsentStatus,
+		ConsentMethod: lead.ConsentMethod,
+		ConsentDate:   lead.ConsentDate,
+	}
+}
+
+// RecordSend writes an SMS send-log row capturing the consent decision used
+func (s *SMSConsentService) RecordSend(phone string, decision SMSConsentDecision, status, reason string, messageLength int) error {
+	toPhone := security.EncryptedString(phone)
+	if s.encryptionManager != nil {
+		encrypted, err := s.encryptionManager.EncryptPhone(phone)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt phone: %w", err)
+		}
+		toPhone = encrypted
+	}
+
+	entry := models.SMSSendLog{
+		ToPhone:            toPhone,
+		LeadReengagementID: decision.LeadID,
+		Status:             status,
+		Reason:             reason,
+		MessageLength:      messageLength,
+		ConsentBasis:       decision.Basis,
+		ConsentStatus:      decision.ConsentStatus,
+		ConsentMethod:      decision.ConsentMethod,
+		ConsentDate:        decision.ConsentDate,
+	}
+	return s.db.Create(&entry).Error
+}
+
+// findLeadsByPhone returns every lead with the phone number, matched on the
+// phone blind index since encrypted values can't be queried
+func (s *SMSConsentService) findLeadsByPhone(phone string) ([]models.LeadReengagement, error) {
+	var leads []models.LeadReengagement
+	if err := s.db.Scopes(models.LeadsWithPhone(s.encryptionManager, phone)).Order("id").Find(&leads).Error; err != nil {
+		return nil, fmt.Errorf("failed to load leads: %w", err)
+	}
+	return leads, nil
+}
+
+// normalizePhoneDigits reduces a phone number to its last 10 digits, or "" if it has fewer
+func normalizePhoneDigits(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	normalized := digits.String()
+	if len(normalized) > 10 {
+		normalized = normalized[len(normalized)-10:]
+	}
+	if len(normalized) < 10 {
+		return ""
+	}
+	return normalized
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+
+	"chrisgross-ctrl-project/internal/models"
+	"chrisgross-ctrl-project/internal/security"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSMSConsent_BlocksWhenAnyLeadWithThePhoneOptedOut(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&security.EncryptionKey{}, &models.LeadReengagement{}, &models.DNCEntry{},
+		&models.DNCSuppressionLog{}, &models.SMSSendLog{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	t.Setenv("ENCRYPTION_KEY", newTestEncryptionKey(t))
+	em, err := security.NewEncryptionManager(db)
+	if err != nil {
+		t.Fatalf("Failed to create encryption manager: %v", err)
+	}
+
+	newLead := func(fubID, phone string, status models.ConsentStatus, onDNC bool) models.LeadReengagement {
+		encrypted, err := em.EncryptPhone(phone)
+		if err != nil {
+			t.Fatalf("Failed to encrypt phone: %v", err)
+		}
+		lead := models.LeadReengagement{FUBContactID: fubID, Phone: encrypted, ConsentStatus: status, OnDNCList: onDNC}
+		lead.SetBlindIndexes(em, "", "", "", phone)
+		if err := db.Create(&lead).Error; err != nil {
+			t.Fatalf("Failed to store lead: %v", err)
+		}
+		return lead
+	}
+	consenting := newLead("fub-1", "(713) 555-0100", models.ConsentExpress, false)
+	newLead("fub-2", "713-555-0100", models.ConsentRevoked, false)
+	newLead("fub-3", "713-555-0200", models.ConsentExpress, false)
+	newLead("fub-4", "+1 713 555 0200", models.ConsentExpress, true)
+	newLead("fub-5", "713-555-0300", models.ConsentExpress, false)
+
+	service := NewSMSConsentService(db, em)
+	for _, tc := range []struct {
+		phone   string
+		allowed bool
+		reason  string
+	}{
+		{"7135550100", false, "recipient has revoked consent"},
+		{"+17135550200", false, "phone number is on the do-not-contact list"},
+		{"713.555.0300", true, ""},
+		{"713-555-0400", false, "no consent record exists for this phone number"},
+		{"555-0100", false, "invalid phone number"},
+	} {
+		decision, err := service.CheckConsent(tc.phone)
+		if err != nil {
+			t.Fatalf("CheckConsent(%q) failed: %v", tc.phone, err)
+		}
+		if decision.Allowed != tc.allowed || decision.Reason != tc.reason {
+			t.Errorf("CheckConsent(%q) = allowed %v, reason %q; expected %v, %q", tc.phone, decision.Allowed, decision.Reason, tc.allowed, tc.reason)
+		}
+	}
+
+	// Once no lead with the number has opted out, consent on any of them allows the send
+	db.Model(&models.LeadReengagement{}).Where("fub_contact_id = ?", "fub-2").Update("consent_status", models.ConsentExpress)
+	decision, err := service.CheckConsent("713-555-0100")
+	if err != nil {
+		t.Fatalf("CheckConsent failed: %v", err)
+	}
+	if !decision.Allowed || decision.Basis != "express_consent" || decision.LeadID == nil || *decision.LeadID != consenting.ID {
+		t.Errorf("Expected express consent from the first matching lead, got %+v", decision)
+	}
+}
@@ -0,0 +1,372 @@
+package services
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SMTPPoolConfig sizes the pooled SMTP sender
+type SMTPPoolConfig struct {
+	MaxConnections           int           // Open connections at most; senders wait for a free one
+	IdleTimeout              time.Duration // Keep-alive: idle connections older than this are closed instead of reused
+	MaxMessagesPerConnection int           // Reconnect after this many messages; 0 for no limit
+	DialTimeout              time.Duration
+	MaxAttempts              int           // Tries per message on 421 or a dropped connection
+	BackoffBase              time.Duration // First wait after a 421; doubles on each one in a row
+	BackoffMax               time.Duration
+}
+
+// DefaultSMTPPoolConfig suits a typical relay that allows a handful of
+// connections per client and closes idle ones after about a minute
+func DefaultSMTPPoolConfig() SMTPPoolConfig {
+	return SMTPPoolConfig{
+		MaxConnections:           4,
+		IdleTimeout:              30 * time.Second,
+		MaxMessagesPerConnection: 100,
+		DialTimeout:              10 * time.Second,
+		MaxAttempts:              3,
+		BackoffBase:              time.Second,
+		BackoffMax:               time.Minute,
+	}
+}
+
+// ErrSMTPDeliveryUnconfirmed means the connection failed after the message
+// data was sent, so the server may have accepted it. It is not retried.
+var ErrSMTPDeliveryUnconfirmed = errors.New("SMTP delivery unconfirmed")
+
+// ErrSMTPPoolClosed means Send was called after Close
+var ErrSMTPPoolClosed = errors.New("SMTP pool is closed")
+
+// SMTPPoolStats is a snapshot of the pool
+type SMTPPoolStats struct {
+	Open         int        `json:"open"`
+	Idle         int        `json:"idle"`
+	InUse        int        `json:"in_use"`
+	Dials        int64      `json:"dials"`
+	Reuses       int64      `json:"reuses"`
+	Sent         int64      `json:"sent"`
+	Errors       int64      `json:"errors"`
+	Backoffs     int64      `json:"backoffs"` // 421 responses
+	BackoffUntil *time.Time `json:"backoff_until,omitempty"`
+}
+
+// smtpPoolConn is a connection and how much it has been used
+type smtpPoolConn struct {
+	client   *smtp.Client
+	messages int
+	lastUsed time.Time
+}
+
+// SMTPPool sends mail over a bounded set of reused SMTP connections instead
+// of a TLS handshake and login per message. A 421 (service unavailable,
+// usually a per-client connection limit) closes the connection and makes
+// every sender wait with exponential backoff before trying again.
+type SMTPPool struct {
+	smtpConfig SMTPConfig
+	config     SMTPPoolConfig
+	dial       func() (*smtp.Client, error)
+
+	slots chan struct{}      // One per open connection
+	idle  chan *smtpPoolConn // Connections ready for reuse
+
+	mutex        sync.Mutex
+	stats        SMTPPoolStats
+	backoffLevel int
+	backoffUntil time.Time
+	closed       bool
+}
+
+// NewSMTPPool creates a pooled SMTP sender. Connections are opened on demand.
+func NewSMTPPool(smtpConfig SMTPConfig, config SMTPPoolConfig) *SMTPPool {
+	defaults := DefaultSMTPPoolConfig()
+	if config.MaxConnections <= 0 {
+		config.MaxConnections = defaults.MaxConnections
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = defaults.IdleTimeout
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = defaults.DialTimeout
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.BackoffBase <= 0 {
+		config.BackoffBase = defaults.BackoffBase
+	}
+	if config.BackoffMax <= 0 {
+		config.BackoffMax = defaults.BackoffMax
+	}
+
+	p := &SMTPPool{
+		smtpConfig: smtpConfig,
+		config:     config,
+		slots:      make(chan struct{}, config.MaxConnections),
+		idle:       make(chan *smtpPoolConn, config.MaxConnections),
+	}
+	p.dial = p.dialSMTP
+	return p
+}
+
+// Send delivers a raw RFC 5322 message to the recipients, retrying on a 421
+// or a dropped connection
+func (p *SMTPPool) Send(from string, to []string, msg []byte) error {
+	var err error
+	for attempt := 1; attempt <= p.config.MaxAttempts; attempt++ {
+		p.waitForBackoff()
+
+		var conn *smtpPoolConn
+		conn, err = p.acquire()
+		if err != nil {
+			p.recordError(err)
+			if !retryableSMTPError(err) {
+				return err
+			}
+			continue
+		}
+
+		err = p.deliver(conn, from, to, msg)
+		if err == nil {
+			p.mutex.Lock()
+			p.stats.Sent++
+			p.backoffLevel = 0
+			p.mutex.Unlock()
+			p.release(conn, true)
+			return nil
+		}
+
+		p.recordError(err)
+		if !retryableSMTPError(err) {
+			// The server refused this message or may already have it; keep
+			// the connection if it still answers
+			keep := conn.client.Reset() == nil
+			p.release(conn, keep)
+			return err
+		}
+		p.release(conn, false)
+	}
+	return fmt.Errorf("SMTP send failed after %d attempts: %w", p.config.MaxAttempts, err)
+}
+
+// Stats returns a snapshot of the pool
+func (p *SMTPPool) Stats() SMTPPoolStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	stats := p.stats
+	stats.Open = len(p.slots)
+	stats.Idle = len(p.idle)
+	stats.InUse = stats.Open - stats.Idle
+	if time.Now().Before(p.backoffUntil) {
+		until := p.backoffUntil
+		stats.BackoffUntil = &until
+	}
+	return stats
+}
+
+// Close quits the idle connections. Connections in use are closed when
+// they are released, and later sends fail with ErrSMTPPoolClosed.
+func (p *SMTPPool) Close() {
+	p.mutex.Lock()
+	p.closed = true
+	p.mutex.Unlock()
+
+	for {
+		select {
+		case conn := <-p.idle:
+			conn.client.Quit()
+			<-p.slots
+		default:
+			return
+		}
+	}
+}
+
+// acquire returns an idle connection, or dials one when the pool has room
+func (p *SMTPPool) acquire() (*smtpPoolConn, error) {
+	p.mutex.Lock()
+	closed := p.closed
+	p.mutex.Unlock()
+	if closed {
+		return nil, ErrSMTPPoolClosed
+	}
+
+	// Prefer an idle connection over dialing, which a server at its
+	// connection limit would refuse
+	var conn *smtpPoolConn
+	select {
+	case conn = <-p.idle:
+	default:
+		select {
+		case conn = <-p.idle:
+		case p.slots <- struct{}{}:
+			return p.open()
+		}
+	}
+
+	if time.Since(conn.lastUsed) < p.config.IdleTimeout {
+		p.mutex.Lock()
+		p.stats.Reuses++
+		p.mutex.Unlock()
+		return conn, nil
+	}
+	// The server has likely closed it; replace it in the same slot
+	conn.client.Close()
+	return p.open()
+}
+
+// open dials a connection in a slot the caller already holds
+func (p *SMTPPool) open() (*smtpPoolConn, error) {
+	client, err := p.dial()
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+	p.mutex.Lock()
+	p.stats.Dials++
+	p.mutex.Unlock()
+	return &smtpPoolConn{client: client, lastUsed: time.Now()}, nil
+}
+
+// release returns a healthy connection to the pool or closes it
+func (p *SMTPPool) release(conn *smtpPoolConn, keep bool) {
+	conn.lastUsed = time.Now()
+	if keep && (p.config.MaxMessagesPerConnection <= 0 || conn.messages < p.config.MaxMessagesPerConnection) {
+		// Checked under the lock so Close can't drain the idle connections
+		// between the check and the hand-back
+		p.mutex.Lock()
+		if !p.closed {
+			p.idle <- conn
+			p.mutex.Unlock()
+			return
+		}
+		p.mutex.Unlock()
+	}
+
+	if keep {
+		conn.client.Quit()
+	} else {
+		conn.client.Close()
+	}
+	<-p.slots
+}
+
+// deliver runs one mail transaction on the connection
+func (p *SMTPPool) deliver(conn *smtpPoolConn, from string, to []string, msg []byte) error {
+	conn.messages++
+	if err := conn.client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := conn.client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	w, err := conn.client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		var protocolErr *textproto.Error
+		if errors.As(err, &protocolErr) {
+			return err
+		}
+		// The reply to the end of data was lost, not the message
+		return fmt.Errorf("%w: %v", ErrSMTPDeliveryUnconfirmed, err)
+	}
+	return nil
+}
+
+// dialSMTP connects, upgrades to TLS when offered and logs in
+func (p *SMTPPool) dialSMTP() (*smtp.Client, error) {
+	cfg := p.smtpConfig
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	tlsConfig := &tls.Config{ServerName: cfg.Host}
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: p.config.DialTimeout}
+	if cfg.UseTLS && cfg.Port == 465 {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok && !(cfg.UseTLS && cfg.Port == 465) {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	if cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// recordError counts the error and starts a backoff on a 421
+func (p *SMTPPool) recordError(err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.stats.Errors++
+
+	var protocolErr *textproto.Error
+	if !errors.As(err, &protocolErr) || protocolErr.Code != 421 {
+		return
+	}
+	p.stats.Backoffs++
+	delay := p.config.BackoffBase << p.backoffLevel
+	if delay <= 0 || delay > p.config.BackoffMax {
+		delay = p.config.BackoffMax
+	} else {
+		p.backoffLevel++
+	}
+	if until := time.Now().Add(delay); until.After(p.backoffUntil) {
+		p.backoffUntil = until
+	}
+	log.Printf("⚠️ SMTP server returned 421, backing off %s: %v", delay, err)
+}
+
+// waitForBackoff blocks while the pool is backing off after a 421
+func (p *SMTPPool) waitForBackoff() {
+	p.mutex.Lock()
+	wait := time.Until(p.backoffUntil)
+	p.mutex.Unlock()
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// retryableSMTPError reports whether a message may succeed on another
+// connection: a 421 or a network failure rather than a refusal of the message.
+// A failure once the data was sent is not retried, since the server may
+// already have the message.
+func retryableSMTPError(err error) bool {
+	var protocolErr *textproto.Error
+	if errors.As(err, &protocolErr) {
+		return protocolErr.Code == 421
+	}
+	return !errors.Is(err, ErrSMTPDeliveryUnconfirmed) && !errors.Is(err, ErrSMTPPoolClosed)
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer accepts mail without delivering it. connectDelay stands in
+// for the TCP, TLS and AUTH round trips of a real relay; connections beyond
+// maxConnections are refused with a 421 like a relay's per-client limit.
+type fakeSMTPServer struct {
+	listener       net.Listener
+	connectDelay   time.Duration
+	maxConnections int32
+
+	active      int32
+	connections int32
+	messages    int32
+	dropReplies int32        // Hang up instead of answering the end of this many messages
+	rejectRcpt  atomic.Value // Recipient refused with a 450
+}
+
+func newFakeSMTPServer(t testing.TB, connectDelay time.Duration, maxConnections int32) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := &fakeSMTPServer{listener: listener, connectDelay: connectDelay, maxConnections: maxConnections}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *fakeSMTPServer) config() SMTPConfig {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	return SMTPConfig{Host: host, Port: portNumber, From: "team@example.com"}
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	time.Sleep(s.connectDelay)
+	if active := atomic.AddInt32(&s.active, 1); s.maxConnections > 0 && active > s.maxConnections {
+		atomic.AddInt32(&s.active, -1)
+		fmt.Fprintf(conn, "421 4.7.0 Too many connections\r\n")
+		return
+	}
+	defer atomic.AddInt32(&s.active, -1)
+	atomic.AddInt32(&s.connections, 1)
+
+	reader := bufio.NewReader(conn)
+	fmt.Fprintf(conn, "220 fake ESMTP\r\n")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch command := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			fmt.Fprintf(conn, "250 fake\r\n")
+		case command == "DATA":
+			fmt.Fprintf(conn, "354 go ahead\r\n")
+			for {
+				if line, err = reader.ReadString('\n'); err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+			}
+			atomic.AddInt32(&s.messages, 1)
+			if atomic.AddInt32(&s.dropReplies, -1) >= 0 {
+				return
+			}
+			fmt.Fprintf(conn, "250 queued\r\n")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			if rejected, _ := s.rejectRcpt.Load().(string); rejected != "" && strings.Contains(command, strings.ToUpper(rejected)) {
+				fmt.Fprintf(conn, "450 mailbox busy\r\n")
+			} else {
+				fmt.Fprintf(conn, "250 ok\r\n")
+			}
+		case command == "QUIT":
+			fmt.Fprintf(conn, "221 bye\r\n")
+			return
+		default:
+			fmt.Fprintf(conn, "250 ok\r\n")
+		}
+	}
+}
+
+func TestSMTPPool_ReusesConnections(t *testing.T) {
+	server := newFakeSMTPServer(t, 0, 0)
+	pool := NewSMTPPool(server.config(), SMTPPoolConfig{MaxConnections: 2, MaxMessagesPerConnection: 20})
+	defer pool.Close()
+
+	msg, _ := buildRawEmail("team@example.com", "ann@example.com", "Hello", "<p>Hi</p>", "Hi", nil)
+	for i := 0; i < 50; i++ {
+		if err := pool.Send("team@example.com", []string{"ann@example.com"}, msg); err != nil {
+			t.Fatalf("Send %d failed: %v", i, err)
+		}
+	}
+
+	stats := pool.Stats()
+	if stats.Sent != 50 || stats.Dials != 3 || stats.Reuses != 47 || stats.Errors != 0 {
+		t.Errorf("Expected 50 sends over 3 connections of at most 20 messages, got %+v", stats)
+	}
+	if stats.Open != 1 || stats.Idle != 1 {
+		t.Errorf("Expected the last connection kept open and idle, got %+v", stats)
+	}
+	if messages, connections := atomic.LoadInt32(&server.messages), atomic.LoadInt32(&server.connections); messages != 50 || connections != 3 {
+		t.Errorf("Expected the server to receive 50 messages on 3 connections, got %d on %d", messages, connections)
+	}
+}
+
+func TestSMTPPool_BacksOffOnConnectionLimit(t *testing.T) {
+	server := newFakeSMTPServer(t, 0, 1)
+	pool := NewSMTPPool(server.config(), SMTPPoolConfig{
+		MaxConnections: 3,
+		MaxAttempts:    10,
+		BackoffBase:    5 * time.Millisecond,
+		BackoffMax:     20 * time.Millisecond,
+	})
+	defer pool.Close()
+
+	msg, _ := buildRawEmail("team@example.com", "ann@example.com", "Hello", "<p>Hi</p>", "Hi", nil)
+	var wg sync.WaitGroup
+	errs := make(chan error, 30)
+	for sender := 0; sender < 3; sender++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				errs <- pool.Send("team@example.com", []string{"ann@example.com"}, msg)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Expected every send to succeed after backing off, got %v", err)
+		}
+	}
+
+	stats := pool.Stats()
+	if stats.Sent != 30 || atomic.LoadInt32(&server.messages) != 30 {
+		t.Errorf("Expected 30 messages delivered, got %+v", stats)
+	}
+	if stats.Backoffs == 0 || stats.Backoffs != stats.Errors {
+		t.Errorf("Expected the refused connections counted as 421 backoffs, got %+v", stats)
+	}
+}
+
+func TestSMTPPool_DoesNotResendAfterData(t *testing.T) {
+	server := newFakeSMTPServer(t, 0, 0)
+	server.dropReplies = 1
+	pool := NewSMTPPool(server.config(), SMTPPoolConfig{MaxAttempts: 3, BackoffBase: time.Millisecond})
+	defer pool.Close()
+
+	msg, _ := buildRawEmail("team@example.com", "ann@example.com", "Hello", "<p>Hi</p>", "Hi", nil)
+	err := pool.Send("team@example.com", []string{"ann@example.com"}, msg)
+	if !errors.Is(err, ErrSMTPDeliveryUnconfirmed) {
+		t.Fatalf("Expected an unconfirmed delivery, got %v", err)
+	}
+	if messages := atomic.LoadInt32(&server.messages); messages != 1 {
+		t.Errorf("Expected the message sent once, got %d", messages)
+	}
+	if err := pool.Send("team@example.com", []string{"ann@example.com"}, msg); err != nil {
+		t.Fatalf("Expected the next send to succeed on a new connection, got %v", err)
+	}
+}
+
+func TestSMTPPool_ClosesConnectionsReleasedAfterClose(t *testing.T) {
+	server := newFakeSMTPServer(t, 0, 0)
+	pool := NewSMTPPool(server.config(), SMTPPoolConfig{MaxConnections: 2})
+
+	idle, err := pool.acquire()
+	if err != nil {
+		t.Fatalf("Failed to acquire a connection: %v", err)
+	}
+	inUse, err := pool.acquire()
+	if err != nil {
+		t.Fatalf("Failed to acquire a connection: %v", err)
+	}
+	pool.release(idle, true)
+
+	pool.Close()
+	if stats := pool.Stats(); stats.Open != 1 || stats.Idle != 0 {
+		t.Fatalf("Expected only the in-use connection left open, got %+v", stats)
+	}
+	pool.release(inUse, true)
+	if stats := pool.Stats(); stats.Open != 0 {
+		t.Errorf("Expected the connection closed on release, got %+v", stats)
+	}
+
+	msg, _ := buildRawEmail("team@example.com", "ann@example.com", "Hello", "<p>Hi</p>", "Hi", nil)
+	if err := pool.Send("team@example.com", []string{"ann@example.com"}, msg); !errors.Is(err, ErrSMTPPoolClosed) {
+		t.Errorf("Expected sends after Close to fail, got %v", err)
+	}
+	if dials := pool.Stats().Dials; dials != 2 {
+		t.Errorf("Expected no connection dialed after Close, got %d dials", dials)
+	}
+}
+
+func TestEmailBatch_RetryOnlySendsToUndeliveredRecipients(t *testing.T) {
+	server := newFakeSMTPServer(t, 0, 0)
+	server.rejectRcpt.Store("bob@example.com")
+	pool := NewSMTPPool(server.config(), SMTPPoolConfig{})
+	defer pool.Close()
+	service := &EmailBatchService{smtpPool: pool}
+
+	email := EmailJob{ID: "job-1", To: []string{"ann@example.com", "bob@example.com", "cy@example.com"}, Subject: "Hello", HTMLBody: "<p>Hi</p>"}
+	if success, err := service.sendEmail(&email); success || err == nil {
+		t.Fatal("Expected the refused recipient to fail the attempt")
+	}
+	if len(email.Delivered) != 1 || email.Delivered[0] != "ann@example.com" {
+		t.Fatalf("Expected only ann recorded as delivered, got %v", email.Delivered)
+	}
+
+	server.rejectRcpt.Store("")
+	if success, err := service.sendEmail(&email); !success || err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if messages := atomic.LoadInt32(&server.messages); messages != 3 {
+		t.Errorf("Expected one message per recipient, got %d", messages)
+	}
+	if len(email.Delivered) != 3 {
+		t.Errorf("Expected every recipient recorded as delivered, got %v", email.Delivered)
+	}
+}
+
+// BenchmarkSMTPPool1000 sends a 1000-email batch from four senders to a relay
+// that takes 20ms to accept a connection, opening a connection per email
+// (the unpooled cost) and with a pool of four reused connections:
+//
+//	go test -run '^$' -bench SMTPPool1000 -benchtime 1x ./internal/services
+func BenchmarkSMTPPool1000(b *testing.B) {
+	server := newFakeSMTPServer(b, 20*time.Millisecond, 0)
+	msg, _ := buildRawEmail("team@example.com", "ann@example.com", "Hello", "<p>Hi</p>", "Hi", nil)
+
+	for _, mode := range []struct {
+		name            string
+		messagesPerDial int
+	}{{"connection-per-email", 1}, {"pooled", 0}} {
+		b.Run(mode.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				pool := NewSMTPPool(server.config(), SMTPPoolConfig{MaxConnections: 4, MaxMessagesPerConnection: mode.messagesPerDial})
+				start := time.Now()
+				var wg sync.WaitGroup
+				for sender := 0; sender < 4; sender++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for j := 0; j < 250; j++ {
+							if err := pool.Send("team@example.com", []string{"ann@example.com"}, msg); err != nil {
+								b.Errorf("Send failed: %v", err)
+								return
+							}
+						}
+					}()
+				}
+				wg.Wait()
+				b.ReportMetric(1000/time.Since(start).Seconds(), "emails/s")
+				pool.Close()
+			}
+		})
+	}
+}
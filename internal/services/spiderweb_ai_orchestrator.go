@@ -1,9 +1,13 @@
 package services
 
 import (
+	"context"
 	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"chrisgross-ctrl-project/internal/models"
 	"gorm.io/gorm"
 )
 
@@ -19,6 +23,42 @@ type SpiderwebAIOrchestrator struct {
 	emailBatch             *EmailBatchService
 	abandonmentRecovery    *AbandonmentRecoveryService
 	cache                  *IntelligenceCacheService
+
+	// Automated cycle settings and state
+	workers    int
+	batchSize  int
+	interval   time.Duration
+	paused     atomic.Bool
+	stateMu    sync.RWMutex
+	lastWarmed time.Time // when WarmCache last finished looking for active leads
+	lastCycle  *IntelligenceCycleReport
+	done       chan struct{}
+}
+
+const (
+	defaultIntelligenceInterval  = 5 * time.Minute
+	defaultIntelligenceWorkers   = 4
+	defaultIntelligenceBatchSize = 100
+)
+
+// IntelligenceCycleReport summarizes one automated intelligence cycle
+type IntelligenceCycleReport struct {
+	StartedAt      time.Time `json:"started_at"`
+	DurationMs     int64     `json:"duration_ms"`
+	FullCycle      bool      `json:"full_cycle"`
+	LeadsProcessed int       `json:"leads_processed"`
+	LeadsFailed    int       `json:"leads_failed"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// IntelligenceAutomationStatus reports the automated cycle's settings and last run
+type IntelligenceAutomationStatus struct {
+	Running         bool                     `json:"running"`
+	Paused          bool                     `json:"paused"`
+	IntervalSeconds int64                    `json:"interval_seconds"`
+	Workers         int                      `json:"workers"`
+	BatchSize       int                      `json:"batch_size"`
+	LastCycle       *IntelligenceCycleReport `json:"last_cycle,omitempty"`
 }
 
 // NewSpiderwebAIOrchestrator creates and initializes the complete AI system
@@ -63,6 +103,9 @@ func NewSpiderwebAIOrchestrator(
 		emailBatch:          emailBatch,
 		abandonmentRecovery: abandonmentRecovery,
 		cache:               cache,
+		workers:             defaultIntelligenceWorkers,
+		batchSize:           defaultIntelligenceBatchSize,
+		interval:            defaultIntelligenceInterval,
 	}
 	
 	log.Println("✅ Spiderweb AI System initialized successfully")
@@ -179,8 +222,18 @@ func (sao *SpiderwebAIOrchestrator) GetPropertyMatches(leadID int64) ([]Property
 	return sao.propertyMatcher.FindMatchesForLead(leadID)
 }
 
-// AnalyzeLeadOpportunity analyzes a specific lead for opportunities
+// AnalyzeLeadOpportunity analyzes a specific lead for opportunities, served
+// from the intelligence cache until the lead's behavior changes
 func (sao *SpiderwebAIOrchestrator) AnalyzeLeadOpportunity(leadID int64) (map[string]interface{}, error) {
+	if !sao.cacheAvailable() {
+		return sao.analyzeLeadOpportunity(leadID)
+	}
+	return sao.cache.GetOrCompute(LeadInsightsKey(leadID), sao.cache.TTLs().Lead, func() (map[string]interface{}, error) {
+		return sao.analyzeLeadOpportunity(leadID)
+	})
+}
+
+func (sao *SpiderwebAIOrchestrator) analyzeLeadOpportunity(leadID int64) (map[string]interface{}, error) {
 	// Get property matches
 	matches, _ := sao.propertyMatcher.FindMatchesForLead(leadID)
 	
@@ -202,19 +255,216 @@ func (sao *SpiderwebAIOrchestrator) AnalyzeLeadOpportunity(leadID int64) (map[st
 	return analysis, nil
 }
 
-// StartAutomatedIntelligence starts the automated intelligence cycle (runs periodically)
-func (sao *SpiderwebAIOrchestrator) StartAutomatedIntelligence(intervalMinutes int) {
-	log.Printf("🤖 Starting automated intelligence cycle (every %d minutes)", intervalMinutes)
-	
-	ticker := time.NewTicker(time.Duration(intervalMinutes) * time.Minute)
-	defer ticker.Stop()
-	
-	// Run immediately on start
-	sao.RunIntelligenceCycle()
-	
-	// Then run on interval
-	for range ticker.C {
+// Cache returns the intelligence cache, which may be nil
+func (sao *SpiderwebAIOrchestrator) Cache() *IntelligenceCacheService {
+	return sao.cache
+}
+
+func (sao *SpiderwebAIOrchestrator) cacheAvailable() bool {
+	return sao.cache != nil && sao.cache.IsAvailable()
+}
+
+// SetConcurrency sets how many leads the automated cycle warms at once and
+// how many it loads per batch; non-positive values keep the current setting
+func (sao *SpiderwebAIOrchestrator) SetConcurrency(workers, batchSize int) {
+	sao.stateMu.Lock()
+	defer sao.stateMu.Unlock()
+	if workers > 0 {
+		sao.workers = workers
+	}
+	if batchSize > 0 {
+		sao.batchSize = batchSize
+	}
+}
+
+// PauseAutomation skips automated cycles until ResumeAutomation is called;
+// a cycle already running finishes
+func (sao *SpiderwebAIOrchestrator) PauseAutomation() {
+	if !sao.paused.Swap(true) {
+		log.Println("⏸️ Automated intelligence cycle paused")
+	}
+}
+
+// ResumeAutomation lets automated cycles run again from the next tick
+func (sao *SpiderwebAIOrchestrator) ResumeAutomation() {
+	if sao.paused.Swap(false) {
+		log.Println("▶️ Automated intelligence cycle resumed")
+	}
+}
+
+// AutomationStatus reports the automated cycle's settings and its last run
+func (sao *SpiderwebAIOrchestrator) AutomationStatus() IntelligenceAutomationStatus {
+	sao.stateMu.RLock()
+	defer sao.stateMu.RUnlock()
+	return IntelligenceAutomationStatus{
+		Running:         sao.done != nil,
+		Paused:          sao.paused.Load(),
+		IntervalSeconds: int64(sao.interval.Seconds()),
+		Workers:         sao.workers,
+		BatchSize:       sao.batchSize,
+		LastCycle:       sao.lastCycle,
+	}
+}
+
+// WarmCache recomputes lead analyses missing from the cache for leads with
+// behavioral events since the last warm, loading leads in batches and
+// analyzing each batch with a bounded worker pool. Leads whose entries are
+// still cached are left alone. It stops between leads when ctx is cancelled.
+func (sao *SpiderwebAIOrchestrator) WarmCache(ctx context.Context) (warmed, failed int, err error) {
+	if !sao.cacheAvailable() {
+		return 0, 0, nil
+	}
+
+	sao.stateMu.RLock()
+	since, workers, batchSize := sao.lastWarmed, sao.workers, sao.batchSize
+	sao.stateMu.RUnlock()
+	if since.IsZero() {
+		since = time.Now().Add(-sao.cache.TTLs().Lead)
+	}
+	startedAt := time.Now()
+
+	var warmedCount, failedCount atomic.Int64
+	lastLeadID := int64(0)
+	for ctx.Err() == nil {
+		var leadIDs []int64
+		if err := sao.db.Model(&models.BehavioralEvent{}).
+			Where("created_at > ? AND lead_id > ?", since, lastLeadID).
+			Distinct().
+			Order("lead_id").
+			Limit(batchSize).
+			Pluck("lead_id", &leadIDs).Error; err != nil {
+			return int(warmedCount.Load()), int(failedCount.Load()), err
+		}
+		if len(leadIDs) == 0 {
+			break
+		}
+		lastLeadID = leadIDs[len(leadIDs)-1]
+
+		queue := make(chan int64)
+		var wg sync.WaitGroup
+		for i := 0; i < workers && i < len(leadIDs); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for leadID := range queue {
+					if sao.cache.Has(LeadInsightsKey(leadID)) {
+						continue
+					}
+					if _, err := sao.AnalyzeLeadOpportunity(leadID); err != nil {
+						log.Printf("⚠️ Failed to warm intelligence for lead %d: %v", leadID, err)
+						failedCount.Add(1)
+						continue
+					}
+					warmedCount.Add(1)
+				}
+			}()
+		}
+	feed:
+		for _, leadID := range leadIDs {
+			select {
+			case <-ctx.Done():
+				break feed
+			case queue <- leadID:
+			}
+		}
+		close(queue)
+		wg.Wait()
+
+		if len(leadIDs) < batchSize {
+			break
+		}
+	}
+
+	// An interrupted warm is picked up from the same point next time
+	if ctx.Err() == nil {
+		sao.stateMu.Lock()
+		sao.lastWarmed = startedAt
+		sao.stateMu.Unlock()
+	}
+	return int(warmedCount.Load()), int(failedCount.Load()), nil
+}
+
+// runAutomatedCycle warms the cache and runs campaign triggers, falling back
+// to the full intelligence cycle when the cache is unavailable or the last
+// full cycle has aged out of it
+func (sao *SpiderwebAIOrchestrator) runAutomatedCycle(ctx context.Context) {
+	report := &IntelligenceCycleReport{StartedAt: time.Now()}
+
+	if !sao.cacheAvailable() {
+		report.FullCycle = true
 		sao.RunIntelligenceCycle()
+	} else {
+		if !sao.cache.Has(KeyIntelligenceCycle) {
+			report.FullCycle = true
+			sao.RunIntelligenceCycle()
+			if err := sao.cache.set(KeyIntelligenceCycle, map[string]interface{}{"completed_at": time.Now()}, sao.cache.TTLs().Warm); err != nil {
+				log.Printf("⚠️ Failed to record intelligence cycle: %v", err)
+			}
+		} else if err := sao.campaignTriggers.RunAllTriggers(); err != nil {
+			log.Printf("❌ Error running campaign triggers: %v", err)
+		}
+
+		warmed, failed, err := sao.WarmCache(ctx)
+		if err != nil {
+			log.Printf("❌ Error warming intelligence cache: %v", err)
+			report.Error = err.Error()
+		}
+		report.LeadsProcessed = warmed
+		report.LeadsFailed = failed
+	}
+
+	duration := time.Since(report.StartedAt)
+	report.DurationMs = duration.Milliseconds()
+	log.Printf("🕸️ Automated intelligence cycle finished in %v (full cycle: %v, leads warmed: %d, failed: %d)",
+		duration.Round(time.Millisecond), report.FullCycle, report.LeadsProcessed, report.LeadsFailed)
+
+	sao.stateMu.Lock()
+	sao.lastCycle = report
+	sao.stateMu.Unlock()
+}
+
+// StartAutomatedIntelligence runs the automated intelligence cycle in the
+// background every interval, skipping ticks while paused, until ctx is cancelled
+func (sao *SpiderwebAIOrchestrator) StartAutomatedIntelligence(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultIntelligenceInterval
+	}
+	done := make(chan struct{})
+	sao.stateMu.Lock()
+	sao.interval = interval
+	sao.done = done
+	workers, batchSize := sao.workers, sao.batchSize
+	sao.stateMu.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if !sao.paused.Load() {
+				sao.runAutomatedCycle(ctx)
+			}
+			select {
+			case <-ctx.Done():
+				log.Println("🛑 Automated intelligence cycle stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	log.Printf("🤖 Starting automated intelligence cycle (every %v, %d workers, batches of %d)", interval, workers, batchSize)
+}
+
+// Stop waits for the automated cycle to exit once its context is cancelled
+func (sao *SpiderwebAIOrchestrator) Stop() {
+	sao.stateMu.RLock()
+	done := sao.done
+	sao.stateMu.RUnlock()
+	if done != nil {
+		<-done
 	}
 }
 
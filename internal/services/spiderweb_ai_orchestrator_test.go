@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSpiderwebAIOrchestrator_PausedAutomationSkipsCyclesAndStops(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	orchestrator := NewSpiderwebAIOrchestrator(db, NewBehavioralScoringEngine(db), nil, nil, nil, nil)
+	orchestrator.SetConcurrency(2, 0)
+	orchestrator.PauseAutomation()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	orchestrator.StartAutomatedIntelligence(ctx, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	status := orchestrator.AutomationStatus()
+	if !status.Running || !status.Paused || status.LastCycle != nil {
+		t.Errorf("Expected a running, paused loop with no cycles, got %+v", status)
+	}
+	if status.Workers != 2 || status.BatchSize != defaultIntelligenceBatchSize || status.IntervalSeconds != 0 {
+		t.Errorf("Expected 2 workers and the default batch size, got %+v", status)
+	}
+
+	cancel()
+	stopped := make(chan struct{})
+	go func() {
+		orchestrator.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Stop to return after the context was cancelled")
+	}
+}
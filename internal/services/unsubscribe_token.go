@@ -0,0 +1,80 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidUnsubscribeToken is returned for malformed or tampered unsubscribe tokens
+var ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+
+// UnsubscribeToken identifies the recipient and campaign email an unsubscribe
+// link was sent in. It carries IDs only, never the address itself.
+type UnsubscribeToken struct {
+	LeadID      uint
+	ExecutionID uint
+}
+
+// UnsubscribeToken returns a signed token for a lead and campaign execution:
+// base64url("v1.<lead>.<execution>") + "." + base64url(HMAC-SHA256)
+func (t *CampaignTracker) UnsubscribeToken(leadID, executionID uint) string {
+	payload := fmt.Sprintf("v1.%d.%d", leadID, executionID)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + t.signUnsubscribe(payload)
+}
+
+// UnsubscribeURL returns the signed unsubscribe link for a campaign email.
+// The same URL accepts the RFC 8058 one-click POST.
+func (t *CampaignTracker) UnsubscribeURL(leadID, executionID uint) string {
+	return fmt.Sprintf("%s/unsubscribe?token=%s", t.baseURL, url.QueryEscape(t.UnsubscribeToken(leadID, executionID)))
+}
+
+// ListUnsubscribeHeaders returns the RFC 2369 / RFC 8058 headers for a campaign email
+func (t *CampaignTracker) ListUnsubscribeHeaders(leadID, executionID uint) map[string]string {
+	return map[string]string{
+		"List-Unsubscribe":      "<" + t.UnsubscribeURL(leadID, executionID) + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+}
+
+// ParseUnsubscribeToken verifies a token's signature and returns its IDs
+func (t *CampaignTracker) ParseUnsubscribeToken(token string) (UnsubscribeToken, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return UnsubscribeToken{}, ErrInvalidUnsubscribeToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return UnsubscribeToken{}, ErrInvalidUnsubscribeToken
+	}
+	if !hmac.Equal([]byte(t.signUnsubscribe(string(payload))), []byte(signature)) {
+		return UnsubscribeToken{}, ErrInvalidUnsubscribeToken
+	}
+
+	parts := strings.Split(string(payload), ".")
+	if len(parts) != 3 || parts[0] != "v1" {
+		return UnsubscribeToken{}, ErrInvalidUnsubscribeToken
+	}
+	leadID, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil || leadID == 0 {
+		return UnsubscribeToken{}, ErrInvalidUnsubscribeToken
+	}
+	executionID, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return UnsubscribeToken{}, ErrInvalidUnsubscribeToken
+	}
+	return UnsubscribeToken{LeadID: uint(leadID), ExecutionID: uint(executionID)}, nil
+}
+
+// signUnsubscribe uses a separate HMAC domain from click signatures so one
+// can never be replayed as the other
+func (t *CampaignTracker) signUnsubscribe(payload string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte("unsubscribe|" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
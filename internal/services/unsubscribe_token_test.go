@@ -0,0 +1,54 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnsubscribeToken_RoundTripAndTamper(t *testing.T) {
+	tracker := NewCampaignTracker("https://example.com/", "secret")
+
+	token := tracker.UnsubscribeToken(42, 7)
+	parsed, err := tracker.ParseUnsubscribeToken(token)
+	if err != nil {
+		t.Fatalf("ParseUnsubscribeToken failed: %v", err)
+	}
+	if parsed.LeadID != 42 || parsed.ExecutionID != 7 {
+		t.Errorf("Expected lead 42 / execution 7, got %+v", parsed)
+	}
+
+	// A different secret, a swapped payload, or garbage must all be rejected
+	other := NewCampaignTracker("https://example.com", "other-secret")
+	if _, err := other.ParseUnsubscribeToken(token); err != ErrInvalidUnsubscribeToken {
+		t.Errorf("Expected token signed with another secret to be rejected, got %v", err)
+	}
+	forged := strings.SplitN(tracker.UnsubscribeToken(43, 7), ".", 2)[0] + "." + strings.SplitN(token, ".", 2)[1]
+	if _, err := tracker.ParseUnsubscribeToken(forged); err != ErrInvalidUnsubscribeToken {
+		t.Errorf("Expected forged lead ID to be rejected, got %v", err)
+	}
+	for _, bad := range []string{"", "abc", "abc.def", token + "x"} {
+		if _, err := tracker.ParseUnsubscribeToken(bad); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+
+	headers := tracker.ListUnsubscribeHeaders(42, 7)
+	if headers["List-Unsubscribe-Post"] != "List-Unsubscribe=One-Click" ||
+		!strings.HasPrefix(headers["List-Unsubscribe"], "<https://example.com/unsubscribe?token=") {
+		t.Errorf("Unexpected List-Unsubscribe headers: %v", headers)
+	}
+
+	raw, err := buildRawEmail("from@example.com", "to@example.com", "Hi", "<p>Hi</p>", "Hi", map[string]string{
+		"List-Unsubscribe": headers["List-Unsubscribe"] + "\r\nBcc: evil@example.com",
+	})
+	if err != nil {
+		t.Fatalf("buildRawEmail failed: %v", err)
+	}
+	message := string(raw)
+	if !strings.Contains(message, "\r\nList-Unsubscribe: <https://example.com/unsubscribe?token=") {
+		t.Errorf("Expected List-Unsubscribe header in raw message:\n%s", message)
+	}
+	if strings.Contains(message, "\r\nBcc:") {
+		t.Error("Expected header injection to be stripped")
+	}
+}
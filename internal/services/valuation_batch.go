@@ -0,0 +1,192 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxBulkValuationSize caps the number of properties accepted in one batch
+	MaxBulkValuationSize = 1000
+
+	// bulkValuationConcurrency limits parallel valuations per batch
+	bulkValuationConcurrency = 5
+)
+
+// ValuationStats summarizes bulk valuation activity
+type ValuationStats struct {
+	TotalBatches  int64   `json:"total_batches"`
+	ActiveBatches int64   `json:"active_batches"`
+	TotalRequests int64   `json:"total_requests"`
+	Completed     int64   `json:"completed"`
+	Pending       int64   `json:"pending"`
+	Failed        int64   `json:"failed"`
+	CacheHits     int64   `json:"cache_hits"`
+	AverageValue  float64 `json:"average_value"`
+	SavedReports  int64   `json:"saved_reports"`
+}
+
+// StartBulkValuation records a batch and values each property in the background
+func (pvs *PropertyValuationService) StartBulkValuation(requests []PropertyValuationRequest, requestedBy string) (*models.ValuationBatch, error) {
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("no properties provided")
+	}
+	if len(requests) > MaxBulkValuationSize {
+		return nil, fmt.Errorf("maximum %d properties allowed per batch", MaxBulkValuationSize)
+	}
+
+	batch := &models.ValuationBatch{
+		ID:          uuid.New().String(),
+		Status:      models.ValuationStatusPending,
+		Total:       len(requests),
+		RequestedBy: requestedBy,
+	}
+
+	results := make([]models.ValuationResult, len(requests))
+	for i, request := range requests {
+		results[i] = models.ValuationResult{
+			BatchID: batch.ID,
+			Address: request.Address,
+			City:    request.City,
+			ZipCode: request.ZipCode,
+			Request: structToJSONB(request),
+			Status:  models.ValuationStatusPending,
+		}
+	}
+
+	err := pvs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(&results, 100).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create valuation batch: %w", err)
+	}
+
+	go pvs.processBulkValuation(batch.ID, requests, results)
+
+	log.Printf("📦 Bulk valuation batch %s queued (%d properties)", batch.ID, len(requests))
+	return batch, nil
+}
+
+// processBulkValuation values every property in a batch with bounded concurrency
+func (pvs *PropertyValuationService) processBulkValuation(batchID string, requests []PropertyValuationRequest, results []models.ValuationResult) {
+	startedAt := time.Now()
+	pvs.db.Model(&models.ValuationBatch{}).Where("id = ?", batchID).Updates(map[string]interface{}{
+		"status":     models.ValuationStatusProcessing,
+		"started_at": startedAt,
+	})
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, bulkValuationConcurrency)
+
+	for i := range requests {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(request PropertyValuationRequest, resultID uint) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			pvs.processBulkValuationItem(batchID, request, resultID)
+		}(requests[i], results[i].ID)
+	}
+	wg.Wait()
+
+	completedAt := time.Now()
+	pvs.db.Model(&models.ValuationBatch{}).Where("id = ?", batchID).Updates(map[string]interface{}{
+		"status":       models.ValuationStatusCompleted,
+		"completed_at": completedAt,
+	})
+	log.Printf("✅ Bulk valuation batch %s finished in %v", batchID, completedAt.Sub(startedAt).Round(time.Second))
+}
+
+// processBulkValuationItem values one property and records the result and batch progress
+func (pvs *PropertyValuationService) processBulkValuationItem(batchID string, request PropertyValuationRequest, resultID uint) {
+	updates := map[string]interface{}{}
+	counter := "completed"
+
+	if reason := validateBulkValuationRequest(request); reason != "" {
+		updates["status"] = models.ValuationStatusFailed
+		updates["error"] = reason
+		counter = "failed"
+	} else if valuation, err := pvs.ValuateProperty(request); err != nil {
+		updates["status"] = models.ValuationStatusFailed
+		updates["error"] = err.Error()
+		counter = "failed"
+	} else {
+		updates["status"] = models.ValuationStatusCompleted
+		updates["estimated_value"] = valuation.EstimatedValue
+		updates["value_low"] = valuation.ValueRange.Low
+		updates["value_high"] = valuation.ValueRange.High
+		updates["confidence"] = valuation.ConfidenceScore
+		updates["cache_hit"] = valuation.CacheHit
+	}
+
+	if err := pvs.db.Model(&models.ValuationResult{}).Where("id = ?", resultID).Updates(updates).Error; err != nil {
+		log.Printf("⚠️ Failed to record valuation result %d: %v", resultID, err)
+	}
+	pvs.db.Model(&models.ValuationBatch{}).Where("id = ?", batchID).
+		Update(counter, gorm.Expr(counter+" + 1"))
+}
+
+// validateBulkValuationRequest returns why a property cannot be valued, or "" if it can
+func validateBulkValuationRequest(request PropertyValuationRequest) string {
+	switch {
+	case strings.TrimSpace(request.Address) == "":
+		return "address is required"
+	case request.City == "" && request.ZipCode == "":
+		return "city or zip_code is required"
+	case request.SquareFeet <= 0:
+		return "square_feet is required"
+	}
+	return ""
+}
+
+// GetBulkValuation returns a batch with its results so far
+func (pvs *PropertyValuationService) GetBulkValuation(batchID string) (*models.ValuationBatch, []models.ValuationResult, error) {
+	var batch models.ValuationBatch
+	if err := pvs.db.First(&batch, "id = ?", batchID).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var results []models.ValuationResult
+	if err := pvs.db.Where("batch_id = ?", batchID).Order("id ASC").Find(&results).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return &batch, results, nil
+}
+
+// GetValuationStats reports totals across bulk valuations and saved reports
+func (pvs *PropertyValuationService) GetValuationStats() (*ValuationStats, error) {
+	stats := &ValuationStats{}
+
+	if err := pvs.db.Model(&models.ValuationBatch{}).Count(&stats.TotalBatches).Error; err != nil {
+		return nil, err
+	}
+	pvs.db.Model(&models.ValuationBatch{}).
+		Where("status IN ?", []string{models.ValuationStatusPending, models.ValuationStatusProcessing}).
+		Count(&stats.ActiveBatches)
+
+	pvs.db.Model(&models.ValuationResult{}).Count(&stats.TotalRequests)
+	pvs.db.Model(&models.ValuationResult{}).Where("status = ?", models.ValuationStatusCompleted).Count(&stats.Completed)
+	pvs.db.Model(&models.ValuationResult{}).Where("status = ?", models.ValuationStatusFailed).Count(&stats.Failed)
+	pvs.db.Model(&models.ValuationResult{}).Where("cache_hit = ?", true).Count(&stats.CacheHits)
+	stats.Pending = stats.TotalRequests - stats.Completed - stats.Failed
+
+	pvs.db.Model(&models.ValuationResult{}).
+		Where("status = ?", models.ValuationStatusCompleted).
+		Select("COALESCE(AVG(estimated_value), 0)").
+		Scan(&stats.AverageValue)
+
+	pvs.db.Model(&models.PropertyValuationRecord{}).Count(&stats.SavedReports)
+
+	return stats, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultValuationCacheTTL is how long a valuation is reused for the same address
+	DefaultValuationCacheTTL = 24 * time.Hour
+
+	valuationCacheKeyPrefix    = "valuation:"
+	valuationCacheMaxEntries   = 1000
+	valuationCacheRedisTimeout = 2 * time.Second
+)
+
+// ValuationCacheStats reports valuation cache effectiveness
+type ValuationCacheStats struct {
+	Backend    string  `json:"backend"` // redis or memory
+	Hits       int64   `json:"hits"`
+	Misses     int64   `json:"misses"`
+	HitRate    float64 `json:"hit_rate"`
+	Entries    int     `json:"entries"` // in-memory entries only
+	TTLSeconds int64   `json:"ttl_seconds"`
+}
+
+// valuationCache stores valuations keyed by normalized address, in Redis when
+// available and in a bounded in-memory LRU otherwise
+type valuationCache struct {
+	redis *redis.Client
+	ttl   time.Duration
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type valuationCacheEntry struct {
+	key       string
+	valuation PropertyValuation
+	expiresAt time.Time
+}
+
+func newValuationCache(ttl time.Duration) *valuationCache {
+	return &valuationCache{
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// valuationCacheKey normalizes the address and includes the property details so a
+// changed request for the same address is not served a stale estimate
+func valuationCacheKey(request PropertyValuationRequest) string {
+	request.Address = strings.Join(strings.Fields(strings.ToLower(request.Address)), " ")
+	request.City = strings.ToLower(strings.TrimSpace(request.City))
+	request.ZipCode = strings.TrimSpace(request.ZipCode)
+	request.ForceRefresh = false
+
+	encoded, _ := json.Marshal(request)
+	sum := sha256.Sum256(encoded)
+	return valuationCacheKeyPrefix + hex.EncodeToString(sum[:16])
+}
+
+func (vc *valuationCache) get(key string) (*PropertyValuation, bool) {
+	if vc.redis != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), valuationCacheRedisTimeout)
+		defer cancel()
+
+		data, err := vc.redis.Get(ctx, key).Bytes()
+		if err == nil {
+			var valuation PropertyValuation
+			if err := json.Unmarshal(data, &valuation); err == nil {
+				vc.hits.Add(1)
+				return &valuation, true
+			}
+		} else if err != redis.Nil {
+			log.Printf("⚠️ Valuation cache read failed: %v", err)
+		}
+		vc.misses.Add(1)
+		return nil, false
+	}
+
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+
+	element, ok := vc.entries[key]
+	if !ok {
+		vc.misses.Add(1)
+		return nil, false
+	}
+
+	entry := element.Value.(*valuationCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		vc.order.Remove(element)
+		delete(vc.entries, key)
+		vc.misses.Add(1)
+		return nil, false
+	}
+
+	vc.order.MoveToFront(element)
+	vc.hits.Add(1)
+	valuation := entry.valuation
+	return &valuation, true
+}
+
+func (vc *valuationCache) set(key string, valuation *PropertyValuation) {
+	if vc.redis != nil {
+		data, err := json.Marshal(valuation)
+		if err != nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), valuationCacheRedisTimeout)
+		defer cancel()
+		if err := vc.redis.Set(ctx, key, data, vc.ttl).Err(); err != nil {
+			log.Printf("⚠️ Valuation cache write failed: %v", err)
+		}
+		return
+	}
+
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+
+	entry := &valuationCacheEntry{key: key, valuation: *valuation, expiresAt: time.Now().Add(vc.ttl)}
+	if element, ok := vc.entries[key]; ok {
+		element.Value = entry
+		vc.order.MoveToFront(element)
+		return
+	}
+
+	vc.entries[key] = vc.order.PushFront(entry)
+	for vc.order.Len() > valuationCacheMaxEntries {
+		oldest := vc.order.Back()
+		vc.order.Remove(oldest)
+		delete(vc.entries, oldest.Value.(*valuationCacheEntry).key)
+	}
+}
+
+func (vc *valuationCache) stats() ValuationCacheStats {
+	vc.mutex.Lock()
+	entries := vc.order.Len()
+	vc.mutex.Unlock()
+
+	stats := ValuationCacheStats{
+		Backend:    "memory",
+		Hits:       vc.hits.Load(),
+		Misses:     vc.misses.Load(),
+		Entries:    entries,
+		TTLSeconds: int64(vc.ttl.Seconds()),
+	}
+	if vc.redis != nil {
+		stats.Backend = "redis"
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/gorm"
+)
+
+// DefaultValuationChangeThresholdPercent is the minimum re-valuation change that raises a PriceChangeEvent
+const DefaultValuationChangeThresholdPercent = 5.0
+
+// SetNotificationHub enables admin notifications for significant valuation changes
+func (pvs *PropertyValuationService) SetNotificationHub(hub *AdminNotificationHub) {
+	pvs.notificationHub = hub
+}
+
+// SetWebhookService enables outbound webhooks for significant valuation changes
+func (pvs *PropertyValuationService) SetWebhookService(webhooks *OutboundWebhookService) {
+	pvs.webhooks = webhooks
+}
+
+// SetValuationChangeThreshold sets the percent change that raises a PriceChangeEvent
+func (pvs *PropertyValuationService) SetValuationChangeThreshold(percent float64) {
+	if percent > 0 {
+		pvs.changeThresholdPercent = percent
+	}
+}
+
+// valuationAddressKey normalizes an address so re-valuations of the same property match
+func valuationAddressKey(request PropertyValuationRequest) string {
+	address := strings.Join(strings.Fields(strings.ToLower(request.Address)), " ")
+	if address == "" {
+		return ""
+	}
+	return address + "|" + strings.TrimSpace(request.ZipCode)
+}
+
+// recordValuationChange stores the latest estimate for the address and raises a
+// PriceChangeEvent when it moved more than the configured threshold
+func (pvs *PropertyValuationService) recordValuationChange(request PropertyValuationRequest, valuation *PropertyValuation) {
+	key := valuationAddressKey(request)
+	if key == "" || valuation.EstimatedValue <= 0 {
+		return
+	}
+
+	var previous models.AddressValuation
+	err := pvs.db.Where("address_key = ?", key).First(&previous).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		log.Printf("⚠️ Failed to load previous valuation for %s: %v", request.Address, err)
+		return
+	}
+
+	now := time.Now()
+	if err == gorm.ErrRecordNotFound {
+		pvs.db.Create(&models.AddressValuation{
+			AddressKey:     key,
+			Address:        request.Address,
+			EstimatedValue: valuation.EstimatedValue,
+			ValuedAt:       now,
+		})
+		return
+	}
+
+	pvs.db.Model(&previous).Updates(map[string]interface{}{
+		"estimated_value": valuation.EstimatedValue,
+		"valued_at":       now,
+	})
+
+	if previous.EstimatedValue <= 0 {
+		return
+	}
+
+	oldValue := float64(previous.EstimatedValue)
+	newValue := float64(valuation.EstimatedValue)
+	changePercent := (newValue - oldValue) / oldValue * 100
+	if math.Abs(changePercent) < pvs.changeThresholdPercent {
+		return
+	}
+
+	event := &models.PriceChangeEvent{
+		PropertyAddress: request.Address,
+		OldPrice:        oldValue,
+		NewPrice:        newValue,
+		ChangeAmount:    newValue - oldValue,
+		ChangePercent:   changePercent,
+		Direction:       models.PriceDirection(newValue - oldValue),
+		Source:          "valuation",
+		ChangedAt:       now,
+		// Alerts go out below; listing price-drop campaigns must not pick this up
+		ProcessedAt: &now,
+	}
+	if err := pvs.db.Create(event).Error; err != nil {
+		log.Printf("⚠️ Failed to record valuation change for %s: %v", request.Address, err)
+		return
+	}
+
+	log.Printf("💲 Valuation for %s moved %s %.1f%% ($%.0f → $%.0f)",
+		request.Address, event.Direction, math.Abs(changePercent), oldValue, newValue)
+
+	if pvs.notificationHub != nil {
+		pvs.notificationHub.SendValuationChangeAlert(event)
+	}
+	pvs.webhooks.SendAsync("valuation.price_changed", map[string]interface{}{
+		"price_change_event_id": event.ID,
+		"property_address":      event.PropertyAddress,
+		"direction":             event.Direction,
+		"old_value":             event.OldPrice,
+		"new_value":             event.NewPrice,
+		"change_amount":         math.Abs(event.ChangeAmount),
+		"change_percent":        math.Abs(event.ChangePercent),
+		"changed_at":            event.ChangedAt,
+	})
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"math"
+	"time"
+)
+
+// WarmupSchedule ramps the daily send cap for a new sending domain or IP so
+// mailbox providers can build its reputation gradually. The cap starts at
+// InitialDailyCap on day 1 and grows by GrowthFactor every StepDays days
+// until it reaches MaxDailyCap.
+type WarmupSchedule struct {
+	Domain          string
+	StartDate       time.Time
+	InitialDailyCap int
+	GrowthFactor    float64
+	StepDays        int
+	MaxDailyCap     int
+}
+
+// WarmupStatus describes where a sending domain is on its warmup curve
+type WarmupStatus struct {
+	Domain    string `json:"domain"`
+	StartDate string `json:"start_date"`
+	Day       int    `json:"day"`
+	DailyCap  int    `json:"daily_cap"`
+	Complete  bool   `json:"complete"`
+}
+
+// NewWarmupSchedule creates a warmup schedule, falling back to doubling a
+// 50-send cap every day when the curve values are not set
+func NewWarmupSchedule(domain string, startDate time.Time, initialDailyCap int, growthFactor float64, stepDays, maxDailyCap int) *WarmupSchedule {
+	if initialDailyCap <= 0 {
+		initialDailyCap = 50
+	}
+	if growthFactor < 1 {
+		growthFactor = 2
+	}
+	if stepDays <= 0 {
+		stepDays = 1
+	}
+	if maxDailyCap < initialDailyCap {
+		maxDailyCap = initialDailyCap
+	}
+	return &WarmupSchedule{
+		Domain:          domain,
+		StartDate:       startOfDay(startDate),
+		InitialDailyCap: initialDailyCap,
+		GrowthFactor:    growthFactor,
+		StepDays:        stepDays,
+		MaxDailyCap:     maxDailyCap,
+	}
+}
+
+// DayOn returns the 1-based warmup day for t; days before the start date count as day 1
+func (w *WarmupSchedule) DayOn(t time.Time) int {
+	start := startOfDay(w.StartDate.In(t.Location()))
+	day := int(math.Round(startOfDay(t).Sub(start).Hours()/24)) + 1
+	if day < 1 {
+		return 1
+	}
+	return day
+}
+
+// CapOn returns the maximum number of sends allowed on the day containing t
+func (w *WarmupSchedule) CapOn(t time.Time) int {
+	steps := (w.DayOn(t) - 1) / w.StepDays
+	limit := float64(w.InitialDailyCap) * math.Pow(w.GrowthFactor, float64(steps))
+	if limit >= float64(w.MaxDailyCap) {
+		return w.MaxDailyCap
+	}
+	return int(limit)
+}
+
+// StatusOn reports the warmup day and cap for the day containing t
+func (w *WarmupSchedule) StatusOn(t time.Time) WarmupStatus {
+	dailyCap := w.CapOn(t)
+	return WarmupStatus{
+		Domain:    w.Domain,
+		StartDate: w.StartDate.Format("2006-01-02"),
+		Day:       w.DayOn(t),
+		DailyCap:  dailyCap,
+		Complete:  dailyCap >= w.MaxDailyCap,
+	}
+}
+
+// startOfDay truncates t to midnight in its own location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
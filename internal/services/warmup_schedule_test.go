@@ -0,0 +1,70 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"chrisgross-ctrl-project/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestWarmupSchedule_DoublesDailyCapUpToCeiling(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	warmup := NewWarmupSchedule("mail.example.com", start, 50, 2, 1, 300)
+
+	cases := []struct {
+		at    time.Time
+		day   int
+		limit int
+	}{
+		{start.Add(-48 * time.Hour), 1, 50},
+		{start.Add(23 * time.Hour), 1, 50},
+		{start.AddDate(0, 0, 1), 2, 100},
+		{start.AddDate(0, 0, 2).Add(12 * time.Hour), 3, 200},
+		{start.AddDate(0, 0, 3), 4, 300},
+		{start.AddDate(0, 0, 30), 31, 300},
+	}
+	for _, tc := range cases {
+		if day := warmup.DayOn(tc.at); day != tc.day {
+			t.Errorf("DayOn(%v) = %d, want %d", tc.at, day, tc.day)
+		}
+		if dailyCap := warmup.CapOn(tc.at); dailyCap != tc.limit {
+			t.Errorf("CapOn(%v) = %d, want %d", tc.at, dailyCap, tc.limit)
+		}
+	}
+
+	if status := warmup.StatusOn(start.AddDate(0, 0, 3)); !status.Complete || status.StartDate != "2026-10-01" {
+		t.Errorf("Expected warmup to be complete at the ceiling, got %+v", status)
+	}
+}
+
+func TestVolumeController_UsesLowerOfWarmupCapAndDailyLimit(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.CampaignExecution{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	controller := NewVolumeController(db)
+	controller.SetWarmupSchedule(NewWarmupSchedule("mail.example.com", time.Now(), 50, 2, 1, 10000))
+
+	status, err := controller.CheckVolumeCompliance()
+	if err != nil {
+		t.Fatalf("CheckVolumeCompliance failed: %v", err)
+	}
+	if status.DailyLimit != 50 || status.ConfiguredDailyLimit != 500 {
+		t.Errorf("Expected warmup cap 50 under configured limit 500, got %d/%d", status.DailyLimit, status.ConfiguredDailyLimit)
+	}
+	if status.Warmup == nil || status.Warmup.Day != 1 || status.Warmup.DailyCap != 50 {
+		t.Errorf("Expected warmup day 1 with cap 50, got %+v", status.Warmup)
+	}
+
+	// Once the warmup cap passes the configured limit, the configured limit applies
+	controller.SetWarmupSchedule(NewWarmupSchedule("mail.example.com", time.Now().AddDate(0, 0, -10), 50, 2, 1, 10000))
+	if status, _ = controller.CheckVolumeCompliance(); status.DailyLimit != 500 {
+		t.Errorf("Expected the configured limit of 500 to apply, got %d", status.DailyLimit)
+	}
+}
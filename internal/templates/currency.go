@@ -0,0 +1,112 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/flosch/pongo2/v6"
+)
+
+// DefaultCurrency is used when formatPrice is given no currency code
+const DefaultCurrency = "USD"
+
+// currencyFormat describes how amounts in one currency are written
+type currencyFormat struct {
+	symbol      string
+	symbolAfter bool // "1.234,56 €" rather than "€1.234,56"
+	space       bool // Space between the symbol and the amount
+	group       string
+	decimal     string
+	digits      int // Maximum fraction digits; trailing zeros are dropped
+}
+
+// currencyFormats is the locale table for the currencies listings are priced in
+var currencyFormats = map[string]currencyFormat{
+	"USD": {symbol: "$", group: ",", decimal: ".", digits: 2},
+	"CAD": {symbol: "CA$", group: ",", decimal: ".", digits: 2},
+	"AUD": {symbol: "A$", group: ",", decimal: ".", digits: 2},
+	"MXN": {symbol: "MX$", group: ",", decimal: ".", digits: 2},
+	"GBP": {symbol: "£", group: ",", decimal: ".", digits: 2},
+	"EUR": {symbol: "€", symbolAfter: true, space: true, group: ".", decimal: ",", digits: 2},
+	"CHF": {symbol: "CHF", space: true, group: "’", decimal: ".", digits: 2},
+	"BRL": {symbol: "R$", space: true, group: ".", decimal: ",", digits: 2},
+	"JPY": {symbol: "¥", group: ",", decimal: ".", digits: 0},
+}
+
+// FormatCurrency writes amount in the given ISO 4217 currency's symbol,
+// grouping and decimal separator. Unknown codes are written as the code
+// followed by a US-formatted amount; an empty code means USD.
+func FormatCurrency(amount float64, currency string) string {
+	code := strings.ToUpper(strings.TrimSpace(currency))
+	if code == "" {
+		code = DefaultCurrency
+	}
+	format, ok := currencyFormats[code]
+	if !ok {
+		format = currencyFormats[DefaultCurrency]
+		format.symbol = code
+		format.space = true
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.FormatFloat(amount, 'f', format.digits, 64)
+	whole, fraction, _ := strings.Cut(digits, ".")
+	fraction = strings.TrimRight(fraction, "0")
+
+	number := groupDigits(whole, format.group)
+	if fraction != "" {
+		number += format.decimal + fraction
+	}
+
+	separator := ""
+	if format.space {
+		separator = " "
+	}
+	if format.symbolAfter {
+		return sign + number + separator + format.symbol
+	}
+	return sign + format.symbol + separator + number
+}
+
+// groupDigits inserts sep between every three digits from the right
+func groupDigits(digits, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+// FilterFormatPrice is the formatPrice filter. The optional parameter is a
+// currency code, e.g. {{ price|formatPrice:"EUR" }}; it defaults to USD.
+// Non-numeric values are written as-is.
+func FilterFormatPrice(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	currency := ""
+	if param != nil && !param.IsNil() {
+		currency = param.String()
+	}
+	switch {
+	case in.IsInteger():
+		return pongo2.AsValue(FormatCurrency(float64(in.Integer()), currency)), nil
+	case in.IsFloat():
+		return pongo2.AsValue(FormatCurrency(in.Float(), currency)), nil
+	default:
+		return pongo2.AsValue(fmt.Sprintf("%v", in.Interface())), nil
+	}
+}
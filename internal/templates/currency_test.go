@@ -0,0 +1,42 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/flosch/pongo2/v6"
+)
+
+func TestFormatPriceFilter(t *testing.T) {
+	RegisterPongo2Filters()
+
+	tests := []struct {
+		template string
+		price    interface{}
+		want     string
+	}{
+		{`{{ price|formatPrice }}`, 2500, "$2,500"},
+		{`{{ price|formatPrice }}`, 1234567.5, "$1,234,567.5"},
+		{`{{ price|formatPrice }}`, int64(-950), "-$950"},
+		{`{{ price|formatPrice:"usd" }}`, 2500.0, "$2,500"},
+		{`{{ price|formatPrice:"EUR" }}`, 1234567.891, "1.234.567,89 €"},
+		{`{{ price|formatPrice:"GBP" }}`, 1850.25, "£1,850.25"},
+		{`{{ price|formatPrice:"CHF" }}`, 1234567, "CHF 1’234’567"},
+		{`{{ price|formatPrice:"BRL" }}`, 3200.5, "R$ 3.200,5"},
+		{`{{ price|formatPrice:"JPY" }}`, 150000.6, "¥150,001"},
+		{`{{ price|formatPrice:"XYZ" }}`, 100, "XYZ 100"},
+		{`{{ price|formatPrice }}`, "Call for price", "Call for price"},
+	}
+	for _, tt := range tests {
+		tpl, err := pongo2.FromString(tt.template)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", tt.template, err)
+		}
+		got, err := tpl.Execute(pongo2.Context{"price": tt.price})
+		if err != nil {
+			t.Fatalf("Failed to render %s: %v", tt.template, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s with %v = %q, want %q", tt.template, tt.price, got, tt.want)
+		}
+	}
+}
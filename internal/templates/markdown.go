@@ -0,0 +1,168 @@
+package templates
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"chrisgross-ctrl-project/internal/security"
+	"github.com/flosch/pongo2/v6"
+)
+
+// markdownPolicy limits markdown filter output to the tags markdown produces
+var markdownPolicy = security.NewMarkdownHTMLPolicy()
+
+var (
+	mdHeading     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdRule        = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	mdBullet      = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	mdNumbered    = regexp.MustCompile(`^\s*\d{1,9}[.)]\s+(.*)$`)
+	mdQuote       = regexp.MustCompile(`^\s*&gt;\s?(.*)$`)
+	mdLink        = regexp.MustCompile(`\[([^\]]+)\]\(([^()\s]+)(?:\s+&#34;(.*?)&#34;)?\)`)
+	mdBold        = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	mdItalic      = regexp.MustCompile(`\*([^*\s](?:[^*]*[^*\s])?)\*|\b_([^_\s](?:[^_]*[^_\s])?)_\b`)
+	mdPlaceholder = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// RenderMarkdown converts lightweight markdown (headings, paragraphs, bold,
+// italics, inline code, fenced code, links, lists, quotes and rules) to
+// HTML and sanitizes the result. Raw HTML in the input is escaped, not
+// rendered.
+func RenderMarkdown(source string) string {
+	// NUL marks held inline HTML while rendering, so it may not come from the input
+	source = strings.NewReplacer("\x00", "", "\r\n", "\n").Replace(source)
+	lines := strings.Split(source, "\n")
+
+	var b strings.Builder
+	var paragraph []string
+	list := ""
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + strings.Join(paragraph, "<br>\n") + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			b.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+	openList := func(tag string) {
+		if list != tag {
+			closeList()
+			b.WriteString("<" + tag + ">\n")
+			list = tag
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := html.EscapeString(lines[i])
+
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			flushParagraph()
+			closeList()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, html.EscapeString(lines[i]))
+			}
+			b.WriteString("<pre><code>" + strings.Join(code, "\n") + "</code></pre>\n")
+			continue
+		}
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			flushParagraph()
+			closeList()
+		case mdHeading.MatchString(line):
+			flushParagraph()
+			closeList()
+			m := mdHeading.FindStringSubmatch(line)
+			level := strconv.Itoa(len(m[1]))
+			b.WriteString("<h" + level + ">" + renderMarkdownInline(m[2]) + "</h" + level + ">\n")
+		case mdRule.MatchString(line):
+			flushParagraph()
+			closeList()
+			b.WriteString("<hr>\n")
+		case mdBullet.MatchString(line):
+			flushParagraph()
+			openList("ul")
+			b.WriteString("<li>" + renderMarkdownInline(mdBullet.FindStringSubmatch(line)[1]) + "</li>\n")
+		case mdNumbered.MatchString(line):
+			flushParagraph()
+			openList("ol")
+			b.WriteString("<li>" + renderMarkdownInline(mdNumbered.FindStringSubmatch(line)[1]) + "</li>\n")
+		case mdQuote.MatchString(line):
+			flushParagraph()
+			closeList()
+			b.WriteString("<blockquote>" + renderMarkdownInline(mdQuote.FindStringSubmatch(line)[1]) + "</blockquote>\n")
+		default:
+			closeList()
+			paragraph = append(paragraph, renderMarkdownInline(strings.TrimSpace(line)))
+		}
+	}
+	flushParagraph()
+	closeList()
+
+	return markdownPolicy.Sanitize(b.String())
+}
+
+// renderMarkdownInline renders the inline markup of one already-escaped
+// line. Code spans and links are set aside first so emphasis markers inside
+// them are left alone.
+func renderMarkdownInline(text string) string {
+	var held []string
+	hold := func(s string) string {
+		held = append(held, s)
+		return fmt.Sprintf("\x00%d\x00", len(held)-1)
+	}
+
+	var b strings.Builder
+	parts := strings.Split(text, "`")
+	for i, part := range parts {
+		switch {
+		case i%2 == 1 && i < len(parts)-1:
+			b.WriteString(hold("<code>" + part + "</code>"))
+		case i%2 == 1:
+			// An unmatched backtick is kept as text
+			b.WriteString("`" + part)
+		default:
+			b.WriteString(part)
+		}
+	}
+	text = b.String()
+
+	text = mdLink.ReplaceAllStringFunc(text, func(match string) string {
+		m := mdLink.FindStringSubmatch(match)
+		link := `<a href="` + m[2] + `"`
+		if m[3] != "" {
+			link += ` title="` + m[3] + `"`
+		}
+		return hold(link + ">" + renderMarkdownEmphasis(m[1]) + "</a>")
+	})
+	text = renderMarkdownEmphasis(text)
+
+	for mdPlaceholder.MatchString(text) {
+		text = mdPlaceholder.ReplaceAllStringFunc(text, func(match string) string {
+			n, _ := strconv.Atoi(strings.Trim(match, "\x00"))
+			return held[n]
+		})
+	}
+	return text
+}
+
+func renderMarkdownEmphasis(text string) string {
+	text = mdBold.ReplaceAllString(text, "<strong>$1$2</strong>")
+	return mdItalic.ReplaceAllString(text, "<em>$1$2</em>")
+}
+
+// FilterMarkdown is the markdown filter. It renders a markdown string as
+// sanitized HTML and marks it safe, so {{ notes|markdown }} needs no |safe.
+func FilterMarkdown(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	if in.IsNil() {
+		return pongo2.AsSafeValue(""), nil
+	}
+	return pongo2.AsSafeValue(RenderMarkdown(in.String())), nil
+}
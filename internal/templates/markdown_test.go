@@ -0,0 +1,109 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flosch/pongo2/v6"
+	"golang.org/x/net/html"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"heading", "## Spring Open House", "<h2>Spring Open House</h2>\n"},
+		{"emphasis", "**Pets** welcome, *no* smoking, `deposit`", "<p><strong>Pets</strong> welcome, <em>no</em> smoking, <code>deposit</code></p>\n"},
+		{"link", `[Apply](https://example.com/apply?a=1&b=2 "Apply now")`, `<p><a href="https://example.com/apply?a=1&amp;b=2" title="Apply now" rel="nofollow noopener noreferrer">Apply</a></p>` + "\n"},
+		{"relative link", "[Listings](/properties)", `<p><a href="/properties" rel="nofollow noopener noreferrer">Listings</a></p>` + "\n"},
+		{"lists", "- one\n- two\n\n1. first", "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n<ol>\n<li>first</li>\n</ol>\n"},
+		{"intraword underscores", "see tenant_portal_url", "<p>see tenant_portal_url</p>\n"},
+	}
+	for _, tt := range tests {
+		if got := RenderMarkdown(tt.markdown); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMarkdownInjection(t *testing.T) {
+	attacks := []string{
+		`<script>alert(1)</script>`,
+		`<img src=x onerror=alert(1)>`,
+		`[click](javascript:alert(1))`,
+		`[click](JaVaScRiPt:alert(1))`,
+		`[click](java&#115;cript:alert(1))`,
+		`[click](data:text/html;base64,PHNjcmlwdD4=)`,
+		`[x](https://example.com" onmouseover="alert(1))`,
+		`[x](https://example.com "a\" onclick=\"alert(1)")`,
+		"`<iframe src=javascript:alert(1)>`",
+		"```\n</code></pre><script>alert(1)</script>\n```",
+		"**<svg onload=alert(1)>**",
+		"\x000\x00<script>",
+	}
+	allowed := map[string]bool{"p": true, "a": true, "strong": true, "code": true, "pre": true}
+	for _, attack := range attacks {
+		got := RenderMarkdown(attack)
+		tokenizer := html.NewTokenizer(strings.NewReader(got))
+		for tt := tokenizer.Next(); tt != html.ErrorToken; tt = tokenizer.Next() {
+			if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+				continue
+			}
+			token := tokenizer.Token()
+			if !allowed[token.Data] {
+				t.Errorf("RenderMarkdown(%q) = %q, renders <%s>", attack, got, token.Data)
+			}
+			for _, attr := range token.Attr {
+				switch attr.Key {
+				case "href":
+					if !strings.HasPrefix(attr.Val, "https://") {
+						t.Errorf("RenderMarkdown(%q) = %q, links to %q", attack, got, attr.Val)
+					}
+				case "title", "rel":
+				default:
+					t.Errorf("RenderMarkdown(%q) = %q, sets attribute %s", attack, got, attr.Key)
+				}
+			}
+		}
+	}
+}
+
+func TestMarkdownFilter(t *testing.T) {
+	RegisterPongo2Filters()
+
+	tpl, err := pongo2.FromString(`{{ notes|markdown }}|{{ notes|markdown|safe }}|{{ missing|markdown }}`)
+	if err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+	out, err := tpl.Execute(pongo2.Context{"notes": "**Hi** <b>there</b>"})
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+	want := "<p><strong>Hi</strong> &lt;b&gt;there&lt;/b&gt;</p>\n"
+	if out != want+"|"+want+"|" {
+		t.Errorf("got %q", out)
+	}
+}
+
+func TestMarkdownPolicySanitize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`<p onclick="x()">Hi <b>there</b></p>`, "<p>Hi there</p>"},
+		{`<script>alert(1)</script><em>ok</em>`, "<em>ok</em>"},
+		{`<a href="javascript:alert(1)" target="_blank">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{`<a href=" JAVA&#x53;CRIPT:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{`<a href="mailto:leasing@example.com" title="Email">x</a>`, `<a href="mailto:leasing@example.com" title="Email" rel="nofollow noopener noreferrer">x</a>`},
+		{`<ul><li>open`, "<ul><li>open</li></ul>"},
+		{`<strong>a</em></strong></p>`, "<strong>a</strong>"},
+		{`<p>1 &lt; 2 &amp; <svg><g onload=x>`, "<p>1 &lt; 2 &amp; </p>"},
+	}
+	for _, tt := range tests {
+		if got := markdownPolicy.Sanitize(tt.input); got != tt.want {
+			t.Errorf("Sanitize(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
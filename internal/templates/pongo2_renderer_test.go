@@ -0,0 +1,95 @@
+package templates
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testBaseTemplate = `<html><head><title>{% block title %}PropertyHub{% endblock %}</title></head>
+<body><nav>{% for link in links %}<a href="{{ link.url }}">{{ link.name }}</a>{% endfor %}</nav>
+{% block content %}{% endblock %}</body></html>`
+
+const testPageTemplate = `{% extends "base.html" %}
+{% block title %}{{ title }}{% endblock %}
+{% block content %}<ul>{% for p in properties %}<li>{{ p.address }} - {{ p.price|formatPrice }}{% if p.featured %} *{% endif %}</li>{% endfor %}</ul>{% endblock %}`
+
+// writeTestTemplates writes a base template and a page extending it
+func writeTestTemplates(tb testing.TB) string {
+	tb.Helper()
+	dir := tb.TempDir()
+	for name, content := range map[string]string{"base.html": testBaseTemplate, "page.html": testPageTemplate} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			tb.Fatalf("Failed to write template: %v", err)
+		}
+	}
+	return dir
+}
+
+func testPageData() gin.H {
+	properties := make([]map[string]interface{}, 20)
+	for i := range properties {
+		properties[i] = map[string]interface{}{"address": "123 Main St", "price": 2500.0, "featured": i%3 == 0}
+	}
+	return gin.H{
+		"title":      "Listings",
+		"links":      []map[string]string{{"url": "/", "name": "Home"}, {"url": "/properties", "name": "Properties"}},
+		"properties": properties,
+	}
+}
+
+func renderPage(tb testing.TB, r *Pongo2Render) string {
+	w := httptest.NewRecorder()
+	if err := r.Instance("page.html", testPageData()).Render(w); err != nil {
+		tb.Fatalf("Render failed: %v", err)
+	}
+	return w.Body.String()
+}
+
+func TestPongo2Render_ReloadPicksUpEdits(t *testing.T) {
+	RegisterPongo2Filters()
+	for _, reload := range []bool{false, true} {
+		dir := writeTestTemplates(t)
+		r := NewPongo2Render(dir, reload)
+		if body := renderPage(t, r); !strings.Contains(body, "<title>Listings</title>") {
+			t.Fatalf("Unexpected render: %s", body)
+		}
+
+		edited := strings.Replace(testPageTemplate, "<ul>", "<ol>", 1)
+		if err := os.WriteFile(filepath.Join(dir, "page.html"), []byte(edited), 0o644); err != nil {
+			t.Fatalf("Failed to edit template: %v", err)
+		}
+		if got := strings.Contains(renderPage(t, r), "<ol>"); got != reload {
+			t.Errorf("reload=%v: expected edit visible=%v, got %v", reload, reload, got)
+		}
+	}
+}
+
+// BenchmarkPongo2Render compares rendering a page that extends a base
+// template with the parsed template cached and with it re-parsed each time:
+//
+//	go test -run '^$' -bench Pongo2Render ./internal/templates
+func BenchmarkPongo2Render(b *testing.B) {
+	RegisterPongo2Filters()
+	for _, mode := range []struct {
+		name   string
+		reload bool
+	}{{"cached", false}, {"reload", true}} {
+		b.Run(mode.name, func(b *testing.B) {
+			r := NewPongo2Render(writeTestTemplates(b), mode.reload)
+			data := testPageData()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				w := httptest.NewRecorder()
+				if err := r.Instance("page.html", data).Render(w); err != nil {
+					b.Fatalf("Render failed: %v", err)
+				}
+			}
+		})
+	}
+}
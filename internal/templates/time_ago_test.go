@@ -0,0 +1,29 @@
+package templates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeAgo(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{now, "just now"},
+		{now.Add(-30 * time.Second), "just now"},
+		{now.Add(45 * time.Second), "just now"},
+		{now.Add(-5 * time.Minute), "5 minutes ago"},
+		{now.Add(-2 * time.Hour), "2 hours ago"},
+		{now.Add(-3 * 24 * time.Hour), "3 days ago"},
+		{now.Add(10 * time.Minute), "10 minutes from now"},
+		{now.Add(2 * 24 * time.Hour), "2 days from now"},
+	}
+	for _, tt := range tests {
+		if got := TimeAgo(tt.at, now); got != tt.want {
+			t.Errorf("TimeAgo(%v) = %q, want %q", now.Sub(tt.at), got, tt.want)
+		}
+	}
+}
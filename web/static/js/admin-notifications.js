@@ -7,6 +7,7 @@ class AdminNotificationManager {
         this.maxRetries = 5;
         this.retryDelay = 3000;
         this.bellElement = null;
+        this.csrfToken = null;
     }
 
     async init() {
@@ -40,8 +41,14 @@ class AdminNotificationManager {
             };
 
             this.socket.onmessage = (event) => {
-                const notification = JSON.parse(event.data);
-                this.handleNotification(notification);
+                const message = JSON.parse(event.data);
+                if (message.type === 'unread_count') {
+                    // Another tab marked notifications read
+                    this.unreadCount = message.unread_count;
+                    this.loadNotifications();
+                    return;
+                }
+                this.handleNotification(message);
             };
 
             this.socket.onclose = () => {
@@ -82,11 +89,11 @@ class AdminNotificationManager {
 
     async loadNotifications() {
         try {
-            const response = await fetch('/api/notifications?limit=50');
+            const response = await fetch('/api/v1/admin/notifications?limit=50');
             if (response.ok) {
                 const data = await response.json();
                 this.notifications = data.notifications || [];
-                this.updateUnreadCount();
+                this.unreadCount = data.unread_count || 0;
                 this.updateUI();
             }
         } catch (error) {
@@ -103,7 +110,7 @@ class AdminNotificationManager {
             this.notifications = this.notifications.slice(0, 100);
         }
         
-        this.updateUnreadCount();
+        this.unreadCount++;
         this.updateUI();
         
         this.showToast(notification);
@@ -121,8 +128,13 @@ class AdminNotificationManager {
         }
     }
 
-    updateUnreadCount() {
-        this.unreadCount = this.notifications.filter(n => !n.read && !n.read_at).length;
+    async csrfHeaders() {
+        if (!this.csrfToken) {
+            const response = await fetch('/api/v1/csrf-token');
+            const data = await response.json();
+            this.csrfToken = data.csrf_token;
+        }
+        return { 'X-CSRF-Token': this.csrfToken };
     }
 
     updateUI() {
@@ -195,17 +207,18 @@ class AdminNotificationManager {
 
     async markAsRead(id) {
         try {
-            const response = await fetch(`/api/notifications/${id}/read`, {
-                method: 'PUT'
+            const response = await fetch(`/api/v1/admin/notifications/${id}/read`, {
+                method: 'POST',
+                headers: await this.csrfHeaders()
             });
 
             if (response.ok) {
                 const notification = this.notifications.find(n => n.id === id);
-                if (notification) {
+                if (notification && !notification.read) {
                     notification.read = true;
                     notification.read_at = new Date().toISOString();
+                    this.unreadCount = Math.max(0, this.unreadCount - 1);
                 }
-                this.updateUnreadCount();
                 this.updateUI();
             }
         } catch (error) {
@@ -215,8 +228,9 @@ class AdminNotificationManager {
 
     async markAllAsRead() {
         try {
-            const response = await fetch('/api/notifications/read-all', {
-                method: 'PUT'
+            const response = await fetch('/api/v1/admin/notifications/mark-all-read', {
+                method: 'POST',
+                headers: await this.csrfHeaders()
             });
 
             if (response.ok) {
@@ -224,7 +238,7 @@ class AdminNotificationManager {
                     n.read = true;
                     n.read_at = new Date().toISOString();
                 });
-                this.updateUnreadCount();
+                this.unreadCount = 0;
                 this.updateUI();
             }
         } catch (error) {
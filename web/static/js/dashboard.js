@@ -622,6 +622,7 @@ function liveActivityFeed() {
         ws: null,
         events: [],
         activeCount: 0,
+        lastCursor: null,
         reconnectAttempts: 0,
         maxReconnects: 5,
         reconnectDelay: 1000,
@@ -632,7 +633,9 @@ function liveActivityFeed() {
         
         connect() {
             const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-            const wsUrl = `${protocol}//${window.location.host}/api/ws/admin/activity`;
+            // Resume after the last event seen so a dropped connection doesn't skip events
+            const resume = this.lastCursor !== null ? `?last_cursor=${this.lastCursor}` : '';
+            const wsUrl = `${protocol}//${window.location.host}/api/ws/admin/activity${resume}`;
             
             try {
                 this.ws = new WebSocket(wsUrl);
@@ -667,7 +670,20 @@ function liveActivityFeed() {
         
         handleMessage(message) {
             if (message.type === 'activity_event') {
+                // Replayed events may repeat ones already shown
+                if (this.lastCursor !== null && message.cursor <= this.lastCursor) {
+                    return;
+                }
+                this.lastCursor = message.cursor;
                 this.handleActivityEvent(message.data.event);
+            } else if (message.type === 'activity_cursor') {
+                if (this.lastCursor === null || message.data.cursor > this.lastCursor) {
+                    this.lastCursor = message.data.cursor;
+                }
+            } else if (message.type === 'replay_truncated') {
+                // Missed more events than the server keeps (or the server restarted)
+                this.events = [];
+                this.lastCursor = null;
             } else if (message.type === 'active_count') {
                 this.activeCount = message.data.count || 0;
             }